@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/pflag"
+	"github.com/stellar/go-stellar-sdk/ingest"
+	"github.com/stellar/stellar-etl/v2/internal/utils"
+)
+
+// addAccountFilterFlags adds the filter-accounts flag
+func addAccountFilterFlags(flags *pflag.FlagSet) {
+	flags.StringSlice("filter-accounts", []string{}, "Comma separated account, muxed account and contract addresses. If set, only the records of transactions whose envelope has one of them are exported. "+
+		"Entries starting with @ are files with one address per line.")
+}
+
+// mustAccountFilter reads the filter-accounts flag. The filter is nil if the flag is not set.
+func mustAccountFilter(flags *pflag.FlagSet) *utils.AccountFilter {
+	entries, err := flags.GetStringSlice("filter-accounts")
+	if err != nil {
+		cmdLogger.Fatal("could not get filter-accounts flag: ", err)
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+
+	addresses, err := readFilterAccounts(entries)
+	if err != nil {
+		cmdLogger.Fatal(err)
+	}
+
+	filter, err := utils.NewAccountFilter(addresses)
+	if err != nil {
+		cmdLogger.Fatal(err)
+	}
+	cmdLogger.Infof("Filtering transactions by %d accounts and contracts", filter.Len())
+	return filter
+}
+
+// readFilterAccounts returns the addresses of the filter-accounts flag, reading the files of the entries that start
+// with @. Blank lines and lines starting with # are skipped.
+func readFilterAccounts(entries []string) ([]string, error) {
+	var addresses []string
+	for _, entry := range entries {
+		path, isFile := strings.CutPrefix(entry, "@")
+		if !isFile {
+			addresses = append(addresses, strings.TrimSpace(entry))
+			continue
+		}
+
+		contents, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("could not read filter-accounts file %s: %v", path, err)
+		}
+		for _, line := range strings.Split(string(contents), "\n") {
+			line = strings.TrimSpace(line)
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			addresses = append(addresses, line)
+		}
+	}
+	return addresses, nil
+}
+
+// skipFilteredAccounts reports whether the transaction is filtered out by the account filter. Every transaction passes
+// a nil filter.
+func skipFilteredAccounts(filter *utils.AccountFilter, transaction ingest.LedgerTransaction) (bool, error) {
+	if filter == nil {
+		return false, nil
+	}
+
+	matches, err := filter.MatchesEnvelope(transaction.Envelope)
+	if err != nil {
+		return false, err
+	}
+	return !matches, nil
+}
@@ -0,0 +1,21 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadFilterAccounts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "accounts.txt")
+	assert.NoError(t, os.WriteFile(path, []byte("# exchanges\nGA1\n\n  GA2  \n"), 0644))
+
+	addresses, err := readFilterAccounts([]string{"GB1", "@" + path})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"GB1", "GA1", "GA2"}, addresses)
+
+	_, err = readFilterAccounts([]string{"@" + filepath.Join(t.TempDir(), "missing.txt")})
+	assert.ErrorContains(t, err, "could not read filter-accounts file")
+}
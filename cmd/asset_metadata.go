@@ -0,0 +1,407 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/guregu/null"
+	"github.com/pelletier/go-toml/v2"
+	"github.com/spf13/cobra"
+	"golang.org/x/time/rate"
+
+	"github.com/stellar/stellar-etl/v2/internal/transform"
+	"github.com/stellar/stellar-etl/v2/internal/utils"
+)
+
+// stellarTomlMaxSize is the largest stellar.toml that is read, which is the limit of the stellar.toml client of the SDK
+const stellarTomlMaxSize = 100 * 1024
+
+// horizonURLs are the default Horizon servers that the home domains of issuers are read from
+var horizonURLs = map[string]string{
+	"pubnet":    "https://horizon.stellar.org",
+	"testnet":   "https://horizon-testnet.stellar.org",
+	"futurenet": "https://horizon-futurenet.stellar.org",
+}
+
+// stellarToml is the part of a SEP-1 stellar.toml that describes the issuer and its currencies
+type stellarToml struct {
+	Documentation struct {
+		OrgName string `toml:"ORG_NAME" json:"org_name"`
+	} `toml:"DOCUMENTATION" json:"documentation"`
+	Currencies []tomlCurrency `toml:"CURRENCIES" json:"currencies"`
+}
+
+// tomlCurrency is a currency of a stellar.toml
+type tomlCurrency struct {
+	Code            string `toml:"code" json:"code"`
+	Issuer          string `toml:"issuer" json:"issuer"`
+	Status          string `toml:"status" json:"status"`
+	DisplayDecimals *int64 `toml:"display_decimals" json:"display_decimals"`
+	Name            string `toml:"name" json:"name"`
+	Desc            string `toml:"desc" json:"desc"`
+	Image           string `toml:"image" json:"image"`
+	IsAssetAnchored bool   `toml:"is_asset_anchored" json:"is_asset_anchored"`
+	AnchorAssetType string `toml:"anchor_asset_type" json:"anchor_asset_type"`
+	AnchorAsset     string `toml:"anchor_asset" json:"anchor_asset"`
+}
+
+// issuerMetadata is the stellar.toml of the home domain of an issuer, or the reason it couldn't be read
+type issuerMetadata struct {
+	HomeDomain string      `json:"home_domain"`
+	Toml       stellarToml `json:"toml"`
+	Error      string      `json:"error,omitempty"`
+	FetchedAt  time.Time   `json:"fetched_at"`
+}
+
+// assetMetadataFetcher reads the stellar.toml of the home domains of asset issuers. Issuers are cached for cacheTTL,
+// and every request to Horizon or a home domain waits for the rate limiter. Issuers whose metadata couldn't be read
+// are only cached for the current run, so they are retried by the next one.
+type assetMetadataFetcher struct {
+	client     *http.Client
+	horizonURL string
+	tomlURL    func(domain string) string
+	limiter    *rate.Limiter
+	cacheTTL   time.Duration
+	now        func() time.Time
+
+	issuers     map[string]issuerMetadata
+	domains     map[string]issuerMetadata
+	numRequests int
+	numCached   int
+}
+
+func newAssetMetadataFetcher(horizonURL string, requestsPerSecond float64, cacheTTL time.Duration) *assetMetadataFetcher {
+	limit := rate.Inf
+	if requestsPerSecond > 0 {
+		limit = rate.Limit(requestsPerSecond)
+	}
+
+	return &assetMetadataFetcher{
+		client:     &http.Client{Timeout: 30 * time.Second},
+		horizonURL: horizonURL,
+		tomlURL: func(domain string) string {
+			return "https://" + domain + "/.well-known/stellar.toml"
+		},
+		limiter:  rate.NewLimiter(limit, 1),
+		cacheTTL: cacheTTL,
+		now:      time.Now,
+		issuers:  map[string]issuerMetadata{},
+		domains:  map[string]issuerMetadata{},
+	}
+}
+
+// loadCache reads the issuers cached by earlier runs. A missing cache file is empty.
+func (f *assetMetadataFetcher) loadCache(path string) error {
+	contents, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	cached := map[string]issuerMetadata{}
+	if err := json.Unmarshal(contents, &cached); err != nil {
+		return fmt.Errorf("could not decode asset metadata cache %s: %v", path, err)
+	}
+	for issuer, metadata := range cached {
+		if f.now().Sub(metadata.FetchedAt) < f.cacheTTL {
+			f.issuers[issuer] = metadata
+		}
+	}
+	return nil
+}
+
+// saveCache writes the issuers whose metadata was read, so the next run doesn't fetch them again
+func (f *assetMetadataFetcher) saveCache(path string) error {
+	cached := map[string]issuerMetadata{}
+	for issuer, metadata := range f.issuers {
+		if metadata.Error == "" {
+			cached[issuer] = metadata
+		}
+	}
+
+	marshalled, err := json.MarshalIndent(cached, "", "  ")
+	if err != nil {
+		return err
+	}
+	mustMakeParentDir(path)
+	return os.WriteFile(path, marshalled, 0644)
+}
+
+// metadata returns the metadata of an asset from the stellar.toml of the home domain of its issuer
+func (f *assetMetadataFetcher) metadata(ctx context.Context, asset transform.AssetOutput) transform.AssetMetadataOutput {
+	issuer := f.issuer(ctx, asset.AssetIssuer)
+	output := transform.AssetMetadataOutput{
+		AssetCode:     asset.AssetCode,
+		AssetIssuer:   asset.AssetIssuer,
+		AssetType:     asset.AssetType,
+		AssetID:       asset.AssetID,
+		HomeDomain:    issuer.HomeDomain,
+		OrgName:       issuer.Toml.Documentation.OrgName,
+		MetadataError: issuer.Error,
+		FetchedAt:     issuer.FetchedAt,
+	}
+	if issuer.Error != "" {
+		return output
+	}
+
+	for _, currency := range issuer.Toml.Currencies {
+		if currency.Code != asset.AssetCode || currency.Issuer != asset.AssetIssuer {
+			continue
+		}
+
+		output.Name = currency.Name
+		output.Description = currency.Desc
+		output.Image = currency.Image
+		output.DisplayDecimals = null.IntFromPtr(currency.DisplayDecimals)
+		output.Status = currency.Status
+		output.IsAssetAnchored = currency.IsAssetAnchored
+		output.AnchorAssetType = currency.AnchorAssetType
+		output.AnchorAsset = currency.AnchorAsset
+		return output
+	}
+
+	output.MetadataError = fmt.Sprintf("the stellar.toml of %s does not list the asset", issuer.HomeDomain)
+	return output
+}
+
+// issuer returns the cached metadata of an issuer, or reads it. Issuers that share a home domain only fetch it once.
+func (f *assetMetadataFetcher) issuer(ctx context.Context, accountID string) issuerMetadata {
+	if metadata, ok := f.issuers[accountID]; ok {
+		f.numCached++
+		return metadata
+	}
+
+	homeDomain, err := f.homeDomain(ctx, accountID)
+	metadata := issuerMetadata{HomeDomain: homeDomain, FetchedAt: f.now().UTC()}
+	switch {
+	case err != nil:
+		metadata.Error = fmt.Sprintf("could not read the home domain of the issuer: %v", err)
+	case homeDomain == "":
+		metadata.Error = "the issuer has no home domain"
+	default:
+		domain, ok := f.domains[homeDomain]
+		if !ok {
+			domain = metadata
+			domain.Toml, err = f.stellarToml(ctx, homeDomain)
+			if err != nil {
+				domain.Error = fmt.Sprintf("could not read the stellar.toml of %s: %v", homeDomain, err)
+			}
+			f.domains[homeDomain] = domain
+		}
+		metadata = domain
+	}
+
+	f.issuers[accountID] = metadata
+	return metadata
+}
+
+// homeDomain reads the home domain of an account from Horizon
+func (f *assetMetadataFetcher) homeDomain(ctx context.Context, accountID string) (string, error) {
+	body, err := f.get(ctx, f.horizonURL+"/accounts/"+url.PathEscape(accountID), 0)
+	if err != nil {
+		return "", err
+	}
+
+	var account struct {
+		HomeDomain string `json:"home_domain"`
+	}
+	if err := json.Unmarshal(body, &account); err != nil {
+		return "", fmt.Errorf("could not decode horizon account: %v", err)
+	}
+	return account.HomeDomain, nil
+}
+
+// stellarToml reads and decodes the stellar.toml of a home domain
+func (f *assetMetadataFetcher) stellarToml(ctx context.Context, homeDomain string) (stellarToml, error) {
+	body, err := f.get(ctx, f.tomlURL(homeDomain), stellarTomlMaxSize)
+	if err != nil {
+		return stellarToml{}, err
+	}
+
+	var decoded stellarToml
+	if err := toml.Unmarshal(body, &decoded); err != nil {
+		return stellarToml{}, fmt.Errorf("could not decode toml: %v", err)
+	}
+	return decoded, nil
+}
+
+// get waits for the rate limiter, then reads the body of a url. A body larger than maxSize fails instead of being cut
+// short, unless maxSize is 0.
+func (f *assetMetadataFetcher) get(ctx context.Context, target string, maxSize int64) ([]byte, error) {
+	if err := f.limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+	f.numRequests++
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s returned %s", target, resp.Status)
+	}
+
+	if maxSize == 0 {
+		return io.ReadAll(resp.Body)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(body)) > maxSize {
+		return nil, fmt.Errorf("%s is too large, it is larger than %d bytes", target, maxSize)
+	}
+	return body, nil
+}
+
+// readExportedAssets reads the issued assets of an assets export, without duplicates and in the order of the file
+func readExportedAssets(path string) ([]transform.AssetOutput, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	assets := []transform.AssetOutput{}
+	seenIDs := map[int64]bool{}
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var asset transform.AssetOutput
+		if err := json.Unmarshal(scanner.Bytes(), &asset); err != nil {
+			return nil, fmt.Errorf("could not decode asset in %s: %v", path, err)
+		}
+		// Native assets have no issuer, so they have no stellar.toml
+		if asset.AssetIssuer == "" || seenIDs[asset.AssetID] {
+			continue
+		}
+		seenIDs[asset.AssetID] = true
+		assets = append(assets, asset)
+	}
+	return assets, scanner.Err()
+}
+
+var assetMetadataCmd = &cobra.Command{
+	Use:   "enrich_asset_metadata assets_file",
+	Short: "Exports the stellar.toml metadata of the assets of an assets export",
+	Long: `Reads an export of export_assets and exports the metadata that the issuer of every asset publishes in the
+SEP-1 stellar.toml of its home domain, such as the name, image and display decimals of the asset and its anchor. The
+home domains of the issuers are read from Horizon. Assets whose metadata can't be read are exported with the reason in
+metadata_error.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		path, err := cmd.Flags().GetString("output")
+		if err != nil {
+			cmdLogger.Fatal("could not get output path: ", err)
+		}
+
+		parquetPath, err := cmd.Flags().GetString("parquet-output")
+		if err != nil {
+			cmdLogger.Fatal("could not get parquet-output path: ", err)
+		}
+
+		horizonURL, err := cmd.Flags().GetString("horizon-url")
+		if err != nil {
+			cmdLogger.Fatal("could not get horizon-url: ", err)
+		}
+
+		requestsPerSecond, err := cmd.Flags().GetFloat64("requests-per-second")
+		if err != nil {
+			cmdLogger.Fatal("could not get requests-per-second: ", err)
+		}
+
+		cachePath, err := cmd.Flags().GetString("cache-file")
+		if err != nil {
+			cmdLogger.Fatal("could not get cache-file: ", err)
+		}
+
+		cacheTTL, err := cmd.Flags().GetDuration("cache-ttl")
+		if err != nil {
+			cmdLogger.Fatal("could not get cache-ttl: ", err)
+		}
+
+		isTest, err := cmd.Flags().GetBool("testnet")
+		if err != nil {
+			cmdLogger.Fatal("could not get testnet boolean: ", err)
+		}
+
+		isFuture, err := cmd.Flags().GetBool("futurenet")
+		if err != nil {
+			cmdLogger.Fatal("could not get futurenet boolean: ", err)
+		}
+
+		if horizonURL == "" {
+			horizonURL = horizonURLs[utils.NetworkName(isTest, isFuture)]
+		}
+
+		assets, err := readExportedAssets(args[0])
+		if err != nil {
+			cmdLogger.Fatal("could not read assets: ", err)
+		}
+
+		fetcher := newAssetMetadataFetcher(horizonURL, requestsPerSecond, cacheTTL)
+		if cachePath != "" {
+			if err := fetcher.loadCache(cachePath); err != nil {
+				cmdLogger.Fatal(err)
+			}
+		}
+
+		outFile := MustOutFile(path)
+		numFailures := 0
+		parquetRecords := []transform.SchemaParquet{}
+		for _, asset := range assets {
+			metadata := fetcher.metadata(cmd.Context(), asset)
+			if metadata.MetadataError != "" {
+				numFailures++
+			}
+			if _, err := ExportEntry(metadata, outFile, nil, false, false); err != nil {
+				cmdLogger.Fatal("could not export asset metadata: ", err)
+			}
+			parquetRecords = append(parquetRecords, metadata)
+		}
+		outFile.Close()
+
+		if parquetPath != "" {
+			WriteParquet(parquetRecords, parquetPath, new(transform.AssetMetadataOutputParquet), nil)
+		}
+		if cachePath != "" {
+			if err := fetcher.saveCache(cachePath); err != nil {
+				cmdLogger.Fatal("could not save asset metadata cache: ", err)
+			}
+		}
+
+		cmdLogger.Infof("Exported the metadata of %d assets, %d of them without metadata, with %d requests and %d cache hits", len(assets), numFailures, fetcher.numRequests, fetcher.numCached)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(assetMetadataCmd)
+	assetMetadataCmd.Flags().StringP("output", "o", "asset_metadata.txt", "Filename of the output file")
+	assetMetadataCmd.Flags().String("parquet-output", "", "If set, the metadata is also written to this parquet file")
+	assetMetadataCmd.Flags().String("horizon-url", "", "Horizon server that the home domains of the issuers are read from. Defaults to the SDF Horizon of the network.")
+	assetMetadataCmd.Flags().Float64("requests-per-second", 5, "Maximum number of requests per second to Horizon and the home domains. 0 is unlimited.")
+	assetMetadataCmd.Flags().String("cache-file", "", "If set, the metadata of the issuers is cached in this file across runs")
+	assetMetadataCmd.Flags().Duration("cache-ttl", 24*time.Hour, "How long the cached metadata of an issuer is used before it is fetched again")
+	assetMetadataCmd.Flags().Bool("testnet", false, "If set, the home domains are read from the testnet Horizon instead of mainnet.")
+	assetMetadataCmd.Flags().Bool("futurenet", false, "If set, the home domains are read from the futurenet Horizon instead of mainnet.")
+}
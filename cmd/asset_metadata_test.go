@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/guregu/null"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stellar/stellar-etl/v2/internal/transform"
+)
+
+const (
+	usdcIssuer = "GA5ZSEJYB37JRC5AVCIA5MOP4RHTM335X2KGX3IHOJAPP5RE34K4KZVN"
+	yxlmIssuer = "GARDNV3Q7YGT4AKSDF25LT32YSCCW4EV22Y2TV3I2PU2MMXJTEDL5T55"
+	noteIssuer = "GAQLXGJTFR2FCHWSGO4BOQGQUGWIYSNTEGP6RIYVRMYRM6AKDT7YEIOQ"
+)
+
+func TestAssetMetadataFetcher(t *testing.T) {
+	fetchedAt := time.Date(2026, time.October, 16, 12, 0, 0, 0, time.UTC)
+	requests := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests[r.URL.Path]++
+		switch r.URL.Path {
+		case "/accounts/" + usdcIssuer, "/accounts/" + yxlmIssuer:
+			w.Write([]byte(`{"id":"` + usdcIssuer + `","home_domain":"centre.io"}`))
+		case "/accounts/" + noteIssuer:
+			w.Write([]byte(`{"id":"` + noteIssuer + `"}`))
+		case "/centre.io/.well-known/stellar.toml":
+			w.Write([]byte(`
+VERSION = "2.0.0"
+
+[DOCUMENTATION]
+ORG_NAME = "Centre Consortium"
+
+[[CURRENCIES]]
+code = "USDC"
+issuer = "` + usdcIssuer + `"
+name = "USD Coin"
+desc = "USDC is a fully collateralized US Dollar stablecoin"
+image = "https://www.centre.io/images/usdc/usdc-icon-86074d9d49.png"
+display_decimals = 2
+status = "live"
+is_asset_anchored = true
+anchor_asset_type = "fiat"
+anchor_asset = "USD"
+`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	fetcher := newAssetMetadataFetcher(server.URL, 0, time.Hour)
+	fetcher.tomlURL = func(domain string) string { return server.URL + "/" + domain + "/.well-known/stellar.toml" }
+	fetcher.now = func() time.Time { return fetchedAt }
+
+	usdc := fetcher.metadata(context.Background(), transform.AssetOutput{AssetCode: "USDC", AssetIssuer: usdcIssuer, AssetType: "credit_alphanum4", AssetID: 1})
+	assert.Equal(t, transform.AssetMetadataOutput{
+		AssetCode:       "USDC",
+		AssetIssuer:     usdcIssuer,
+		AssetType:       "credit_alphanum4",
+		AssetID:         1,
+		HomeDomain:      "centre.io",
+		OrgName:         "Centre Consortium",
+		Name:            "USD Coin",
+		Description:     "USDC is a fully collateralized US Dollar stablecoin",
+		Image:           "https://www.centre.io/images/usdc/usdc-icon-86074d9d49.png",
+		DisplayDecimals: null.IntFrom(2),
+		Status:          "live",
+		IsAssetAnchored: true,
+		AnchorAssetType: "fiat",
+		AnchorAsset:     "USD",
+		FetchedAt:       fetchedAt,
+	}, usdc)
+
+	// A second asset of the same issuer is cached, and another issuer with the same home domain doesn't fetch it again
+	usdcAgain := fetcher.metadata(context.Background(), transform.AssetOutput{AssetCode: "USDC", AssetIssuer: usdcIssuer, AssetID: 1})
+	assert.Equal(t, "USD Coin", usdcAgain.Name)
+	yxlm := fetcher.metadata(context.Background(), transform.AssetOutput{AssetCode: "yXLM", AssetIssuer: yxlmIssuer, AssetID: 2})
+	assert.Equal(t, "the stellar.toml of centre.io does not list the asset", yxlm.MetadataError)
+	assert.Equal(t, "Centre Consortium", yxlm.OrgName)
+	note := fetcher.metadata(context.Background(), transform.AssetOutput{AssetCode: "NOTE", AssetIssuer: noteIssuer, AssetID: 3})
+	assert.Equal(t, "the issuer has no home domain", note.MetadataError)
+
+	assert.Equal(t, map[string]int{
+		"/accounts/" + usdcIssuer:             1,
+		"/accounts/" + yxlmIssuer:             1,
+		"/accounts/" + noteIssuer:             1,
+		"/centre.io/.well-known/stellar.toml": 1,
+	}, requests)
+	assert.Equal(t, 4, fetcher.numRequests)
+	assert.Equal(t, 1, fetcher.numCached)
+
+	// Only the issuers whose metadata was read are cached across runs, until they are older than the ttl
+	cachePath := filepath.Join(t.TempDir(), "asset_metadata_cache.json")
+	assert.NoError(t, fetcher.saveCache(cachePath))
+
+	cached := newAssetMetadataFetcher(server.URL, 0, time.Hour)
+	cached.now = func() time.Time { return fetchedAt.Add(30 * time.Minute) }
+	assert.NoError(t, cached.loadCache(cachePath))
+	assert.ElementsMatch(t, []string{usdcIssuer, yxlmIssuer}, cachedIssuers(cached.issuers))
+
+	expired := newAssetMetadataFetcher(server.URL, 0, time.Hour)
+	expired.now = func() time.Time { return fetchedAt.Add(2 * time.Hour) }
+	assert.NoError(t, expired.loadCache(cachePath))
+	assert.Empty(t, expired.issuers)
+}
+
+func cachedIssuers(issuers map[string]issuerMetadata) []string {
+	names := []string{}
+	for name := range issuers {
+		names = append(names, name)
+	}
+	return names
+}
+
+func TestAssetMetadataFetcherTomlTooLarge(t *testing.T) {
+	largeAccount := `{"home_domain":"large.example","padding":"` + strings.Repeat("a", stellarTomlMaxSize) + `"}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/accounts/" + usdcIssuer:
+			w.Write([]byte(largeAccount))
+		case "/large.example/.well-known/stellar.toml":
+			w.Write([]byte(`VERSION = "2.0.0"` + "\n#" + strings.Repeat("a", stellarTomlMaxSize)))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	fetcher := newAssetMetadataFetcher(server.URL, 0, time.Hour)
+	fetcher.tomlURL = func(domain string) string { return server.URL + "/" + domain + "/.well-known/stellar.toml" }
+
+	// Horizon responses aren't limited, but a stellar.toml above the limit fails instead of being cut short
+	usdc := fetcher.metadata(context.Background(), transform.AssetOutput{AssetCode: "USDC", AssetIssuer: usdcIssuer, AssetID: 1})
+	assert.Equal(t, "large.example", usdc.HomeDomain)
+	assert.Equal(t, "could not read the stellar.toml of large.example: "+server.URL+"/large.example/.well-known/stellar.toml is too large, it is larger than 102400 bytes", usdc.MetadataError)
+}
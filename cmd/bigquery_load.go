@@ -0,0 +1,317 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/stellar/stellar-etl/v2/internal/toid"
+	"github.com/stellar/stellar-etl/v2/internal/transform"
+	"github.com/stellar/stellar-etl/v2/internal/utils"
+	"golang.org/x/oauth2/google"
+)
+
+// bigQueryEndpoint is the endpoint of the BigQuery API
+const bigQueryEndpoint = "https://bigquery.googleapis.com/bigquery/v2"
+
+// bigQueryLoadPollInterval is how often a load job is checked while the export waits for it
+const bigQueryLoadPollInterval = 5 * time.Second
+
+// bigQueryLoadTimeout is how long the export waits for a load job to finish
+const bigQueryLoadTimeout = time.Hour
+
+// bigQueryTable is a BigQuery table, named as <project>.<dataset>.<table>
+type bigQueryTable struct {
+	ProjectID string `json:"projectId"`
+	DatasetID string `json:"datasetId"`
+	TableID   string `json:"tableId"`
+}
+
+// parseBigQueryTable parses a table named as <project>.<dataset>.<table>
+func parseBigQueryTable(name string) (bigQueryTable, error) {
+	parts := strings.Split(name, ".")
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		return bigQueryTable{}, fmt.Errorf("bigquery table %s must have the form <project>.<dataset>.<table>", name)
+	}
+	return bigQueryTable{ProjectID: parts[0], DatasetID: parts[1], TableID: parts[2]}, nil
+}
+
+// bigQueryChunk is the part of a table that an export replaces: the rows of the ledgers it exported, which filter selects.
+// Its token is the chunk token of the export, which names its jobs and its staging table.
+type bigQueryChunk struct {
+	token  string
+	filter string
+}
+
+// newBigQueryChunk returns the chunk of a table with the parquet schema that holds the ledgers [start, end] of a dataset.
+// Rows are matched to ledgers by their ledger sequence, or by the TOID of their operation, which starts with the ledger
+// sequence.
+func newBigQueryChunk(network, dataset string, parquetSchema interface{}, start, end uint32) (bigQueryChunk, error) {
+	schemaType := reflect.TypeOf(parquetSchema)
+	for schemaType.Kind() == reflect.Pointer {
+		schemaType = schemaType.Elem()
+	}
+	columns := map[string]bool{}
+	for i := 0; i < schemaType.NumField(); i++ {
+		columns[utils.ParseParquetTag(schemaType.Field(i).Tag.Get("parquet"))["name"]] = true
+	}
+
+	chunk := bigQueryChunk{token: chunkToken(network, dataset, start, end)}
+	switch {
+	case columns["ledger_sequence"]:
+		chunk.filter = fmt.Sprintf("ledger_sequence BETWEEN %d AND %d", start, end)
+	case schemaType == reflect.TypeOf(transform.LedgerOutputParquet{}):
+		chunk.filter = fmt.Sprintf("sequence BETWEEN %d AND %d", start, end)
+	case columns["history_operation_id"], columns["operation_id"]:
+		column := "operation_id"
+		if columns["history_operation_id"] {
+			column = "history_operation_id"
+		}
+		startID := toid.New(int32(start), 0, 0).ToInt64()
+		endID := toid.New(int32(end)+1, 0, 0).ToInt64()
+		chunk.filter = fmt.Sprintf("%s >= %d AND %s < %d", column, startID, column, endID)
+	default:
+		return bigQueryChunk{}, fmt.Errorf("the %s rows have no ledger sequence or operation id, so their ledgers can't be replaced in bigquery", schemaType.Name())
+	}
+	return chunk, nil
+}
+
+// bigQueryLoadStats are the statistics of a finished load job
+type bigQueryLoadStats struct {
+	JobID       string
+	InputFiles  int64
+	InputBytes  int64
+	OutputRows  int64
+	OutputBytes int64
+}
+
+// bigQueryJob is the part of a BigQuery job resource that is sent to create a load job and read to follow it
+type bigQueryJob struct {
+	JobReference  *bigQueryJobReference     `json:"jobReference,omitempty"`
+	Configuration *bigQueryJobConfiguration `json:"configuration,omitempty"`
+	Status        *struct {
+		State       string `json:"state"`
+		ErrorResult *struct {
+			Message string `json:"message"`
+		} `json:"errorResult"`
+	} `json:"status,omitempty"`
+	Statistics *struct {
+		Load struct {
+			InputFiles     string `json:"inputFiles"`
+			InputFileBytes string `json:"inputFileBytes"`
+			OutputRows     string `json:"outputRows"`
+			OutputBytes    string `json:"outputBytes"`
+		} `json:"load"`
+	} `json:"statistics,omitempty"`
+}
+
+type bigQueryJobReference struct {
+	ProjectID string `json:"projectId"`
+	JobID     string `json:"jobId"`
+	Location  string `json:"location,omitempty"`
+}
+
+type bigQueryJobConfiguration struct {
+	Load  *bigQueryLoadConfiguration  `json:"load,omitempty"`
+	Query *bigQueryQueryConfiguration `json:"query,omitempty"`
+}
+
+type bigQueryLoadConfiguration struct {
+	SourceURIs        []string      `json:"sourceUris"`
+	SourceFormat      string        `json:"sourceFormat"`
+	DestinationTable  bigQueryTable `json:"destinationTable"`
+	WriteDisposition  string        `json:"writeDisposition"`
+	CreateDisposition string        `json:"createDisposition"`
+}
+
+type bigQueryQueryConfiguration struct {
+	Query string `json:"query"`
+	// Jobs default to legacy SQL, which doesn't support scripts
+	UseLegacySQL bool `json:"useLegacySql"`
+}
+
+// bigQueryAPIError is an error response of the BigQuery API
+type bigQueryAPIError struct {
+	statusCode int
+	status     string
+	body       []byte
+}
+
+func (e *bigQueryAPIError) Error() string {
+	return fmt.Sprintf("bigquery returned %s: %s", e.status, e.body)
+}
+
+// bigQueryLoader runs BigQuery load jobs of parquet files in GCS and waits for them to finish
+type bigQueryLoader struct {
+	client       *http.Client
+	endpoint     string
+	pollInterval time.Duration
+}
+
+func newBigQueryLoader(ctx context.Context) (*bigQueryLoader, error) {
+	client, err := google.DefaultClient(ctx, "https://www.googleapis.com/auth/bigquery")
+	if err != nil {
+		return nil, fmt.Errorf("could not create bigquery client: %v", err)
+	}
+	return &bigQueryLoader{client: client, endpoint: bigQueryEndpoint, pollInterval: bigQueryLoadPollInterval}, nil
+}
+
+// load replaces the rows of a chunk of a table with a parquet file in GCS, and returns the statistics of the load once
+// the table is updated. The file is loaded into a staging table, and a script then deletes the rows of the chunk from the
+// table and inserts the staged rows in a single transaction, so a retried or replanned chunk doesn't duplicate rows. The
+// table is created from the schema of the file if it doesn't exist.
+func (l *bigQueryLoader) load(ctx context.Context, table bigQueryTable, sourceURI string, chunk bigQueryChunk) (bigQueryLoadStats, error) {
+	staging := table
+	staging.TableID = fmt.Sprintf("%s_staging_%s", table.TableID, chunk.token)
+
+	loadJob, err := l.runJob(ctx, table.ProjectID, "stellar_etl_load_"+chunk.token, bigQueryJobConfiguration{Load: &bigQueryLoadConfiguration{
+		SourceURIs:        []string{sourceURI},
+		SourceFormat:      "PARQUET",
+		DestinationTable:  staging,
+		WriteDisposition:  "WRITE_TRUNCATE",
+		CreateDisposition: "CREATE_IF_NEEDED",
+	}})
+	if err != nil {
+		return bigQueryLoadStats{}, fmt.Errorf("could not load %s into staging table %s: %v", sourceURI, staging.TableID, err)
+	}
+
+	stats := bigQueryLoadStats{JobID: loadJob.JobReference.JobID}
+	if loadJob.Statistics != nil {
+		// The BigQuery API encodes 64 bit integers as strings
+		stats.InputFiles, _ = strconv.ParseInt(loadJob.Statistics.Load.InputFiles, 10, 64)
+		stats.InputBytes, _ = strconv.ParseInt(loadJob.Statistics.Load.InputFileBytes, 10, 64)
+		stats.OutputRows, _ = strconv.ParseInt(loadJob.Statistics.Load.OutputRows, 10, 64)
+		stats.OutputBytes, _ = strconv.ParseInt(loadJob.Statistics.Load.OutputBytes, 10, 64)
+	}
+
+	mergeJob, err := l.runJob(ctx, table.ProjectID, "stellar_etl_merge_"+chunk.token, bigQueryJobConfiguration{Query: &bigQueryQueryConfiguration{
+		Query: replaceChunkScript(table, staging, chunk),
+	}})
+	if err != nil {
+		return bigQueryLoadStats{}, fmt.Errorf("could not replace the chunk from staging table %s: %v", staging.TableID, err)
+	}
+	cmdLogger.Infof("Bigquery job %s replaced the rows where %s with the rows of %s", mergeJob.JobReference.JobID, chunk.filter, sourceURI)
+
+	return stats, nil
+}
+
+// replaceChunkScript returns the script that replaces the rows of a chunk of a table with the rows of its staging table,
+// then drops the staging table
+func replaceChunkScript(table, staging bigQueryTable, chunk bigQueryChunk) string {
+	tableName := fmt.Sprintf("`%s.%s.%s`", table.ProjectID, table.DatasetID, table.TableID)
+	stagingName := fmt.Sprintf("`%s.%s.%s`", staging.ProjectID, staging.DatasetID, staging.TableID)
+	return strings.Join([]string{
+		fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s LIKE %s;", tableName, stagingName),
+		"BEGIN TRANSACTION;",
+		fmt.Sprintf("DELETE FROM %s WHERE %s;", tableName, chunk.filter),
+		fmt.Sprintf("INSERT INTO %s SELECT * FROM %s;", tableName, stagingName),
+		"COMMIT TRANSACTION;",
+		fmt.Sprintf("DROP TABLE %s;", stagingName),
+	}, "\n")
+}
+
+// runJob runs a job and waits for it to finish. Job ids are the prefix followed by an attempt number, so the jobs of a
+// chunk can be found from its chunk token. If a job of an earlier attempt still exists, it is waited for, so that two
+// exports of the same chunk don't write it at the same time, and the job runs as the next attempt.
+func (l *bigQueryLoader) runJob(ctx context.Context, projectID, jobIDPrefix string, configuration bigQueryJobConfiguration) (bigQueryJob, error) {
+	for attempt := 0; ; attempt++ {
+		reference := bigQueryJobReference{ProjectID: projectID, JobID: fmt.Sprintf("%s_%d", jobIDPrefix, attempt)}
+		body, err := json.Marshal(bigQueryJob{JobReference: &reference, Configuration: &configuration})
+		if err != nil {
+			return bigQueryJob{}, err
+		}
+
+		job, err := l.call(ctx, http.MethodPost, fmt.Sprintf("%s/projects/%s/jobs", l.endpoint, url.PathEscape(projectID)), body)
+		var apiErr *bigQueryAPIError
+		if errors.As(err, &apiErr) && apiErr.statusCode == http.StatusConflict {
+			cmdLogger.Infof("Bigquery job %s of an earlier attempt exists, waiting for it before the next attempt", reference.JobID)
+			if _, err := l.wait(ctx, bigQueryJob{JobReference: &reference}); err != nil {
+				return bigQueryJob{}, err
+			}
+			continue
+		}
+		if err != nil {
+			return bigQueryJob{}, fmt.Errorf("could not create job %s: %v", reference.JobID, err)
+		}
+		if job.JobReference == nil {
+			return bigQueryJob{}, fmt.Errorf("bigquery did not return job %s", reference.JobID)
+		}
+		cmdLogger.Infof("Started bigquery job %s", job.JobReference.JobID)
+
+		job, err = l.wait(ctx, job)
+		if err != nil {
+			return bigQueryJob{}, err
+		}
+		if job.Status.ErrorResult != nil {
+			return bigQueryJob{}, fmt.Errorf("job %s failed: %s", job.JobReference.JobID, job.Status.ErrorResult.Message)
+		}
+		return job, nil
+	}
+}
+
+// wait polls a job until it is done
+func (l *bigQueryLoader) wait(ctx context.Context, job bigQueryJob) (bigQueryJob, error) {
+	reference := *job.JobReference
+	for job.Status == nil || job.Status.State != "DONE" {
+		select {
+		case <-ctx.Done():
+			return bigQueryJob{}, fmt.Errorf("job %s did not finish: %v", reference.JobID, ctx.Err())
+		case <-time.After(l.pollInterval):
+		}
+
+		jobURL := fmt.Sprintf("%s/projects/%s/jobs/%s", l.endpoint, url.PathEscape(reference.ProjectID), url.PathEscape(reference.JobID))
+		if reference.Location != "" {
+			jobURL += "?location=" + url.QueryEscape(reference.Location)
+		}
+
+		var err error
+		job, err = l.call(ctx, http.MethodGet, jobURL, nil)
+		if err != nil {
+			return bigQueryJob{}, fmt.Errorf("could not get job %s: %v", reference.JobID, err)
+		}
+		if job.JobReference == nil {
+			job.JobReference = &reference
+		}
+	}
+	return job, nil
+}
+
+// call sends a request to the BigQuery API and decodes the job it returns
+func (l *bigQueryLoader) call(ctx context.Context, method, requestURL string, body []byte) (bigQueryJob, error) {
+	request, err := http.NewRequestWithContext(ctx, method, requestURL, bytes.NewReader(body))
+	if err != nil {
+		return bigQueryJob{}, err
+	}
+	if body != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := l.client.Do(request)
+	if err != nil {
+		return bigQueryJob{}, fmt.Errorf("could not call bigquery: %v", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return bigQueryJob{}, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return bigQueryJob{}, &bigQueryAPIError{statusCode: resp.StatusCode, status: resp.Status, body: respBody}
+	}
+
+	var job bigQueryJob
+	if err := json.Unmarshal(respBody, &job); err != nil {
+		return bigQueryJob{}, fmt.Errorf("could not decode bigquery response: %v", err)
+	}
+	return job, nil
+}
@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stellar/stellar-etl/v2/internal/transform"
+)
+
+func TestParseBigQueryTable(t *testing.T) {
+	table, err := parseBigQueryTable("my-project.crypto_stellar.history_trades")
+	assert.NoError(t, err)
+	assert.Equal(t, bigQueryTable{ProjectID: "my-project", DatasetID: "crypto_stellar", TableID: "history_trades"}, table)
+
+	_, err = parseBigQueryTable("crypto_stellar.history_trades")
+	assert.EqualError(t, err, "bigquery table crypto_stellar.history_trades must have the form <project>.<dataset>.<table>")
+}
+
+func TestNewBigQueryChunk(t *testing.T) {
+	chunk, err := newBigQueryChunk("testnet", "effects", new(transform.EffectOutputParquet), 100, 200)
+	assert.NoError(t, err)
+	assert.Equal(t, bigQueryChunk{token: chunkToken("testnet", "effects", 100, 200), filter: "ledger_sequence BETWEEN 100 AND 200"}, chunk)
+
+	chunk, err = newBigQueryChunk("testnet", "ledgers", new(transform.LedgerOutputParquet), 100, 200)
+	assert.NoError(t, err)
+	assert.Equal(t, "sequence BETWEEN 100 AND 200", chunk.filter)
+
+	// Trades have no ledger sequence, so they are matched by the TOID of their operation
+	chunk, err = newBigQueryChunk("testnet", "trades", new(transform.TradeOutputParquet), 100, 200)
+	assert.NoError(t, err)
+	assert.Equal(t, "history_operation_id >= 429496729600 AND history_operation_id < 863288426496", chunk.filter)
+
+	_, err = newBigQueryChunk("testnet", "asset_metadata", new(transform.AssetMetadataOutputParquet), 100, 200)
+	assert.EqualError(t, err, "the AssetMetadataOutputParquet rows have no ledger sequence or operation id, so their ledgers can't be replaced in bigquery")
+}
+
+func TestBigQueryLoader(t *testing.T) {
+	table := bigQueryTable{ProjectID: "my-project", DatasetID: "crypto_stellar", TableID: "history_trades"}
+	staging := bigQueryTable{ProjectID: "my-project", DatasetID: "crypto_stellar", TableID: "history_trades_staging_0123456789abcdef"}
+	chunk := bigQueryChunk{token: "0123456789abcdef", filter: "ledger_sequence BETWEEN 100 AND 200"}
+
+	created := []bigQueryJob{}
+	polls := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/projects/my-project/jobs":
+			body, err := io.ReadAll(r.Body)
+			assert.NoError(t, err)
+			var job bigQueryJob
+			assert.NoError(t, json.Unmarshal(body, &job))
+			// The first attempt of the load job was created by an earlier export of the chunk
+			if job.JobReference.JobID == "stellar_etl_load_0123456789abcdef_0" {
+				w.WriteHeader(http.StatusConflict)
+				w.Write([]byte(`{"error":{"message":"Already Exists: Job my-project:US.stellar_etl_load_0123456789abcdef_0"}}`))
+				return
+			}
+			created = append(created, job)
+			w.Write([]byte(`{"jobReference":{"projectId":"my-project","jobId":"` + job.JobReference.JobID + `","location":"US"},"status":{"state":"RUNNING"}}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/projects/my-project/jobs/stellar_etl_load_0123456789abcdef_0":
+			polls["earlier"]++
+			w.Write([]byte(`{"status":{"state":"DONE","errorResult":{"message":"Access Denied"}}}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/projects/my-project/jobs/stellar_etl_load_0123456789abcdef_1":
+			assert.Equal(t, "US", r.URL.Query().Get("location"))
+			polls["load"]++
+			if polls["load"] < 2 {
+				w.Write([]byte(`{"jobReference":{"projectId":"my-project","jobId":"stellar_etl_load_0123456789abcdef_1","location":"US"},"status":{"state":"RUNNING"}}`))
+				return
+			}
+			w.Write([]byte(`{"jobReference":{"projectId":"my-project","jobId":"stellar_etl_load_0123456789abcdef_1","location":"US"},"status":{"state":"DONE"},` +
+				`"statistics":{"load":{"inputFiles":"1","inputFileBytes":"2048","outputRows":"120","outputBytes":"4096"}}}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/projects/my-project/jobs/stellar_etl_merge_0123456789abcdef_0":
+			polls["merge"]++
+			w.Write([]byte(`{"jobReference":{"projectId":"my-project","jobId":"stellar_etl_merge_0123456789abcdef_0","location":"US"},"status":{"state":"DONE"}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	loader := &bigQueryLoader{client: server.Client(), endpoint: server.URL}
+	stats, err := loader.load(context.Background(), table, "gs://my-bucket/exported_trades.parquet", chunk)
+	assert.NoError(t, err)
+	assert.Equal(t, bigQueryLoadStats{JobID: "stellar_etl_load_0123456789abcdef_1", InputFiles: 1, InputBytes: 2048, OutputRows: 120, OutputBytes: 4096}, stats)
+	assert.Equal(t, map[string]int{"earlier": 1, "load": 2, "merge": 1}, polls)
+
+	// The file is staged with WRITE_TRUNCATE, so retried loads replace the staged rows
+	assert.Len(t, created, 2)
+	assert.Equal(t, "stellar_etl_load_0123456789abcdef_1", created[0].JobReference.JobID)
+	assert.Equal(t, &bigQueryLoadConfiguration{
+		SourceURIs:        []string{"gs://my-bucket/exported_trades.parquet"},
+		SourceFormat:      "PARQUET",
+		DestinationTable:  staging,
+		WriteDisposition:  "WRITE_TRUNCATE",
+		CreateDisposition: "CREATE_IF_NEEDED",
+	}, created[0].Configuration.Load)
+
+	// The rows of the chunk are then replaced in a transaction
+	assert.Equal(t, "stellar_etl_merge_0123456789abcdef_0", created[1].JobReference.JobID)
+	assert.Equal(t, &bigQueryQueryConfiguration{Query: "CREATE TABLE IF NOT EXISTS `my-project.crypto_stellar.history_trades` LIKE `my-project.crypto_stellar.history_trades_staging_0123456789abcdef`;\n" +
+		"BEGIN TRANSACTION;\n" +
+		"DELETE FROM `my-project.crypto_stellar.history_trades` WHERE ledger_sequence BETWEEN 100 AND 200;\n" +
+		"INSERT INTO `my-project.crypto_stellar.history_trades` SELECT * FROM `my-project.crypto_stellar.history_trades_staging_0123456789abcdef`;\n" +
+		"COMMIT TRANSACTION;\n" +
+		"DROP TABLE `my-project.crypto_stellar.history_trades_staging_0123456789abcdef`;"}, created[1].Configuration.Query)
+}
+
+func TestBigQueryLoaderJobError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"jobReference":{"projectId":"my-project","jobId":"stellar_etl_load_0123456789abcdef_0"},"status":{"state":"DONE","errorResult":{"message":"Provided Schema does not match Table"}}}`))
+	}))
+	defer server.Close()
+
+	loader := &bigQueryLoader{client: server.Client(), endpoint: server.URL}
+	chunk := bigQueryChunk{token: "0123456789abcdef", filter: "ledger_sequence BETWEEN 100 AND 200"}
+	_, err := loader.load(context.Background(), bigQueryTable{ProjectID: "my-project", DatasetID: "crypto_stellar", TableID: "history_trades"}, "gs://my-bucket/exported_trades.parquet", chunk)
+	assert.EqualError(t, err, "could not load gs://my-bucket/exported_trades.parquet into staging table history_trades_staging_0123456789abcdef: job stellar_etl_load_0123456789abcdef_0 failed: Provided Schema does not match Table")
+}
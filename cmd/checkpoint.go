@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// readCheckpoint returns the last exported ledger saved in a checkpoint file. found is false if the file doesn't exist,
+// which means the export hasn't run before.
+func readCheckpoint(path string) (lastLedger uint32, found bool, err error) {
+	contents, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("could not read checkpoint %s: %v", path, err)
+	}
+
+	parsed, err := strconv.ParseUint(strings.TrimSpace(string(contents)), 10, 32)
+	if err != nil {
+		return 0, false, fmt.Errorf("could not parse checkpoint %s: %v", path, err)
+	}
+
+	return uint32(parsed), true, nil
+}
+
+// writeCheckpoint saves the last exported ledger to a checkpoint file. The file is replaced by a rename, so it never
+// holds a partially written ledger even if the process is killed.
+func writeCheckpoint(path string, lastLedger uint32) error {
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return fmt.Errorf("could not create checkpoint directory: %v", err)
+	}
+
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, []byte(strconv.FormatUint(uint64(lastLedger), 10)+"\n"), 0644); err != nil {
+		return fmt.Errorf("could not write checkpoint %s: %v", path, err)
+	}
+
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("could not write checkpoint %s: %v", path, err)
+	}
+
+	return nil
+}
+
+// mustAppendOutFile opens an output file to add records after the ones an earlier run wrote to it
+func mustAppendOutFile(path string) *os.File {
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		cmdLogger.Fatalf("could not create directory %s: %s", path, err)
+	}
+
+	outFile, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		cmdLogger.Fatal("error in opening output file: ", err)
+	}
+
+	return outFile
+}
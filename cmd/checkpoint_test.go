@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckpoint(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoints", "effects.checkpoint")
+
+	_, found, err := readCheckpoint(path)
+	assert.NoError(t, err)
+	assert.False(t, found)
+
+	assert.NoError(t, writeCheckpoint(path, 52000000))
+	assert.NoError(t, writeCheckpoint(path, 52000001))
+
+	lastLedger, found, err := readCheckpoint(path)
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, uint32(52000001), lastLedger)
+
+	_, err = os.Stat(path + ".tmp")
+	assert.True(t, os.IsNotExist(err))
+
+	assert.NoError(t, os.WriteFile(path, []byte("not a ledger"), 0644))
+	_, _, err = readCheckpoint(path)
+	assert.Error(t, err)
+}
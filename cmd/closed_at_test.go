@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stellar/stellar-etl/v2/internal/transform"
+)
+
+func TestMarshalEntryClosedAtMillis(t *testing.T) {
+	// The output must not depend on the timezone of the machine running the export
+	local := time.Local
+	time.Local = time.FixedZone("UTC+2", 2*60*60)
+	defer func() { time.Local = local }()
+
+	closedAt := time.Date(2020, time.July, 9, 5, 28, 42, 0, time.UTC)
+	type entry struct {
+		ClosedAt       time.Time `json:"closed_at"`
+		LedgerClosedAt time.Time `json:"ledger_closed_at"`
+	}
+	record := entry{ClosedAt: closedAt, LedgerClosedAt: closedAt.In(time.Local)}
+
+	marshalled, err := MarshalEntry(record, nil, false, true)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"closed_at": "2020-07-09T05:28:42Z",
+		"closed_at_ms": 1594272522000,
+		"ledger_closed_at": "2020-07-09T05:28:42Z",
+		"ledger_closed_at_ms": 1594272522000
+	}`, string(marshalled))
+
+	// Without the flag the close times are written as they are
+	marshalled, err = MarshalEntry(record, nil, false, false)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"closed_at": "2020-07-09T05:28:42Z", "ledger_closed_at": "2020-07-09T07:28:42+02:00"}`, string(marshalled))
+
+	// Parquet timestamps are epoch milliseconds, which are the same in every location
+	parquet := transform.ContractEventOutput{ClosedAt: closedAt.In(time.Local)}.ToParquet().(transform.ContractEventOutputParquet)
+	assert.Equal(t, int64(1594272522000), parquet.ClosedAt)
+}
@@ -6,8 +6,14 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
 
+	"github.com/stellar/stellar-etl/v2/internal/arrowipc"
+	"github.com/stellar/stellar-etl/v2/internal/orc"
 	"github.com/stellar/stellar-etl/v2/internal/transform"
+	"github.com/stellar/stellar-etl/v2/internal/utils"
 	"github.com/xitongsys/parquet-go-source/local"
 	"github.com/xitongsys/parquet-go/writer"
 )
@@ -52,7 +58,33 @@ func MustOutFile(path string) *os.File {
 	return outFile
 }
 
-func ExportEntry(entry interface{}, outFile *os.File, extra map[string]string) (int, error) {
+// ExportEntry writes an entry to the output file as a single line of JSON, together with the extra fields.
+// If stringifyLargeInts is set, 64 bit integers are written as strings so that consumers that decode numbers as doubles don't lose precision.
+// If closedAtMillis is set, the close times of the entry are also written as unix epoch milliseconds.
+func ExportEntry(entry interface{}, outFile *os.File, extra map[string]string, stringifyLargeInts, closedAtMillis bool) (int, error) {
+	marshalled, err := MarshalEntry(entry, extra, stringifyLargeInts, closedAtMillis)
+	if err != nil {
+		return 0, err
+	}
+	return writeEntryLine(marshalled, outFile), nil
+}
+
+// writeEntryLine writes an entry that was encoded with MarshalEntry to the output file, and returns the number of bytes written
+func writeEntryLine(marshalled []byte, outFile *os.File) int {
+	cmdLogger.Debugf("Writing entry to %s", outFile.Name())
+	numBytes, err := outFile.Write(marshalled)
+	if err != nil {
+		cmdLogger.Errorf("Error writing %s to file: %s", marshalled, err)
+	}
+	newLineNumBytes, err := outFile.WriteString("\n")
+	if err != nil {
+		cmdLogger.Errorf("Error writing new line to file %s: %s", outFile.Name(), err)
+	}
+	return numBytes + newLineNumBytes
+}
+
+// MarshalEntry encodes an entry as a JSON object with the extra fields added, the same way it is written to the output files
+func MarshalEntry(entry interface{}, extra map[string]string, stringifyLargeInts, closedAtMillis bool) ([]byte, error) {
 	// This extra marshalling/unmarshalling is silly, but it's required to properly handle the null.[String|Int*] types, and add the extra fields.
 	m, err := json.Marshal(entry)
 	if err != nil {
@@ -66,35 +98,100 @@ func ExportEntry(entry interface{}, outFile *os.File, extra map[string]string) (
 	if err != nil {
 		cmdLogger.Errorf("Error unmarshalling %+v: %v ", i, err)
 	}
+	if stringifyLargeInts {
+		stringifyLargeIntValues(reflect.ValueOf(entry), i)
+	}
+	if closedAtMillis {
+		if err := addClosedAtMillis(i); err != nil {
+			return nil, err
+		}
+	}
 	for k, v := range extra {
 		i[k] = v
 	}
 
 	marshalled, err := json.Marshal(i)
 	if err != nil {
-		return 0, fmt.Errorf("could not json encode %+v: %s", entry, err)
+		return nil, fmt.Errorf("could not json encode %+v: %s", entry, err)
 	}
-	cmdLogger.Debugf("Writing entry to %s", outFile.Name())
-	numBytes, err := outFile.Write(marshalled)
-	if err != nil {
-		cmdLogger.Errorf("Error writing %+v to file: %s", entry, err)
-	}
-	newLineNumBytes, err := outFile.WriteString("\n")
-	if err != nil {
-		cmdLogger.Errorf("Error writing new line to file %s: %s", outFile.Name(), err)
+	return marshalled, nil
+}
+
+// addClosedAtMillis rewrites the close times of a decoded entry as RFC3339 in UTC, whatever the location of the time
+// they were encoded from, and adds them as unix epoch milliseconds in a field with the _ms suffix, such as closed_at_ms
+func addClosedAtMillis(entry map[string]interface{}) error {
+	for _, field := range closedAtFields {
+		value, ok := entry[field].(string)
+		if !ok {
+			continue
+		}
+		closedAt, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			return fmt.Errorf("could not parse %s %q: %v", field, value, err)
+		}
+		entry[field] = closedAt.UTC().Format(time.RFC3339Nano)
+		entry[field+"_ms"] = closedAt.UnixMilli()
 	}
-	return numBytes + newLineNumBytes, nil
+	return nil
 }
 
 // Prints the number of attempted, failed, and successful transformations as a JSON object
-func PrintTransformStats(attempts, failures int) {
-	resultsMap := map[string]int{
-		"attempted_transforms":  attempts,
-		"failed_transforms":     failures,
-		"successful_transforms": attempts - failures,
+// Failure classes group the failed transforms and exports in the transform stats
+const (
+	failureUnknownType   = "unknown_type"
+	failureMissingResult = "missing_result"
+	failureXDRDecode     = "xdr_decode"
+	failureSink          = "sink"
+	failurePanic         = "panic"
+	failureOther         = "other"
+)
+
+// failureClassPatterns are the phrases of the transform errors of each failure class, checked in order
+var failureClassPatterns = []struct {
+	class    string
+	patterns []string
+}{
+	{failureUnknownType, []string{"unknown operation type", "unknown host function type", "unknown upgrade type", "unknown event type", "does not result in"}},
+	{failureMissingResult, []string{"result"}},
+	{failureXDRDecode, []string{"xdr", "decode", "unmarshal"}},
+}
+
+// classifyFailure returns the failure class of a transform error
+func classifyFailure(err error) string {
+	message := strings.ToLower(err.Error())
+	for _, classPatterns := range failureClassPatterns {
+		for _, pattern := range classPatterns.patterns {
+			if strings.Contains(message, pattern) {
+				return classPatterns.class
+			}
+		}
+	}
+	return failureOther
+}
+
+// transformStats are the counts of an export that are logged once it finishes
+type transformStats struct {
+	Dataset              string         `json:"dataset"`
+	AttemptedTransforms  int            `json:"attempted_transforms"`
+	FailedTransforms     int            `json:"failed_transforms"`
+	SuccessfulTransforms int            `json:"successful_transforms"`
+	FailuresByClass      map[string]int `json:"failures_by_class"`
+}
+
+// PrintTransformStats logs the number of attempted, failed and successful transforms of a dataset as JSON, with the
+// failures broken down by class so that failed jobs can be triaged from their logs
+func PrintTransformStats(dataset string, attempts, failures int, failuresByClass map[string]int) {
+	if failuresByClass == nil {
+		failuresByClass = map[string]int{}
 	}
 
-	results, err := json.Marshal(resultsMap)
+	results, err := json.Marshal(transformStats{
+		Dataset:              dataset,
+		AttemptedTransforms:  attempts,
+		FailedTransforms:     failures,
+		SuccessfulTransforms: attempts - failures,
+		FailuresByClass:      failuresByClass,
+	})
 	if err != nil {
 		cmdLogger.Fatal("Could not marshal results: ", err)
 	}
@@ -102,12 +199,23 @@ func PrintTransformStats(attempts, failures int) {
 	cmdLogger.Info(string(results))
 }
 
-func exportFilename(start, end uint32, dataType string) string {
-	return fmt.Sprintf("%d-%d-%s.txt", start, end-1, dataType)
+// limitReached returns true when the number of exported records has reached the provided limit.
+// A negative limit means that there is no limit on the number of exported records.
+func limitReached(numRecords int, limit int64) bool {
+	return limit >= 0 && int64(numRecords) >= limit
 }
 
-func exportParquetFilename(start, end uint32, dataType string) string {
-	return fmt.Sprintf("%d-%d-%s.parquet", start, end-1, dataType)
+// exportFilename names the output file of the ledgers [start, end) of a dataset, with the filename template if one is set
+func exportFilename(filenameTemplate, network, chunkToken string, start, end uint32, dataType, ext string) string {
+	if filenameTemplate == "" {
+		return fmt.Sprintf("%d-%d-%s.%s", start, end-1, dataType, ext)
+	}
+
+	name, err := utils.ResolveFilenameTemplate(filenameTemplate, network, dataType, chunkToken, start, end-1, ext)
+	if err != nil {
+		cmdLogger.Fatal("could not resolve filename-template: ", err)
+	}
+	return name
 }
 
 func deleteLocalFiles(path string) error {
@@ -120,28 +228,165 @@ func deleteLocalFiles(path string) error {
 	return nil
 }
 
-func MaybeUpload(cloudCredentials, cloudStorageBucket, cloudProvider, path string) {
+// alignArchiveRange widens the range of an export that reads the history archives to the checkpoints that contain it,
+// because the archives only have the ledgers of published checkpoints. The adjustment is logged, and with noAlign an
+// unaligned range is an error instead.
+func alignArchiveRange(cmdArgs *utils.FlagValues, noAlign bool) error {
+	start, end := utils.AlignToCheckpoints(cmdArgs.StartNum, cmdArgs.EndNum)
+	if start == cmdArgs.StartNum && end == cmdArgs.EndNum {
+		return nil
+	}
+
+	if noAlign {
+		return fmt.Errorf("the range %d-%d is not aligned to checkpoints, which start at multiples of 64 and end one ledger before; the checkpoints containing it cover %d-%d", cmdArgs.StartNum, cmdArgs.EndNum, start, end)
+	}
+	cmdLogger.Infof("History archives are read a checkpoint of 64 ledgers at a time, so the range %d-%d was aligned to the checkpoints %d-%d that contain it", cmdArgs.StartNum, cmdArgs.EndNum, start, end)
+	cmdArgs.StartNum, cmdArgs.EndNum = start, end
+	return nil
+}
+
+// MaybeUpload uploads a file to the cloud provider, if one is set. Failed uploads are retried with a wait that doubles
+// every time. If the file can't be uploaded after all retries, the export stops, unless the options keep the local
+// file instead. The local file is only deleted after a verified upload if the options ask for it. If the options ask
+// for checksums, the checksum file is written first, even without a cloud provider, and uploaded after the file. It
+// returns the path the file was uploaded to, which has the checksum with content-addressed, or an empty path if the
+// file was not uploaded.
+func MaybeUpload(cloudCredentials, cloudStorageBucket, cloudProvider, path string, options utils.UploadOptions) string {
+	return maybeUploadWithSidecars(cloudCredentials, cloudStorageBucket, cloudProvider, path, options, nil)
+}
+
+// maybeUploadWithSidecars is MaybeUpload for output files with sidecar files, such as their index. writeSidecars is
+// called with the final path of the output file, once it has been renamed to its checksum, and returns the paths of the
+// sidecar files it wrote, which are uploaded after the file.
+func maybeUploadWithSidecars(cloudCredentials, cloudStorageBucket, cloudProvider, path string, options utils.UploadOptions, writeSidecars func(outputPath string) ([]string, error)) string {
+	paths := []string{path}
+	if options.ChecksumFiles || options.ContentAddressed {
+		outputPath, checksumPath, err := checksumOutputFile(path, options.ContentAddressed)
+		if err != nil {
+			cmdLogger.Fatal(err)
+		}
+		cmdLogger.Infof("Wrote checksum file %s", checksumPath)
+		paths = []string{outputPath, checksumPath}
+	}
+
+	if writeSidecars != nil {
+		sidecarPaths, err := writeSidecars(paths[0])
+		if err != nil {
+			cmdLogger.Fatal(err)
+		}
+		paths = append(paths, sidecarPaths...)
+	}
+
 	if cloudProvider == "" {
 		cmdLogger.Info("No cloud provider specified for upload. Skipping upload.")
-		return
+		return ""
 	}
 
 	if len(cloudStorageBucket) == 0 {
 		cmdLogger.Fatal("No bucket specified")
-		return
+		return ""
 	}
 
 	var cloudStorage CloudStorage
 	switch cloudProvider {
 	case "gcp":
-		cloudStorage = newGCS(cloudCredentials, cloudStorageBucket)
-		err := cloudStorage.UploadTo(cloudCredentials, cloudStorageBucket, path)
-		if err != nil {
-			cmdLogger.Fatalf("Unable to upload output to GCS: %s", err)
-			return
-		}
+		cloudStorage = newGCS(cloudCredentials, cloudStorageBucket, options.ChunkSizeMB)
 	default:
 		cmdLogger.Fatal("Unknown cloud provider")
+		return ""
+	}
+
+	uploadedPath := paths[0]
+	for i, path := range paths {
+		err := uploadFile(cloudStorage, cloudCredentials, cloudStorageBucket, path, options)
+		if err == nil {
+			continue
+		}
+		if options.KeepLocalOnFailure {
+			cmdLogger.Errorf("Unable to upload output to %s, keeping the local file %s: %s", cloudProvider, path, err)
+			if i == 0 {
+				uploadedPath = ""
+			}
+			continue
+		}
+		cmdLogger.Fatalf("Unable to upload output to %s: %s", cloudProvider, err)
+	}
+	return uploadedPath
+}
+
+// uploadFile uploads a file with the retries of the options, and deletes the local file once it is uploaded if
+// DeleteLocalAfterUpload is set. Uploads return an error unless the uploaded object has the checksum of the local file.
+func uploadFile(cloudStorage CloudStorage, credentialsPath, bucket, path string, options utils.UploadOptions) error {
+	err := uploadWithRetries(cloudStorage, credentialsPath, bucket, path, options.Retries, time.Duration(options.RetryWait)*time.Second)
+	if err != nil {
+		return err
+	}
+
+	if options.DeleteLocalAfterUpload {
+		deleteLocalFiles(path)
+	}
+	return nil
+}
+
+// uploadWithRetries uploads a file, and retries failed uploads up to retries times
+func uploadWithRetries(cloudStorage CloudStorage, credentialsPath, bucket, path string, retries uint32, retryWait time.Duration) error {
+	for attempt := uint32(1); ; attempt++ {
+		err := cloudStorage.UploadTo(credentialsPath, bucket, path)
+		if err == nil {
+			return nil
+		}
+		if attempt > retries {
+			return fmt.Errorf("could not upload %s after %d attempts: %v", path, attempt, err)
+		}
+
+		wait := retryWait << (attempt - 1)
+		cmdLogger.Warnf("Could not upload %s, retrying in %s: %v", path, wait, err)
+		time.Sleep(wait)
+	}
+}
+
+// mustMakeParentDir creates the folder of an output file, which filename templates can name
+func mustMakeParentDir(path string) {
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		cmdLogger.Fatalf("could not create directory %s: %s", path, err)
+	}
+}
+
+// WriteORC creates the ORC file and writes the exported data into it, using the parquet schema of the data
+func WriteORC(data []transform.SchemaParquet, path string, schema interface{}) {
+	mustMakeParentDir(path)
+	orcFile, err := os.Create(path)
+	if err != nil {
+		cmdLogger.Fatal("could not create orc file: ", err)
+	}
+	defer orcFile.Close()
+
+	rows := make([]interface{}, 0, len(data))
+	for _, record := range data {
+		rows = append(rows, record.ToParquet())
+	}
+
+	if err := orc.Write(orcFile, schema, rows); err != nil {
+		cmdLogger.Fatal("could not write orc file: ", err)
+	}
+}
+
+// WriteArrow creates the Arrow IPC file and writes the exported data into it, with the columns of the parquet schema
+func WriteArrow(data []transform.SchemaParquet, path string, schema interface{}) {
+	mustMakeParentDir(path)
+	arrowFile, err := os.Create(path)
+	if err != nil {
+		cmdLogger.Fatal("could not create arrow file: ", err)
+	}
+	defer arrowFile.Close()
+
+	rows := make([]interface{}, 0, len(data))
+	for _, record := range data {
+		rows = append(rows, record.ToParquet())
+	}
+
+	if err := arrowipc.Write(arrowFile, schema, rows, arrowipc.DefaultBatchSize); err != nil {
+		cmdLogger.Fatal("could not write arrow file: ", err)
 	}
 }
 
@@ -155,11 +400,13 @@ func MaybeUpload(cloudCredentials, cloudStorageBucket, cloudProvider, path strin
 //	path string                     - The file path where the Parquet file will be created and written.
 //										For example, "some/file/path/export_output.parquet"
 //	schema interface{}              - The schema that defines the structure of the Parquet file.
+//	mapping fieldMapping            - The renames of the top level columns, or nil to keep the schema names.
 //
 //	Errors:
 //
 //	stellar-etl will log a Fatal error and stop in the case it cannot create or write to the parquet file
-func WriteParquet(data []transform.SchemaParquet, path string, schema interface{}) {
+func WriteParquet(data []transform.SchemaParquet, path string, schema interface{}, mapping fieldMapping) {
+	mustMakeParentDir(path)
 	parquetFile, err := local.NewLocalFileWriter(path)
 	if err != nil {
 		cmdLogger.Fatal("could not create parquet file: ", err)
@@ -170,6 +417,11 @@ func WriteParquet(data []transform.SchemaParquet, path string, schema interface{
 	if err != nil {
 		cmdLogger.Fatal("could not create parquet file writer: ", err)
 	}
+	if mapping != nil {
+		if err := mapping.renameParquetColumns(writer.SchemaHandler); err != nil {
+			cmdLogger.Fatal("could not rename parquet columns: ", err)
+		}
+	}
 	defer writer.WriteStop()
 
 	for _, record := range data {
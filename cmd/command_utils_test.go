@@ -0,0 +1,27 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stellar/stellar-etl/v2/internal/utils"
+)
+
+func TestAlignArchiveRange(t *testing.T) {
+	cmdArgs := utils.FlagValues{StartNum: 100, EndNum: 200}
+	assert.NoError(t, alignArchiveRange(&cmdArgs, false))
+	assert.Equal(t, uint32(64), cmdArgs.StartNum)
+	assert.Equal(t, uint32(255), cmdArgs.EndNum)
+
+	cmdArgs = utils.FlagValues{StartNum: 100, EndNum: 200}
+	assert.EqualError(t, alignArchiveRange(&cmdArgs, true), "the range 100-200 is not aligned to checkpoints, which start at multiples of 64 and end one ledger before; the checkpoints containing it cover 64-255")
+	assert.Equal(t, uint32(100), cmdArgs.StartNum)
+	assert.Equal(t, uint32(200), cmdArgs.EndNum)
+
+	// An aligned range is kept, even with no-align
+	cmdArgs = utils.FlagValues{StartNum: 128, EndNum: 191}
+	assert.NoError(t, alignArchiveRange(&cmdArgs, true))
+	assert.Equal(t, uint32(128), cmdArgs.StartNum)
+	assert.Equal(t, uint32(191), cmdArgs.EndNum)
+}
@@ -0,0 +1,215 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/stellar/stellar-etl/v2/internal/utils"
+)
+
+// exportDaemon exports the ledgers that closed since its last run for every dataset. The last exported ledger of every
+// dataset is saved to a checkpoint file in stateDir after every chunk, so a restarted daemon continues where it stopped.
+type exportDaemon struct {
+	datasets         []string
+	stateDir         string
+	startLedger      uint32
+	chunkSize        uint32
+	network          string
+	filenameTemplate string
+	extraArgs        []string
+	latest           func() (uint32, error)
+	export           func(ctx context.Context, args []string) error
+}
+
+var daemonCmd = &cobra.Command{
+	Use:   "daemon [-- export flags]",
+	Short: "Periodically exports the ledgers that closed since the last run",
+	Long: `Every interval, exports the ledgers that closed since the last run for every dataset, in chunks of at most
+chunk-size ledgers. Each chunk is exported by running the export command of the dataset with the same arguments that
+plan prints for it, so the outputs are named with filename-template. The last exported ledger of every dataset is kept
+in state-dir, and a chunk that fails is retried at the next run. Flags after -- are added to every export, for example
+-- --cloud-provider gcp --cloud-storage-bucket my-bucket.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		datasets, err := cmd.Flags().GetStringSlice("datasets")
+		if err != nil {
+			cmdLogger.Fatal("could not get datasets: ", err)
+		}
+
+		interval, err := cmd.Flags().GetDuration("interval")
+		if err != nil {
+			cmdLogger.Fatal("could not get interval: ", err)
+		}
+
+		stateDir, err := cmd.Flags().GetString("state-dir")
+		if err != nil {
+			cmdLogger.Fatal("could not get state-dir: ", err)
+		}
+
+		startLedger, err := cmd.Flags().GetUint32("start-ledger")
+		if err != nil {
+			cmdLogger.Fatal("could not get start-ledger: ", err)
+		}
+
+		chunkSize, err := cmd.Flags().GetUint32("chunk-size")
+		if err != nil {
+			cmdLogger.Fatal("could not get chunk-size: ", err)
+		}
+
+		filenameTemplate, err := cmd.Flags().GetString("filename-template")
+		if err != nil {
+			cmdLogger.Fatal("could not get filename-template: ", err)
+		}
+
+		isTest, err := cmd.Flags().GetBool("testnet")
+		if err != nil {
+			cmdLogger.Fatal("could not get testnet boolean: ", err)
+		}
+
+		isFuture, err := cmd.Flags().GetBool("futurenet")
+		if err != nil {
+			cmdLogger.Fatal("could not get futurenet boolean: ", err)
+		}
+
+		if interval <= 0 {
+			cmdLogger.Fatal("interval must be positive")
+		}
+
+		executable, err := os.Executable()
+		if err != nil {
+			cmdLogger.Fatal("could not find the stellar-etl executable: ", err)
+		}
+
+		env := utils.GetEnvironmentDetails(utils.CommonFlagValues{IsTest: isTest, IsFuture: isFuture})
+		daemon := exportDaemon{
+			datasets:         datasets,
+			stateDir:         stateDir,
+			startLedger:      startLedger,
+			chunkSize:        chunkSize,
+			network:          utils.NetworkName(isTest, isFuture),
+			filenameTemplate: filenameTemplate,
+			extraArgs:        args,
+			latest: func() (uint32, error) {
+				return utils.GetLatestLedgerSequence(env.ArchiveURLs)
+			},
+			export: func(ctx context.Context, args []string) error {
+				export := exec.CommandContext(ctx, executable, args...)
+				export.Stdout = os.Stdout
+				export.Stderr = os.Stderr
+				return export.Run()
+			},
+		}
+		if len(datasets) == 0 {
+			cmdLogger.Fatal("datasets must name at least one dataset")
+		}
+		for _, dataset := range datasets {
+			if err := validatePlanDataset(dataset); err != nil {
+				cmdLogger.Fatal(err)
+			}
+		}
+		if chunkSize == 0 {
+			cmdLogger.Fatal("chunk-size must be positive")
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			if err := daemon.runOnce(ctx); err != nil {
+				cmdLogger.Errorf("Daemon run failed: %v", err)
+			}
+
+			select {
+			case <-ctx.Done():
+				cmdLogger.Info("Stopping the daemon")
+				return
+			case <-ticker.C:
+			}
+		}
+	},
+}
+
+// runOnce exports the ledgers from the last exported ledger of every dataset up to the latest ledger. A dataset
+// without a checkpoint starts at startLedger, or at the latest ledger if it is 0. The datasets are exported
+// independently, so a failing dataset doesn't hold the others back; its error is returned after the others ran.
+func (d exportDaemon) runOnce(ctx context.Context) error {
+	latest, err := d.latest()
+	if err != nil {
+		return fmt.Errorf("could not get the latest ledger: %v", err)
+	}
+
+	var runErr error
+	for _, dataset := range d.datasets {
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err := d.exportDataset(ctx, dataset, latest); err != nil {
+			cmdLogger.Errorf("Could not export %s: %v", dataset, err)
+			runErr = fmt.Errorf("could not export %s: %v", dataset, err)
+		}
+	}
+
+	return runErr
+}
+
+// exportDataset exports the ledgers of a dataset that weren't exported yet, up to latest
+func (d exportDaemon) exportDataset(ctx context.Context, dataset string, latest uint32) error {
+	checkpointPath := filepath.Join(d.stateDir, dataset+".checkpoint")
+	lastLedger, found, err := readCheckpoint(checkpointPath)
+	if err != nil {
+		return err
+	}
+
+	start := lastLedger + 1
+	if !found {
+		start = d.startLedger
+		if start == 0 {
+			start = latest
+		}
+	}
+	if start > latest {
+		cmdLogger.Infof("No new ledgers to export for %s", dataset)
+		return nil
+	}
+
+	plan, err := buildExportPlan([]string{dataset}, start, latest, d.chunkSize, d.network, d.filenameTemplate, d.extraArgs)
+	if err != nil {
+		return err
+	}
+
+	for _, invocation := range plan.Invocations {
+		cmdLogger.Infof("Exporting %s for ledgers %d-%d", dataset, invocation.StartLedger, invocation.EndLedger)
+		if err := d.export(ctx, invocation.Args); err != nil {
+			return fmt.Errorf("export of ledgers %d-%d failed: %v", invocation.StartLedger, invocation.EndLedger, err)
+		}
+		if err := writeCheckpoint(checkpointPath, invocation.EndLedger); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(daemonCmd)
+
+	daemonCmd.Flags().StringSlice("datasets", nil, "Comma separated datasets to export, named after their export commands such as ledgers or contract_events.")
+	daemonCmd.Flags().Duration("interval", 5*time.Minute, "Time between the runs of the daemon, such as 5m or 1h.")
+	daemonCmd.Flags().String("state-dir", "daemon_state", "Folder that the last exported ledger of every dataset is kept in.")
+	daemonCmd.Flags().Uint32P("start-ledger", "s", 0, "Ledger that datasets without a saved state start at. If 0, they start at the latest ledger when the daemon first runs.")
+	daemonCmd.Flags().Uint32("chunk-size", 17280, "Largest number of ledgers exported by a single export. Runs that are behind export several chunks.")
+	daemonCmd.Flags().String("filename-template", "{dataset}_{network}_{start}_{end}_{token}.{ext}", "Template for the names of the output files of every export, passed to it as filename-template.")
+	daemonCmd.Flags().Bool("testnet", false, "If set, will connect to Testnet instead of Mainnet.")
+	daemonCmd.Flags().Bool("futurenet", false, "If set, will connect to Futurenet instead of Mainnet.")
+
+	daemonCmd.MarkFlagRequired("datasets")
+}
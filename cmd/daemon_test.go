@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExportDaemon(t *testing.T) {
+	stateDir := t.TempDir()
+	latest := uint32(100)
+	exports := [][]string{}
+	failing := map[string]bool{}
+	daemon := exportDaemon{
+		datasets:         []string{"ledgers", "trades"},
+		stateDir:         stateDir,
+		chunkSize:        64,
+		network:          "pubnet",
+		filenameTemplate: "{dataset}_{start}_{end}.{ext}",
+		extraArgs:        []string{"--write-parquet"},
+		latest:           func() (uint32, error) { return latest, nil },
+		export: func(ctx context.Context, args []string) error {
+			if failing[args[0]] {
+				return errors.New("exit status 1")
+			}
+			exports = append(exports, args[:5])
+			return nil
+		},
+	}
+
+	// The first run starts at the latest ledger
+	assert.NoError(t, daemon.runOnce(context.Background()))
+	assert.Equal(t, [][]string{
+		{"export_ledgers", "--start-ledger", "100", "--end-ledger", "100"},
+		{"export_trades", "--start-ledger", "100", "--end-ledger", "100"},
+	}, exports)
+
+	// Later runs export the new ledgers in chunks, and a failing dataset doesn't stop the others
+	exports = [][]string{}
+	latest = 200
+	failing["export_trades"] = true
+	assert.EqualError(t, daemon.runOnce(context.Background()), "could not export trades: export of ledgers 101-164 failed: exit status 1")
+	assert.Equal(t, [][]string{
+		{"export_ledgers", "--start-ledger", "101", "--end-ledger", "164"},
+		{"export_ledgers", "--start-ledger", "165", "--end-ledger", "200"},
+	}, exports)
+
+	// The failed chunk is retried, and datasets without new ledgers are skipped
+	exports = [][]string{}
+	failing["export_trades"] = false
+	assert.NoError(t, daemon.runOnce(context.Background()))
+	assert.Equal(t, [][]string{
+		{"export_trades", "--start-ledger", "101", "--end-ledger", "164"},
+		{"export_trades", "--start-ledger", "165", "--end-ledger", "200"},
+	}, exports)
+
+	lastLedger, found, err := readCheckpoint(filepath.Join(stateDir, "trades.checkpoint"))
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, uint32(200), lastLedger)
+}
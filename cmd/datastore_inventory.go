@@ -0,0 +1,178 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+
+	"github.com/spf13/cobra"
+	"github.com/stellar/go-stellar-sdk/support/datastore"
+
+	"github.com/stellar/stellar-etl/v2/internal/utils"
+)
+
+// datastoreFile is a txmeta file of the datastore that an export of a range reads
+type datastoreFile struct {
+	File        string `json:"file"`
+	Partition   string `json:"partition"`
+	StartLedger uint32 `json:"start_ledger"`
+	EndLedger   uint32 `json:"end_ledger"`
+	SizeBytes   int64  `json:"size_bytes"`
+	Exists      bool   `json:"exists"`
+}
+
+// inventorySummary describes the layout of the datastore and totals the files that a range reads
+type inventorySummary struct {
+	LedgersPerFile    uint32 `json:"ledgers_per_file"`
+	FilesPerPartition uint32 `json:"files_per_partition"`
+	FileExtension     string `json:"file_extension"`
+	Files             int    `json:"files"`
+	MissingFiles      int    `json:"missing_files"`
+	Partitions        int    `json:"partitions"`
+	TotalBytes        int64  `json:"total_bytes"`
+}
+
+var datastoreInventoryCmd = &cobra.Command{
+	Use:   "datastore_inventory",
+	Short: "Lists the datastore files that an export of a range reads",
+	Long: `Lists the txmeta files of the datastore that an export of the range reads, without reading them. Every file is
+written as a JSON line with its name, partition, ledger range and size, and whether it exists. The layout of the
+datastore and the number of files, partitions and bytes are logged, which helps to estimate the egress of an export
+and to debug datastores whose layout doesn't match the expected schema.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		startNum, err := cmd.Flags().GetUint32("start-ledger")
+		if err != nil {
+			cmdLogger.Fatal("could not get start sequence number: ", err)
+		}
+
+		endNum, err := cmd.Flags().GetUint32("end-ledger")
+		if err != nil {
+			cmdLogger.Fatal("could not get end sequence number: ", err)
+		}
+
+		isTest, err := cmd.Flags().GetBool("testnet")
+		if err != nil {
+			cmdLogger.Fatal("could not get testnet boolean: ", err)
+		}
+
+		isFuture, err := cmd.Flags().GetBool("futurenet")
+		if err != nil {
+			cmdLogger.Fatal("could not get futurenet boolean: ", err)
+		}
+
+		datastorePath, err := cmd.Flags().GetString("datastore-path")
+		if err != nil {
+			cmdLogger.Fatal("could not get datastore-path: ", err)
+		}
+
+		path, err := cmd.Flags().GetString("output")
+		if err != nil {
+			cmdLogger.Fatal("could not get output path: ", err)
+		}
+
+		if endNum < startNum {
+			cmdLogger.Fatalf("end sequence number is less than start (%d < %d)", endNum, startNum)
+		}
+
+		ctx := context.Background()
+		env := utils.GetEnvironmentDetails(utils.CommonFlagValues{IsTest: isTest, IsFuture: isFuture, DatastorePath: datastorePath})
+		dataStore, dataStoreConfig, err := utils.CreateDatastore(ctx, env)
+		if err != nil {
+			cmdLogger.Fatal("could not connect to the datastore: ", err)
+		}
+		defer dataStore.Close()
+
+		schema, err := datastore.LoadSchema(ctx, dataStore, dataStoreConfig)
+		if err != nil {
+			cmdLogger.Fatal("could not load the datastore schema: ", err)
+		}
+
+		var out io.Writer = cmd.OutOrStdout()
+		if path != "" {
+			outFile := MustOutFile(path)
+			defer outFile.Close()
+			out = outFile
+		}
+
+		encoder := json.NewEncoder(out)
+		summary, err := inventoryDatastore(ctx, dataStore, schema, startNum, endNum, func(file datastoreFile) {
+			if err := encoder.Encode(file); err != nil {
+				cmdLogger.Fatal("could not write datastore file: ", err)
+			}
+		})
+		if err != nil {
+			cmdLogger.Fatal(err)
+		}
+
+		marshalled, err := json.Marshal(summary)
+		if err != nil {
+			cmdLogger.Fatal("could not json encode datastore inventory: ", err)
+		}
+		cmdLogger.Info(string(marshalled))
+	},
+}
+
+// inventoryDatastore calls report for every file of the datastore that holds ledgers of the range, in ledger order,
+// with the size of the files that exist
+func inventoryDatastore(ctx context.Context, dataStore datastore.DataStore, schema datastore.DataStoreSchema, start, end uint32, report func(datastoreFile)) (inventorySummary, error) {
+	summary := inventorySummary{
+		LedgersPerFile:    schema.LedgersPerFile,
+		FilesPerPartition: schema.FilesPerPartition,
+		FileExtension:     schema.FileExtension,
+	}
+	if schema.LedgersPerFile == 0 {
+		return summary, fmt.Errorf("the datastore schema has no ledgers per file")
+	}
+
+	partitions := map[string]bool{}
+	for seq := schema.GetSequenceNumberStartBoundary(start); seq <= end; {
+		key := schema.GetObjectKeyFromSequenceNumber(seq)
+		file := datastoreFile{
+			File:        key,
+			StartLedger: seq,
+			EndLedger:   schema.GetSequenceNumberEndBoundary(seq),
+		}
+		if dir := path.Dir(key); dir != "." {
+			file.Partition = dir
+		}
+
+		size, err := dataStore.Size(ctx, key)
+		switch {
+		case err == nil:
+			file.SizeBytes = size
+			file.Exists = true
+		case errors.Is(err, os.ErrNotExist):
+			summary.MissingFiles += 1
+		default:
+			return summary, fmt.Errorf("could not get the size of %s: %v", key, err)
+		}
+
+		summary.Files += 1
+		summary.TotalBytes += file.SizeBytes
+		partitions[file.Partition] = true
+		report(file)
+
+		if file.EndLedger >= end || file.EndLedger == ^uint32(0) {
+			break
+		}
+		seq = file.EndLedger + 1
+	}
+
+	summary.Partitions = len(partitions)
+	return summary, nil
+}
+
+func init() {
+	rootCmd.AddCommand(datastoreInventoryCmd)
+	datastoreInventoryCmd.Flags().Uint32P("start-ledger", "s", 2, "The ledger sequence number for the beginning of the range.")
+	datastoreInventoryCmd.Flags().Uint32P("end-ledger", "e", 0, "The ledger sequence number for the end of the range.")
+	datastoreInventoryCmd.Flags().Bool("testnet", false, "If set, the datastore of testnet is listed instead of mainnet.")
+	datastoreInventoryCmd.Flags().Bool("futurenet", false, "If set, the datastore of futurenet is listed instead of mainnet.")
+	datastoreInventoryCmd.Flags().String("datastore-path", "sdf-ledger-close-meta/v1/ledgers", "Datastore bucket path to read txmeta files from.")
+	datastoreInventoryCmd.Flags().StringP("output", "o", "", "If set, the files are written to this file instead of stdout.")
+	datastoreInventoryCmd.MarkFlagRequired("end-ledger")
+}
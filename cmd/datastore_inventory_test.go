@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stellar/go-stellar-sdk/support/datastore"
+	"github.com/stretchr/testify/assert"
+)
+
+// sizedDatastore is a datastore that only knows the sizes of its files
+type sizedDatastore struct {
+	datastore.DataStore
+	sizes map[string]int64
+}
+
+func (s sizedDatastore) Size(ctx context.Context, path string) (int64, error) {
+	size, ok := s.sizes[path]
+	if !ok {
+		return 0, os.ErrNotExist
+	}
+	return size, nil
+}
+
+func TestInventoryDatastore(t *testing.T) {
+	schema := datastore.DataStoreSchema{LedgersPerFile: 2, FilesPerPartition: 2, FileExtension: "zst"}
+	dataStore := sizedDatastore{sizes: map[string]int64{
+		"FFFFFFFB--4-7/FFFFFFFB--4-5.xdr.zst": 1000,
+		"FFFFFFFB--4-7/FFFFFFF9--6-7.xdr.zst": 1200,
+	}}
+
+	var files []datastoreFile
+	summary, err := inventoryDatastore(context.Background(), dataStore, schema, 5, 8, func(file datastoreFile) {
+		files = append(files, file)
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []datastoreFile{
+		{File: "FFFFFFFB--4-7/FFFFFFFB--4-5.xdr.zst", Partition: "FFFFFFFB--4-7", StartLedger: 4, EndLedger: 5, SizeBytes: 1000, Exists: true},
+		{File: "FFFFFFFB--4-7/FFFFFFF9--6-7.xdr.zst", Partition: "FFFFFFFB--4-7", StartLedger: 6, EndLedger: 7, SizeBytes: 1200, Exists: true},
+		{File: "FFFFFFF7--8-11/FFFFFFF7--8-9.xdr.zst", Partition: "FFFFFFF7--8-11", StartLedger: 8, EndLedger: 9},
+	}, files)
+	assert.Equal(t, inventorySummary{
+		LedgersPerFile:    2,
+		FilesPerPartition: 2,
+		FileExtension:     "zst",
+		Files:             3,
+		MissingFiles:      1,
+		Partitions:        2,
+		TotalBytes:        2200,
+	}, summary)
+}
@@ -0,0 +1,275 @@
+package cmd
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/stellar/stellar-etl/v2/internal/transform"
+	"github.com/stellar/stellar-etl/v2/internal/utils"
+)
+
+// ddlSchemas are the parquet schemas of the datasets, which are the columns of the tables that the exports load into
+var ddlSchemas = map[string]interface{}{
+	"account_operations":          new(transform.AccountOperationOutputParquet),
+	"accounts":                    new(transform.AccountOutputParquet),
+	"asset_supply":                new(transform.AssetSupplyOutputParquet),
+	"assets":                      new(transform.AssetOutputParquet),
+	"config_setting_diffs":        new(transform.ConfigSettingDiffOutputParquet),
+	"config_settings":             new(transform.ConfigSettingOutputParquet),
+	"config_upgrades":             new(transform.ConfigUpgradeOutputParquet),
+	"contract_code":               new(transform.ContractCodeOutputParquet),
+	"contract_data":               new(transform.ContractDataOutputParquet),
+	"contract_events":             new(transform.ContractEventOutputParquet),
+	"effects":                     new(transform.EffectOutputParquet),
+	"footprint_reads":             new(transform.FootprintReadOutputParquet),
+	"footprints":                  new(transform.FootprintOutputParquet),
+	"inflation_payouts":           new(transform.InflationPayoutOutputParquet),
+	"cb_claims":                   new(transform.ClaimableBalanceClaimOutputParquet),
+	"ledger_fee_stats":            new(transform.LedgerFeeStatsOutputParquet),
+	"ledger_upgrades":             new(transform.LedgerUpgradeOutputParquet),
+	"ledgers":                     new(transform.LedgerOutputParquet),
+	"liquidity_pools":             new(transform.PoolOutputParquet),
+	"offers":                      new(transform.OfferOutputParquet),
+	"operations":                  new(transform.OperationOutputParquet),
+	"participants":                new(transform.ParticipantOutputParquet),
+	"path_payment_hops":           new(transform.PathPaymentHopOutputParquet),
+	"restore_candidates":          new(transform.RestoreCandidateOutputParquet),
+	"signers":                     new(transform.AccountSignerOutputParquet),
+	"trades":                      new(transform.TradeOutputParquet),
+	"transactions":                new(transform.TransactionOutputParquet),
+	"trustlines":                  new(transform.TrustlineOutputParquet),
+	"ttl":                         new(transform.TtlOutputParquet),
+	"ttl_expiry":                  new(transform.TtlExpiryOutputParquet),
+	"liquidity_pool_participants": new(transform.PoolParticipantOutputParquet),
+	"asset_metadata":              new(transform.AssetMetadataOutputParquet),
+	"contract_specs":              new(transform.ContractSpecOutputParquet),
+}
+
+// ddlFlattenedSchemas are the parquet schemas of the datasets exported with flatten-details
+var ddlFlattenedSchemas = map[string]interface{}{
+	"effects":    new(transform.FlattenedEffectOutputParquet),
+	"operations": new(transform.FlattenedOperationOutputParquet),
+}
+
+var ddlCmd = &cobra.Command{
+	Use:   "ddl",
+	Short: "Prints the CREATE TABLE statement of a dataset",
+	Long: `Prints the CREATE TABLE statement of a dataset for a warehouse, so that the warehouse tables match the output
+schemas of the exporter. The columns are taken from the parquet schema of the dataset. Supported dialects are
+bigquery, postgres and clickhouse.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		dialect, err := cmd.Flags().GetString("dialect")
+		if err != nil {
+			cmdLogger.Fatal("could not get dialect: ", err)
+		}
+
+		dataset, err := cmd.Flags().GetString("dataset")
+		if err != nil {
+			cmdLogger.Fatal("could not get dataset: ", err)
+		}
+
+		table, err := cmd.Flags().GetString("table")
+		if err != nil {
+			cmdLogger.Fatal("could not get table: ", err)
+		}
+		if table == "" {
+			table = dataset
+		}
+
+		flattenDetails, err := cmd.Flags().GetBool("flatten-details")
+		if err != nil {
+			cmdLogger.Fatal("could not get flatten-details flag: ", err)
+		}
+
+		fieldMappingPath, err := cmd.Flags().GetString("field-mapping")
+		if err != nil {
+			cmdLogger.Fatal("could not get field-mapping: ", err)
+		}
+
+		fieldMappingDestination, err := cmd.Flags().GetString("field-mapping-destination")
+		if err != nil {
+			cmdLogger.Fatal("could not get field-mapping-destination: ", err)
+		}
+
+		parquetSchema, err := datasetParquetSchema(dataset, flattenDetails)
+		if err != nil {
+			cmdLogger.Fatal(err)
+		}
+
+		var mapping fieldMapping
+		if fieldMappingPath != "" {
+			mapping, err = loadFieldMapping(fieldMappingPath, fieldMappingDestination, dataset)
+			if err != nil {
+				cmdLogger.Fatal(err)
+			}
+		}
+
+		statement, err := createTableStatement(dialect, table, parquetSchema, mapping)
+		if err != nil {
+			cmdLogger.Fatal("could not build the CREATE TABLE statement: ", err)
+		}
+
+		fmt.Fprintln(cmd.OutOrStdout(), statement)
+	},
+}
+
+// datasetParquetSchema returns the parquet schema of a dataset
+func datasetParquetSchema(dataset string, flattenDetails bool) (interface{}, error) {
+	if flattenDetails {
+		if parquetSchema, ok := ddlFlattenedSchemas[dataset]; ok {
+			return parquetSchema, nil
+		}
+	}
+
+	parquetSchema, ok := ddlSchemas[dataset]
+	if !ok {
+		datasets := make([]string, 0, len(ddlSchemas))
+		for name := range ddlSchemas {
+			datasets = append(datasets, name)
+		}
+		sort.Strings(datasets)
+		return nil, fmt.Errorf("unsupported dataset %s; expected one of %s", dataset, strings.Join(datasets, ", "))
+	}
+
+	return parquetSchema, nil
+}
+
+// ddlColumnType converts a parquet physical and converted type into a column type of a dialect
+func ddlColumnType(dialect, parquetType, convertedType string) (string, error) {
+	var bigquery, postgres, clickhouse string
+	switch parquetType {
+	case "BYTE_ARRAY":
+		if convertedType == "UTF8" {
+			bigquery, postgres, clickhouse = "STRING", "TEXT", "String"
+		} else {
+			bigquery, postgres, clickhouse = "BYTES", "BYTEA", "String"
+		}
+	case "STRING", "MAP":
+		// Maps are written as JSON
+		bigquery, postgres, clickhouse = "JSON", "JSONB", "String"
+	case "BOOLEAN":
+		bigquery, postgres, clickhouse = "BOOL", "BOOLEAN", "Bool"
+	case "INT32":
+		bigquery, postgres, clickhouse = "INT64", "INTEGER", "Int32"
+	case "INT64":
+		switch convertedType {
+		case "TIMESTAMP_MILLIS":
+			bigquery, postgres, clickhouse = "TIMESTAMP", "TIMESTAMPTZ", "DateTime64(3, 'UTC')"
+		case "UINT_64":
+			bigquery, postgres, clickhouse = "INT64", "BIGINT", "UInt64"
+		default:
+			bigquery, postgres, clickhouse = "INT64", "BIGINT", "Int64"
+		}
+	case "FLOAT":
+		bigquery, postgres, clickhouse = "FLOAT64", "REAL", "Float32"
+	case "DOUBLE":
+		bigquery, postgres, clickhouse = "FLOAT64", "DOUBLE PRECISION", "Float64"
+	default:
+		return "", fmt.Errorf("unsupported parquet type %s", parquetType)
+	}
+
+	switch dialect {
+	case "bigquery":
+		return bigquery, nil
+	case "postgres":
+		return postgres, nil
+	case "clickhouse":
+		return clickhouse, nil
+	}
+
+	return "", fmt.Errorf("unsupported dialect %s; expected bigquery, postgres or clickhouse", dialect)
+}
+
+// ddlListType wraps the column type of the elements of a list column
+func ddlListType(dialect, elementType string) string {
+	switch dialect {
+	case "bigquery":
+		return "ARRAY<" + elementType + ">"
+	case "postgres":
+		return elementType + "[]"
+	default:
+		return "Array(" + elementType + ")"
+	}
+}
+
+// quoteIdentifier quotes a column or table name, where every part of a qualified table name is quoted on its own
+func quoteIdentifier(dialect, name string) string {
+	quote := "`"
+	if dialect == "postgres" {
+		quote = `"`
+	}
+
+	parts := strings.Split(name, ".")
+	for i, part := range parts {
+		parts[i] = quote + strings.ReplaceAll(part, quote, quote+quote) + quote
+	}
+	return strings.Join(parts, ".")
+}
+
+// createTableStatement builds the CREATE TABLE statement of a table from the parquet tags of its parquet schema struct,
+// with the columns renamed by the field mapping
+func createTableStatement(dialect, table string, parquetSchema interface{}, mapping fieldMapping) (string, error) {
+	schemaType := reflect.TypeOf(parquetSchema)
+	for schemaType.Kind() == reflect.Pointer {
+		schemaType = schemaType.Elem()
+	}
+	if schemaType.Kind() != reflect.Struct {
+		return "", fmt.Errorf("parquet schema must be a struct, got %s", schemaType)
+	}
+
+	columns := []string{}
+	names := map[string]bool{}
+	for i := 0; i < schemaType.NumField(); i++ {
+		field := schemaType.Field(i)
+		tag := utils.ParseParquetTag(field.Tag.Get("parquet"))
+		if tag["name"] == "" {
+			continue
+		}
+
+		elementType, elementConvertedType := tag["type"], tag["convertedtype"]
+		isList := field.Type.Kind() == reflect.Slice && field.Type.Elem().Kind() != reflect.Uint8
+		if tag["type"] == "MAP" && tag["convertedtype"] == "LIST" {
+			elementType, elementConvertedType = tag["valuetype"], tag["valueconvertedtype"]
+			isList = true
+		}
+
+		columnType, err := ddlColumnType(dialect, elementType, elementConvertedType)
+		if err != nil {
+			return "", fmt.Errorf("column %s: %v", tag["name"], err)
+		}
+		if isList {
+			columnType = ddlListType(dialect, columnType)
+		}
+
+		name := mapping.name(tag["name"])
+		if names[name] {
+			return "", fmt.Errorf("more than one column is named %s after renaming", name)
+		}
+		names[name] = true
+
+		columns = append(columns, fmt.Sprintf("  %s %s", quoteIdentifier(dialect, name), columnType))
+	}
+
+	statement := fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (\n%s\n)", quoteIdentifier(dialect, table), strings.Join(columns, ",\n"))
+	if dialect == "clickhouse" {
+		// MergeTree tables need a sorting key, and an empty one keeps the rows in the order they are inserted
+		statement += "\nENGINE = MergeTree\nORDER BY tuple()"
+	}
+
+	return statement + ";", nil
+}
+
+func init() {
+	rootCmd.AddCommand(ddlCmd)
+	ddlCmd.Flags().String("dialect", "bigquery", "SQL dialect of the statement: bigquery, postgres or clickhouse.")
+	ddlCmd.Flags().String("dataset", "", "Dataset whose table is created, such as operations or contract_data.")
+	ddlCmd.Flags().String("table", "", "Name of the table, which can be qualified with a dataset or schema. Defaults to the name of the dataset.")
+	ddlCmd.Flags().Bool("flatten-details", false, "If set, the table has the columns of the operations or effects exported with flatten-details.")
+	ddlCmd.Flags().String("field-mapping", "", "If set, the columns are renamed with the renames of field-mapping-destination in this JSON mapping file.")
+	ddlCmd.Flags().String("field-mapping-destination", "", "Destination in the field-mapping file whose renames are used, such as snowflake.")
+	ddlCmd.MarkFlagRequired("dataset")
+}
@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type ddlTestSchema struct {
+	AccountID  string   `parquet:"name=account_id, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	Sequence   int64    `parquet:"name=ledger_sequence, type=INT64, convertedtype=UINT_64"`
+	ClosedAt   int64    `parquet:"name=closed_at, type=INT64, convertedtype=TIMESTAMP_MILLIS"`
+	Successful bool     `parquet:"name=successful, type=BOOLEAN"`
+	Signers    []string `parquet:"name=signers, type=MAP, convertedtype=LIST, valuetype=BYTE_ARRAY, valueconvertedtype=UTF8"`
+	Windows    []int64  `parquet:"name=windows, type=INT64, repetitiontype=REPEATED"`
+	Details    string   `parquet:"name=details, type=MAP, convertedtype=MAP, keytype=BYTE_ARRAY, keyconvertedtype=UTF8, valuetype=STRING"`
+}
+
+func TestCreateTableStatement(t *testing.T) {
+	type testCase struct {
+		dialect string
+		table   string
+		want    string
+	}
+
+	tests := []testCase{
+		{
+			dialect: "bigquery",
+			table:   "crypto_stellar.history_operations",
+			want: "CREATE TABLE IF NOT EXISTS `crypto_stellar`.`history_operations` (\n" +
+				"  `account_id` STRING,\n" +
+				"  `ledger_sequence` INT64,\n" +
+				"  `closed_at` TIMESTAMP,\n" +
+				"  `successful` BOOL,\n" +
+				"  `signers` ARRAY<STRING>,\n" +
+				"  `windows` ARRAY<INT64>,\n" +
+				"  `operation_details` JSON\n" +
+				");",
+		},
+		{
+			dialect: "postgres",
+			table:   "history_operations",
+			want: "CREATE TABLE IF NOT EXISTS \"history_operations\" (\n" +
+				"  \"account_id\" TEXT,\n" +
+				"  \"ledger_sequence\" BIGINT,\n" +
+				"  \"closed_at\" TIMESTAMPTZ,\n" +
+				"  \"successful\" BOOLEAN,\n" +
+				"  \"signers\" TEXT[],\n" +
+				"  \"windows\" BIGINT[],\n" +
+				"  \"operation_details\" JSONB\n" +
+				");",
+		},
+		{
+			dialect: "clickhouse",
+			table:   "history_operations",
+			want: "CREATE TABLE IF NOT EXISTS `history_operations` (\n" +
+				"  `account_id` String,\n" +
+				"  `ledger_sequence` UInt64,\n" +
+				"  `closed_at` DateTime64(3, 'UTC'),\n" +
+				"  `successful` Bool,\n" +
+				"  `signers` Array(String),\n" +
+				"  `windows` Array(Int64),\n" +
+				"  `operation_details` String\n" +
+				")\n" +
+				"ENGINE = MergeTree\n" +
+				"ORDER BY tuple();",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.dialect, func(t *testing.T) {
+			statement, err := createTableStatement(test.dialect, test.table, new(ddlTestSchema), fieldMapping{"details": "operation_details"})
+			assert.NoError(t, err)
+			assert.Equal(t, test.want, statement)
+		})
+	}
+
+	_, err := createTableStatement("snowflake", "operations", new(ddlTestSchema), nil)
+	assert.Error(t, err)
+
+	_, err = createTableStatement("bigquery", "operations", new(ddlTestSchema), fieldMapping{"details": "account_id"})
+	assert.Error(t, err)
+}
+
+func TestCreateTableStatementForEveryDataset(t *testing.T) {
+	for _, dialect := range []string{"bigquery", "postgres", "clickhouse"} {
+		for dataset := range ddlSchemas {
+			for _, flattenDetails := range []bool{false, true} {
+				parquetSchema, err := datasetParquetSchema(dataset, flattenDetails)
+				assert.NoError(t, err)
+				_, err = createTableStatement(dialect, dataset, parquetSchema, nil)
+				assert.NoError(t, err, "%s %s", dialect, dataset)
+			}
+		}
+	}
+
+	_, err := datasetParquetSchema("token_transfers", false)
+	assert.Error(t, err)
+}
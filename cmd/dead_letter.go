@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stellar/stellar-etl/v2/internal/utils"
+)
+
+// ledgerPanic is a panic in the processing of a ledger, with the stack where it happened
+type ledgerPanic struct {
+	value interface{}
+	stack []byte
+}
+
+func (p *ledgerPanic) Error() string {
+	return fmt.Sprintf("panic: %v", p.value)
+}
+
+// recoverLedger calls processLedger on a ledger, and returns a *ledgerPanic error if it panics, so a transform bug in a
+// single ledger doesn't stop a long running export
+func recoverLedger(processLedger func(xdr.LedgerCloseMeta) error, lcm xdr.LedgerCloseMeta) (err error) {
+	defer func() {
+		if value := recover(); value != nil {
+			err = &ledgerPanic{value: value, stack: debug.Stack()}
+		}
+	}()
+	return processLedger(lcm)
+}
+
+// ledgerPanicked counts a ledger whose processing panicked as a failure, and writes it to the dead letter folder. The
+// records exported from the ledger before the panic were already discarded by exportLedger.
+func (e *exportEngine) ledgerPanicked(lcm xdr.LedgerCloseMeta, panicked *ledgerPanic) {
+	ledgerSeq := utils.GetLedgerSequence(lcm)
+	cmdLogger.Debugf("Stack of the panic in ledger %d:\n%s", ledgerSeq, panicked.stack)
+	e.failAs(failurePanic, fmt.Errorf("ledger %d panicked, skipping its records: %v", ledgerSeq, panicked.value))
+
+	if e.cmdArgs.DeadLetterDir == "" {
+		return
+	}
+	ledgerPath, err := writeDeadLetter(e.cmdArgs.DeadLetterDir, lcm, panicked)
+	if err != nil {
+		cmdLogger.Error(err)
+		return
+	}
+	cmdLogger.Infof("Wrote ledger %d to %s", ledgerSeq, ledgerPath)
+}
+
+// writeDeadLetter writes a ledger to the dead letter folder as <sequence>.xdr, which replay can read, and its panic and
+// stack to <sequence>.panic.txt. It returns the path of the ledger file.
+func writeDeadLetter(dir string, lcm xdr.LedgerCloseMeta, panicked *ledgerPanic) (string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("could not create dead letter folder %s: %v", dir, err)
+	}
+
+	ledgerSeq := utils.GetLedgerSequence(lcm)
+	raw, err := lcm.MarshalBinary()
+	if err != nil {
+		return "", fmt.Errorf("could not encode ledger %d for the dead letter folder: %v", ledgerSeq, err)
+	}
+
+	ledgerPath := filepath.Join(dir, fmt.Sprintf("%d.xdr", ledgerSeq))
+	if err := os.WriteFile(ledgerPath, raw, 0644); err != nil {
+		return "", fmt.Errorf("could not write ledger %d to %s: %v", ledgerSeq, ledgerPath, err)
+	}
+
+	panicPath := filepath.Join(dir, fmt.Sprintf("%d.panic.txt", ledgerSeq))
+	contents := fmt.Sprintf("%s\n\n%s", panicked.Error(), panicked.stack)
+	if err := os.WriteFile(panicPath, []byte(contents), 0644); err != nil {
+		return "", fmt.Errorf("could not write the panic of ledger %d to %s: %v", ledgerSeq, panicPath, err)
+	}
+
+	return ledgerPath, nil
+}
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stellar/stellar-etl/v2/internal/transform"
+	"github.com/stellar/stellar-etl/v2/internal/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecoverLedger(t *testing.T) {
+	lcm := replayLedger(30)
+	err := recoverLedger(func(xdr.LedgerCloseMeta) error {
+		var details map[string]interface{}
+		details["amount"] = 1
+		return nil
+	}, lcm)
+	var panicked *ledgerPanic
+	assert.True(t, errors.As(err, &panicked))
+	assert.Equal(t, "panic: assignment to entry in nil map", err.Error())
+
+	processErr := errors.New("could not read ledger")
+	assert.Equal(t, processErr, recoverLedger(func(xdr.LedgerCloseMeta) error { return processErr }, lcm))
+
+	dir := filepath.Join(t.TempDir(), "dead-letter")
+	ledgerPath, err := writeDeadLetter(dir, lcm, panicked)
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "30.xdr"), ledgerPath)
+
+	// The folder can be replayed
+	lcms, err := utils.ReadLedgerCloseMetas(dir)
+	assert.NoError(t, err)
+	assert.Len(t, lcms, 1)
+	assert.Equal(t, uint32(30), lcms[0].LedgerSequence())
+
+	contents, err := os.ReadFile(filepath.Join(dir, "30.panic.txt"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(contents), "panic: assignment to entry in nil map")
+	assert.Contains(t, string(contents), "TestRecoverLedger")
+}
+
+func TestExportLedgerDiscardsPanickedRecords(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "upgrades.txt")
+	engine := &exportEngine{
+		cmdArgs: utils.FlagValues{Limit: -1},
+		outFile: MustOutFile(path),
+	}
+
+	err := engine.exportLedger(func(lcm xdr.LedgerCloseMeta) error {
+		engine.export(transform.LedgerUpgradeOutput{LedgerSequence: lcm.LedgerSequence()})
+		var details map[string]interface{}
+		details["amount"] = 1
+		return nil
+	}, replayLedger(30))
+	assert.NoError(t, err)
+	assert.Equal(t, 0, engine.numExported)
+	assert.Equal(t, 1, engine.numFailures)
+
+	err = engine.exportLedger(func(lcm xdr.LedgerCloseMeta) error {
+		engine.export(transform.LedgerUpgradeOutput{LedgerSequence: lcm.LedgerSequence()})
+		return nil
+	}, replayLedger(31))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, engine.numExported)
+	engine.outFile.Close()
+
+	contents, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(string(contents), "\n"))
+	assert.Contains(t, string(contents), `"ledger_sequence":31`)
+}
@@ -0,0 +1,427 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/storage"
+	"github.com/google/uuid"
+	"github.com/stellar/stellar-etl/v2/internal/utils"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/iterator"
+)
+
+// maxDeltaCommitAttempts is the number of times a commit is retried when another writer commits the same version first
+const maxDeltaCommitAttempts = 10
+
+// errDeltaCommitExists is returned by a deltaTableStore when the commit file for a version has already been written
+var errDeltaCommitExists = errors.New("delta commit already exists")
+
+// deltaTableStore reads and writes the files of a Delta Lake table. Commit files must be written atomically and only if
+// they don't exist yet, which is what gives concurrent exports to the same table their ACID guarantees.
+type deltaTableStore interface {
+	// latestVersion returns the version of the newest commit in the table, or -1 if the table has no commits
+	latestVersion() (int64, error)
+	// putCommit writes the commit file for a version, or returns errDeltaCommitExists if it was already written
+	putCommit(version int64, data []byte) error
+	// putDataFile copies a local parquet file into the table with the provided name
+	putDataFile(localPath, name string) error
+}
+
+// deltaCommitName returns the path of the commit file for a version, relative to the table root
+func deltaCommitName(version int64) string {
+	return path.Join("_delta_log", fmt.Sprintf("%020d.json", version))
+}
+
+// parseDeltaCommitVersion returns the version of a commit file, or false if the name isn't a commit file
+func parseDeltaCommitVersion(name string) (int64, bool) {
+	base := path.Base(name)
+	if !strings.HasSuffix(base, ".json") {
+		return 0, false
+	}
+
+	version, err := strconv.ParseInt(strings.TrimSuffix(base, ".json"), 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return version, true
+}
+
+// localDeltaTableStore stores a Delta Lake table in a local directory
+type localDeltaTableStore struct {
+	root string
+}
+
+func (l localDeltaTableStore) latestVersion() (int64, error) {
+	entries, err := os.ReadDir(filepath.Join(l.root, "_delta_log"))
+	if os.IsNotExist(err) {
+		return -1, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	latest := int64(-1)
+	for _, entry := range entries {
+		if version, ok := parseDeltaCommitVersion(entry.Name()); ok && version > latest {
+			latest = version
+		}
+	}
+	return latest, nil
+}
+
+func (l localDeltaTableStore) putCommit(version int64, data []byte) error {
+	commitPath := filepath.Join(l.root, deltaCommitName(version))
+	if err := os.MkdirAll(filepath.Dir(commitPath), os.ModePerm); err != nil {
+		return err
+	}
+
+	// Write the commit to a temporary file first and link it into place, so readers never see a partial commit
+	tmpFile, err := os.CreateTemp(filepath.Dir(commitPath), ".tmp-commit-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmpFile.Name())
+
+	if _, err := tmpFile.Write(data); err != nil {
+		tmpFile.Close()
+		return err
+	}
+	if err := tmpFile.Close(); err != nil {
+		return err
+	}
+
+	err = os.Link(tmpFile.Name(), commitPath)
+	if os.IsExist(err) {
+		return errDeltaCommitExists
+	}
+	return err
+}
+
+func (l localDeltaTableStore) putDataFile(localPath, name string) error {
+	source, err := os.Open(localPath)
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+
+	destinationPath := filepath.Join(l.root, name)
+	if err := os.MkdirAll(filepath.Dir(destinationPath), os.ModePerm); err != nil {
+		return err
+	}
+
+	destination, err := os.Create(destinationPath)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(destination, source); err != nil {
+		destination.Close()
+		return err
+	}
+	return destination.Close()
+}
+
+// gcsDeltaTableStore stores a Delta Lake table under a prefix in a GCS bucket
+type gcsDeltaTableStore struct {
+	credentialsPath string
+	bucket          string
+	root            string
+}
+
+func (g gcsDeltaTableStore) client(ctx context.Context) (*storage.Client, error) {
+	// Use credentials file in dev/local runs. Otherwise, derive credentials from the service account.
+	if len(g.credentialsPath) > 0 {
+		os.Setenv("GOOGLE_APPLICATION_CREDENTIALS", g.credentialsPath)
+	}
+	return storage.NewClient(ctx)
+}
+
+func (g gcsDeltaTableStore) latestVersion() (int64, error) {
+	ctx := context.Background()
+	client, err := g.client(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create client: %v", err)
+	}
+	defer client.Close()
+
+	latest := int64(-1)
+	objects := client.Bucket(g.bucket).Objects(ctx, &storage.Query{Prefix: path.Join(g.root, "_delta_log") + "/"})
+	for {
+		attrs, err := objects.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return 0, err
+		}
+
+		if version, ok := parseDeltaCommitVersion(attrs.Name); ok && version > latest {
+			latest = version
+		}
+	}
+	return latest, nil
+}
+
+func (g gcsDeltaTableStore) putCommit(version int64, data []byte) error {
+	ctx := context.Background()
+	client, err := g.client(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to create client: %v", err)
+	}
+	defer client.Close()
+
+	// GCS objects only become visible once they are fully written, and the precondition makes the write fail if another
+	// writer committed the same version first
+	object := client.Bucket(g.bucket).Object(path.Join(g.root, deltaCommitName(version)))
+	wc := object.If(storage.Conditions{DoesNotExist: true}).NewWriter(ctx)
+	if _, err := wc.Write(data); err != nil {
+		wc.Close()
+		return err
+	}
+
+	err = wc.Close()
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) && apiErr.Code == http.StatusPreconditionFailed {
+		return errDeltaCommitExists
+	}
+	return err
+}
+
+func (g gcsDeltaTableStore) putDataFile(localPath, name string) error {
+	ctx := context.Background()
+	client, err := g.client(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to create client: %v", err)
+	}
+	defer client.Close()
+
+	reader, err := os.Open(localPath)
+	if err != nil {
+		return fmt.Errorf("failed to open file %s: %v", localPath, err)
+	}
+	defer reader.Close()
+
+	wc := client.Bucket(g.bucket).Object(path.Join(g.root, name)).NewWriter(ctx)
+	if _, err := io.Copy(wc, reader); err != nil {
+		wc.Close()
+		return fmt.Errorf("unable to copy: %v", err)
+	}
+	return wc.Close()
+}
+
+// newDeltaTableStore returns the store for a Delta Lake table. Tables are written to the cloud storage bucket if a cloud
+// provider is set, and to a local directory otherwise.
+func newDeltaTableStore(tablePath, cloudCredentials, cloudStorageBucket, cloudProvider string) (deltaTableStore, error) {
+	switch cloudProvider {
+	case "":
+		return localDeltaTableStore{root: tablePath}, nil
+	case "gcp":
+		return gcsDeltaTableStore{credentialsPath: cloudCredentials, bucket: cloudStorageBucket, root: tablePath}, nil
+	default:
+		return nil, fmt.Errorf("unknown cloud provider %s", cloudProvider)
+	}
+}
+
+// deltaField is a column in the schema of a Delta Lake table
+type deltaField struct {
+	Name     string                 `json:"name"`
+	Type     interface{}            `json:"type"`
+	Nullable bool                   `json:"nullable"`
+	Metadata map[string]interface{} `json:"metadata"`
+}
+
+// deltaArrayType is the Delta Lake type of a list column
+type deltaArrayType struct {
+	Type         string `json:"type"`
+	ElementType  string `json:"elementType"`
+	ContainsNull bool   `json:"containsNull"`
+}
+
+// deltaPrimitiveType converts a parquet physical and converted type into a Delta Lake primitive type
+func deltaPrimitiveType(parquetType, convertedType string) (string, error) {
+	switch parquetType {
+	case "BYTE_ARRAY":
+		if convertedType == "UTF8" {
+			return "string", nil
+		}
+		return "binary", nil
+	case "BOOLEAN":
+		return "boolean", nil
+	case "INT32":
+		return "integer", nil
+	case "INT64":
+		if convertedType == "TIMESTAMP_MILLIS" || convertedType == "TIMESTAMP_MICROS" {
+			return "timestamp", nil
+		}
+		return "long", nil
+	case "FLOAT":
+		return "float", nil
+	case "DOUBLE":
+		return "double", nil
+	}
+
+	return "", fmt.Errorf("unsupported parquet type %s", parquetType)
+}
+
+// deltaSchemaString builds the Delta Lake schema of a table from the parquet tags of its parquet schema struct, with the
+// columns renamed by the field mapping
+func deltaSchemaString(parquetSchema interface{}, mapping fieldMapping) (string, error) {
+	schemaType := reflect.TypeOf(parquetSchema)
+	for schemaType.Kind() == reflect.Pointer {
+		schemaType = schemaType.Elem()
+	}
+	if schemaType.Kind() != reflect.Struct {
+		return "", fmt.Errorf("parquet schema must be a struct, got %s", schemaType)
+	}
+
+	fields := []deltaField{}
+	for i := 0; i < schemaType.NumField(); i++ {
+		field := schemaType.Field(i)
+		tag := utils.ParseParquetTag(field.Tag.Get("parquet"))
+		if tag["name"] == "" {
+			continue
+		}
+
+		var fieldType interface{}
+		switch {
+		case tag["type"] == "MAP" && tag["convertedtype"] == "LIST":
+			elementType, err := deltaPrimitiveType(tag["valuetype"], tag["valueconvertedtype"])
+			if err != nil {
+				return "", fmt.Errorf("column %s: %v", tag["name"], err)
+			}
+			fieldType = deltaArrayType{Type: "array", ElementType: elementType, ContainsNull: true}
+		case field.Type.Kind() == reflect.Slice && field.Type.Elem().Kind() != reflect.Uint8:
+			elementType, err := deltaPrimitiveType(tag["type"], tag["convertedtype"])
+			if err != nil {
+				return "", fmt.Errorf("column %s: %v", tag["name"], err)
+			}
+			fieldType = deltaArrayType{Type: "array", ElementType: elementType, ContainsNull: true}
+		default:
+			primitiveType, err := deltaPrimitiveType(tag["type"], tag["convertedtype"])
+			if err != nil {
+				return "", fmt.Errorf("column %s: %v", tag["name"], err)
+			}
+			fieldType = primitiveType
+		}
+
+		fields = append(fields, deltaField{
+			Name:     mapping.name(tag["name"]),
+			Type:     fieldType,
+			Nullable: true,
+			Metadata: map[string]interface{}{},
+		})
+	}
+
+	schema, err := json.Marshal(map[string]interface{}{"type": "struct", "fields": fields})
+	if err != nil {
+		return "", err
+	}
+	return string(schema), nil
+}
+
+// deltaCommit builds the newline delimited actions of a commit that appends a data file to the table.
+// The first commit of a table also sets the protocol and the table metadata.
+func deltaCommit(version int64, schemaString, dataFileName string, dataFileSize int64, now time.Time) ([]byte, error) {
+	actions := []map[string]interface{}{}
+	if version == 0 {
+		actions = append(actions,
+			map[string]interface{}{"protocol": map[string]interface{}{
+				"minReaderVersion": 1,
+				"minWriterVersion": 2,
+			}},
+			map[string]interface{}{"metaData": map[string]interface{}{
+				"id":               uuid.NewString(),
+				"format":           map[string]interface{}{"provider": "parquet", "options": map[string]string{}},
+				"schemaString":     schemaString,
+				"partitionColumns": []string{},
+				"configuration":    map[string]string{},
+				"createdTime":      now.UnixMilli(),
+			}},
+		)
+	}
+
+	actions = append(actions,
+		map[string]interface{}{"add": map[string]interface{}{
+			"path":             dataFileName,
+			"partitionValues":  map[string]string{},
+			"size":             dataFileSize,
+			"modificationTime": now.UnixMilli(),
+			"dataChange":       true,
+		}},
+		map[string]interface{}{"commitInfo": map[string]interface{}{
+			"timestamp":           now.UnixMilli(),
+			"operation":           "WRITE",
+			"operationParameters": map[string]string{"mode": "Append"},
+			"isBlindAppend":       true,
+			"engineInfo":          "stellar-etl",
+		}},
+	)
+
+	var commit []byte
+	for _, action := range actions {
+		line, err := json.Marshal(action)
+		if err != nil {
+			return nil, err
+		}
+		commit = append(commit, line...)
+		commit = append(commit, '\n')
+	}
+	return commit, nil
+}
+
+// appendToDeltaTable adds a parquet file to a Delta Lake table as a new commit and returns the committed version.
+// The data file is copied into the table before the commit is written, so readers never see a commit without its data.
+// Appends never conflict with each other, so the commit is retried with the next version if another writer commits first.
+func appendToDeltaTable(store deltaTableStore, parquetPath string, parquetSchema interface{}, mapping fieldMapping) (int64, error) {
+	schemaString, err := deltaSchemaString(parquetSchema, mapping)
+	if err != nil {
+		return 0, err
+	}
+
+	info, err := os.Stat(parquetPath)
+	if err != nil {
+		return 0, err
+	}
+
+	dataFileName := fmt.Sprintf("part-%s.parquet", uuid.NewString())
+	if err := store.putDataFile(parquetPath, dataFileName); err != nil {
+		return 0, fmt.Errorf("could not write data file %s: %v", dataFileName, err)
+	}
+
+	for attempt := 0; attempt < maxDeltaCommitAttempts; attempt++ {
+		latest, err := store.latestVersion()
+		if err != nil {
+			return 0, fmt.Errorf("could not read the delta log: %v", err)
+		}
+
+		version := latest + 1
+		commit, err := deltaCommit(version, schemaString, dataFileName, info.Size(), time.Now())
+		if err != nil {
+			return 0, err
+		}
+
+		err = store.putCommit(version, commit)
+		if err == errDeltaCommitExists {
+			continue
+		}
+		if err != nil {
+			return 0, fmt.Errorf("could not write commit %d: %v", version, err)
+		}
+
+		return version, nil
+	}
+
+	return 0, fmt.Errorf("could not commit %s after %d attempts because of concurrent commits", dataFileName, maxDeltaCommitAttempts)
+}
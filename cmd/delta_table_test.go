@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type deltaTestParquet struct {
+	Name      string   `parquet:"name=name, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	Sequence  int64    `parquet:"name=ledger_sequence, type=INT64, convertedtype=INT64, convertedtype=UINT_64"`
+	ClosedAt  int64    `parquet:"name=closed_at, type=INT64, convertedtype=TIMESTAMP_MILLIS"`
+	Amount    float64  `parquet:"name=amount, type=DOUBLE"`
+	Type      int32    `parquet:"name=type, type=INT32"`
+	Deleted   bool     `parquet:"name=deleted, type=BOOLEAN"`
+	Signers   []string `parquet:"name=signers, type=MAP, convertedtype=LIST, valuetype=BYTE_ARRAY, valueconvertedtype=UTF8"`
+	unchecked string
+}
+
+func TestDeltaSchemaString(t *testing.T) {
+	schema, err := deltaSchemaString(new(deltaTestParquet), nil)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"type": "struct", "fields": [
+		{"name": "name", "type": "string", "nullable": true, "metadata": {}},
+		{"name": "ledger_sequence", "type": "long", "nullable": true, "metadata": {}},
+		{"name": "closed_at", "type": "timestamp", "nullable": true, "metadata": {}},
+		{"name": "amount", "type": "double", "nullable": true, "metadata": {}},
+		{"name": "type", "type": "integer", "nullable": true, "metadata": {}},
+		{"name": "deleted", "type": "boolean", "nullable": true, "metadata": {}},
+		{"name": "signers", "type": {"type": "array", "elementType": "string", "containsNull": true}, "nullable": true, "metadata": {}}
+	]}`, schema)
+}
+
+// staleDeltaTableStore reports an outdated latest version once, as if another writer committed in the meantime
+type staleDeltaTableStore struct {
+	localDeltaTableStore
+	stale bool
+}
+
+func (s *staleDeltaTableStore) latestVersion() (int64, error) {
+	if s.stale {
+		s.stale = false
+		return -1, nil
+	}
+	return s.localDeltaTableStore.latestVersion()
+}
+
+func TestAppendToDeltaTable(t *testing.T) {
+	tablePath := filepath.Join(t.TempDir(), "table")
+	parquetPath := filepath.Join(t.TempDir(), "exported.parquet")
+	assert.NoError(t, os.WriteFile(parquetPath, []byte("parquet data"), 0644))
+
+	store := localDeltaTableStore{root: tablePath}
+	version, err := appendToDeltaTable(store, parquetPath, new(deltaTestParquet), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), version)
+
+	version, err = appendToDeltaTable(&staleDeltaTableStore{localDeltaTableStore: store, stale: true}, parquetPath, new(deltaTestParquet), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), version)
+
+	latest, err := store.latestVersion()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), latest)
+
+	firstCommit, err := os.ReadFile(filepath.Join(tablePath, "_delta_log", "00000000000000000000.json"))
+	assert.NoError(t, err)
+	assert.Len(t, strings.Split(strings.TrimSpace(string(firstCommit)), "\n"), 4)
+	assert.Contains(t, string(firstCommit), `"protocol"`)
+	assert.Contains(t, string(firstCommit), `"metaData"`)
+
+	secondCommit, err := os.ReadFile(filepath.Join(tablePath, "_delta_log", "00000000000000000001.json"))
+	assert.NoError(t, err)
+	assert.Len(t, strings.Split(strings.TrimSpace(string(secondCommit)), "\n"), 2)
+	assert.NotContains(t, string(secondCommit), `"metaData"`)
+	assert.Contains(t, string(secondCommit), `"size":12`)
+
+	dataFiles, err := filepath.Glob(filepath.Join(tablePath, "part-*.parquet"))
+	assert.NoError(t, err)
+	assert.Len(t, dataFiles, 2)
+}
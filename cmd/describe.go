@@ -0,0 +1,191 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/stellar/stellar-etl/v2/internal/transform"
+	"github.com/stellar/stellar-etl/v2/internal/utils"
+)
+
+// describeSchemas are the output structs of the datasets, whose fields are the fields of the JSON output
+var describeSchemas = map[string]interface{}{
+	"account_operations":          new(transform.AccountOperationOutput),
+	"accounts":                    new(transform.AccountOutput),
+	"asset_supply":                new(transform.AssetSupplyOutput),
+	"assets":                      new(transform.AssetOutput),
+	"config_setting_diffs":        new(transform.ConfigSettingDiffOutput),
+	"config_settings":             new(transform.ConfigSettingOutput),
+	"config_upgrades":             new(transform.ConfigUpgradeOutput),
+	"contract_code":               new(transform.ContractCodeOutput),
+	"contract_data":               new(transform.ContractDataOutput),
+	"contract_events":             new(transform.ContractEventOutput),
+	"effects":                     new(transform.EffectOutput),
+	"footprint_reads":             new(transform.FootprintReadOutput),
+	"footprints":                  new(transform.FootprintOutput),
+	"inflation_payouts":           new(transform.InflationPayoutOutput),
+	"cb_claims":                   new(transform.ClaimableBalanceClaimOutput),
+	"ledger_fee_stats":            new(transform.LedgerFeeStatsOutput),
+	"ledger_upgrades":             new(transform.LedgerUpgradeOutput),
+	"ledgers":                     new(transform.LedgerOutput),
+	"liquidity_pools":             new(transform.PoolOutput),
+	"offers":                      new(transform.OfferOutput),
+	"operations":                  new(transform.OperationOutput),
+	"participants":                new(transform.ParticipantOutput),
+	"path_payment_hops":           new(transform.PathPaymentHopOutput),
+	"restore_candidates":          new(transform.RestoreCandidateOutput),
+	"signers":                     new(transform.AccountSignerOutput),
+	"trades":                      new(transform.TradeOutput),
+	"transactions":                new(transform.TransactionOutput),
+	"trustlines":                  new(transform.TrustlineOutput),
+	"ttl":                         new(transform.TtlOutput),
+	"ttl_expiry":                  new(transform.TtlExpiryOutput),
+	"liquidity_pool_participants": new(transform.PoolParticipantOutput),
+	"asset_metadata":              new(transform.AssetMetadataOutput),
+	"contract_specs":              new(transform.ContractSpecOutput),
+}
+
+// fieldDescription is a field of the output of a dataset. ParquetType is empty if the field has no parquet column.
+type fieldDescription struct {
+	Field       string `json:"field"`
+	GoType      string `json:"go_type"`
+	JSONName    string `json:"json_name"`
+	ParquetType string `json:"parquet_type"`
+	Description string `json:"description"`
+}
+
+var describeCmd = &cobra.Command{
+	Use:   "describe <dataset>",
+	Short: "Prints the fields of the output of a dataset",
+	Long: `Prints every field of the output of a dataset with its Go type, JSON name, parquet type and description, so
+that data dictionaries can be generated from the code. The descriptions are the description tags of the output structs.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		format, err := cmd.Flags().GetString("format")
+		if err != nil {
+			cmdLogger.Fatal("could not get format: ", err)
+		}
+
+		fields, err := describeDataset(args[0])
+		if err != nil {
+			cmdLogger.Fatal(err)
+		}
+
+		if err := printFieldDescriptions(cmd.OutOrStdout(), format, fields); err != nil {
+			cmdLogger.Fatal("could not print the fields: ", err)
+		}
+	},
+}
+
+// describeDataset returns the fields of the JSON output of a dataset, with the type of the parquet column of the same
+// name
+func describeDataset(dataset string) ([]fieldDescription, error) {
+	schema, ok := describeSchemas[dataset]
+	if !ok {
+		datasets := make([]string, 0, len(describeSchemas))
+		for name := range describeSchemas {
+			datasets = append(datasets, name)
+		}
+		sort.Strings(datasets)
+		return nil, fmt.Errorf("unsupported dataset %s; expected one of %s", dataset, strings.Join(datasets, ", "))
+	}
+
+	parquetSchema, err := datasetParquetSchema(dataset, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return describeFields(schema, parquetTypes(reflect.TypeOf(parquetSchema).Elem())), nil
+}
+
+// describeFields returns the fields of an output struct in the order they are marshalled. The fields of embedded
+// structs are described in place of the embedded struct, and fields that are not marshalled are skipped.
+func describeFields(schema interface{}, columnTypes map[string]string) []fieldDescription {
+	schemaType := reflect.TypeOf(schema)
+	for schemaType.Kind() == reflect.Pointer {
+		schemaType = schemaType.Elem()
+	}
+
+	fields := []fieldDescription{}
+	for i := 0; i < schemaType.NumField(); i++ {
+		field := schemaType.Field(i)
+		jsonName, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if jsonName == "-" {
+			continue
+		}
+		if field.Anonymous && jsonName == "" && field.Type.Kind() == reflect.Struct {
+			fields = append(fields, describeFields(reflect.New(field.Type).Interface(), columnTypes)...)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if jsonName == "" {
+			jsonName = field.Name
+		}
+
+		fields = append(fields, fieldDescription{
+			Field:       field.Name,
+			GoType:      field.Type.String(),
+			JSONName:    jsonName,
+			ParquetType: columnTypes[jsonName],
+			Description: field.Tag.Get("description"),
+		})
+	}
+
+	return fields
+}
+
+// parquetTypes returns the type of every column of a parquet schema struct by the name of the column, with the
+// converted type in parentheses
+func parquetTypes(schemaType reflect.Type) map[string]string {
+	columnTypes := map[string]string{}
+	for i := 0; i < schemaType.NumField(); i++ {
+		tag := utils.ParseParquetTag(schemaType.Field(i).Tag.Get("parquet"))
+		if tag["name"] == "" {
+			continue
+		}
+
+		columnType := tag["type"]
+		if tag["convertedtype"] != "" && tag["convertedtype"] != columnType {
+			columnType += " (" + tag["convertedtype"] + ")"
+		}
+		columnTypes[tag["name"]] = columnType
+	}
+	return columnTypes
+}
+
+// printFieldDescriptions prints the fields of a dataset as an aligned table, or as a JSON array
+func printFieldDescriptions(out io.Writer, format string, fields []fieldDescription) error {
+	switch format {
+	case "json":
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(fields)
+	case "table":
+		writer := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(writer, "FIELD\tGO TYPE\tJSON NAME\tPARQUET TYPE\tDESCRIPTION")
+		for _, field := range fields {
+			parquetType := field.ParquetType
+			if parquetType == "" {
+				parquetType = "-"
+			}
+			fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\n", field.Field, field.GoType, field.JSONName, parquetType, field.Description)
+		}
+		return writer.Flush()
+	}
+
+	return fmt.Errorf("unsupported format %s; expected table or json", format)
+}
+
+func init() {
+	rootCmd.AddCommand(describeCmd)
+	describeCmd.Flags().String("format", "table", "Format of the output: table or json.")
+}
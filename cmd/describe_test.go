@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type describeTestEmbedded struct {
+	Sequence uint32 `json:"ledger_sequence" description:"Sequence number of the ledger"`
+}
+
+type describeTestSchema struct {
+	describeTestEmbedded
+	AccountID string                 `json:"account_id" description:"Account of the record"`
+	ClosedAt  time.Time              `json:"closed_at"`
+	Details   map[string]interface{} `json:"details_json,omitempty"`
+	Ignored   string                 `json:"-"`
+	internal  string
+}
+
+func TestDescribeFields(t *testing.T) {
+	columnTypes := parquetTypes(reflect.TypeOf(ddlTestSchema{}))
+	want := []fieldDescription{
+		{Field: "Sequence", GoType: "uint32", JSONName: "ledger_sequence", ParquetType: "INT64 (UINT_64)", Description: "Sequence number of the ledger"},
+		{Field: "AccountID", GoType: "string", JSONName: "account_id", ParquetType: "BYTE_ARRAY (UTF8)", Description: "Account of the record"},
+		{Field: "ClosedAt", GoType: "time.Time", JSONName: "closed_at", ParquetType: "INT64 (TIMESTAMP_MILLIS)"},
+		{Field: "Details", GoType: "map[string]interface {}", JSONName: "details_json"},
+	}
+	assert.Equal(t, want, describeFields(new(describeTestSchema), columnTypes))
+}
+
+func TestDescribeDataset(t *testing.T) {
+	fields, err := describeDataset("operations")
+	assert.NoError(t, err)
+	assert.Contains(t, fields, fieldDescription{
+		Field:       "OperationID",
+		GoType:      "int64",
+		JSONName:    "id",
+		ParquetType: "INT64",
+		Description: "ID of the operation, unique across all operations",
+	})
+
+	_, err = describeDataset("token_transfers")
+	assert.ErrorContains(t, err, "unsupported dataset token_transfers")
+}
+
+func TestPrintFieldDescriptions(t *testing.T) {
+	fields := []fieldDescription{
+		{Field: "AccountID", GoType: "string", JSONName: "account_id", ParquetType: "BYTE_ARRAY (UTF8)", Description: "Account of the record"},
+		{Field: "Details", GoType: "map[string]interface {}", JSONName: "details_json"},
+	}
+
+	var table bytes.Buffer
+	assert.NoError(t, printFieldDescriptions(&table, "table", fields))
+	assert.Equal(t, "FIELD      GO TYPE                  JSON NAME     PARQUET TYPE       DESCRIPTION\n"+
+		"AccountID  string                   account_id    BYTE_ARRAY (UTF8)  Account of the record\n"+
+		"Details    map[string]interface {}  details_json  -                  \n", table.String())
+
+	var out bytes.Buffer
+	assert.NoError(t, printFieldDescriptions(&out, "json", fields[:1]))
+	assert.JSONEq(t, `[{"field": "AccountID", "go_type": "string", "json_name": "account_id", "parquet_type": "BYTE_ARRAY (UTF8)", "description": "Account of the record"}]`, out.String())
+
+	assert.Error(t, printFieldDescriptions(&out, "csv", fields))
+}
@@ -0,0 +1,264 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	recordAdded   = "added"
+	recordRemoved = "removed"
+	recordChanged = "changed"
+)
+
+// keyedRecords are the records of an exported file by the JSON encoding of their key, in the order of the file
+type keyedRecords struct {
+	keys    []string
+	records map[string]map[string]interface{}
+}
+
+// fieldChange is the old and new value of a changed field
+type fieldChange struct {
+	Old interface{} `json:"old"`
+	New interface{} `json:"new"`
+}
+
+// recordDiff is a record that was added, removed or changed between two exports. Added and removed records are
+// included in full, and changed records with their changed fields.
+type recordDiff struct {
+	Change string                 `json:"change"`
+	Key    map[string]interface{} `json:"key"`
+	Record map[string]interface{} `json:"record,omitempty"`
+	Fields map[string]fieldChange `json:"fields,omitempty"`
+}
+
+// diffSummary counts the differences between two exports
+type diffSummary struct {
+	Added         int            `json:"added"`
+	Removed       int            `json:"removed"`
+	Changed       int            `json:"changed"`
+	Unchanged     int            `json:"unchanged"`
+	ChangedFields map[string]int `json:"changed_fields"`
+}
+
+var diffCmd = &cobra.Command{
+	Use:   "diff fileA fileB",
+	Short: "Compares two exports of a dataset",
+	Long: `Compares two exports of a dataset for the same range, such as the exports of two versions of stellar-etl, and
+reports the records that were added, removed or changed. Records are matched by the fields of --key. Every difference
+is written as a JSON line to the output, and the number of differences of every kind is logged.`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		keyFields, err := cmd.Flags().GetStringSlice("key")
+		if err != nil {
+			cmdLogger.Fatal("could not get key: ", err)
+		}
+
+		ignoreFields, err := cmd.Flags().GetStringSlice("ignore-fields")
+		if err != nil {
+			cmdLogger.Fatal("could not get ignore-fields: ", err)
+		}
+
+		path, err := cmd.Flags().GetString("output")
+		if err != nil {
+			cmdLogger.Fatal("could not get output path: ", err)
+		}
+
+		failOnDiff, err := cmd.Flags().GetBool("fail-on-diff")
+		if err != nil {
+			cmdLogger.Fatal("could not get fail-on-diff boolean: ", err)
+		}
+
+		if len(keyFields) == 0 {
+			cmdLogger.Fatal("key is required to match the records of the two exports")
+		}
+
+		before, err := readKeyedRecords(args[0], keyFields)
+		if err != nil {
+			cmdLogger.Fatal(err)
+		}
+		after, err := readKeyedRecords(args[1], keyFields)
+		if err != nil {
+			cmdLogger.Fatal(err)
+		}
+
+		var out io.Writer = cmd.OutOrStdout()
+		if path != "" {
+			outFile := MustOutFile(path)
+			defer outFile.Close()
+			out = outFile
+		}
+
+		encoder := json.NewEncoder(out)
+		summary := diffExports(before, after, keyFields, ignoreFields, func(diff recordDiff) {
+			if err := encoder.Encode(diff); err != nil {
+				cmdLogger.Fatal("could not write difference: ", err)
+			}
+		})
+
+		marshalled, err := json.Marshal(summary)
+		if err != nil {
+			cmdLogger.Fatal("could not json encode diff summary: ", err)
+		}
+		cmdLogger.Info(string(marshalled))
+
+		if failOnDiff && summary.Added+summary.Removed+summary.Changed > 0 {
+			cmdLogger.Fatalf("%s and %s differ", args[0], args[1])
+		}
+	},
+}
+
+// readKeyedRecords reads the JSON lines of an exported file, keyed by the values of the key fields. Every record must
+// have the key fields, and the key must be unique.
+func readKeyedRecords(path string, keyFields []string) (keyedRecords, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return keyedRecords{}, fmt.Errorf("could not open %s: %v", path, err)
+	}
+	defer file.Close()
+
+	result := keyedRecords{records: map[string]map[string]interface{}{}}
+	reader := bufio.NewReader(file)
+	for lineNumber := 1; ; lineNumber++ {
+		line, err := reader.ReadBytes('\n')
+		if line = bytes.TrimSpace(line); len(line) > 0 {
+			record, decodeErr := decodeRecord(line)
+			if decodeErr != nil {
+				return keyedRecords{}, fmt.Errorf("could not decode line %d of %s: %v", lineNumber, path, decodeErr)
+			}
+
+			key, keyErr := recordKey(record, keyFields)
+			if keyErr != nil {
+				return keyedRecords{}, fmt.Errorf("line %d of %s: %v", lineNumber, path, keyErr)
+			}
+			if _, exists := result.records[key]; exists {
+				return keyedRecords{}, fmt.Errorf("line %d of %s has the same key %s as an earlier record", lineNumber, path, key)
+			}
+
+			result.keys = append(result.keys, key)
+			result.records[key] = record
+		}
+
+		if err == io.EOF {
+			return result, nil
+		}
+		if err != nil {
+			return keyedRecords{}, fmt.Errorf("could not read %s: %v", path, err)
+		}
+	}
+}
+
+// decodeRecord decodes a JSON record, keeping numbers as they were written so that large integers compare exactly
+func decodeRecord(line []byte) (map[string]interface{}, error) {
+	record := map[string]interface{}{}
+	decoder := json.NewDecoder(bytes.NewReader(line))
+	decoder.UseNumber()
+	if err := decoder.Decode(&record); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+// recordKey returns the JSON encoding of the values of the key fields of a record
+func recordKey(record map[string]interface{}, keyFields []string) (string, error) {
+	values := make([]interface{}, 0, len(keyFields))
+	for _, field := range keyFields {
+		value, ok := record[field]
+		if !ok {
+			return "", fmt.Errorf("record has no key field %s", field)
+		}
+		values = append(values, value)
+	}
+
+	key, err := json.Marshal(values)
+	if err != nil {
+		return "", err
+	}
+	return string(key), nil
+}
+
+// diffExports compares the records of two exports and calls report for every record that was removed or changed, in
+// the order of the first export, and then for every record that was added, in the order of the second export. Fields
+// in ignoreFields are not compared.
+func diffExports(before, after keyedRecords, keyFields, ignoreFields []string, report func(recordDiff)) diffSummary {
+	ignored := map[string]bool{}
+	for _, field := range ignoreFields {
+		ignored[field] = true
+	}
+
+	summary := diffSummary{ChangedFields: map[string]int{}}
+	for _, key := range before.keys {
+		oldRecord := before.records[key]
+		newRecord, ok := after.records[key]
+		if !ok {
+			summary.Removed += 1
+			report(recordDiff{Change: recordRemoved, Key: keyValues(oldRecord, keyFields), Record: oldRecord})
+			continue
+		}
+
+		fields := changedFields(oldRecord, newRecord, ignored)
+		if len(fields) == 0 {
+			summary.Unchanged += 1
+			continue
+		}
+
+		summary.Changed += 1
+		for field := range fields {
+			summary.ChangedFields[field] += 1
+		}
+		report(recordDiff{Change: recordChanged, Key: keyValues(oldRecord, keyFields), Fields: fields})
+	}
+
+	for _, key := range after.keys {
+		if _, ok := before.records[key]; ok {
+			continue
+		}
+		summary.Added += 1
+		newRecord := after.records[key]
+		report(recordDiff{Change: recordAdded, Key: keyValues(newRecord, keyFields), Record: newRecord})
+	}
+
+	return summary
+}
+
+// changedFields returns the top level fields whose values differ between two records, including fields that only
+// one of them has
+func changedFields(oldRecord, newRecord map[string]interface{}, ignored map[string]bool) map[string]fieldChange {
+	fields := map[string]fieldChange{}
+	for _, record := range []map[string]interface{}{oldRecord, newRecord} {
+		for field := range record {
+			if ignored[field] {
+				continue
+			}
+			oldValue, newValue := oldRecord[field], newRecord[field]
+			if !reflect.DeepEqual(oldValue, newValue) {
+				fields[field] = fieldChange{Old: oldValue, New: newValue}
+			}
+		}
+	}
+	return fields
+}
+
+// keyValues returns the key fields of a record
+func keyValues(record map[string]interface{}, keyFields []string) map[string]interface{} {
+	values := make(map[string]interface{}, len(keyFields))
+	for _, field := range keyFields {
+		values[field] = record[field]
+	}
+	return values
+}
+
+func init() {
+	rootCmd.AddCommand(diffCmd)
+	diffCmd.Flags().StringSlice("key", []string{}, "Comma separated fields that identify a record in both exports, such as operation_id or ledger_key_hash,ledger_sequence.")
+	diffCmd.Flags().StringSlice("ignore-fields", []string{}, "Comma separated fields that are not compared, such as fields that hold the time of the export.")
+	diffCmd.Flags().StringP("output", "o", "", "If set, the differences are written to this file instead of stdout.")
+	diffCmd.Flags().Bool("fail-on-diff", false, "If set, the command exits with an error if the exports differ.")
+}
@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeExport(t *testing.T, contents string) string {
+	path := filepath.Join(t.TempDir(), "export.txt")
+	assert.NoError(t, os.WriteFile(path, []byte(contents), 0644))
+	return path
+}
+
+func TestDiffExports(t *testing.T) {
+	before, err := readKeyedRecords(writeExport(t, `{"operation_id":12884905985,"type":1,"amount":10,"batch_run_date":"2024-01-01"}
+{"operation_id":12884905986,"type":1,"amount":20,"batch_run_date":"2024-01-01"}
+{"operation_id":12884905987,"type":2,"amount":30,"batch_run_date":"2024-01-01"}
+`), []string{"operation_id"})
+	assert.NoError(t, err)
+
+	after, err := readKeyedRecords(writeExport(t, `{"operation_id":12884905985,"type":1,"amount":10,"batch_run_date":"2024-02-01"}
+
+{"operation_id":12884905987,"type":2,"amount":31,"asset_code":"USDC","batch_run_date":"2024-02-01"}
+{"operation_id":12884905988,"type":3,"amount":40,"batch_run_date":"2024-02-01"}`), []string{"operation_id"})
+	assert.NoError(t, err)
+
+	diffs := []string{}
+	summary := diffExports(before, after, []string{"operation_id"}, []string{"batch_run_date"}, func(diff recordDiff) {
+		marshalled, err := json.Marshal(diff)
+		assert.NoError(t, err)
+		diffs = append(diffs, string(marshalled))
+	})
+
+	assert.Equal(t, []string{
+		`{"change":"removed","key":{"operation_id":12884905986},"record":{"amount":20,"batch_run_date":"2024-01-01","operation_id":12884905986,"type":1}}`,
+		`{"change":"changed","key":{"operation_id":12884905987},"fields":{"amount":{"old":30,"new":31},"asset_code":{"old":null,"new":"USDC"}}}`,
+		`{"change":"added","key":{"operation_id":12884905988},"record":{"amount":40,"batch_run_date":"2024-02-01","operation_id":12884905988,"type":3}}`,
+	}, diffs)
+	assert.Equal(t, diffSummary{Added: 1, Removed: 1, Changed: 1, Unchanged: 1, ChangedFields: map[string]int{"amount": 1, "asset_code": 1}}, summary)
+}
+
+func TestReadKeyedRecords(t *testing.T) {
+	records, err := readKeyedRecords(writeExport(t, `{"ledger_key_hash":"a","ledger_sequence":1}
+{"ledger_key_hash":"a","ledger_sequence":2}
+`), []string{"ledger_key_hash", "ledger_sequence"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{`["a",1]`, `["a",2]`}, records.keys)
+
+	_, err = readKeyedRecords(writeExport(t, `{"ledger_key_hash":"a","ledger_sequence":1}
+{"ledger_key_hash":"a","ledger_sequence":2}
+`), []string{"ledger_key_hash"})
+	assert.Error(t, err)
+
+	_, err = readKeyedRecords(writeExport(t, `{"ledger_sequence":1}`), []string{"operation_id"})
+	assert.Error(t, err)
+
+	_, err = readKeyedRecords(writeExport(t, `{"ledger_sequence":`), []string{"ledger_sequence"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// duckDBLoadScript returns the SQL that appends an export to a table of a DuckDB database. Parquet files keep the types
+// of the parquet schema, while the output jsons are loaded with the types that DuckDB detects. The table is created from
+// the first export, and later exports are inserted by column name.
+func duckDBLoadScript(table, dataPath string, isParquet bool) string {
+	literal := "'" + strings.ReplaceAll(dataPath, "'", "''") + "'"
+	source := fmt.Sprintf("read_json_auto(%s, format = 'newline_delimited')", literal)
+	if isParquet {
+		source = fmt.Sprintf("read_parquet(%s)", literal)
+	}
+
+	quotedTable := quoteSQLIdentifier(table)
+	return fmt.Sprintf("BEGIN TRANSACTION;\n"+
+		"CREATE TABLE IF NOT EXISTS %s AS SELECT * FROM %s LIMIT 0;\n"+
+		"INSERT INTO %s BY NAME SELECT * FROM %s;\n"+
+		"COMMIT;\n", quotedTable, source, quotedTable, source)
+}
+
+// loadIntoDuckDB appends an output file to a table of the DuckDB database at dbPath, with the duckdb executable
+func loadIntoDuckDB(executable, dbPath, table, dataPath string, isParquet bool) error {
+	if table == "" {
+		return fmt.Errorf("duckdb-table is required to write to duckdb")
+	}
+
+	absolutePath, err := filepath.Abs(dataPath)
+	if err != nil {
+		return fmt.Errorf("could not get absolute path of %s: %v", dataPath, err)
+	}
+
+	cmd := exec.Command(executable, "-bail", dbPath)
+	cmd.Stdin = strings.NewReader(duckDBLoadScript(table, absolutePath, isParquet))
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("duckdb failed: %v: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDuckDBLoadScript(t *testing.T) {
+	assert.Equal(t, `BEGIN TRANSACTION;
+CREATE TABLE IF NOT EXISTS "trades" AS SELECT * FROM read_parquet('/tmp/it''s.parquet') LIMIT 0;
+INSERT INTO "trades" BY NAME SELECT * FROM read_parquet('/tmp/it''s.parquet');
+COMMIT;
+`, duckDBLoadScript("trades", "/tmp/it's.parquet", true))
+
+	assert.Equal(t, `BEGIN TRANSACTION;
+CREATE TABLE IF NOT EXISTS "token_transfer" AS SELECT * FROM read_json_auto('/tmp/out.txt', format = 'newline_delimited') LIMIT 0;
+INSERT INTO "token_transfer" BY NAME SELECT * FROM read_json_auto('/tmp/out.txt', format = 'newline_delimited');
+COMMIT;
+`, duckDBLoadScript("token_transfer", "/tmp/out.txt", false))
+}
+
+func TestLoadIntoDuckDB(t *testing.T) {
+	dir := t.TempDir()
+	recordPath := filepath.Join(dir, "invocation.txt")
+	// The fake duckdb executable records its arguments and the script it receives
+	executable := filepath.Join(dir, "duckdb")
+	script := "#!/bin/sh\necho \"$@\" > " + recordPath + "\ncat >> " + recordPath + "\n"
+	assert.NoError(t, os.WriteFile(executable, []byte(script), 0755))
+
+	assert.NoError(t, loadIntoDuckDB(executable, "out.duckdb", "ledgers", "/tmp/ledgers.txt", false))
+
+	invocation, err := os.ReadFile(recordPath)
+	assert.NoError(t, err)
+	assert.Equal(t, "-bail out.duckdb\n"+duckDBLoadScript("ledgers", "/tmp/ledgers.txt", false), string(invocation))
+}
+
+func TestLoadIntoDuckDBReportsErrors(t *testing.T) {
+	executable := filepath.Join(t.TempDir(), "duckdb")
+	assert.NoError(t, os.WriteFile(executable, []byte("#!/bin/sh\necho 'Catalog Error: bad table'\nexit 1\n"), 0755))
+
+	err := loadIntoDuckDB(executable, "out.duckdb", "ledgers", "/tmp/ledgers.txt", false)
+	assert.ErrorContains(t, err, "Catalog Error: bad table")
+}
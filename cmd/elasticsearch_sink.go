@@ -0,0 +1,200 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// elasticsearchSink indexes batches of exported records with the bulk API of Elasticsearch or OpenSearch. Requests are
+// retried with an exponential backoff when the cluster can't be reached or responds with a 429 or 5xx status, and
+// records that the cluster rejects with those statuses are retried on their own.
+type elasticsearchSink struct {
+	bulkURL     string
+	username    string
+	password    string
+	apiKey      string
+	index       string
+	batchSize   int
+	maxAttempts int
+	retryWait   time.Duration
+	client      *http.Client
+
+	batch      [][]byte
+	numIndexed int
+}
+
+// elasticsearchBulkResponse is the part of a bulk API response that reports the result of every record
+type elasticsearchBulkResponse struct {
+	Errors bool `json:"errors"`
+	Items  []map[string]struct {
+		Status int             `json:"status"`
+		Error  json.RawMessage `json:"error"`
+	} `json:"items"`
+}
+
+func newElasticsearchSink(clusterURL, apiKey, index string, batchSize int) (*elasticsearchSink, error) {
+	if clusterURL == "" {
+		return nil, fmt.Errorf("elasticsearch-url is required for the elasticsearch sink")
+	}
+	if index == "" {
+		return nil, fmt.Errorf("elasticsearch-index is required for the elasticsearch sink")
+	}
+	if batchSize <= 0 {
+		return nil, fmt.Errorf("elasticsearch-batch-size must be positive, got %d", batchSize)
+	}
+
+	parsed, err := url.Parse(clusterURL)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse elasticsearch url: %v", err)
+	}
+
+	// Credentials in the url are sent with basic auth instead of being part of the request url
+	var username, password string
+	if parsed.User != nil {
+		username = parsed.User.Username()
+		password, _ = parsed.User.Password()
+		parsed.User = nil
+	}
+	parsed.Path = strings.TrimSuffix(parsed.Path, "/") + "/_bulk"
+
+	return &elasticsearchSink{
+		bulkURL:     parsed.String(),
+		username:    username,
+		password:    password,
+		apiKey:      apiKey,
+		index:       index,
+		batchSize:   batchSize,
+		maxAttempts: 5,
+		retryWait:   time.Second,
+		client:      &http.Client{Timeout: 60 * time.Second},
+	}, nil
+}
+
+// add queues an encoded record, and indexes the batch once it is full
+func (e *elasticsearchSink) add(record []byte) error {
+	e.batch = append(e.batch, record)
+	if len(e.batch) >= e.batchSize {
+		return e.flush()
+	}
+	return nil
+}
+
+// flush indexes the queued records, if there are any
+func (e *elasticsearchSink) flush() error {
+	if len(e.batch) == 0 {
+		return nil
+	}
+
+	pending := e.batch
+	for attempt := 1; ; attempt++ {
+		rejected, retry, err := e.bulk(pending)
+		if err == nil {
+			break
+		}
+		if !retry || attempt >= e.maxAttempts {
+			return fmt.Errorf("could not index %d records after %d attempts: %v", len(pending), attempt, err)
+		}
+
+		pending = rejected
+		wait := e.retryWait << (attempt - 1)
+		cmdLogger.Warnf("Could not index %d records, retrying in %s: %v", len(pending), wait, err)
+		time.Sleep(wait)
+	}
+
+	e.numIndexed += len(e.batch)
+	e.batch = e.batch[:0]
+	return nil
+}
+
+// bulkBody returns the bulk API request body that indexes the records
+func (e *elasticsearchSink) bulkBody(records [][]byte) ([]byte, error) {
+	action, err := json.Marshal(map[string]map[string]string{"index": {"_index": e.index}})
+	if err != nil {
+		return nil, err
+	}
+
+	var body bytes.Buffer
+	for _, record := range records {
+		body.Write(action)
+		body.WriteByte('\n')
+		body.Write(record)
+		body.WriteByte('\n')
+	}
+	return body.Bytes(), nil
+}
+
+// bulk sends a single bulk request. If it fails, bulk returns the records that have to be sent again and whether they
+// should be retried.
+func (e *elasticsearchSink) bulk(records [][]byte) ([][]byte, bool, error) {
+	body, err := e.bulkBody(records)
+	if err != nil {
+		return nil, false, fmt.Errorf("could not encode bulk request: %v", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, e.bulkURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, false, err
+	}
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	if e.apiKey != "" {
+		req.Header.Set("Authorization", "ApiKey "+e.apiKey)
+	} else if e.username != "" {
+		req.SetBasicAuth(e.username, e.password)
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return records, true, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return records, true, err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		retry := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+		return records, retry, fmt.Errorf("elasticsearch returned %s: %s", resp.Status, respBody)
+	}
+
+	var result elasticsearchBulkResponse
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return nil, false, fmt.Errorf("could not decode bulk response: %v", err)
+	}
+	if !result.Errors {
+		return nil, false, nil
+	}
+	if len(result.Items) != len(records) {
+		return nil, false, fmt.Errorf("bulk response has %d items for %d records", len(result.Items), len(records))
+	}
+
+	rejected := [][]byte{}
+	for i, item := range result.Items {
+		for _, itemResult := range item {
+			if itemResult.Status >= 200 && itemResult.Status < 300 {
+				continue
+			}
+			if itemResult.Status != http.StatusTooManyRequests && itemResult.Status < 500 {
+				return nil, false, fmt.Errorf("elasticsearch rejected a record with status %d: %s", itemResult.Status, itemResult.Error)
+			}
+			rejected = append(rejected, records[i])
+		}
+	}
+
+	return rejected, true, fmt.Errorf("elasticsearch rejected %d records", len(rejected))
+}
+
+func (e *elasticsearchSink) close() error {
+	return nil
+}
+
+func (e *elasticsearchSink) describe() string {
+	return fmt.Sprintf("Indexed %d records in the %s index", e.numIndexed, e.index)
+}
@@ -0,0 +1,335 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/kms"
+	"golang.org/x/oauth2/google"
+)
+
+// encryptedValuePrefix marks the values that were encrypted by a fieldEncryptor. Encrypted values have the format
+// enc:v1:<base64 wrapped data key>:<base64 nonce and ciphertext>, so every value can be decrypted on its own.
+const encryptedValuePrefix = "enc:v1:"
+
+// keyWrapper encrypts the data keys that encrypt the output fields with a key held in a key management service
+type keyWrapper interface {
+	wrapKey(dataKey []byte) ([]byte, error)
+}
+
+// newKeyWrapper returns the keyWrapper for a key URI:
+//
+//	gcp-kms://projects/<project>/locations/<location>/keyRings/<ring>/cryptoKeys/<key>
+//	aws-kms://arn:aws:kms:<region>:<account>:key/<key id>
+//	local-key://<base64 encoded 256 bit key>, for development and testing only
+func newKeyWrapper(keyURI string) (keyWrapper, error) {
+	switch {
+	case strings.HasPrefix(keyURI, "gcp-kms://"):
+		return gcpKMSKeyWrapper{keyName: strings.TrimPrefix(keyURI, "gcp-kms://")}, nil
+	case strings.HasPrefix(keyURI, "aws-kms://"):
+		return newAWSKMSKeyWrapper(strings.TrimPrefix(keyURI, "aws-kms://"))
+	case strings.HasPrefix(keyURI, "local-key://"):
+		key, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(keyURI, "local-key://"))
+		if err != nil {
+			return nil, fmt.Errorf("could not decode local key: %v", err)
+		}
+		if len(key) != 32 {
+			return nil, fmt.Errorf("local key must be 32 bytes, got %d", len(key))
+		}
+		return localKeyWrapper{key: key}, nil
+	}
+
+	return nil, fmt.Errorf("unsupported kms key %s; expected a gcp-kms://, aws-kms:// or local-key:// uri", keyURI)
+}
+
+// gcpKMSKeyWrapper wraps data keys with a GCP KMS key through the Cloud KMS REST API
+type gcpKMSKeyWrapper struct {
+	keyName string
+}
+
+func (g gcpKMSKeyWrapper) wrapKey(dataKey []byte) ([]byte, error) {
+	ctx := context.Background()
+	client, err := google.DefaultClient(ctx, "https://www.googleapis.com/auth/cloudkms")
+	if err != nil {
+		return nil, fmt.Errorf("could not create kms client: %v", err)
+	}
+
+	body, err := json.Marshal(map[string]string{"plaintext": base64.StdEncoding.EncodeToString(dataKey)})
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.Post(fmt.Sprintf("https://cloudkms.googleapis.com/v1/%s:encrypt", g.keyName), "application/json", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("could not call kms: %v", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("kms returned %s: %s", resp.Status, respBody)
+	}
+
+	var encrypted struct {
+		Ciphertext string `json:"ciphertext"`
+	}
+	if err := json.Unmarshal(respBody, &encrypted); err != nil {
+		return nil, fmt.Errorf("could not decode kms response: %v", err)
+	}
+	return base64.StdEncoding.DecodeString(encrypted.Ciphertext)
+}
+
+// awsKMSKeyWrapper wraps data keys with an AWS KMS key
+type awsKMSKeyWrapper struct {
+	keyID  string
+	client *kms.KMS
+}
+
+func newAWSKMSKeyWrapper(keyID string) (keyWrapper, error) {
+	config := aws.Config{}
+	// Key ARNs include the region of the key, which takes precedence over the configured region
+	if arnParts := strings.Split(keyID, ":"); len(arnParts) > 3 && arnParts[0] == "arn" {
+		config.Region = aws.String(arnParts[3])
+	}
+
+	sess, err := session.NewSessionWithOptions(session.Options{
+		Config:            config,
+		SharedConfigState: session.SharedConfigEnable,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not create aws session: %v", err)
+	}
+
+	return awsKMSKeyWrapper{keyID: keyID, client: kms.New(sess)}, nil
+}
+
+func (a awsKMSKeyWrapper) wrapKey(dataKey []byte) ([]byte, error) {
+	output, err := a.client.Encrypt(&kms.EncryptInput{
+		KeyId:     aws.String(a.keyID),
+		Plaintext: dataKey,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not call kms: %v", err)
+	}
+	return output.CiphertextBlob, nil
+}
+
+// localKeyWrapper wraps data keys with a local AES-256-GCM key
+type localKeyWrapper struct {
+	key []byte
+}
+
+func (l localKeyWrapper) wrapKey(dataKey []byte) ([]byte, error) {
+	aead, err := newAEAD(l.key)
+	if err != nil {
+		return nil, err
+	}
+	return seal(aead, dataKey)
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// seal encrypts the plaintext with a random nonce, and returns the nonce followed by the ciphertext
+func seal(aead cipher.AEAD, plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// fieldEncryptor envelope encrypts fields of the exported records. Every export uses a new random data key, which is
+// wrapped once by the key management service and stored next to every encrypted value.
+type fieldEncryptor struct {
+	fields     map[string]bool
+	aead       cipher.AEAD
+	wrappedKey string
+}
+
+// newFieldEncryptor creates the encryptor of the fields of an output, which are checked before the data key is created,
+// so that a misspelled field stops the export instead of being written in plaintext
+func newFieldEncryptor(fields []string, keyURI string, outputSchema interface{}) (*fieldEncryptor, error) {
+	if keyURI == "" {
+		return nil, fmt.Errorf("a kms key is required to encrypt fields")
+	}
+
+	fieldSet := map[string]bool{}
+	for _, field := range fields {
+		fieldSet[strings.TrimSpace(field)] = true
+	}
+	if err := checkEncryptedFields(fieldSet, reflect.TypeOf(outputSchema)); err != nil {
+		return nil, err
+	}
+
+	wrapper, err := newKeyWrapper(keyURI)
+	if err != nil {
+		return nil, err
+	}
+
+	dataKey := make([]byte, 32)
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, err
+	}
+
+	wrappedKey, err := wrapper.wrapKey(dataKey)
+	if err != nil {
+		return nil, fmt.Errorf("could not wrap the data key: %v", err)
+	}
+
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &fieldEncryptor{
+		fields:     fieldSet,
+		aead:       aead,
+		wrappedKey: base64.StdEncoding.EncodeToString(wrappedKey),
+	}, nil
+}
+
+// checkEncryptedFields returns an error if a field to encrypt is not a string or string slice field of the output
+func checkEncryptedFields(fields map[string]bool, outputType reflect.Type) error {
+	fieldTypes := map[string]reflect.Type{}
+	if outputType != nil {
+		outputFieldTypes(outputType, fieldTypes)
+	}
+
+	unknown := []string{}
+	for name := range fields {
+		fieldType, ok := fieldTypes[name]
+		if !ok {
+			unknown = append(unknown, name)
+			continue
+		}
+		if fieldType.Kind() != reflect.String && (fieldType.Kind() != reflect.Slice || fieldType.Elem().Kind() != reflect.String) {
+			return fmt.Errorf("field %s is a %s; only string fields can be encrypted", name, fieldType)
+		}
+	}
+
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return fmt.Errorf("%s in encrypt-fields are not fields of the output", strings.Join(unknown, ", "))
+	}
+	return nil
+}
+
+// outputFieldTypes adds the type of every field of an output struct to fieldTypes by JSON name, with the fields of
+// embedded structs, the same way encryptStructFields finds them
+func outputFieldTypes(outputType reflect.Type, fieldTypes map[string]reflect.Type) {
+	for outputType.Kind() == reflect.Pointer {
+		outputType = outputType.Elem()
+	}
+	if outputType.Kind() != reflect.Struct {
+		return
+	}
+
+	for i := 0; i < outputType.NumField(); i++ {
+		field := outputType.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct && field.IsExported() {
+			outputFieldTypes(field.Type, fieldTypes)
+			continue
+		}
+
+		if field.IsExported() && name != "" && name != "-" {
+			fieldTypes[name] = field.Type
+		}
+	}
+}
+
+// encryptValue encrypts a single value. Empty values are left empty.
+func (f *fieldEncryptor) encryptValue(value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+
+	sealed, err := seal(f.aead, []byte(value))
+	if err != nil {
+		return "", err
+	}
+	return encryptedValuePrefix + f.wrappedKey + ":" + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// encryptRecord returns a copy of the record with the selected fields encrypted, so both the JSON and the parquet output
+// only contain the encrypted values. Fields are selected by their JSON name. String fields and string slices are supported.
+func (f *fieldEncryptor) encryptRecord(record interface{}) (interface{}, error) {
+	original := reflect.ValueOf(record)
+	if original.Kind() != reflect.Struct {
+		return record, nil
+	}
+
+	encrypted := reflect.New(original.Type()).Elem()
+	encrypted.Set(original)
+	if err := f.encryptStructFields(encrypted); err != nil {
+		return nil, err
+	}
+	return encrypted.Interface(), nil
+}
+
+func (f *fieldEncryptor) encryptStructFields(record reflect.Value) error {
+	recordType := record.Type()
+	for i := 0; i < recordType.NumField(); i++ {
+		field := recordType.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct && field.IsExported() {
+			if err := f.encryptStructFields(record.Field(i)); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if !f.fields[name] || !field.IsExported() {
+			continue
+		}
+
+		value := record.Field(i)
+		switch {
+		case value.Kind() == reflect.String:
+			encrypted, err := f.encryptValue(value.String())
+			if err != nil {
+				return err
+			}
+			value.SetString(encrypted)
+		case value.Kind() == reflect.Slice && value.Type().Elem().Kind() == reflect.String:
+			// Build a new slice so the values of the original record aren't modified
+			encryptedSlice := reflect.MakeSlice(value.Type(), value.Len(), value.Len())
+			for j := 0; j < value.Len(); j++ {
+				encrypted, err := f.encryptValue(value.Index(j).String())
+				if err != nil {
+					return err
+				}
+				encryptedSlice.Index(j).SetString(encrypted)
+			}
+			if !value.IsNil() {
+				value.Set(encryptedSlice)
+			}
+		default:
+			return fmt.Errorf("field %s is a %s; only string fields can be encrypted", name, value.Type())
+		}
+	}
+
+	return nil
+}
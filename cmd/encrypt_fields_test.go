@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stellar/stellar-etl/v2/internal/transform"
+)
+
+// decryptTestValue decrypts a value encrypted with a local key
+func decryptTestValue(t *testing.T, localKey []byte, value string) string {
+	parts := strings.Split(strings.TrimPrefix(value, encryptedValuePrefix), ":")
+	assert.Len(t, parts, 2)
+
+	open := func(key []byte, encoded string) []byte {
+		sealed, err := base64.StdEncoding.DecodeString(encoded)
+		assert.NoError(t, err)
+		aead, err := newAEAD(key)
+		assert.NoError(t, err)
+		plaintext, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], nil)
+		assert.NoError(t, err)
+		return plaintext
+	}
+
+	dataKey := open(localKey, parts[0])
+	return string(open(dataKey, parts[1]))
+}
+
+func TestEncryptRecord(t *testing.T) {
+	localKey := []byte("0123456789abcdef0123456789abcdef")
+	encryptor, err := newFieldEncryptor([]string{"memo", "tx_signers"}, "local-key://"+base64.StdEncoding.EncodeToString(localKey), new(transform.TransactionOutput))
+	assert.NoError(t, err)
+
+	transaction := transform.TransactionOutput{
+		TransactionHash: "a87fef5eeb260269c380f2de456aad72b59bb315aaac777860456e09dac0bafb",
+		MemoType:        "MemoTypeMemoText",
+		Memo:            "customer 123",
+		TxSigners:       []string{"signature-1", ""},
+	}
+
+	encrypted, err := encryptor.encryptRecord(transaction)
+	assert.NoError(t, err)
+	encryptedTransaction := encrypted.(transform.TransactionOutput)
+
+	assert.Equal(t, transaction.TransactionHash, encryptedTransaction.TransactionHash)
+	assert.Equal(t, transaction.MemoType, encryptedTransaction.MemoType)
+	assert.True(t, strings.HasPrefix(encryptedTransaction.Memo, encryptedValuePrefix))
+	assert.Equal(t, "customer 123", decryptTestValue(t, localKey, encryptedTransaction.Memo))
+	assert.Equal(t, "signature-1", decryptTestValue(t, localKey, encryptedTransaction.TxSigners[0]))
+	assert.Equal(t, "", encryptedTransaction.TxSigners[1])
+
+	// The original record is left unchanged
+	assert.Equal(t, "customer 123", transaction.Memo)
+	assert.Equal(t, []string{"signature-1", ""}, transaction.TxSigners)
+}
+
+func TestEncryptRecordRejectsNonStringFields(t *testing.T) {
+	localKey := base64.StdEncoding.EncodeToString([]byte("0123456789abcdef0123456789abcdef"))
+	_, err := newFieldEncryptor([]string{"fee_charged"}, "local-key://"+localKey, new(transform.TransactionOutput))
+	assert.EqualError(t, err, "field fee_charged is a int64; only string fields can be encrypted")
+
+	encryptor, err := newFieldEncryptor([]string{"memo"}, "local-key://"+localKey, new(transform.TransactionOutput))
+	assert.NoError(t, err)
+	encryptor.fields["fee_charged"] = true
+	_, err = encryptor.encryptRecord(transform.TransactionOutput{FeeCharged: 100})
+	assert.Error(t, err)
+}
+
+func TestNewFieldEncryptorRejectsUnknownFields(t *testing.T) {
+	localKey := base64.StdEncoding.EncodeToString([]byte("0123456789abcdef0123456789abcdef"))
+	_, err := newFieldEncryptor([]string{"memo", "acount_id", " tx_signer"}, "local-key://"+localKey, new(transform.TransactionOutput))
+	assert.EqualError(t, err, "acount_id, tx_signer in encrypt-fields are not fields of the output")
+
+	// The fields of embedded structs can be encrypted
+	_, err = newFieldEncryptor([]string{"source_account"}, "local-key://"+localKey, new(transform.FlattenedOperationOutput))
+	assert.NoError(t, err)
+}
+
+func TestNewFieldEncryptorRequiresKey(t *testing.T) {
+	_, err := newFieldEncryptor([]string{"memo"}, "", new(transform.TransactionOutput))
+	assert.Error(t, err)
+
+	_, err = newFieldEncryptor([]string{"memo"}, "vault://key", new(transform.TransactionOutput))
+	assert.Error(t, err)
+}
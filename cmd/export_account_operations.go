@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stellar/stellar-etl/v2/internal/input"
+	"github.com/stellar/stellar-etl/v2/internal/transform"
+	"github.com/stellar/stellar-etl/v2/internal/utils"
+)
+
+var accountOperationsCmd = &cobra.Command{
+	Use:   "export_account_operations",
+	Short: "Exports (account, operation) pairs over a specified range.",
+	Long: `Exports an (account_id, operation_id) pair for every account or contract taking part in an operation within the
+specified range to an output file. The pairs have no operation details, so they can be loaded into reverse indexes cheaply.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmdLogger.SetLevel(logrus.InfoLevel)
+		cmdArgs := utils.MustFlags(cmd.Flags(), cmdLogger)
+
+		// TODO: https://stellarorg.atlassian.net/browse/HUBBLE-386 GetEnvironmentDetails should be refactored
+		commonArgs := utils.MustCommonFlags(cmd.Flags(), cmdLogger)
+		env := utils.GetEnvironmentDetails(commonArgs)
+
+		engine := newExportEngine(cmdArgs, env, new(transform.AccountOperationOutput), new(transform.AccountOperationOutputParquet))
+		engine.run(func(lcm xdr.LedgerCloseMeta) error {
+			transactions, err := input.GetLedgerTransactions(lcm, env)
+			if err != nil {
+				return err
+			}
+
+			// Operations involve any number of accounts, so the limit is applied to the exported pairs instead of the input
+			for _, transformInput := range transactions {
+				if engine.limitReached() {
+					break
+				}
+
+				engine.attempt()
+				ledgerSeq := uint32(transformInput.LedgerHistory.Header.LedgerSeq)
+				transformed, err := transform.TransformAccountOperations(transformInput.Transaction, ledgerSeq, transformInput.LedgerCloseMeta, env.NetworkPassphrase)
+				if err != nil {
+					engine.fail(fmt.Errorf("could not transform account operations in transaction %d in ledger %d: %v", transformInput.Transaction.Index, ledgerSeq, err))
+					continue
+				}
+
+				for _, pair := range transformed {
+					engine.export(pair)
+				}
+			}
+
+			return nil
+		})
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(accountOperationsCmd)
+	utils.AddCommonFlags(accountOperationsCmd.Flags())
+	utils.AddArchiveFlags("account_operations", accountOperationsCmd.Flags())
+	utils.AddCloudStorageFlags(accountOperationsCmd.Flags())
+
+	accountOperationsCmd.MarkFlagRequired("start-ledger")
+	accountOperationsCmd.MarkFlagRequired("end-ledger")
+}
@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stellar/stellar-etl/v2/internal/input"
+	"github.com/stellar/stellar-etl/v2/internal/transform"
+	"github.com/stellar/stellar-etl/v2/internal/utils"
+)
+
+var ammPoolSnapshotsCmd = &cobra.Command{
+	Use:   "export_amm_pool_snapshots",
+	Short: "Exports the state of every liquidity pool at a fixed ledger frequency.",
+	Long: `Exports the state of every liquidity pool every snapshot-frequency ledgers within the specified range to an
+output file, which is a compact time series of the reserves of every pool. The pools are read from the bucket list of
+the checkpoint before the start of the range and kept up to date with the changes of every ledger, so intermediate
+changes are not exported.
+
+If the end-ledger is omitted, then the export will continue running and exporting snapshots as new ledgers are
+confirmed by the Stellar network.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmdLogger.SetLevel(logrus.InfoLevel)
+		cmdArgs := utils.MustFlags(cmd.Flags(), cmdLogger)
+		commonArgs := utils.MustCommonFlags(cmd.Flags(), cmdLogger)
+		env := utils.GetEnvironmentDetails(commonArgs)
+
+		frequency, err := cmd.Flags().GetUint32("snapshot-frequency")
+		if err != nil {
+			cmdLogger.Fatal("could not get snapshot-frequency: ", err)
+		}
+		if frequency == 0 {
+			cmdLogger.Fatal("snapshot-frequency must be greater than 0")
+		}
+
+		if commonArgs.Descending {
+			cmdLogger.Fatal("descending order is not supported when exporting pool snapshots, because the pools are updated in ledger order")
+		}
+
+		if commonArgs.EveryNthLedger > 1 || commonArgs.SampleRate < 1 {
+			cmdLogger.Fatal("ledger sampling is not supported when exporting pool snapshots; use snapshot-frequency instead")
+		}
+
+		engine := newExportEngine(cmdArgs, env, new(transform.PoolOutput), new(transform.PoolOutputParquet))
+
+		// The pools are read from the last checkpoint before the range, and the ledgers after the checkpoint are
+		// replayed up to the start of the range without exporting them
+		exportStart := engine.cmdArgs.StartNum
+		pools := input.LiquidityPools{}
+		replayStart := uint32(2)
+		if exportStart >= 64 {
+			checkpoint := utils.GetMostRecentCheckpoint(exportStart - 1)
+			cmdLogger.Infof("Reading the liquidity pools of checkpoint %d", checkpoint)
+			pools, err = input.GetCheckpointLiquidityPools(context.Background(), env, checkpoint)
+			if err != nil {
+				cmdLogger.Fatal(err)
+			}
+			replayStart = checkpoint + 1
+		}
+		if replayStart < exportStart {
+			cmdLogger.Infof("Replaying ledgers %d to %d before the start of the range", replayStart, exportStart-1)
+			engine.cmdArgs.StartNum = replayStart
+		}
+
+		engine.run(func(lcm xdr.LedgerCloseMeta) error {
+			// Every later snapshot would be wrong if the changes of a ledger were missed, so they stop the export
+			ledgerSeq := utils.GetLedgerSequence(lcm)
+			if err := pools.ApplyLedger(lcm, env.NetworkPassphrase); err != nil {
+				return err
+			}
+
+			if ledgerSeq < exportStart || !isSnapshotLedger(ledgerSeq, frequency) {
+				return nil
+			}
+
+			header := lcm.LedgerHeaderHistoryEntry()
+			for _, change := range pools.Changes() {
+				engine.attempt()
+				pool, err := transform.TransformPool(change, header)
+				if err != nil {
+					engine.fail(fmt.Errorf("could not transform liquidity pool in ledger %d: %v", ledgerSeq, err))
+					continue
+				}
+
+				engine.export(pool)
+			}
+
+			return nil
+		})
+	},
+}
+
+// isSnapshotLedger reports whether pools are snapshotted at a ledger. Snapshots are taken at the ledgers before a
+// multiple of the frequency, so that the default frequency of 64 snapshots the pools at every checkpoint ledger and
+// the snapshot ledgers don't depend on the start of the range.
+func isSnapshotLedger(ledgerSeq, frequency uint32) bool {
+	return (ledgerSeq+1)%frequency == 0
+}
+
+func init() {
+	rootCmd.AddCommand(ammPoolSnapshotsCmd)
+	utils.AddCommonFlags(ammPoolSnapshotsCmd.Flags())
+	utils.AddArchiveFlags("amm_pool_snapshots", ammPoolSnapshotsCmd.Flags())
+	utils.AddCloudStorageFlags(ammPoolSnapshotsCmd.Flags())
+	ammPoolSnapshotsCmd.Flags().Uint32("snapshot-frequency", 64, "The number of ledgers between snapshots of the liquidity pools. The default snapshots the pools at every checkpoint ledger.")
+}
@@ -0,0 +1,21 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsSnapshotLedger(t *testing.T) {
+	snapshots := []uint32{}
+	for seq := uint32(60); seq <= 200; seq++ {
+		if isSnapshotLedger(seq, 64) {
+			snapshots = append(snapshots, seq)
+		}
+	}
+	assert.Equal(t, []uint32{63, 127, 191}, snapshots)
+
+	assert.True(t, isSnapshotLedger(99, 10))
+	assert.False(t, isSnapshotLedger(100, 10))
+	assert.True(t, isSnapshotLedger(100, 1))
+}
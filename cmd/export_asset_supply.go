@@ -0,0 +1,175 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stellar/stellar-etl/v2/internal/transform"
+	"github.com/stellar/stellar-etl/v2/internal/utils"
+)
+
+// supplyState is the circulating supply of every asset after the last exported ledger, by contract id, which lets a
+// later export of the following ledgers continue the running totals
+type supplyState struct {
+	LastLedger uint32              `json:"last_ledger"`
+	Supplies   map[string]*big.Int `json:"supplies"`
+}
+
+var assetSupplyCmd = &cobra.Command{
+	Use:   "export_asset_supply",
+	Short: "Exports the changes in the circulating supply of assets.",
+	Long: `Exports the change in circulating supply of every asset within every ledger of the specified range to an output
+file. Mints, burns and clawbacks, including payments from and to the issuer of a Stellar asset and Stellar Asset
+Contract mint and burn events, are aggregated into a supply delta and a running circulating supply per asset.
+
+The running supply starts at zero at ledger 2, the first ledger after genesis. Exports that start at any other ledger
+continue the supply that supply-state holds after an earlier export.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmdLogger.SetLevel(logrus.InfoLevel)
+		cmdArgs := utils.MustFlags(cmd.Flags(), cmdLogger)
+		commonArgs := utils.MustCommonFlags(cmd.Flags(), cmdLogger)
+		env := utils.GetEnvironmentDetails(commonArgs)
+
+		statePath, err := cmd.Flags().GetString("supply-state")
+		if err != nil {
+			cmdLogger.Fatal("could not get supply-state path: ", err)
+		}
+
+		if commonArgs.Descending {
+			cmdLogger.Fatal("descending order is not supported when exporting asset supply, because the running supply is summed in ledger order")
+		}
+
+		if commonArgs.EveryNthLedger > 1 || commonArgs.SampleRate < 1 {
+			cmdLogger.Fatal("ledger sampling is not supported when exporting asset supply, because every ledger is needed for the running supply")
+		}
+
+		engine := newExportEngine(cmdArgs, env, new(transform.AssetSupplyOutput), new(transform.AssetSupplyOutputParquet))
+
+		state, err := loadSupplyState(statePath, engine.cmdArgs.StartNum)
+		if err != nil {
+			cmdLogger.Fatal(err)
+		}
+		if state.LastLedger != 0 {
+			cmdLogger.Infof("Continuing the supply of %d assets after ledger %d", len(state.Supplies), state.LastLedger)
+		}
+
+		engine.run(func(lcm xdr.LedgerCloseMeta) error {
+			// Ledgers can change the supply of any number of assets, so the limit is applied to the exported supply changes instead of the input.
+			// A ledger that can't be transformed stops the export, because the running supply would be wrong for every later ledger
+			engine.attempt()
+			ledgerSeq := utils.GetLedgerSequence(lcm)
+			transfers, err := transform.TransformTokenTransfer(lcm, env.NetworkPassphrase)
+			if err != nil {
+				return fmt.Errorf("could not transform token transfers of ledger %d: %v", ledgerSeq, err)
+			}
+
+			transformed, err := transform.TransformAssetSupply(transfers, state.Supplies)
+			if err != nil {
+				return fmt.Errorf("could not transform asset supply of ledger %d: %v", ledgerSeq, err)
+			}
+
+			for _, supply := range transformed {
+				engine.export(supply)
+			}
+
+			// The limit can cut the supply changes of a ledger short, so the state is only saved for complete ledgers and
+			// that ledger is exported again by the next export
+			state.LastLedger = ledgerSeq
+			if statePath != "" && !engine.limitReached() {
+				if err := writeSupplyState(statePath, state); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		})
+	},
+}
+
+// supplyStartLedger is the first ledger after genesis, before which no asset has a circulating supply
+const supplyStartLedger = 2
+
+// loadSupplyState returns the supply before startLedger. The supply is only known to be zero before the first ledger
+// after genesis, so an export that starts at any other ledger has to continue the supply saved in statePath by the
+// export of the ledgers before it.
+func loadSupplyState(statePath string, startLedger uint32) (supplyState, error) {
+	state := supplyState{Supplies: map[string]*big.Int{}}
+	found := false
+	if statePath != "" {
+		var err error
+		state, found, err = readSupplyState(statePath)
+		if err != nil {
+			return supplyState{}, err
+		}
+	}
+
+	if !found {
+		if startLedger != supplyStartLedger {
+			return supplyState{}, fmt.Errorf("the circulating supply is only known to be zero at ledger %d, so an export that starts at ledger %d needs the supply-state of an export that ended at ledger %d", supplyStartLedger, startLedger, startLedger-1)
+		}
+		return supplyState{Supplies: map[string]*big.Int{}}, nil
+	}
+
+	if state.LastLedger+1 != startLedger {
+		return supplyState{}, fmt.Errorf("supply-state %s ends at ledger %d, so the export must start at ledger %d instead of %d", statePath, state.LastLedger, state.LastLedger+1, startLedger)
+	}
+	return state, nil
+}
+
+// readSupplyState returns the supply saved by an earlier export. found is false if the file doesn't exist.
+func readSupplyState(path string) (state supplyState, found bool, err error) {
+	contents, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return supplyState{}, false, nil
+	}
+	if err != nil {
+		return supplyState{}, false, fmt.Errorf("could not read supply state %s: %v", path, err)
+	}
+
+	if err := json.Unmarshal(contents, &state); err != nil {
+		return supplyState{}, false, fmt.Errorf("could not parse supply state %s: %v", path, err)
+	}
+	if state.Supplies == nil {
+		state.Supplies = map[string]*big.Int{}
+	}
+
+	return state, true, nil
+}
+
+// writeSupplyState saves the supply after the last exported ledger. Like checkpoints, the file is replaced by a
+// rename so that it is never partially written.
+func writeSupplyState(path string, state supplyState) error {
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return fmt.Errorf("could not create supply state directory: %v", err)
+	}
+
+	marshalled, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("could not json encode supply state: %v", err)
+	}
+
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, marshalled, 0644); err != nil {
+		return fmt.Errorf("could not write supply state %s: %v", path, err)
+	}
+
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("could not write supply state %s: %v", path, err)
+	}
+
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(assetSupplyCmd)
+	utils.AddCommonFlags(assetSupplyCmd.Flags())
+	utils.AddArchiveFlags("asset_supply", assetSupplyCmd.Flags())
+	utils.AddCloudStorageFlags(assetSupplyCmd.Flags())
+	assetSupplyCmd.Flags().String("supply-state", "", "If set, the running supply is read from this file before the export and saved to it after every ledger, so that the next export continues it.")
+}
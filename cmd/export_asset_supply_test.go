@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSupplyState(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "asset_supply.json")
+
+	_, found, err := readSupplyState(path)
+	assert.NoError(t, err)
+	assert.False(t, found)
+
+	supply, _ := new(big.Int).SetString("170141183460469231731687303715884105727", 10)
+	assert.NoError(t, writeSupplyState(path, supplyState{LastLedger: 52000000, Supplies: map[string]*big.Int{"contract": supply}}))
+
+	state, found, err := readSupplyState(path)
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, uint32(52000000), state.LastLedger)
+	assert.Equal(t, supply, state.Supplies["contract"])
+}
+
+func TestLoadSupplyState(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "asset_supply.json")
+
+	state, err := loadSupplyState("", 2)
+	assert.NoError(t, err)
+	assert.Equal(t, supplyState{Supplies: map[string]*big.Int{}}, state)
+
+	state, err = loadSupplyState(path, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, supplyState{Supplies: map[string]*big.Int{}}, state)
+
+	_, err = loadSupplyState("", 100)
+	assert.EqualError(t, err, "the circulating supply is only known to be zero at ledger 2, so an export that starts at ledger 100 needs the supply-state of an export that ended at ledger 99")
+
+	_, err = loadSupplyState(path, 100)
+	assert.Error(t, err)
+
+	assert.NoError(t, writeSupplyState(path, supplyState{LastLedger: 99, Supplies: map[string]*big.Int{"contract": big.NewInt(5)}}))
+	state, err = loadSupplyState(path, 100)
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(5), state.Supplies["contract"])
+
+	_, err = loadSupplyState(path, 101)
+	assert.EqualError(t, err, "supply-state "+path+" ends at ledger 99, so the export must start at ledger 100 instead of 101")
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
+	"github.com/stellar/go-stellar-sdk/xdr"
 	"github.com/stellar/stellar-etl/v2/internal/input"
 	"github.com/stellar/stellar-etl/v2/internal/transform"
 	"github.com/stellar/stellar-etl/v2/internal/utils"
@@ -16,71 +17,69 @@ var assetsCmd = &cobra.Command{
 	Long:  `Exports the assets that are created from payment operations over a specified ledger range`,
 	Run: func(cmd *cobra.Command, args []string) {
 		cmdLogger.SetLevel(logrus.InfoLevel)
+		cmdArgs := utils.MustFlags(cmd.Flags(), cmdLogger)
 		commonArgs := utils.MustCommonFlags(cmd.Flags(), cmdLogger)
-		cmdLogger.StrictExport = commonArgs.StrictExport
-		startNum, path, parquetPath, limit := utils.MustArchiveFlags(cmd.Flags(), cmdLogger)
-		cloudStorageBucket, cloudCredentials, cloudProvider := utils.MustCloudStorageFlags(cmd.Flags(), cmdLogger)
 		env := utils.GetEnvironmentDetails(commonArgs)
 
-		outFile := MustOutFile(path)
-
-		var paymentOps []input.AssetTransformInput
-		var err error
-
-		if commonArgs.UseCaptiveCore {
-			paymentOps, err = input.GetPaymentOperationsHistoryArchive(startNum, commonArgs.EndNum, limit, env, commonArgs.UseCaptiveCore)
-		} else {
-			paymentOps, err = input.GetPaymentOperations(startNum, commonArgs.EndNum, limit, env, commonArgs.UseCaptiveCore)
-		}
+		noAlign, err := cmd.Flags().GetBool("no-align")
 		if err != nil {
-			cmdLogger.Fatal("could not read asset: ", err)
+			cmdLogger.Fatal("could not get no-align: ", err)
 		}
 
-		// With seenIDs, the code doesn't export duplicate assets within a single export. Note that across exports, assets may be duplicated
-		seenIDs := map[int64]bool{}
-		numFailures := 0
-		totalNumBytes := 0
-		var transformedAssets []transform.SchemaParquet
-		for _, transformInput := range paymentOps {
-			transformed, err := transform.TransformAsset(transformInput.Operation, transformInput.OperationIndex, transformInput.TransactionIndex, transformInput.LedgerSeqNum, transformInput.LedgerCloseMeta)
-			if err != nil {
-				txIndex := transformInput.TransactionIndex
-				cmdLogger.LogError(fmt.Errorf("could not extract asset from operation %d in transaction %d in ledger %d: ", transformInput.OperationIndex, txIndex, transformInput.LedgerSeqNum))
-				numFailures += 1
-				continue
-			}
-
-			// if we have seen the asset already, do not export it
-			if _, exists := seenIDs[transformed.AssetID]; exists {
-				continue
+		if cmdArgs.UseCaptiveCore {
+			if err := alignArchiveRange(&cmdArgs, noAlign); err != nil {
+				cmdLogger.Fatal(err)
 			}
+		}
 
-			seenIDs[transformed.AssetID] = true
-			numBytes, err := ExportEntry(transformed, outFile, commonArgs.Extra)
+		// With seenIDs, the code doesn't export duplicate assets within a single export. Note that across exports, assets may be duplicated
+		// Since duplicates are skipped, the limit is applied to the exported assets instead of the payment operations,
+		// and reading stops once it is reached
+		engine := newExportEngine(cmdArgs, env, new(transform.AssetOutput), new(transform.AssetOutputParquet))
+		seenIDs := map[int64]bool{}
+		if cmdArgs.UseCaptiveCore {
+			err = input.ReadPaymentOperationsHistoryArchive(cmdArgs.StartNum, cmdArgs.EndNum, env, func(paymentOps []input.AssetTransformInput) bool {
+				exportAssets(engine, paymentOps, seenIDs)
+				return !engine.limitReached()
+			})
 			if err != nil {
-				cmdLogger.LogError(err)
-				numFailures += 1
-				continue
-			}
-			totalNumBytes += numBytes
-
-			if commonArgs.WriteParquet {
-				transformedAssets = append(transformedAssets, transformed)
+				cmdLogger.Fatal("could not read asset: ", err)
 			}
+			engine.finish()
+			return
 		}
 
-		outFile.Close()
-		cmdLogger.Infof("%d bytes written to %s", totalNumBytes, outFile.Name())
+		engine.run(func(lcm xdr.LedgerCloseMeta) error {
+			exportAssets(engine, input.GetLedgerPaymentOperations(lcm), seenIDs)
+			return nil
+		})
+	},
+}
 
-		PrintTransformStats(len(paymentOps), numFailures)
+// exportAssets exports the assets of the payment operations of a ledger that weren't exported yet
+func exportAssets(engine *exportEngine, paymentOps []input.AssetTransformInput, seenIDs map[int64]bool) {
+	for _, transformInput := range paymentOps {
+		if engine.limitReached() {
+			break
+		}
 
-		MaybeUpload(cloudCredentials, cloudStorageBucket, cloudProvider, path)
+		engine.attempt()
+		engine.beginLedger(uint32(transformInput.LedgerSeqNum))
+		transformed, err := transform.TransformAsset(transformInput.Operation, transformInput.OperationIndex, transformInput.TransactionIndex, transformInput.LedgerSeqNum, transformInput.LedgerCloseMeta)
+		if err != nil {
+			txIndex := transformInput.TransactionIndex
+			engine.fail(fmt.Errorf("could not extract asset from operation %d in transaction %d in ledger %d: %v", transformInput.OperationIndex, txIndex, transformInput.LedgerSeqNum, err))
+			continue
+		}
 
-		if commonArgs.WriteParquet {
-			WriteParquet(transformedAssets, parquetPath, new(transform.AssetOutputParquet))
-			MaybeUpload(cloudCredentials, cloudStorageBucket, cloudProvider, parquetPath)
+		// if we have seen the asset already, do not export it
+		if _, exists := seenIDs[transformed.AssetID]; exists {
+			continue
 		}
-	},
+
+		seenIDs[transformed.AssetID] = true
+		engine.export(transformed)
+	}
 }
 
 func init() {
@@ -88,6 +87,7 @@ func init() {
 	utils.AddCommonFlags(assetsCmd.Flags())
 	utils.AddArchiveFlags("assets", assetsCmd.Flags())
 	utils.AddCloudStorageFlags(assetsCmd.Flags())
+	assetsCmd.Flags().Bool("no-align", false, "If set, a range that is not aligned to checkpoints stops the export when reading from the history archives, instead of being widened to the checkpoints that contain it.")
 	assetsCmd.MarkFlagRequired("end-ledger")
 
 	/*
@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stellar/stellar-etl/v2/internal/input"
+	"github.com/stellar/stellar-etl/v2/internal/transform"
+	"github.com/stellar/stellar-etl/v2/internal/utils"
+)
+
+var cbClaimsCmd = &cobra.Command{
+	Use:   "export_cb_claims",
+	Short: "Exports the claims and clawbacks of claimable balances over a specified range.",
+	Long: `Exports every claim and clawback of a claimable balance within the specified range to an output file, paired
+with the creation of the balance and the time it took to be claimed. Balances created before the range have no
+creation time.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmdLogger.SetLevel(logrus.InfoLevel)
+		cmdArgs := utils.MustFlags(cmd.Flags(), cmdLogger)
+		commonArgs := utils.MustCommonFlags(cmd.Flags(), cmdLogger)
+		env := utils.GetEnvironmentDetails(commonArgs)
+
+		// The creations of the balances that weren't claimed yet, keyed by balance id
+		creations := map[string]transform.ClaimableBalanceCreation{}
+
+		engine := newExportEngine(cmdArgs, env, new(transform.ClaimableBalanceClaimOutput), new(transform.ClaimableBalanceClaimOutputParquet))
+		engine.run(func(lcm xdr.LedgerCloseMeta) error {
+			operations, err := input.GetLedgerOperations(lcm, env)
+			if err != nil {
+				return err
+			}
+
+			for _, transformInput := range operations {
+				if engine.limitReached() {
+					break
+				}
+
+				txIndex := transformInput.Transaction.Index
+				creation, created, err := transform.TransformClaimableBalanceCreation(transformInput.Operation, transformInput.OperationIndex, transformInput.Transaction, transformInput.LedgerSeqNum, transformInput.LedgerCloseMeta)
+				if err != nil {
+					engine.attempt()
+					engine.fail(fmt.Errorf("could not transform claimable balance creation of operation %d in transaction %d in ledger %d: %v", transformInput.OperationIndex, txIndex, transformInput.LedgerSeqNum, err))
+					continue
+				}
+				if created {
+					creations[creation.BalanceID] = creation
+					continue
+				}
+
+				claim, claimed, err := transform.TransformClaimableBalanceClaim(transformInput.Operation, transformInput.OperationIndex, transformInput.Transaction, transformInput.LedgerSeqNum, transformInput.LedgerCloseMeta, creations)
+				if err != nil {
+					engine.attempt()
+					engine.fail(fmt.Errorf("could not transform claimable balance claim of operation %d in transaction %d in ledger %d: %v", transformInput.OperationIndex, txIndex, transformInput.LedgerSeqNum, err))
+					continue
+				}
+				if !claimed {
+					continue
+				}
+
+				engine.attempt()
+				delete(creations, claim.BalanceID)
+				engine.export(claim)
+			}
+
+			return nil
+		})
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(cbClaimsCmd)
+	utils.AddCommonFlags(cbClaimsCmd.Flags())
+	utils.AddArchiveFlags("cb_claims", cbClaimsCmd.Flags())
+	utils.AddCloudStorageFlags(cbClaimsCmd.Flags())
+	cbClaimsCmd.MarkFlagRequired("end-ledger")
+}
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stellar/stellar-etl/v2/internal/transform"
+	"github.com/stellar/stellar-etl/v2/internal/utils"
+)
+
+var configUpgradesCmd = &cobra.Command{
+	Use:   "export_config_upgrades",
+	Short: "Exports the soroban config settings changed by config upgrades over a specified range.",
+	Long: `Exports the soroban config settings changed by config upgrades within the specified range to an output file.
+Each changed setting is exported as a separate JSON object containing both its previous and upgraded values.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmdLogger.SetLevel(logrus.InfoLevel)
+		cmdArgs := utils.MustFlags(cmd.Flags(), cmdLogger)
+
+		// TODO: https://stellarorg.atlassian.net/browse/HUBBLE-386 GetEnvironmentDetails should be refactored
+		commonArgs := utils.MustCommonFlags(cmd.Flags(), cmdLogger)
+		env := utils.GetEnvironmentDetails(commonArgs)
+
+		engine := newExportEngine(cmdArgs, env, new(transform.ConfigUpgradeOutput), new(transform.ConfigUpgradeOutputParquet))
+		engine.run(func(lcm xdr.LedgerCloseMeta) error {
+			// A single config upgrade can change several settings, so the limit is applied to the exported settings instead of the input
+			engine.attempt()
+			transformed, err := transform.TransformConfigUpgrades(lcm)
+			if err != nil {
+				engine.fail(fmt.Errorf("could not transform config upgrades in ledger %d: %v", utils.GetLedgerSequence(lcm), err))
+				return nil
+			}
+
+			for _, configUpgrade := range transformed {
+				engine.export(configUpgrade)
+			}
+
+			return nil
+		})
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(configUpgradesCmd)
+	utils.AddCommonFlags(configUpgradesCmd.Flags())
+	utils.AddArchiveFlags("config_upgrades", configUpgradesCmd.Flags())
+	utils.AddCloudStorageFlags(configUpgradesCmd.Flags())
+
+	configUpgradesCmd.MarkFlagRequired("start-ledger")
+	configUpgradesCmd.MarkFlagRequired("end-ledger")
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
+	"github.com/stellar/go-stellar-sdk/xdr"
 	"github.com/stellar/stellar-etl/v2/internal/input"
 	"github.com/stellar/stellar-etl/v2/internal/transform"
 	"github.com/stellar/stellar-etl/v2/internal/utils"
@@ -13,60 +14,91 @@ import (
 var effectsCmd = &cobra.Command{
 	Use:   "export_effects",
 	Short: "Exports the effects data over a specified range",
-	Long:  "Exports the effects data over a specified range to an output file.",
+	Long:  "Exports the effects data over a specified range to an output file. Without an end ledger, effects are exported continuously as ledgers close.",
 	Run: func(cmd *cobra.Command, args []string) {
 		cmdLogger.SetLevel(logrus.InfoLevel)
+		cmdArgs := utils.MustFlags(cmd.Flags(), cmdLogger)
 		commonArgs := utils.MustCommonFlags(cmd.Flags(), cmdLogger)
-		cmdLogger.StrictExport = commonArgs.StrictExport
-		startNum, path, parquetPath, limit := utils.MustArchiveFlags(cmd.Flags(), cmdLogger)
-		cloudStorageBucket, cloudCredentials, cloudProvider := utils.MustCloudStorageFlags(cmd.Flags(), cmdLogger)
 		env := utils.GetEnvironmentDetails(commonArgs)
 
-		transactions, err := input.GetTransactions(startNum, commonArgs.EndNum, limit, env, commonArgs.UseCaptiveCore)
+		flattenDetails, err := cmd.Flags().GetBool("flatten-details")
 		if err != nil {
-			cmdLogger.Fatalf("could not read transactions in [%d, %d] (limit=%d): %v", startNum, commonArgs.EndNum, limit, err)
+			cmdLogger.Fatal("could not get flatten-details flag: ", err)
 		}
 
-		outFile := MustOutFile(path)
-		numFailures := 0
-		totalNumBytes := 0
-		var transformedEffects []transform.SchemaParquet
-		for _, transformInput := range transactions {
-			LedgerSeq := uint32(transformInput.LedgerHistory.Header.LedgerSeq)
-			effects, err := transform.TransformEffect(transformInput.Transaction, LedgerSeq, transformInput.LedgerCloseMeta, env.NetworkPassphrase)
+		successfulOnly, err := cmd.Flags().GetBool("successful-only")
+		if err != nil {
+			cmdLogger.Fatal("could not get successful-only flag: ", err)
+		}
+
+		var outputSchema, parquetSchema interface{} = new(transform.EffectOutput), new(transform.EffectOutputParquet)
+		if flattenDetails {
+			outputSchema, parquetSchema = new(transform.FlattenedEffectOutput), new(transform.FlattenedEffectOutputParquet)
+		}
+
+		kindFilter := mustTransactionKindFilter(cmd.Flags())
+		accountFilter := mustAccountFilter(cmd.Flags())
+
+		engine := newExportEngine(cmdArgs, env, outputSchema, parquetSchema)
+		engine.run(func(lcm xdr.LedgerCloseMeta) error {
+			skip, err := kindFilter.skipLedger(lcm)
+			if err != nil {
+				return err
+			}
+			if skip {
+				return nil
+			}
+
+			transactions, err := input.GetLedgerTransactions(lcm, env)
 			if err != nil {
-				txIndex := transformInput.Transaction.Index
-				cmdLogger.LogError(fmt.Errorf("could not transform transaction %d in ledger %d: %v", txIndex, LedgerSeq, err))
-				numFailures += 1
-				continue
+				return err
 			}
 
-			for _, transformed := range effects {
-				numBytes, err := ExportEntry(transformed, outFile, commonArgs.Extra)
-				if err != nil {
-					cmdLogger.LogError(err)
-					numFailures += 1
+			// Transactions can produce any number of effects, so the limit is applied to the exported effects instead of the input
+			for _, transformInput := range transactions {
+				if engine.limitReached() {
+					break
+				}
+
+				if kindFilter.skipTransaction(transformInput.Transaction) {
 					continue
 				}
-				totalNumBytes += numBytes
 
-				if commonArgs.WriteParquet {
-					transformedEffects = append(transformedEffects, transformed)
+				if successfulOnly && !transformInput.Transaction.Result.Successful() {
+					continue
 				}
-			}
-		}
 
-		outFile.Close()
-		cmdLogger.Info("Number of bytes written: ", totalNumBytes)
+				skipAccounts, err := skipFilteredAccounts(accountFilter, transformInput.Transaction)
+				if err != nil {
+					return err
+				}
+				if skipAccounts {
+					continue
+				}
 
-		PrintTransformStats(len(transactions), numFailures)
+				engine.attempt()
+				LedgerSeq := uint32(transformInput.LedgerHistory.Header.LedgerSeq)
+				timed := engine.timeTransform(transactionOperationType(transformInput.Transaction))
+				effects, err := transform.TransformEffect(transformInput.Transaction, LedgerSeq, transformInput.LedgerCloseMeta, env.NetworkPassphrase)
+				timed()
+				if err != nil {
+					txIndex := transformInput.Transaction.Index
+					engine.fail(fmt.Errorf("could not transform transaction %d in ledger %d: %v", txIndex, LedgerSeq, err))
+					continue
+				}
 
-		MaybeUpload(cloudCredentials, cloudStorageBucket, cloudProvider, path)
+				for _, transformed := range effects {
+					if flattenDetails {
+						engine.export(transform.FlattenedEffectOutput{EffectOutput: transformed})
+						continue
+					}
 
-		if commonArgs.WriteParquet {
-			WriteParquet(transformedEffects, parquetPath, new(transform.EffectOutputParquet))
-			MaybeUpload(cloudCredentials, cloudStorageBucket, cloudProvider, parquetPath)
-		}
+					engine.export(transformed)
+				}
+			}
+
+			return nil
+		})
 	},
 }
 
@@ -75,12 +107,15 @@ func init() {
 	utils.AddCommonFlags(effectsCmd.Flags())
 	utils.AddArchiveFlags("effects", effectsCmd.Flags())
 	utils.AddCloudStorageFlags(effectsCmd.Flags())
-	effectsCmd.MarkFlagRequired("end-ledger")
+	addAccountFilterFlags(effectsCmd.Flags())
+	addTransactionKindFlags(effectsCmd.Flags())
+	effectsCmd.Flags().Bool("flatten-details", false, "If set, the most common effect details are written to typed columns in the parquet output.")
+	effectsCmd.Flags().Bool("successful-only", false, "If set, records of failed transactions are not exported.")
 
 	/*
 		Current flags:
 			start-ledger: the ledger sequence number for the beginning of the export period
-			end-ledger: the ledger sequence number for the end of the export range (required)
+			end-ledger: the ledger sequence number for the end of the export range; if omitted, effects are exported continuously as ledgers close
 
 			limit: maximum number of effects to export; default to 6,000,000
 				each transaction can have up to 100 effects
@@ -0,0 +1,854 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"syscall"
+	"time"
+
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stellar/stellar-etl/v2/internal/input"
+	"github.com/stellar/stellar-etl/v2/internal/transform"
+	"github.com/stellar/stellar-etl/v2/internal/utils"
+)
+
+// recordSink receives the JSON encoded records of an export instead of the output file
+type recordSink interface {
+	// add queues a record, and may send the queued records
+	add(record []byte) error
+	// flush sends the queued records
+	flush() error
+	close() error
+	// describe summarizes what was sent, for the export logs
+	describe() string
+}
+
+// asyncSink is a record sink that sends its records in the background, so a record is only delivered once the sink
+// acknowledges it. Closing the sink waits for the records in flight.
+type asyncSink interface {
+	recordSink
+	// added returns the number of records added to the sink
+	added() int
+	// acked returns the number of records added before the first one that is not acknowledged yet, and the error that
+	// stopped the sink if records could not be delivered
+	acked() (int, error)
+}
+
+// pendingCheckpoint is a streamed ledger whose checkpoint is saved once the async sinks acknowledge its records, with
+// the number of records added to every async sink by the end of the ledger
+type pendingCheckpoint struct {
+	ledgerSeq uint32
+	numAdded  []int
+}
+
+// exportEngine writes the records produced by an export command to its output file, or sends them to a record sink. It
+// gives every command the same limit, failure, parquet and upload handling, and stops reading cleanly when the process is interrupted.
+type exportEngine struct {
+	cmdArgs       utils.FlagValues
+	env           utils.EnvironmentDetails
+	parquetSchema interface{}
+	outFile       *os.File
+	sink          recordSink
+	fanouts       []*fanoutSink
+	asyncSinks    []asyncSink
+	sqlite        *sqliteTable
+	encryptor     *fieldEncryptor
+	sizeGuard     *recordSizeGuard
+	fieldMapping  fieldMapping
+	index         *outputIndex
+	notifier      *uploadNotifier
+	bigQueryTable *bigQueryTable
+
+	startedAt      time.Time
+	coreVersion    string
+	bigQueryLoad   *bigQueryLoadStats
+	interrupted    bool
+	ledgerSeq      uint32
+	numAttempts    int
+	numFailures    int
+	failureClasses map[string]int
+	timings        map[string]*transformTiming
+	numExported    int
+	totalNumBytes  int
+	parquetRecords []transform.SchemaParquet
+	sortedRecords  []sortedRecord
+	bufferLedger   bool
+	ledgerRecords  []sortedRecord
+	unhandledArms  map[string]int
+
+	pendingCheckpoints []pendingCheckpoint
+}
+
+// ledgerReadAhead is the number of ledgers that are read while the previous ones are exported, which bounds the memory
+// used by ledgers that are waiting to be exported
+const ledgerReadAhead = 4
+
+// sortedRecord is a record that is held back until the end of the export with sort, along with the ledger it was
+// exported from
+type sortedRecord struct {
+	ledgerSeq  uint32
+	record     interface{}
+	marshalled []byte
+	output     []byte
+}
+
+// newExportEngine creates the output file for an export of the records of outputSchema. Parquet and ORC files are only
+// written if a parquet schema is provided.
+func newExportEngine(cmdArgs utils.FlagValues, env utils.EnvironmentDetails, outputSchema, parquetSchema interface{}) *exportEngine {
+	cmdLogger.StrictExport = cmdArgs.StrictExport
+	transform.SetScValLimits(cmdArgs.ScValMaxDepth, cmdArgs.ScValMaxSize)
+
+	var encryptor *fieldEncryptor
+	if len(cmdArgs.EncryptFields) > 0 {
+		var err error
+		encryptor, err = newFieldEncryptor(cmdArgs.EncryptFields, cmdArgs.KMSKey, outputSchema)
+		if err != nil {
+			cmdLogger.Fatal("could not set up field encryption: ", err)
+		}
+	}
+
+	engine := &exportEngine{
+		cmdArgs:       cmdArgs,
+		env:           env,
+		parquetSchema: parquetSchema,
+		encryptor:     encryptor,
+		startedAt:     time.Now(),
+	}
+
+	if cmdArgs.BigQueryLoadTable != "" {
+		if !cmdArgs.WriteParquet || parquetSchema == nil {
+			cmdLogger.Fatal("bigquery-load-table loads the parquet output, so it needs write-parquet and an export with a parquet schema")
+		}
+		if cmdArgs.Provider != "gcp" {
+			cmdLogger.Fatal("bigquery-load-table loads the parquet output from GCS, so it needs the gcp cloud-provider")
+		}
+		table, err := parseBigQueryTable(cmdArgs.BigQueryLoadTable)
+		if err != nil {
+			cmdLogger.Fatal(err)
+		}
+		if _, err := newBigQueryChunk(env.Network, cmdArgs.Dataset, parquetSchema, cmdArgs.StartNum, cmdArgs.EndNum); err != nil {
+			cmdLogger.Fatal(err)
+		}
+		engine.bigQueryTable = &table
+	}
+
+	if env.CommonFlagValues.Sort && cmdArgs.EndNum == 0 {
+		cmdLogger.Fatal("sort needs an end-ledger, because the records are only written once the whole range is exported")
+	}
+
+	resumed := false
+	if cmdArgs.CheckpointPath != "" {
+		if cmdArgs.EndNum != 0 {
+			cmdLogger.Fatal("checkpoint-file is only used when streaming without an end-ledger")
+		}
+
+		lastLedger, found, err := readCheckpoint(cmdArgs.CheckpointPath)
+		if err != nil {
+			cmdLogger.Fatal(err)
+		}
+		if found {
+			// Every nth ledger is counted from the start of the range, so the export resumes at the next sampled ledger
+			engine.cmdArgs.StartNum = lastLedger + max(1, env.CommonFlagValues.EveryNthLedger)
+			resumed = true
+			cmdLogger.Infof("Resuming the export at ledger %d after checkpoint %s", engine.cmdArgs.StartNum, cmdArgs.CheckpointPath)
+		}
+	}
+
+	var err error
+	switch cmdArgs.Sink {
+	case "", "file":
+		if resumed {
+			engine.outFile = mustAppendOutFile(cmdArgs.Path)
+		} else {
+			engine.outFile = MustOutFile(cmdArgs.Path)
+		}
+	case "webhook":
+		engine.sink, err = newWebhookSink(cmdArgs.WebhookURL, cmdArgs.WebhookSecret, cmdArgs.WebhookBatchSize)
+	case "redis-stream":
+		engine.sink, err = newRedisStreamSink(cmdArgs.RedisURL, cmdArgs.RedisStream, cmdArgs.RedisStreamMaxLen)
+	case "elasticsearch":
+		engine.sink, err = newElasticsearchSink(cmdArgs.ElasticsearchURL, cmdArgs.ElasticsearchAPIKey, cmdArgs.ElasticsearchIndex, cmdArgs.ElasticsearchBatchSize)
+	case "pubsub":
+		engine.sink, err = newPubSubSink(cmdArgs.PubSubTopic, cmdArgs.PubSubEndpoint, cmdArgs.MessageKeyField, cmdArgs.PublishBatchSize, cmdArgs.PublishMaxInFlight)
+	case "kafka":
+		engine.sink, err = newKafkaSink(cmdArgs.KafkaRESTURL, cmdArgs.KafkaTopic, cmdArgs.MessageKeyField, cmdArgs.PublishBatchSize, cmdArgs.PublishMaxInFlight)
+	default:
+		cmdLogger.Fatalf("unsupported sink %s; expected file, webhook, redis-stream, elasticsearch, pubsub or kafka", cmdArgs.Sink)
+	}
+	if err != nil {
+		cmdLogger.Fatalf("could not set up %s sink: %v", cmdArgs.Sink, err)
+	}
+
+	sinkConfigs, err := loadSinkConfigs()
+	if err != nil {
+		cmdLogger.Fatal(err)
+	}
+	engine.fanouts, err = newFanoutSinks(sinkConfigs, cmdArgs)
+	if err != nil {
+		cmdLogger.Fatal(err)
+	}
+
+	if async, ok := engine.sink.(asyncSink); ok {
+		engine.asyncSinks = append(engine.asyncSinks, async)
+	}
+	for _, fanout := range engine.fanouts {
+		if async, ok := fanout.sink.(asyncSink); ok {
+			engine.asyncSinks = append(engine.asyncSinks, async)
+		}
+	}
+
+	if cmdArgs.FieldMappingPath != "" {
+		engine.fieldMapping, err = loadFieldMapping(cmdArgs.FieldMappingPath, cmdArgs.FieldMappingDest, cmdArgs.Dataset)
+		if err != nil {
+			cmdLogger.Fatal("could not load field mapping: ", err)
+		}
+	}
+
+	if cmdArgs.MaxRecordBytes != 0 {
+		engine.sizeGuard, err = newRecordSizeGuard(cmdArgs.MaxRecordBytes, cmdArgs.OversizedRecords, cmdArgs.OversizedPath)
+		if err != nil {
+			cmdLogger.Fatal("could not set up the record size guard: ", err)
+		}
+	}
+
+	if cmdArgs.IndexFiles {
+		engine.index = newOutputIndex()
+	}
+
+	engine.notifier, err = newUploadNotifier(cmdArgs.Upload, cmdArgs.Provider, cmdArgs.Bucket, cmdArgs.PubSubEndpoint, cmdArgs.KafkaRESTURL)
+	if err != nil {
+		cmdLogger.Fatal("could not set up upload notifications: ", err)
+	}
+
+	if cmdArgs.SQLitePath != "" {
+		engine.sqlite, err = newSQLiteTable(cmdArgs.SQLitePath, cmdArgs.SQLiteTable)
+		if err != nil {
+			cmdLogger.Fatal("could not set up sqlite output: ", err)
+		}
+	}
+
+	return engine
+}
+
+// run calls processLedger on every ledger in the export range, then finishes the export. Reading stops once the limit
+// is reached or the process receives an interrupt, and the records exported so far are still written and uploaded.
+// Without an end ledger, ledgers are streamed as they close: the progress of every ledger is logged and saved to the
+// checkpoint file, and if the ledgers can't be read even after retrying, the streamed records are finished and
+// uploaded before the export stops.
+func (e *exportEngine) run(processLedger func(xdr.LedgerCloseMeta) error) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if e.cmdArgs.UseCaptiveCore && e.env.CommonFlagValues.LedgerFixture == "" {
+		e.detectCoreVersion()
+	}
+
+	unbounded := e.cmdArgs.EndNum == 0
+	err := e.exportLedgers(ctx, processLedger)
+	if err != nil {
+		if !unbounded {
+			cmdLogger.Fatal("could not read ledgers: ", err)
+		}
+
+		cmdLogger.LogError(fmt.Errorf("could not read ledgers, finishing the export: %v", err))
+		e.finish()
+		cmdLogger.Fatal("stopped streaming ledgers: ", err)
+	}
+
+	if ctx.Err() != nil && !e.interrupted {
+		cmdLogger.Info("Interrupted while waiting for the next ledger, finishing the export")
+		e.interrupted = true
+	}
+
+	e.finish()
+}
+
+// exportLedgers reads the ledgers of the export range ahead of the ledger being exported, at most ledgerReadAhead of
+// them, and exports them until the range ends, the limit is reached or the context is cancelled
+func (e *exportEngine) exportLedgers(ctx context.Context, processLedger func(xdr.LedgerCloseMeta) error) error {
+	readCtx, stopReading := context.WithCancel(ctx)
+	defer stopReading()
+
+	unbounded := e.cmdArgs.EndNum == 0
+	ledgers, readErrs := input.StreamLedgerCloseMetas(readCtx, e.cmdArgs.StartNum, e.cmdArgs.EndNum, e.env, e.cmdArgs.UseCaptiveCore, ledgerReadAhead)
+	var err error
+	for lcm := range ledgers {
+		if ctx.Err() != nil {
+			cmdLogger.Infof("Interrupted before ledger %d, finishing the export", utils.GetLedgerSequence(lcm))
+			e.interrupted = true
+			break
+		}
+
+		if e.limitReached() {
+			break
+		}
+
+		ledgerSeq := utils.GetLedgerSequence(lcm)
+		exportedBefore := e.numExported
+		if err = e.exportLedger(processLedger, lcm); err != nil {
+			break
+		}
+
+		// Send the records of every ledger right away, so sinks receive low volume streams without delay
+		e.flushSink()
+
+		if unbounded {
+			e.ledgerStreamed(ledgerSeq, e.numExported-exportedBefore)
+		}
+
+		if e.limitReached() {
+			break
+		}
+	}
+
+	// Stopping the reader drops the ledgers read ahead, and the channels are closed once it returns
+	stopReading()
+	for range ledgers {
+	}
+	if readErr := <-readErrs; err == nil {
+		err = readErr
+	}
+	return err
+}
+
+// exportLedger calls processLedger on a ledger and writes the records it exports. The records are held back until the
+// whole ledger is processed, so a ledger that panics exports none of its records, and exporting it again from the dead
+// letter folder doesn't duplicate them.
+func (e *exportEngine) exportLedger(processLedger func(xdr.LedgerCloseMeta) error, lcm xdr.LedgerCloseMeta) error {
+	exportedBefore := e.numExported
+	e.beginLedger(utils.GetLedgerSequence(lcm))
+	if e.cmdArgs.WarnUnhandledFields {
+		e.warnUnhandledXdrArms(lcm)
+	}
+
+	e.bufferLedger = true
+	err := recoverLedger(processLedger, lcm)
+	e.bufferLedger = false
+
+	var panicked *ledgerPanic
+	if errors.As(err, &panicked) {
+		e.ledgerRecords = nil
+		e.numExported = exportedBefore
+		e.ledgerPanicked(lcm, panicked)
+		return nil
+	}
+
+	for _, buffered := range e.ledgerRecords {
+		e.keep(buffered)
+	}
+	e.ledgerRecords = nil
+	return err
+}
+
+// ledgerStreamed logs the progress of an export without an end ledger after every ledger, and saves the ledger to the
+// checkpoint file once its records are written. With async sinks, the ledger is saved once they acknowledge its records.
+func (e *exportEngine) ledgerStreamed(ledgerSeq uint32, numRecords int) {
+	cmdLogger.Infof("Exported %d records from ledger %d, %d in total", numRecords, ledgerSeq, e.numExported)
+
+	// The limit can cut the records of a ledger short, so that ledger is exported again by a restarted export
+	if e.cmdArgs.CheckpointPath == "" || e.limitReached() {
+		return
+	}
+
+	if len(e.asyncSinks) == 0 {
+		if err := writeCheckpoint(e.cmdArgs.CheckpointPath, ledgerSeq); err != nil {
+			cmdLogger.Fatal(err)
+		}
+		return
+	}
+
+	numAdded := make([]int, len(e.asyncSinks))
+	for i, sink := range e.asyncSinks {
+		numAdded[i] = sink.added()
+	}
+	e.pendingCheckpoints = append(e.pendingCheckpoints, pendingCheckpoint{ledgerSeq: ledgerSeq, numAdded: numAdded})
+	e.saveAckedCheckpoint()
+}
+
+// saveAckedCheckpoint saves the last streamed ledger whose records were acknowledged by every async sink to the
+// checkpoint file. The export stops if an async sink could not deliver its records, without saving the ledgers after
+// the last acknowledged one.
+func (e *exportEngine) saveAckedCheckpoint() {
+	numAcked := make([]int, len(e.asyncSinks))
+	for i, sink := range e.asyncSinks {
+		var err error
+		numAcked[i], err = sink.acked()
+		if err != nil {
+			cmdLogger.Fatal("could not deliver records: ", err)
+		}
+	}
+
+	numSaved := 0
+	for _, pending := range e.pendingCheckpoints {
+		acknowledged := true
+		for i := range pending.numAdded {
+			acknowledged = acknowledged && pending.numAdded[i] <= numAcked[i]
+		}
+		if !acknowledged {
+			break
+		}
+		numSaved += 1
+	}
+	if numSaved == 0 {
+		return
+	}
+
+	if err := writeCheckpoint(e.cmdArgs.CheckpointPath, e.pendingCheckpoints[numSaved-1].ledgerSeq); err != nil {
+		cmdLogger.Fatal(err)
+	}
+	e.pendingCheckpoints = e.pendingCheckpoints[numSaved:]
+}
+
+// warnUnhandledXdrArms logs the first ledger with every XDR union arm that the transforms were not written against,
+// and counts the ledgers that have it
+func (e *exportEngine) warnUnhandledXdrArms(lcm xdr.LedgerCloseMeta) {
+	if e.unhandledArms == nil {
+		e.unhandledArms = map[string]int{}
+	}
+
+	for _, arm := range transform.FindUnhandledXdrArms(lcm) {
+		if e.unhandledArms[arm.String()] == 0 {
+			cmdLogger.Warnf("Ledger %d has %s, which the transforms were not written against, so its data may be missing from the output", e.ledgerSeq, arm)
+		}
+		e.unhandledArms[arm.String()] += 1
+	}
+}
+
+// beginLedger sets the ledger that the next records are exported from, which is the order of the records with sort
+func (e *exportEngine) beginLedger(ledgerSeq uint32) {
+	e.ledgerSeq = ledgerSeq
+}
+
+// attempt counts an input that is about to be transformed
+func (e *exportEngine) attempt() {
+	e.numAttempts += 1
+}
+
+// fail logs and counts a failed transform, classified by its error. The export only stops if strict export is set.
+func (e *exportEngine) fail(err error) {
+	e.failAs(classifyFailure(err), err)
+}
+
+// failAs logs and counts a failed transform or export of a known failure class
+func (e *exportEngine) failAs(class string, err error) {
+	cmdLogger.LogError(err)
+	e.numFailures += 1
+	if e.failureClasses == nil {
+		e.failureClasses = map[string]int{}
+	}
+	e.failureClasses[class] += 1
+}
+
+// limitReached returns true once the number of exported records has reached the limit
+func (e *exportEngine) limitReached() bool {
+	return limitReached(e.numExported, e.cmdArgs.Limit)
+}
+
+// export writes a transformed record to the output file, unless the limit has already been reached
+func (e *exportEngine) export(record interface{}) {
+	if e.limitReached() {
+		return
+	}
+
+	if e.encryptor != nil {
+		encrypted, err := e.encryptor.encryptRecord(record)
+		if err != nil {
+			e.failAs(failureSink, fmt.Errorf("could not encrypt record: %v", err))
+			return
+		}
+		record = encrypted
+	}
+
+	marshalled, err := MarshalEntry(record, e.cmdArgs.Extra, e.cmdArgs.StringifyLargeInts, e.cmdArgs.ClosedAtMillis)
+	if err != nil {
+		e.failAs(failureSink, fmt.Errorf("could not export record: %v", err))
+		return
+	}
+
+	if e.fieldMapping != nil {
+		marshalled, err = e.fieldMapping.renameRecord(marshalled)
+		if err != nil {
+			e.failAs(failureSink, fmt.Errorf("could not rename record fields: %v", err))
+			return
+		}
+	}
+
+	output := marshalled
+	if e.sizeGuard != nil && e.sizeGuard.oversized(marshalled) {
+		output, err = e.sizeGuard.handle(marshalled)
+		if err != nil {
+			e.failAs(failureSink, err)
+			return
+		}
+	}
+
+	e.numExported += 1
+	exported := sortedRecord{ledgerSeq: e.ledgerSeq, record: record, marshalled: marshalled, output: output}
+	if e.bufferLedger {
+		e.ledgerRecords = append(e.ledgerRecords, exported)
+		return
+	}
+	e.keep(exported)
+}
+
+// keep writes an exported record, or holds it back until the end of the export with sort
+func (e *exportEngine) keep(exported sortedRecord) {
+	if e.env.CommonFlagValues.Sort {
+		e.sortedRecords = append(e.sortedRecords, exported)
+		return
+	}
+
+	e.write(exported.ledgerSeq, exported.record, exported.marshalled, exported.output)
+}
+
+// write writes a record exported from a ledger to the output file or the sink, and keeps it for the sqlite and parquet
+// outputs and the index
+func (e *exportEngine) write(ledgerSeq uint32, record interface{}, marshalled, output []byte) {
+	switch {
+	case output == nil:
+		// Spilled records are only written to the side file
+	case e.sink != nil:
+		// A failed batch can't be recovered, so the export stops instead of skipping records
+		if err := e.sink.add(output); err != nil {
+			cmdLogger.Fatalf("could not send records to the %s sink: %v", e.cmdArgs.Sink, err)
+		}
+		e.totalNumBytes += len(output)
+	default:
+		e.totalNumBytes += writeEntryLine(output, e.outFile)
+	}
+
+	if output != nil {
+		for _, fanout := range e.fanouts {
+			if err := fanout.add(output); err != nil {
+				cmdLogger.Fatalf("could not send records to sink %s: %v", fanout.name, err)
+			}
+		}
+	}
+
+	if e.sqlite != nil {
+		if err := e.sqlite.write(record, marshalled); err != nil {
+			cmdLogger.Fatal("could not write record to sqlite: ", err)
+		}
+	}
+
+	if e.index != nil {
+		if err := e.index.add(ledgerSeq, marshalled); err != nil {
+			cmdLogger.Fatal(err)
+		}
+	}
+
+	if (e.cmdArgs.WriteParquet || e.cmdArgs.WriteORC || e.cmdArgs.WriteArrow) && e.parquetSchema != nil {
+		if parquetRecord, ok := record.(transform.SchemaParquet); ok {
+			e.parquetRecords = append(e.parquetRecords, parquetRecord)
+		}
+	}
+}
+
+// writeSorted writes the records held back by sort in ledger order. The sort is stable, so the records of a ledger keep
+// the transaction, operation and effect order they were exported in.
+func (e *exportEngine) writeSorted() {
+	sort.SliceStable(e.sortedRecords, func(i, j int) bool {
+		return e.sortedRecords[i].ledgerSeq < e.sortedRecords[j].ledgerSeq
+	})
+	for _, sorted := range e.sortedRecords {
+		e.write(sorted.ledgerSeq, sorted.record, sorted.marshalled, sorted.output)
+	}
+	e.sortedRecords = nil
+}
+
+// finish closes the output file or sends the last records to the sink, prints the transform stats, and uploads the output files
+func (e *exportEngine) finish() {
+	if e.env.CommonFlagValues.Sort {
+		e.writeSorted()
+	}
+
+	if e.sink != nil {
+		e.flushSink()
+		e.sink.close()
+		cmdLogger.Info(e.sink.describe())
+	} else {
+		e.outFile.Close()
+	}
+	for _, fanout := range e.fanouts {
+		e.flushFanout(fanout)
+		fanout.sink.close()
+		cmdLogger.Info(fanout.describe())
+	}
+	if len(e.asyncSinks) > 0 {
+		// Closing the async sinks waited for their records, so the checkpoint is saved for the last streamed ledger
+		e.saveAckedCheckpoint()
+	}
+	cmdLogger.Info("Number of bytes written: ", e.totalNumBytes)
+
+	if e.sizeGuard != nil {
+		cmdLogger.Info(e.sizeGuard.describe())
+		if e.sizeGuard.close() {
+			MaybeUpload(e.cmdArgs.Credentials, e.cmdArgs.Bucket, e.cmdArgs.Provider, e.cmdArgs.OversizedPath, e.cmdArgs.Upload)
+		}
+	}
+
+	if e.sqlite != nil {
+		if err := e.sqlite.close(); err != nil {
+			cmdLogger.Fatal("could not write records to sqlite: ", err)
+		}
+		cmdLogger.Infof("Wrote %d rows to table %s of %s", e.sqlite.numRows, e.cmdArgs.SQLiteTable, e.cmdArgs.SQLitePath)
+	}
+
+	PrintTransformStats(e.cmdArgs.Dataset, e.numAttempts, e.numFailures, e.failureClasses)
+	printTransformTimings(e.cmdArgs.Dataset, e.timings)
+	if len(e.unhandledArms) > 0 {
+		cmdLogger.Warnf("Ledgers with XDR union arms that the transforms were not written against: %v", e.unhandledArms)
+	}
+
+	// DuckDB loads the parquet output when there is one, since it has the exact column types
+	writesParquet := e.cmdArgs.WriteParquet && e.parquetSchema != nil
+	if e.cmdArgs.DuckDBPath != "" && !writesParquet {
+		if e.outFile == nil {
+			cmdLogger.Fatalf("duckdb needs the output file or write-parquet, but the records were sent to the %s sink", e.cmdArgs.Sink)
+		}
+		e.loadIntoDuckDB(e.cmdArgs.Path, false)
+	}
+
+	if e.outFile != nil {
+		e.upload(e.cmdArgs.Path, "json")
+	}
+
+	if writesParquet {
+		WriteParquet(e.parquetRecords, e.cmdArgs.ParquetPath, e.parquetSchema, e.fieldMapping)
+		if e.cmdArgs.DeltaTablePath != "" {
+			e.appendToDeltaTable()
+		}
+		if e.cmdArgs.DuckDBPath != "" {
+			e.loadIntoDuckDB(e.cmdArgs.ParquetPath, true)
+		}
+		uploadedPath := e.upload(e.cmdArgs.ParquetPath, "parquet")
+		if e.bigQueryTable != nil && uploadedPath != "" {
+			e.loadIntoBigQuery(fmt.Sprintf("gs://%s/%s", e.cmdArgs.Bucket, uploadedPath))
+		}
+	}
+
+	if e.cmdArgs.WriteORC && e.parquetSchema != nil {
+		WriteORC(e.parquetRecords, e.cmdArgs.ORCPath, e.parquetSchema)
+		e.upload(e.cmdArgs.ORCPath, "orc")
+	}
+
+	if e.cmdArgs.WriteArrow && e.parquetSchema != nil {
+		WriteArrow(e.parquetRecords, e.cmdArgs.ArrowPath, e.parquetSchema)
+		e.upload(e.cmdArgs.ArrowPath, "arrow")
+	}
+
+	if e.notifier != nil {
+		if err := e.notifier.close(); err != nil {
+			cmdLogger.Fatal(err)
+		}
+		cmdLogger.Info(e.notifier.describe())
+	}
+
+	if e.cmdArgs.RunMetadataTable != "" {
+		e.recordRunMetadata()
+	}
+}
+
+// upload uploads an output file of a format, along with its index if index-files is set, and publishes its upload
+// notification if notify-bus is set. It returns the path of the uploaded file in the bucket, which is empty if the file
+// wasn't uploaded.
+func (e *exportEngine) upload(path, format string) string {
+	var writeIndex func(string) ([]string, error)
+	if e.index != nil {
+		writeIndex = func(outputPath string) ([]string, error) {
+			indexPath, err := e.index.write(outputPath, e.cmdArgs.Dataset, e.cmdArgs.ChunkToken)
+			if err != nil {
+				return nil, err
+			}
+			cmdLogger.Infof("Wrote index file %s", indexPath)
+			return []string{indexPath}, nil
+		}
+	}
+	uploadedPath := maybeUploadWithSidecars(e.cmdArgs.Credentials, e.cmdArgs.Bucket, e.cmdArgs.Provider, path, e.cmdArgs.Upload, writeIndex)
+	if e.notifier == nil || uploadedPath == "" {
+		return uploadedPath
+	}
+
+	// Exports without an end ledger end at the last ledger they read
+	endLedger := e.cmdArgs.EndNum
+	if endLedger == 0 {
+		endLedger = e.ledgerSeq
+	}
+	err := e.notifier.notify(uploadNotification{
+		Path:        uploadedPath,
+		Dataset:     e.cmdArgs.Dataset,
+		Format:      format,
+		Network:     e.env.Network,
+		StartLedger: e.cmdArgs.StartNum,
+		EndLedger:   endLedger,
+		NumRecords:  e.numExported,
+		Interrupted: e.interrupted,
+		ChunkToken:  e.cmdArgs.ChunkToken,
+	})
+	if err != nil {
+		cmdLogger.Fatal(err)
+	}
+	return uploadedPath
+}
+
+// detectCoreVersion records the version of the stellar-core binary that reads the ledgers, so anomalies in the output
+// can be matched with core upgrades. With core-version-field, it is also added to every exported record. The export
+// goes on without it if the version can't be read.
+func (e *exportEngine) detectCoreVersion() {
+	version, err := utils.CoreVersion(e.env.BinaryPath)
+	if err != nil {
+		cmdLogger.Warnf("Could not get the stellar-core version: %v", err)
+		return
+	}
+
+	cmdLogger.Infof("Reading ledgers with stellar-core %s", version)
+	e.coreVersion = version
+	if e.cmdArgs.CoreVersionField {
+		e.cmdArgs.Extra = withCoreVersion(e.cmdArgs.Extra, version)
+	}
+}
+
+// withCoreVersion returns a copy of the extra fields of the output jsons with the core_version field
+func withCoreVersion(extra map[string]string, version string) map[string]string {
+	withVersion := make(map[string]string, len(extra)+1)
+	for key, value := range extra {
+		withVersion[key] = value
+	}
+	withVersion["core_version"] = version
+	return withVersion
+}
+
+// loadIntoBigQuery replaces the rows of the exported ledgers in the BigQuery table with the uploaded parquet output and
+// waits for the jobs, so that the records are queryable once the export finishes. The statistics of the load job are
+// logged and recorded in the run metadata.
+func (e *exportEngine) loadIntoBigQuery(sourceURI string) {
+	if e.numExported == 0 {
+		cmdLogger.Info("No records to load into bigquery")
+		return
+	}
+
+	// Without an end ledger, the chunk ends at the last streamed ledger
+	endLedger := e.cmdArgs.EndNum
+	if endLedger == 0 {
+		endLedger = e.ledgerSeq
+	}
+	chunk, err := newBigQueryChunk(e.env.Network, e.cmdArgs.Dataset, e.parquetSchema, e.cmdArgs.StartNum, endLedger)
+	if err != nil {
+		cmdLogger.Fatal(err)
+	}
+	if e.cmdArgs.ChunkToken != "" {
+		chunk.token = e.cmdArgs.ChunkToken
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), bigQueryLoadTimeout)
+	defer cancel()
+	loader, err := newBigQueryLoader(ctx)
+	if err != nil {
+		cmdLogger.Fatal(err)
+	}
+
+	stats, err := loader.load(ctx, *e.bigQueryTable, sourceURI, chunk)
+	if err != nil {
+		cmdLogger.Fatalf("could not load %s into bigquery table %s: %v", sourceURI, e.cmdArgs.BigQueryLoadTable, err)
+	}
+	cmdLogger.Infof("Bigquery load job %s loaded %d rows (%d bytes) from %d files into %s", stats.JobID, stats.OutputRows, stats.OutputBytes, stats.InputFiles, e.cmdArgs.BigQueryLoadTable)
+	e.bigQueryLoad = &stats
+}
+
+// runMetadata returns the metadata of the export, once it has finished
+func (e *exportEngine) runMetadata(finishedAt time.Time) exportRunMetadata {
+	metadata := exportRunMetadata{
+		Dataset:         e.cmdArgs.Dataset,
+		Network:         e.env.Network,
+		StartLedger:     e.cmdArgs.StartNum,
+		EndLedger:       e.cmdArgs.EndNum,
+		Attempted:       e.numAttempts,
+		Failed:          e.numFailures,
+		Exported:        e.numExported,
+		BytesWritten:    e.totalNumBytes,
+		Interrupted:     e.interrupted,
+		StartedAt:       e.startedAt,
+		FinishedAt:      finishedAt,
+		DurationSeconds: finishedAt.Sub(e.startedAt).Seconds(),
+		Version:         etlVersion(),
+		CoreVersion:     e.coreVersion,
+	}
+	if e.bigQueryLoad != nil {
+		metadata.BigQueryLoadJobID = e.bigQueryLoad.JobID
+		metadata.BigQueryLoadedRows = e.bigQueryLoad.OutputRows
+		metadata.BigQueryInputFiles = e.bigQueryLoad.InputFiles
+		metadata.BigQueryInputBytes = e.bigQueryLoad.InputBytes
+	}
+	return metadata
+}
+
+// recordRunMetadata writes the metadata of the export to the run metadata table. It runs after the output files are
+// uploaded, so a recorded run means its output is available.
+func (e *exportEngine) recordRunMetadata() {
+	store, err := newRunMetadataStore(e.cmdArgs.RunMetadataTable)
+	if err != nil {
+		cmdLogger.Fatal("could not open run metadata table: ", err)
+	}
+
+	if err := store.record(e.runMetadata(time.Now())); err != nil {
+		cmdLogger.Fatal("could not record run metadata: ", err)
+	}
+}
+
+// flushSink sends the records queued for the record sink and the sinks of the config file
+func (e *exportEngine) flushSink() {
+	for _, fanout := range e.fanouts {
+		e.flushFanout(fanout)
+	}
+
+	if e.sink == nil {
+		return
+	}
+
+	if err := e.sink.flush(); err != nil {
+		cmdLogger.Fatalf("could not send records to the %s sink: %v", e.cmdArgs.Sink, err)
+	}
+}
+
+// flushFanout sends the records queued for a sink of the config file
+func (e *exportEngine) flushFanout(fanout *fanoutSink) {
+	if err := fanout.sink.flush(); err != nil {
+		cmdLogger.Fatalf("could not send records to sink %s: %v", fanout.name, err)
+	}
+}
+
+// loadIntoDuckDB appends an output file to the DuckDB table. It has to run before the file is uploaded, because uploading
+// removes the local file.
+func (e *exportEngine) loadIntoDuckDB(dataPath string, isParquet bool) {
+	if e.numExported == 0 {
+		cmdLogger.Info("No records to write to duckdb")
+		return
+	}
+
+	err := loadIntoDuckDB(e.cmdArgs.DuckDBExecutable, e.cmdArgs.DuckDBPath, e.cmdArgs.DuckDBTable, dataPath, isParquet)
+	if err != nil {
+		cmdLogger.Fatalf("could not write to table %s of duckdb database %s: %v", e.cmdArgs.DuckDBTable, e.cmdArgs.DuckDBPath, err)
+	}
+
+	cmdLogger.Infof("Wrote %d rows to table %s of %s", e.numExported, e.cmdArgs.DuckDBTable, e.cmdArgs.DuckDBPath)
+}
+
+// appendToDeltaTable commits the parquet output to the Delta Lake table. It has to run before the parquet file is uploaded,
+// because uploading removes the local file.
+func (e *exportEngine) appendToDeltaTable() {
+	store, err := newDeltaTableStore(e.cmdArgs.DeltaTablePath, e.cmdArgs.Credentials, e.cmdArgs.Bucket, e.cmdArgs.Provider)
+	if err != nil {
+		cmdLogger.Fatal("could not open delta table: ", err)
+	}
+
+	version, err := appendToDeltaTable(store, e.cmdArgs.ParquetPath, e.parquetSchema, e.fieldMapping)
+	if err != nil {
+		cmdLogger.Fatalf("could not append to delta table %s: %v", e.cmdArgs.DeltaTablePath, err)
+	}
+
+	cmdLogger.Infof("Committed version %d of delta table %s", version, e.cmdArgs.DeltaTablePath)
+}
@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stellar/stellar-etl/v2/internal/transform"
+	"github.com/stellar/stellar-etl/v2/internal/utils"
+)
+
+func TestExportEngineAppliesLimit(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "upgrades.txt")
+	cmdArgs := utils.FlagValues{Path: path, Limit: 2, WriteParquet: true}
+	engine := newExportEngine(cmdArgs, utils.EnvironmentDetails{}, new(transform.LedgerUpgradeOutput), new(transform.LedgerUpgradeOutputParquet))
+
+	for i := int32(0); i < 3; i++ {
+		engine.attempt()
+		engine.export(transform.LedgerUpgradeOutput{LedgerSequence: 10, UpgradeIndex: i})
+	}
+	engine.attempt()
+	engine.fail(fmt.Errorf("could not transform upgrade"))
+	engine.outFile.Close()
+
+	assert.True(t, engine.limitReached())
+	assert.Equal(t, 4, engine.numAttempts)
+	assert.Equal(t, 1, engine.numFailures)
+	assert.Equal(t, map[string]int{failureOther: 1}, engine.failureClasses)
+	assert.Equal(t, 2, engine.numExported)
+	assert.Len(t, engine.parquetRecords, 2)
+
+	contents, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Len(t, strings.Split(strings.TrimSpace(string(contents)), "\n"), 2)
+}
+
+func TestClassifyFailure(t *testing.T) {
+	tests := map[string]string{
+		"could not transform operation 1 in transaction 2 in ledger 3: unknown operation type: OperationType(99)":                                    failureUnknownType,
+		"could not transform trade 1 in transaction 2 in ledger 3: operation of type OperationTypeBumpSequence at index 0 does not result in trades": failureUnknownType,
+		"could not transform transaction 2 in ledger 3: could not get any results from this transaction":                                             failureMissingResult,
+		"could not transform operation 1 in transaction 2 in ledger 3: could not get result Tr for operation at index 0":                             failureMissingResult,
+		"could not transform contract events in transaction 2 in ledger 3: xdr:DecodeUint: unexpected EOF":                                           failureXDRDecode,
+		"could not transform fee stats of ledger 3: negative fee":                                                                                    failureOther,
+	}
+
+	for message, class := range tests {
+		assert.Equal(t, class, classifyFailure(fmt.Errorf("%s", message)), message)
+	}
+}
+
+func TestExportEngineSkipsParquetWithoutSchema(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "upgrades.txt")
+	cmdArgs := utils.FlagValues{Path: path, Limit: -1, WriteParquet: true}
+	engine := newExportEngine(cmdArgs, utils.EnvironmentDetails{}, new(transform.LedgerUpgradeOutput), nil)
+
+	engine.export(transform.LedgerUpgradeOutput{LedgerSequence: 10})
+	engine.outFile.Close()
+
+	assert.False(t, engine.limitReached())
+	assert.Equal(t, 1, engine.numExported)
+	assert.Empty(t, engine.parquetRecords)
+}
+
+func TestExportEngineSortsRecords(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "upgrades.txt")
+	cmdArgs := utils.FlagValues{Path: path, Limit: -1, EndNum: 12, WriteParquet: true}
+	env := utils.EnvironmentDetails{CommonFlagValues: utils.CommonFlagValues{Sort: true}}
+	engine := newExportEngine(cmdArgs, env, new(transform.LedgerUpgradeOutput), new(transform.LedgerUpgradeOutputParquet))
+
+	// The ledgers are read newest first, as in a descending export
+	for _, ledgerSeq := range []uint32{12, 11, 10} {
+		engine.beginLedger(ledgerSeq)
+		for i := int32(0); i < 2; i++ {
+			engine.attempt()
+			engine.export(transform.LedgerUpgradeOutput{LedgerSequence: ledgerSeq, UpgradeIndex: i})
+		}
+	}
+	assert.Equal(t, 6, engine.numExported)
+	assert.Empty(t, engine.parquetRecords)
+
+	engine.writeSorted()
+	engine.outFile.Close()
+
+	contents, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	order := []string{}
+	for _, line := range strings.Split(strings.TrimSpace(string(contents)), "\n") {
+		var upgrade transform.LedgerUpgradeOutput
+		assert.NoError(t, json.Unmarshal([]byte(line), &upgrade))
+		order = append(order, fmt.Sprintf("%d/%d", upgrade.LedgerSequence, upgrade.UpgradeIndex))
+	}
+	assert.Equal(t, []string{"10/0", "10/1", "11/0", "11/1", "12/0", "12/1"}, order)
+	assert.Equal(t, transform.LedgerUpgradeOutput{LedgerSequence: 10, UpgradeIndex: 0}, engine.parquetRecords[0])
+	assert.Equal(t, transform.LedgerUpgradeOutput{LedgerSequence: 12, UpgradeIndex: 1}, engine.parquetRecords[5])
+}
+
+func TestExportEngineExportsStreamedLedgers(t *testing.T) {
+	fixture := filepath.Join(t.TempDir(), "ledgers.xdr.zst")
+	ledgers := []xdr.LedgerCloseMeta{}
+	for seq := uint32(10); seq < 20; seq++ {
+		ledgers = append(ledgers, replayLedger(seq))
+	}
+	assert.NoError(t, utils.WriteLedgerFixture(fixture, ledgers))
+
+	path := filepath.Join(t.TempDir(), "upgrades.txt")
+	cmdArgs := utils.FlagValues{Path: path, StartNum: 10, EndNum: 19, Limit: 3}
+	env := utils.EnvironmentDetails{CommonFlagValues: utils.CommonFlagValues{LedgerFixture: fixture}}
+	engine := newExportEngine(cmdArgs, env, new(transform.LedgerUpgradeOutput), nil)
+
+	exported := []uint32{}
+	err := engine.exportLedgers(context.Background(), func(lcm xdr.LedgerCloseMeta) error {
+		exported = append(exported, lcm.LedgerSequence())
+		engine.export(transform.LedgerUpgradeOutput{LedgerSequence: lcm.LedgerSequence()})
+		return nil
+	})
+	engine.outFile.Close()
+
+	// Reading stops at the limit, and the ledgers that were read ahead are dropped
+	assert.NoError(t, err)
+	assert.Equal(t, []uint32{10, 11, 12}, exported)
+	assert.Equal(t, 3, engine.numExported)
+}
@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stellar/stellar-etl/v2/internal/input"
+	"github.com/stellar/stellar-etl/v2/internal/transform"
+	"github.com/stellar/stellar-etl/v2/internal/utils"
+)
+
+// eventTypes are the values of the event_type column of the events, in the order they are listed in the types flag
+var eventTypes = []string{"contract", "diagnostic", "system"}
+
+var eventsCmd = &cobra.Command{
+	Use:   "export_events",
+	Short: "Exports the contract, diagnostic and system events over a specified range.",
+	Long: `Exports the events of the types in --types over a specified range to an output file. Every event has its type in
+the event_type column, and the events of every type share the schema of export_contract_events.
+
+If the end-ledger is omitted, then the export will continue running and exporting events as new ledgers are confirmed
+by the Stellar network.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		runEventsExport(cmd, mustEventTypes(cmd.Flags()))
+	},
+}
+
+// mustEventTypes reads the types flag into the set of event types to export
+func mustEventTypes(flags *pflag.FlagSet) map[string]bool {
+	types, err := flags.GetStringSlice("types")
+	if err != nil {
+		cmdLogger.Fatal("could not get types flag: ", err)
+	}
+	if len(types) == 0 {
+		cmdLogger.Fatal("types needs at least one event type")
+	}
+
+	selected := map[string]bool{}
+	for _, eventType := range types {
+		if !slices.Contains(eventTypes, eventType) {
+			cmdLogger.Fatalf("unknown event type %q in types, expected one of %v", eventType, eventTypes)
+		}
+		selected[eventType] = true
+	}
+	return selected
+}
+
+// runEventsExport exports the events of the selected types, with the flags of export_events and export_contract_events.
+// Every event is exported if selectedTypes is nil.
+func runEventsExport(cmd *cobra.Command, selectedTypes map[string]bool) {
+	cmdLogger.SetLevel(logrus.InfoLevel)
+	cmdArgs := utils.MustFlags(cmd.Flags(), cmdLogger)
+
+	// TODO: https://stellarorg.atlassian.net/browse/HUBBLE-386 GetEnvironmentDetails should be refactored
+	commonArgs := utils.MustCommonFlags(cmd.Flags(), cmdLogger)
+	env := utils.GetEnvironmentDetails(commonArgs)
+
+	kindFilter := mustTransactionKindFilter(cmd.Flags())
+	accountFilter := mustAccountFilter(cmd.Flags())
+
+	engine := newExportEngine(cmdArgs, env, new(transform.ContractEventOutput), new(transform.ContractEventOutputParquet))
+	engine.run(func(lcm xdr.LedgerCloseMeta) error {
+		skip, err := kindFilter.skipLedger(lcm)
+		if err != nil {
+			return err
+		}
+		if skip {
+			return nil
+		}
+
+		transactions, err := input.GetLedgerTransactions(lcm, env)
+		if err != nil {
+			return err
+		}
+
+		// Transactions can emit any number of events, so the limit is applied to the exported events instead of the input
+		for _, transformInput := range transactions {
+			if engine.limitReached() {
+				break
+			}
+
+			if kindFilter.skipTransaction(transformInput.Transaction) {
+				continue
+			}
+
+			skipAccounts, err := skipFilteredAccounts(accountFilter, transformInput.Transaction)
+			if err != nil {
+				return err
+			}
+			if skipAccounts {
+				continue
+			}
+
+			engine.attempt()
+			timed := engine.timeTransform(transactionOperationType(transformInput.Transaction))
+			transformed, err := transform.TransformContractEvent(transformInput.Transaction, transformInput.LedgerHistory)
+			timed()
+			if err != nil {
+				ledgerSeq := transformInput.LedgerHistory.Header.LedgerSeq
+				engine.fail(fmt.Errorf("could not transform contract events in transaction %d in ledger %d: %v", transformInput.Transaction.Index, ledgerSeq, err))
+				continue
+			}
+
+			for _, contractEvent := range transformed {
+				if selectedTypes != nil && !selectedTypes[contractEvent.EventType] {
+					continue
+				}
+				engine.export(contractEvent)
+			}
+		}
+
+		return nil
+	})
+}
+
+func init() {
+	rootCmd.AddCommand(eventsCmd)
+	utils.AddCommonFlags(eventsCmd.Flags())
+	utils.AddArchiveFlags("contract_events", eventsCmd.Flags())
+	utils.AddCloudStorageFlags(eventsCmd.Flags())
+	addAccountFilterFlags(eventsCmd.Flags())
+	addTransactionKindFlags(eventsCmd.Flags())
+	eventsCmd.Flags().StringSlice("types", eventTypes, "Comma separated types of the events to export: contract, diagnostic and system.")
+
+	eventsCmd.MarkFlagRequired("start-ledger")
+
+	/*
+		Current flags:
+			start-ledger: the ledger sequence number for the beginning of the export period (required)
+			end-ledger: the ledger sequence number for the end of the export range; if omitted, events are exported continuously as ledgers close
+			types: the types of the events to export; defaults to every type
+	*/
+}
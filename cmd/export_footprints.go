@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stellar/stellar-etl/v2/internal/input"
+	"github.com/stellar/stellar-etl/v2/internal/transform"
+	"github.com/stellar/stellar-etl/v2/internal/utils"
+)
+
+var footprintsCmd = &cobra.Command{
+	Use:   "export_footprints",
+	Short: "Exports the soroban transaction footprints over a specified range.",
+	Long: `Exports the read-only and read-write ledger keys declared in the footprints of soroban transactions over a specified
+range to an output file. Each key is exported as its own record, decoded into its type and identifying fields.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmdLogger.SetLevel(logrus.InfoLevel)
+		cmdArgs := utils.MustFlags(cmd.Flags(), cmdLogger)
+
+		// TODO: https://stellarorg.atlassian.net/browse/HUBBLE-386 GetEnvironmentDetails should be refactored
+		commonArgs := utils.MustCommonFlags(cmd.Flags(), cmdLogger)
+		env := utils.GetEnvironmentDetails(commonArgs)
+
+		engine := newExportEngine(cmdArgs, env, new(transform.FootprintOutput), new(transform.FootprintOutputParquet))
+		engine.run(func(lcm xdr.LedgerCloseMeta) error {
+			transactions, err := input.GetLedgerTransactions(lcm, env)
+			if err != nil {
+				return err
+			}
+
+			// Footprints have any number of keys, so the limit is applied to the exported keys instead of the input
+			for _, transformInput := range transactions {
+				if engine.limitReached() {
+					break
+				}
+
+				if !transformInput.Transaction.IsSorobanTx() {
+					continue
+				}
+
+				engine.attempt()
+				transformed, err := transform.TransformFootprint(transformInput.Transaction, transformInput.LedgerHistory)
+				if err != nil {
+					ledgerSeq := transformInput.LedgerHistory.Header.LedgerSeq
+					engine.fail(fmt.Errorf("could not transform footprint of transaction %d in ledger %d: %v", transformInput.Transaction.Index, ledgerSeq, err))
+					continue
+				}
+
+				for _, footprintKey := range transformed {
+					engine.export(footprintKey)
+				}
+			}
+
+			return nil
+		})
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(footprintsCmd)
+	utils.AddCommonFlags(footprintsCmd.Flags())
+	utils.AddArchiveFlags("footprints", footprintsCmd.Flags())
+	utils.AddCloudStorageFlags(footprintsCmd.Flags())
+
+	footprintsCmd.MarkFlagRequired("start-ledger")
+	footprintsCmd.MarkFlagRequired("end-ledger")
+}
@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stellar/stellar-etl/v2/internal/input"
+	"github.com/stellar/stellar-etl/v2/internal/transform"
+	"github.com/stellar/stellar-etl/v2/internal/utils"
+)
+
+var inflationPayoutsCmd = &cobra.Command{
+	Use:   "export_inflation_payouts",
+	Short: "Exports the inflation payouts over a specified range.",
+	Long: `Exports the payouts of successful inflation operations within the specified range to an output file.
+Each payout is exported as a separate JSON object. Inflation was disabled in protocol 12, so only older ledgers contain payouts.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmdLogger.SetLevel(logrus.InfoLevel)
+		cmdArgs := utils.MustFlags(cmd.Flags(), cmdLogger)
+		commonArgs := utils.MustCommonFlags(cmd.Flags(), cmdLogger)
+		env := utils.GetEnvironmentDetails(commonArgs)
+
+		engine := newExportEngine(cmdArgs, env, new(transform.InflationPayoutOutput), new(transform.InflationPayoutOutputParquet))
+		engine.run(func(lcm xdr.LedgerCloseMeta) error {
+			operations, err := input.GetLedgerOperations(lcm, env)
+			if err != nil {
+				return err
+			}
+
+			// An inflation operation can produce any number of payouts, so the limit is applied to the exported payouts instead of the input
+			for _, transformInput := range operations {
+				if engine.limitReached() {
+					break
+				}
+
+				engine.attempt()
+				payouts, err := transform.TransformInflationPayouts(transformInput.Operation, transformInput.OperationIndex, transformInput.Transaction, transformInput.LedgerSeqNum, transformInput.LedgerCloseMeta)
+				if err != nil {
+					txIndex := transformInput.Transaction.Index
+					engine.fail(fmt.Errorf("could not transform inflation payouts of operation %d in transaction %d in ledger %d: %v", transformInput.OperationIndex, txIndex, transformInput.LedgerSeqNum, err))
+					continue
+				}
+
+				for _, payout := range payouts {
+					engine.export(payout)
+				}
+			}
+
+			return nil
+		})
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(inflationPayoutsCmd)
+	utils.AddCommonFlags(inflationPayoutsCmd.Flags())
+	utils.AddArchiveFlags("inflation_payouts", inflationPayoutsCmd.Flags())
+	utils.AddCloudStorageFlags(inflationPayoutsCmd.Flags())
+	inflationPayoutsCmd.MarkFlagRequired("end-ledger")
+}
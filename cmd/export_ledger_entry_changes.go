@@ -6,9 +6,11 @@ import (
 	"math"
 	"os"
 	"path/filepath"
+	"sort"
 
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	"github.com/stellar/go-stellar-sdk/ingest/ledgerbackend"
 	"github.com/stellar/go-stellar-sdk/xdr"
 	"github.com/stellar/stellar-etl/v2/internal/input"
@@ -32,15 +34,55 @@ be exported.`,
 		cmdLogger.SetLevel(logrus.InfoLevel)
 		commonArgs := utils.MustCommonFlags(cmd.Flags(), cmdLogger)
 		cmdLogger.StrictExport = commonArgs.StrictExport
+		transform.SetScValLimits(commonArgs.ScValMaxDepth, commonArgs.ScValMaxSize)
 		env := utils.GetEnvironmentDetails(commonArgs)
 
-		_, configPath, startNum, batchSize, outputFolder, parquetOutputFolder := utils.MustCoreFlags(cmd.Flags(), cmdLogger)
+		_, configPath, startNum, batchSize, outputFolder, parquetOutputFolder, limit := utils.MustCoreFlags(cmd.Flags(), cmdLogger)
 		exports := utils.MustExportTypeFlags(cmd.Flags(), cmdLogger)
 		cloudStorageBucket, cloudCredentials, cloudProvider := utils.MustCloudStorageFlags(cmd.Flags(), cmdLogger)
+		uploadOptions := utils.MustUploadFlags(cmd.Flags(), cmdLogger)
+
+		pubSubEndpoint, err := cmd.Flags().GetString("pubsub-endpoint")
+		if err != nil {
+			cmdLogger.Fatal("could not get pubsub-endpoint: ", err)
+		}
+		kafkaRESTURL, err := cmd.Flags().GetString("kafka-rest-url")
+		if err != nil {
+			cmdLogger.Fatal("could not get kafka-rest-url: ", err)
+		}
+		ttlExpiringWithin, err := cmd.Flags().GetUint32("ttl-expiring-within")
+		if err != nil {
+			cmdLogger.Fatal("could not get ttl-expiring-within: ", err)
+		}
+		wasmOutput, err := cmd.Flags().GetString("wasm-output")
+		if err != nil {
+			cmdLogger.Fatal("could not get wasm-output: ", err)
+		}
+		notifier, err := newUploadNotifier(uploadOptions, cloudProvider, cloudStorageBucket, pubSubEndpoint, kafkaRESTURL)
+		if err != nil {
+			cmdLogger.Fatal("could not set up upload notifications: ", err)
+		}
+		if notifier != nil {
+			defer func() {
+				if err := notifier.close(); err != nil {
+					cmdLogger.Fatal(err)
+				}
+				cmdLogger.Info(notifier.describe())
+			}()
+		}
+
+		var wasm *wasmStore
+		if wasmOutput != "" {
+			wasm, err = newWasmStore(wasmOutput, cloudCredentials, cloudStorageBucket, cloudProvider, uploadOptions)
+			if err != nil {
+				cmdLogger.Fatal("could not set up the wasm store: ", err)
+			}
+			defer func() { cmdLogger.Info(wasm.describe()) }()
+		}
 
 		cmd.Flags()
 
-		err := os.MkdirAll(outputFolder, os.ModePerm)
+		err = os.MkdirAll(outputFolder, os.ModePerm)
 		if err != nil {
 			cmdLogger.Fatalf("unable to mkdir %s: %v", outputFolder, err)
 		}
@@ -54,10 +96,34 @@ be exported.`,
 			cmdLogger.Fatalf("batch-size (%d) must be greater than 0", batchSize)
 		}
 
+		if commonArgs.Descending {
+			cmdLogger.Fatal("descending order is not supported when exporting ledger entry changes")
+		}
+
+		if commonArgs.EveryNthLedger > 1 || commonArgs.SampleRate < 1 {
+			cmdLogger.Fatal("ledger sampling is not supported when exporting ledger entry changes")
+		}
+
+		if err := checkEngineOnlyFlags(cmd.Flags()); err != nil {
+			cmdLogger.Fatal(err)
+		}
+
 		if configPath == "" && commonArgs.EndNum == 0 {
 			cmdLogger.Fatal("stellar-core needs a config file path when exporting ledgers continuously (endNum = 0)")
 		}
 
+		if commonArgs.UseCaptiveCore && commonArgs.LedgerFixture == "" {
+			coreVersion, err := utils.CoreVersion(env.BinaryPath)
+			if err != nil {
+				cmdLogger.Warnf("Could not get the stellar-core version: %v", err)
+			} else {
+				cmdLogger.Infof("Reading ledgers with stellar-core %s", coreVersion)
+				if commonArgs.CoreVersionField {
+					commonArgs.Extra = withCoreVersion(commonArgs.Extra, coreVersion)
+				}
+			}
+		}
+
 		ctx := context.Background()
 		backend, err := utils.CreateLedgerBackend(ctx, commonArgs.UseCaptiveCore, env)
 		if err != nil {
@@ -73,6 +139,7 @@ be exported.`,
 			commonArgs.EndNum = math.MaxInt32
 		}
 
+		numExported := 0
 		changeChan := make(chan input.ChangeBatch)
 		closeChan := make(chan int)
 		go input.StreamChanges(&backend, startNum, commonArgs.EndNum, batchSize, changeChan, closeChan, env, cmdLogger)
@@ -88,16 +155,21 @@ be exported.`,
 				transformedOutputs := map[string][]interface{}{}
 
 				exportMapping := map[string][]string{
-					"export-accounts":        {"accounts", "signers"},
-					"export-balances":        {"claimable_balances"},
-					"export-offers":          {"offers"},
-					"export-trustlines":      {"trustlines"},
-					"export-pools":           {"liquidity_pools"},
-					"export-contract-data":   {"contract_data"},
-					"export-contract-code":   {"contract_code"},
-					"export-config-settings": {"config_settings"},
-					"export-ttl":             {"ttl"},
-					"export-restored-keys":   {"restored_key"},
+					"export-accounts":             {"accounts", "signers"},
+					"export-balances":             {"claimable_balances"},
+					"export-offers":               {"offers"},
+					"export-trustlines":           {"trustlines"},
+					"export-pools":                {"liquidity_pools"},
+					"export-contract-data":        {"contract_data"},
+					"export-contract-code":        {"contract_code"},
+					"export-config-settings":      {"config_settings"},
+					"export-ttl":                  {"ttl"},
+					"export-restored-keys":        {"restored_key"},
+					"export-footprint-reads":      {"footprint_reads"},
+					"export-config-setting-diffs": {"config_setting_diffs"},
+					"export-ttl-expiry":           {"ttl_expiry"},
+					"export-contract-specs":       {"contract_specs"},
+					"export-pool-participants":    {"liquidity_pool_participants"},
 				}
 
 				for flagName, outputKeys := range exportMapping {
@@ -108,6 +180,19 @@ be exported.`,
 					}
 				}
 
+				if exports["export-footprint-reads"] {
+					for i, transaction := range batch.SorobanTransactions.Transactions {
+						reads, err := transform.TransformFootprintReads(transaction, batch.SorobanTransactions.LedgerHeaders[i])
+						if err != nil {
+							cmdLogger.LogError(fmt.Errorf("error transforming footprint reads of transaction %d in ledger %d: %s", transaction.Index, batch.SorobanTransactions.LedgerHeaders[i].Header.LedgerSeq, err))
+							continue
+						}
+						for _, read := range reads {
+							transformedOutputs["footprint_reads"] = append(transformedOutputs["footprint_reads"], read)
+						}
+					}
+				}
+
 				for entryType, changes := range batch.Changes {
 					if exports["export-restored-keys"] {
 						for i, change := range changes.Changes {
@@ -184,6 +269,19 @@ be exported.`,
 							transformedOutputs["offers"] = append(transformedOutputs["offers"], offer)
 						}
 					case xdr.LedgerEntryTypeTrustline:
+						if exports["export-pool-participants"] {
+							for i, change := range changes.Changes {
+								participant, ok, err := transform.TransformPoolParticipant(change, changes.LedgerHeaders[i])
+								if err != nil {
+									entry, _, _, _ := utils.ExtractEntryFromChange(change)
+									cmdLogger.LogError(fmt.Errorf("error transforming pool participant of trustline entry last updated at %d: %s", entry.LastModifiedLedgerSeq, err))
+									continue
+								}
+								if ok {
+									transformedOutputs["liquidity_pool_participants"] = append(transformedOutputs["liquidity_pool_participants"], participant)
+								}
+							}
+						}
 						if !exports["export-trustlines"] {
 							continue
 						}
@@ -230,6 +328,33 @@ be exported.`,
 							transformedOutputs["contract_data"] = append(transformedOutputs["contract_data"], contractData)
 						}
 					case xdr.LedgerEntryTypeContractCode:
+						if wasm != nil {
+							for _, change := range changes.Changes {
+								if change.Post == nil {
+									continue
+								}
+								contractCode := change.Post.Data.MustContractCode()
+								if err := wasm.store(contractCode.Hash.HexString(), contractCode.Code); err != nil {
+									cmdLogger.LogError(err)
+								}
+							}
+						}
+						if exports["export-contract-specs"] {
+							// The spec of a contract code doesn't change, so it is read when the code is uploaded
+							for i, change := range changes.Changes {
+								if change.ChangeType != xdr.LedgerEntryChangeTypeLedgerEntryCreated {
+									continue
+								}
+								specs, err := transform.TransformContractSpecs(change, changes.LedgerHeaders[i])
+								if err != nil {
+									cmdLogger.LogError(fmt.Errorf("error transforming contract spec of entry last updated at %d: %s", change.Post.LastModifiedLedgerSeq, err))
+									continue
+								}
+								for _, spec := range specs {
+									transformedOutputs["contract_specs"] = append(transformedOutputs["contract_specs"], spec)
+								}
+							}
+						}
 						if !exports["export-contract-code"] {
 							continue
 						}
@@ -243,6 +368,19 @@ be exported.`,
 							transformedOutputs["contract_code"] = append(transformedOutputs["contract_code"], contractCode)
 						}
 					case xdr.LedgerEntryTypeConfigSetting:
+						if exports["export-config-setting-diffs"] {
+							for i, change := range changes.Changes {
+								diffs, err := transform.TransformConfigSettingDiffs(change, changes.LedgerHeaders[i])
+								if err != nil {
+									entry, _, _, _ := utils.ExtractEntryFromChange(change)
+									cmdLogger.LogError(fmt.Errorf("error transforming config setting diffs of entry last updated at %d: %s", entry.LastModifiedLedgerSeq, err))
+									continue
+								}
+								for _, diff := range diffs {
+									transformedOutputs["config_setting_diffs"] = append(transformedOutputs["config_setting_diffs"], diff)
+								}
+							}
+						}
 						if !exports["export-config-settings"] {
 							continue
 						}
@@ -256,7 +394,7 @@ be exported.`,
 							transformedOutputs["config_settings"] = append(transformedOutputs["config_settings"], configSettings)
 						}
 					case xdr.LedgerEntryTypeTtl:
-						if !exports["export-ttl"] {
+						if !exports["export-ttl"] && !exports["export-ttl-expiry"] {
 							continue
 						}
 						for i, change := range changes.Changes {
@@ -266,11 +404,23 @@ be exported.`,
 								cmdLogger.LogError(fmt.Errorf("error transforming ttl entry last updated at %d: %s", entry.LastModifiedLedgerSeq, err))
 								continue
 							}
-							transformedOutputs["ttl"] = append(transformedOutputs["ttl"], ttl)
+							if exports["export-ttl"] {
+								transformedOutputs["ttl"] = append(transformedOutputs["ttl"], ttl)
+							}
+
+							// Removed ttls belong to deleted entries, which can't expire
+							if exports["export-ttl-expiry"] && !ttl.Deleted {
+								expiry := transform.TransformTtlExpiry(ttl)
+								if ttlExpiringWithin == 0 || expiry.LedgersUntilExpiry <= int64(ttlExpiringWithin) {
+									transformedOutputs["ttl_expiry"] = append(transformedOutputs["ttl_expiry"], expiry)
+								}
+							}
 						}
 					}
 				}
 
+				numExported = capTransformedOutputs(transformedOutputs, numExported, limit)
+
 				err := exportTransformedData(
 					batch.BatchStart,
 					batch.BatchEnd,
@@ -280,40 +430,129 @@ be exported.`,
 					cloudCredentials,
 					cloudStorageBucket,
 					cloudProvider,
+					uploadOptions,
+					notifier,
 					commonArgs.Extra,
+					commonArgs.StringifyLargeInts,
+					commonArgs.ClosedAtMillis,
 					commonArgs.WriteParquet,
+					commonArgs.FilenameTemplate,
+					env.Network,
+					commonArgs.ChunkToken,
 				)
 				if err != nil {
 					cmdLogger.LogError(err)
 					continue
 				}
+
+				if limitReached(numExported, limit) {
+					cmdLogger.Infof("Reached the limit of %d exported records at ledger %d", limit, batch.BatchEnd)
+					return
+				}
 			}
 		}
 	},
 }
 
+// capTransformedOutputs truncates the transformed outputs so that the total number of exported records,
+// including the numExported records from previous batches, does not exceed the limit. Resources are visited
+// in name order so that the kept records are deterministic. It returns the updated number of exported records.
+// engineOnlyFlags are the common flags that only the exports written by the export engine use. Ledger entry changes
+// are written without it, so these outputs, sinks and guards would be skipped without a warning.
+var engineOnlyFlags = []string{
+	"sort",
+	"write-orc",
+	"write-arrow",
+	"encrypt-fields",
+	"kms-key",
+	"sink",
+	"webhook-url",
+	"webhook-secret",
+	"webhook-batch-size",
+	"redis-url",
+	"redis-stream-maxlen",
+	"elasticsearch-url",
+	"elasticsearch-api-key",
+	"elasticsearch-batch-size",
+	"pubsub-topic",
+	"message-key-field",
+	"publish-batch-size",
+	"publish-max-in-flight",
+	"name-template",
+	"field-mapping",
+	"field-mapping-destination",
+	"max-record-bytes",
+	"oversized-records",
+	"sqlite",
+	"duckdb",
+	"duckdb-executable",
+	"run-metadata-table",
+	"bigquery-load-table",
+	"warn-unhandled-fields",
+	"dead-letter-dir",
+	"index-files",
+}
+
+// checkEngineOnlyFlags returns an error for the first flag in engineOnlyFlags that is set
+func checkEngineOnlyFlags(flags *pflag.FlagSet) error {
+	for _, name := range engineOnlyFlags {
+		if flags.Changed(name) {
+			return fmt.Errorf("%s is not supported when exporting ledger entry changes", name)
+		}
+	}
+
+	return nil
+}
+
+func capTransformedOutputs(transformedOutputs map[string][]interface{}, numExported int, limit int64) int {
+	resources := make([]string, 0, len(transformedOutputs))
+	for resource := range transformedOutputs {
+		resources = append(resources, resource)
+	}
+	sort.Strings(resources)
+
+	for _, resource := range resources {
+		output := transformedOutputs[resource]
+		if limit >= 0 {
+			remaining := max(int(limit)-numExported, 0)
+			if len(output) > remaining {
+				output = output[:remaining]
+				transformedOutputs[resource] = output
+			}
+		}
+		numExported += len(output)
+	}
+
+	return numExported
+}
+
 func exportTransformedData(
 	start, end uint32,
 	folderPath string,
 	parquetFolderPath string,
 	transformedOutput map[string][]interface{},
 	cloudCredentials, cloudStorageBucket, cloudProvider string,
+	uploadOptions utils.UploadOptions,
+	notifier *uploadNotifier,
 	extra map[string]string,
-	writeParquet bool) error {
+	stringifyLargeInts bool,
+	closedAtMillis bool,
+	writeParquet bool,
+	filenameTemplate, network, chunkToken string) error {
 
 	for resource, output := range transformedOutput {
 
 		// Filenames are typically exclusive of end point. This processor
 		// is different and we have to increment by 1 since the end batch number
 		// is included in this filename.
-		path := filepath.Join(folderPath, exportFilename(start, end+1, resource))
-		parquetPath := filepath.Join(parquetFolderPath, exportParquetFilename(start, end+1, resource))
+		path := filepath.Join(folderPath, exportFilename(filenameTemplate, network, chunkToken, start, end+1, resource, "txt"))
+		parquetPath := filepath.Join(parquetFolderPath, exportFilename(filenameTemplate, network, chunkToken, start, end+1, resource, "parquet"))
 		outFile := MustOutFile(path)
 		var transformedResource []transform.SchemaParquet
 		var parquetSchema interface{}
 		var skip bool
 		for _, o := range output {
-			_, err := ExportEntry(o, outFile, extra)
+			_, err := ExportEntry(o, outFile, extra, stringifyLargeInts, closedAtMillis)
 			if err != nil {
 				return err
 			}
@@ -361,15 +600,47 @@ func exportTransformedData(
 					transformedResource = append(transformedResource, v)
 					parquetSchema = new(transform.TtlOutputParquet)
 					skip = false
+				case transform.FootprintReadOutput:
+					transformedResource = append(transformedResource, v)
+					parquetSchema = new(transform.FootprintReadOutputParquet)
+					skip = false
+				case transform.ConfigSettingDiffOutput:
+					transformedResource = append(transformedResource, v)
+					parquetSchema = new(transform.ConfigSettingDiffOutputParquet)
+					skip = false
+				case transform.TtlExpiryOutput:
+					transformedResource = append(transformedResource, v)
+					parquetSchema = new(transform.TtlExpiryOutputParquet)
+					skip = false
+				case transform.ContractSpecOutput:
+					transformedResource = append(transformedResource, v)
+					parquetSchema = new(transform.ContractSpecOutputParquet)
+					skip = false
+				case transform.PoolParticipantOutput:
+					transformedResource = append(transformedResource, v)
+					parquetSchema = new(transform.PoolParticipantOutputParquet)
+					skip = false
 				}
 			}
 		}
 
-		MaybeUpload(cloudCredentials, cloudStorageBucket, cloudProvider, path)
+		// The files of a batch hold the changes of the ledgers of the batch, which is the range of their notifications
+		notifyUpload := func(uploadedPath, format string) {
+			if notifier == nil || uploadedPath == "" {
+				return
+			}
+			err := notifier.notify(uploadNotification{Path: uploadedPath, Dataset: resource, Format: format, Network: network,
+				StartLedger: start, EndLedger: end, NumRecords: len(output), ChunkToken: chunkToken})
+			if err != nil {
+				cmdLogger.Fatal(err)
+			}
+		}
+
+		notifyUpload(MaybeUpload(cloudCredentials, cloudStorageBucket, cloudProvider, path, uploadOptions), "json")
 
 		if !skip && writeParquet {
-			WriteParquet(transformedResource, parquetPath, parquetSchema)
-			MaybeUpload(cloudCredentials, cloudStorageBucket, cloudProvider, parquetPath)
+			WriteParquet(transformedResource, parquetPath, parquetSchema, nil)
+			notifyUpload(MaybeUpload(cloudCredentials, cloudStorageBucket, cloudProvider, parquetPath, uploadOptions), "parquet")
 		}
 	}
 
@@ -382,6 +653,8 @@ func init() {
 	utils.AddCoreFlags(exportLedgerEntryChangesCmd.Flags(), "changes_output/")
 	utils.AddExportTypeFlags(exportLedgerEntryChangesCmd.Flags())
 	utils.AddCloudStorageFlags(exportLedgerEntryChangesCmd.Flags())
+	exportLedgerEntryChangesCmd.Flags().String("wasm-output", "", "If set, the wasm of every created or updated contract code is written to this folder as <hash>.wasm, and uploaded with the other outputs. Wasm that is already in the folder or the bucket is skipped.")
+	exportLedgerEntryChangesCmd.Flags().Uint32("ttl-expiring-within", 0, "If set, ttl_expiry only has the entries that expire within this many ledgers, including the expired ones. 0 exports every entry.")
 
 	exportLedgerEntryChangesCmd.MarkFlagRequired("start-ledger")
 	/*
@@ -390,7 +663,7 @@ func init() {
 			end-ledger: the ledger sequence number for the end of the export range
 
 			output-folder: folder that will contain the output files
-			limit: maximum number of changes to export in a given batch; if negative then everything gets exported
+			limit: maximum number of changes to export across all batches; if negative then everything gets exported
 			batch-size: size of the export batches
 
 			core-executable: path to stellar-core executable
@@ -3,6 +3,11 @@ package cmd
 import (
 	"fmt"
 	"testing"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stellar/stellar-etl/v2/internal/utils"
 )
 
 const coreExecutablePath = "../stellar-core/src/stellar-core"
@@ -148,3 +153,61 @@ func TestExportChanges(t *testing.T) {
 		RunCLITest(t, test, "testdata/changes/", "", false)
 	}
 }
+
+func TestCapTransformedOutputs(t *testing.T) {
+	newOutputs := func() map[string][]interface{} {
+		return map[string][]interface{}{
+			"offers":   {1, 2, 3},
+			"accounts": {1, 2},
+			"ttl":      {},
+		}
+	}
+
+	tests := []struct {
+		name        string
+		numExported int
+		limit       int64
+		wantCount   int
+		wantLengths map[string]int
+	}{
+		{"no limit", 0, -1, 5, map[string]int{"accounts": 2, "offers": 3, "ttl": 0}},
+		{"limit within batch", 0, 3, 3, map[string]int{"accounts": 2, "offers": 1, "ttl": 0}},
+		{"limit reached by previous batches", 4, 4, 4, map[string]int{"accounts": 0, "offers": 0, "ttl": 0}},
+		{"limit larger than batch", 1, 10, 6, map[string]int{"accounts": 2, "offers": 3, "ttl": 0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outputs := newOutputs()
+			count := capTransformedOutputs(outputs, tt.numExported, tt.limit)
+			assert.Equal(t, tt.wantCount, count)
+			for resource, wantLength := range tt.wantLengths {
+				assert.Len(t, outputs[resource], wantLength)
+			}
+		})
+	}
+}
+
+func TestCheckEngineOnlyFlags(t *testing.T) {
+	newFlags := func() *pflag.FlagSet {
+		flags := pflag.NewFlagSet("export_ledger_entry_changes", pflag.ContinueOnError)
+		utils.AddCommonFlags(flags)
+		return flags
+	}
+
+	for _, name := range engineOnlyFlags {
+		assert.NotNil(t, newFlags().Lookup(name), name)
+	}
+
+	flags := newFlags()
+	assert.NoError(t, flags.Parse([]string{"--write-parquet", "--end-ledger", "10"}))
+	assert.NoError(t, checkEngineOnlyFlags(flags))
+
+	flags = newFlags()
+	assert.NoError(t, flags.Parse([]string{"--encrypt-fields", "account_id", "--kms-key", "gcp-kms://key"}))
+	assert.EqualError(t, checkEngineOnlyFlags(flags), "encrypt-fields is not supported when exporting ledger entry changes")
+
+	flags = newFlags()
+	assert.NoError(t, flags.Parse([]string{"--sink", "file"}))
+	assert.EqualError(t, checkEngineOnlyFlags(flags), "sink is not supported when exporting ledger entry changes")
+}
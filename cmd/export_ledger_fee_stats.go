@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/stellar/go-stellar-sdk/ingest"
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stellar/stellar-etl/v2/internal/input"
+	"github.com/stellar/stellar-etl/v2/internal/transform"
+	"github.com/stellar/stellar-etl/v2/internal/utils"
+)
+
+var ledgerFeeStatsCmd = &cobra.Command{
+	Use:   "export_ledger_fee_stats",
+	Short: "Exports the inclusion fee metrics of ledgers over a specified range.",
+	Long: `Exports the inclusion metrics of every ledger within the specified range to an output file: the number of operations
+compared to the max tx set size, the minimum, median and maximum inclusion fee charged, and whether the ledger was surge priced.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmdLogger.SetLevel(logrus.InfoLevel)
+		cmdArgs := utils.MustFlags(cmd.Flags(), cmdLogger)
+
+		// TODO: https://stellarorg.atlassian.net/browse/HUBBLE-386 GetEnvironmentDetails should be refactored
+		commonArgs := utils.MustCommonFlags(cmd.Flags(), cmdLogger)
+		env := utils.GetEnvironmentDetails(commonArgs)
+
+		engine := newExportEngine(cmdArgs, env, new(transform.LedgerFeeStatsOutput), new(transform.LedgerFeeStatsOutputParquet))
+		engine.run(func(lcm xdr.LedgerCloseMeta) error {
+			transformInputs, err := input.GetLedgerTransactions(lcm, env)
+			if err != nil {
+				return err
+			}
+
+			transactions := make([]ingest.LedgerTransaction, len(transformInputs))
+			for i, transformInput := range transformInputs {
+				transactions[i] = transformInput.Transaction
+			}
+
+			engine.attempt()
+			transformed, err := transform.TransformLedgerFeeStats(lcm, transactions)
+			if err != nil {
+				engine.fail(fmt.Errorf("could not transform fee stats of ledger %d: %v", utils.GetLedgerSequence(lcm), err))
+				return nil
+			}
+
+			engine.export(transformed)
+			return nil
+		})
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(ledgerFeeStatsCmd)
+	utils.AddCommonFlags(ledgerFeeStatsCmd.Flags())
+	utils.AddArchiveFlags("ledger_fee_stats", ledgerFeeStatsCmd.Flags())
+	utils.AddCloudStorageFlags(ledgerFeeStatsCmd.Flags())
+
+	ledgerFeeStatsCmd.MarkFlagRequired("start-ledger")
+	ledgerFeeStatsCmd.MarkFlagRequired("end-ledger")
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
+	"github.com/stellar/go-stellar-sdk/xdr"
 	"github.com/stellar/stellar-etl/v2/internal/input"
 	"github.com/stellar/stellar-etl/v2/internal/transform"
 	"github.com/stellar/stellar-etl/v2/internal/utils"
@@ -13,47 +14,41 @@ import (
 var ledgerTransactionCmd = &cobra.Command{
 	Use:   "export_ledger_transaction",
 	Short: "Exports the ledger_transaction transaction data over a specified range.",
-	Long:  `Exports the ledger_transaction transaction data over a specified range to an output file.`,
+	Long: `Exports the ledger_transaction transaction data over a specified range to an output file.
+
+If the end-ledger is omitted, then the export will continue running and exporting transactions as new ledgers are
+confirmed by the Stellar network.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		cmdLogger.SetLevel(logrus.InfoLevel)
+		cmdArgs := utils.MustFlags(cmd.Flags(), cmdLogger)
 		commonArgs := utils.MustCommonFlags(cmd.Flags(), cmdLogger)
-		cmdLogger.StrictExport = commonArgs.StrictExport
-		startNum, path, _, limit := utils.MustArchiveFlags(cmd.Flags(), cmdLogger)
-		cloudStorageBucket, cloudCredentials, cloudProvider := utils.MustCloudStorageFlags(cmd.Flags(), cmdLogger)
 		env := utils.GetEnvironmentDetails(commonArgs)
 
-		ledgerTransaction, err := input.GetTransactions(startNum, commonArgs.EndNum, limit, env, commonArgs.UseCaptiveCore)
-		if err != nil {
-			cmdLogger.Fatal("could not read ledger_transaction: ", err)
-		}
-
-		outFile := MustOutFile(path)
-		numFailures := 0
-		totalNumBytes := 0
-		for _, transformInput := range ledgerTransaction {
-			transformed, err := transform.TransformLedgerTransaction(transformInput.Transaction, transformInput.LedgerHistory)
+		engine := newExportEngine(cmdArgs, env, new(transform.LedgerTransactionOutput), nil)
+		engine.run(func(lcm xdr.LedgerCloseMeta) error {
+			ledgerTransaction, err := input.GetLedgerTransactions(lcm, env)
 			if err != nil {
-				ledgerSeq := transformInput.LedgerHistory.Header.LedgerSeq
-				cmdLogger.LogError(fmt.Errorf("could not transform ledger_transaction transaction %d in ledger %d: ", transformInput.Transaction.Index, ledgerSeq))
-				numFailures += 1
-				continue
+				return err
 			}
 
-			numBytes, err := ExportEntry(transformed, outFile, commonArgs.Extra)
-			if err != nil {
-				cmdLogger.LogError(fmt.Errorf("could not export transaction: %v", err))
-				numFailures += 1
-				continue
-			}
-			totalNumBytes += numBytes
-		}
+			for _, transformInput := range ledgerTransaction {
+				if engine.limitReached() {
+					break
+				}
 
-		outFile.Close()
-		cmdLogger.Info("Number of bytes written: ", totalNumBytes)
+				engine.attempt()
+				transformed, err := transform.TransformLedgerTransaction(transformInput.Transaction, transformInput.LedgerHistory)
+				if err != nil {
+					ledgerSeq := transformInput.LedgerHistory.Header.LedgerSeq
+					engine.fail(fmt.Errorf("could not transform ledger_transaction transaction %d in ledger %d: %v", transformInput.Transaction.Index, ledgerSeq, err))
+					continue
+				}
 
-		PrintTransformStats(len(ledgerTransaction), numFailures)
+				engine.export(transformed)
+			}
 
-		MaybeUpload(cloudCredentials, cloudStorageBucket, cloudProvider, path)
+			return nil
+		})
 	},
 }
 
@@ -62,12 +57,11 @@ func init() {
 	utils.AddCommonFlags(ledgerTransactionCmd.Flags())
 	utils.AddArchiveFlags("ledger_transaction", ledgerTransactionCmd.Flags())
 	utils.AddCloudStorageFlags(ledgerTransactionCmd.Flags())
-	ledgerTransactionCmd.MarkFlagRequired("end-ledger")
 
 	/*
 		Current flags:
 			start-ledger: the ledger sequence number for the beginning of the export period
-			end-ledger: the ledger sequence number for the end of the export range (*required)
+			end-ledger: the ledger sequence number for the end of the export range; if omitted, ledgers are exported continuously
 
 			limit: maximum number of ledger_transaction to export
 				TODO: measure a good default value that ensures all ledger_transaction within a 5 minute period will be exported with a single call
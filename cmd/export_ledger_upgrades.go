@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stellar/stellar-etl/v2/internal/transform"
+	"github.com/stellar/stellar-etl/v2/internal/utils"
+)
+
+var ledgerUpgradesCmd = &cobra.Command{
+	Use:   "export_ledger_upgrades",
+	Short: "Exports the ledger upgrades over a specified range.",
+	Long: `Exports the upgrades applied to the ledger header (protocol version, base fee, base reserve, max tx set size,
+flags and soroban config upgrades) within the specified range to an output file. Each upgrade is exported as a separate JSON object.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmdLogger.SetLevel(logrus.InfoLevel)
+		cmdArgs := utils.MustFlags(cmd.Flags(), cmdLogger)
+
+		// TODO: https://stellarorg.atlassian.net/browse/HUBBLE-386 GetEnvironmentDetails should be refactored
+		commonArgs := utils.MustCommonFlags(cmd.Flags(), cmdLogger)
+		env := utils.GetEnvironmentDetails(commonArgs)
+
+		engine := newExportEngine(cmdArgs, env, new(transform.LedgerUpgradeOutput), new(transform.LedgerUpgradeOutputParquet))
+		engine.run(func(lcm xdr.LedgerCloseMeta) error {
+			// Most ledgers have no upgrades, so the limit is applied to the exported upgrades instead of the input
+			engine.attempt()
+			transformed, err := transform.TransformLedgerUpgrades(lcm)
+			if err != nil {
+				engine.fail(fmt.Errorf("could not transform upgrades in ledger %d: %v", utils.GetLedgerSequence(lcm), err))
+				return nil
+			}
+
+			for _, upgrade := range transformed {
+				engine.export(upgrade)
+			}
+
+			return nil
+		})
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(ledgerUpgradesCmd)
+	utils.AddCommonFlags(ledgerUpgradesCmd.Flags())
+	utils.AddArchiveFlags("ledger_upgrades", ledgerUpgradesCmd.Flags())
+	utils.AddCloudStorageFlags(ledgerUpgradesCmd.Flags())
+
+	ledgerUpgradesCmd.MarkFlagRequired("start-ledger")
+	ledgerUpgradesCmd.MarkFlagRequired("end-ledger")
+}
@@ -16,61 +16,46 @@ var ledgersCmd = &cobra.Command{
 	Long:  `Exports ledger data within the specified range to an output file. Encodes ledgers as JSON objects and exports them to the output file.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		cmdLogger.SetLevel(logrus.InfoLevel)
+		cmdArgs := utils.MustFlags(cmd.Flags(), cmdLogger)
 		commonArgs := utils.MustCommonFlags(cmd.Flags(), cmdLogger)
-		cmdLogger.StrictExport = commonArgs.StrictExport
-		startNum, path, parquetPath, limit := utils.MustArchiveFlags(cmd.Flags(), cmdLogger)
-		cloudStorageBucket, cloudCredentials, cloudProvider := utils.MustCloudStorageFlags(cmd.Flags(), cmdLogger)
 		env := utils.GetEnvironmentDetails(commonArgs)
 
-		var ledgers []utils.HistoryArchiveLedgerAndLCM
-		var err error
+		noAlign, err := cmd.Flags().GetBool("no-align")
+		if err != nil {
+			cmdLogger.Fatal("could not get no-align: ", err)
+		}
 
-		if commonArgs.UseCaptiveCore {
-			ledgers, err = input.GetLedgersHistoryArchive(startNum, commonArgs.EndNum, limit, env, commonArgs.UseCaptiveCore)
+		var ledgers []utils.HistoryArchiveLedgerAndLCM
+		if cmdArgs.UseCaptiveCore {
+			if err := alignArchiveRange(&cmdArgs, noAlign); err != nil {
+				cmdLogger.Fatal(err)
+			}
+			ledgers, err = input.GetLedgersHistoryArchive(cmdArgs.StartNum, cmdArgs.EndNum, cmdArgs.Limit, env, cmdArgs.UseCaptiveCore)
 		} else {
-			ledgers, err = input.GetLedgers(startNum, commonArgs.EndNum, limit, env, commonArgs.UseCaptiveCore)
+			ledgers, err = input.GetLedgers(cmdArgs.StartNum, cmdArgs.EndNum, cmdArgs.Limit, env, cmdArgs.UseCaptiveCore)
 		}
 		if err != nil {
 			cmdLogger.Fatal("could not read ledgers: ", err)
 		}
 
-		outFile := MustOutFile(path)
-
-		numFailures := 0
-		totalNumBytes := 0
-		var transformedLedgers []transform.SchemaParquet
-		for i, ledger := range ledgers {
-			transformed, err := transform.TransformLedger(ledger.Ledger, ledger.LCM)
+		// The history archive ledgers are read up front, so only the writing is shared with the other exports
+		engine := newExportEngine(cmdArgs, env, new(transform.LedgerOutput), new(transform.LedgerOutputParquet))
+		for _, ledger := range ledgers {
+			engine.attempt()
+			ledgerSeq := utils.GetLedgerSequence(ledger.LCM)
+			engine.beginLedger(ledgerSeq)
+			timed := engine.timeTransform(ledgerTransform)
+			transformed, err := transform.TransformLedger(ledger.Ledger, ledger.LCM, env.NetworkPassphrase)
+			timed()
 			if err != nil {
-				cmdLogger.LogError(fmt.Errorf("could not json transform ledger %d: %s", startNum+uint32(i), err))
-				numFailures += 1
+				engine.fail(fmt.Errorf("could not json transform ledger %d: %s", ledgerSeq, err))
 				continue
 			}
 
-			numBytes, err := ExportEntry(transformed, outFile, commonArgs.Extra)
-			if err != nil {
-				cmdLogger.LogError(fmt.Errorf("could not export ledger %d: %s", startNum+uint32(i), err))
-				numFailures += 1
-				continue
-			}
-			totalNumBytes += numBytes
-
-			if commonArgs.WriteParquet {
-				transformedLedgers = append(transformedLedgers, transformed)
-			}
+			engine.export(transformed)
 		}
 
-		outFile.Close()
-		cmdLogger.Info("Number of bytes written: ", totalNumBytes)
-
-		PrintTransformStats(len(ledgers), numFailures)
-
-		MaybeUpload(cloudCredentials, cloudStorageBucket, cloudProvider, path)
-
-		if commonArgs.WriteParquet {
-			MaybeUpload(cloudCredentials, cloudStorageBucket, cloudProvider, parquetPath)
-			WriteParquet(transformedLedgers, parquetPath, new(transform.LedgerOutputParquet))
-		}
+		engine.finish()
 	},
 }
 
@@ -79,6 +64,7 @@ func init() {
 	utils.AddCommonFlags(ledgersCmd.Flags())
 	utils.AddArchiveFlags("ledgers", ledgersCmd.Flags())
 	utils.AddCloudStorageFlags(ledgersCmd.Flags())
+	ledgersCmd.Flags().Bool("no-align", false, "If set, a range that is not aligned to checkpoints stops the export when reading from the history archives, instead of being widened to the checkpoints that contain it.")
 	ledgersCmd.MarkFlagRequired("end-ledger")
 	/*
 		Current flags:
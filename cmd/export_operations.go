@@ -5,6 +5,7 @@ import (
 
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
+	"github.com/stellar/go-stellar-sdk/xdr"
 	"github.com/stellar/stellar-etl/v2/internal/input"
 	"github.com/stellar/stellar-etl/v2/internal/transform"
 	"github.com/stellar/stellar-etl/v2/internal/utils"
@@ -16,54 +17,143 @@ var operationsCmd = &cobra.Command{
 	Long:  `Exports the operations data over a specified range. Each operation is an individual command that mutates the Stellar ledger.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		cmdLogger.SetLevel(logrus.InfoLevel)
+		cmdArgs := utils.MustFlags(cmd.Flags(), cmdLogger)
 		commonArgs := utils.MustCommonFlags(cmd.Flags(), cmdLogger)
-		cmdLogger.StrictExport = commonArgs.StrictExport
-		startNum, path, parquetPath, limit := utils.MustArchiveFlags(cmd.Flags(), cmdLogger)
-		cloudStorageBucket, cloudCredentials, cloudProvider := utils.MustCloudStorageFlags(cmd.Flags(), cmdLogger)
 		env := utils.GetEnvironmentDetails(commonArgs)
 
-		operations, err := input.GetOperations(startNum, commonArgs.EndNum, limit, env, commonArgs.UseCaptiveCore)
+		flattenDetails, err := cmd.Flags().GetBool("flatten-details")
 		if err != nil {
-			cmdLogger.Fatal("could not read operations: ", err)
+			cmdLogger.Fatal("could not get flatten-details flag: ", err)
 		}
 
-		outFile := MustOutFile(path)
-		numFailures := 0
-		totalNumBytes := 0
-		var transformedOps []transform.SchemaParquet
-		for _, transformInput := range operations {
-			transformed, err := transform.TransformOperation(transformInput.Operation, transformInput.OperationIndex, transformInput.Transaction, transformInput.LedgerSeqNum, transformInput.LedgerCloseMeta, env.NetworkPassphrase)
+		decodeManageData, err := cmd.Flags().GetBool("decode-manage-data")
+		if err != nil {
+			cmdLogger.Fatal("could not get decode-manage-data flag: ", err)
+		}
+
+		noDetails, err := cmd.Flags().GetBool("no-details")
+		if err != nil {
+			cmdLogger.Fatal("could not get no-details flag: ", err)
+		}
+
+		successfulOnly, err := cmd.Flags().GetBool("successful-only")
+		if err != nil {
+			cmdLogger.Fatal("could not get successful-only flag: ", err)
+		}
+
+		failedOnly, err := cmd.Flags().GetBool("failed-only")
+		if err != nil {
+			cmdLogger.Fatal("could not get failed-only flag: ", err)
+		}
+		if successfulOnly && failedOnly {
+			cmdLogger.Fatal("successful-only and failed-only can't be used together")
+		}
+
+		expandSubInvocations, err := cmd.Flags().GetBool("expand-sub-invocations")
+		if err != nil {
+			cmdLogger.Fatal("could not get expand-sub-invocations flag: ", err)
+		}
+		if expandSubInvocations && noDetails {
+			cmdLogger.Fatal("expand-sub-invocations and no-details can't be used together")
+		}
+
+		var outputSchema, parquetSchema interface{} = new(transform.OperationOutput), new(transform.OperationOutputParquet)
+		if flattenDetails {
+			outputSchema, parquetSchema = new(transform.FlattenedOperationOutput), new(transform.FlattenedOperationOutputParquet)
+		}
+
+		kindFilter := mustTransactionKindFilter(cmd.Flags())
+		accountFilter := mustAccountFilter(cmd.Flags())
+
+		engine := newExportEngine(cmdArgs, env, outputSchema, parquetSchema)
+		engine.run(func(lcm xdr.LedgerCloseMeta) error {
+			skip, err := kindFilter.skipLedger(lcm)
 			if err != nil {
-				txIndex := transformInput.Transaction.Index
-				cmdLogger.LogError(fmt.Errorf("could not transform operation %d in transaction %d in ledger %d: %v", transformInput.OperationIndex, txIndex, transformInput.LedgerSeqNum, err))
-				numFailures += 1
-				continue
+				return err
+			}
+			if skip {
+				return nil
 			}
 
-			numBytes, err := ExportEntry(transformed, outFile, commonArgs.Extra)
+			operations, err := input.GetLedgerOperations(lcm, env)
 			if err != nil {
-				cmdLogger.LogError(fmt.Errorf("could not export operation: %v", err))
-				numFailures += 1
-				continue
+				return err
 			}
-			totalNumBytes += numBytes
 
-			if commonArgs.WriteParquet {
-				transformedOps = append(transformedOps, transformed)
+			// The operations of a transaction are transformed one after the other, so they share a cache
+			var transactionCache *transform.TransactionCache
+			for _, transformInput := range operations {
+				if engine.limitReached() {
+					break
+				}
+
+				if kindFilter.skipTransaction(transformInput.Transaction) {
+					continue
+				}
+
+				if successfulOnly && !transformInput.Transaction.Result.Successful() {
+					continue
+				}
+				if failedOnly && transformInput.Transaction.Result.Successful() {
+					continue
+				}
+
+				skipAccounts, err := skipFilteredAccounts(accountFilter, transformInput.Transaction)
+				if err != nil {
+					return err
+				}
+				if skipAccounts {
+					continue
+				}
+
+				engine.attempt()
+				timed := engine.timeTransform(transform.OperationTypeName(transformInput.Operation))
+				var transformed transform.OperationOutput
+				if noDetails {
+					transformed, err = transform.TransformOperationWithoutDetails(transformInput.Operation, transformInput.OperationIndex, transformInput.Transaction, transformInput.LedgerSeqNum, transformInput.LedgerCloseMeta)
+				} else {
+					if transactionCache == nil || transactionCache.TransactionIndex() != transformInput.Transaction.Index {
+						transactionCache = transform.NewTransactionCache(transformInput.Transaction)
+					}
+					transformed, err = transactionCache.TransformOperation(transformInput.Operation, transformInput.OperationIndex, transformInput.LedgerSeqNum, transformInput.LedgerCloseMeta, env.NetworkPassphrase)
+				}
+				timed()
+				if err != nil {
+					txIndex := transformInput.Transaction.Index
+					engine.fail(fmt.Errorf("could not transform operation %d in transaction %d in ledger %d: %v", transformInput.OperationIndex, txIndex, transformInput.LedgerSeqNum, err))
+					continue
+				}
+
+				if decodeManageData {
+					if err := transform.DecodeManageDataValue(transformed); err != nil {
+						engine.fail(fmt.Errorf("could not decode operation %d in transaction %d in ledger %d: %v", transformInput.OperationIndex, transformInput.Transaction.Index, transformInput.LedgerSeqNum, err))
+						continue
+					}
+				}
+
+				exportOperation := func(operation transform.OperationOutput) {
+					if flattenDetails {
+						engine.export(transform.FlattenedOperationOutput{OperationOutput: operation})
+						return
+					}
+					engine.export(operation)
+				}
+				exportOperation(transformed)
+
+				if expandSubInvocations {
+					subInvocations, err := transform.TransformSubInvocations(transformed, transformInput.Operation)
+					if err != nil {
+						engine.fail(fmt.Errorf("could not expand the sub-invocations of operation %d in transaction %d in ledger %d: %v", transformInput.OperationIndex, transformInput.Transaction.Index, transformInput.LedgerSeqNum, err))
+						continue
+					}
+					for _, subInvocation := range subInvocations {
+						exportOperation(subInvocation)
+					}
+				}
 			}
-		}
-
-		outFile.Close()
-		cmdLogger.Info("Number of bytes written: ", totalNumBytes)
 
-		PrintTransformStats(len(operations), numFailures)
-
-		MaybeUpload(cloudCredentials, cloudStorageBucket, cloudProvider, path)
-
-		if commonArgs.WriteParquet {
-			MaybeUpload(cloudCredentials, cloudStorageBucket, cloudProvider, parquetPath)
-			WriteParquet(transformedOps, parquetPath, new(transform.OperationOutputParquet))
-		}
+			return nil
+		})
 	},
 }
 
@@ -72,6 +162,14 @@ func init() {
 	utils.AddCommonFlags(operationsCmd.Flags())
 	utils.AddArchiveFlags("operations", operationsCmd.Flags())
 	utils.AddCloudStorageFlags(operationsCmd.Flags())
+	addAccountFilterFlags(operationsCmd.Flags())
+	addTransactionKindFlags(operationsCmd.Flags())
+	operationsCmd.Flags().Bool("flatten-details", false, "If set, the well-known operation details are written to typed columns in the parquet output.")
+	operationsCmd.Flags().Bool("decode-manage-data", false, "If set, the values of manage_data operations are also written decoded, as text when they are valid UTF-8 and as hex otherwise.")
+	operationsCmd.Flags().Bool("no-details", false, "If set, the details of the operations are not computed, which is much faster when only their ids, types and accounts are needed.")
+	operationsCmd.Flags().Bool("successful-only", false, "If set, records of failed transactions are not exported.")
+	operationsCmd.Flags().Bool("failed-only", false, "If set, only the records of failed transactions are exported.")
+	operationsCmd.Flags().Bool("expand-sub-invocations", false, "If set, every contract call authorized by an invoke_host_function operation is also exported as a row, with the id of the operation as its parent_operation_id.")
 	operationsCmd.MarkFlagRequired("end-ledger")
 
 	/*
@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stellar/stellar-etl/v2/internal/input"
+	"github.com/stellar/stellar-etl/v2/internal/transform"
+	"github.com/stellar/stellar-etl/v2/internal/utils"
+)
+
+var participantsCmd = &cobra.Command{
+	Use:   "export_participants",
+	Short: "Exports the participants of operations over a specified range.",
+	Long: `Exports the accounts and contracts taking part in operations within the specified range to an output file.
+Each participant is exported with its role in the operation, such as source, destination or authorizer.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmdLogger.SetLevel(logrus.InfoLevel)
+		cmdArgs := utils.MustFlags(cmd.Flags(), cmdLogger)
+
+		// TODO: https://stellarorg.atlassian.net/browse/HUBBLE-386 GetEnvironmentDetails should be refactored
+		commonArgs := utils.MustCommonFlags(cmd.Flags(), cmdLogger)
+		env := utils.GetEnvironmentDetails(commonArgs)
+
+		engine := newExportEngine(cmdArgs, env, new(transform.ParticipantOutput), new(transform.ParticipantOutputParquet))
+		engine.run(func(lcm xdr.LedgerCloseMeta) error {
+			transactions, err := input.GetLedgerTransactions(lcm, env)
+			if err != nil {
+				return err
+			}
+
+			// Operations have any number of participants, so the limit is applied to the exported participants instead of the input
+			for _, transformInput := range transactions {
+				if engine.limitReached() {
+					break
+				}
+
+				engine.attempt()
+				ledgerSeq := uint32(transformInput.LedgerHistory.Header.LedgerSeq)
+				transformed, err := transform.TransformParticipants(transformInput.Transaction, ledgerSeq, transformInput.LedgerCloseMeta, env.NetworkPassphrase)
+				if err != nil {
+					engine.fail(fmt.Errorf("could not transform participants in transaction %d in ledger %d: %v", transformInput.Transaction.Index, ledgerSeq, err))
+					continue
+				}
+
+				for _, participant := range transformed {
+					engine.export(participant)
+				}
+			}
+
+			return nil
+		})
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(participantsCmd)
+	utils.AddCommonFlags(participantsCmd.Flags())
+	utils.AddArchiveFlags("participants", participantsCmd.Flags())
+	utils.AddCloudStorageFlags(participantsCmd.Flags())
+
+	participantsCmd.MarkFlagRequired("start-ledger")
+	participantsCmd.MarkFlagRequired("end-ledger")
+}
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stellar/stellar-etl/v2/internal/toid"
+
+	"github.com/spf13/cobra"
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stellar/stellar-etl/v2/internal/input"
+	"github.com/stellar/stellar-etl/v2/internal/transform"
+	"github.com/stellar/stellar-etl/v2/internal/utils"
+)
+
+var pathPaymentHopsCmd = &cobra.Command{
+	Use:   "export_path_payment_hops",
+	Short: "Exports the path payment hop data",
+	Long: `Exports the offers and liquidity pools crossed by path payments within the specified range to an output file.
+Each crossed offer or liquidity pool is exported as a separate JSON object tagged with its hop along the payment path.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmdLogger.SetLevel(logrus.InfoLevel)
+		cmdArgs := utils.MustFlags(cmd.Flags(), cmdLogger)
+		commonArgs := utils.MustCommonFlags(cmd.Flags(), cmdLogger)
+		env := utils.GetEnvironmentDetails(commonArgs)
+
+		engine := newExportEngine(cmdArgs, env, new(transform.PathPaymentHopOutput), new(transform.PathPaymentHopOutputParquet))
+		engine.run(func(lcm xdr.LedgerCloseMeta) error {
+			// Path payments are read through the trade input, which includes every successful operation that can cross offers
+			trades, err := input.GetLedgerTrades(lcm, env)
+			if err != nil {
+				return err
+			}
+
+			// Operations can cross any number of offers, so the limit is applied to the exported hops instead of the input
+			for _, tradeInput := range trades {
+				if engine.limitReached() {
+					break
+				}
+
+				engine.attempt()
+				hops, err := transform.TransformPathPaymentHops(tradeInput.OperationIndex, tradeInput.OperationHistoryID, tradeInput.Transaction, tradeInput.CloseTime)
+				if err != nil {
+					parsedID := toid.Parse(tradeInput.OperationHistoryID)
+					engine.fail(fmt.Errorf("from ledger %d, transaction %d, operation %d: %v", parsedID.LedgerSequence, parsedID.TransactionOrder, parsedID.OperationOrder, err))
+					continue
+				}
+
+				for _, transformed := range hops {
+					engine.export(transformed)
+				}
+			}
+
+			return nil
+		})
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(pathPaymentHopsCmd)
+	utils.AddCommonFlags(pathPaymentHopsCmd.Flags())
+	utils.AddArchiveFlags("path_payment_hops", pathPaymentHopsCmd.Flags())
+	utils.AddCloudStorageFlags(pathPaymentHopsCmd.Flags())
+	pathPaymentHopsCmd.MarkFlagRequired("end-ledger")
+}
@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stellar/stellar-etl/v2/internal/input"
+	"github.com/stellar/stellar-etl/v2/internal/transform"
+	"github.com/stellar/stellar-etl/v2/internal/utils"
+)
+
+var restoreCandidatesCmd = &cobra.Command{
+	Use:   "export_restore_candidates",
+	Short: "Exports the archived entries that soroban transactions tried to use over a specified range.",
+	Long: `Exports the persistent contract data and contract code entries that soroban transactions tried to use while they
+were archived, so operators can restore them. The entries come from the footprints of the transactions that failed
+because an entry was archived, and from the entries that transactions declared as archived. The ttls changed since the
+start of the range are used to leave out the entries that are known to be live.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmdLogger.SetLevel(logrus.InfoLevel)
+		cmdArgs := utils.MustFlags(cmd.Flags(), cmdLogger)
+
+		// TODO: https://stellarorg.atlassian.net/browse/HUBBLE-386 GetEnvironmentDetails should be refactored
+		commonArgs := utils.MustCommonFlags(cmd.Flags(), cmdLogger)
+		env := utils.GetEnvironmentDetails(commonArgs)
+
+		// liveUntil has the live until ledger of every ttl changed so far, keyed by the ledger key hash of its entry
+		liveUntil := map[string]uint32{}
+
+		engine := newExportEngine(cmdArgs, env, new(transform.RestoreCandidateOutput), new(transform.RestoreCandidateOutputParquet))
+		engine.run(func(lcm xdr.LedgerCloseMeta) error {
+			transactions, err := input.GetLedgerTransactions(lcm, env)
+			if err != nil {
+				return err
+			}
+
+			for _, transformInput := range transactions {
+				if !transformInput.Transaction.IsSorobanTx() {
+					continue
+				}
+				ledgerSeq := transformInput.LedgerHistory.Header.LedgerSeq
+
+				if !engine.limitReached() {
+					engine.attempt()
+					transformed, err := transform.TransformRestoreCandidates(transformInput.Transaction, transformInput.LedgerHistory, liveUntil)
+					if err != nil {
+						engine.fail(fmt.Errorf("could not transform restore candidates of transaction %d in ledger %d: %v", transformInput.Transaction.Index, ledgerSeq, err))
+					} else {
+						for _, candidate := range transformed {
+							engine.export(candidate)
+						}
+					}
+				}
+
+				// The ttls are updated after the transaction, since they are the state it was applied to
+				ttls, err := transform.TransactionTtls(transformInput.Transaction)
+				if err != nil {
+					return fmt.Errorf("could not read ttl changes of transaction %d in ledger %d: %v", transformInput.Transaction.Index, ledgerSeq, err)
+				}
+				for keyHash, liveUntilLedger := range ttls {
+					if liveUntilLedger == 0 {
+						delete(liveUntil, keyHash)
+						continue
+					}
+					liveUntil[keyHash] = liveUntilLedger
+				}
+			}
+
+			return nil
+		})
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(restoreCandidatesCmd)
+	utils.AddCommonFlags(restoreCandidatesCmd.Flags())
+	utils.AddArchiveFlags("restore_candidates", restoreCandidatesCmd.Flags())
+	utils.AddCloudStorageFlags(restoreCandidatesCmd.Flags())
+
+	restoreCandidatesCmd.MarkFlagRequired("start-ledger")
+	restoreCandidatesCmd.MarkFlagRequired("end-ledger")
+}
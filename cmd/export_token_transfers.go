@@ -5,7 +5,7 @@ import (
 
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
-	"github.com/stellar/stellar-etl/v2/internal/input"
+	"github.com/stellar/go-stellar-sdk/xdr"
 	"github.com/stellar/stellar-etl/v2/internal/transform"
 	"github.com/stellar/stellar-etl/v2/internal/utils"
 )
@@ -13,53 +13,52 @@ import (
 var tokenTransfersCmd = &cobra.Command{
 	Use:   "export_token_transfer",
 	Short: "Exports the token transfer event data.",
-	Long:  `Exports token transfer data within the specified range to an output file. Encodes ledgers as JSON objects and exports them to the output file.`,
+	Long: `Exports token transfer data within the specified range to an output file. Encodes ledgers as JSON objects and exports them to the output file.
+
+If the end-ledger is omitted, then the export will continue running and exporting token transfers as new ledgers are
+confirmed by the Stellar network.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		cmdLogger.SetLevel(logrus.InfoLevel)
+		cmdArgs := utils.MustFlags(cmd.Flags(), cmdLogger)
 		commonArgs := utils.MustCommonFlags(cmd.Flags(), cmdLogger)
-		cmdLogger.StrictExport = commonArgs.StrictExport
-		startNum, path, _, limit := utils.MustArchiveFlags(cmd.Flags(), cmdLogger)
-		cloudStorageBucket, cloudCredentials, cloudProvider := utils.MustCloudStorageFlags(cmd.Flags(), cmdLogger)
 		env := utils.GetEnvironmentDetails(commonArgs)
 
-		var ledgers []utils.HistoryArchiveLedgerAndLCM
-		var err error
-
-		ledgers, err = input.GetLedgers(startNum, commonArgs.EndNum, limit, env, commonArgs.UseCaptiveCore)
-
+		successfulOnly, err := cmd.Flags().GetBool("successful-only")
 		if err != nil {
-			cmdLogger.Fatal("could not read ledgers: ", err)
+			cmdLogger.Fatal("could not get successful-only flag: ", err)
 		}
 
-		outFile := MustOutFile(path)
-
-		numFailures := 0
-		totalNumBytes := 0
-		for i, ledger := range ledgers {
-			transformed, err := transform.TransformTokenTransfer(ledger.LCM, env.NetworkPassphrase)
+		engine := newExportEngine(cmdArgs, env, new(transform.TokenTransferOutput), nil)
+		engine.run(func(lcm xdr.LedgerCloseMeta) error {
+			// Ledgers can contain any number of token transfers, so the limit is applied to the exported token transfers instead of the input
+			engine.attempt()
+			ledgerSeq := utils.GetLedgerSequence(lcm)
+			timed := engine.timeTransform(ledgerTransform)
+			transformed, err := transform.TransformTokenTransfer(lcm, env.NetworkPassphrase)
+			timed()
 			if err != nil {
-				cmdLogger.LogError(fmt.Errorf("could not json transform ttp %d: %s", startNum+uint32(i), err))
-				numFailures += 1
-				continue
+				engine.fail(fmt.Errorf("could not json transform ttp %d: %s", ledgerSeq, err))
+				return nil
 			}
 
-			for _, transform := range transformed {
-				numBytes, err := ExportEntry(transform, outFile, commonArgs.Extra)
+			// Failed transactions still charge fees, so their fee events are the only token transfers they have
+			var failedTransactions map[string]bool
+			if successfulOnly {
+				failedTransactions, err = utils.FailedTransactionHashes(lcm)
 				if err != nil {
-					cmdLogger.LogError(fmt.Errorf("could not export ledger %d: %s", startNum+uint32(i), err))
-					numFailures += 1
-					continue
+					return err
 				}
-				totalNumBytes += numBytes
 			}
-		}
-
-		outFile.Close()
-		cmdLogger.Info("Number of bytes written: ", totalNumBytes)
 
-		PrintTransformStats(len(ledgers), numFailures)
+			for _, tokenTransfer := range transformed {
+				if failedTransactions[tokenTransfer.TransactionHash] {
+					continue
+				}
+				engine.export(tokenTransfer)
+			}
 
-		MaybeUpload(cloudCredentials, cloudStorageBucket, cloudProvider, path)
+			return nil
+		})
 	},
 }
 
@@ -68,11 +67,11 @@ func init() {
 	utils.AddCommonFlags(tokenTransfersCmd.Flags())
 	utils.AddArchiveFlags("token_transfer", tokenTransfersCmd.Flags())
 	utils.AddCloudStorageFlags(tokenTransfersCmd.Flags())
-	tokenTransfersCmd.MarkFlagRequired("end-ledger")
+	tokenTransfersCmd.Flags().Bool("successful-only", false, "If set, records of failed transactions are not exported.")
 	/*
 		Current flags:
 			start-ledger: the ledger sequence number for the beginning of the export period
-			end-ledger: the ledger sequence number for the end of the export range (required)
+			end-ledger: the ledger sequence number for the end of the export range; if omitted, ledgers are exported continuously
 
 			limit: maximum number of ledgers to export; default to 60 (1 ledger per 5 seconds over our 5 minute update period)
 			output-file: filename of the output file
@@ -7,6 +7,7 @@ import (
 	"github.com/stellar/stellar-etl/v2/internal/toid"
 
 	"github.com/spf13/cobra"
+	"github.com/stellar/go-stellar-sdk/xdr"
 	"github.com/stellar/stellar-etl/v2/internal/input"
 	"github.com/stellar/stellar-etl/v2/internal/transform"
 	"github.com/stellar/stellar-etl/v2/internal/utils"
@@ -19,56 +20,50 @@ var tradesCmd = &cobra.Command{
 	Long:  `Exports trade data within the specified range to an output file`,
 	Run: func(cmd *cobra.Command, args []string) {
 		cmdLogger.SetLevel(logrus.InfoLevel)
+		cmdArgs := utils.MustFlags(cmd.Flags(), cmdLogger)
 		commonArgs := utils.MustCommonFlags(cmd.Flags(), cmdLogger)
-		cmdLogger.StrictExport = commonArgs.StrictExport
-		startNum, path, parquetPath, limit := utils.MustArchiveFlags(cmd.Flags(), cmdLogger)
 		env := utils.GetEnvironmentDetails(commonArgs)
-		cloudStorageBucket, cloudCredentials, cloudProvider := utils.MustCloudStorageFlags(cmd.Flags(), cmdLogger)
 
-		trades, err := input.GetTrades(startNum, commonArgs.EndNum, limit, env, commonArgs.UseCaptiveCore)
-		if err != nil {
-			cmdLogger.Fatal("could not read trades ", err)
-		}
+		accountFilter := mustAccountFilter(cmd.Flags())
 
-		outFile := MustOutFile(path)
-		numFailures := 0
-		totalNumBytes := 0
-		var transformedTrades []transform.SchemaParquet
-		for _, tradeInput := range trades {
-			trades, err := transform.TransformTrade(tradeInput.OperationIndex, tradeInput.OperationHistoryID, tradeInput.Transaction, tradeInput.CloseTime)
+		engine := newExportEngine(cmdArgs, env, new(transform.TradeOutput), new(transform.TradeOutputParquet))
+		engine.run(func(lcm xdr.LedgerCloseMeta) error {
+			trades, err := input.GetLedgerTrades(lcm, env)
 			if err != nil {
-				parsedID := toid.Parse(tradeInput.OperationHistoryID)
-				cmdLogger.LogError(fmt.Errorf("from ledger %d, transaction %d, operation %d: %v", parsedID.LedgerSequence, parsedID.TransactionOrder, parsedID.OperationOrder, err))
-				numFailures += 1
-				continue
+				return err
 			}
 
-			for _, transformed := range trades {
-				numBytes, err := ExportEntry(transformed, outFile, commonArgs.Extra)
+			// Operations can produce any number of trades, so the limit is applied to the exported trades instead of the input
+			for _, tradeInput := range trades {
+				if engine.limitReached() {
+					break
+				}
+
+				skipAccounts, err := skipFilteredAccounts(accountFilter, tradeInput.Transaction)
+				if err != nil {
+					return err
+				}
+				if skipAccounts {
+					continue
+				}
+
+				engine.attempt()
+				timed := engine.timeTransform(transform.OperationTypeName(tradeInput.Transaction.Envelope.Operations()[tradeInput.OperationIndex]))
+				transformedTrades, err := transform.TransformTrade(tradeInput.OperationIndex, tradeInput.OperationHistoryID, tradeInput.Transaction, tradeInput.CloseTime, env.NetworkPassphrase)
+				timed()
 				if err != nil {
-					cmdLogger.LogError(err)
-					numFailures += 1
+					parsedID := toid.Parse(tradeInput.OperationHistoryID)
+					engine.fail(fmt.Errorf("from ledger %d, transaction %d, operation %d: %v", parsedID.LedgerSequence, parsedID.TransactionOrder, parsedID.OperationOrder, err))
 					continue
 				}
-				totalNumBytes += numBytes
 
-				if commonArgs.WriteParquet {
-					transformedTrades = append(transformedTrades, transformed)
+				for _, transformed := range transformedTrades {
+					engine.export(transformed)
 				}
 			}
-		}
-
-		outFile.Close()
-		cmdLogger.Info("Number of bytes written: ", totalNumBytes)
-
-		PrintTransformStats(len(trades), numFailures)
-
-		MaybeUpload(cloudCredentials, cloudStorageBucket, cloudProvider, path)
 
-		if commonArgs.WriteParquet {
-			MaybeUpload(cloudCredentials, cloudStorageBucket, cloudProvider, parquetPath)
-			WriteParquet(transformedTrades, parquetPath, new(transform.TradeOutputParquet))
-		}
+			return nil
+		})
 	},
 }
 
@@ -77,6 +72,9 @@ func init() {
 	utils.AddCommonFlags(tradesCmd.Flags())
 	utils.AddArchiveFlags("trades", tradesCmd.Flags())
 	utils.AddCloudStorageFlags(tradesCmd.Flags())
+	addAccountFilterFlags(tradesCmd.Flags())
+	// Trades are only made by successful transactions, so successful-only is accepted for consistency with the other exports
+	tradesCmd.Flags().Bool("successful-only", false, "If set, records of failed transactions are not exported.")
 	tradesCmd.MarkFlagRequired("end-ledger")
 
 	/*
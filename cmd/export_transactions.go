@@ -5,6 +5,7 @@ import (
 
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
+	"github.com/stellar/go-stellar-sdk/xdr"
 	"github.com/stellar/stellar-etl/v2/internal/input"
 	"github.com/stellar/stellar-etl/v2/internal/transform"
 	"github.com/stellar/stellar-etl/v2/internal/utils"
@@ -16,54 +17,69 @@ var transactionsCmd = &cobra.Command{
 	Long:  `Exports the transaction data over a specified range to an output file.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		cmdLogger.SetLevel(logrus.InfoLevel)
+		cmdArgs := utils.MustFlags(cmd.Flags(), cmdLogger)
 		commonArgs := utils.MustCommonFlags(cmd.Flags(), cmdLogger)
-		cmdLogger.StrictExport = commonArgs.StrictExport
-		startNum, path, parquetPath, limit := utils.MustArchiveFlags(cmd.Flags(), cmdLogger)
-		cloudStorageBucket, cloudCredentials, cloudProvider := utils.MustCloudStorageFlags(cmd.Flags(), cmdLogger)
 		env := utils.GetEnvironmentDetails(commonArgs)
 
-		transactions, err := input.GetTransactions(startNum, commonArgs.EndNum, limit, env, commonArgs.UseCaptiveCore)
+		failedOnly, err := cmd.Flags().GetBool("failed-only")
 		if err != nil {
-			cmdLogger.Fatal("could not read transactions: ", err)
+			cmdLogger.Fatal("could not get failed-only flag: ", err)
 		}
 
-		outFile := MustOutFile(path)
-		numFailures := 0
-		totalNumBytes := 0
-		var transformedTransaction []transform.SchemaParquet
-		for _, transformInput := range transactions {
-			transformed, err := transform.TransformTransaction(transformInput.Transaction, transformInput.LedgerHistory)
+		kindFilter := mustTransactionKindFilter(cmd.Flags())
+		accountFilter := mustAccountFilter(cmd.Flags())
+
+		engine := newExportEngine(cmdArgs, env, new(transform.TransactionOutput), new(transform.TransactionOutputParquet))
+		engine.run(func(lcm xdr.LedgerCloseMeta) error {
+			skip, err := kindFilter.skipLedger(lcm)
 			if err != nil {
-				ledgerSeq := transformInput.LedgerHistory.Header.LedgerSeq
-				cmdLogger.LogError(fmt.Errorf("could not transform transaction %d in ledger %d: ", transformInput.Transaction.Index, ledgerSeq))
-				numFailures += 1
-				continue
+				return err
+			}
+			if skip {
+				return nil
 			}
 
-			numBytes, err := ExportEntry(transformed, outFile, commonArgs.Extra)
+			transactions, err := input.GetLedgerTransactions(lcm, env)
 			if err != nil {
-				cmdLogger.LogError(fmt.Errorf("could not export transaction: %v", err))
-				numFailures += 1
-				continue
+				return err
 			}
-			totalNumBytes += numBytes
 
-			if commonArgs.WriteParquet {
-				transformedTransaction = append(transformedTransaction, transformed)
+			for _, transformInput := range transactions {
+				if engine.limitReached() {
+					break
+				}
+
+				if kindFilter.skipTransaction(transformInput.Transaction) {
+					continue
+				}
+
+				if failedOnly && transformInput.Transaction.Result.Successful() {
+					continue
+				}
+
+				skipAccounts, err := skipFilteredAccounts(accountFilter, transformInput.Transaction)
+				if err != nil {
+					return err
+				}
+				if skipAccounts {
+					continue
+				}
+
+				engine.attempt()
+				timed := engine.timeTransform(transactionOperationType(transformInput.Transaction))
+				transformed, err := transform.TransformTransaction(transformInput.Transaction, transformInput.LedgerHistory)
+				timed()
+				if err != nil {
+					ledgerSeq := transformInput.LedgerHistory.Header.LedgerSeq
+					engine.fail(fmt.Errorf("could not transform transaction %d in ledger %d: %v", transformInput.Transaction.Index, ledgerSeq, err))
+					continue
+				}
+
+				engine.export(transformed)
 			}
-		}
-
-		outFile.Close()
-		cmdLogger.Info("Number of bytes written: ", totalNumBytes)
 
-		PrintTransformStats(len(transactions), numFailures)
-
-		MaybeUpload(cloudCredentials, cloudStorageBucket, cloudProvider, path)
-
-		if commonArgs.WriteParquet {
-			MaybeUpload(cloudCredentials, cloudStorageBucket, cloudProvider, parquetPath)
-			WriteParquet(transformedTransaction, parquetPath, new(transform.TransactionOutputParquet))
-		}
+			return nil
+		})
 	},
 }
 
@@ -72,6 +88,9 @@ func init() {
 	utils.AddCommonFlags(transactionsCmd.Flags())
 	utils.AddArchiveFlags("transactions", transactionsCmd.Flags())
 	utils.AddCloudStorageFlags(transactionsCmd.Flags())
+	addAccountFilterFlags(transactionsCmd.Flags())
+	addTransactionKindFlags(transactionsCmd.Flags())
+	transactionsCmd.Flags().Bool("failed-only", false, "If set, only the records of failed transactions are exported.")
 	transactionsCmd.MarkFlagRequired("end-ledger")
 
 	/*
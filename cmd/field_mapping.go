@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/xitongsys/parquet-go/parquet"
+	"github.com/xitongsys/parquet-go/schema"
+)
+
+// fieldMapping renames the top level fields of the exported records for a destination, such as fields named after
+// reserved words. The same names are used in the JSON and parquet outputs.
+type fieldMapping map[string]string
+
+// loadFieldMapping reads the renames of a destination and dataset from a mapping file. The file is a JSON object with
+// an object of renames for every destination. Renames of a field of a single dataset, such as "operations.type", take
+// precedence over renames of the field in every dataset.
+func loadFieldMapping(path, destination, dataset string) (fieldMapping, error) {
+	if destination == "" {
+		return nil, fmt.Errorf("field-mapping-destination is required with field-mapping")
+	}
+
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read field mapping file: %v", err)
+	}
+
+	destinations := map[string]map[string]string{}
+	if err := json.Unmarshal(contents, &destinations); err != nil {
+		return nil, fmt.Errorf("could not parse field mapping file %s: %v", path, err)
+	}
+
+	renames, ok := destinations[destination]
+	if !ok {
+		return nil, fmt.Errorf("field mapping file %s has no destination %s", path, destination)
+	}
+
+	mapping := fieldMapping{}
+	for field, name := range renames {
+		if strings.Contains(field, ".") {
+			continue
+		}
+		mapping[field] = name
+	}
+	for field, name := range renames {
+		if datasetField, ok := strings.CutPrefix(field, dataset+"."); ok {
+			mapping[datasetField] = name
+		}
+	}
+
+	for field, name := range mapping {
+		if name == "" {
+			return nil, fmt.Errorf("field %s of destination %s is renamed to an empty name", field, destination)
+		}
+	}
+
+	return mapping, nil
+}
+
+// name returns the name of a field in the destination
+func (m fieldMapping) name(field string) string {
+	if name, ok := m[field]; ok {
+		return name
+	}
+	return field
+}
+
+// renameRecord renames the top level fields of a JSON encoded record
+func (m fieldMapping) renameRecord(marshalled []byte) ([]byte, error) {
+	record := map[string]interface{}{}
+	decoder := json.NewDecoder(bytes.NewReader(marshalled))
+	decoder.UseNumber()
+	if err := decoder.Decode(&record); err != nil {
+		return nil, fmt.Errorf("could not decode record to rename its fields: %v", err)
+	}
+
+	renamed := make(map[string]interface{}, len(record))
+	for field, value := range record {
+		name := m.name(field)
+		if _, exists := renamed[name]; exists {
+			return nil, fmt.Errorf("more than one field is named %s after renaming", name)
+		}
+		renamed[name] = value
+	}
+
+	return json.Marshal(renamed)
+}
+
+// renameParquetColumns renames the top level columns of a parquet schema. Nested elements, such as the key and value
+// of a map, keep their names.
+func (m fieldMapping) renameParquetColumns(handler *schema.SchemaHandler) error {
+	names := map[string]bool{}
+	pos := 1
+	for i := int32(0); i < handler.SchemaElements[0].GetNumChildren(); i++ {
+		info := handler.Infos[pos]
+		info.ExName = m.name(info.ExName)
+		if names[info.ExName] {
+			return fmt.Errorf("more than one column is named %s after renaming", info.ExName)
+		}
+		names[info.ExName] = true
+		pos = skipSchemaElement(handler.SchemaElements, pos)
+	}
+
+	handler.CreateInExMap()
+	return nil
+}
+
+// skipSchemaElement returns the position of the schema element that follows an element and all of its children
+func skipSchemaElement(elements []*parquet.SchemaElement, pos int) int {
+	numChildren := elements[pos].GetNumChildren()
+	pos++
+	for i := int32(0); i < numChildren; i++ {
+		pos = skipSchemaElement(elements, pos)
+	}
+	return pos
+}
@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xitongsys/parquet-go-source/local"
+	"github.com/xitongsys/parquet-go/reader"
+
+	"github.com/stellar/stellar-etl/v2/internal/transform"
+)
+
+func writeFieldMappingFile(t *testing.T, contents string) string {
+	path := filepath.Join(t.TempDir(), "mapping.json")
+	assert.NoError(t, os.WriteFile(path, []byte(contents), 0644))
+	return path
+}
+
+func TestLoadFieldMapping(t *testing.T) {
+	path := writeFieldMappingFile(t, `{
+		"snowflake": {"type": "type_code", "operations.type": "operation_type", "effects.details": "effect_details"},
+		"bigquery": {}
+	}`)
+
+	mapping, err := loadFieldMapping(path, "snowflake", "operations")
+	assert.NoError(t, err)
+	assert.Equal(t, fieldMapping{"type": "operation_type"}, mapping)
+
+	mapping, err = loadFieldMapping(path, "snowflake", "contract_events")
+	assert.NoError(t, err)
+	assert.Equal(t, fieldMapping{"type": "type_code"}, mapping)
+
+	mapping, err = loadFieldMapping(path, "bigquery", "operations")
+	assert.NoError(t, err)
+	assert.Empty(t, mapping)
+
+	_, err = loadFieldMapping(path, "redshift", "operations")
+	assert.Error(t, err)
+
+	_, err = loadFieldMapping(path, "", "operations")
+	assert.Error(t, err)
+
+	_, err = loadFieldMapping(writeFieldMappingFile(t, `{"snowflake": {"type": ""}}`), "snowflake", "operations")
+	assert.Error(t, err)
+}
+
+func TestFieldMappingRenameRecord(t *testing.T) {
+	mapping := fieldMapping{"type": "type_code", "details": "operation_details"}
+
+	renamed, err := mapping.renameRecord([]byte(`{"details":{"type":"nested"},"id":12884905985,"type":1}`))
+	assert.NoError(t, err)
+	assert.Equal(t, `{"id":12884905985,"operation_details":{"type":"nested"},"type_code":1}`, string(renamed))
+
+	_, err = mapping.renameRecord([]byte(`{"type":1,"type_code":2}`))
+	assert.Error(t, err)
+}
+
+func TestWriteParquetRenamesColumns(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "upgrades.parquet")
+	records := []transform.SchemaParquet{transform.LedgerUpgradeOutput{LedgerSequence: 10, Type: 2, TypeString: "LedgerUpgradeTypeLedgerUpgradeBaseFee"}}
+	WriteParquet(records, path, new(transform.LedgerUpgradeOutputParquet), fieldMapping{"type": "type_code", "type_string": "kind"})
+
+	parquetFile, err := local.NewLocalFileReader(path)
+	assert.NoError(t, err)
+	defer parquetFile.Close()
+
+	parquetReader, err := reader.NewParquetColumnReader(parquetFile, 1)
+	assert.NoError(t, err)
+	defer parquetReader.ReadStop()
+
+	names := []string{}
+	for _, info := range parquetReader.SchemaHandler.Infos[1:] {
+		names = append(names, info.ExName)
+	}
+	assert.Equal(t, "ledger_sequence", names[0])
+	assert.Equal(t, "type_code", names[3])
+	assert.Equal(t, "kind", names[4])
+
+	values, _, _, err := parquetReader.ReadColumnByPath("parquet_go_root\x01kind", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{"LedgerUpgradeTypeLedgerUpgradeBaseFee"}, values)
+}
@@ -0,0 +1,211 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/stellar/go-stellar-sdk/xdr"
+
+	"github.com/stellar/stellar-etl/v2/internal/input"
+	"github.com/stellar/stellar-etl/v2/internal/utils"
+)
+
+const (
+	ledgerFixtureFile   = "ledgers.xdr.zst"
+	fixtureManifestFile = "fixture.json"
+)
+
+// fixtureManifest describes a regression fixture: the range and network of its ledgers, and the datasets whose
+// expected outputs are saved next to them as <dataset>.golden
+type fixtureManifest struct {
+	StartLedger uint32   `json:"start_ledger"`
+	EndLedger   uint32   `json:"end_ledger"`
+	Network     string   `json:"network"`
+	Datasets    []string `json:"datasets"`
+}
+
+// exportArgs returns the arguments of the export of a dataset that replays the ledger fixture in fixtureDir
+func (m fixtureManifest) exportArgs(dataset, fixtureDir, output string) []string {
+	args := []string{
+		"export_" + dataset,
+		"--ledger-fixture", filepath.Join(fixtureDir, ledgerFixtureFile),
+		"-s", strconv.FormatUint(uint64(m.StartLedger), 10),
+		"-e", strconv.FormatUint(uint64(m.EndLedger), 10),
+		"-o", output,
+	}
+	switch m.Network {
+	case "testnet":
+		args = append(args, "--testnet")
+	case "futurenet":
+		args = append(args, "--futurenet")
+	}
+	return args
+}
+
+var genFixturesCmd = &cobra.Command{
+	Use:   "gen-fixtures",
+	Short: "Captures a ledger range and its exports as a regression fixture",
+	Long: `Captures the ledgers of a range as a compressed ledger fixture, and exports every dataset from the fixture as
+its expected output. The fixture is written to a folder of testdata/fixtures, where the golden tests replay the exports
+and compare them to the expected outputs. This makes it easy to add regression cases for tricky ledgers.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		ledgerRange, err := cmd.Flags().GetString("ledger-range")
+		if err != nil {
+			cmdLogger.Fatal("could not get ledger-range: ", err)
+		}
+
+		datasets, err := cmd.Flags().GetStringSlice("datasets")
+		if err != nil {
+			cmdLogger.Fatal("could not get datasets: ", err)
+		}
+
+		outputFolder, err := cmd.Flags().GetString("output")
+		if err != nil {
+			cmdLogger.Fatal("could not get output folder: ", err)
+		}
+
+		name, err := cmd.Flags().GetString("name")
+		if err != nil {
+			cmdLogger.Fatal("could not get name: ", err)
+		}
+
+		isTest, err := cmd.Flags().GetBool("testnet")
+		if err != nil {
+			cmdLogger.Fatal("could not get testnet boolean: ", err)
+		}
+
+		isFuture, err := cmd.Flags().GetBool("futurenet")
+		if err != nil {
+			cmdLogger.Fatal("could not get futurenet boolean: ", err)
+		}
+
+		datastorePath, err := cmd.Flags().GetString("datastore-path")
+		if err != nil {
+			cmdLogger.Fatal("could not get datastore path: ", err)
+		}
+
+		start, end, err := parseLedgerRange(ledgerRange)
+		if err != nil {
+			cmdLogger.Fatal(err)
+		}
+
+		for _, dataset := range datasets {
+			if err := validateFixtureDataset(dataset); err != nil {
+				cmdLogger.Fatal(err)
+			}
+		}
+
+		if name == "" {
+			name = fmt.Sprintf("ledgers_%d_%d", start, end)
+		}
+		fixtureDir := filepath.Join(outputFolder, name)
+		if err := os.MkdirAll(fixtureDir, os.ModePerm); err != nil {
+			cmdLogger.Fatal("could not create fixture folder: ", err)
+		}
+
+		env := utils.GetEnvironmentDetails(utils.CommonFlagValues{
+			EndNum:         end,
+			IsTest:         isTest,
+			IsFuture:       isFuture,
+			DatastorePath:  datastorePath,
+			BufferSize:     200,
+			NumWorkers:     10,
+			RetryLimit:     3,
+			RetryWait:      5,
+			EveryNthLedger: 1,
+			SampleRate:     1,
+		})
+		ledgers, err := input.GetLedgers(start, end, -1, env, false)
+		if err != nil {
+			cmdLogger.Fatal("could not read ledgers: ", err)
+		}
+
+		lcms := make([]xdr.LedgerCloseMeta, 0, len(ledgers))
+		for _, ledger := range ledgers {
+			lcms = append(lcms, ledger.LCM)
+		}
+		if err := utils.WriteLedgerFixture(filepath.Join(fixtureDir, ledgerFixtureFile), lcms); err != nil {
+			cmdLogger.Fatal(err)
+		}
+
+		manifest := fixtureManifest{StartLedger: start, EndLedger: end, Network: env.Network, Datasets: datasets}
+		marshalled, err := json.MarshalIndent(manifest, "", "  ")
+		if err != nil {
+			cmdLogger.Fatal("could not json encode fixture manifest: ", err)
+		}
+		if err := os.WriteFile(filepath.Join(fixtureDir, fixtureManifestFile), append(marshalled, '\n'), 0644); err != nil {
+			cmdLogger.Fatal("could not write fixture manifest: ", err)
+		}
+
+		// The expected outputs are exported from the fixture by the export commands themselves, so they are exactly
+		// what the golden tests replay
+		executable, err := os.Executable()
+		if err != nil {
+			cmdLogger.Fatal("could not find the stellar-etl executable: ", err)
+		}
+		for _, dataset := range datasets {
+			output := filepath.Join(fixtureDir, dataset+".golden")
+			exportCmd := exec.Command(executable, manifest.exportArgs(dataset, fixtureDir, output)...)
+			if out, err := exportCmd.CombinedOutput(); err != nil {
+				cmdLogger.Fatalf("could not export %s from the ledger fixture: %v\n%s", dataset, err, out)
+			}
+		}
+
+		cmdLogger.Infof("Wrote a fixture of %d ledgers with %d datasets to %s", len(lcms), len(datasets), fixtureDir)
+	},
+}
+
+// parseLedgerRange parses an inclusive ledger range such as 100-200
+func parseLedgerRange(ledgerRange string) (uint32, uint32, error) {
+	startString, endString, found := strings.Cut(ledgerRange, "-")
+	if !found {
+		return 0, 0, fmt.Errorf("ledger-range %s must be a start and end ledger such as 100-200", ledgerRange)
+	}
+
+	start, err := strconv.ParseUint(startString, 10, 32)
+	if err != nil {
+		return 0, 0, fmt.Errorf("could not parse start of ledger-range %s: %v", ledgerRange, err)
+	}
+	end, err := strconv.ParseUint(endString, 10, 32)
+	if err != nil {
+		return 0, 0, fmt.Errorf("could not parse end of ledger-range %s: %v", ledgerRange, err)
+	}
+
+	if start == 0 || end < start {
+		return 0, 0, fmt.Errorf("ledger-range %s must start after ledger 0 and end at or after its start", ledgerRange)
+	}
+
+	return uint32(start), uint32(end), nil
+}
+
+// validateFixtureDataset checks that a dataset has an export command that writes a single output file
+func validateFixtureDataset(dataset string) error {
+	if dataset == "ledger_entry_changes" {
+		return fmt.Errorf("dataset ledger_entry_changes is not supported because it is exported to a folder")
+	}
+
+	exportCmd, _, err := rootCmd.Find([]string{"export_" + dataset})
+	if err != nil || exportCmd.Name() != "export_"+dataset {
+		return fmt.Errorf("unsupported dataset %s; there is no export_%s command", dataset, dataset)
+	}
+
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(genFixturesCmd)
+	genFixturesCmd.Flags().String("ledger-range", "", "Inclusive range of the ledgers to capture, such as 100-200.")
+	genFixturesCmd.Flags().StringSlice("datasets", []string{"ledgers", "transactions", "operations", "effects"}, "Comma separated datasets whose expected outputs are saved, named after their export commands such as ledgers or contract_events.")
+	genFixturesCmd.Flags().StringP("output", "o", "testdata/fixtures", "Folder that the fixture folder is created in.")
+	genFixturesCmd.Flags().String("name", "", "Name of the fixture folder. Defaults to ledgers_<start>_<end>.")
+	genFixturesCmd.Flags().Bool("testnet", false, "If set, will connect to Testnet instead of Mainnet.")
+	genFixturesCmd.Flags().Bool("futurenet", false, "If set, will connect to Futurenet instead of Mainnet.")
+	genFixturesCmd.Flags().String("datastore-path", "sdf-ledger-close-meta/v1/ledgers", "Datastore bucket path to read txmeta files from.")
+	genFixturesCmd.MarkFlagRequired("ledger-range")
+}
@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLedgerRange(t *testing.T) {
+	start, end, err := parseLedgerRange("30822015-30822025")
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(30822015), start)
+	assert.Equal(t, uint32(30822025), end)
+
+	for _, ledgerRange := range []string{"30822015", "a-b", "100-50", "0-10", "1-4294967296"} {
+		_, _, err = parseLedgerRange(ledgerRange)
+		assert.Error(t, err, ledgerRange)
+	}
+}
+
+func TestValidateFixtureDataset(t *testing.T) {
+	assert.NoError(t, validateFixtureDataset("contract_events"))
+	assert.Error(t, validateFixtureDataset("ledger_entry_changes"))
+	assert.Error(t, validateFixtureDataset("accounts"))
+}
+
+func TestFixtureManifestExportArgs(t *testing.T) {
+	manifest := fixtureManifest{StartLedger: 100, EndLedger: 110, Network: "testnet", Datasets: []string{"ledgers"}}
+	assert.Equal(t,
+		[]string{"export_ledgers", "--ledger-fixture", "testdata/fixtures/swaps/ledgers.xdr.zst", "-s", "100", "-e", "110", "-o", "out.txt", "--testnet"},
+		manifest.exportArgs("ledgers", "testdata/fixtures/swaps", "out.txt"))
+}
+
+// TestLedgerFixtures replays the exports of every fixture written by gen-fixtures and compares them to the expected
+// outputs of the fixture
+func TestLedgerFixtures(t *testing.T) {
+	manifestPaths, err := filepath.Glob(filepath.Join("testdata", "fixtures", "*", fixtureManifestFile))
+	assert.NoError(t, err)
+	if len(manifestPaths) == 0 {
+		t.Skip("no ledger fixtures in testdata/fixtures")
+	}
+
+	for _, manifestPath := range manifestPaths {
+		contents, err := os.ReadFile(manifestPath)
+		assert.NoError(t, err)
+
+		var manifest fixtureManifest
+		assert.NoError(t, json.Unmarshal(contents, &manifest))
+
+		fixtureDir := filepath.Dir(manifestPath)
+		for _, dataset := range manifest.Datasets {
+			name := filepath.Base(fixtureDir) + "/" + dataset
+			RunCLITest(t, CliTest{
+				Name:   name,
+				Args:   manifest.exportArgs(dataset, fixtureDir, GotTestDir(t, name+".txt")),
+				Golden: dataset + ".golden",
+			}, fixtureDir+"/", "", false)
+		}
+	}
+}
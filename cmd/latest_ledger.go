@@ -0,0 +1,204 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stellar/go-stellar-sdk/support/datastore"
+
+	"github.com/stellar/stellar-etl/v2/internal/utils"
+)
+
+// ledgerSource is a place that ledgers are read from, such as the history archives or the datastore
+type ledgerSource struct {
+	name   string
+	latest func(ctx context.Context) (uint32, error)
+}
+
+// sourceLatestLedger is the latest ledger of a source, and how many ledgers it is behind the most recent source
+type sourceLatestLedger struct {
+	Source       string `json:"source"`
+	LatestLedger uint32 `json:"latest_ledger,omitempty"`
+	Lag          uint32 `json:"lag"`
+	Error        string `json:"error,omitempty"`
+}
+
+// latestLedgerReport is the latest ledger of every source. LatestLedger is the most recent of them, and
+// LatestCommonLedger is the latest ledger that every source that could be reached has. RootLedgerHash identifies the
+// history of the network, and is the value of expected-root-hash for exports.
+type latestLedgerReport struct {
+	Network            string               `json:"network"`
+	RootLedgerHash     string               `json:"root_ledger_hash,omitempty"`
+	LatestLedger       uint32               `json:"latest_ledger"`
+	LatestCommonLedger uint32               `json:"latest_common_ledger"`
+	Sources            []sourceLatestLedger `json:"sources"`
+}
+
+var latestLedgerCmd = &cobra.Command{
+	Use:   "latest_ledger",
+	Short: "Reports the latest ledger of the history archives, the datastore and RPC",
+	Long: `Reports the latest ledger that is available from the history archives, the datastore and, if rpc-url is set, a
+Stellar RPC server, and how many ledgers each of them is behind the most recent one. latest_common_ledger is the latest
+ledger that every reachable source has, which is a valid end-ledger for an export from any of them. A source that can't
+be reached is reported with its error; the command only fails if no source can be reached. root_ledger_hash is the hash
+of the root ledger of the history archives, to be passed to expected-root-hash.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		isTest, err := cmd.Flags().GetBool("testnet")
+		if err != nil {
+			cmdLogger.Fatal("could not get testnet boolean: ", err)
+		}
+
+		isFuture, err := cmd.Flags().GetBool("futurenet")
+		if err != nil {
+			cmdLogger.Fatal("could not get futurenet boolean: ", err)
+		}
+
+		datastorePath, err := cmd.Flags().GetString("datastore-path")
+		if err != nil {
+			cmdLogger.Fatal("could not get datastore-path: ", err)
+		}
+
+		rpcURL, err := cmd.Flags().GetString("rpc-url")
+		if err != nil {
+			cmdLogger.Fatal("could not get rpc-url: ", err)
+		}
+
+		path, err := cmd.Flags().GetString("output")
+		if err != nil {
+			cmdLogger.Fatal("could not get output path: ", err)
+		}
+
+		env := utils.GetEnvironmentDetails(utils.CommonFlagValues{IsTest: isTest, IsFuture: isFuture, DatastorePath: datastorePath})
+		sources := []ledgerSource{
+			{name: "history_archive", latest: func(ctx context.Context) (uint32, error) {
+				return utils.GetLatestLedgerSequence(env.ArchiveURLs)
+			}},
+			{name: "datastore", latest: func(ctx context.Context) (uint32, error) {
+				dataStore, _, err := utils.CreateDatastore(ctx, env)
+				if err != nil {
+					return 0, err
+				}
+				defer dataStore.Close()
+				return datastore.FindLatestLedgerSequence(ctx, dataStore)
+			}},
+		}
+		if rpcURL != "" {
+			sources = append(sources, ledgerSource{name: "rpc", latest: func(ctx context.Context) (uint32, error) {
+				return rpcLatestLedger(ctx, &http.Client{Timeout: 30 * time.Second}, rpcURL)
+			}})
+		}
+
+		report := latestLedgers(context.Background(), sources)
+		report.Network = env.Network
+		if report.LatestLedger == 0 {
+			cmdLogger.Fatalf("could not get the latest ledger of any source: %+v", report.Sources)
+		}
+
+		archive, err := utils.CreateHistoryArchiveClient(env.ArchiveURLs)
+		if err == nil {
+			report.RootLedgerHash, err = utils.GetRootLedgerHash(archive)
+		}
+		if err != nil {
+			cmdLogger.Warnf("Could not get the root ledger hash of the history archives: %v", err)
+		}
+
+		marshalled, err := json.Marshal(report)
+		if err != nil {
+			cmdLogger.Fatal("could not json encode latest ledgers: ", err)
+		}
+
+		if path != "" {
+			outFile := MustOutFile(path)
+			outFile.Write(marshalled)
+			outFile.WriteString("\n")
+			outFile.Close()
+		} else {
+			fmt.Println(string(marshalled))
+		}
+	},
+}
+
+// latestLedgers gets the latest ledger of every source, and the lag of every source behind the most recent one.
+// Sources that fail are reported with their error and left out of the latest and common ledgers.
+func latestLedgers(ctx context.Context, sources []ledgerSource) latestLedgerReport {
+	report := latestLedgerReport{Sources: make([]sourceLatestLedger, 0, len(sources))}
+	for _, source := range sources {
+		result := sourceLatestLedger{Source: source.name}
+		latest, err := source.latest(ctx)
+		if err != nil {
+			result.Error = err.Error()
+			cmdLogger.Warnf("Could not get the latest ledger of the %s: %v", source.name, err)
+		} else {
+			result.LatestLedger = latest
+			report.LatestLedger = max(report.LatestLedger, latest)
+			if report.LatestCommonLedger == 0 || latest < report.LatestCommonLedger {
+				report.LatestCommonLedger = latest
+			}
+		}
+		report.Sources = append(report.Sources, result)
+	}
+
+	for i, result := range report.Sources {
+		if result.Error == "" {
+			report.Sources[i].Lag = report.LatestLedger - result.LatestLedger
+		}
+	}
+	return report
+}
+
+// rpcLatestLedger gets the latest ledger of a Stellar RPC server with its getLatestLedger method
+func rpcLatestLedger(ctx context.Context, client *http.Client, rpcURL string) (uint32, error) {
+	body, err := json.Marshal(map[string]interface{}{"jsonrpc": "2.0", "id": 1, "method": "getLatestLedger"})
+	if err != nil {
+		return 0, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, rpcURL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("rpc returned %s", resp.Status)
+	}
+
+	var decoded struct {
+		Result *struct {
+			Sequence uint32 `json:"sequence"`
+		} `json:"result"`
+		Error *struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&decoded); err != nil {
+		return 0, fmt.Errorf("could not decode rpc response: %v", err)
+	}
+	if decoded.Error != nil {
+		return 0, fmt.Errorf("rpc error %d: %s", decoded.Error.Code, decoded.Error.Message)
+	}
+	if decoded.Result == nil {
+		return 0, fmt.Errorf("rpc response has no result")
+	}
+	return decoded.Result.Sequence, nil
+}
+
+func init() {
+	rootCmd.AddCommand(latestLedgerCmd)
+	latestLedgerCmd.Flags().Bool("testnet", false, "If set, the latest ledgers of testnet are reported instead of mainnet.")
+	latestLedgerCmd.Flags().Bool("futurenet", false, "If set, the latest ledgers of futurenet are reported instead of mainnet.")
+	latestLedgerCmd.Flags().String("datastore-path", "sdf-ledger-close-meta/v1/ledgers", "Datastore bucket path to read txmeta files from.")
+	latestLedgerCmd.Flags().String("rpc-url", "", "If set, the latest ledger of the Stellar RPC server at this URL is reported too.")
+	latestLedgerCmd.Flags().StringP("output", "o", "", "If set, the report is written to this file instead of stdout.")
+}
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLatestLedgers(t *testing.T) {
+	fixed := func(seq uint32) func(context.Context) (uint32, error) {
+		return func(context.Context) (uint32, error) { return seq, nil }
+	}
+	report := latestLedgers(context.Background(), []ledgerSource{
+		{name: "history_archive", latest: fixed(59999999)},
+		{name: "datastore", latest: fixed(60000050)},
+		{name: "rpc", latest: func(context.Context) (uint32, error) { return 0, errors.New("connection refused") }},
+	})
+
+	assert.Equal(t, uint32(60000050), report.LatestLedger)
+	assert.Equal(t, uint32(59999999), report.LatestCommonLedger)
+	assert.Equal(t, []sourceLatestLedger{
+		{Source: "history_archive", LatestLedger: 59999999, Lag: 51},
+		{Source: "datastore", LatestLedger: 60000050, Lag: 0},
+		{Source: "rpc", Error: "connection refused"},
+	}, report.Sources)
+}
+
+func TestRPCLatestLedger(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"jsonrpc":"2.0","id":1,"method":"getLatestLedger"}`, string(body))
+		w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"id":"ab","protocolVersion":23,"sequence":60000051}}`))
+	}))
+	defer server.Close()
+
+	latest, err := rpcLatestLedger(context.Background(), server.Client(), server.URL)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(60000051), latest)
+
+	errorServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"jsonrpc":"2.0","id":1,"error":{"code":-32601,"message":"method not found"}}`))
+	}))
+	defer errorServer.Close()
+
+	_, err = rpcLatestLedger(context.Background(), errorServer.Client(), errorServer.URL)
+	assert.EqualError(t, err, "rpc error -32601: method not found")
+}
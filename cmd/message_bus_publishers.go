@@ -0,0 +1,195 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2/google"
+)
+
+// pubSubEmulatorHostEnvVar is the environment variable with the host of a Pub/Sub emulator, which is used without
+// credentials instead of pubsub-endpoint when it is set
+const pubSubEmulatorHostEnvVar = "PUBSUB_EMULATOR_HOST"
+
+// pubSubPublisher publishes records to a Google Cloud Pub/Sub topic with the publish method of the REST API. The key of
+// a record is its ordering key.
+type pubSubPublisher struct {
+	endpoint string
+	topic    string
+	client   *http.Client
+}
+
+func newPubSubPublisher(topic, endpoint string) (*pubSubPublisher, error) {
+	if parts := strings.Split(topic, "/"); len(parts) != 4 || parts[0] != "projects" || parts[2] != "topics" {
+		return nil, fmt.Errorf("pubsub topic must be projects/<project>/topics/<topic>, got %s", topic)
+	}
+
+	if emulatorHost := os.Getenv(pubSubEmulatorHostEnvVar); emulatorHost != "" {
+		return &pubSubPublisher{endpoint: "http://" + emulatorHost, topic: topic, client: &http.Client{Timeout: 60 * time.Second}}, nil
+	}
+
+	client, err := google.DefaultClient(context.Background(), "https://www.googleapis.com/auth/pubsub")
+	if err != nil {
+		return nil, fmt.Errorf("could not create pubsub client: %v", err)
+	}
+	client.Timeout = 60 * time.Second
+
+	return &pubSubPublisher{endpoint: strings.TrimSuffix(endpoint, "/"), topic: topic, client: client}, nil
+}
+
+// pubSubMessage is a message of a publish request. Data is base64 encoded in the JSON of the request.
+type pubSubMessage struct {
+	Data        []byte `json:"data"`
+	OrderingKey string `json:"orderingKey,omitempty"`
+}
+
+func (p *pubSubPublisher) publish(messages []busMessage) (bool, error) {
+	request := struct {
+		Messages []pubSubMessage `json:"messages"`
+	}{make([]pubSubMessage, 0, len(messages))}
+	for _, message := range messages {
+		request.Messages = append(request.Messages, pubSubMessage{Data: message.record, OrderingKey: message.key})
+	}
+
+	body, err := json.Marshal(request)
+	if err != nil {
+		return false, fmt.Errorf("could not encode batch: %v", err)
+	}
+
+	return postBusBatch(p.client, p.endpoint+"/v1/"+p.topic+":publish", "application/json", body)
+}
+
+func (p *pubSubPublisher) destination() string {
+	return "pubsub topic " + p.topic
+}
+
+// kafkaRESTPublisher produces records to a Kafka topic through the v2 API of the Kafka REST Proxy. The key of a record
+// is its Kafka key, which picks its partition.
+type kafkaRESTPublisher struct {
+	url    string
+	topic  string
+	client *http.Client
+}
+
+func newKafkaRESTPublisher(proxyURL, topic string) (*kafkaRESTPublisher, error) {
+	if proxyURL == "" {
+		return nil, fmt.Errorf("kafka-rest-url is required to publish to kafka")
+	}
+
+	return &kafkaRESTPublisher{
+		url:    strings.TrimSuffix(proxyURL, "/"),
+		topic:  topic,
+		client: &http.Client{Timeout: 60 * time.Second},
+	}, nil
+}
+
+// kafkaRecord is a record of a produce request, with the embedded JSON format
+type kafkaRecord struct {
+	Key   *string         `json:"key,omitempty"`
+	Value json.RawMessage `json:"value"`
+}
+
+// kafkaProduceResponse has the offset or error of every record of a produce request. An error code of 2 is retriable.
+type kafkaProduceResponse struct {
+	Offsets []struct {
+		ErrorCode *int   `json:"error_code"`
+		Error     string `json:"error"`
+	} `json:"offsets"`
+}
+
+func (k *kafkaRESTPublisher) publish(messages []busMessage) (bool, error) {
+	request := struct {
+		Records []kafkaRecord `json:"records"`
+	}{make([]kafkaRecord, 0, len(messages))}
+	for _, message := range messages {
+		record := kafkaRecord{Value: message.record}
+		if message.key != "" {
+			key := message.key
+			record.Key = &key
+		}
+		request.Records = append(request.Records, record)
+	}
+
+	body, err := json.Marshal(request)
+	if err != nil {
+		return false, fmt.Errorf("could not encode batch: %v", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, k.url+"/topics/"+url.PathEscape(k.topic), bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/vnd.kafka.json.v2+json")
+	req.Header.Set("Accept", "application/vnd.kafka.v2+json")
+
+	resp, err := k.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return busStatusError(resp)
+	}
+
+	var produced kafkaProduceResponse
+	if err := json.NewDecoder(resp.Body).Decode(&produced); err != nil {
+		return true, fmt.Errorf("could not decode kafka rest proxy response: %v", err)
+	}
+
+	// The records that were produced are produced again when the batch is retried, so they can be repeated
+	retry := true
+	var firstErr string
+	numFailed := 0
+	for _, offset := range produced.Offsets {
+		if offset.ErrorCode == nil {
+			continue
+		}
+		numFailed += 1
+		if firstErr == "" {
+			firstErr = offset.Error
+		}
+		if *offset.ErrorCode != 2 {
+			retry = false
+		}
+	}
+	if numFailed > 0 {
+		return retry, fmt.Errorf("kafka rejected %d records: %s", numFailed, firstErr)
+	}
+	return false, nil
+}
+
+func (k *kafkaRESTPublisher) destination() string {
+	return "kafka topic " + k.topic
+}
+
+// postBusBatch POSTs a batch, and returns whether a failed request should be retried
+func postBusBatch(client *http.Client, requestURL, contentType string, body []byte) (bool, error) {
+	resp, err := client.Post(requestURL, contentType, bytes.NewReader(body))
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return busStatusError(resp)
+	}
+	io.Copy(io.Discard, resp.Body)
+	return false, nil
+}
+
+// busStatusError returns the error of a response with a failed status, and whether the request should be retried. 429
+// and 5xx statuses are retried, since that is how buses push back on a publisher that is too fast.
+func busStatusError(resp *http.Response) (bool, error) {
+	message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	retry := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	return retry, fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(message)))
+}
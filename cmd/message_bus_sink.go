@@ -0,0 +1,290 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// maxBusBatchBytes is the size above which a batch is published before it is full, so requests stay below the 10MB
+// limit of Pub/Sub and the default request limits of the Kafka REST Proxy
+const maxBusBatchBytes = 8 * 1024 * 1024
+
+// busMessage is a record published to a message bus, with the key that orders it
+type busMessage struct {
+	key    string
+	record []byte
+}
+
+// busPublisher sends batches of records to a message bus. The records of a key in a batch are delivered in the order
+// they are in the batch.
+type busPublisher interface {
+	// publish sends a batch and waits for the bus to acknowledge it, and returns whether a failed batch should be retried
+	publish(messages []busMessage) (bool, error)
+	// destination names where the records are published, for the export logs
+	destination() string
+}
+
+// busBatch is a batch of records that is being published, with the number of records added to the sink before it
+type busBatch struct {
+	messages []busMessage
+	start    int
+	keys     []string
+}
+
+// messageBusSink publishes the records of an export to a message bus in batches, without waiting for a batch to be
+// acknowledged before the next ones are exported. Up to maxInFlight batches are published at once, and the export
+// waits once that many are in flight, so a bus that slows down or throttles the export holds it back instead of
+// queueing records in memory. A batch with a key of a batch that is still in flight waits for it, so the records of a
+// key are delivered in order even when a batch is retried.
+type messageBusSink struct {
+	publisher   busPublisher
+	keyField    string
+	batchSize   int
+	maxInFlight int
+	maxAttempts int
+	retryWait   time.Duration
+
+	batch      []busMessage
+	batchBytes int
+	numAdded   int
+
+	mu            sync.Mutex
+	cond          *sync.Cond
+	inFlight      map[int]bool
+	busyKeys      map[string]bool
+	numDispatched int
+	numBatches    int
+	numPublished  int
+	err           error
+}
+
+func newMessageBusSink(publisher busPublisher, keyField string, batchSize, maxInFlight int) (*messageBusSink, error) {
+	if batchSize <= 0 {
+		return nil, fmt.Errorf("publish-batch-size must be positive, got %d", batchSize)
+	}
+	if maxInFlight <= 0 {
+		return nil, fmt.Errorf("publish-max-in-flight must be positive, got %d", maxInFlight)
+	}
+
+	sink := &messageBusSink{
+		publisher:   publisher,
+		keyField:    keyField,
+		batchSize:   batchSize,
+		maxInFlight: maxInFlight,
+		maxAttempts: 8,
+		retryWait:   time.Second,
+		inFlight:    map[int]bool{},
+		busyKeys:    map[string]bool{},
+	}
+	sink.cond = sync.NewCond(&sink.mu)
+	return sink, nil
+}
+
+// newPubSubSink creates the sink that publishes records to a Pub/Sub topic
+func newPubSubSink(topic, endpoint, keyField string, batchSize, maxInFlight int) (*messageBusSink, error) {
+	if topic == "" {
+		return nil, fmt.Errorf("pubsub-topic is required for the pubsub sink")
+	}
+
+	publisher, err := newPubSubPublisher(topic, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	return newMessageBusSink(publisher, keyField, batchSize, maxInFlight)
+}
+
+// newKafkaSink creates the sink that produces records to a Kafka topic through a Kafka REST Proxy
+func newKafkaSink(proxyURL, topic, keyField string, batchSize, maxInFlight int) (*messageBusSink, error) {
+	if topic == "" {
+		return nil, fmt.Errorf("kafka-topic is required for the kafka sink")
+	}
+
+	publisher, err := newKafkaRESTPublisher(proxyURL, topic)
+	if err != nil {
+		return nil, err
+	}
+	return newMessageBusSink(publisher, keyField, batchSize, maxInFlight)
+}
+
+// add queues an encoded record, and publishes the batch once it is full. It returns the error of a batch that could
+// not be delivered, since the records after it can't be delivered in order.
+func (s *messageBusSink) add(record []byte) error {
+	if err := s.failed(); err != nil {
+		return err
+	}
+
+	key, err := messageKey(record, s.keyField)
+	if err != nil {
+		return err
+	}
+
+	s.batch = append(s.batch, busMessage{key: key, record: record})
+	s.batchBytes += len(record)
+	s.numAdded += 1
+	if len(s.batch) >= s.batchSize || s.batchBytes >= maxBusBatchBytes {
+		return s.dispatch()
+	}
+	return nil
+}
+
+// flush starts publishing the queued records, without waiting for them to be acknowledged
+func (s *messageBusSink) flush() error {
+	if len(s.batch) > 0 {
+		if err := s.dispatch(); err != nil {
+			return err
+		}
+	}
+	return s.failed()
+}
+
+// dispatch publishes the queued records in the background once fewer than maxInFlight batches are in flight and no
+// batch in flight has any of their keys
+func (s *messageBusSink) dispatch() error {
+	batch := busBatch{messages: s.batch, start: s.numAdded - len(s.batch), keys: batchKeys(s.batch)}
+	s.batch, s.batchBytes = nil, 0
+
+	s.mu.Lock()
+	for s.err == nil && (len(s.inFlight) >= s.maxInFlight || s.anyKeyBusy(batch.keys)) {
+		s.cond.Wait()
+	}
+	if s.err != nil {
+		s.mu.Unlock()
+		return s.err
+	}
+	s.inFlight[batch.start] = true
+	for _, key := range batch.keys {
+		s.busyKeys[key] = true
+	}
+	s.numDispatched = s.numAdded
+	s.mu.Unlock()
+
+	go s.publish(batch)
+	return nil
+}
+
+// publish sends a batch, retrying it with an exponential backoff when the bus can't be reached or throttles it
+func (s *messageBusSink) publish(batch busBatch) {
+	var err error
+	attempt := 1
+	for ; ; attempt++ {
+		var retry bool
+		retry, err = s.publisher.publish(batch.messages)
+		if err == nil || !retry || attempt >= s.maxAttempts {
+			break
+		}
+
+		wait := s.retryWait << (attempt - 1)
+		cmdLogger.Warnf("Could not publish batch to %s, retrying in %s: %v", s.publisher.destination(), wait, err)
+		time.Sleep(wait)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.inFlight, batch.start)
+	for _, key := range batch.keys {
+		delete(s.busyKeys, key)
+	}
+	if err != nil {
+		if s.err == nil {
+			s.err = fmt.Errorf("could not publish batch of %d records to %s after %d attempts: %v", len(batch.messages), s.publisher.destination(), attempt, err)
+		}
+	} else {
+		s.numBatches += 1
+		s.numPublished += len(batch.messages)
+	}
+	s.cond.Broadcast()
+}
+
+// anyKeyBusy checks if a batch in flight has one of the keys
+func (s *messageBusSink) anyKeyBusy(keys []string) bool {
+	for _, key := range keys {
+		if s.busyKeys[key] {
+			return true
+		}
+	}
+	return false
+}
+
+// failed returns the error of a batch that could not be delivered
+func (s *messageBusSink) failed() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+func (s *messageBusSink) added() int {
+	return s.numAdded
+}
+
+// acked returns the number of records added before the first record that is not acknowledged yet. Batches can be
+// acknowledged out of order, so it stops at the first batch in flight.
+func (s *messageBusSink) acked() (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	numAcked := s.numDispatched
+	for start := range s.inFlight {
+		numAcked = min(numAcked, start)
+	}
+	return numAcked, s.err
+}
+
+// close publishes the queued records and waits until every batch is acknowledged
+func (s *messageBusSink) close() error {
+	if err := s.flush(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for len(s.inFlight) > 0 {
+		s.cond.Wait()
+	}
+	return s.err
+}
+
+func (s *messageBusSink) describe() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return fmt.Sprintf("Published %d records in %d batches to %s", s.numPublished, s.numBatches, s.publisher.destination())
+}
+
+// messageKey returns the value of the key field of an encoded record. Strings are used as they are and other values as
+// they are written in the JSON. Records without the field, or with a null value, have no key.
+func messageKey(record []byte, keyField string) (string, error) {
+	if keyField == "" {
+		return "", nil
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(record, &fields); err != nil {
+		return "", fmt.Errorf("could not decode record: %v", err)
+	}
+
+	value, ok := fields[keyField]
+	if !ok || string(value) == "null" {
+		return "", nil
+	}
+
+	var key string
+	if err := json.Unmarshal(value, &key); err == nil {
+		return key, nil
+	}
+	return string(value), nil
+}
+
+// batchKeys returns the distinct keys of a batch. Records without a key are not ordered, so they are left out.
+func batchKeys(messages []busMessage) []string {
+	seen := map[string]bool{}
+	keys := []string{}
+	for _, message := range messages {
+		if message.key == "" || seen[message.key] {
+			continue
+		}
+		seen[message.key] = true
+		keys = append(keys, message.key)
+	}
+	return keys
+}
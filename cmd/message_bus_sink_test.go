@@ -0,0 +1,310 @@
+package cmd
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stellar/stellar-etl/v2/internal/transform"
+	"github.com/stellar/stellar-etl/v2/internal/utils"
+)
+
+// gatedPublisher acknowledges a batch once the gate of the key of its first record is released
+type gatedPublisher struct {
+	mu        sync.Mutex
+	gates     map[string]chan struct{}
+	published []string
+}
+
+func (p *gatedPublisher) gate(key string) chan struct{} {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.gates == nil {
+		p.gates = map[string]chan struct{}{}
+	}
+	if p.gates[key] == nil {
+		p.gates[key] = make(chan struct{})
+	}
+	return p.gates[key]
+}
+
+func (p *gatedPublisher) release(key string) {
+	close(p.gate(key))
+}
+
+func (p *gatedPublisher) publish(messages []busMessage) (bool, error) {
+	<-p.gate(messages[0].key)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, message := range messages {
+		p.published = append(p.published, string(message.record))
+	}
+	return false, nil
+}
+
+func (p *gatedPublisher) destination() string {
+	return "test topic"
+}
+
+func (p *gatedPublisher) publishedRecords() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]string{}, p.published...)
+}
+
+func TestMessageBusSinkWaitsToDispatch(t *testing.T) {
+	type testCase struct {
+		name        string
+		maxInFlight int
+		keys        []string
+	}
+
+	tests := []testCase{
+		{name: "same key", maxInFlight: 4, keys: []string{"x", "x"}},
+		{name: "max in flight", maxInFlight: 1, keys: []string{"x", "y"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			publisher := &gatedPublisher{}
+			sink, err := newMessageBusSink(publisher, "account", 1, test.maxInFlight)
+			assert.NoError(t, err)
+
+			first := fmt.Sprintf(`{"account":%q,"n":1}`, test.keys[0])
+			second := fmt.Sprintf(`{"account":%q,"n":2}`, test.keys[1])
+			assert.NoError(t, sink.add([]byte(first)))
+
+			dispatched := make(chan error)
+			go func() { dispatched <- sink.add([]byte(second)) }()
+			select {
+			case <-dispatched:
+				t.Fatal("the second batch was dispatched while the first one was in flight")
+			case <-time.After(50 * time.Millisecond):
+			}
+
+			publisher.release(test.keys[0])
+			assert.NoError(t, <-dispatched)
+			if test.keys[1] != test.keys[0] {
+				publisher.release(test.keys[1])
+			}
+
+			assert.NoError(t, sink.close())
+			assert.Equal(t, []string{first, second}, publisher.publishedRecords())
+			assert.Equal(t, "Published 2 records in 2 batches to test topic", sink.describe())
+		})
+	}
+}
+
+func TestMessageBusSinkAcknowledgements(t *testing.T) {
+	publisher := &gatedPublisher{}
+	sink, err := newMessageBusSink(publisher, "account", 2, 4)
+	assert.NoError(t, err)
+
+	for _, record := range []string{`{"account":"a"}`, `{"account":"a"}`, `{"account":"b"}`, `{"account":"c"}`} {
+		assert.NoError(t, sink.add([]byte(record)))
+	}
+	assert.NoError(t, sink.add([]byte(`{"account":"d"}`)))
+	assert.Equal(t, 5, sink.added())
+
+	// The second batch is acknowledged first, so only the records before the first batch are acknowledged
+	publisher.release("b")
+	assert.Eventually(t, func() bool { return len(publisher.publishedRecords()) == 2 }, time.Second, time.Millisecond)
+	numAcked, err := sink.acked()
+	assert.NoError(t, err)
+	assert.Equal(t, 0, numAcked)
+
+	publisher.release("a")
+	assert.Eventually(t, func() bool {
+		numAcked, _ := sink.acked()
+		return numAcked == 4
+	}, time.Second, time.Millisecond)
+
+	publisher.release("d")
+	assert.NoError(t, sink.close())
+	numAcked, err = sink.acked()
+	assert.NoError(t, err)
+	assert.Equal(t, 5, numAcked)
+}
+
+// failingPublisher fails the first failures batches, which are retried if retry is set
+type failingPublisher struct {
+	failures int
+	retry    bool
+	attempts int
+}
+
+func (p *failingPublisher) publish(messages []busMessage) (bool, error) {
+	p.attempts += 1
+	if p.attempts <= p.failures {
+		return p.retry, fmt.Errorf("429 Too Many Requests")
+	}
+	return false, nil
+}
+
+func (p *failingPublisher) destination() string {
+	return "test topic"
+}
+
+func TestMessageBusSinkRetries(t *testing.T) {
+	publisher := &failingPublisher{failures: 2, retry: true}
+	sink, err := newMessageBusSink(publisher, "", 10, 1)
+	assert.NoError(t, err)
+	sink.retryWait = 0
+
+	assert.NoError(t, sink.add([]byte(`{"a":1}`)))
+	assert.NoError(t, sink.close())
+	assert.Equal(t, 3, publisher.attempts)
+}
+
+func TestMessageBusSinkStopsAfterFailedBatch(t *testing.T) {
+	publisher := &failingPublisher{failures: 1}
+	sink, err := newMessageBusSink(publisher, "", 1, 1)
+	assert.NoError(t, err)
+
+	assert.NoError(t, sink.add([]byte(`{"a":1}`)))
+	assert.ErrorContains(t, sink.close(), "could not publish batch of 1 records to test topic after 1 attempts: 429 Too Many Requests")
+	assert.Error(t, sink.add([]byte(`{"a":2}`)))
+	_, err = sink.acked()
+	assert.Error(t, err)
+	assert.Equal(t, 1, publisher.attempts)
+}
+
+func TestMessageKey(t *testing.T) {
+	type testCase struct {
+		record   string
+		keyField string
+		want     string
+	}
+
+	tests := []testCase{
+		{record: `{"account":"GABC","ledger":10}`, keyField: "account", want: "GABC"},
+		{record: `{"account":"GABC","ledger":10}`, keyField: "ledger", want: "10"},
+		{record: `{"account":"GABC","ledger":10}`, keyField: "", want: ""},
+		{record: `{"account":null}`, keyField: "account", want: ""},
+		{record: `{"ledger":10}`, keyField: "account", want: ""},
+	}
+
+	for _, test := range tests {
+		key, err := messageKey([]byte(test.record), test.keyField)
+		assert.NoError(t, err)
+		assert.Equal(t, test.want, key)
+	}
+}
+
+func TestPubSubPublisher(t *testing.T) {
+	var messages []pubSubMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/projects/stellar/topics/trades:publish", r.URL.Path)
+		var request struct {
+			Messages []pubSubMessage `json:"messages"`
+		}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		messages = request.Messages
+		io.WriteString(w, `{"messageIds":["1","2"]}`)
+	}))
+	defer server.Close()
+
+	t.Setenv(pubSubEmulatorHostEnvVar, strings.TrimPrefix(server.URL, "http://"))
+	publisher, err := newPubSubPublisher("projects/stellar/topics/trades", "https://pubsub.googleapis.com")
+	assert.NoError(t, err)
+
+	retry, err := publisher.publish([]busMessage{{key: "GABC", record: []byte(`{"a":1}`)}, {record: []byte(`{"a":2}`)}})
+	assert.NoError(t, err)
+	assert.False(t, retry)
+	assert.Equal(t, []pubSubMessage{{Data: []byte(`{"a":1}`), OrderingKey: "GABC"}, {Data: []byte(`{"a":2}`)}}, messages)
+
+	_, err = newPubSubPublisher("trades", "")
+	assert.EqualError(t, err, "pubsub topic must be projects/<project>/topics/<topic>, got trades")
+}
+
+func TestPubSubMessageEncodesData(t *testing.T) {
+	encoded, err := json.Marshal(pubSubMessage{Data: []byte(`{"a":1}`)})
+	assert.NoError(t, err)
+	assert.JSONEq(t, fmt.Sprintf(`{"data":%q}`, base64.StdEncoding.EncodeToString([]byte(`{"a":1}`))), string(encoded))
+}
+
+func TestKafkaRESTPublisher(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests += 1
+		assert.Equal(t, "/topics/stellar-etl.trades", r.URL.Path)
+		assert.Equal(t, "application/vnd.kafka.json.v2+json", r.Header.Get("Content-Type"))
+
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"records":[{"key":"GABC","value":{"a":1}},{"value":{"a":2}}]}`, string(body))
+
+		switch requests {
+		case 1:
+			io.WriteString(w, `{"offsets":[{"partition":0,"offset":null,"error_code":2,"error":"leader not available"},{"partition":1,"offset":7}]}`)
+		case 2:
+			io.WriteString(w, `{"offsets":[{"partition":0,"offset":null,"error_code":1,"error":"record too large"},{"partition":1,"offset":8}]}`)
+		default:
+			io.WriteString(w, `{"offsets":[{"partition":0,"offset":9},{"partition":1,"offset":10}]}`)
+		}
+	}))
+	defer server.Close()
+
+	publisher, err := newKafkaRESTPublisher(server.URL+"/", "stellar-etl.trades")
+	assert.NoError(t, err)
+
+	messages := []busMessage{{key: "GABC", record: []byte(`{"a":1}`)}, {record: []byte(`{"a":2}`)}}
+	retry, err := publisher.publish(messages)
+	assert.EqualError(t, err, "kafka rejected 1 records: leader not available")
+	assert.True(t, retry)
+
+	retry, err = publisher.publish(messages)
+	assert.EqualError(t, err, "kafka rejected 1 records: record too large")
+	assert.False(t, retry)
+
+	retry, err = publisher.publish(messages)
+	assert.NoError(t, err)
+	assert.False(t, retry)
+}
+
+func TestExportEngineCheckpointsAcknowledgedLedgers(t *testing.T) {
+	publisher := &gatedPublisher{}
+	sink, err := newMessageBusSink(publisher, "ledger_sequence", 10, 4)
+	assert.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "upgrades.checkpoint")
+	engine := &exportEngine{
+		cmdArgs:    utils.FlagValues{Limit: -1, Sink: "kafka", CheckpointPath: path},
+		sink:       sink,
+		asyncSinks: []asyncSink{sink},
+	}
+
+	for _, ledgerSeq := range []uint32{10, 11} {
+		engine.beginLedger(ledgerSeq)
+		engine.export(transform.LedgerUpgradeOutput{LedgerSequence: ledgerSeq})
+		engine.flushSink()
+		engine.ledgerStreamed(ledgerSeq, 1)
+	}
+
+	// Ledger 11 is acknowledged before ledger 10, so neither is saved until ledger 10 is acknowledged
+	publisher.release("11")
+	assert.Eventually(t, func() bool { return len(publisher.publishedRecords()) == 1 }, time.Second, time.Millisecond)
+	engine.saveAckedCheckpoint()
+	_, found, err := readCheckpoint(path)
+	assert.NoError(t, err)
+	assert.False(t, found)
+
+	publisher.release("10")
+	assert.NoError(t, sink.close())
+	engine.saveAckedCheckpoint()
+	lastLedger, found, err := readCheckpoint(path)
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, uint32(11), lastLedger)
+	assert.Empty(t, engine.pendingCheckpoints)
+}
@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// checksumOutputFile writes a .sha256 file next to an output file, in the format of sha256sum so that the file can be
+// verified with sha256sum -c. If contentAddressed is set, the output file is first renamed to have its SHA-256 before
+// its extension, so exports with the same content have the same name. It returns the path of the output file and of
+// the checksum file.
+func checksumOutputFile(path string, contentAddressed bool) (outputPath, checksumPath string, err error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", "", fmt.Errorf("could not open %s to checksum it: %v", path, err)
+	}
+
+	hasher := sha256.New()
+	_, err = io.Copy(hasher, file)
+	file.Close()
+	if err != nil {
+		return "", "", fmt.Errorf("could not checksum %s: %v", path, err)
+	}
+	digest := hex.EncodeToString(hasher.Sum(nil))
+
+	outputPath = path
+	if contentAddressed {
+		outputPath = contentAddressedPath(path, digest)
+		if err := os.Rename(path, outputPath); err != nil {
+			return "", "", fmt.Errorf("could not rename %s to its checksum: %v", path, err)
+		}
+	}
+
+	checksumPath = outputPath + ".sha256"
+	contents := fmt.Sprintf("%s  %s\n", digest, filepath.Base(outputPath))
+	if err := os.WriteFile(checksumPath, []byte(contents), 0644); err != nil {
+		return "", "", fmt.Errorf("could not write checksum file %s: %v", checksumPath, err)
+	}
+
+	return outputPath, checksumPath, nil
+}
+
+// contentAddressedPath adds a digest to a file name before its extension, such as trades-<digest>.txt
+func contentAddressedPath(path, digest string) string {
+	ext := filepath.Ext(path)
+	return strings.TrimSuffix(path, ext) + "-" + digest + ext
+}
@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChecksumOutputFile(t *testing.T) {
+	// sha256 of "hello\n"
+	const digest = "5891b5b522d5df086d0ff0b110fbd9d21bb4fc7163af34d08286a2e846f6be03"
+	dir := t.TempDir()
+
+	path := filepath.Join(dir, "exported_trades.txt")
+	assert.NoError(t, os.WriteFile(path, []byte("hello\n"), 0644))
+	outputPath, checksumPath, err := checksumOutputFile(path, false)
+	assert.NoError(t, err)
+	assert.Equal(t, path, outputPath)
+	assert.Equal(t, path+".sha256", checksumPath)
+	contents, err := os.ReadFile(checksumPath)
+	assert.NoError(t, err)
+	assert.Equal(t, digest+"  exported_trades.txt\n", string(contents))
+
+	outputPath, checksumPath, err = checksumOutputFile(path, true)
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "exported_trades-"+digest+".txt"), outputPath)
+	assert.Equal(t, outputPath+".sha256", checksumPath)
+	_, err = os.Stat(path)
+	assert.True(t, os.IsNotExist(err))
+	contents, err = os.ReadFile(checksumPath)
+	assert.NoError(t, err)
+	assert.Equal(t, digest+"  exported_trades-"+digest+".txt\n", string(contents))
+}
@@ -0,0 +1,171 @@
+package cmd
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/stellar/stellar-etl/v2/internal/utils"
+)
+
+// outputIndex collects the ledger and close time range of the records of an export, and the accounts and contracts in
+// them, so that the files of the export can be pruned by query planners without opening them. Every output file of an
+// export gets the same index: it covers all the written records, which is a superset of the records of each file.
+type outputIndex struct {
+	numRecords  int
+	minLedger   uint32
+	maxLedger   uint32
+	minClosedAt time.Time
+	maxClosedAt time.Time
+	accounts    map[[32]byte]struct{}
+}
+
+// outputIndexFile is the contents of a .index.json file. The ranges are null if no record has a ledger or close time.
+// ChunkToken is the chunk-token of the export, so that loaders can skip the files of a chunk they already loaded.
+type outputIndexFile struct {
+	File         string            `json:"file"`
+	Dataset      string            `json:"dataset"`
+	ChunkToken   string            `json:"chunk_token,omitempty"`
+	NumRecords   int               `json:"num_records"`
+	MinLedger    *uint32           `json:"min_ledger"`
+	MaxLedger    *uint32           `json:"max_ledger"`
+	MinClosedAt  *time.Time        `json:"min_closed_at"`
+	MaxClosedAt  *time.Time        `json:"max_closed_at"`
+	AccountBloom accountBloomIndex `json:"account_bloom"`
+}
+
+// accountBloomIndex is the bloom filter of the accounts and contracts of an index, with the hashing of
+// utils.AccountBloom. Bits has the base64 of the little endian words of the filter.
+type accountBloomIndex struct {
+	NumAccounts int    `json:"num_accounts"`
+	NumBits     uint64 `json:"num_bits"`
+	NumHashes   int    `json:"num_hashes"`
+	Bits        string `json:"bits"`
+}
+
+// closedAtFields are the fields with the close time of the ledger of a record
+var closedAtFields = []string{"closed_at", "ledger_closed_at"}
+
+func newOutputIndex() *outputIndex {
+	return &outputIndex{accounts: map[[32]byte]struct{}{}}
+}
+
+// add indexes a record exported from a ledger. The close time is read from the closed_at or ledger_closed_at field of
+// the record, and every account, muxed account and contract address in its fields is added to the accounts.
+func (i *outputIndex) add(ledgerSeq uint32, marshalled []byte) error {
+	var fields map[string]interface{}
+	if err := json.Unmarshal(marshalled, &fields); err != nil {
+		return fmt.Errorf("could not index record: %v", err)
+	}
+
+	i.numRecords += 1
+	if ledgerSeq != 0 {
+		if i.minLedger == 0 || ledgerSeq < i.minLedger {
+			i.minLedger = ledgerSeq
+		}
+		i.maxLedger = max(i.maxLedger, ledgerSeq)
+	}
+
+	for _, field := range closedAtFields {
+		value, ok := fields[field].(string)
+		if !ok {
+			continue
+		}
+		closedAt, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			continue
+		}
+		if i.minClosedAt.IsZero() || closedAt.Before(i.minClosedAt) {
+			i.minClosedAt = closedAt
+		}
+		if closedAt.After(i.maxClosedAt) {
+			i.maxClosedAt = closedAt
+		}
+	}
+
+	i.addAccounts(fields)
+	return nil
+}
+
+// addAccounts adds the addresses in a decoded JSON value and the values nested in it to the accounts
+func (i *outputIndex) addAccounts(value interface{}) {
+	switch v := value.(type) {
+	case string:
+		if !looksLikeAddress(v) {
+			return
+		}
+		if key, err := utils.AddressKey(v); err == nil {
+			i.accounts[key] = struct{}{}
+		}
+	case map[string]interface{}:
+		for _, nested := range v {
+			i.addAccounts(nested)
+		}
+	case []interface{}:
+		for _, nested := range v {
+			i.addAccounts(nested)
+		}
+	}
+}
+
+// looksLikeAddress checks the length and prefix of a string before it is decoded, so most strings are skipped cheaply
+func looksLikeAddress(value string) bool {
+	switch len(value) {
+	case 56:
+		return value[0] == 'G' || value[0] == 'C'
+	case 69:
+		return value[0] == 'M'
+	default:
+		return false
+	}
+}
+
+// contents returns the index file of an output file
+func (i *outputIndex) contents(outputPath, dataset, chunkToken string) outputIndexFile {
+	bloom := utils.NewAccountBloom(len(i.accounts))
+	for key := range i.accounts {
+		bloom.Add(key[:])
+	}
+	bits := make([]byte, 8*len(bloom.Bits))
+	for n, word := range bloom.Bits {
+		binary.LittleEndian.PutUint64(bits[8*n:], word)
+	}
+
+	file := outputIndexFile{
+		File:       filepath.Base(outputPath),
+		Dataset:    dataset,
+		ChunkToken: chunkToken,
+		NumRecords: i.numRecords,
+		AccountBloom: accountBloomIndex{
+			NumAccounts: len(i.accounts),
+			NumBits:     bloom.NumBits,
+			NumHashes:   utils.AccountBloomNumHashes,
+			Bits:        base64.StdEncoding.EncodeToString(bits),
+		},
+	}
+	if i.minLedger != 0 {
+		file.MinLedger, file.MaxLedger = &i.minLedger, &i.maxLedger
+	}
+	if !i.minClosedAt.IsZero() {
+		file.MinClosedAt, file.MaxClosedAt = &i.minClosedAt, &i.maxClosedAt
+	}
+	return file
+}
+
+// write writes the index of an output file next to it, as <output>.index.json, and returns the path of the index file
+func (i *outputIndex) write(outputPath, dataset, chunkToken string) (string, error) {
+	contents, err := json.Marshal(i.contents(outputPath, dataset, chunkToken))
+	if err != nil {
+		return "", fmt.Errorf("could not encode the index of %s: %v", outputPath, err)
+	}
+
+	indexPath := outputPath + ".index.json"
+	if err := os.WriteFile(indexPath, append(contents, '\n'), 0644); err != nil {
+		return "", fmt.Errorf("could not write index file %s: %v", indexPath, err)
+	}
+	return indexPath, nil
+}
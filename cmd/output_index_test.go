@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stellar/go-stellar-sdk/keypair"
+	"github.com/stellar/stellar-etl/v2/internal/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOutputIndex(t *testing.T) {
+	source, destination, other := keypair.MustRandom().Address(), keypair.MustRandom().Address(), keypair.MustRandom().Address()
+	index := newOutputIndex()
+	assert.NoError(t, index.add(31, []byte(`{"source_account":"`+source+`","closed_at":"2024-02-01T10:00:05Z","details":{"to":"`+destination+`"}}`)))
+	assert.NoError(t, index.add(30, []byte(`{"source_account":"`+source+`","closed_at":"2024-02-01T10:00:00Z","memo":"GABC"}`)))
+
+	path := filepath.Join(t.TempDir(), "exported_operations.txt")
+	indexPath, err := index.write(path, "operations", "3f9a1c")
+	assert.NoError(t, err)
+	assert.Equal(t, path+".index.json", indexPath)
+
+	contents, err := os.ReadFile(indexPath)
+	assert.NoError(t, err)
+	var file outputIndexFile
+	assert.NoError(t, json.Unmarshal(contents, &file))
+	assert.Equal(t, "exported_operations.txt", file.File)
+	assert.Equal(t, "operations", file.Dataset)
+	assert.Equal(t, "3f9a1c", file.ChunkToken)
+	assert.Equal(t, 2, file.NumRecords)
+	assert.Equal(t, uint32(30), *file.MinLedger)
+	assert.Equal(t, uint32(31), *file.MaxLedger)
+	assert.True(t, time.Date(2024, 2, 1, 10, 0, 0, 0, time.UTC).Equal(*file.MinClosedAt))
+	assert.True(t, time.Date(2024, 2, 1, 10, 0, 5, 0, time.UTC).Equal(*file.MaxClosedAt))
+	assert.Equal(t, 2, file.AccountBloom.NumAccounts)
+
+	// The bloom filter is read back the way a query planner would read it
+	bits, err := base64.StdEncoding.DecodeString(file.AccountBloom.Bits)
+	assert.NoError(t, err)
+	bloom := utils.AccountBloom{NumBits: file.AccountBloom.NumBits}
+	for n := 0; n < len(bits); n += 8 {
+		bloom.Bits = append(bloom.Bits, binary.LittleEndian.Uint64(bits[n:]))
+	}
+	for _, address := range []string{source, destination} {
+		key, err := utils.AddressKey(address)
+		assert.NoError(t, err)
+		assert.True(t, bloom.MightContain(key[:]))
+	}
+	key, err := utils.AddressKey(other)
+	assert.NoError(t, err)
+	assert.False(t, bloom.MightContain(key[:]))
+
+	// Files without records have no ranges
+	empty, err := json.Marshal(newOutputIndex().contents(path, "operations", ""))
+	assert.NoError(t, err)
+	assert.Contains(t, string(empty), `"min_ledger":null,"max_ledger":null,"min_closed_at":null,"max_closed_at":null`)
+}
@@ -0,0 +1,244 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/stellar/stellar-etl/v2/internal/input"
+	"github.com/stellar/stellar-etl/v2/internal/utils"
+)
+
+// exportPlan is the list of export invocations that covers a ledger range, so that orchestrators such as Airflow or
+// Dagster can generate their tasks from it instead of repeating the range math
+type exportPlan struct {
+	Network     string             `json:"network"`
+	StartTime   string             `json:"start_time,omitempty"`
+	EndTime     string             `json:"end_time,omitempty"`
+	StartLedger uint32             `json:"start_ledger"`
+	EndLedger   uint32             `json:"end_ledger"`
+	ChunkSize   uint32             `json:"chunk_size"`
+	Invocations []exportInvocation `json:"invocations"`
+}
+
+// exportInvocation is a single export command of a plan. Args are the arguments of stellar-etl, starting with the
+// command. Output is the JSON output file of the export, which is empty for ledger_entry_changes since it is exported
+// to a folder. ChunkToken is the idempotency token of the chunk, which is passed to the export as chunk-token.
+type exportInvocation struct {
+	Dataset     string   `json:"dataset"`
+	Command     string   `json:"command"`
+	StartLedger uint32   `json:"start_ledger"`
+	EndLedger   uint32   `json:"end_ledger"`
+	ChunkToken  string   `json:"chunk_token"`
+	Output      string   `json:"output,omitempty"`
+	Args        []string `json:"args"`
+}
+
+var planCmd = &cobra.Command{
+	Use:   "plan [-- export flags]",
+	Short: "Prints the chunked export invocations that cover a time range",
+	Long: `Converts a time range into a ledger range, splits it into chunks of chunk-size ledgers and prints the export
+command of every chunk and dataset as JSON, with the resolved ledger range and output file of every invocation. Times
+are in the same format as get_ledger_range_from_times. Flags after -- are added to every invocation, for example
+-- --cloud-provider gcp --cloud-storage-bucket my-bucket.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		startString, err := cmd.Flags().GetString("start-time")
+		if err != nil {
+			cmdLogger.Fatal("could not get start time: ", err)
+		}
+
+		endString, err := cmd.Flags().GetString("end-time")
+		if err != nil {
+			cmdLogger.Fatal("could not get end time: ", err)
+		}
+
+		datasets, err := cmd.Flags().GetStringSlice("datasets")
+		if err != nil {
+			cmdLogger.Fatal("could not get datasets: ", err)
+		}
+
+		chunkSize, err := cmd.Flags().GetUint32("chunk-size")
+		if err != nil {
+			cmdLogger.Fatal("could not get chunk-size: ", err)
+		}
+
+		filenameTemplate, err := cmd.Flags().GetString("filename-template")
+		if err != nil {
+			cmdLogger.Fatal("could not get filename-template: ", err)
+		}
+
+		path, err := cmd.Flags().GetString("output")
+		if err != nil {
+			cmdLogger.Fatal("could not get output path: ", err)
+		}
+
+		isTest, err := cmd.Flags().GetBool("testnet")
+		if err != nil {
+			cmdLogger.Fatal("could not get testnet boolean: ", err)
+		}
+
+		isFuture, err := cmd.Flags().GetBool("futurenet")
+		if err != nil {
+			cmdLogger.Fatal("could not get futurenet boolean: ", err)
+		}
+
+		formatString := "2006-01-02T15:04:05-07:00"
+		startTime, err := time.Parse(formatString, startString)
+		if err != nil {
+			cmdLogger.Fatal("could not parse start time: ", err)
+		}
+
+		endTime, err := time.Parse(formatString, endString)
+		if err != nil {
+			cmdLogger.Fatal("could not parse end time: ", err)
+		}
+
+		startLedger, endLedger, err := input.GetLedgerRange(startTime, endTime, isTest, isFuture)
+		if err != nil {
+			cmdLogger.Fatal("could not calculate ledger range: ", err)
+		}
+
+		plan, err := buildExportPlan(datasets, uint32(startLedger), uint32(endLedger), chunkSize, utils.NetworkName(isTest, isFuture), filenameTemplate, args)
+		if err != nil {
+			cmdLogger.Fatal(err)
+		}
+		plan.StartTime = startTime.UTC().Format(time.RFC3339)
+		plan.EndTime = endTime.UTC().Format(time.RFC3339)
+
+		marshalled, err := json.MarshalIndent(plan, "", "  ")
+		if err != nil {
+			cmdLogger.Fatal("could not json encode plan: ", err)
+		}
+
+		if path != "" {
+			outFile := MustOutFile(path)
+			outFile.Write(marshalled)
+			outFile.WriteString("\n")
+			outFile.Close()
+		} else {
+			fmt.Println(string(marshalled))
+		}
+	},
+}
+
+// buildExportPlan splits the inclusive range [start, end] into chunks of chunkSize ledgers, and returns the export
+// invocation of every chunk and dataset, in the order of the chunks. The extra arguments come last so they can
+// override the flags of the plan.
+func buildExportPlan(datasets []string, start, end, chunkSize uint32, network, filenameTemplate string, extraArgs []string) (exportPlan, error) {
+	if len(datasets) == 0 {
+		return exportPlan{}, fmt.Errorf("datasets must name at least one dataset")
+	}
+	for _, dataset := range datasets {
+		if err := validatePlanDataset(dataset); err != nil {
+			return exportPlan{}, err
+		}
+	}
+
+	if chunkSize == 0 {
+		return exportPlan{}, fmt.Errorf("chunk-size must be positive")
+	}
+	if end < start {
+		return exportPlan{}, fmt.Errorf("end ledger %d is before start ledger %d", end, start)
+	}
+
+	if !strings.Contains(filenameTemplate, "{ext}") {
+		return exportPlan{}, fmt.Errorf("filename-template %s needs an {ext} placeholder", filenameTemplate)
+	}
+	if _, err := utils.ResolveFilenameTemplate(filenameTemplate, "", "", "", 0, 0, ""); err != nil {
+		return exportPlan{}, fmt.Errorf("invalid filename-template: %v", err)
+	}
+
+	plan := exportPlan{Network: network, StartLedger: start, EndLedger: end, ChunkSize: chunkSize, Invocations: []exportInvocation{}}
+	for chunkStart := uint64(start); chunkStart <= uint64(end); chunkStart += uint64(chunkSize) {
+		chunkEnd := min(chunkStart+uint64(chunkSize)-1, uint64(end))
+		for _, dataset := range datasets {
+			invocation, err := planInvocation(dataset, uint32(chunkStart), uint32(chunkEnd), network, filenameTemplate, extraArgs)
+			if err != nil {
+				return exportPlan{}, err
+			}
+			plan.Invocations = append(plan.Invocations, invocation)
+		}
+	}
+
+	return plan, nil
+}
+
+// planInvocation builds the export command of a dataset over the inclusive range [start, end]. Single file exports
+// are named with the filename template, so the chunks of a dataset don't overwrite each other.
+func planInvocation(dataset string, start, end uint32, network, filenameTemplate string, extraArgs []string) (exportInvocation, error) {
+	command := "export_" + dataset
+	token := chunkToken(network, dataset, start, end)
+	args := []string{
+		command,
+		"--start-ledger", strconv.FormatUint(uint64(start), 10),
+		"--end-ledger", strconv.FormatUint(uint64(end), 10),
+		"--filename-template", filenameTemplate,
+		"--chunk-token", token,
+	}
+
+	var output string
+	if dataset != "ledger_entry_changes" {
+		var err error
+		output, err = utils.ResolveFilenameTemplate(filenameTemplate, network, dataset, token, start, end, "txt")
+		if err != nil {
+			return exportInvocation{}, err
+		}
+	}
+
+	switch network {
+	case "testnet":
+		args = append(args, "--testnet")
+	case "futurenet":
+		args = append(args, "--futurenet")
+	}
+
+	return exportInvocation{
+		Dataset:     dataset,
+		Command:     command,
+		StartLedger: start,
+		EndLedger:   end,
+		ChunkToken:  token,
+		Output:      output,
+		Args:        append(args, extraArgs...),
+	}, nil
+}
+
+// chunkToken returns the idempotency token of the export of a dataset over the inclusive range [start, end]. It only
+// depends on the chunk, so a retried or replanned chunk gets the same token and overwrites the outputs of the earlier
+// attempts instead of adding overlapping ones.
+func chunkToken(network, dataset string, start, end uint32) string {
+	hash := sha256.Sum256([]byte(fmt.Sprintf("%s/%s/%d-%d", network, dataset, start, end)))
+	return hex.EncodeToString(hash[:8])
+}
+
+// validatePlanDataset checks that a dataset has an export command
+func validatePlanDataset(dataset string) error {
+	exportCmd, _, err := rootCmd.Find([]string{"export_" + dataset})
+	if err != nil || exportCmd.Name() != "export_"+dataset {
+		return fmt.Errorf("unsupported dataset %s; there is no export_%s command", dataset, dataset)
+	}
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(planCmd)
+
+	planCmd.Flags().StringP("start-time", "s", "", "The start time")
+	planCmd.Flags().StringP("end-time", "e", "", "The end time")
+	planCmd.Flags().StringSlice("datasets", nil, "Comma separated datasets to export, named after their export commands such as ledgers or contract_events.")
+	planCmd.Flags().Uint32("chunk-size", 17280, "Number of ledgers exported by each invocation. The last chunk of the range can be smaller.")
+	planCmd.Flags().String("filename-template", "{dataset}_{network}_{start}_{end}_{token}.{ext}", "Template for the names of the output files of every invocation, passed to it as filename-template.")
+	planCmd.Flags().StringP("output", "o", "", "If set, the plan is written to this file instead of stdout")
+	planCmd.Flags().Bool("testnet", false, "If set, the plan is for testnet instead of mainnet.")
+	planCmd.Flags().Bool("futurenet", false, "If set, the plan is for futurenet instead of mainnet.")
+
+	planCmd.MarkFlagRequired("start-time")
+	planCmd.MarkFlagRequired("end-time")
+	planCmd.MarkFlagRequired("datasets")
+}
@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildExportPlan(t *testing.T) {
+	plan, err := buildExportPlan([]string{"operations", "ledger_entry_changes"}, 100, 250, 100, "testnet", "{dataset}_{start}_{end}_{token}.{ext}", []string{"--cloud-provider", "gcp"})
+	assert.NoError(t, err)
+	assert.Equal(t, exportPlan{
+		Network:     "testnet",
+		StartLedger: 100,
+		EndLedger:   250,
+		ChunkSize:   100,
+		Invocations: []exportInvocation{
+			{
+				Dataset:     "operations",
+				Command:     "export_operations",
+				StartLedger: 100,
+				EndLedger:   199,
+				ChunkToken:  "934f5d2c16d82d11",
+				Output:      "operations_100_199_934f5d2c16d82d11.txt",
+				Args:        []string{"export_operations", "--start-ledger", "100", "--end-ledger", "199", "--filename-template", "{dataset}_{start}_{end}_{token}.{ext}", "--chunk-token", "934f5d2c16d82d11", "--testnet", "--cloud-provider", "gcp"},
+			},
+			{
+				Dataset:     "ledger_entry_changes",
+				Command:     "export_ledger_entry_changes",
+				StartLedger: 100,
+				EndLedger:   199,
+				ChunkToken:  "a65a152d06658944",
+				Args:        []string{"export_ledger_entry_changes", "--start-ledger", "100", "--end-ledger", "199", "--filename-template", "{dataset}_{start}_{end}_{token}.{ext}", "--chunk-token", "a65a152d06658944", "--testnet", "--cloud-provider", "gcp"},
+			},
+			{
+				Dataset:     "operations",
+				Command:     "export_operations",
+				StartLedger: 200,
+				EndLedger:   250,
+				ChunkToken:  "7fab332b4859aa3f",
+				Output:      "operations_200_250_7fab332b4859aa3f.txt",
+				Args:        []string{"export_operations", "--start-ledger", "200", "--end-ledger", "250", "--filename-template", "{dataset}_{start}_{end}_{token}.{ext}", "--chunk-token", "7fab332b4859aa3f", "--testnet", "--cloud-provider", "gcp"},
+			},
+			{
+				Dataset:     "ledger_entry_changes",
+				Command:     "export_ledger_entry_changes",
+				StartLedger: 200,
+				EndLedger:   250,
+				ChunkToken:  "8db6e75997961f7d",
+				Args:        []string{"export_ledger_entry_changes", "--start-ledger", "200", "--end-ledger", "250", "--filename-template", "{dataset}_{start}_{end}_{token}.{ext}", "--chunk-token", "8db6e75997961f7d", "--testnet", "--cloud-provider", "gcp"},
+			},
+		},
+	}, plan)
+}
+
+func TestBuildExportPlanErrors(t *testing.T) {
+	type testCase struct {
+		name      string
+		datasets  []string
+		start     uint32
+		end       uint32
+		chunkSize uint32
+		template  string
+		wantErr   string
+	}
+
+	tests := []testCase{
+		{name: "unknown dataset", datasets: []string{"orderbooks"}, start: 1, end: 2, chunkSize: 1, template: "{dataset}.{ext}", wantErr: "unsupported dataset orderbooks; there is no export_orderbooks command"},
+		{name: "no datasets", datasets: []string{}, start: 1, end: 2, chunkSize: 1, template: "{dataset}.{ext}", wantErr: "datasets must name at least one dataset"},
+		{name: "zero chunk size", datasets: []string{"ledgers"}, start: 1, end: 2, chunkSize: 0, template: "{dataset}.{ext}", wantErr: "chunk-size must be positive"},
+		{name: "reversed range", datasets: []string{"ledgers"}, start: 5, end: 2, chunkSize: 1, template: "{dataset}.{ext}", wantErr: "end ledger 2 is before start ledger 5"},
+		{name: "template without ext", datasets: []string{"ledgers"}, start: 1, end: 2, chunkSize: 1, template: "{dataset}", wantErr: "filename-template {dataset} needs an {ext} placeholder"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := buildExportPlan(test.datasets, test.start, test.end, test.chunkSize, "pubnet", test.template, nil)
+			assert.EqualError(t, err, test.wantErr)
+		})
+	}
+}
+
+func TestBuildExportPlanLastLedger(t *testing.T) {
+	plan, err := buildExportPlan([]string{"ledgers"}, 4294967290, 4294967295, 4, "pubnet", "{dataset}_{start}_{end}.{ext}", nil)
+	assert.NoError(t, err)
+	assert.Len(t, plan.Invocations, 2)
+	assert.Equal(t, uint32(4294967295), plan.Invocations[1].EndLedger)
+}
+
+func TestChunkToken(t *testing.T) {
+	// Retries of a chunk get the same token, and any other chunk gets a different one
+	assert.Equal(t, chunkToken("pubnet", "operations", 100, 199), chunkToken("pubnet", "operations", 100, 199))
+	assert.NotEqual(t, chunkToken("pubnet", "operations", 100, 199), chunkToken("pubnet", "operations", 100, 200))
+	assert.NotEqual(t, chunkToken("pubnet", "operations", 100, 199), chunkToken("testnet", "operations", 100, 199))
+	assert.NotEqual(t, chunkToken("pubnet", "operations", 100, 199), chunkToken("pubnet", "trades", 100, 199))
+}
@@ -0,0 +1,189 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+)
+
+const (
+	oversizedRecordsTruncate = "truncate"
+	oversizedRecordsSpill    = "spill"
+	oversizedRecordsDrop     = "drop"
+)
+
+// recordSizeGuard keeps the JSON records of an export under max-record-bytes, so a single enormous record can't break
+// the row limits of a load. Oversized records are truncated, spilled in full to a side file, or dropped with an error.
+type recordSizeGuard struct {
+	maxBytes  int
+	mode      string
+	spillPath string
+	spillFile *os.File
+
+	numTruncated int
+	numSpilled   int
+}
+
+func newRecordSizeGuard(maxBytes int, mode, spillPath string) (*recordSizeGuard, error) {
+	if maxBytes < 0 {
+		return nil, fmt.Errorf("max-record-bytes can't be negative, got %d", maxBytes)
+	}
+
+	switch mode {
+	case oversizedRecordsTruncate, oversizedRecordsSpill, oversizedRecordsDrop:
+	default:
+		return nil, fmt.Errorf("unsupported oversized-records %s; expected truncate, spill or drop", mode)
+	}
+
+	if mode == oversizedRecordsSpill && spillPath == "" {
+		return nil, fmt.Errorf("oversized-output is required to spill oversized records")
+	}
+
+	return &recordSizeGuard{maxBytes: maxBytes, mode: mode, spillPath: spillPath}, nil
+}
+
+// oversized returns true if the record is larger than max-record-bytes
+func (g *recordSizeGuard) oversized(marshalled []byte) bool {
+	return len(marshalled) > g.maxBytes
+}
+
+// handle deals with an oversized record. It returns the record to write in its place, or nil if the record was spilled
+// to the side file. Dropped records and records that can't be truncated enough are returned as errors.
+func (g *recordSizeGuard) handle(marshalled []byte) ([]byte, error) {
+	switch g.mode {
+	case oversizedRecordsTruncate:
+		truncated, fields, err := truncateRecord(marshalled, g.maxBytes)
+		if err != nil {
+			return nil, err
+		}
+		cmdLogger.Warnf("Truncated fields %v of a record of %d bytes to fit in %d bytes", fields, len(marshalled), g.maxBytes)
+		g.numTruncated += 1
+		return truncated, nil
+	case oversizedRecordsSpill:
+		if g.spillFile == nil {
+			g.spillFile = MustOutFile(g.spillPath)
+		}
+		writeEntryLine(marshalled, g.spillFile)
+		g.numSpilled += 1
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("dropped a record of %d bytes that exceeds max-record-bytes of %d", len(marshalled), g.maxBytes)
+	}
+}
+
+// close closes the side file, and returns true if any records were spilled to it
+func (g *recordSizeGuard) close() bool {
+	if g.spillFile == nil {
+		return false
+	}
+	g.spillFile.Close()
+	return true
+}
+
+// describe summarizes how the oversized records were handled, for the export logs
+func (g *recordSizeGuard) describe() string {
+	switch g.mode {
+	case oversizedRecordsTruncate:
+		return fmt.Sprintf("Truncated %d records larger than %d bytes", g.numTruncated, g.maxBytes)
+	case oversizedRecordsSpill:
+		return fmt.Sprintf("Spilled %d records larger than %d bytes to %s", g.numSpilled, g.maxBytes, g.spillPath)
+	default:
+		return fmt.Sprintf("Dropped the records larger than %d bytes", g.maxBytes)
+	}
+}
+
+// truncateRecord empties the largest strings, arrays and objects of a JSON record until it fits in maxBytes, and
+// returns the truncated record with the paths of the emptied fields. Objects such as details are truncated field by
+// field when that is enough, so their small fields are kept.
+func truncateRecord(marshalled []byte, maxBytes int) ([]byte, []string, error) {
+	record := map[string]interface{}{}
+	decoder := json.NewDecoder(bytes.NewReader(marshalled))
+	decoder.UseNumber()
+	if err := decoder.Decode(&record); err != nil {
+		return nil, nil, fmt.Errorf("could not decode oversized record: %v", err)
+	}
+
+	fields := []string{}
+	shrinkObject(record, "", len(marshalled)-maxBytes, &fields)
+	sort.Strings(fields)
+
+	truncated, err := json.Marshal(record)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not encode truncated record: %v", err)
+	}
+	if len(truncated) > maxBytes {
+		return nil, nil, fmt.Errorf("record of %d bytes is still %d bytes after truncating %v, which exceeds max-record-bytes of %d",
+			len(marshalled), len(truncated), fields, maxBytes)
+	}
+
+	return truncated, fields, nil
+}
+
+// shrinkObject empties the largest values of an object until at least excess bytes are saved, and returns the number
+// of bytes saved. The paths of the emptied values are added to fields.
+func shrinkObject(object map[string]interface{}, prefix string, excess int, fields *[]string) int {
+	saved := 0
+	for saved < excess {
+		key, size := largestValue(object)
+		if key == "" {
+			break
+		}
+
+		// Emptying a whole object that is larger than needed would lose its small fields, so its largest values are
+		// emptied first
+		if child, ok := object[key].(map[string]interface{}); ok && size-2 > excess-saved {
+			saved += shrinkObject(child, prefix+key+".", excess-saved, fields)
+			if saved >= excess {
+				break
+			}
+			size = encodedSize(child)
+		}
+
+		object[key] = emptyValue(object[key])
+		saved += size - 2
+		*fields = append(*fields, prefix+key)
+	}
+
+	return saved
+}
+
+// largestValue returns the key and the encoded size of the largest string, array or object in an object that isn't
+// empty yet, or an empty key if there isn't one
+func largestValue(object map[string]interface{}) (string, int) {
+	largestKey, largestSize := "", 2
+	for key, value := range object {
+		switch value.(type) {
+		case string, []interface{}, map[string]interface{}:
+		default:
+			continue
+		}
+
+		size := encodedSize(value)
+		if size > largestSize || (size == largestSize && size > 2 && key < largestKey) {
+			largestKey, largestSize = key, size
+		}
+	}
+	return largestKey, largestSize
+}
+
+func encodedSize(value interface{}) int {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return 0
+	}
+	return len(encoded)
+}
+
+// emptyValue returns the empty value of the same JSON type, so that truncated fields keep their column types
+func emptyValue(value interface{}) interface{} {
+	switch value.(type) {
+	case []interface{}:
+		return []interface{}{}
+	case map[string]interface{}:
+		return map[string]interface{}{}
+	default:
+		return ""
+	}
+}
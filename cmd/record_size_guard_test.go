@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stellar/stellar-etl/v2/internal/transform"
+	"github.com/stellar/stellar-etl/v2/internal/utils"
+)
+
+func TestTruncateRecord(t *testing.T) {
+	largeParameter := strings.Repeat("a", 100)
+	record := []byte(`{"details":{"contract_id":"C1","parameters":["` + largeParameter + `"]},"id":12884905985,"memo":"short"}`)
+
+	truncated, fields, err := truncateRecord(record, 80)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"details":{"contract_id":"C1","parameters":[]},"id":12884905985,"memo":"short"}`, string(truncated))
+	assert.Equal(t, []string{"details.parameters"}, fields)
+
+	truncated, fields, err = truncateRecord(record, 41)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"details":{},"id":12884905985,"memo":""}`, string(truncated))
+	assert.Equal(t, []string{"details", "memo"}, fields)
+
+	_, _, err = truncateRecord(record, 40)
+	assert.Error(t, err)
+}
+
+func TestNewRecordSizeGuard(t *testing.T) {
+	_, err := newRecordSizeGuard(100, "compress", "")
+	assert.Error(t, err)
+
+	_, err = newRecordSizeGuard(100, oversizedRecordsSpill, "")
+	assert.Error(t, err)
+
+	_, err = newRecordSizeGuard(-1, oversizedRecordsDrop, "")
+	assert.Error(t, err)
+}
+
+func TestExportEngineHandlesOversizedRecords(t *testing.T) {
+	largeDetails := map[string]interface{}{"parameters": strings.Repeat("a", 1000)}
+	records := []transform.OperationOutput{
+		{OperationID: 1, OperationDetails: map[string]interface{}{}},
+		{OperationID: 2, OperationDetails: largeDetails},
+	}
+
+	type testCase struct {
+		mode          string
+		wantExported  int
+		wantFailures  int
+		wantOutput    int
+		wantSpilled   int
+		wantTruncated int
+	}
+
+	tests := []testCase{
+		{mode: oversizedRecordsDrop, wantExported: 1, wantFailures: 1, wantOutput: 1},
+		{mode: oversizedRecordsSpill, wantExported: 2, wantOutput: 1, wantSpilled: 1},
+		{mode: oversizedRecordsTruncate, wantExported: 2, wantOutput: 2, wantTruncated: 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.mode, func(t *testing.T) {
+			dir := t.TempDir()
+			cmdArgs := utils.FlagValues{
+				Path:             filepath.Join(dir, "operations.txt"),
+				OversizedPath:    filepath.Join(dir, "operations_oversized.txt"),
+				Limit:            -1,
+				MaxRecordBytes:   500,
+				OversizedRecords: test.mode,
+			}
+			engine := newExportEngine(cmdArgs, utils.EnvironmentDetails{}, nil, nil)
+			for _, record := range records {
+				engine.attempt()
+				engine.export(record)
+			}
+			engine.outFile.Close()
+			spilled := engine.sizeGuard.close()
+
+			assert.Equal(t, test.wantExported, engine.numExported)
+			assert.Equal(t, test.wantFailures, engine.numFailures)
+			assert.Equal(t, test.wantTruncated, engine.sizeGuard.numTruncated)
+			assert.Equal(t, test.wantSpilled, engine.sizeGuard.numSpilled)
+			assert.Equal(t, test.wantSpilled > 0, spilled)
+
+			contents, err := os.ReadFile(cmdArgs.Path)
+			assert.NoError(t, err)
+			lines := strings.Split(strings.TrimSpace(string(contents)), "\n")
+			assert.Len(t, lines, test.wantOutput)
+			for _, line := range lines {
+				assert.LessOrEqual(t, len(line), 500)
+			}
+
+			if test.wantSpilled > 0 {
+				spilledContents, err := os.ReadFile(cmdArgs.OversizedPath)
+				assert.NoError(t, err)
+				assert.Contains(t, string(spilledContents), largeDetails["parameters"])
+			}
+		})
+	}
+}
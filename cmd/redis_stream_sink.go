@@ -0,0 +1,185 @@
+package cmd
+
+import (
+	"bufio"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// redisStreamSink publishes every exported record as an entry of a Redis Stream, with the JSON encoded record in the
+// record field. Queued records are sent in a single pipeline when the sink is flushed.
+type redisStreamSink struct {
+	stream string
+	maxLen int64
+	conn   net.Conn
+	reader *bufio.Reader
+
+	pending    [][]byte
+	numEntries int
+}
+
+// newRedisStreamSink connects to the Redis server at a redis:// or rediss:// url. The url can include a password and a
+// database number, such as redis://:password@localhost:6379/2. A maxLen above 0 approximately trims the stream to that length.
+func newRedisStreamSink(redisURL, stream string, maxLen int64) (*redisStreamSink, error) {
+	if stream == "" {
+		return nil, fmt.Errorf("redis-stream is required for the redis-stream sink")
+	}
+
+	parsed, err := url.Parse(redisURL)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse redis url: %v", err)
+	}
+
+	address := parsed.Host
+	if parsed.Port() == "" {
+		address = net.JoinHostPort(parsed.Hostname(), "6379")
+	}
+
+	dialer := &net.Dialer{Timeout: 30 * time.Second}
+	var conn net.Conn
+	switch parsed.Scheme {
+	case "redis":
+		conn, err = dialer.Dial("tcp", address)
+	case "rediss":
+		conn, err = tls.DialWithDialer(dialer, "tcp", address, &tls.Config{ServerName: parsed.Hostname()})
+	default:
+		return nil, fmt.Errorf("unsupported redis url scheme %s; expected redis or rediss", parsed.Scheme)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not connect to redis at %s: %v", address, err)
+	}
+
+	sink := &redisStreamSink{
+		stream: stream,
+		maxLen: maxLen,
+		conn:   conn,
+		reader: bufio.NewReader(conn),
+	}
+
+	if password, ok := parsed.User.Password(); ok {
+		args := []string{"AUTH", password}
+		if username := parsed.User.Username(); username != "" {
+			args = []string{"AUTH", username, password}
+		}
+		if _, err := sink.call(args...); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("could not authenticate with redis: %v", err)
+		}
+	}
+
+	if db := strings.TrimPrefix(parsed.Path, "/"); db != "" {
+		if _, err := sink.call("SELECT", db); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("could not select redis database %s: %v", db, err)
+		}
+	}
+
+	return sink, nil
+}
+
+// add queues an encoded record until the next flush
+func (r *redisStreamSink) add(record []byte) error {
+	r.pending = append(r.pending, record)
+	return nil
+}
+
+// flush adds the queued records to the stream
+func (r *redisStreamSink) flush() error {
+	if len(r.pending) == 0 {
+		return nil
+	}
+
+	writer := bufio.NewWriter(r.conn)
+	for _, record := range r.pending {
+		args := []string{"XADD", r.stream}
+		if r.maxLen > 0 {
+			args = append(args, "MAXLEN", "~", strconv.FormatInt(r.maxLen, 10))
+		}
+		args = append(args, "*", "record", string(record))
+		writeRedisCommand(writer, args)
+	}
+	if err := writer.Flush(); err != nil {
+		return fmt.Errorf("could not write to redis: %v", err)
+	}
+
+	// Read every reply, so the connection stays usable even if an entry was rejected
+	var firstErr error
+	for range r.pending {
+		if _, err := readRedisReply(r.reader); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	if firstErr != nil {
+		return fmt.Errorf("could not add records to redis stream %s: %v", r.stream, firstErr)
+	}
+
+	r.numEntries += len(r.pending)
+	r.pending = r.pending[:0]
+	return nil
+}
+
+func (r *redisStreamSink) close() error {
+	return r.conn.Close()
+}
+
+func (r *redisStreamSink) describe() string {
+	return fmt.Sprintf("Added %d entries to redis stream %s", r.numEntries, r.stream)
+}
+
+// call sends a single command and returns its reply
+func (r *redisStreamSink) call(args ...string) (string, error) {
+	writer := bufio.NewWriter(r.conn)
+	writeRedisCommand(writer, args)
+	if err := writer.Flush(); err != nil {
+		return "", err
+	}
+	return readRedisReply(r.reader)
+}
+
+// writeRedisCommand encodes a command as a RESP array of bulk strings
+func writeRedisCommand(w *bufio.Writer, args []string) {
+	fmt.Fprintf(w, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(w, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+}
+
+// readRedisReply reads a simple string, error, integer or bulk string reply
+func readRedisReply(r *bufio.Reader) (string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	line = strings.TrimSuffix(line, "\r\n")
+	if line == "" {
+		return "", fmt.Errorf("empty redis reply")
+	}
+
+	switch line[0] {
+	case '+', ':':
+		return line[1:], nil
+	case '-':
+		return "", fmt.Errorf("redis error: %s", line[1:])
+	case '$':
+		length, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return "", fmt.Errorf("invalid redis bulk string length %s", line[1:])
+		}
+		if length < 0 {
+			return "", nil
+		}
+		data := make([]byte, length+2)
+		if _, err := io.ReadFull(r, data); err != nil {
+			return "", err
+		}
+		return string(data[:length]), nil
+	}
+
+	return "", fmt.Errorf("unexpected redis reply %q", line)
+}
@@ -0,0 +1,147 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeRedisServer records the commands it receives, and replies to XADD with an entry id and to everything else with OK
+type fakeRedisServer struct {
+	listener net.Listener
+	mu       sync.Mutex
+	commands [][]string
+}
+
+func newFakeRedisServer(t *testing.T) *fakeRedisServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	server := &fakeRedisServer{listener: listener}
+	go server.serve()
+	t.Cleanup(func() { listener.Close() })
+	return server
+}
+
+func (f *fakeRedisServer) serve() {
+	conn, err := f.listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+	for id := 1; ; id++ {
+		command, err := readTestRedisCommand(reader)
+		if err != nil {
+			return
+		}
+		f.mu.Lock()
+		f.commands = append(f.commands, command)
+		f.mu.Unlock()
+
+		switch command[0] {
+		case "XADD":
+			entryID := fmt.Sprintf("1700000000000-%d", id)
+			fmt.Fprintf(conn, "$%d\r\n%s\r\n", len(entryID), entryID)
+		case "AUTH":
+			if command[1] != "password" {
+				fmt.Fprint(conn, "-WRONGPASS invalid password\r\n")
+				continue
+			}
+			fmt.Fprint(conn, "+OK\r\n")
+		default:
+			fmt.Fprint(conn, "+OK\r\n")
+		}
+	}
+}
+
+func (f *fakeRedisServer) receivedCommands() [][]string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.commands
+}
+
+func readTestRedisCommand(reader *bufio.Reader) ([]string, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	numArgs, err := strconv.Atoi(strings.TrimSpace(line[1:]))
+	if err != nil {
+		return nil, err
+	}
+
+	args := make([]string, numArgs)
+	for i := range args {
+		if _, err := reader.ReadString('\n'); err != nil {
+			return nil, err
+		}
+		arg, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		args[i] = strings.TrimSuffix(arg, "\r\n")
+	}
+	return args, nil
+}
+
+func TestRedisStreamSink(t *testing.T) {
+	server := newFakeRedisServer(t)
+	redisURL := fmt.Sprintf("redis://:password@%s/2", server.listener.Addr())
+
+	sink, err := newRedisStreamSink(redisURL, "stellar-etl:trades", 1000)
+	assert.NoError(t, err)
+
+	assert.NoError(t, sink.add([]byte(`{"ledger_sequence":10}`)))
+	assert.NoError(t, sink.add([]byte(`{"ledger_sequence":11}`)))
+	assert.NoError(t, sink.flush())
+	assert.NoError(t, sink.flush())
+	assert.NoError(t, sink.close())
+
+	assert.Equal(t, 2, sink.numEntries)
+	assert.Equal(t, [][]string{
+		{"AUTH", "password"},
+		{"SELECT", "2"},
+		{"XADD", "stellar-etl:trades", "MAXLEN", "~", "1000", "*", "record", `{"ledger_sequence":10}`},
+		{"XADD", "stellar-etl:trades", "MAXLEN", "~", "1000", "*", "record", `{"ledger_sequence":11}`},
+	}, server.receivedCommands())
+}
+
+func TestRedisStreamSinkWithoutTrimming(t *testing.T) {
+	server := newFakeRedisServer(t)
+
+	sink, err := newRedisStreamSink("redis://"+server.listener.Addr().String(), "stellar-etl:ledgers", 0)
+	assert.NoError(t, err)
+	assert.NoError(t, sink.add([]byte(`{}`)))
+	assert.NoError(t, sink.flush())
+	assert.NoError(t, sink.close())
+
+	assert.Equal(t, [][]string{{"XADD", "stellar-etl:ledgers", "*", "record", `{}`}}, server.receivedCommands())
+}
+
+func TestRedisStreamSinkAuthError(t *testing.T) {
+	server := newFakeRedisServer(t)
+
+	_, err := newRedisStreamSink(fmt.Sprintf("redis://:wrong@%s", server.listener.Addr()), "stellar-etl:ledgers", 0)
+	assert.ErrorContains(t, err, "WRONGPASS")
+}
+
+func TestReadRedisReply(t *testing.T) {
+	reader := bufio.NewReader(strings.NewReader("+OK\r\n:5\r\n$5\r\nhello\r\n$-1\r\n-ERR bad\r\n"))
+
+	for _, expected := range []string{"OK", "5", "hello", ""} {
+		reply, err := readRedisReply(reader)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, reply)
+	}
+
+	_, err := readRedisReply(reader)
+	assert.ErrorContains(t, err, "ERR bad")
+}
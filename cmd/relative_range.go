@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/stellar/stellar-etl/v2/internal/input"
+	"github.com/stellar/stellar-etl/v2/internal/utils"
+)
+
+// resolveRelativeRange sets start-ledger and end-ledger of an export from last-n-ledgers or last-24h. It runs before the
+// required flags are checked, so that end-ledger doesn't have to be passed with them.
+func resolveRelativeRange(cmd *cobra.Command, args []string) error {
+	flags := cmd.Flags()
+	if flags.Lookup("last-n-ledgers") == nil || flags.Lookup("last-24h") == nil {
+		return nil
+	}
+
+	lastN, err := flags.GetUint32("last-n-ledgers")
+	if err != nil {
+		return fmt.Errorf("could not get last-n-ledgers: %v", err)
+	}
+
+	last24h, err := flags.GetBool("last-24h")
+	if err != nil {
+		return fmt.Errorf("could not get last-24h: %v", err)
+	}
+
+	if lastN == 0 && !last24h {
+		return nil
+	}
+	if lastN > 0 && last24h {
+		return fmt.Errorf("last-n-ledgers and last-24h can't be combined")
+	}
+	if flags.Changed("start-ledger") || flags.Changed("end-ledger") {
+		return fmt.Errorf("last-n-ledgers and last-24h can't be combined with start-ledger or end-ledger")
+	}
+
+	isTest, err := flags.GetBool("testnet")
+	if err != nil {
+		return fmt.Errorf("could not get testnet boolean: %v", err)
+	}
+
+	isFuture, err := flags.GetBool("futurenet")
+	if err != nil {
+		return fmt.Errorf("could not get futurenet boolean: %v", err)
+	}
+
+	var startNum, endNum uint32
+	if last24h {
+		now := time.Now()
+		start, end, err := input.GetLedgerRange(now.Add(-24*time.Hour), now, isTest, isFuture)
+		if err != nil {
+			return fmt.Errorf("could not get the ledgers of the last 24 hours: %v", err)
+		}
+		startNum, endNum = uint32(start), uint32(end)
+	} else {
+		env := utils.GetEnvironmentDetails(utils.CommonFlagValues{IsTest: isTest, IsFuture: isFuture})
+		latest, err := utils.GetLatestLedgerSequence(env.ArchiveURLs)
+		if err != nil {
+			return fmt.Errorf("could not get the latest ledger: %v", err)
+		}
+		startNum, endNum = lastLedgersRange(latest, lastN)
+	}
+
+	cmdLogger.Infof("Exporting ledgers %d to %d", startNum, endNum)
+	if err := flags.Set("start-ledger", fmt.Sprint(startNum)); err != nil {
+		return err
+	}
+	return flags.Set("end-ledger", fmt.Sprint(endNum))
+}
+
+// lastLedgersRange is the range of the last n ledgers up to the latest ledger. It starts no earlier than ledger 2, the
+// first ledger with a real close time.
+func lastLedgersRange(latest, n uint32) (uint32, uint32) {
+	if n >= latest-1 {
+		return 2, latest
+	}
+	return latest - n + 1, latest
+}
@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stellar/stellar-etl/v2/internal/utils"
+)
+
+func TestLastLedgersRange(t *testing.T) {
+	start, end := lastLedgersRange(60000000, 17280)
+	assert.Equal(t, uint32(59982721), start)
+	assert.Equal(t, uint32(60000000), end)
+
+	// The range doesn't start before ledger 2
+	start, end = lastLedgersRange(100, 1000)
+	assert.Equal(t, uint32(2), start)
+	assert.Equal(t, uint32(100), end)
+}
+
+func TestResolveRelativeRangeConflicts(t *testing.T) {
+	newCmd := func(args ...string) *cobra.Command {
+		cmd := &cobra.Command{}
+		utils.AddCommonFlags(cmd.Flags())
+		utils.AddArchiveFlags("ledgers", cmd.Flags())
+		assert.NoError(t, cmd.Flags().Parse(args))
+		return cmd
+	}
+
+	// Without a relative range, the flags are left as they are
+	cmd := newCmd("--end-ledger", "100")
+	assert.NoError(t, resolveRelativeRange(cmd, nil))
+	endNum, _ := cmd.Flags().GetUint32("end-ledger")
+	assert.Equal(t, uint32(100), endNum)
+
+	assert.EqualError(t, resolveRelativeRange(newCmd("--last-n-ledgers", "10", "--last-24h"), nil), "last-n-ledgers and last-24h can't be combined")
+	assert.EqualError(t, resolveRelativeRange(newCmd("--last-n-ledgers", "10", "--start-ledger", "5"), nil), "last-n-ledgers and last-24h can't be combined with start-ledger or end-ledger")
+	assert.EqualError(t, resolveRelativeRange(newCmd("--last-24h", "--end-ledger", "5"), nil), "last-n-ledgers and last-24h can't be combined with start-ledger or end-ledger")
+}
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/stellar/stellar-etl/v2/internal/utils"
+)
+
+var replayCmd = &cobra.Command{
+	Use:   "replay [-- export flags]",
+	Short: "Runs an export over locally stored LedgerCloseMeta files",
+	Long: `Runs an export command over the LedgerCloseMeta XDR files in a folder instead of the datastore, so that problem
+ledgers reported by downstream consumers can be debugged without network access. The folder can hold zstd compressed
+batches like the files of the datastore, such as a copied datastore partition or a gen-fixtures fixture, and uncompressed
+batches or single ledgers ending in .xdr.
+
+The export covers every ledger in the folder. Flags after -- are passed to the export command, for example
+-- -s 100 -e 105 -o operations.txt to narrow the range and set the output file.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		lcmDir, err := cmd.Flags().GetString("lcm-dir")
+		if err != nil {
+			cmdLogger.Fatal("could not get lcm-dir: ", err)
+		}
+
+		command, err := cmd.Flags().GetString("command")
+		if err != nil {
+			cmdLogger.Fatal("could not get command: ", err)
+		}
+
+		exportCmd, exportArgs, err := replayExport(command, lcmDir, args)
+		if err != nil {
+			cmdLogger.Fatal(err)
+		}
+
+		if err := exportCmd.ParseFlags(exportArgs); err != nil {
+			cmdLogger.Fatalf("could not parse the flags of %s: %v", exportCmd.Name(), err)
+		}
+		exportCmd.Run(exportCmd, exportCmd.Flags().Args())
+	},
+}
+
+// replayExport finds the export command of a dataset, such as operations or export_operations, and builds its
+// arguments to export every ledger in lcmDir. The extra arguments come last so they can override the range.
+func replayExport(command, lcmDir string, extraArgs []string) (*cobra.Command, []string, error) {
+	name := "export_" + strings.TrimPrefix(command, "export_")
+	exportCmd, _, err := rootCmd.Find([]string{name})
+	if err != nil || exportCmd.Name() != name {
+		return nil, nil, fmt.Errorf("unsupported command %s; there is no %s command", command, name)
+	}
+
+	lcms, err := utils.ReadLedgerCloseMetas(lcmDir)
+	if err != nil {
+		return nil, nil, err
+	}
+	start := lcms[0].LedgerSequence()
+	end := lcms[len(lcms)-1].LedgerSequence()
+	cmdLogger.Infof("Replaying %s over %d local ledgers in the range %d-%d", name, len(lcms), start, end)
+
+	args := []string{
+		"--ledger-fixture", lcmDir,
+		"--start-ledger", strconv.FormatUint(uint64(start), 10),
+		"--end-ledger", strconv.FormatUint(uint64(end), 10),
+	}
+	return exportCmd, append(args, extraArgs...), nil
+}
+
+func init() {
+	rootCmd.AddCommand(replayCmd)
+	replayCmd.Flags().String("lcm-dir", "", "Folder of the LedgerCloseMeta XDR files to replay. Subfolders are read too.")
+	replayCmd.Flags().String("command", "", "Export to run, named after its export command such as operations or contract_events.")
+	replayCmd.MarkFlagRequired("lcm-dir")
+	replayCmd.MarkFlagRequired("command")
+}
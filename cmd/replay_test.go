@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stellar/stellar-etl/v2/internal/utils"
+)
+
+func replayLedger(sequence uint32) xdr.LedgerCloseMeta {
+	return xdr.LedgerCloseMeta{
+		V: 0,
+		V0: &xdr.LedgerCloseMetaV0{
+			LedgerHeader: xdr.LedgerHeaderHistoryEntry{Header: xdr.LedgerHeader{LedgerSeq: xdr.Uint32(sequence)}},
+		},
+	}
+}
+
+func TestReplay(t *testing.T) {
+	lcmDir := t.TempDir()
+	single, err := replayLedger(10).MarshalBinary()
+	assert.NoError(t, err)
+	assert.NoError(t, os.WriteFile(filepath.Join(lcmDir, "ledger_10.xdr"), single, 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(lcmDir, "notes.txt"), []byte("reported by a consumer"), 0644))
+	assert.NoError(t, os.Mkdir(filepath.Join(lcmDir, "FFFFFFFF--0-63999"), 0755))
+	assert.NoError(t, utils.WriteLedgerFixture(filepath.Join(lcmDir, "FFFFFFFF--0-63999", "FFFFFFF4--11-12.xdr.zst"),
+		[]xdr.LedgerCloseMeta{replayLedger(11), replayLedger(12)}))
+
+	output := filepath.Join(t.TempDir(), "ledgers.txt")
+	exportCmd, exportArgs, err := replayExport("ledgers", lcmDir, []string{"-e", "11", "-o", output})
+	assert.NoError(t, err)
+	assert.Equal(t, "export_ledgers", exportCmd.Name())
+	assert.Equal(t, []string{"--ledger-fixture", lcmDir, "--start-ledger", "10", "--end-ledger", "12", "-e", "11", "-o", output}, exportArgs)
+
+	assert.NoError(t, exportCmd.ParseFlags(exportArgs))
+	exportCmd.Run(exportCmd, exportCmd.Flags().Args())
+
+	contents, err := os.ReadFile(output)
+	assert.NoError(t, err)
+	sequences := []float64{}
+	for _, line := range strings.Split(strings.TrimSpace(string(contents)), "\n") {
+		record := map[string]interface{}{}
+		assert.NoError(t, json.Unmarshal([]byte(line), &record))
+		sequences = append(sequences, record["sequence"].(float64))
+	}
+	assert.Equal(t, []float64{10, 11}, sequences)
+
+	_, _, err = replayExport("accounts", lcmDir, nil)
+	assert.Error(t, err)
+
+	_, _, err = replayExport("export_operations", t.TempDir(), nil)
+	assert.Error(t, err)
+}
@@ -20,6 +20,8 @@ var rootCmd = &cobra.Command{
 	Use:   "stellar-etl",
 	Short: "Stellar Development Foundation ETL.",
 	Long:  `A tool to extract data from the historical record of the Stellar network.`,
+	// Exports of a relative range have their start and end ledgers set before they run
+	PersistentPreRunE: resolveRelativeRange,
 	// Uncomment the following line if your bare application
 	// has an action associated with it:
 	//	Run: func(cmd *cobra.Command, args []string) { },
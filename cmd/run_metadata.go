@@ -0,0 +1,337 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"time"
+
+	_ "github.com/lib/pq"
+	"golang.org/x/oauth2/google"
+)
+
+// bigQueryQueryTimeoutMs is how long a BigQuery query request waits for the query to finish
+const bigQueryQueryTimeoutMs = 60000
+
+// defaultRunMetadataTable is the postgres table that run metadata is written to if the url doesn't set one
+const defaultRunMetadataTable = "stellar_etl_runs"
+
+// exportRunMetadata describes a finished export, so completeness can be monitored from the destination
+type exportRunMetadata struct {
+	Dataset         string
+	Network         string
+	StartLedger     uint32
+	EndLedger       uint32
+	Attempted       int
+	Failed          int
+	Exported        int
+	BytesWritten    int
+	Interrupted     bool
+	StartedAt       time.Time
+	FinishedAt      time.Time
+	DurationSeconds float64
+	Version         string
+	CoreVersion     string
+
+	BigQueryLoadJobID  string
+	BigQueryLoadedRows int64
+	BigQueryInputFiles int64
+	BigQueryInputBytes int64
+}
+
+// runMetadataColumn is a column of the run metadata table, with its postgres and BigQuery types
+type runMetadataColumn struct {
+	name         string
+	postgresType string
+	bigQueryType string
+}
+
+var runMetadataColumns = []runMetadataColumn{
+	{"dataset", "TEXT", "STRING"},
+	{"network", "TEXT", "STRING"},
+	{"start_ledger", "BIGINT", "INT64"},
+	{"end_ledger", "BIGINT", "INT64"},
+	{"attempted_transforms", "BIGINT", "INT64"},
+	{"failed_transforms", "BIGINT", "INT64"},
+	{"exported_records", "BIGINT", "INT64"},
+	{"bytes_written", "BIGINT", "INT64"},
+	{"interrupted", "BOOLEAN", "BOOL"},
+	{"started_at", "TIMESTAMPTZ", "TIMESTAMP"},
+	{"finished_at", "TIMESTAMPTZ", "TIMESTAMP"},
+	{"duration_seconds", "DOUBLE PRECISION", "FLOAT64"},
+	{"version", "TEXT", "STRING"},
+	{"core_version", "TEXT", "STRING"},
+	{"bigquery_load_job_id", "TEXT", "STRING"},
+	{"bigquery_loaded_rows", "BIGINT", "INT64"},
+	{"bigquery_input_files", "BIGINT", "INT64"},
+	{"bigquery_input_bytes", "BIGINT", "INT64"},
+}
+
+// runMetadataAddedColumns are the columns added to runMetadataColumns after the table was first released, which are
+// added to tables created by earlier versions before a run is recorded
+var runMetadataAddedColumns = map[string]bool{
+	"core_version":         true,
+	"bigquery_load_job_id": true,
+	"bigquery_loaded_rows": true,
+	"bigquery_input_files": true,
+	"bigquery_input_bytes": true,
+}
+
+// values returns the values of the run metadata, in the order of runMetadataColumns
+func (m exportRunMetadata) values() []interface{} {
+	return []interface{}{
+		m.Dataset,
+		m.Network,
+		int64(m.StartLedger),
+		int64(m.EndLedger),
+		int64(m.Attempted),
+		int64(m.Failed),
+		int64(m.Exported),
+		int64(m.BytesWritten),
+		m.Interrupted,
+		m.StartedAt,
+		m.FinishedAt,
+		m.DurationSeconds,
+		m.Version,
+		m.CoreVersion,
+		m.BigQueryLoadJobID,
+		m.BigQueryLoadedRows,
+		m.BigQueryInputFiles,
+		m.BigQueryInputBytes,
+	}
+}
+
+// etlVersion returns the module version of the running binary
+func etlVersion() string {
+	buildInfo, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "(unknown)"
+	}
+	return buildInfo.Main.Version
+}
+
+// runMetadataStore records the metadata of finished exports in a warehouse table
+type runMetadataStore interface {
+	record(metadata exportRunMetadata) error
+}
+
+// newRunMetadataStore returns the store for a run metadata table url:
+//
+//	bigquery://<project>.<dataset>.<table>
+//	postgres://<user>:<password>@<host>:<port>/<database>?table=<table>, with any other lib/pq connection parameters
+func newRunMetadataStore(tableURL string) (runMetadataStore, error) {
+	switch {
+	case strings.HasPrefix(tableURL, "bigquery://"):
+		parts := strings.Split(strings.TrimPrefix(tableURL, "bigquery://"), ".")
+		if len(parts) != 3 {
+			return nil, fmt.Errorf("bigquery table %s must have the form bigquery://<project>.<dataset>.<table>", tableURL)
+		}
+		return bigQueryRunMetadataStore{project: parts[0], dataset: parts[1], table: parts[2]}, nil
+	case strings.HasPrefix(tableURL, "postgres://"), strings.HasPrefix(tableURL, "postgresql://"):
+		parsed, err := url.Parse(tableURL)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse postgres url: %v", err)
+		}
+
+		query := parsed.Query()
+		table := query.Get("table")
+		if table == "" {
+			table = defaultRunMetadataTable
+		}
+		query.Del("table")
+		parsed.RawQuery = query.Encode()
+
+		return postgresRunMetadataStore{connection: parsed.String(), table: table}, nil
+	}
+
+	return nil, fmt.Errorf("unsupported run metadata table %s; expected a bigquery:// or postgres:// url", tableURL)
+}
+
+// postgresRunMetadataStore inserts run metadata into a postgres table, which is created if it doesn't exist
+type postgresRunMetadataStore struct {
+	connection string
+	table      string
+}
+
+// quotePostgresTable quotes every part of a table name that may be qualified with a schema
+func quotePostgresTable(table string) string {
+	parts := strings.Split(table, ".")
+	for i, part := range parts {
+		parts[i] = quoteSQLIdentifier(part)
+	}
+	return strings.Join(parts, ".")
+}
+
+// postgresRunMetadataStatements returns the statements that create the table, add the columns that tables created by
+// earlier versions don't have, and insert the run metadata
+func postgresRunMetadataStatements(table string) (string, []string, string) {
+	quotedTable := quotePostgresTable(table)
+	definitions := make([]string, len(runMetadataColumns))
+	names := make([]string, len(runMetadataColumns))
+	placeholders := make([]string, len(runMetadataColumns))
+	alters := []string{}
+	for i, column := range runMetadataColumns {
+		definitions[i] = column.name + " " + column.postgresType
+		names[i] = column.name
+		placeholders[i] = "$" + strconv.Itoa(i+1)
+		if runMetadataAddedColumns[column.name] {
+			alters = append(alters, fmt.Sprintf("ALTER TABLE %s ADD COLUMN IF NOT EXISTS %s", quotedTable, definitions[i]))
+		}
+	}
+
+	create := fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (%s)", quotedTable, strings.Join(definitions, ", "))
+	insert := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", quotedTable, strings.Join(names, ", "), strings.Join(placeholders, ", "))
+	return create, alters, insert
+}
+
+func (p postgresRunMetadataStore) record(metadata exportRunMetadata) error {
+	db, err := sql.Open("postgres", p.connection)
+	if err != nil {
+		return fmt.Errorf("could not open postgres connection: %v", err)
+	}
+	defer db.Close()
+
+	create, alters, insert := postgresRunMetadataStatements(p.table)
+	if _, err := db.Exec(create); err != nil {
+		return fmt.Errorf("could not create table %s: %v", p.table, err)
+	}
+	for _, alter := range alters {
+		if _, err := db.Exec(alter); err != nil {
+			return fmt.Errorf("could not add column to %s: %v", p.table, err)
+		}
+	}
+	if _, err := db.Exec(insert, metadata.values()...); err != nil {
+		return fmt.Errorf("could not insert into %s: %v", p.table, err)
+	}
+	return nil
+}
+
+// bigQueryRunMetadataStore inserts run metadata into a BigQuery table with DML queries, so rows can be queried right
+// away instead of waiting for a streaming buffer. The table is created if it doesn't exist.
+type bigQueryRunMetadataStore struct {
+	project string
+	dataset string
+	table   string
+}
+
+// bigQueryQuery is the request body of the BigQuery jobs.query API
+type bigQueryQuery struct {
+	Query           string                   `json:"query"`
+	UseLegacySQL    bool                     `json:"useLegacySql"`
+	TimeoutMs       int                      `json:"timeoutMs"`
+	ParameterMode   string                   `json:"parameterMode,omitempty"`
+	QueryParameters []bigQueryQueryParameter `json:"queryParameters,omitempty"`
+}
+
+type bigQueryQueryParameter struct {
+	Name          string `json:"name"`
+	ParameterType struct {
+		Type string `json:"type"`
+	} `json:"parameterType"`
+	ParameterValue struct {
+		Value string `json:"value"`
+	} `json:"parameterValue"`
+}
+
+// queries returns the queries that create the table, add the columns that tables created by earlier versions don't
+// have, and insert the run metadata
+func (b bigQueryRunMetadataStore) queries(metadata exportRunMetadata) (bigQueryQuery, []bigQueryQuery, bigQueryQuery) {
+	tableName := fmt.Sprintf("`%s.%s.%s`", b.project, b.dataset, b.table)
+
+	definitions := make([]string, len(runMetadataColumns))
+	names := make([]string, len(runMetadataColumns))
+	placeholders := make([]string, len(runMetadataColumns))
+	parameters := make([]bigQueryQueryParameter, len(runMetadataColumns))
+	alters := []bigQueryQuery{}
+	values := metadata.values()
+	for i, column := range runMetadataColumns {
+		definitions[i] = column.name + " " + column.bigQueryType
+		names[i] = column.name
+		placeholders[i] = "@" + column.name
+		if runMetadataAddedColumns[column.name] {
+			alters = append(alters, bigQueryQuery{
+				Query:     fmt.Sprintf("ALTER TABLE %s ADD COLUMN IF NOT EXISTS %s", tableName, definitions[i]),
+				TimeoutMs: bigQueryQueryTimeoutMs,
+			})
+		}
+
+		parameters[i].Name = column.name
+		parameters[i].ParameterType.Type = column.bigQueryType
+		switch value := values[i].(type) {
+		case time.Time:
+			parameters[i].ParameterValue.Value = value.UTC().Format(time.RFC3339Nano)
+		default:
+			parameters[i].ParameterValue.Value = fmt.Sprint(value)
+		}
+	}
+
+	create := bigQueryQuery{
+		Query:     fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (%s)", tableName, strings.Join(definitions, ", ")),
+		TimeoutMs: bigQueryQueryTimeoutMs,
+	}
+	insert := bigQueryQuery{
+		Query:           fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", tableName, strings.Join(names, ", "), strings.Join(placeholders, ", ")),
+		TimeoutMs:       bigQueryQueryTimeoutMs,
+		ParameterMode:   "NAMED",
+		QueryParameters: parameters,
+	}
+	return create, alters, insert
+}
+
+func (b bigQueryRunMetadataStore) record(metadata exportRunMetadata) error {
+	ctx := context.Background()
+	client, err := google.DefaultClient(ctx, "https://www.googleapis.com/auth/bigquery")
+	if err != nil {
+		return fmt.Errorf("could not create bigquery client: %v", err)
+	}
+
+	create, alters, insert := b.queries(metadata)
+	queries := append(append([]bigQueryQuery{create}, alters...), insert)
+	for _, query := range queries {
+		if err := b.run(client, query); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// run runs a query with the jobs.query API and waits for it to finish
+func (b bigQueryRunMetadataStore) run(client *http.Client, query bigQueryQuery) error {
+	body, err := json.Marshal(query)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.Post(fmt.Sprintf("https://bigquery.googleapis.com/bigquery/v2/projects/%s/queries", b.project), "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("could not call bigquery: %v", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("bigquery returned %s: %s", resp.Status, respBody)
+	}
+
+	var result struct {
+		JobComplete bool `json:"jobComplete"`
+	}
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return fmt.Errorf("could not decode bigquery response: %v", err)
+	}
+	if !result.JobComplete {
+		return fmt.Errorf("bigquery query did not finish in time: %s", query.Query)
+	}
+	return nil
+}
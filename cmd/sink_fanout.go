@@ -0,0 +1,165 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/spf13/viper"
+	"github.com/stellar/stellar-etl/v2/internal/utils"
+)
+
+// sinkConfig is a sink that the records of an export are fanned out to in addition to its output, defined in the sinks
+// list of the config file. Sink settings that are not set take the value of their flag.
+type sinkConfig struct {
+	Name        string              `mapstructure:"name"`
+	Type        string              `mapstructure:"type"`
+	Datasets    []string            `mapstructure:"datasets"`
+	ExtraFields map[string]string   `mapstructure:"extra-fields"`
+	Filter      map[string][]string `mapstructure:"filter"`
+
+	WebhookURL             string `mapstructure:"webhook-url"`
+	WebhookSecret          string `mapstructure:"webhook-secret"`
+	WebhookBatchSize       int    `mapstructure:"webhook-batch-size"`
+	RedisURL               string `mapstructure:"redis-url"`
+	RedisStream            string `mapstructure:"redis-stream"`
+	RedisStreamMaxLen      int64  `mapstructure:"redis-stream-maxlen"`
+	ElasticsearchURL       string `mapstructure:"elasticsearch-url"`
+	ElasticsearchAPIKey    string `mapstructure:"elasticsearch-api-key"`
+	ElasticsearchIndex     string `mapstructure:"elasticsearch-index"`
+	ElasticsearchBatchSize int    `mapstructure:"elasticsearch-batch-size"`
+	PubSubTopic            string `mapstructure:"pubsub-topic"`
+	PubSubEndpoint         string `mapstructure:"pubsub-endpoint"`
+	KafkaRESTURL           string `mapstructure:"kafka-rest-url"`
+	KafkaTopic             string `mapstructure:"kafka-topic"`
+	MessageKeyField        string `mapstructure:"message-key-field"`
+	PublishBatchSize       int    `mapstructure:"publish-batch-size"`
+	PublishMaxInFlight     int    `mapstructure:"publish-max-in-flight"`
+}
+
+// fanoutSink sends the records that match its filter to a sink, with its own extra fields
+type fanoutSink struct {
+	name        string
+	sink        recordSink
+	extraFields map[string]string
+	filter      map[string][]string
+	numSkipped  int
+}
+
+// loadSinkConfigs reads the sinks list of the config file
+func loadSinkConfigs() ([]sinkConfig, error) {
+	var configs []sinkConfig
+	if err := viper.UnmarshalKey("sinks", &configs); err != nil {
+		return nil, fmt.Errorf("could not parse the sinks of the config file: %v", err)
+	}
+	return configs, nil
+}
+
+// newFanoutSinks sets up the configured sinks that the dataset of the export is sent to
+func newFanoutSinks(configs []sinkConfig, cmdArgs utils.FlagValues) ([]*fanoutSink, error) {
+	var fanouts []*fanoutSink
+	for i, config := range configs {
+		if config.Name == "" {
+			config.Name = fmt.Sprintf("%s-%d", config.Type, i)
+		}
+		if len(config.Datasets) > 0 && !slices.Contains(config.Datasets, cmdArgs.Dataset) {
+			continue
+		}
+
+		sink, err := newConfiguredSink(config, cmdArgs)
+		if err != nil {
+			return nil, fmt.Errorf("could not set up sink %s: %v", config.Name, err)
+		}
+
+		fanouts = append(fanouts, &fanoutSink{name: config.Name, sink: sink, extraFields: config.ExtraFields, filter: config.Filter})
+	}
+	return fanouts, nil
+}
+
+// newConfiguredSink creates the sink of a sink config, falling back to the flags for the settings it doesn't set
+func newConfiguredSink(config sinkConfig, cmdArgs utils.FlagValues) (recordSink, error) {
+	orString := func(value, fallback string) string {
+		if value == "" {
+			return fallback
+		}
+		return value
+	}
+	orInt := func(value, fallback int) int {
+		if value == 0 {
+			return fallback
+		}
+		return value
+	}
+
+	switch config.Type {
+	case "webhook":
+		return newWebhookSink(orString(config.WebhookURL, cmdArgs.WebhookURL), orString(config.WebhookSecret, cmdArgs.WebhookSecret),
+			orInt(config.WebhookBatchSize, cmdArgs.WebhookBatchSize))
+	case "redis-stream":
+		maxLen := config.RedisStreamMaxLen
+		if maxLen == 0 {
+			maxLen = cmdArgs.RedisStreamMaxLen
+		}
+		return newRedisStreamSink(orString(config.RedisURL, cmdArgs.RedisURL), orString(config.RedisStream, cmdArgs.RedisStream), maxLen)
+	case "elasticsearch":
+		return newElasticsearchSink(orString(config.ElasticsearchURL, cmdArgs.ElasticsearchURL), orString(config.ElasticsearchAPIKey, cmdArgs.ElasticsearchAPIKey),
+			orString(config.ElasticsearchIndex, cmdArgs.ElasticsearchIndex), orInt(config.ElasticsearchBatchSize, cmdArgs.ElasticsearchBatchSize))
+	case "pubsub":
+		return newPubSubSink(orString(config.PubSubTopic, cmdArgs.PubSubTopic), orString(config.PubSubEndpoint, cmdArgs.PubSubEndpoint),
+			orString(config.MessageKeyField, cmdArgs.MessageKeyField), orInt(config.PublishBatchSize, cmdArgs.PublishBatchSize), orInt(config.PublishMaxInFlight, cmdArgs.PublishMaxInFlight))
+	case "kafka":
+		return newKafkaSink(orString(config.KafkaRESTURL, cmdArgs.KafkaRESTURL), orString(config.KafkaTopic, cmdArgs.KafkaTopic),
+			orString(config.MessageKeyField, cmdArgs.MessageKeyField), orInt(config.PublishBatchSize, cmdArgs.PublishBatchSize), orInt(config.PublishMaxInFlight, cmdArgs.PublishMaxInFlight))
+	default:
+		return nil, fmt.Errorf("unsupported sink type %q; expected webhook, redis-stream, elasticsearch, pubsub or kafka", config.Type)
+	}
+}
+
+// add sends a record to the sink if it matches the filter, with the extra fields of the sink added to it
+func (f *fanoutSink) add(record []byte) error {
+	if len(f.filter) == 0 && len(f.extraFields) == 0 {
+		return f.sink.add(record)
+	}
+
+	fields := map[string]interface{}{}
+	decoder := json.NewDecoder(bytes.NewReader(record))
+	decoder.UseNumber()
+	if err := decoder.Decode(&fields); err != nil {
+		return fmt.Errorf("could not decode record: %v", err)
+	}
+
+	if !matchesFilter(fields, f.filter) {
+		f.numSkipped += 1
+		return nil
+	}
+	if len(f.extraFields) == 0 {
+		return f.sink.add(record)
+	}
+
+	for k, v := range f.extraFields {
+		fields[k] = v
+	}
+	marshalled, err := json.Marshal(fields)
+	if err != nil {
+		return fmt.Errorf("could not json encode record: %v", err)
+	}
+	return f.sink.add(marshalled)
+}
+
+// describe summarizes what was sent to the sink, and how many records its filter skipped
+func (f *fanoutSink) describe() string {
+	return fmt.Sprintf("Sink %s: %s, skipped %d records", f.name, f.sink.describe(), f.numSkipped)
+}
+
+// matchesFilter reports whether every field of the filter has one of its values in the record. Values are compared
+// as they are written in the JSON, so numbers and booleans are matched by their text.
+func matchesFilter(fields map[string]interface{}, filter map[string][]string) bool {
+	for field, values := range filter {
+		value, ok := fields[field]
+		if !ok || !slices.Contains(values, fmt.Sprint(value)) {
+			return false
+		}
+	}
+	return true
+}
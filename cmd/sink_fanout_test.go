@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stellar/stellar-etl/v2/internal/utils"
+)
+
+func TestLoadSinkConfigs(t *testing.T) {
+	defer viper.Reset()
+	viper.SetConfigType("yaml")
+	assert.NoError(t, viper.ReadConfig(strings.NewReader(`
+sinks:
+  - name: payments
+    type: webhook
+    webhook-url: https://example.org/hook
+    datasets: [operations]
+    extra-fields:
+      client: acme
+    filter:
+      type_string: payment
+`)))
+
+	configs, err := loadSinkConfigs()
+	assert.NoError(t, err)
+	assert.Equal(t, []sinkConfig{{
+		Name:        "payments",
+		Type:        "webhook",
+		Datasets:    []string{"operations"},
+		ExtraFields: map[string]string{"client": "acme"},
+		Filter:      map[string][]string{"type_string": {"payment"}},
+		WebhookURL:  "https://example.org/hook",
+	}}, configs)
+}
+
+func TestFanoutSinkFiltersAndAddsExtraFields(t *testing.T) {
+	var received []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+
+		var batch []map[string]interface{}
+		assert.NoError(t, json.Unmarshal(body, &batch))
+		received = append(received, batch...)
+	}))
+	defer server.Close()
+
+	configs := []sinkConfig{
+		{Name: "payments", Type: "webhook", WebhookURL: server.URL, ExtraFields: map[string]string{"client": "acme"},
+			Filter: map[string][]string{"type_string": {"payment", "path_payment_strict_send"}}},
+		{Name: "trades", Type: "webhook", Datasets: []string{"trades"}},
+	}
+	fanouts, err := newFanoutSinks(configs, utils.FlagValues{Dataset: "operations", WebhookBatchSize: 10})
+	assert.NoError(t, err)
+	assert.Len(t, fanouts, 1)
+
+	payments := fanouts[0]
+	assert.NoError(t, payments.add([]byte(`{"id":1,"type_string":"payment"}`)))
+	assert.NoError(t, payments.add([]byte(`{"id":2,"type_string":"create_account"}`)))
+	assert.NoError(t, payments.add([]byte(`{"id":3,"type_string":"path_payment_strict_send"}`)))
+	assert.NoError(t, payments.sink.flush())
+
+	assert.Equal(t, []map[string]interface{}{
+		{"id": float64(1), "type_string": "payment", "client": "acme"},
+		{"id": float64(3), "type_string": "path_payment_strict_send", "client": "acme"},
+	}, received)
+	assert.Equal(t, 1, payments.numSkipped)
+
+	_, err = newFanoutSinks([]sinkConfig{{Type: "nats"}}, utils.FlagValues{})
+	assert.EqualError(t, err, `could not set up sink nats-0: unsupported sink type "nats"; expected webhook, redis-stream, elasticsearch, pubsub or kafka`)
+}
+
+func TestExportEngineFansOutToConfiguredSinks(t *testing.T) {
+	var numReceived int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var batch []json.RawMessage
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&batch))
+		numReceived += len(batch)
+	}))
+	defer server.Close()
+
+	defer viper.Reset()
+	viper.Set("sinks", []map[string]interface{}{{"name": "hook", "type": "webhook", "webhook-url": server.URL}})
+
+	path := filepath.Join(t.TempDir(), "out.txt")
+	engine := newExportEngine(utils.FlagValues{Limit: -1, Path: path, WebhookBatchSize: 10}, utils.EnvironmentDetails{}, nil, nil)
+	engine.export(map[string]interface{}{"id": 1})
+	engine.export(map[string]interface{}{"id": 2})
+	engine.flushSink()
+	engine.outFile.Close()
+
+	assert.Len(t, engine.fanouts, 1)
+	assert.Equal(t, 2, numReceived)
+}
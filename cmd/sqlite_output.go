@@ -0,0 +1,295 @@
+package cmd
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/guregu/null"
+	"github.com/guregu/null/zero"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// sqliteCommitSize is the number of rows inserted in a single transaction
+const sqliteCommitSize = 10000
+
+// sqliteTable writes the exported records to a table in a local SQLite database. The table is created from the fields of
+// the first record, and columns that are missing from an existing table are added.
+type sqliteTable struct {
+	db    *sql.DB
+	table string
+
+	columns     []string
+	tx          *sql.Tx
+	insert      *sql.Stmt
+	pendingRows int
+	numRows     int
+}
+
+func newSQLiteTable(path, table string) (*sqliteTable, error) {
+	if table == "" {
+		return nil, fmt.Errorf("sqlite-table is required to write to sqlite")
+	}
+
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		return nil, fmt.Errorf("could not open sqlite database %s: %v", path, err)
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("could not open sqlite database %s: %v", path, err)
+	}
+
+	return &sqliteTable{db: db, table: table}, nil
+}
+
+// write inserts a record. The encoded record holds the values, so the rows match the output jsons including the extra fields.
+func (s *sqliteTable) write(record interface{}, encoded []byte) error {
+	values := map[string]interface{}{}
+	decoder := json.NewDecoder(bytes.NewReader(encoded))
+	decoder.UseNumber()
+	if err := decoder.Decode(&values); err != nil {
+		return fmt.Errorf("could not decode record: %v", err)
+	}
+
+	if s.columns == nil {
+		if err := s.createTable(reflect.TypeOf(record), values); err != nil {
+			return err
+		}
+	}
+
+	if s.tx == nil {
+		if err := s.begin(); err != nil {
+			return err
+		}
+	}
+
+	args := make([]interface{}, len(s.columns))
+	for i, column := range s.columns {
+		value, err := sqliteValue(values[column])
+		if err != nil {
+			return fmt.Errorf("could not convert %s: %v", column, err)
+		}
+		args[i] = value
+	}
+
+	if _, err := s.insert.Exec(args...); err != nil {
+		return fmt.Errorf("could not insert into %s: %v", s.table, err)
+	}
+
+	s.pendingRows += 1
+	if s.pendingRows >= sqliteCommitSize {
+		return s.commit()
+	}
+	return nil
+}
+
+// close commits the pending rows and closes the database
+func (s *sqliteTable) close() error {
+	if err := s.commit(); err != nil {
+		s.db.Close()
+		return err
+	}
+	return s.db.Close()
+}
+
+func (s *sqliteTable) begin() error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(s.columns)), ", ")
+	quoted := make([]string, len(s.columns))
+	for i, column := range s.columns {
+		quoted[i] = quoteSQLIdentifier(column)
+	}
+	insert, err := tx.Prepare(fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", quoteSQLIdentifier(s.table), strings.Join(quoted, ", "), placeholders))
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("could not prepare insert into %s: %v", s.table, err)
+	}
+
+	s.tx = tx
+	s.insert = insert
+	return nil
+}
+
+func (s *sqliteTable) commit() error {
+	if s.tx == nil {
+		return nil
+	}
+
+	s.insert.Close()
+	if err := s.tx.Commit(); err != nil {
+		return fmt.Errorf("could not commit rows to %s: %v", s.table, err)
+	}
+
+	s.numRows += s.pendingRows
+	s.pendingRows = 0
+	s.tx = nil
+	s.insert = nil
+	return nil
+}
+
+// createTable creates the table, or adds the columns that an existing table is missing. Column types come from the
+// fields of the record, and extra fields that the record doesn't have are added as text columns.
+func (s *sqliteTable) createTable(recordType reflect.Type, values map[string]interface{}) error {
+	columnTypes := map[string]string{}
+	var columns []string
+	addColumn := func(name, columnType string) {
+		if _, ok := columnTypes[name]; ok {
+			return
+		}
+		columnTypes[name] = columnType
+		columns = append(columns, name)
+	}
+
+	for recordType.Kind() == reflect.Ptr {
+		recordType = recordType.Elem()
+	}
+	if recordType.Kind() == reflect.Struct {
+		addSQLiteStructColumns(recordType, addColumn)
+	}
+
+	var extra []string
+	for name := range values {
+		if _, ok := columnTypes[name]; !ok {
+			extra = append(extra, name)
+		}
+	}
+	sort.Strings(extra)
+	for _, name := range extra {
+		addColumn(name, "TEXT")
+	}
+
+	definitions := make([]string, len(columns))
+	for i, column := range columns {
+		definitions[i] = quoteSQLIdentifier(column) + " " + columnTypes[column]
+	}
+	_, err := s.db.Exec(fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (%s)", quoteSQLIdentifier(s.table), strings.Join(definitions, ", ")))
+	if err != nil {
+		return fmt.Errorf("could not create table %s: %v", s.table, err)
+	}
+
+	existing, err := s.existingColumns()
+	if err != nil {
+		return err
+	}
+	for _, column := range columns {
+		if existing[column] {
+			continue
+		}
+		_, err := s.db.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", quoteSQLIdentifier(s.table), quoteSQLIdentifier(column), columnTypes[column]))
+		if err != nil {
+			return fmt.Errorf("could not add column %s to %s: %v", column, s.table, err)
+		}
+	}
+
+	s.columns = columns
+	return nil
+}
+
+func (s *sqliteTable) existingColumns() (map[string]bool, error) {
+	rows, err := s.db.Query(fmt.Sprintf("PRAGMA table_info(%s)", quoteSQLIdentifier(s.table)))
+	if err != nil {
+		return nil, fmt.Errorf("could not read the columns of %s: %v", s.table, err)
+	}
+	defer rows.Close()
+
+	columns := map[string]bool{}
+	for rows.Next() {
+		var (
+			cid        int
+			name       string
+			columnType string
+			notNull    int
+			defaultVal interface{}
+			primaryKey int
+		)
+		if err := rows.Scan(&cid, &name, &columnType, &notNull, &defaultVal, &primaryKey); err != nil {
+			return nil, err
+		}
+		columns[name] = true
+	}
+	return columns, rows.Err()
+}
+
+// addSQLiteStructColumns adds a column for every field of a struct that is written to the output jsons
+func addSQLiteStructColumns(structType reflect.Type, addColumn func(name, columnType string)) {
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			addSQLiteStructColumns(field.Type, addColumn)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		addColumn(name, sqliteColumnType(field.Type))
+	}
+}
+
+// sqliteColumnType returns the column type of a field. Nested values are stored as JSON text.
+func sqliteColumnType(fieldType reflect.Type) string {
+	for fieldType.Kind() == reflect.Ptr {
+		fieldType = fieldType.Elem()
+	}
+
+	switch fieldType {
+	case reflect.TypeOf(null.Int{}), reflect.TypeOf(zero.Int{}), reflect.TypeOf(null.Bool{}):
+		return "INTEGER"
+	case reflect.TypeOf(null.Float{}):
+		return "REAL"
+	case reflect.TypeOf(time.Time{}), reflect.TypeOf(null.Time{}):
+		return "TEXT"
+	}
+
+	switch fieldType.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Bool:
+		return "INTEGER"
+	case reflect.Float32, reflect.Float64:
+		return "REAL"
+	}
+	return "TEXT"
+}
+
+// sqliteValue converts a decoded json value to the value that is inserted
+func sqliteValue(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i, nil
+		}
+		if f, err := v.Float64(); err == nil {
+			return f, nil
+		}
+		// Integers that don't fit in 64 bits are stored as text
+		return v.String(), nil
+	case map[string]interface{}, []interface{}:
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		return string(encoded), nil
+	}
+	return value, nil
+}
+
+// quoteSQLIdentifier quotes a table or column name, which works for both SQLite and DuckDB
+func quoteSQLIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/guregu/null"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stellar/stellar-etl/v2/internal/transform"
+	"github.com/stellar/stellar-etl/v2/internal/utils"
+)
+
+func TestExportEngineWritesSQLite(t *testing.T) {
+	dir := t.TempDir()
+	dbPath := filepath.Join(dir, "out.db")
+	cmdArgs := utils.FlagValues{
+		Path:        filepath.Join(dir, "upgrades.txt"),
+		Limit:       -1,
+		Extra:       map[string]string{"batch_id": "b1"},
+		SQLitePath:  dbPath,
+		SQLiteTable: "ledger_upgrades",
+	}
+
+	for run := 0; run < 2; run++ {
+		engine := newExportEngine(cmdArgs, utils.EnvironmentDetails{}, new(transform.LedgerUpgradeOutput), nil)
+		engine.export(transform.LedgerUpgradeOutput{
+			LedgerSequence: 10,
+			ClosedAt:       time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+			UpgradeIndex:   int32(run),
+			TypeString:     "LedgerUpgradeTypeLedgerUpgradeBaseFee",
+			NewBaseFee:     null.IntFrom(200),
+		})
+		engine.outFile.Close()
+		assert.NoError(t, engine.sqlite.close())
+		assert.Equal(t, 1, engine.sqlite.numRows)
+	}
+
+	db, err := sql.Open("sqlite3", dbPath)
+	assert.NoError(t, err)
+	defer db.Close()
+
+	var count int
+	assert.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM ledger_upgrades`).Scan(&count))
+	assert.Equal(t, 2, count)
+
+	var (
+		closedAt   string
+		typeString string
+		newBaseFee int64
+		newFlags   sql.NullInt64
+		batchID    string
+	)
+	row := db.QueryRow(`SELECT closed_at, type_string, new_base_fee, new_flags, batch_id FROM ledger_upgrades WHERE upgrade_index = 1`)
+	assert.NoError(t, row.Scan(&closedAt, &typeString, &newBaseFee, &newFlags, &batchID))
+	assert.Equal(t, "2024-01-02T03:04:05Z", closedAt)
+	assert.Equal(t, "LedgerUpgradeTypeLedgerUpgradeBaseFee", typeString)
+	assert.Equal(t, int64(200), newBaseFee)
+	assert.False(t, newFlags.Valid)
+	assert.Equal(t, "b1", batchID)
+
+	var columnType string
+	assert.NoError(t, db.QueryRow(`SELECT type FROM pragma_table_info('ledger_upgrades') WHERE name = 'new_base_fee'`).Scan(&columnType))
+	assert.Equal(t, "INTEGER", columnType)
+}
+
+func TestSQLiteTableAddsMissingColumns(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "out.db")
+
+	table, err := newSQLiteTable(dbPath, "records")
+	assert.NoError(t, err)
+	assert.NoError(t, table.write(struct {
+		A int64 `json:"a"`
+	}{1}, []byte(`{"a":1}`)))
+	assert.NoError(t, table.close())
+
+	table, err = newSQLiteTable(dbPath, "records")
+	assert.NoError(t, err)
+	assert.NoError(t, table.write(struct {
+		A int64    `json:"a"`
+		B []string `json:"b"`
+	}{2, []string{"x"}}, []byte(`{"a":2,"b":["x"]}`)))
+	assert.NoError(t, table.close())
+
+	db, err := sql.Open("sqlite3", dbPath)
+	assert.NoError(t, err)
+	defer db.Close()
+
+	var b sql.NullString
+	assert.NoError(t, db.QueryRow(`SELECT b FROM records WHERE a = 2`).Scan(&b))
+	assert.Equal(t, `["x"]`, b.String)
+	assert.NoError(t, db.QueryRow(`SELECT b FROM records WHERE a = 1`).Scan(&b))
+	assert.False(t, b.Valid)
+}
@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	"github.com/guregu/null"
+	"github.com/guregu/null/zero"
+)
+
+var (
+	nullIntType = reflect.TypeOf(null.Int{})
+	zeroIntType = reflect.TypeOf(zero.Int{})
+)
+
+// isLargeIntType returns true for the types that can hold integers beyond 2^53, which is the largest integer that
+// JSON consumers that decode numbers as doubles can represent exactly
+func isLargeIntType(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint64:
+		return true
+	}
+
+	return t == nullIntType || t == zeroIntType
+}
+
+// stringifyLargeIntValues replaces the numbers in decoded that come from 64 bit integers in original with strings. The type of the
+// original value decides which numbers are replaced, so a field is always a string or always a number, regardless of its value.
+// decoded has to be the output of marshalling original and decoding it with UseNumber.
+func stringifyLargeIntValues(original reflect.Value, decoded interface{}) interface{} {
+	for original.Kind() == reflect.Pointer || original.Kind() == reflect.Interface {
+		if original.IsNil() {
+			return decoded
+		}
+		original = original.Elem()
+	}
+
+	if isLargeIntType(original.Type()) {
+		if number, ok := decoded.(json.Number); ok {
+			return number.String()
+		}
+		return decoded
+	}
+
+	switch original.Kind() {
+	case reflect.Struct:
+		if fields, ok := decoded.(map[string]interface{}); ok {
+			stringifyStructFields(original, fields)
+		}
+	case reflect.Map:
+		fields, ok := decoded.(map[string]interface{})
+		if !ok || original.Type().Key().Kind() != reflect.String {
+			return decoded
+		}
+		iter := original.MapRange()
+		for iter.Next() {
+			key := iter.Key().String()
+			if value, ok := fields[key]; ok {
+				fields[key] = stringifyLargeIntValues(iter.Value(), value)
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		items, ok := decoded.([]interface{})
+		if !ok || len(items) != original.Len() {
+			return decoded
+		}
+		for i := range items {
+			items[i] = stringifyLargeIntValues(original.Index(i), items[i])
+		}
+	}
+
+	return decoded
+}
+
+// stringifyStructFields applies stringifyLargeIntValues to the decoded value of every exported field of a struct.
+// Embedded structs are flattened by encoding/json, so their fields are looked up in the same map.
+func stringifyStructFields(original reflect.Value, fields map[string]interface{}) {
+	structType := original.Type()
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		name, _, _ := strings.Cut(tag, ",")
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			stringifyStructFields(original.Field(i), fields)
+			continue
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+
+		if value, ok := fields[name]; ok {
+			fields[name] = stringifyLargeIntValues(original.Field(i), value)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/guregu/null"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stellar/stellar-etl/v2/internal/transform"
+)
+
+func TestExportEntryStringifiesLargeInts(t *testing.T) {
+	type embedded struct {
+		Balance int64 `json:"balance"`
+	}
+	type entry struct {
+		embedded
+		LedgerSequence uint32                 `json:"ledger_sequence"`
+		Sequence       int64                  `json:"sequence"`
+		OfferID        null.Int               `json:"offer_id"`
+		MissingOfferID null.Int               `json:"missing_offer_id"`
+		Amount         float64                `json:"amount"`
+		IDs            []uint64               `json:"ids"`
+		Details        map[string]interface{} `json:"details"`
+	}
+
+	path := filepath.Join(t.TempDir(), "entries.txt")
+	outFile := MustOutFile(path)
+	_, err := ExportEntry(entry{
+		embedded:       embedded{Balance: 5},
+		LedgerSequence: 10,
+		Sequence:       9007199254740993,
+		OfferID:        null.IntFrom(7),
+		Amount:         1.5,
+		IDs:            []uint64{18446744073709551615},
+		Details:        map[string]interface{}{"new_seq": int64(9007199254740993), "bump_to": uint32(3)},
+	}, outFile, map[string]string{"batch_id": "1"}, true, false)
+	assert.NoError(t, err)
+	outFile.Close()
+
+	contents, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"balance": "5",
+		"ledger_sequence": 10,
+		"sequence": "9007199254740993",
+		"offer_id": "7",
+		"missing_offer_id": null,
+		"amount": 1.5,
+		"ids": ["18446744073709551615"],
+		"details": {"new_seq": "9007199254740993", "bump_to": 3},
+		"batch_id": "1"
+	}`, string(contents))
+}
+
+func TestExportEntryKeepsNumbersByDefault(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "upgrades.txt")
+	outFile := MustOutFile(path)
+	_, err := ExportEntry(transform.LedgerUpgradeOutput{LedgerSequence: 10, UpgradeIndex: 2}, outFile, nil, false, false)
+	assert.NoError(t, err)
+	outFile.Close()
+
+	contents, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Contains(t, string(contents), `"ledger_sequence":10`)
+}
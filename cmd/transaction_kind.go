@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"github.com/spf13/pflag"
+	"github.com/stellar/go-stellar-sdk/ingest"
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stellar/stellar-etl/v2/internal/utils"
+)
+
+// transactionKindFilter restricts an export to the soroban or the classic transactions, so the transactions of the
+// other kind are skipped before they are transformed
+type transactionKindFilter struct {
+	sorobanOnly bool
+	classicOnly bool
+}
+
+// addTransactionKindFlags adds the soroban-only and classic-only flags
+func addTransactionKindFlags(flags *pflag.FlagSet) {
+	flags.Bool("soroban-only", false, "If set, only the records of soroban transactions are exported, and ledgers without soroban transactions are skipped.")
+	flags.Bool("classic-only", false, "If set, only the records of classic transactions are exported.")
+}
+
+// mustTransactionKindFilter reads the soroban-only and classic-only flags
+func mustTransactionKindFilter(flags *pflag.FlagSet) transactionKindFilter {
+	sorobanOnly, err := flags.GetBool("soroban-only")
+	if err != nil {
+		cmdLogger.Fatal("could not get soroban-only flag: ", err)
+	}
+
+	classicOnly, err := flags.GetBool("classic-only")
+	if err != nil {
+		cmdLogger.Fatal("could not get classic-only flag: ", err)
+	}
+
+	if sorobanOnly && classicOnly {
+		cmdLogger.Fatal("soroban-only and classic-only can't be used together")
+	}
+
+	return transactionKindFilter{sorobanOnly: sorobanOnly, classicOnly: classicOnly}
+}
+
+// skipLedger reports whether none of the transactions of the ledger can pass the filter, which is only known without
+// reading the transactions for soroban-only exports of ledgers whose soroban phase is empty
+func (f transactionKindFilter) skipLedger(lcm xdr.LedgerCloseMeta) (bool, error) {
+	if !f.sorobanOnly {
+		return false, nil
+	}
+
+	hasSoroban, err := utils.LedgerHasSorobanTransactions(lcm)
+	if err != nil {
+		return false, err
+	}
+	return !hasSoroban, nil
+}
+
+// skipTransaction reports whether the transaction is of the kind that is filtered out
+func (f transactionKindFilter) skipTransaction(transaction ingest.LedgerTransaction) bool {
+	isSoroban := transaction.IsSorobanTx()
+	return (f.sorobanOnly && !isSoroban) || (f.classicOnly && isSoroban)
+}
@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stellar/go-stellar-sdk/ingest"
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransactionKindFilter(t *testing.T) {
+	classic := ingest.LedgerTransaction{Envelope: xdr.TransactionEnvelope{
+		Type: xdr.EnvelopeTypeEnvelopeTypeTx,
+		V1:   &xdr.TransactionV1Envelope{},
+	}}
+	soroban := ingest.LedgerTransaction{Envelope: xdr.TransactionEnvelope{
+		Type: xdr.EnvelopeTypeEnvelopeTypeTx,
+		V1: &xdr.TransactionV1Envelope{Tx: xdr.Transaction{
+			Ext: xdr.TransactionExt{V: 1, SorobanData: &xdr.SorobanTransactionData{}},
+		}},
+	}}
+
+	sorobanOnly := transactionKindFilter{sorobanOnly: true}
+	assert.True(t, sorobanOnly.skipTransaction(classic))
+	assert.False(t, sorobanOnly.skipTransaction(soroban))
+
+	classicOnly := transactionKindFilter{classicOnly: true}
+	assert.False(t, classicOnly.skipTransaction(classic))
+	assert.True(t, classicOnly.skipTransaction(soroban))
+
+	assert.False(t, transactionKindFilter{}.skipTransaction(soroban))
+
+	// Ledgers from before soroban have no soroban transactions to export
+	skip, err := sorobanOnly.skipLedger(xdr.LedgerCloseMeta{V: 0, V0: &xdr.LedgerCloseMetaV0{}})
+	assert.NoError(t, err)
+	assert.True(t, skip)
+
+	skip, err = classicOnly.skipLedger(xdr.LedgerCloseMeta{V: 0, V0: &xdr.LedgerCloseMetaV0{}})
+	assert.NoError(t, err)
+	assert.False(t, skip)
+}
@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/stellar/go-stellar-sdk/ingest"
+	"github.com/stellar/stellar-etl/v2/internal/transform"
+	"github.com/stellar/stellar-etl/v2/internal/utils"
+)
+
+// ledgerTransform is the operation type of the transforms that read a whole ledger
+const ledgerTransform = "ledger"
+
+// transformTiming is how long the transforms of an operation type took over an export
+type transformTiming struct {
+	count int
+	total time.Duration
+	max   time.Duration
+}
+
+// transformTimingSummary is the timing of an operation type in the summary that is logged once an export finishes
+type transformTimingSummary struct {
+	OperationType    string  `json:"operation_type"`
+	Count            int     `json:"count"`
+	TotalSeconds     float64 `json:"total_seconds"`
+	MeanMicroseconds float64 `json:"mean_microseconds"`
+	MaxMicroseconds  float64 `json:"max_microseconds"`
+}
+
+// transactionOperationType returns the operation type of the transforms of a transaction: the type of its operations,
+// or mixed if they have different types
+func transactionOperationType(transaction ingest.LedgerTransaction) string {
+	operationType := ""
+	for _, operation := range transaction.Envelope.Operations() {
+		name := transform.OperationTypeName(operation)
+		if operationType != "" && name != operationType {
+			return "mixed"
+		}
+		operationType = name
+	}
+	return operationType
+}
+
+// timeTransform starts timing a transform of an operation type, and returns the function that records how long it took
+// once the transform returns
+func (e *exportEngine) timeTransform(operationType string) func() {
+	started := time.Now()
+	return func() {
+		e.observeTransform(operationType, time.Since(started))
+	}
+}
+
+// observeTransform records how long a transform of an operation type took, in the metrics and the timing summary
+func (e *exportEngine) observeTransform(operationType string, duration time.Duration) {
+	utils.TransformDuration.WithLabelValues(e.cmdArgs.Dataset, operationType).Observe(duration.Seconds())
+
+	if e.timings == nil {
+		e.timings = map[string]*transformTiming{}
+	}
+	timing, ok := e.timings[operationType]
+	if !ok {
+		timing = &transformTiming{}
+		e.timings[operationType] = timing
+	}
+	timing.count += 1
+	timing.total += duration
+	timing.max = max(timing.max, duration)
+}
+
+// summarizeTransformTimings returns the timings of every operation type, with the slowest operation types overall first
+func summarizeTransformTimings(timings map[string]*transformTiming) []transformTimingSummary {
+	summaries := make([]transformTimingSummary, 0, len(timings))
+	for operationType, timing := range timings {
+		summaries = append(summaries, transformTimingSummary{
+			OperationType:    operationType,
+			Count:            timing.count,
+			TotalSeconds:     timing.total.Seconds(),
+			MeanMicroseconds: float64(timing.total.Microseconds()) / float64(timing.count),
+			MaxMicroseconds:  float64(timing.max.Microseconds()),
+		})
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		if summaries[i].TotalSeconds != summaries[j].TotalSeconds {
+			return summaries[i].TotalSeconds > summaries[j].TotalSeconds
+		}
+		return summaries[i].OperationType < summaries[j].OperationType
+	})
+	return summaries
+}
+
+// printTransformTimings logs how long the transforms of every operation type of a dataset took as JSON, so the slowest
+// transforms can be found from the logs of an export
+func printTransformTimings(dataset string, timings map[string]*transformTiming) {
+	if len(timings) == 0 {
+		return
+	}
+
+	results, err := json.Marshal(struct {
+		Dataset          string                   `json:"dataset"`
+		TransformTimings []transformTimingSummary `json:"transform_timings"`
+	}{dataset, summarizeTransformTimings(timings)})
+	if err != nil {
+		cmdLogger.Fatal("Could not marshal transform timings: ", err)
+	}
+
+	cmdLogger.Info(string(results))
+}
@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stellar/go-stellar-sdk/ingest"
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransactionOperationType(t *testing.T) {
+	transaction := func(types ...xdr.OperationType) ingest.LedgerTransaction {
+		var operations []xdr.Operation
+		for _, operationType := range types {
+			operations = append(operations, xdr.Operation{Body: xdr.OperationBody{Type: operationType}})
+		}
+		return ingest.LedgerTransaction{Envelope: xdr.TransactionEnvelope{
+			Type: xdr.EnvelopeTypeEnvelopeTypeTx,
+			V1:   &xdr.TransactionV1Envelope{Tx: xdr.Transaction{Operations: operations}},
+		}}
+	}
+
+	assert.Equal(t, "invoke_host_function", transactionOperationType(transaction(xdr.OperationTypeInvokeHostFunction)))
+	assert.Equal(t, "payment", transactionOperationType(transaction(xdr.OperationTypePayment, xdr.OperationTypePayment)))
+	assert.Equal(t, "mixed", transactionOperationType(transaction(xdr.OperationTypePayment, xdr.OperationTypeManageData)))
+}
+
+func TestSummarizeTransformTimings(t *testing.T) {
+	engine := &exportEngine{}
+	engine.observeTransform("payment", 10*time.Microsecond)
+	engine.observeTransform("payment", 30*time.Microsecond)
+	engine.observeTransform("invoke_host_function", 2*time.Millisecond)
+
+	assert.Equal(t, []transformTimingSummary{
+		{OperationType: "invoke_host_function", Count: 1, TotalSeconds: 0.002, MeanMicroseconds: 2000, MaxMicroseconds: 2000},
+		{OperationType: "payment", Count: 2, TotalSeconds: 0.00004, MeanMicroseconds: 20, MaxMicroseconds: 30},
+	}, summarizeTransformTimings(engine.timings))
+}
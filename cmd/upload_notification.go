@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/stellar/stellar-etl/v2/internal/utils"
+)
+
+// uploadNotification is the message published once an output file is uploaded, which tells a loader where the file is
+// and what it holds. The range is the range of the export, or of the batch for export_ledger_entry_changes.
+type uploadNotification struct {
+	Provider    string    `json:"provider"`
+	Bucket      string    `json:"bucket"`
+	Path        string    `json:"path"`
+	Dataset     string    `json:"dataset"`
+	Format      string    `json:"format"`
+	Network     string    `json:"network"`
+	StartLedger uint32    `json:"start_ledger"`
+	EndLedger   uint32    `json:"end_ledger"`
+	NumRecords  int       `json:"num_records"`
+	Interrupted bool      `json:"interrupted"`
+	ChunkToken  string    `json:"chunk_token,omitempty"`
+	UploadedAt  time.Time `json:"uploaded_at"`
+}
+
+// uploadNotifier publishes a notification for every uploaded output file, so loaders can be triggered by a message
+// instead of watching the bucket. Notifications are published one at a time, in the order the files were uploaded,
+// and keyed by dataset.
+type uploadNotifier struct {
+	sink     *messageBusSink
+	provider string
+	bucket   string
+}
+
+// newUploadNotifier creates the notifier of the upload options. It is nil unless notify-bus is set.
+func newUploadNotifier(options utils.UploadOptions, provider, bucket, pubSubEndpoint, kafkaRESTURL string) (*uploadNotifier, error) {
+	if options.NotifyBus == "" {
+		return nil, nil
+	}
+	if options.NotifyTopic == "" {
+		return nil, fmt.Errorf("notify-topic is required with notify-bus")
+	}
+
+	var publisher busPublisher
+	var err error
+	switch options.NotifyBus {
+	case "pubsub":
+		publisher, err = newPubSubPublisher(options.NotifyTopic, pubSubEndpoint)
+	case "kafka":
+		publisher, err = newKafkaRESTPublisher(kafkaRESTURL, options.NotifyTopic)
+	default:
+		return nil, fmt.Errorf("unsupported notify-bus %s; expected pubsub or kafka", options.NotifyBus)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if provider == "" {
+		return nil, fmt.Errorf("notify-bus needs a cloud-provider, since notifications are only published for uploaded files")
+	}
+
+	sink, err := newMessageBusSink(publisher, "dataset", 1, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	return &uploadNotifier{sink: sink, provider: provider, bucket: bucket}, nil
+}
+
+// notify publishes the notification of an uploaded file in the background
+func (n *uploadNotifier) notify(notification uploadNotification) error {
+	notification.Provider = n.provider
+	notification.Bucket = n.bucket
+	notification.UploadedAt = time.Now().UTC()
+
+	message, err := json.Marshal(notification)
+	if err != nil {
+		return fmt.Errorf("could not encode upload notification: %v", err)
+	}
+
+	if err := n.sink.add(message); err != nil {
+		return fmt.Errorf("could not publish upload notification of %s: %v", notification.Path, err)
+	}
+	return nil
+}
+
+// close waits until every notification is acknowledged
+func (n *uploadNotifier) close() error {
+	if err := n.sink.close(); err != nil {
+		return fmt.Errorf("could not publish upload notifications: %v", err)
+	}
+	return nil
+}
+
+func (n *uploadNotifier) describe() string {
+	return "Upload notifications: " + n.sink.describe()
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stellar/stellar-etl/v2/internal/utils"
+)
+
+func TestUploadNotifier(t *testing.T) {
+	var records []kafkaRecord
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/topics/stellar-etl.uploads", r.URL.Path)
+		var request struct {
+			Records []kafkaRecord `json:"records"`
+		}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		records = append(records, request.Records...)
+		io.WriteString(w, `{"offsets":[{"partition":0,"offset":1}]}`)
+	}))
+	defer server.Close()
+
+	options := utils.UploadOptions{NotifyBus: "kafka", NotifyTopic: "stellar-etl.uploads"}
+	notifier, err := newUploadNotifier(options, "gcp", "stellar-etl-cli", "", server.URL)
+	assert.NoError(t, err)
+
+	assert.NoError(t, notifier.notify(uploadNotification{Path: "exported_trades.txt", Dataset: "trades", Format: "json", Network: "pubnet", StartLedger: 1000, EndLedger: 1999, NumRecords: 42}))
+	assert.NoError(t, notifier.notify(uploadNotification{Path: "exported_trades.parquet", Dataset: "trades", Format: "parquet", Network: "pubnet", StartLedger: 1000, EndLedger: 1999, NumRecords: 42}))
+	assert.NoError(t, notifier.close())
+	assert.Equal(t, "Upload notifications: Published 2 records in 2 batches to kafka topic stellar-etl.uploads", notifier.describe())
+
+	assert.Len(t, records, 2)
+	assert.Equal(t, "trades", *records[0].Key)
+
+	var notification map[string]interface{}
+	assert.NoError(t, json.Unmarshal(records[0].Value, &notification))
+	assert.NotEmpty(t, notification["uploaded_at"])
+	delete(notification, "uploaded_at")
+	assert.Equal(t, map[string]interface{}{
+		"provider":     "gcp",
+		"bucket":       "stellar-etl-cli",
+		"path":         "exported_trades.txt",
+		"dataset":      "trades",
+		"format":       "json",
+		"network":      "pubnet",
+		"start_ledger": float64(1000),
+		"end_ledger":   float64(1999),
+		"num_records":  float64(42),
+		"interrupted":  false,
+	}, notification)
+}
+
+func TestNewUploadNotifier(t *testing.T) {
+	notifier, err := newUploadNotifier(utils.UploadOptions{}, "", "", "", "")
+	assert.NoError(t, err)
+	assert.Nil(t, notifier)
+
+	_, err = newUploadNotifier(utils.UploadOptions{NotifyBus: "kafka"}, "gcp", "stellar-etl-cli", "", "http://localhost:8082")
+	assert.EqualError(t, err, "notify-topic is required with notify-bus")
+
+	_, err = newUploadNotifier(utils.UploadOptions{NotifyBus: "sqs", NotifyTopic: "uploads"}, "gcp", "stellar-etl-cli", "", "")
+	assert.EqualError(t, err, "unsupported notify-bus sqs; expected pubsub or kafka")
+
+	_, err = newUploadNotifier(utils.UploadOptions{NotifyBus: "kafka", NotifyTopic: "uploads"}, "", "stellar-etl-cli", "", "http://localhost:8082")
+	assert.EqualError(t, err, "notify-bus needs a cloud-provider, since notifications are only published for uploaded files")
+
+	_, err = newUploadNotifier(utils.UploadOptions{NotifyBus: "pubsub", NotifyTopic: "uploads"}, "gcp", "stellar-etl-cli", "", "")
+	assert.EqualError(t, err, "pubsub topic must be projects/<project>/topics/<topic>, got uploads")
+}
@@ -2,7 +2,9 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"hash/crc32"
 	"io"
 	"os"
 	"time"
@@ -13,12 +15,16 @@ import (
 type GCS struct {
 	gcsCredentialsPath string
 	gcsBucket          string
+	chunkSize          int
 }
 
-func newGCS(gcsCredentialsPath, gcsBucket string) CloudStorage {
+// newGCS creates a GCS uploader. Files are uploaded in resumable sessions of chunkSizeMB MiB chunks, so a chunk that
+// fails is retried without restarting the upload. A chunkSizeMB of 0 uploads a file in a single request.
+func newGCS(gcsCredentialsPath, gcsBucket string, chunkSizeMB int) CloudStorage {
 	return &GCS{
 		gcsCredentialsPath: gcsCredentialsPath,
 		gcsBucket:          gcsBucket,
+		chunkSize:          chunkSizeMB << 20,
 	}
 }
 
@@ -33,6 +39,7 @@ func (g *GCS) UploadTo(credentialsPath, bucket, path string) error {
 	if err != nil {
 		return fmt.Errorf("failed to open file %s: %v", path, err)
 	}
+	defer reader.Close()
 
 	ctx := context.Background()
 	client, err := storage.NewClient(ctx)
@@ -44,13 +51,19 @@ func (g *GCS) UploadTo(credentialsPath, bucket, path string) error {
 	ctx, cancel := context.WithTimeout(ctx, time.Hour)
 	defer cancel()
 
-	wc := client.Bucket(bucket).Object(path).NewWriter(ctx)
+	// Writes of new objects aren't idempotent, so the client doesn't retry them by default. The object is written
+	// as a whole once the upload completes, so retrying a chunk of the resumable session is safe.
+	object := client.Bucket(bucket).Object(path).Retryer(storage.WithPolicy(storage.RetryAlways))
+	wc := object.NewWriter(ctx)
+	wc.ChunkSize = g.chunkSize
 
 	uploadLocation := fmt.Sprintf("gs://%s/%s", bucket, path)
 	cmdLogger.Infof("Uploading %s to %s", path, uploadLocation)
 
+	// The checksum of the local file is computed while it is uploaded, and compared with the checksum GCS computed
+	checksum := crc32.New(crc32.MakeTable(crc32.Castagnoli))
 	var written int64
-	if written, err = io.Copy(wc, reader); err != nil {
+	if written, err = io.Copy(wc, io.TeeReader(reader, checksum)); err != nil {
 		return fmt.Errorf("unable to copy: %v", err)
 	}
 	err = wc.Close()
@@ -61,14 +74,38 @@ func (g *GCS) UploadTo(credentialsPath, bucket, path string) error {
 	// This is a possibly redundant check to make sure that the file is actually
 	// uploaded to GCS and is readable
 	pathObj := client.Bucket(bucket).Object(path)
-	_, err = pathObj.Attrs(ctx)
+	attrs, err := pathObj.Attrs(ctx)
 	if err != nil {
 		return fmt.Errorf("uploaded file does not exist: %v", err)
 	}
+	if attrs.CRC32C != checksum.Sum32() {
+		return fmt.Errorf("uploaded file %s has CRC32C %d, but the local file has %d", uploadLocation, attrs.CRC32C, checksum.Sum32())
+	}
 
 	cmdLogger.Infof("Successfully uploaded %d bytes to gs://%s/%s", written, bucket, path)
 
-	deleteLocalFiles(path)
-
 	return nil
 }
+
+// Exists checks if an object was already uploaded to the bucket
+func (g *GCS) Exists(credentialsPath, bucket, path string) (bool, error) {
+	if len(credentialsPath) > 0 {
+		os.Setenv("GOOGLE_APPLICATION_CREDENTIALS", credentialsPath)
+	}
+
+	ctx := context.Background()
+	client, err := storage.NewClient(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to create client: %v", err)
+	}
+	defer client.Close()
+
+	_, err = client.Bucket(bucket).Object(path).Attrs(ctx)
+	if errors.Is(err, storage.ErrObjectNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stellar/stellar-etl/v2/internal/utils"
+)
+
+// flakyStorage fails the first failures uploads
+type flakyStorage struct {
+	failures int
+	uploads  int
+}
+
+func (f *flakyStorage) UploadTo(credentialsPath, bucket, path string) error {
+	f.uploads += 1
+	if f.uploads <= f.failures {
+		return errors.New("connection reset by peer")
+	}
+	return nil
+}
+
+func TestUploadWithRetries(t *testing.T) {
+	storage := &flakyStorage{failures: 2}
+	assert.NoError(t, uploadWithRetries(storage, "", "bucket", "ledgers.txt", 3, 0))
+	assert.Equal(t, 3, storage.uploads)
+
+	storage = &flakyStorage{failures: 5}
+	err := uploadWithRetries(storage, "", "bucket", "ledgers.txt", 3, 0)
+	assert.EqualError(t, err, "could not upload ledgers.txt after 4 attempts: connection reset by peer")
+	assert.Equal(t, 4, storage.uploads)
+
+	storage = &flakyStorage{failures: 1}
+	assert.Error(t, uploadWithRetries(storage, "", "bucket", "ledgers.txt", 0, 0))
+	assert.Equal(t, 1, storage.uploads)
+}
+
+func TestUploadFileDeletesLocalFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ledgers.txt")
+	assert.NoError(t, os.WriteFile(path, []byte("{}\n"), 0644))
+
+	assert.NoError(t, uploadFile(&flakyStorage{}, "", "bucket", path, utils.UploadOptions{}))
+	assert.FileExists(t, path)
+
+	err := uploadFile(&flakyStorage{failures: 1}, "", "bucket", path, utils.UploadOptions{DeleteLocalAfterUpload: true})
+	assert.Error(t, err)
+	assert.FileExists(t, path)
+
+	assert.NoError(t, uploadFile(&flakyStorage{}, "", "bucket", path, utils.UploadOptions{DeleteLocalAfterUpload: true}))
+	assert.NoFileExists(t, path)
+}
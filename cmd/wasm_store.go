@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/stellar/stellar-etl/v2/internal/utils"
+)
+
+// wasmObjectStore is the cloud storage of a wasm store, which can check if an object was already uploaded
+type wasmObjectStore interface {
+	CloudStorage
+	Exists(credentialsPath, bucket, path string) (bool, error)
+}
+
+// wasmStore writes the wasm of exported contract code to a folder, named by its hash, and uploads it to the bucket of
+// the export. A wasm that is already in the folder or the bucket is not written or uploaded again, so the store can be
+// shared by every export and only grows with new contracts.
+type wasmStore struct {
+	folder      string
+	storage     wasmObjectStore
+	credentials string
+	bucket      string
+	options     utils.UploadOptions
+
+	seen       map[string]bool
+	numStored  int
+	numSkipped int
+}
+
+// newWasmStore creates the wasm store of the wasm-output folder. The wasm is only uploaded if a cloud provider is set.
+func newWasmStore(folder, credentials, bucket, provider string, options utils.UploadOptions) (*wasmStore, error) {
+	if err := os.MkdirAll(folder, os.ModePerm); err != nil {
+		return nil, fmt.Errorf("unable to mkdir %s: %v", folder, err)
+	}
+
+	store := &wasmStore{folder: folder, credentials: credentials, bucket: bucket, options: options, seen: map[string]bool{}}
+	switch provider {
+	case "":
+	case "gcp":
+		if bucket == "" {
+			return nil, fmt.Errorf("wasm-output needs a cloud-storage-bucket to upload to")
+		}
+		store.storage = newGCS(credentials, bucket, options.ChunkSizeMB).(*GCS)
+	default:
+		return nil, fmt.Errorf("unsupported cloud provider %s for wasm-output", provider)
+	}
+	return store, nil
+}
+
+// store writes the wasm of a contract code with the hex encoded hash, unless it is already stored
+func (s *wasmStore) store(hash string, code []byte) error {
+	if s.seen[hash] {
+		s.numSkipped += 1
+		return nil
+	}
+
+	// The hash of contract code is the SHA-256 of its wasm, so a mismatch means the wasm would be stored under the wrong name
+	sum := sha256.Sum256(code)
+	if hex.EncodeToString(sum[:]) != hash {
+		return fmt.Errorf("wasm of contract code %s has SHA-256 %s", hash, hex.EncodeToString(sum[:]))
+	}
+
+	path := filepath.Join(s.folder, hash+".wasm")
+	stored := true
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		if err := os.WriteFile(path, code, 0644); err != nil {
+			return fmt.Errorf("could not write wasm %s: %v", path, err)
+		}
+		stored = false
+	} else if err != nil {
+		return fmt.Errorf("could not check wasm %s: %v", path, err)
+	}
+
+	if s.storage != nil {
+		exists, err := s.storage.Exists(s.credentials, s.bucket, path)
+		if err != nil {
+			return fmt.Errorf("could not check if wasm %s was uploaded: %v", path, err)
+		}
+		if !exists {
+			if err := uploadWithRetries(s.storage, s.credentials, s.bucket, path, s.options.Retries, time.Duration(s.options.RetryWait)*time.Second); err != nil {
+				return err
+			}
+			stored = false
+		}
+	}
+
+	s.seen[hash] = true
+	if stored {
+		s.numSkipped += 1
+	} else {
+		s.numStored += 1
+	}
+	return nil
+}
+
+func (s *wasmStore) describe() string {
+	return fmt.Sprintf("Stored %d new contract wasm files in %s, skipped %d that were already stored", s.numStored, s.folder, s.numSkipped)
+}
@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stellar/stellar-etl/v2/internal/utils"
+)
+
+// bucketStorage records the objects uploaded to a bucket
+type bucketStorage struct {
+	objects map[string]bool
+	uploads int
+}
+
+func (b *bucketStorage) UploadTo(credentialsPath, bucket, path string) error {
+	b.uploads += 1
+	b.objects[path] = true
+	return nil
+}
+
+func (b *bucketStorage) Exists(credentialsPath, bucket, path string) (bool, error) {
+	return b.objects[path], nil
+}
+
+func TestWasmStore(t *testing.T) {
+	folder := filepath.Join(t.TempDir(), "wasm")
+	store, err := newWasmStore(folder, "", "", "", utils.UploadOptions{})
+	assert.NoError(t, err)
+
+	code := []byte("\x00asm\x01\x00\x00\x00")
+	sum := sha256.Sum256(code)
+	hash := hex.EncodeToString(sum[:])
+	assert.NoError(t, store.store(hash, code))
+	assert.NoError(t, store.store(hash, code))
+
+	written, err := os.ReadFile(filepath.Join(folder, hash+".wasm"))
+	assert.NoError(t, err)
+	assert.Equal(t, code, written)
+	assert.Equal(t, "Stored 1 new contract wasm files in "+folder+", skipped 1 that were already stored", store.describe())
+
+	// The wasm is already in the folder, so it is only uploaded
+	bucket := &bucketStorage{objects: map[string]bool{}}
+	store, err = newWasmStore(folder, "", "", "", utils.UploadOptions{})
+	assert.NoError(t, err)
+	store.storage = bucket
+	assert.NoError(t, store.store(hash, code))
+	assert.Equal(t, 1, bucket.uploads)
+	assert.True(t, bucket.objects[filepath.Join(folder, hash+".wasm")])
+
+	// Wasm that is in both the folder and the bucket is skipped
+	store.seen = map[string]bool{}
+	assert.NoError(t, store.store(hash, code))
+	assert.Equal(t, 1, bucket.uploads)
+	assert.Equal(t, 1, store.numStored)
+	assert.Equal(t, 1, store.numSkipped)
+
+	assert.EqualError(t, store.store("00", code), "wasm of contract code 00 has SHA-256 "+hash)
+}
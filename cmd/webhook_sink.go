@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	// webhookSignatureHeader holds the hex encoded HMAC-SHA256 of the timestamp, a period, and the request body
+	webhookSignatureHeader = "X-Stellar-ETL-Signature"
+	// webhookTimestampHeader holds the unix time the batch was signed at, so receivers can reject replayed requests
+	webhookTimestampHeader = "X-Stellar-ETL-Timestamp"
+)
+
+// webhookSink POSTs batches of exported records to a webhook as JSON arrays. Each batch is retried with an exponential
+// backoff when the webhook can't be reached or responds with a 429 or 5xx status.
+type webhookSink struct {
+	url         string
+	secret      []byte
+	batchSize   int
+	maxAttempts int
+	retryWait   time.Duration
+	client      *http.Client
+
+	batch      []json.RawMessage
+	numBatches int
+}
+
+func newWebhookSink(url, secret string, batchSize int) (*webhookSink, error) {
+	if url == "" {
+		return nil, fmt.Errorf("webhook-url is required for the webhook sink")
+	}
+	if batchSize <= 0 {
+		return nil, fmt.Errorf("webhook-batch-size must be positive, got %d", batchSize)
+	}
+
+	return &webhookSink{
+		url:         url,
+		secret:      []byte(secret),
+		batchSize:   batchSize,
+		maxAttempts: 5,
+		retryWait:   time.Second,
+		client:      &http.Client{Timeout: 30 * time.Second},
+	}, nil
+}
+
+// add queues an encoded record, and sends the batch once it is full
+func (w *webhookSink) add(record []byte) error {
+	w.batch = append(w.batch, record)
+	if len(w.batch) >= w.batchSize {
+		return w.flush()
+	}
+	return nil
+}
+
+// flush sends the queued records, if there are any
+func (w *webhookSink) flush() error {
+	if len(w.batch) == 0 {
+		return nil
+	}
+
+	body, err := json.Marshal(w.batch)
+	if err != nil {
+		return fmt.Errorf("could not encode batch: %v", err)
+	}
+
+	for attempt := 1; ; attempt++ {
+		retry, err := w.post(body)
+		if err == nil {
+			break
+		}
+		if !retry || attempt >= w.maxAttempts {
+			return fmt.Errorf("could not send batch of %d records to webhook after %d attempts: %v", len(w.batch), attempt, err)
+		}
+
+		wait := w.retryWait << (attempt - 1)
+		cmdLogger.Warnf("Could not send batch to webhook, retrying in %s: %v", wait, err)
+		time.Sleep(wait)
+	}
+
+	w.numBatches += 1
+	w.batch = w.batch[:0]
+	return nil
+}
+
+// post sends a single request, and returns whether a failed request should be retried
+func (w *webhookSink) post(body []byte) (bool, error) {
+	req, err := http.NewRequest(http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	if len(w.secret) > 0 {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		req.Header.Set(webhookTimestampHeader, timestamp)
+		req.Header.Set(webhookSignatureHeader, signWebhookBody(w.secret, timestamp, body))
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+
+	retry := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	return retry, fmt.Errorf("webhook returned %s", resp.Status)
+}
+
+func (w *webhookSink) close() error {
+	return nil
+}
+
+func (w *webhookSink) describe() string {
+	return fmt.Sprintf("Sent %d batches to the webhook", w.numBatches)
+}
+
+// signWebhookBody returns the signature of a request, prefixed with the algorithm used
+func signWebhookBody(secret []byte, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
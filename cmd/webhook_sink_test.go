@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stellar/stellar-etl/v2/internal/transform"
+	"github.com/stellar/stellar-etl/v2/internal/utils"
+)
+
+func TestSignWebhookBody(t *testing.T) {
+	signature := signWebhookBody([]byte("secret"), "1700000000", []byte(`[{"a":1}]`))
+	assert.Equal(t, "sha256=516856209dea79008d72e48f00c1c767cd294a440ae742a16140cd7375ced090", signature)
+}
+
+func TestWebhookSinkBatchesAndSigns(t *testing.T) {
+	var batches [][]map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.Equal(t, signWebhookBody([]byte("secret"), r.Header.Get(webhookTimestampHeader), body), r.Header.Get(webhookSignatureHeader))
+
+		var batch []map[string]interface{}
+		assert.NoError(t, json.Unmarshal(body, &batch))
+		batches = append(batches, batch)
+	}))
+	defer server.Close()
+
+	cmdArgs := utils.FlagValues{Limit: -1, Sink: "webhook", WebhookURL: server.URL, WebhookSecret: "secret", WebhookBatchSize: 2}
+	engine := newExportEngine(cmdArgs, utils.EnvironmentDetails{}, new(transform.LedgerUpgradeOutput), nil)
+	for i := int32(0); i < 3; i++ {
+		engine.export(transform.LedgerUpgradeOutput{LedgerSequence: 10, UpgradeIndex: i})
+	}
+	engine.flushSink()
+
+	assert.Nil(t, engine.outFile)
+	assert.Equal(t, 3, engine.numExported)
+	assert.Equal(t, 2, engine.sink.(*webhookSink).numBatches)
+	assert.Len(t, batches, 2)
+	assert.Len(t, batches[0], 2)
+	assert.Len(t, batches[1], 1)
+	assert.Equal(t, float64(2), batches[1][0]["upgrade_index"])
+}
+
+func TestWebhookSinkRetries(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests += 1
+		if requests < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	sink, err := newWebhookSink(server.URL, "", 10)
+	assert.NoError(t, err)
+	sink.retryWait = 0
+
+	assert.NoError(t, sink.add([]byte(`{"a":1}`)))
+	assert.NoError(t, sink.flush())
+	assert.Equal(t, 3, requests)
+	assert.Empty(t, sink.batch)
+}
+
+func TestWebhookSinkDoesNotRetryClientErrors(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests += 1
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	sink, err := newWebhookSink(server.URL, "", 10)
+	assert.NoError(t, err)
+	sink.retryWait = 0
+
+	assert.NoError(t, sink.add([]byte(`{"a":1}`)))
+	assert.Error(t, sink.flush())
+	assert.Equal(t, 1, requests)
+}
+
+func TestNewWebhookSinkRequiresURL(t *testing.T) {
+	_, err := newWebhookSink("", "", 10)
+	assert.Error(t, err)
+
+	_, err = newWebhookSink("http://localhost", "", 0)
+	assert.Error(t, err)
+}
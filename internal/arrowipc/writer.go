@@ -0,0 +1,234 @@
+// Package arrowipc writes rows to Apache Arrow IPC files (.arrow), which pyarrow and pandas can read without parsing
+// or copying the data. Schemas are read from the same parquet struct tags that are used for the parquet output, so
+// every dataset has the same columns in both formats.
+package arrowipc
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+
+	"github.com/apache/arrow/go/arrow"
+	"github.com/apache/arrow/go/arrow/array"
+	"github.com/apache/arrow/go/arrow/ipc"
+	"github.com/apache/arrow/go/arrow/memory"
+	"github.com/stellar/stellar-etl/v2/internal/utils"
+)
+
+// DefaultBatchSize is the number of rows in each record batch
+const DefaultBatchSize = 65536
+
+// column maps a field of the parquet schema struct to a field of the arrow schema
+type column struct {
+	name       string
+	fieldIndex int
+	dataType   arrow.DataType
+}
+
+// dataType returns the arrow type of a column from its parquet tag
+func dataType(parquetType, convertedType string) (arrow.DataType, error) {
+	switch parquetType {
+	case "BYTE_ARRAY":
+		if convertedType == "UTF8" {
+			return arrow.BinaryTypes.String, nil
+		}
+		return arrow.BinaryTypes.Binary, nil
+	case "STRING", "MAP":
+		// Maps are written as JSON strings
+		return arrow.BinaryTypes.String, nil
+	case "BOOLEAN":
+		return arrow.FixedWidthTypes.Boolean, nil
+	case "INT32":
+		return arrow.PrimitiveTypes.Int32, nil
+	case "INT64":
+		if convertedType == "TIMESTAMP_MILLIS" {
+			return arrow.FixedWidthTypes.Timestamp_ms, nil
+		}
+		return arrow.PrimitiveTypes.Int64, nil
+	case "FLOAT":
+		return arrow.PrimitiveTypes.Float32, nil
+	case "DOUBLE":
+		return arrow.PrimitiveTypes.Float64, nil
+	}
+
+	return nil, fmt.Errorf("unsupported parquet type %s", parquetType)
+}
+
+// newColumns builds the columns of a parquet schema struct
+func newColumns(schemaType reflect.Type) ([]column, error) {
+	columns := []column{}
+	for i := 0; i < schemaType.NumField(); i++ {
+		field := schemaType.Field(i)
+		tag := utils.ParseParquetTag(field.Tag.Get("parquet"))
+		if tag["name"] == "" {
+			continue
+		}
+
+		elementType, elementConvertedType := tag["type"], tag["convertedtype"]
+		isList := field.Type.Kind() == reflect.Slice && field.Type.Elem().Kind() != reflect.Uint8
+		if tag["type"] == "MAP" && tag["convertedtype"] == "LIST" {
+			elementType, elementConvertedType = tag["valuetype"], tag["valueconvertedtype"]
+			isList = true
+		}
+
+		columnType, err := dataType(elementType, elementConvertedType)
+		if err != nil {
+			return nil, fmt.Errorf("column %s: %v", tag["name"], err)
+		}
+		if isList {
+			columnType = arrow.ListOf(columnType)
+		}
+
+		columns = append(columns, column{name: tag["name"], fieldIndex: i, dataType: columnType})
+	}
+
+	return columns, nil
+}
+
+// toBytes converts a string, byte slice or any other value to bytes. Other values are encoded as JSON.
+func toBytes(value reflect.Value) ([]byte, error) {
+	if value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return []byte{}, nil
+		}
+		value = value.Elem()
+	}
+
+	switch {
+	case value.Kind() == reflect.String:
+		return []byte(value.String()), nil
+	case value.Kind() == reflect.Slice && value.Type().Elem().Kind() == reflect.Uint8:
+		return value.Bytes(), nil
+	}
+
+	return json.Marshal(value.Interface())
+}
+
+// toInt converts any integer value to an int64
+func toInt(value reflect.Value) (int64, error) {
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return value.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int64(value.Uint()), nil
+	}
+	return 0, fmt.Errorf("expected an integer, got %s", value.Type())
+}
+
+// appendValue appends a value to the builder of a column
+func appendValue(builder array.Builder, value reflect.Value) error {
+	switch b := builder.(type) {
+	case *array.StringBuilder:
+		v, err := toBytes(value)
+		if err != nil {
+			return err
+		}
+		b.Append(string(v))
+	case *array.BinaryBuilder:
+		v, err := toBytes(value)
+		if err != nil {
+			return err
+		}
+		b.Append(v)
+	case *array.BooleanBuilder:
+		b.Append(value.Bool())
+	case *array.Int32Builder:
+		v, err := toInt(value)
+		if err != nil {
+			return err
+		}
+		b.Append(int32(v))
+	case *array.Int64Builder:
+		v, err := toInt(value)
+		if err != nil {
+			return err
+		}
+		b.Append(v)
+	case *array.TimestampBuilder:
+		v, err := toInt(value)
+		if err != nil {
+			return err
+		}
+		b.Append(arrow.Timestamp(v))
+	case *array.Float32Builder:
+		b.Append(float32(value.Float()))
+	case *array.Float64Builder:
+		b.Append(value.Float())
+	case *array.ListBuilder:
+		b.Append(true)
+		for i := 0; i < value.Len(); i++ {
+			if err := appendValue(b.ValueBuilder(), value.Index(i)); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("unsupported arrow builder %T", builder)
+	}
+
+	return nil
+}
+
+// Write writes the rows to w as an Arrow IPC file, in record batches of up to batchSize rows. parquetSchema is the
+// parquet schema struct of the rows, and every row has to be a value of that struct.
+func Write(w io.WriteSeeker, parquetSchema interface{}, rows []interface{}, batchSize int) error {
+	schemaType := reflect.TypeOf(parquetSchema)
+	for schemaType.Kind() == reflect.Pointer {
+		schemaType = schemaType.Elem()
+	}
+	if schemaType.Kind() != reflect.Struct {
+		return fmt.Errorf("parquet schema must be a struct, got %s", schemaType)
+	}
+	if batchSize <= 0 {
+		return fmt.Errorf("batch size must be positive, got %d", batchSize)
+	}
+
+	columns, err := newColumns(schemaType)
+	if err != nil {
+		return err
+	}
+
+	fields := make([]arrow.Field, len(columns))
+	for i, col := range columns {
+		fields[i] = arrow.Field{Name: col.name, Type: col.dataType}
+	}
+	schema := arrow.NewSchema(fields, nil)
+
+	mem := memory.NewGoAllocator()
+	writer, err := ipc.NewFileWriter(w, ipc.WithSchema(schema), ipc.WithAllocator(mem))
+	if err != nil {
+		return err
+	}
+
+	builder := array.NewRecordBuilder(mem, schema)
+	defer builder.Release()
+
+	for start := 0; start < len(rows); start += batchSize {
+		end := start + batchSize
+		if end > len(rows) {
+			end = len(rows)
+		}
+
+		for i := start; i < end; i++ {
+			rowValue := reflect.ValueOf(rows[i])
+			if rowValue.Type() != schemaType {
+				return fmt.Errorf("row %d is a %s, expected a %s", i, rowValue.Type(), schemaType)
+			}
+
+			for j, col := range columns {
+				if err := appendValue(builder.Field(j), rowValue.Field(col.fieldIndex)); err != nil {
+					return fmt.Errorf("row %d, column %s: %v", i, col.name, err)
+				}
+			}
+		}
+
+		record := builder.NewRecord()
+		err := writer.Write(record)
+		record.Release()
+		if err != nil {
+			return err
+		}
+	}
+
+	return writer.Close()
+}
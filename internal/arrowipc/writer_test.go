@@ -0,0 +1,79 @@
+package arrowipc
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/apache/arrow/go/arrow"
+	"github.com/apache/arrow/go/arrow/array"
+	"github.com/apache/arrow/go/arrow/ipc"
+	"github.com/stretchr/testify/assert"
+)
+
+type testRow struct {
+	Name     string   `parquet:"name=name, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	Sequence int64    `parquet:"name=ledger_sequence, type=INT64, convertedtype=UINT_64"`
+	ClosedAt int64    `parquet:"name=closed_at, type=INT64, convertedtype=TIMESTAMP_MILLIS"`
+	Index    int32    `parquet:"name=index, type=INT32"`
+	Amount   float64  `parquet:"name=amount, type=DOUBLE"`
+	Deleted  bool     `parquet:"name=deleted, type=BOOLEAN"`
+	Signers  []string `parquet:"name=signers, type=MAP, convertedtype=LIST, valuetype=BYTE_ARRAY, valueconvertedtype=UTF8"`
+}
+
+func TestWrite(t *testing.T) {
+	rows := []interface{}{
+		testRow{Name: "a", Sequence: 10, ClosedAt: 1000000, Index: 1, Amount: 1.5, Deleted: true, Signers: []string{"x", "y"}},
+		testRow{Name: "bc", Sequence: 11, ClosedAt: 1001000, Index: 2, Amount: 2.5},
+		testRow{Name: "d", Sequence: 12, ClosedAt: 1002000, Index: 3, Amount: 3.5, Signers: []string{"z"}},
+	}
+
+	path := filepath.Join(t.TempDir(), "rows.arrow")
+	file, err := os.Create(path)
+	assert.NoError(t, err)
+	assert.NoError(t, Write(file, new(testRow), rows, 2))
+	assert.NoError(t, file.Close())
+
+	file, err = os.Open(path)
+	assert.NoError(t, err)
+	defer file.Close()
+
+	reader, err := ipc.NewFileReader(file)
+	assert.NoError(t, err)
+	defer reader.Close()
+
+	schema := reader.Schema()
+	assert.Equal(t, "name", schema.Field(0).Name)
+	assert.Equal(t, arrow.BinaryTypes.String, schema.Field(0).Type)
+	assert.Equal(t, arrow.PrimitiveTypes.Int64, schema.Field(1).Type)
+	assert.Equal(t, arrow.TIMESTAMP, schema.Field(2).Type.ID())
+	assert.Equal(t, arrow.PrimitiveTypes.Int32, schema.Field(3).Type)
+	assert.Equal(t, arrow.LIST, schema.Field(6).Type.ID())
+
+	assert.Equal(t, 2, reader.NumRecords())
+	first, err := reader.Record(0)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), first.NumRows())
+	assert.Equal(t, "bc", first.Column(0).(*array.String).Value(1))
+	assert.Equal(t, arrow.Timestamp(1000000), first.Column(2).(*array.Timestamp).Value(0))
+	assert.Equal(t, true, first.Column(5).(*array.Boolean).Value(0))
+
+	signers := first.Column(6).(*array.List)
+	assert.Equal(t, []int32{0, 2, 2}, signers.Offsets()[:3])
+	assert.Equal(t, "y", signers.ListValues().(*array.String).Value(1))
+
+	second, err := reader.Record(1)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), second.NumRows())
+	assert.Equal(t, int64(12), second.Column(1).(*array.Int64).Value(0))
+	assert.Equal(t, 3.5, second.Column(4).(*array.Float64).Value(0))
+}
+
+func TestWriteRejectsOtherRowTypes(t *testing.T) {
+	file, err := os.Create(filepath.Join(t.TempDir(), "rows.arrow"))
+	assert.NoError(t, err)
+	defer file.Close()
+
+	err = Write(file, new(testRow), []interface{}{struct{}{}}, DefaultBatchSize)
+	assert.Error(t, err)
+}
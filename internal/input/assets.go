@@ -1,11 +1,8 @@
 package input
 
 import (
-	"context"
-
 	"github.com/stellar/stellar-etl/v2/internal/utils"
 
-	"github.com/stellar/go-stellar-sdk/ingest/ledgerbackend"
 	"github.com/stellar/go-stellar-sdk/xdr"
 )
 
@@ -17,45 +14,27 @@ type AssetTransformInput struct {
 	LedgerCloseMeta  xdr.LedgerCloseMeta
 }
 
-// GetPaymentOperations returns a slice of payment operations that can include new assets from the ledgers in the provided range (inclusive on both ends)
-func GetPaymentOperations(start, end uint32, limit int64, env utils.EnvironmentDetails, useCaptiveCore bool) ([]AssetTransformInput, error) {
-	ctx := context.Background()
-	backend, err := utils.CreateLedgerBackend(ctx, useCaptiveCore, env)
-	if err != nil {
-		return []AssetTransformInput{}, err
-	}
-
+// GetLedgerPaymentOperations returns the payment operations of a ledger, which can include new assets
+func GetLedgerPaymentOperations(ledger xdr.LedgerCloseMeta) []AssetTransformInput {
 	assetSlice := []AssetTransformInput{}
-	err = backend.PrepareRange(ctx, ledgerbackend.BoundedRange(start, end))
-	panicIf(err)
-	for seq := start; seq <= end; seq++ {
-		// Get ledger from sequence number
-		ledger, err := backend.GetLedger(ctx, seq)
-		if err != nil {
-			return []AssetTransformInput{}, err
-		}
-
-		transactionSet := ledger.TransactionEnvelopes()
-
-		for txIndex, transaction := range transactionSet {
-			for opIndex, op := range transaction.Operations() {
-				if op.Body.Type == xdr.OperationTypePayment || op.Body.Type == xdr.OperationTypeManageSellOffer {
-					assetSlice = append(assetSlice, AssetTransformInput{
-						Operation:        op,
-						OperationIndex:   int32(opIndex),
-						TransactionIndex: int32(txIndex),
-						LedgerSeqNum:     int32(seq),
-						LedgerCloseMeta:  ledger,
-					})
-				}
-
+	seq := utils.GetLedgerSequence(ledger)
+	transactionSet := ledger.TransactionEnvelopes()
+
+	for txIndex, transaction := range transactionSet {
+		for opIndex, op := range transaction.Operations() {
+			if op.Body.Type == xdr.OperationTypePayment || op.Body.Type == xdr.OperationTypeManageSellOffer {
+				assetSlice = append(assetSlice, AssetTransformInput{
+					Operation:        op,
+					OperationIndex:   int32(opIndex),
+					TransactionIndex: int32(txIndex),
+					LedgerSeqNum:     int32(seq),
+					LedgerCloseMeta:  ledger,
+				})
 			}
 
 		}
-		if int64(len(assetSlice)) >= limit && limit >= 0 {
-			break
-		}
+
 	}
 
-	return assetSlice, nil
+	return assetSlice
 }
@@ -9,24 +9,32 @@ import (
 	"github.com/stellar/go-stellar-sdk/xdr"
 )
 
-// GetPaymentOperations returns a slice of payment operations that can include new assets from the ledgers in the provided range (inclusive on both ends)
-func GetPaymentOperationsHistoryArchive(start, end uint32, limit int64, env utils.EnvironmentDetails, useCaptivere bool) ([]AssetTransformInput, error) {
+// ReadPaymentOperationsHistoryArchive reads the payment operations that can include new assets from the ledgers in the
+// provided range (inclusive on both ends) of the history archives, and calls processLedger with the operations of every
+// ledger. Reading stops early if processLedger returns false.
+func ReadPaymentOperationsHistoryArchive(start, end uint32, env utils.EnvironmentDetails, processLedger func([]AssetTransformInput) bool) error {
 	backend, err := utils.CreateBackend(start, end, env.ArchiveURLs)
 	if err != nil {
-		return []AssetTransformInput{}, err
+		return err
 	}
 
-	assetSlice := []AssetTransformInput{}
 	ctx := context.Background()
-	for seq := start; seq <= end; seq++ {
+	for _, seq := range ledgerSequences(start, end, env.CommonFlagValues) {
 		// Get ledger from sequence number
 		ledger, err := backend.GetLedgerArchive(ctx, seq)
 		if err != nil {
-			return []AssetTransformInput{}, err
+			return err
+		}
+
+		// The asset transform only reads the close time of the ledger, which the archives have
+		lcm, err := backend.GetLedgerHeaderMeta(ctx, seq)
+		if err != nil {
+			return err
 		}
 
 		transactionSet := transform.GetTransactionSet(ledger)
 
+		assetSlice := []AssetTransformInput{}
 		for txIndex, transaction := range transactionSet {
 			for opIndex, op := range transaction.Operations() {
 				if op.Body.Type == xdr.OperationTypePayment || op.Body.Type == xdr.OperationTypeManageSellOffer {
@@ -35,17 +43,17 @@ func GetPaymentOperationsHistoryArchive(start, end uint32, limit int64, env util
 						OperationIndex:   int32(opIndex),
 						TransactionIndex: int32(txIndex),
 						LedgerSeqNum:     int32(seq),
-						LedgerCloseMeta:  xdr.LedgerCloseMeta{}, // Using historyArchive will not support getting LCM
+						LedgerCloseMeta:  lcm,
 					})
 				}
 
 			}
 
 		}
-		if int64(len(assetSlice)) >= limit && limit >= 0 {
+		if !processLedger(assetSlice) {
 			break
 		}
 	}
 
-	return assetSlice, nil
+	return nil
 }
@@ -1,10 +1,12 @@
 package input
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io"
 	"math"
+	"sort"
 
 	"github.com/stellar/stellar-etl/v2/internal/utils"
 
@@ -22,11 +24,19 @@ type LedgerChanges struct {
 	LedgerHeaders []xdr.LedgerHeaderHistoryEntry
 }
 
+// LedgerTransactions are transactions along with the headers of the ledgers they were applied in
+type LedgerTransactions struct {
+	Transactions  []ingest.LedgerTransaction
+	LedgerHeaders []xdr.LedgerHeaderHistoryEntry
+}
+
 // ChangeBatch represents the changes in a batch of ledgers represented by the range [BatchStart, BatchEnd)
 type ChangeBatch struct {
-	Changes    map[xdr.LedgerEntryType]LedgerChanges
-	BatchStart uint32
-	BatchEnd   uint32
+	Changes map[xdr.LedgerEntryType]LedgerChanges
+	// SorobanTransactions are kept so that entries which were read without being changed can be found in their footprints
+	SorobanTransactions LedgerTransactions
+	BatchStart          uint32
+	BatchEnd            uint32
 }
 
 // PrepareCaptiveCore creates a new captive core instance and prepares it with the given range. The range is unbounded when end = 0, and is bounded and validated otherwise
@@ -97,6 +107,7 @@ func extractBatch(
 		xdr.LedgerEntryTypeTtl}
 
 	ledgerChanges := map[xdr.LedgerEntryType]LedgerChanges{}
+	sorobanTransactions := LedgerTransactions{}
 	ctx := context.Background()
 	for seq := batchStart; seq <= batchEnd; {
 		changeCompactors := map[xdr.LedgerEntryType]*ingest.ChangeCompactor{}
@@ -135,12 +146,28 @@ func extractBatch(
 				}
 			}
 
+			// The change reader has read every transaction of the ledger, so it is rewound to read them again
+			changeReader.LedgerTransactionReader.Rewind()
+			for {
+				transaction, err := changeReader.LedgerTransactionReader.Read()
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					logger.Fatal(fmt.Sprintf("unable to read transactions from ledger %d: ", seq), err)
+				}
+				if transaction.IsSorobanTx() {
+					sorobanTransactions.Transactions = append(sorobanTransactions.Transactions, transaction)
+					sorobanTransactions.LedgerHeaders = append(sorobanTransactions.LedgerHeaders, header)
+				}
+			}
+
 			changeReader.Close()
 			seq++
 		}
 
 		for dataType, compactor := range changeCompactors {
-			for _, change := range compactor.GetChanges() {
+			for _, change := range sortChanges(compactor.GetChanges()) {
 				dataTypeChanges := ledgerChanges[dataType]
 				dataTypeChanges.Changes = append(dataTypeChanges.Changes, change)
 				dataTypeChanges.LedgerHeaders = append(dataTypeChanges.LedgerHeaders, header)
@@ -151,10 +178,46 @@ func extractBatch(
 	}
 
 	return ChangeBatch{
-		Changes:    ledgerChanges,
-		BatchStart: batchStart,
-		BatchEnd:   batchEnd,
+		Changes:             ledgerChanges,
+		SorobanTransactions: sorobanTransactions,
+		BatchStart:          batchStart,
+		BatchEnd:            batchEnd,
+	}
+}
+
+// sortChanges sorts the compacted changes of a ledger by their ledger keys. The compactor returns the changes in a
+// random order, so they are sorted to export them in the same order every time.
+func sortChanges(changes []ingest.Change) []ingest.Change {
+	keys := make([][]byte, len(changes))
+	for i, change := range changes {
+		key, err := change.LedgerKey()
+		if err != nil {
+			continue
+		}
+		keys[i], _ = key.MarshalBinary()
 	}
+
+	sort.Sort(changesByKey{changes: changes, keys: keys})
+	return changes
+}
+
+// changesByKey sorts changes by their encoded ledger keys
+type changesByKey struct {
+	changes []ingest.Change
+	keys    [][]byte
+}
+
+func (c changesByKey) Len() int {
+	return len(c.changes)
+}
+
+func (c changesByKey) Less(i, j int) bool {
+	return bytes.Compare(c.keys[i], c.keys[j]) < 0
+}
+
+func (c changesByKey) Swap(i, j int) {
+	c.changes[i], c.changes[j] = c.changes[j], c.changes[i]
+	c.keys[i], c.keys[j] = c.keys[j], c.keys[i]
 }
 
 // StreamChanges reads in ledgers, processes the changes, and send the changes to the channel matching their type
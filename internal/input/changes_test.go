@@ -228,3 +228,27 @@ func TestStreamChangesBatchNumbers(t *testing.T) {
 		})
 	}
 }
+
+func TestSortChanges(t *testing.T) {
+	offerChange := func(offerID xdr.Int64) ingest.Change {
+		return ingest.Change{
+			Type: xdr.LedgerEntryTypeOffer,
+			Post: &xdr.LedgerEntry{
+				Data: xdr.LedgerEntryData{
+					Type: xdr.LedgerEntryTypeOffer,
+					Offer: &xdr.OfferEntry{
+						SellerId: xdr.MustAddress("GAOEOQMXDDXPVJC3HDFX6LZFKANJ4OOLQOD2MNXJ7PGAY5FEO4BRRAQU"),
+						OfferId:  offerID,
+					},
+				},
+			},
+		}
+	}
+
+	sorted := sortChanges([]ingest.Change{offerChange(3), offerChange(1), offerChange(2)})
+	offerIDs := []xdr.Int64{}
+	for _, change := range sorted {
+		offerIDs = append(offerIDs, change.Post.Data.Offer.OfferId)
+	}
+	assert.Equal(t, []xdr.Int64{1, 2, 3}, offerIDs)
+}
@@ -0,0 +1,351 @@
+package input
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	farm "github.com/dgryski/go-farm"
+	"github.com/stellar/stellar-etl/v2/internal/utils"
+
+	"github.com/stellar/go-stellar-sdk/ingest/ledgerbackend"
+	"github.com/stellar/go-stellar-sdk/support/log"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+// descendingSegmentSize is the number of ledgers read at a time when exporting in descending order.
+// The ledger backends can only be read forwards, so each segment is read in ascending order and then reversed.
+const descendingSegmentSize = 64
+
+// maxSampleGap is the largest gap between two sampled ledgers that are still read from the same segment.
+// The unsampled ledgers in between are read and discarded; larger gaps start a new segment so they are never downloaded.
+const maxSampleGap = 64
+
+// ledgerSegment is a contiguous range of ledgers [Start, End] that is read from a single prepared backend range
+type ledgerSegment struct {
+	Start uint32
+	End   uint32
+}
+
+// sequences returns the ledger sequences in the segment in the order they should be processed
+func (s ledgerSegment) sequences(descending bool) []uint32 {
+	seqs := make([]uint32, 0, s.End-s.Start+1)
+	for seq := s.Start; seq <= s.End; seq++ {
+		seqs = append(seqs, seq)
+	}
+
+	if descending {
+		for i, j := 0, len(seqs)-1; i < j; i, j = i+1, j-1 {
+			seqs[i], seqs[j] = seqs[j], seqs[i]
+		}
+	}
+
+	return seqs
+}
+
+// isSampling returns true if the sampling flags select a subset of the ledgers in a range
+func isSampling(flags utils.CommonFlagValues) bool {
+	return flags.EveryNthLedger > 1 || (flags.SampleRate > 0 && flags.SampleRate < 1)
+}
+
+// isSampledLedger returns true if the ledger should be exported. Every nth ledger is counted from the start of the range.
+// The sample rate hashes the ledger sequence, so a ledger is either always or never sampled for a given rate.
+func isSampledLedger(seq, start uint32, flags utils.CommonFlagValues) bool {
+	if flags.EveryNthLedger > 1 && (seq-start)%flags.EveryNthLedger != 0 {
+		return false
+	}
+
+	if flags.SampleRate > 0 && flags.SampleRate < 1 {
+		seqBytes := make([]byte, 4)
+		binary.BigEndian.PutUint32(seqBytes, seq)
+		return float64(farm.Fingerprint64(seqBytes)) < flags.SampleRate*math.MaxUint64
+	}
+
+	return true
+}
+
+// sampledSegments groups the sampled ledgers in the range [start, end] into ascending segments
+func sampledSegments(start, end uint32, flags utils.CommonFlagValues) []ledgerSegment {
+	if !isSampling(flags) {
+		return []ledgerSegment{{Start: start, End: end}}
+	}
+
+	segments := []ledgerSegment{}
+	for seq := start; seq <= end; seq++ {
+		if isSampledLedger(seq, start, flags) {
+			last := len(segments) - 1
+			if last >= 0 && seq-segments[last].End <= maxSampleGap {
+				segments[last].End = seq
+			} else {
+				segments = append(segments, ledgerSegment{Start: seq, End: seq})
+			}
+		}
+
+		// Avoid wrapping around when the range ends at the largest possible sequence
+		if seq == math.MaxUint32 {
+			break
+		}
+	}
+
+	return segments
+}
+
+// planLedgerSegments splits the range [start, end] into the segments that should be read, in the order they should be read.
+// Ascending exports read the whole range as a single segment unless sampling skips large parts of it.
+// Descending exports read checkpoint sized segments starting from the end of the range.
+func planLedgerSegments(start, end uint32, flags utils.CommonFlagValues) []ledgerSegment {
+	if end < start {
+		return []ledgerSegment{}
+	}
+
+	ascending := sampledSegments(start, end, flags)
+	if !flags.Descending {
+		return ascending
+	}
+
+	segments := []ledgerSegment{}
+	for i := len(ascending) - 1; i >= 0; i-- {
+		runStart := ascending[i].Start
+		segmentEnd := ascending[i].End
+		for {
+			segmentStart := runStart
+			if segmentEnd-runStart >= descendingSegmentSize {
+				segmentStart = segmentEnd - descendingSegmentSize + 1
+			}
+
+			segments = append(segments, ledgerSegment{Start: segmentStart, End: segmentEnd})
+			if segmentStart == runStart {
+				break
+			}
+			segmentEnd = segmentStart - 1
+		}
+	}
+
+	return segments
+}
+
+// ledgerSequences returns every sampled ledger sequence in the range [start, end] in the order it should be processed.
+// It is used by the history archive backends, which can be read in any order.
+func ledgerSequences(start, end uint32, flags utils.CommonFlagValues) []uint32 {
+	seqs := []uint32{}
+	for _, segment := range planLedgerSegments(start, end, flags) {
+		for _, seq := range segment.sequences(flags.Descending) {
+			if isSampledLedger(seq, start, flags) {
+				seqs = append(seqs, seq)
+			}
+		}
+	}
+
+	return seqs
+}
+
+// readLedgers reads the ledgers in the range [start, end] and calls processLedger on each sampled ledger, following the order
+// configured in the environment flags. A new datastore backend is created for every segment because the backends cannot be
+// rewound. Captive core replays the ledgers from a checkpoint every time a range is prepared, so it prepares the whole range
+// once and discards the unsampled ledgers instead, and it can't read in descending order.
+// With autoscale-workers, the backend is also recreated at the next ledger whenever the number of workers changes.
+// Reading stops early if processLedger returns false or an error, or without an error if the context is cancelled.
+func readLedgers(ctx context.Context, start, end uint32, env utils.EnvironmentDetails, useCaptiveCore bool, processLedger func(xdr.LedgerCloseMeta) (bool, error)) error {
+	flags := env.CommonFlagValues
+	autoscaler := newWorkerAutoscaler(env, useCaptiveCore)
+
+	segments := planLedgerSegments(start, end, flags)
+	if useCaptiveCore && flags.LedgerFixture == "" {
+		if flags.Descending {
+			return fmt.Errorf("descending order is not supported with captive-core, which can only replay ledgers forwards")
+		}
+		if len(segments) > 1 {
+			segments = []ledgerSegment{{Start: segments[0].Start, End: segments[len(segments)-1].End}}
+		}
+	}
+
+	prepareBackend := func(from, to uint32) (ledgerbackend.LedgerBackend, error) {
+		backendEnv := env
+		if autoscaler != nil {
+			backendEnv = autoscaler.env(env)
+		}
+
+		backend, err := utils.CreateLedgerBackend(ctx, useCaptiveCore, backendEnv)
+		if err != nil {
+			return nil, err
+		}
+
+		err = backend.PrepareRange(ctx, ledgerbackend.BoundedRange(from, to))
+		if err != nil {
+			backend.Close()
+			return nil, err
+		}
+		return backend, nil
+	}
+
+	for _, segment := range segments {
+		backend, err := prepareBackend(segment.Start, segment.End)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		var ledgers []xdr.LedgerCloseMeta
+		keepReading := true
+		for seq := segment.Start; seq <= segment.End && keepReading; seq++ {
+			if autoscaler != nil && seq != segment.Start && autoscaler.adjust(time.Now()) {
+				backend.Close()
+				backend, err = prepareBackend(seq, segment.End)
+				if ctx.Err() != nil {
+					return nil
+				}
+				if err != nil {
+					return err
+				}
+			}
+
+			readStart := time.Now()
+			lcm, err := backend.GetLedger(ctx, seq)
+			if err != nil {
+				backend.Close()
+				if ctx.Err() != nil {
+					return nil
+				}
+				return err
+			}
+			if autoscaler != nil {
+				autoscaler.observe(time.Since(readStart))
+			}
+
+			if !isSampledLedger(seq, start, flags) {
+				continue
+			}
+
+			// Descending segments have to be fully read before they can be processed in reverse
+			if flags.Descending {
+				ledgers = append(ledgers, lcm)
+				continue
+			}
+
+			keepReading, err = processLedger(lcm)
+			if err != nil {
+				backend.Close()
+				return err
+			}
+		}
+		backend.Close()
+
+		for i := len(ledgers) - 1; i >= 0 && keepReading; i-- {
+			keepReading, err = processLedger(ledgers[i])
+			if err != nil {
+				return err
+			}
+		}
+
+		if !keepReading {
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// ReadLedgers calls processLedger on each sampled ledger in the range [start, end], following the order configured in the
+// environment flags. If end is 0, ledgers are streamed as they close until processLedger returns false or an error, or
+// the context is cancelled.
+func ReadLedgers(ctx context.Context, start, end uint32, env utils.EnvironmentDetails, useCaptiveCore bool, processLedger func(xdr.LedgerCloseMeta) (bool, error)) error {
+	if end == 0 {
+		return StreamUnboundedLedgers(ctx, start, env, useCaptiveCore, processLedger)
+	}
+
+	return readLedgers(ctx, start, end, env, useCaptiveCore, processLedger)
+}
+
+// StreamUnboundedLedgers reads the ledgers starting at start and calls processLedger on each sampled ledger as soon as it is available.
+// The backend waits for new ledgers to close, so streaming only stops if processLedger returns false or an error, or the
+// context is cancelled, which stops streaming without an error even while waiting for a ledger. A ledger that can't be
+// read is retried on a new backend up to retry-limit times, retry-wait seconds apart, before streaming stops with the error.
+func StreamUnboundedLedgers(ctx context.Context, start uint32, env utils.EnvironmentDetails, useCaptiveCore bool, processLedger func(xdr.LedgerCloseMeta) (bool, error)) error {
+	flags := env.CommonFlagValues
+	if flags.Descending {
+		return fmt.Errorf("descending order is not supported when streaming ledgers without an end ledger")
+	}
+	// Once the stream catches up, it waits for ledgers to close, which the autoscaler would take for slow downloads
+	if newWorkerAutoscaler(env, useCaptiveCore) != nil {
+		return fmt.Errorf("autoscale-workers is not supported when streaming ledgers without an end ledger")
+	}
+
+	backend, err := prepareUnboundedBackend(ctx, start, env, useCaptiveCore)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if backend != nil {
+			backend.Close()
+		}
+	}()
+
+	var retries uint32
+	for seq := start; ; {
+		lcm, err := backend.GetLedger(ctx, seq)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if errors.Is(err, utils.ErrUnsupportedLedger) {
+			return err
+		}
+		if err != nil {
+			if retries >= flags.RetryLimit {
+				return fmt.Errorf("could not read ledger %d after %d retries: %v", seq, retries, err)
+			}
+			retries += 1
+			log.Warnf("Could not read ledger %d, retrying on a new backend (%d/%d): %v", seq, retries, flags.RetryLimit, err)
+
+			backend.Close()
+			backend = nil
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(time.Duration(flags.RetryWait) * time.Second):
+			}
+
+			backend, err = prepareUnboundedBackend(ctx, seq, env, useCaptiveCore)
+			if ctx.Err() != nil {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		retries = 0
+
+		if isSampledLedger(seq, start, flags) {
+			keepReading, err := processLedger(lcm)
+			if err != nil {
+				return err
+			}
+
+			if !keepReading {
+				return nil
+			}
+		}
+		seq++
+	}
+}
+
+// prepareUnboundedBackend creates a ledger backend that streams the ledgers starting at start
+func prepareUnboundedBackend(ctx context.Context, start uint32, env utils.EnvironmentDetails, useCaptiveCore bool) (ledgerbackend.LedgerBackend, error) {
+	backend, err := utils.CreateLedgerBackend(ctx, useCaptiveCore, env)
+	if err != nil {
+		return nil, err
+	}
+
+	err = backend.PrepareRange(ctx, ledgerbackend.UnboundedRange(start))
+	if err != nil {
+		backend.Close()
+		return nil, err
+	}
+
+	return backend, nil
+}
@@ -0,0 +1,142 @@
+package input
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stellar/stellar-etl/v2/internal/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlanLedgerSegments(t *testing.T) {
+	tests := []struct {
+		name       string
+		start, end uint32
+		flags      utils.CommonFlagValues
+		want       []ledgerSegment
+	}{
+		{
+			name:  "ascending range is a single segment",
+			start: 100,
+			end:   300,
+			want:  []ledgerSegment{{Start: 100, End: 300}},
+		},
+		{
+			name:  "end before start",
+			start: 100,
+			end:   50,
+			want:  []ledgerSegment{},
+		},
+		{
+			name:  "descending range smaller than a segment",
+			start: 10,
+			end:   20,
+			flags: utils.CommonFlagValues{Descending: true},
+			want:  []ledgerSegment{{Start: 10, End: 20}},
+		},
+		{
+			name:  "descending range is split from the end",
+			start: 100,
+			end:   300,
+			flags: utils.CommonFlagValues{Descending: true},
+			want: []ledgerSegment{
+				{Start: 237, End: 300},
+				{Start: 173, End: 236},
+				{Start: 109, End: 172},
+				{Start: 100, End: 108},
+			},
+		},
+		{
+			name:  "descending range of exactly one segment",
+			start: 1,
+			end:   64,
+			flags: utils.CommonFlagValues{Descending: true},
+			want:  []ledgerSegment{{Start: 1, End: 64}},
+		},
+		{
+			name:  "sampled ledgers close together share a segment",
+			start: 1000,
+			end:   1035,
+			flags: utils.CommonFlagValues{EveryNthLedger: 10},
+			want:  []ledgerSegment{{Start: 1000, End: 1030}},
+		},
+		{
+			name:  "sampled ledgers far apart are read separately",
+			start: 1000,
+			end:   1250,
+			flags: utils.CommonFlagValues{EveryNthLedger: 100},
+			want: []ledgerSegment{
+				{Start: 1000, End: 1000},
+				{Start: 1100, End: 1100},
+				{Start: 1200, End: 1200},
+			},
+		},
+		{
+			name:  "descending sampled ledgers",
+			start: 1000,
+			end:   1250,
+			flags: utils.CommonFlagValues{EveryNthLedger: 100, Descending: true},
+			want: []ledgerSegment{
+				{Start: 1200, End: 1200},
+				{Start: 1100, End: 1100},
+				{Start: 1000, End: 1000},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, planLedgerSegments(tt.start, tt.end, tt.flags))
+		})
+	}
+}
+
+func TestLedgerSequences(t *testing.T) {
+	assert.Equal(t, []uint32{5, 6, 7}, ledgerSequences(5, 7, utils.CommonFlagValues{}))
+	assert.Equal(t, []uint32{7, 6, 5}, ledgerSequences(5, 7, utils.CommonFlagValues{Descending: true}))
+	assert.Equal(t, []uint32{}, ledgerSequences(7, 5, utils.CommonFlagValues{}))
+}
+
+func TestLedgerSequencesEveryNthLedger(t *testing.T) {
+	flags := utils.CommonFlagValues{EveryNthLedger: 3}
+	assert.Equal(t, []uint32{5, 8, 11}, ledgerSequences(5, 12, flags))
+
+	flags.Descending = true
+	assert.Equal(t, []uint32{11, 8, 5}, ledgerSequences(5, 12, flags))
+}
+
+func TestLedgerSequencesSampleRate(t *testing.T) {
+	flags := utils.CommonFlagValues{SampleRate: 0.1}
+	sampled := ledgerSequences(1, 10000, flags)
+
+	// The sample should be close to the requested rate
+	assert.InDelta(t, 1000, len(sampled), 150)
+
+	// Sampling depends only on the ledger sequence, so a sub-range samples the same ledgers
+	expected := []uint32{}
+	for _, seq := range sampled {
+		if seq >= 5000 && seq <= 6000 {
+			expected = append(expected, seq)
+		}
+	}
+	assert.Equal(t, expected, ledgerSequences(5000, 6000, flags))
+}
+
+func TestReadLedgersCaptiveCoreDescending(t *testing.T) {
+	env := utils.EnvironmentDetails{CommonFlagValues: utils.CommonFlagValues{Descending: true}}
+	err := readLedgers(context.Background(), 5, 7, env, true, func(xdr.LedgerCloseMeta) (bool, error) {
+		t.Fatal("no ledger should be processed")
+		return false, nil
+	})
+	assert.EqualError(t, err, "descending order is not supported with captive-core, which can only replay ledgers forwards")
+}
+
+func TestStreamUnboundedLedgersAutoscaleWorkers(t *testing.T) {
+	env := utils.EnvironmentDetails{CommonFlagValues: utils.CommonFlagValues{AutoscaleWorkers: true}}
+	err := StreamUnboundedLedgers(context.Background(), 5, env, false, func(xdr.LedgerCloseMeta) (bool, error) {
+		t.Fatal("no ledger should be processed")
+		return false, nil
+	})
+	assert.EqualError(t, err, "autoscale-workers is not supported when streaming ledgers without an end ledger")
+}
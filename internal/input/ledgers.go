@@ -6,86 +6,65 @@ import (
 	"github.com/stellar/stellar-etl/v2/internal/utils"
 
 	"github.com/stellar/go-stellar-sdk/historyarchive"
-	"github.com/stellar/go-stellar-sdk/ingest/ledgerbackend"
 	"github.com/stellar/go-stellar-sdk/xdr"
 )
 
 // GetLedgers returns a slice of ledger close metas for the ledgers in the provided range (inclusive on both ends)
 func GetLedgers(start, end uint32, limit int64, env utils.EnvironmentDetails, useCaptiveCore bool) ([]utils.HistoryArchiveLedgerAndLCM, error) {
-	ctx := context.Background()
-	backend, err := utils.CreateLedgerBackend(ctx, useCaptiveCore, env)
+	ledgerSlice := []utils.HistoryArchiveLedgerAndLCM{}
+	err := readLedgers(context.Background(), start, end, env, useCaptiveCore, func(lcm xdr.LedgerCloseMeta) (bool, error) {
+		if int64(len(ledgerSlice)) >= limit && limit >= 0 {
+			return false, nil
+		}
+
+		ledger, err := ledgerAndLCM(lcm)
+		if err != nil {
+			return false, err
+		}
+
+		ledgerSlice = append(ledgerSlice, ledger)
+		return int64(len(ledgerSlice)) < limit || limit < 0, nil
+	})
 	if err != nil {
 		return []utils.HistoryArchiveLedgerAndLCM{}, err
 	}
 
-	ledgerSlice := []utils.HistoryArchiveLedgerAndLCM{}
-	err = backend.PrepareRange(ctx, ledgerbackend.BoundedRange(start, end))
-	panicIf(err)
-	for seq := start; seq <= end; seq++ {
-		lcm, err := backend.GetLedger(ctx, seq)
-		if err != nil {
-			return []utils.HistoryArchiveLedgerAndLCM{}, err
-		}
+	return ledgerSlice, nil
+}
 
-		var ext xdr.TransactionHistoryEntryExt
-		var transactionResultPair []xdr.TransactionResultPair
+// ledgerAndLCM builds the history archive representation of a ledger from its ledger close meta
+func ledgerAndLCM(lcm xdr.LedgerCloseMeta) (utils.HistoryArchiveLedgerAndLCM, error) {
+	ext, err := utils.LedgerCloseMetaTxSet(lcm)
+	if err != nil {
+		return utils.HistoryArchiveLedgerAndLCM{}, err
+	}
 
-		switch lcm.V {
-		case 0:
-			ext = xdr.TransactionHistoryEntryExt{
-				V:                0,
-				GeneralizedTxSet: nil,
-			}
-			for _, transactionResultMeta := range lcm.V0.TxProcessing {
-				transactionResultPair = append(transactionResultPair, transactionResultMeta.Result)
-			}
-		case 1:
-			ext = xdr.TransactionHistoryEntryExt{
-				V:                1,
-				GeneralizedTxSet: &lcm.V1.TxSet,
-			}
-			for _, transactionResultMeta := range lcm.V1.TxProcessing {
-				transactionResultPair = append(transactionResultPair, transactionResultMeta.Result)
-			}
-		case 2:
-			ext = xdr.TransactionHistoryEntryExt{
-				V:                1,
-				GeneralizedTxSet: &lcm.V2.TxSet,
-			}
-			for _, transactionResultMeta := range lcm.V2.TxProcessing {
-				transactionResultPair = append(transactionResultPair, transactionResultMeta.Result)
-			}
-		}
+	transactionResultPair, err := utils.LedgerCloseMetaResultPairs(lcm)
+	if err != nil {
+		return utils.HistoryArchiveLedgerAndLCM{}, err
+	}
 
-		ledger := historyarchive.Ledger{
-			Header: lcm.LedgerHeaderHistoryEntry(),
-			Transaction: xdr.TransactionHistoryEntry{
-				LedgerSeq: lcm.LedgerHeaderHistoryEntry().Header.LedgerSeq,
-				TxSet: xdr.TransactionSet{
-					PreviousLedgerHash: lcm.LedgerHeaderHistoryEntry().Header.PreviousLedgerHash,
-					Txs:                lcm.TransactionEnvelopes(),
-				},
-				Ext: ext,
+	ledger := historyarchive.Ledger{
+		Header: lcm.LedgerHeaderHistoryEntry(),
+		Transaction: xdr.TransactionHistoryEntry{
+			LedgerSeq: lcm.LedgerHeaderHistoryEntry().Header.LedgerSeq,
+			TxSet: xdr.TransactionSet{
+				PreviousLedgerHash: lcm.LedgerHeaderHistoryEntry().Header.PreviousLedgerHash,
+				Txs:                lcm.TransactionEnvelopes(),
 			},
-			TransactionResult: xdr.TransactionHistoryResultEntry{
-				LedgerSeq: lcm.LedgerHeaderHistoryEntry().Header.LedgerSeq,
-				TxResultSet: xdr.TransactionResultSet{
-					Results: transactionResultPair,
-				},
-				Ext: xdr.TransactionHistoryResultEntryExt{},
+			Ext: ext,
+		},
+		TransactionResult: xdr.TransactionHistoryResultEntry{
+			LedgerSeq: lcm.LedgerHeaderHistoryEntry().Header.LedgerSeq,
+			TxResultSet: xdr.TransactionResultSet{
+				Results: transactionResultPair,
 			},
-		}
-
-		ledgerLCM := utils.HistoryArchiveLedgerAndLCM{
-			Ledger: ledger,
-			LCM:    lcm,
-		}
-
-		ledgerSlice = append(ledgerSlice, ledgerLCM)
-		if int64(len(ledgerSlice)) >= limit && limit >= 0 {
-			break
-		}
+			Ext: xdr.TransactionHistoryResultEntryExt{},
+		},
 	}
 
-	return ledgerSlice, nil
+	return utils.HistoryArchiveLedgerAndLCM{
+		Ledger: ledger,
+		LCM:    lcm,
+	}, nil
 }
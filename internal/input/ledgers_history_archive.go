@@ -15,14 +15,21 @@ func GetLedgersHistoryArchive(start, end uint32, limit int64, env utils.Environm
 
 	ledgerSlice := []utils.HistoryArchiveLedgerAndLCM{}
 	ctx := context.Background()
-	for seq := start; seq <= end; seq++ {
+	for _, seq := range ledgerSequences(start, end, env.CommonFlagValues) {
 		ledger, err := backend.GetLedgerArchive(ctx, seq)
 		if err != nil {
 			return []utils.HistoryArchiveLedgerAndLCM{}, err
 		}
 
+		// The ledger transform only reads the header, transaction set and results, which the archives have
+		lcm, err := backend.GetLedgerHeaderMeta(ctx, seq)
+		if err != nil {
+			return []utils.HistoryArchiveLedgerAndLCM{}, err
+		}
+
 		ledgerLCM := utils.HistoryArchiveLedgerAndLCM{
 			Ledger: ledger,
+			LCM:    lcm,
 		}
 
 		ledgerSlice = append(ledgerSlice, ledgerLCM)
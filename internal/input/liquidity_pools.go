@@ -0,0 +1,99 @@
+package input
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/stellar/go-stellar-sdk/ingest"
+	"github.com/stellar/go-stellar-sdk/xdr"
+
+	"github.com/stellar/stellar-etl/v2/internal/utils"
+)
+
+// LiquidityPools is the state of every liquidity pool by pool id, kept up to date with the changes of every ledger
+type LiquidityPools map[xdr.PoolId]xdr.LedgerEntry
+
+// GetCheckpointLiquidityPools reads the liquidity pools in the bucket list of a checkpoint ledger from the history archives
+func GetCheckpointLiquidityPools(ctx context.Context, env utils.EnvironmentDetails, checkpoint uint32) (LiquidityPools, error) {
+	archive, err := utils.CreateHistoryArchiveClient(env.ArchiveURLs)
+	if err != nil {
+		return nil, err
+	}
+
+	reader, err := ingest.NewCheckpointChangeReader(ctx, archive, checkpoint, ingest.WithFilter(
+		func(entry xdr.LedgerEntry) bool { return entry.Data.Type == xdr.LedgerEntryTypeLiquidityPool },
+		func(key xdr.LedgerKey) bool { return key.Type == xdr.LedgerEntryTypeLiquidityPool },
+	))
+	if err != nil {
+		return nil, fmt.Errorf("could not read the bucket list of checkpoint %d: %v", checkpoint, err)
+	}
+	defer reader.Close()
+
+	pools := LiquidityPools{}
+	for {
+		change, err := reader.Read()
+		if err == io.EOF {
+			return pools, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("could not read the bucket list of checkpoint %d: %v", checkpoint, err)
+		}
+		pools.applyChange(change)
+	}
+}
+
+// ApplyLedger updates the pools with the changes of a ledger
+func (p LiquidityPools) ApplyLedger(lcm xdr.LedgerCloseMeta, networkPassphrase string) error {
+	reader, err := ingest.NewLedgerChangeReaderFromLedgerCloseMeta(networkPassphrase, lcm)
+	if err != nil {
+		return fmt.Errorf("could not create change reader for ledger %d: %v", lcm.LedgerSequence(), err)
+	}
+	defer reader.Close()
+
+	for {
+		change, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("could not read changes from ledger %d: %v", lcm.LedgerSequence(), err)
+		}
+		p.applyChange(change)
+	}
+}
+
+// applyChange keeps the latest state of a changed pool, and forgets removed pools
+func (p LiquidityPools) applyChange(change ingest.Change) {
+	if change.Type != xdr.LedgerEntryTypeLiquidityPool {
+		return
+	}
+
+	if change.Post == nil {
+		delete(p, change.Pre.Data.MustLiquidityPool().LiquidityPoolId)
+		return
+	}
+	p[change.Post.Data.MustLiquidityPool().LiquidityPoolId] = *change.Post
+}
+
+// Changes returns the state of every pool as a created change, like the changes read from a checkpoint, ordered by
+// pool id so that snapshots are exported in the same order every time
+func (p LiquidityPools) Changes() []ingest.Change {
+	changes := make([]ingest.Change, 0, len(p))
+	for _, entry := range p {
+		entry := entry
+		changes = append(changes, ingest.Change{
+			Type:       xdr.LedgerEntryTypeLiquidityPool,
+			Post:       &entry,
+			ChangeType: xdr.LedgerEntryChangeTypeLedgerEntryCreated,
+		})
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		a, b := changes[i].Post.Data.MustLiquidityPool().LiquidityPoolId, changes[j].Post.Data.MustLiquidityPool().LiquidityPoolId
+		return bytes.Compare(a[:], b[:]) < 0
+	})
+	return changes
+}
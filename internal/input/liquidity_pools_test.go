@@ -0,0 +1,48 @@
+package input
+
+import (
+	"testing"
+
+	"github.com/stellar/go-stellar-sdk/ingest"
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLiquidityPools(t *testing.T) {
+	poolEntry := func(id byte, reserveA xdr.Int64) *xdr.LedgerEntry {
+		return &xdr.LedgerEntry{
+			Data: xdr.LedgerEntryData{
+				Type: xdr.LedgerEntryTypeLiquidityPool,
+				LiquidityPool: &xdr.LiquidityPoolEntry{
+					LiquidityPoolId: xdr.PoolId{id},
+					Body: xdr.LiquidityPoolEntryBody{
+						Type:            xdr.LiquidityPoolTypeLiquidityPoolConstantProduct,
+						ConstantProduct: &xdr.LiquidityPoolEntryConstantProduct{ReserveA: reserveA},
+					},
+				},
+			},
+		}
+	}
+	poolChange := func(pre, post *xdr.LedgerEntry) ingest.Change {
+		return ingest.Change{Type: xdr.LedgerEntryTypeLiquidityPool, Pre: pre, Post: post}
+	}
+
+	pools := LiquidityPools{}
+	pools.applyChange(poolChange(nil, poolEntry(3, 100)))
+	pools.applyChange(poolChange(nil, poolEntry(1, 200)))
+	pools.applyChange(poolChange(nil, poolEntry(2, 300)))
+	pools.applyChange(poolChange(poolEntry(1, 200), poolEntry(1, 250)))
+	pools.applyChange(poolChange(poolEntry(2, 300), nil))
+	pools.applyChange(ingest.Change{Type: xdr.LedgerEntryTypeOffer, Post: &xdr.LedgerEntry{
+		Data: xdr.LedgerEntryData{Type: xdr.LedgerEntryTypeOffer, Offer: &xdr.OfferEntry{}},
+	}})
+
+	changes := pools.Changes()
+	reserves := []xdr.Int64{}
+	for _, change := range changes {
+		assert.Equal(t, xdr.LedgerEntryChangeTypeLedgerEntryCreated, change.ChangeType)
+		reserves = append(reserves, change.Post.Data.LiquidityPool.Body.ConstantProduct.ReserveA)
+	}
+	assert.Equal(t, []xdr.Int64{250, 100}, reserves)
+	assert.Equal(t, xdr.PoolId{1}, changes[0].Post.Data.LiquidityPool.LiquidityPoolId)
+}
@@ -6,7 +6,6 @@ import (
 	"io"
 
 	"github.com/stellar/go-stellar-sdk/ingest"
-	"github.com/stellar/go-stellar-sdk/ingest/ledgerbackend"
 	"github.com/stellar/go-stellar-sdk/xdr"
 	"github.com/stellar/stellar-etl/v2/internal/utils"
 )
@@ -28,53 +27,54 @@ func panicIf(err error) {
 
 // GetOperations returns a slice of operations for the ledgers in the provided range (inclusive on both ends)
 func GetOperations(start, end uint32, limit int64, env utils.EnvironmentDetails, useCaptiveCore bool) ([]OperationTransformInput, error) {
-	ctx := context.Background()
-
-	backend, err := utils.CreateLedgerBackend(ctx, useCaptiveCore, env)
-	if err != nil {
-		return []OperationTransformInput{}, err
-	}
-
 	opSlice := []OperationTransformInput{}
-	err = backend.PrepareRange(ctx, ledgerbackend.BoundedRange(start, end))
-	panicIf(err)
-	for seq := start; seq <= end; seq++ {
-		ledgerCloseMeta, err := backend.GetLedger(ctx, seq)
+	err := readLedgers(context.Background(), start, end, env, useCaptiveCore, func(ledgerCloseMeta xdr.LedgerCloseMeta) (bool, error) {
+		ledgerOps, err := GetLedgerOperations(ledgerCloseMeta, env)
 		if err != nil {
-			return []OperationTransformInput{}, fmt.Errorf("error getting ledger seq %d from the backend: %v", seq, err)
+			return false, err
 		}
 
-		txReader, err := ingest.NewLedgerTransactionReaderFromLedgerCloseMeta(env.NetworkPassphrase, ledgerCloseMeta)
-		if err != nil {
-			return []OperationTransformInput{}, err
-		}
-
-		for int64(len(opSlice)) < limit || limit < 0 {
-			tx, err := txReader.Read()
-			if err == io.EOF {
+		for _, op := range ledgerOps {
+			if int64(len(opSlice)) >= limit && limit >= 0 {
 				break
 			}
+			opSlice = append(opSlice, op)
+		}
 
-			for index, op := range tx.Envelope.Operations() {
-				opSlice = append(opSlice, OperationTransformInput{
-					Operation:       op,
-					OperationIndex:  int32(index),
-					Transaction:     tx,
-					LedgerSeqNum:    int32(seq),
-					LedgerCloseMeta: ledgerCloseMeta,
-				})
+		return int64(len(opSlice)) < limit || limit < 0, nil
+	})
+	if err != nil {
+		return []OperationTransformInput{}, err
+	}
 
-				if int64(len(opSlice)) >= limit && limit >= 0 {
-					break
-				}
-			}
-		}
+	return opSlice, nil
+}
 
-		txReader.Close()
+// GetLedgerOperations returns a slice of the operations in a single ledger
+func GetLedgerOperations(ledgerCloseMeta xdr.LedgerCloseMeta, env utils.EnvironmentDetails) ([]OperationTransformInput, error) {
+	seq := utils.GetLedgerSequence(ledgerCloseMeta)
+	txReader, err := ingest.NewLedgerTransactionReaderFromLedgerCloseMeta(env.NetworkPassphrase, ledgerCloseMeta)
+	if err != nil {
+		return []OperationTransformInput{}, err
+	}
+	defer txReader.Close()
 
-		if int64(len(opSlice)) >= limit && limit >= 0 {
+	opSlice := []OperationTransformInput{}
+	for {
+		tx, err := txReader.Read()
+		if err == io.EOF {
 			break
 		}
+
+		for index, op := range tx.Envelope.Operations() {
+			opSlice = append(opSlice, OperationTransformInput{
+				Operation:       op,
+				OperationIndex:  int32(index),
+				Transaction:     tx,
+				LedgerSeqNum:    int32(seq),
+				LedgerCloseMeta: ledgerCloseMeta,
+			})
+		}
 	}
 
 	return opSlice, nil
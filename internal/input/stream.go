@@ -0,0 +1,97 @@
+package input
+
+import (
+	"context"
+
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stellar/stellar-etl/v2/internal/utils"
+)
+
+// ledgerReader reads ledgers and calls processLedger on each of them until it returns false or an error
+type ledgerReader func(processLedger func(xdr.LedgerCloseMeta) (bool, error)) error
+
+// streamInputs reads ledgers on a separate goroutine and sends the inputs extracted from each ledger to the returned channel.
+// At most bufferSize inputs are read ahead of the consumer, so memory use doesn't grow with the size of the range.
+// The input channel is closed once reading stops. The error channel receives the error that stopped reading, if any, and is then closed.
+// Reading stops without an error when the context is cancelled.
+func streamInputs[T any](ctx context.Context, bufferSize int, read ledgerReader, extract func(xdr.LedgerCloseMeta) ([]T, error)) (<-chan T, <-chan error) {
+	if bufferSize < 0 {
+		bufferSize = 0
+	}
+
+	inputChan := make(chan T, bufferSize)
+	errChan := make(chan error, 1)
+	go func() {
+		defer close(errChan)
+		defer close(inputChan)
+
+		err := read(func(lcm xdr.LedgerCloseMeta) (bool, error) {
+			inputs, err := extract(lcm)
+			if err != nil {
+				return false, err
+			}
+
+			for _, item := range inputs {
+				select {
+				case <-ctx.Done():
+					return false, nil
+				case inputChan <- item:
+				}
+			}
+
+			return ctx.Err() == nil, nil
+		})
+		if err != nil {
+			errChan <- err
+		}
+	}()
+
+	return inputChan, errChan
+}
+
+// readerForRange returns a ledgerReader for the ledgers in the range [start, end]. If end is 0, ledgers are streamed as they close.
+func readerForRange(ctx context.Context, start, end uint32, env utils.EnvironmentDetails, useCaptiveCore bool) ledgerReader {
+	return func(processLedger func(xdr.LedgerCloseMeta) (bool, error)) error {
+		return ReadLedgers(ctx, start, end, env, useCaptiveCore, processLedger)
+	}
+}
+
+// StreamLedgerCloseMetas sends the LedgerCloseMeta of every ledger in the range [start, end] to the returned channel as it is read.
+// If end is 0, ledgers are streamed as they close.
+func StreamLedgerCloseMetas(ctx context.Context, start, end uint32, env utils.EnvironmentDetails, useCaptiveCore bool, bufferSize int) (<-chan xdr.LedgerCloseMeta, <-chan error) {
+	return streamInputs(ctx, bufferSize, readerForRange(ctx, start, end, env, useCaptiveCore), func(lcm xdr.LedgerCloseMeta) ([]xdr.LedgerCloseMeta, error) {
+		return []xdr.LedgerCloseMeta{lcm}, nil
+	})
+}
+
+// StreamLedgers sends the ledgers in the range [start, end] to the returned channel as they are read, instead of loading the whole range into memory
+func StreamLedgers(ctx context.Context, start, end uint32, env utils.EnvironmentDetails, useCaptiveCore bool, bufferSize int) (<-chan utils.HistoryArchiveLedgerAndLCM, <-chan error) {
+	return streamInputs(ctx, bufferSize, readerForRange(ctx, start, end, env, useCaptiveCore), func(lcm xdr.LedgerCloseMeta) ([]utils.HistoryArchiveLedgerAndLCM, error) {
+		ledger, err := ledgerAndLCM(lcm)
+		if err != nil {
+			return nil, err
+		}
+		return []utils.HistoryArchiveLedgerAndLCM{ledger}, nil
+	})
+}
+
+// StreamTransactions sends the transactions in the range [start, end] to the returned channel as they are read, instead of loading the whole range into memory
+func StreamTransactions(ctx context.Context, start, end uint32, env utils.EnvironmentDetails, useCaptiveCore bool, bufferSize int) (<-chan LedgerTransformInput, <-chan error) {
+	return streamInputs(ctx, bufferSize, readerForRange(ctx, start, end, env, useCaptiveCore), func(lcm xdr.LedgerCloseMeta) ([]LedgerTransformInput, error) {
+		return GetLedgerTransactions(lcm, env)
+	})
+}
+
+// StreamOperations sends the operations in the range [start, end] to the returned channel as they are read, instead of loading the whole range into memory
+func StreamOperations(ctx context.Context, start, end uint32, env utils.EnvironmentDetails, useCaptiveCore bool, bufferSize int) (<-chan OperationTransformInput, <-chan error) {
+	return streamInputs(ctx, bufferSize, readerForRange(ctx, start, end, env, useCaptiveCore), func(lcm xdr.LedgerCloseMeta) ([]OperationTransformInput, error) {
+		return GetLedgerOperations(lcm, env)
+	})
+}
+
+// StreamTrades sends the operations that can result in trades in the range [start, end] to the returned channel as they are read, instead of loading the whole range into memory
+func StreamTrades(ctx context.Context, start, end uint32, env utils.EnvironmentDetails, useCaptiveCore bool, bufferSize int) (<-chan TradeTransformInput, <-chan error) {
+	return streamInputs(ctx, bufferSize, readerForRange(ctx, start, end, env, useCaptiveCore), func(lcm xdr.LedgerCloseMeta) ([]TradeTransformInput, error) {
+		return GetLedgerTrades(lcm, env)
+	})
+}
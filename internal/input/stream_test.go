@@ -0,0 +1,88 @@
+package input
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeLedgerReader calls processLedger on ledgers with the given sequences and records the sequences it read
+func fakeLedgerReader(seqs []uint32, read *[]uint32) ledgerReader {
+	return func(processLedger func(xdr.LedgerCloseMeta) (bool, error)) error {
+		for _, seq := range seqs {
+			*read = append(*read, seq)
+			keepReading, err := processLedger(xdr.LedgerCloseMeta{
+				V0: &xdr.LedgerCloseMetaV0{
+					LedgerHeader: xdr.LedgerHeaderHistoryEntry{
+						Header: xdr.LedgerHeader{LedgerSeq: xdr.Uint32(seq)},
+					},
+				},
+			})
+			if err != nil || !keepReading {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
+
+// extractSequence returns the ledger sequence twice, so every ledger produces more than one input
+func extractSequence(lcm xdr.LedgerCloseMeta) ([]uint32, error) {
+	seq := lcm.LedgerSequence()
+	return []uint32{seq, seq}, nil
+}
+
+func collectStream[T any](inputChan <-chan T, errChan <-chan error) ([]T, error) {
+	inputs := []T{}
+	for item := range inputChan {
+		inputs = append(inputs, item)
+	}
+
+	return inputs, <-errChan
+}
+
+func TestStreamInputs(t *testing.T) {
+	var read []uint32
+	inputChan, errChan := streamInputs(context.Background(), 1, fakeLedgerReader([]uint32{1, 2, 3}, &read), extractSequence)
+
+	inputs, err := collectStream(inputChan, errChan)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint32{1, 1, 2, 2, 3, 3}, inputs)
+	assert.Equal(t, []uint32{1, 2, 3}, read)
+}
+
+func TestStreamInputsExtractError(t *testing.T) {
+	var read []uint32
+	extractErr := errors.New("could not extract inputs")
+	extract := func(lcm xdr.LedgerCloseMeta) ([]uint32, error) {
+		if lcm.LedgerSequence() == 2 {
+			return nil, extractErr
+		}
+		return extractSequence(lcm)
+	}
+
+	inputChan, errChan := streamInputs(context.Background(), 0, fakeLedgerReader([]uint32{1, 2, 3}, &read), extract)
+
+	inputs, err := collectStream(inputChan, errChan)
+	assert.Equal(t, extractErr, err)
+	assert.Equal(t, []uint32{1, 1}, inputs)
+	assert.Equal(t, []uint32{1, 2}, read)
+}
+
+func TestStreamInputsCancelled(t *testing.T) {
+	var read []uint32
+	ctx, cancel := context.WithCancel(context.Background())
+	inputChan, errChan := streamInputs(ctx, 0, fakeLedgerReader([]uint32{1, 2, 3}, &read), extractSequence)
+
+	assert.Equal(t, uint32(1), <-inputChan)
+	cancel()
+
+	// The remaining inputs are dropped once the reader notices the cancellation
+	_, err := collectStream(inputChan, errChan)
+	assert.NoError(t, err)
+	assert.NotContains(t, read, uint32(3))
+}
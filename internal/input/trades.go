@@ -9,7 +9,6 @@ import (
 	"github.com/stellar/stellar-etl/v2/internal/utils"
 
 	"github.com/stellar/go-stellar-sdk/ingest"
-	"github.com/stellar/go-stellar-sdk/ingest/ledgerbackend"
 	"github.com/stellar/go-stellar-sdk/support/errors"
 	"github.com/stellar/go-stellar-sdk/xdr"
 )
@@ -24,61 +23,63 @@ type TradeTransformInput struct {
 
 // GetTrades returns a slice of trades for the ledgers in the provided range (inclusive on both ends)
 func GetTrades(start, end uint32, limit int64, env utils.EnvironmentDetails, useCaptiveCore bool) ([]TradeTransformInput, error) {
-	ctx := context.Background()
-
-	backend, err := utils.CreateLedgerBackend(ctx, useCaptiveCore, env)
-	if err != nil {
-		return []TradeTransformInput{}, err
-	}
-
 	tradeSlice := []TradeTransformInput{}
-	err = backend.PrepareRange(ctx, ledgerbackend.BoundedRange(start, end))
-	panicIf(err)
-	for seq := start; seq <= end; seq++ {
-		ledgerCloseMeta, err := backend.GetLedger(ctx, seq)
+	err := readLedgers(context.Background(), start, end, env, useCaptiveCore, func(ledgerCloseMeta xdr.LedgerCloseMeta) (bool, error) {
+		ledgerTrades, err := GetLedgerTrades(ledgerCloseMeta, env)
 		if err != nil {
-			return []TradeTransformInput{}, errors.Wrap(err, "error getting ledger from the backend")
+			return false, err
 		}
 
-		txReader, err := ingest.NewLedgerTransactionReaderFromLedgerCloseMeta(env.NetworkPassphrase, ledgerCloseMeta)
-		if err != nil {
-			return []TradeTransformInput{}, err
+		for _, trade := range ledgerTrades {
+			if int64(len(tradeSlice)) >= limit && limit >= 0 {
+				break
+			}
+			tradeSlice = append(tradeSlice, trade)
 		}
 
-		closeTime, _ := utils.TimePointToUTCTimeStamp(txReader.GetHeader().Header.ScpValue.CloseTime)
+		return int64(len(tradeSlice)) < limit || limit < 0, nil
+	})
+	if err != nil {
+		return []TradeTransformInput{}, errors.Wrap(err, "error getting ledger from the backend")
+	}
 
-		for int64(len(tradeSlice)) < limit || limit < 0 {
-			tx, err := txReader.Read()
-			if err == io.EOF {
-				break
-			}
+	return tradeSlice, nil
+}
 
-			for index, op := range tx.Envelope.Operations() {
-				/*
-					Trades occur on these operation types:
-					manage buy offer, manage sell offer, create passive sell offer, path payment send, and path payment receive
-					Not all of these operations will result in trades, but this is checked in TransformTrade (an empty slice is returned if no trades occurred)
+// GetLedgerTrades returns a slice of the operations in a single ledger that can result in trades
+func GetLedgerTrades(ledgerCloseMeta xdr.LedgerCloseMeta, env utils.EnvironmentDetails) ([]TradeTransformInput, error) {
+	seq := utils.GetLedgerSequence(ledgerCloseMeta)
+	txReader, err := ingest.NewLedgerTransactionReaderFromLedgerCloseMeta(env.NetworkPassphrase, ledgerCloseMeta)
+	if err != nil {
+		return []TradeTransformInput{}, err
+	}
+	defer txReader.Close()
 
-					Trades also can only occur when these operations are successful
-				*/
-				if operationResultsInTrade(op) && tx.Result.Successful() {
-					tradeSlice = append(tradeSlice, TradeTransformInput{
-						OperationIndex:     int32(index),
-						Transaction:        tx,
-						CloseTime:          closeTime,
-						OperationHistoryID: toid.New(int32(seq), int32(tx.Index), int32(index)).ToInt64(),
-					})
-				}
+	closeTime, _ := utils.TimePointToUTCTimeStamp(txReader.GetHeader().Header.ScpValue.CloseTime)
 
-				if int64(len(tradeSlice)) >= limit && limit >= 0 {
-					break
-				}
-			}
+	tradeSlice := []TradeTransformInput{}
+	for {
+		tx, err := txReader.Read()
+		if err == io.EOF {
+			break
 		}
 
-		txReader.Close()
-		if int64(len(tradeSlice)) >= limit && limit >= 0 {
-			break
+		for index, op := range tx.Envelope.Operations() {
+			/*
+				Trades occur on these operation types:
+				manage buy offer, manage sell offer, create passive sell offer, path payment send, and path payment receive
+				Not all of these operations will result in trades, but this is checked in TransformTrade (an empty slice is returned if no trades occurred)
+
+				Trades also can only occur when these operations are successful
+			*/
+			if operationResultsInTrade(op) && tx.Result.Successful() {
+				tradeSlice = append(tradeSlice, TradeTransformInput{
+					OperationIndex:     int32(index),
+					Transaction:        tx,
+					CloseTime:          closeTime,
+					OperationHistoryID: toid.New(int32(seq), int32(tx.Index), int32(index)).ToInt64(),
+				})
+			}
 		}
 	}
 
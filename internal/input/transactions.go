@@ -7,7 +7,6 @@ import (
 	"github.com/stellar/stellar-etl/v2/internal/utils"
 
 	"github.com/stellar/go-stellar-sdk/ingest"
-	"github.com/stellar/go-stellar-sdk/ingest/ledgerbackend"
 	"github.com/stellar/go-stellar-sdk/support/errors"
 	"github.com/stellar/go-stellar-sdk/xdr"
 )
@@ -21,50 +20,52 @@ type LedgerTransformInput struct {
 
 // GetTransactions returns a slice of transactions for the ledgers in the provided range (inclusive on both ends)
 func GetTransactions(start, end uint32, limit int64, env utils.EnvironmentDetails, useCaptiveCore bool) ([]LedgerTransformInput, error) {
-	ctx := context.Background()
-
-	backend, err := utils.CreateLedgerBackend(ctx, useCaptiveCore, env)
-	if err != nil {
-		return []LedgerTransformInput{}, err
-	}
-
 	txSlice := []LedgerTransformInput{}
-	err = backend.PrepareRange(ctx, ledgerbackend.BoundedRange(start, end))
-	if err != nil {
-		return []LedgerTransformInput{}, err
-	}
-	panicIf(err)
-	for seq := start; seq <= end; seq++ {
-		ledgerCloseMeta, err := backend.GetLedger(ctx, seq)
-		if err != nil {
-			return []LedgerTransformInput{}, errors.Wrap(err, "error getting ledger from the backend")
-		}
-
-		txReader, err := ingest.NewLedgerTransactionReaderFromLedgerCloseMeta(env.NetworkPassphrase, ledgerCloseMeta)
+	err := readLedgers(context.Background(), start, end, env, useCaptiveCore, func(ledgerCloseMeta xdr.LedgerCloseMeta) (bool, error) {
+		ledgerTxs, err := GetLedgerTransactions(ledgerCloseMeta, env)
 		if err != nil {
-			return []LedgerTransformInput{}, err
+			return false, err
 		}
 
-		lhe := txReader.GetHeader()
-
 		// A negative limit value means that all input should be processed
-		for int64(len(txSlice)) < limit || limit < 0 {
-			tx, err := txReader.Read()
-			if err == io.EOF {
+		for _, tx := range ledgerTxs {
+			if int64(len(txSlice)) >= limit && limit >= 0 {
 				break
 			}
-
-			txSlice = append(txSlice, LedgerTransformInput{
-				Transaction:     tx,
-				LedgerHistory:   lhe,
-				LedgerCloseMeta: ledgerCloseMeta,
-			})
+			txSlice = append(txSlice, tx)
 		}
 
-		txReader.Close()
-		if int64(len(txSlice)) >= limit && limit >= 0 {
+		return int64(len(txSlice)) < limit || limit < 0, nil
+	})
+	if err != nil {
+		return []LedgerTransformInput{}, errors.Wrap(err, "error getting ledger from the backend")
+	}
+
+	return txSlice, nil
+}
+
+// GetLedgerTransactions returns a slice of the transactions in a single ledger
+func GetLedgerTransactions(ledgerCloseMeta xdr.LedgerCloseMeta, env utils.EnvironmentDetails) ([]LedgerTransformInput, error) {
+	txReader, err := ingest.NewLedgerTransactionReaderFromLedgerCloseMeta(env.NetworkPassphrase, ledgerCloseMeta)
+	if err != nil {
+		return []LedgerTransformInput{}, err
+	}
+	defer txReader.Close()
+
+	lhe := txReader.GetHeader()
+
+	txSlice := []LedgerTransformInput{}
+	for {
+		tx, err := txReader.Read()
+		if err == io.EOF {
 			break
 		}
+
+		txSlice = append(txSlice, LedgerTransformInput{
+			Transaction:     tx,
+			LedgerHistory:   lhe,
+			LedgerCloseMeta: ledgerCloseMeta,
+		})
 	}
 
 	return txSlice, nil
@@ -0,0 +1,122 @@
+package input
+
+import (
+	"math"
+	"runtime"
+	"runtime/debug"
+	"time"
+
+	"github.com/stellar/go-stellar-sdk/support/log"
+	"github.com/stellar/stellar-etl/v2/internal/utils"
+)
+
+const (
+	// autoscaleInterval is how often the datastore workers are adjusted. Adjusting them restarts the backend, which
+	// has to fill its buffer again, so they aren't adjusted more often.
+	autoscaleInterval = 30 * time.Second
+	// autoscaleScaleUpWait is the fraction of the time waiting for ledgers above which the workers are doubled, since
+	// the export is waiting for downloads
+	autoscaleScaleUpWait = 0.25
+	// autoscaleScaleDownWait is the fraction of the time waiting for ledgers below which a quarter of the workers are
+	// removed, since the buffer is always full and the export is limited by the transforms
+	autoscaleScaleDownWait = 0.02
+	// autoscaleMemoryHeadroom is the fraction of the memory limit above which the workers are halved
+	autoscaleMemoryHeadroom = 0.8
+)
+
+// workerAutoscaler adjusts the number of workers and the buffer size of the datastore backend to the rate at which
+// ledgers are consumed. The time the export waits in GetLedger tells if the workers download ledgers slower than they
+// are consumed, in which case there are too few workers, or if the buffer is always full, in which case the workers
+// only hold memory. The buffer size keeps its ratio to the number of workers.
+type workerAutoscaler struct {
+	numWorkers      uint32
+	bufferPerWorker uint32
+	maxWorkers      uint32
+	memoryLimit     uint64
+	heapInUse       func() uint64
+
+	windowStart time.Time
+	waited      time.Duration
+	numLedgers  int
+}
+
+// newWorkerAutoscaler creates the autoscaler of the flags. It is nil unless autoscale-workers is set and the ledgers are
+// read from the datastore.
+func newWorkerAutoscaler(env utils.EnvironmentDetails, useCaptiveCore bool) *workerAutoscaler {
+	flags := env.CommonFlagValues
+	if !flags.AutoscaleWorkers || useCaptiveCore || flags.LedgerFixture != "" {
+		return nil
+	}
+
+	memoryLimit := uint64(flags.AutoscaleMemoryMB) * 1024 * 1024
+	if memoryLimit == 0 {
+		// debug.SetMemoryLimit with a negative limit returns the limit without changing it
+		if limit := debug.SetMemoryLimit(-1); limit != math.MaxInt64 {
+			memoryLimit = uint64(limit)
+		}
+	}
+
+	numWorkers := max(flags.NumWorkers, 1)
+	return &workerAutoscaler{
+		numWorkers:      numWorkers,
+		bufferPerWorker: max(flags.BufferSize/numWorkers, 1),
+		maxWorkers:      max(flags.MaxNumWorkers, numWorkers),
+		memoryLimit:     memoryLimit,
+		heapInUse:       readHeapInUse,
+		windowStart:     time.Now(),
+	}
+}
+
+func readHeapInUse() uint64 {
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	return stats.HeapInuse
+}
+
+// env returns the environment with the current number of workers and buffer size
+func (a *workerAutoscaler) env(env utils.EnvironmentDetails) utils.EnvironmentDetails {
+	env.CommonFlagValues.NumWorkers = a.numWorkers
+	env.CommonFlagValues.BufferSize = a.numWorkers * a.bufferPerWorker
+	return env
+}
+
+// observe records a ledger that the export waited for
+func (a *workerAutoscaler) observe(waited time.Duration) {
+	a.waited += waited
+	a.numLedgers += 1
+}
+
+// adjust picks the number of workers for the ledgers observed since the last adjustment, once autoscaleInterval has
+// passed. It returns true if the number of workers changed, in which case the backend has to be recreated.
+func (a *workerAutoscaler) adjust(now time.Time) bool {
+	elapsed := now.Sub(a.windowStart)
+	if elapsed < autoscaleInterval || a.numLedgers == 0 {
+		return false
+	}
+
+	waitRatio := a.waited.Seconds() / elapsed.Seconds()
+	ledgerRate := float64(a.numLedgers) / elapsed.Seconds()
+	utils.LedgerWaitRatio.Set(waitRatio)
+	utils.LedgerReadRate.Set(ledgerRate)
+	a.windowStart, a.waited, a.numLedgers = now, 0, 0
+
+	numWorkers := a.numWorkers
+	heapInUse := a.heapInUse()
+	switch {
+	case a.memoryLimit != 0 && float64(heapInUse) > autoscaleMemoryHeadroom*float64(a.memoryLimit):
+		numWorkers = numWorkers / 2
+	case waitRatio > autoscaleScaleUpWait:
+		numWorkers = numWorkers * 2
+	case waitRatio < autoscaleScaleDownWait:
+		numWorkers = numWorkers - numWorkers/4
+	}
+	numWorkers = min(max(numWorkers, 1), a.maxWorkers)
+	if numWorkers == a.numWorkers {
+		return false
+	}
+
+	log.Infof("Reading %.1f ledgers per second and waiting for ledgers %.0f%% of the time with %d MiB in use, changing the datastore workers from %d to %d",
+		ledgerRate, 100*waitRatio, heapInUse/(1024*1024), a.numWorkers, numWorkers)
+	a.numWorkers = numWorkers
+	return true
+}
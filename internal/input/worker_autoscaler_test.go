@@ -0,0 +1,59 @@
+package input
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stellar/stellar-etl/v2/internal/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkerAutoscaler(t *testing.T) {
+	env := utils.EnvironmentDetails{CommonFlagValues: utils.CommonFlagValues{
+		AutoscaleWorkers:  true,
+		NumWorkers:        10,
+		BufferSize:        200,
+		MaxNumWorkers:     32,
+		AutoscaleMemoryMB: 1024,
+	}}
+	assert.Nil(t, newWorkerAutoscaler(env, true))
+
+	autoscaler := newWorkerAutoscaler(env, false)
+	heapInUse := uint64(100 * 1024 * 1024)
+	autoscaler.heapInUse = func() uint64 { return heapInUse }
+	now := autoscaler.windowStart
+
+	// Nothing changes before the interval has passed
+	autoscaler.observe(10 * time.Second)
+	assert.False(t, autoscaler.adjust(now.Add(time.Second)))
+
+	// Waiting for ledgers most of the time doubles the workers, up to the maximum
+	now = now.Add(autoscaleInterval)
+	assert.True(t, autoscaler.adjust(now))
+	assert.Equal(t, uint32(20), autoscaler.env(env).CommonFlagValues.NumWorkers)
+	assert.Equal(t, uint32(400), autoscaler.env(env).CommonFlagValues.BufferSize)
+
+	autoscaler.observe(20 * time.Second)
+	now = now.Add(autoscaleInterval)
+	assert.True(t, autoscaler.adjust(now))
+	assert.Equal(t, uint32(32), autoscaler.numWorkers)
+
+	// Waiting for ledgers some of the time keeps the workers
+	autoscaler.observe(3 * time.Second)
+	now = now.Add(autoscaleInterval)
+	assert.False(t, autoscaler.adjust(now))
+
+	// A full buffer removes a quarter of the workers
+	autoscaler.observe(0)
+	now = now.Add(autoscaleInterval)
+	assert.True(t, autoscaler.adjust(now))
+	assert.Equal(t, uint32(24), autoscaler.numWorkers)
+
+	// Memory use close to the limit halves the workers, even while waiting for ledgers
+	heapInUse = 900 * 1024 * 1024
+	autoscaler.observe(20 * time.Second)
+	now = now.Add(autoscaleInterval)
+	assert.True(t, autoscaler.adjust(now))
+	assert.Equal(t, uint32(12), autoscaler.numWorkers)
+	assert.Equal(t, uint32(240), autoscaler.env(env).CommonFlagValues.BufferSize)
+}
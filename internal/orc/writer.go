@@ -0,0 +1,477 @@
+// Package orc writes rows to Apache ORC files. Schemas are read from the same parquet struct tags that are used for
+// the parquet output, so every dataset has the same columns in both formats.
+//
+// Files are written uncompressed as a single stripe with the DIRECT (run length encoding v1) column encodings, which
+// every ORC reader supports. Columns can't be null: unset values are written as zero values, like in the parquet output.
+package orc
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+
+	"github.com/stellar/stellar-etl/v2/internal/utils"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+const magic = "ORC"
+
+// timestampBase is the ORC timestamp epoch, 2015-01-01 00:00:00 UTC, in seconds since the unix epoch
+const timestampBase = 1420070400
+
+// maxLiterals is the largest number of values in a run length encoded literal group
+const maxLiterals = 128
+
+// ORC type kinds
+const (
+	kindBoolean   = 0
+	kindInt       = 3
+	kindLong      = 4
+	kindFloat     = 5
+	kindDouble    = 6
+	kindString    = 7
+	kindBinary    = 8
+	kindTimestamp = 9
+	kindList      = 10
+	kindStruct    = 12
+)
+
+// ORC stream kinds
+const (
+	streamData      = 1
+	streamLength    = 2
+	streamSecondary = 5
+)
+
+// column holds the values of a column until the stripe is written
+type column struct {
+	id         uint32
+	kind       uint64
+	name       string
+	fieldIndex int
+	element    *column
+
+	numValues uint64
+	ints      []int64
+	floats    []float64
+	bools     []bool
+	bytes     [][]byte
+	lengths   []int64
+}
+
+// columnKind returns the ORC type kind of a column from its parquet tag
+func columnKind(parquetType, convertedType string) (uint64, error) {
+	switch parquetType {
+	case "BYTE_ARRAY":
+		if convertedType == "UTF8" {
+			return kindString, nil
+		}
+		return kindBinary, nil
+	case "STRING", "MAP":
+		// Maps are written as JSON strings
+		return kindString, nil
+	case "BOOLEAN":
+		return kindBoolean, nil
+	case "INT32":
+		return kindInt, nil
+	case "INT64":
+		if convertedType == "TIMESTAMP_MILLIS" {
+			return kindTimestamp, nil
+		}
+		return kindLong, nil
+	case "FLOAT":
+		return kindFloat, nil
+	case "DOUBLE":
+		return kindDouble, nil
+	}
+
+	return 0, fmt.Errorf("unsupported parquet type %s", parquetType)
+}
+
+// newColumns builds the columns of a parquet schema struct. Columns are numbered in pre-order, starting from the
+// root struct with id 0.
+func newColumns(schemaType reflect.Type) ([]*column, error) {
+	columns := []*column{}
+	nextID := uint32(1)
+	for i := 0; i < schemaType.NumField(); i++ {
+		field := schemaType.Field(i)
+		tag := utils.ParseParquetTag(field.Tag.Get("parquet"))
+		if tag["name"] == "" {
+			continue
+		}
+
+		col := &column{id: nextID, name: tag["name"], fieldIndex: i}
+		nextID++
+
+		elementType, elementConvertedType := tag["type"], tag["convertedtype"]
+		isList := field.Type.Kind() == reflect.Slice && field.Type.Elem().Kind() != reflect.Uint8
+		if tag["type"] == "MAP" && tag["convertedtype"] == "LIST" {
+			elementType, elementConvertedType = tag["valuetype"], tag["valueconvertedtype"]
+			isList = true
+		}
+
+		if isList {
+			elementKind, err := columnKind(elementType, elementConvertedType)
+			if err != nil {
+				return nil, fmt.Errorf("column %s: %v", col.name, err)
+			}
+			col.kind = kindList
+			col.element = &column{id: nextID, kind: elementKind}
+			nextID++
+		} else {
+			kind, err := columnKind(tag["type"], tag["convertedtype"])
+			if err != nil {
+				return nil, fmt.Errorf("column %s: %v", col.name, err)
+			}
+			col.kind = kind
+		}
+
+		columns = append(columns, col)
+	}
+
+	return columns, nil
+}
+
+// toBytes converts a string, byte slice or any other value to bytes. Other values are encoded as JSON.
+func toBytes(value reflect.Value) ([]byte, error) {
+	if value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return []byte{}, nil
+		}
+		value = value.Elem()
+	}
+
+	switch {
+	case value.Kind() == reflect.String:
+		return []byte(value.String()), nil
+	case value.Kind() == reflect.Slice && value.Type().Elem().Kind() == reflect.Uint8:
+		return value.Bytes(), nil
+	}
+
+	return json.Marshal(value.Interface())
+}
+
+// add appends a value to the column
+func (c *column) add(value reflect.Value) error {
+	c.numValues++
+	switch c.kind {
+	case kindBoolean:
+		c.bools = append(c.bools, value.Bool())
+	case kindInt, kindLong, kindTimestamp:
+		switch value.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			c.ints = append(c.ints, value.Int())
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			c.ints = append(c.ints, int64(value.Uint()))
+		default:
+			return fmt.Errorf("expected an integer, got %s", value.Type())
+		}
+	case kindFloat, kindDouble:
+		c.floats = append(c.floats, value.Float())
+	case kindString, kindBinary:
+		b, err := toBytes(value)
+		if err != nil {
+			return err
+		}
+		c.bytes = append(c.bytes, b)
+	case kindList:
+		c.lengths = append(c.lengths, int64(value.Len()))
+		for i := 0; i < value.Len(); i++ {
+			if err := c.element.add(value.Index(i)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// zigzag maps signed integers to unsigned integers so that small negative numbers have short varints
+func zigzag(v int64) uint64 {
+	return uint64((v << 1) ^ (v >> 63))
+}
+
+// encodeIntegers run length encodes integers (RLE v1) as groups of literals
+func encodeIntegers(values []int64, signed bool) []byte {
+	encoded := []byte{}
+	for start := 0; start < len(values); start += maxLiterals {
+		end := min(start+maxLiterals, len(values))
+		encoded = append(encoded, byte(-(end - start)))
+		for _, v := range values[start:end] {
+			if signed {
+				encoded = protowire.AppendVarint(encoded, zigzag(v))
+			} else {
+				encoded = protowire.AppendVarint(encoded, uint64(v))
+			}
+		}
+	}
+	return encoded
+}
+
+// encodeBytes run length encodes bytes as groups of literals
+func encodeBytes(values []byte) []byte {
+	encoded := []byte{}
+	for start := 0; start < len(values); start += maxLiterals {
+		end := min(start+maxLiterals, len(values))
+		encoded = append(encoded, byte(-(end - start)))
+		encoded = append(encoded, values[start:end]...)
+	}
+	return encoded
+}
+
+// encodeBooleans packs booleans into bytes, most significant bit first, and run length encodes the bytes
+func encodeBooleans(values []bool) []byte {
+	packed := make([]byte, (len(values)+7)/8)
+	for i, v := range values {
+		if v {
+			packed[i/8] |= 0x80 >> (i % 8)
+		}
+	}
+	return encodeBytes(packed)
+}
+
+// encodeNanos encodes the nanoseconds of a timestamp, replacing trailing decimal zeros with their count
+func encodeNanos(nanos int64) int64 {
+	if nanos == 0 {
+		return 0
+	}
+	if nanos%100 != 0 {
+		return nanos << 3
+	}
+
+	nanos /= 100
+	trailingZeros := int64(1)
+	for nanos%10 == 0 && trailingZeros < 7 {
+		nanos /= 10
+		trailingZeros++
+	}
+	return nanos<<3 | trailingZeros
+}
+
+// stream is an encoded stream of a column
+type stream struct {
+	kind   uint64
+	column uint32
+	data   []byte
+}
+
+// streams encodes the values of the column and its children
+func (c *column) streams() []stream {
+	var streams []stream
+	switch c.kind {
+	case kindBoolean:
+		streams = append(streams, stream{streamData, c.id, encodeBooleans(c.bools)})
+	case kindInt, kindLong:
+		streams = append(streams, stream{streamData, c.id, encodeIntegers(c.ints, true)})
+	case kindTimestamp:
+		seconds := make([]int64, len(c.ints))
+		nanos := make([]int64, len(c.ints))
+		for i, millis := range c.ints {
+			unixSeconds := millis / 1000
+			if millis%1000 < 0 {
+				unixSeconds--
+			}
+			seconds[i] = unixSeconds - timestampBase
+			nanos[i] = encodeNanos((millis - unixSeconds*1000) * 1000000)
+		}
+		streams = append(streams,
+			stream{streamData, c.id, encodeIntegers(seconds, true)},
+			stream{streamSecondary, c.id, encodeIntegers(nanos, false)},
+		)
+	case kindFloat:
+		data := make([]byte, 0, 4*len(c.floats))
+		for _, f := range c.floats {
+			data = binary.LittleEndian.AppendUint32(data, math.Float32bits(float32(f)))
+		}
+		streams = append(streams, stream{streamData, c.id, data})
+	case kindDouble:
+		data := make([]byte, 0, 8*len(c.floats))
+		for _, f := range c.floats {
+			data = binary.LittleEndian.AppendUint64(data, math.Float64bits(f))
+		}
+		streams = append(streams, stream{streamData, c.id, data})
+	case kindString, kindBinary:
+		data := []byte{}
+		lengths := make([]int64, len(c.bytes))
+		for i, b := range c.bytes {
+			data = append(data, b...)
+			lengths[i] = int64(len(b))
+		}
+		streams = append(streams,
+			stream{streamData, c.id, data},
+			stream{streamLength, c.id, encodeIntegers(lengths, false)},
+		)
+	case kindList:
+		streams = append(streams, stream{streamLength, c.id, encodeIntegers(c.lengths, false)})
+		streams = append(streams, c.element.streams()...)
+	}
+
+	return streams
+}
+
+// appendTypes appends the footer types of the column and its children
+func (c *column) appendTypes(b []byte) []byte {
+	var t []byte
+	t = protowire.AppendTag(t, 1, protowire.VarintType)
+	t = protowire.AppendVarint(t, c.kind)
+	if c.element != nil {
+		t = protowire.AppendTag(t, 2, protowire.VarintType)
+		t = protowire.AppendVarint(t, uint64(c.element.id))
+	}
+	b = protowire.AppendTag(b, 4, protowire.BytesType)
+	b = protowire.AppendBytes(b, t)
+
+	if c.element != nil {
+		b = c.element.appendTypes(b)
+	}
+	return b
+}
+
+// appendStatistics appends the footer statistics of the column and its children. Only value counts are recorded.
+func (c *column) appendStatistics(b []byte) []byte {
+	b = appendValueCount(b, c.numValues)
+	if c.element != nil {
+		b = c.element.appendStatistics(b)
+	}
+	return b
+}
+
+func appendValueCount(b []byte, numValues uint64) []byte {
+	var s []byte
+	s = protowire.AppendTag(s, 1, protowire.VarintType)
+	s = protowire.AppendVarint(s, numValues)
+	s = protowire.AppendTag(s, 10, protowire.VarintType)
+	s = protowire.AppendVarint(s, 0)
+	b = protowire.AppendTag(b, 7, protowire.BytesType)
+	return protowire.AppendBytes(b, s)
+}
+
+// appendVarintField appends a varint field to an encoded protobuf message
+func appendVarintField(b []byte, num protowire.Number, v uint64) []byte {
+	b = protowire.AppendTag(b, num, protowire.VarintType)
+	return protowire.AppendVarint(b, v)
+}
+
+// appendMessageField appends an embedded message field to an encoded protobuf message
+func appendMessageField(b []byte, num protowire.Number, message []byte) []byte {
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendBytes(b, message)
+}
+
+// Write writes the rows to w as an ORC file. parquetSchema is the parquet schema struct of the rows, and every row
+// has to be a value of that struct.
+func Write(w io.Writer, parquetSchema interface{}, rows []interface{}) error {
+	schemaType := reflect.TypeOf(parquetSchema)
+	for schemaType.Kind() == reflect.Pointer {
+		schemaType = schemaType.Elem()
+	}
+	if schemaType.Kind() != reflect.Struct {
+		return fmt.Errorf("parquet schema must be a struct, got %s", schemaType)
+	}
+
+	columns, err := newColumns(schemaType)
+	if err != nil {
+		return err
+	}
+
+	for i, row := range rows {
+		rowValue := reflect.ValueOf(row)
+		if rowValue.Type() != schemaType {
+			return fmt.Errorf("row %d is a %s, expected a %s", i, rowValue.Type(), schemaType)
+		}
+
+		for _, col := range columns {
+			if err := col.add(rowValue.Field(col.fieldIndex)); err != nil {
+				return fmt.Errorf("row %d, column %s: %v", i, col.name, err)
+			}
+		}
+	}
+
+	file := []byte(magic)
+	var stripeInformation []byte
+	if len(rows) > 0 {
+		var data, stripeFooter []byte
+		for _, col := range columns {
+			for _, s := range col.streams() {
+				data = append(data, s.data...)
+
+				var streamMessage []byte
+				streamMessage = appendVarintField(streamMessage, 1, s.kind)
+				streamMessage = appendVarintField(streamMessage, 2, uint64(s.column))
+				streamMessage = appendVarintField(streamMessage, 3, uint64(len(s.data)))
+				stripeFooter = appendMessageField(stripeFooter, 1, streamMessage)
+			}
+		}
+
+		// Every column, including the root struct, uses the DIRECT encoding
+		numColumns := 1
+		for _, col := range columns {
+			numColumns++
+			if col.element != nil {
+				numColumns++
+			}
+		}
+		for i := 0; i < numColumns; i++ {
+			stripeFooter = appendMessageField(stripeFooter, 2, appendVarintField(nil, 1, 0))
+		}
+		stripeFooter = protowire.AppendTag(stripeFooter, 3, protowire.BytesType)
+		stripeFooter = protowire.AppendString(stripeFooter, "UTC")
+
+		stripeInformation = appendVarintField(stripeInformation, 1, uint64(len(file)))
+		stripeInformation = appendVarintField(stripeInformation, 2, 0)
+		stripeInformation = appendVarintField(stripeInformation, 3, uint64(len(data)))
+		stripeInformation = appendVarintField(stripeInformation, 4, uint64(len(stripeFooter)))
+		stripeInformation = appendVarintField(stripeInformation, 5, uint64(len(rows)))
+
+		file = append(file, data...)
+		file = append(file, stripeFooter...)
+	}
+
+	var footer []byte
+	footer = appendVarintField(footer, 1, uint64(len(magic)))
+	footer = appendVarintField(footer, 2, uint64(len(file)))
+	if stripeInformation != nil {
+		footer = appendMessageField(footer, 3, stripeInformation)
+	}
+
+	var rootType []byte
+	rootType = appendVarintField(rootType, 1, kindStruct)
+	for _, col := range columns {
+		rootType = appendVarintField(rootType, 2, uint64(col.id))
+	}
+	for _, col := range columns {
+		rootType = protowire.AppendTag(rootType, 3, protowire.BytesType)
+		rootType = protowire.AppendString(rootType, col.name)
+	}
+	footer = appendMessageField(footer, 4, rootType)
+	for _, col := range columns {
+		footer = col.appendTypes(footer)
+	}
+
+	footer = appendVarintField(footer, 6, uint64(len(rows)))
+	footer = appendValueCount(footer, uint64(len(rows)))
+	for _, col := range columns {
+		footer = col.appendStatistics(footer)
+	}
+	footer = appendVarintField(footer, 8, 0)
+
+	var postScript []byte
+	postScript = appendVarintField(postScript, 1, uint64(len(footer)))
+	postScript = appendVarintField(postScript, 2, 0)
+	postScript = appendVarintField(postScript, 4, 0)
+	postScript = appendVarintField(postScript, 4, 12)
+	postScript = appendVarintField(postScript, 5, 0)
+	postScript = appendVarintField(postScript, 6, 1)
+	postScript = protowire.AppendTag(postScript, 8000, protowire.BytesType)
+	postScript = protowire.AppendString(postScript, magic)
+
+	file = append(file, footer...)
+	file = append(file, postScript...)
+	file = append(file, byte(len(postScript)))
+
+	_, err = w.Write(file)
+	return err
+}
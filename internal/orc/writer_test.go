@@ -0,0 +1,384 @@
+package orc
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+type testRow struct {
+	Name     string   `parquet:"name=name, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	Sequence int64    `parquet:"name=ledger_sequence, type=INT64, convertedtype=UINT_64"`
+	ClosedAt int64    `parquet:"name=closed_at, type=INT64, convertedtype=TIMESTAMP_MILLIS"`
+	Amount   float64  `parquet:"name=amount, type=DOUBLE"`
+	Deleted  bool     `parquet:"name=deleted, type=BOOLEAN"`
+	Signers  []string `parquet:"name=signers, type=MAP, convertedtype=LIST, valuetype=BYTE_ARRAY, valueconvertedtype=UTF8"`
+}
+
+// varintFields decodes the varint fields of a protobuf message, keeping the last value of repeated fields
+func varintFields(t *testing.T, message []byte) map[protowire.Number]uint64 {
+	fields := map[protowire.Number]uint64{}
+	for len(message) > 0 {
+		num, typ, n := protowire.ConsumeTag(message)
+		assert.Greater(t, n, 0)
+		message = message[n:]
+
+		if typ == protowire.VarintType {
+			v, n := protowire.ConsumeVarint(message)
+			assert.Greater(t, n, 0)
+			fields[num] = v
+			message = message[n:]
+			continue
+		}
+
+		n = protowire.ConsumeFieldValue(num, typ, message)
+		assert.Greater(t, n, 0)
+		message = message[n:]
+	}
+	return fields
+}
+
+func TestWrite(t *testing.T) {
+	rows := []interface{}{
+		testRow{Name: "a", Sequence: 10, ClosedAt: 1000000, Amount: 1.5, Deleted: true, Signers: []string{"x", "y"}},
+		testRow{Name: "bc", Sequence: 11, ClosedAt: 1001000, Amount: 2.5},
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(t, Write(&buf, new(testRow), rows))
+	file := buf.Bytes()
+
+	assert.Equal(t, []byte("ORC"), file[:3])
+	postScriptLength := int(file[len(file)-1])
+	postScript := file[len(file)-1-postScriptLength : len(file)-1]
+	assert.True(t, bytes.HasSuffix(postScript, []byte("ORC")))
+
+	postScriptFields := varintFields(t, postScript)
+	assert.Equal(t, uint64(0), postScriptFields[2])
+
+	footerLength := int(postScriptFields[1])
+	footer := file[len(file)-1-postScriptLength-footerLength : len(file)-1-postScriptLength]
+	footerFields := varintFields(t, footer)
+	assert.Equal(t, uint64(3), footerFields[1])
+	assert.Equal(t, uint64(len(file)-1-postScriptLength-footerLength), footerFields[2])
+	assert.Equal(t, uint64(2), footerFields[6])
+}
+
+func TestWriteRejectsOtherRowTypes(t *testing.T) {
+	var buf bytes.Buffer
+	err := Write(&buf, new(testRow), []interface{}{struct{}{}})
+	assert.Error(t, err)
+}
+
+func TestEncodeIntegers(t *testing.T) {
+	assert.Equal(t, []byte{0xfd, 0x02, 0x01, 0x80, 0x01}, encodeIntegers([]int64{1, -1, 64}, true))
+	assert.Equal(t, []byte{0xfe, 0x01, 0x80, 0x01}, encodeIntegers([]int64{1, 128}, false))
+	assert.Len(t, encodeIntegers(make([]int64, 129), false), 131)
+}
+
+func TestEncodeBooleans(t *testing.T) {
+	assert.Equal(t, []byte{0xfe, 0xa0, 0x80}, encodeBooleans([]bool{true, false, true, false, false, false, false, false, true}))
+}
+
+func TestEncodeNanos(t *testing.T) {
+	assert.Equal(t, int64(0), encodeNanos(0))
+	assert.Equal(t, int64(123<<3), encodeNanos(123))
+	assert.Equal(t, int64(1<<3|5), encodeNanos(1000000))
+	assert.Equal(t, int64(15<<3|1), encodeNanos(1500))
+}
+
+// protoField is a decoded field of a protobuf message
+type protoField struct {
+	num    protowire.Number
+	varint uint64
+	bytes  []byte
+}
+
+// protoFields decodes the fields of a protobuf message in order
+func protoFields(t *testing.T, message []byte) []protoField {
+	fields := []protoField{}
+	for len(message) > 0 {
+		num, typ, n := protowire.ConsumeTag(message)
+		require.Greater(t, n, 0)
+		message = message[n:]
+
+		field := protoField{num: num}
+		switch typ {
+		case protowire.VarintType:
+			field.varint, n = protowire.ConsumeVarint(message)
+		case protowire.BytesType:
+			field.bytes, n = protowire.ConsumeBytes(message)
+		default:
+			n = protowire.ConsumeFieldValue(num, typ, message)
+		}
+		require.Greater(t, n, 0)
+		fields = append(fields, field)
+		message = message[n:]
+	}
+	return fields
+}
+
+// decodeIntegers decodes n run length encoded (RLE v1) integers
+func decodeIntegers(t *testing.T, data []byte, n int, signed bool) []int64 {
+	values := []int64{}
+	for len(values) < n {
+		require.NotEmpty(t, data)
+		header := int8(data[0])
+		data = data[1:]
+
+		if header >= 0 {
+			delta := int64(int8(data[0]))
+			base, k := protowire.ConsumeVarint(data[1:])
+			require.Greater(t, k, 0)
+			data = data[1+k:]
+			for i := int64(0); i < int64(header)+3; i++ {
+				v := int64(base)
+				if signed {
+					v = protowire.DecodeZigZag(base)
+				}
+				values = append(values, v+i*delta)
+			}
+			continue
+		}
+
+		for i := 0; i < -int(header); i++ {
+			v, k := protowire.ConsumeVarint(data)
+			require.Greater(t, k, 0)
+			data = data[k:]
+			if signed {
+				values = append(values, protowire.DecodeZigZag(v))
+			} else {
+				values = append(values, int64(v))
+			}
+		}
+	}
+	return values[:n]
+}
+
+// decodeBooleans decodes n booleans that were packed into run length encoded bytes
+func decodeBooleans(t *testing.T, data []byte, n int) []bool {
+	packed := []byte{}
+	for len(packed)*8 < n {
+		require.NotEmpty(t, data)
+		header := int8(data[0])
+		if header >= 0 {
+			packed = append(packed, bytes.Repeat(data[1:2], int(header)+3)...)
+			data = data[2:]
+		} else {
+			packed = append(packed, data[1:1-int(header)]...)
+			data = data[1-int(header):]
+		}
+	}
+
+	values := make([]bool, n)
+	for i := range values {
+		values[i] = packed[i/8]&(0x80>>(i%8)) != 0
+	}
+	return values
+}
+
+// orcFile is an ORC file decoded by readORC
+type orcFile struct {
+	numRows uint64
+	// columns are the values of the top level columns by name
+	columns map[string][]interface{}
+	// numValues are the number of values in the footer statistics of every column, by column id
+	numValues []uint64
+	hasNull   []bool
+}
+
+// readORC decodes an uncompressed single stripe ORC file following the ORC v1 specification
+func readORC(t *testing.T, file []byte) orcFile {
+	require.Equal(t, []byte(magic), file[:3])
+	postScriptLength := int(file[len(file)-1])
+	postScript := file[len(file)-1-postScriptLength : len(file)-1]
+
+	var footerLength int
+	for _, field := range protoFields(t, postScript) {
+		switch field.num {
+		case 1:
+			footerLength = int(field.varint)
+		case 2:
+			require.Equal(t, uint64(0), field.varint, "compression")
+		case 8000:
+			require.Equal(t, magic, string(field.bytes))
+		}
+	}
+	footer := file[len(file)-1-postScriptLength-footerLength : len(file)-1-postScriptLength]
+
+	result := orcFile{columns: map[string][]interface{}{}}
+	var stripes, types [][]byte
+	for _, field := range protoFields(t, footer) {
+		switch field.num {
+		case 3:
+			stripes = append(stripes, field.bytes)
+		case 4:
+			types = append(types, field.bytes)
+		case 6:
+			result.numRows = field.varint
+		case 7:
+			var numValues uint64
+			hasNull := true
+			for _, statistic := range protoFields(t, field.bytes) {
+				switch statistic.num {
+				case 1:
+					numValues = statistic.varint
+				case 10:
+					hasNull = statistic.varint != 0
+				}
+			}
+			result.numValues = append(result.numValues, numValues)
+			result.hasNull = append(result.hasNull, hasNull)
+		}
+	}
+	require.Len(t, stripes, 1)
+
+	var offset, dataLength, stripeFooterLength uint64
+	for _, field := range protoFields(t, stripes[0]) {
+		switch field.num {
+		case 1:
+			offset = field.varint
+		case 3:
+			dataLength = field.varint
+		case 4:
+			stripeFooterLength = field.varint
+		case 5:
+			require.Equal(t, result.numRows, field.varint)
+		}
+	}
+
+	// The streams are stored one after the other in the order of the stripe footer
+	streams := map[[2]uint64][]byte{}
+	data := file[offset : offset+dataLength]
+	for _, field := range protoFields(t, file[offset+dataLength:offset+dataLength+stripeFooterLength]) {
+		if field.num != 1 {
+			continue
+		}
+
+		var key [2]uint64
+		var length uint64
+		for _, s := range protoFields(t, field.bytes) {
+			switch s.num {
+			case 1:
+				key[1] = s.varint
+			case 2:
+				key[0] = s.varint
+			case 3:
+				length = s.varint
+			}
+		}
+		require.NotEqual(t, uint64(0), key[1], "present streams are never written")
+		streams[key] = data[:length]
+		data = data[length:]
+	}
+	require.Empty(t, data)
+
+	// typeInfo returns the kind and the subtypes of a type
+	typeInfo := func(id uint64) (kind uint64, subtypes []uint64, names []string) {
+		for _, field := range protoFields(t, types[id]) {
+			switch field.num {
+			case 1:
+				kind = field.varint
+			case 2:
+				subtypes = append(subtypes, field.varint)
+			case 3:
+				names = append(names, string(field.bytes))
+			}
+		}
+		return kind, subtypes, names
+	}
+
+	var readColumn func(id uint64, n int) []interface{}
+	readColumn = func(id uint64, n int) []interface{} {
+		kind, subtypes, _ := typeInfo(id)
+		values := make([]interface{}, 0, n)
+		switch kind {
+		case kindBoolean:
+			for _, v := range decodeBooleans(t, streams[[2]uint64{id, streamData}], n) {
+				values = append(values, v)
+			}
+		case kindLong:
+			for _, v := range decodeIntegers(t, streams[[2]uint64{id, streamData}], n, true) {
+				values = append(values, v)
+			}
+		case kindDouble:
+			stream := streams[[2]uint64{id, streamData}]
+			require.Len(t, stream, 8*n)
+			for i := 0; i < n; i++ {
+				values = append(values, math.Float64frombits(binary.LittleEndian.Uint64(stream[8*i:])))
+			}
+		case kindString:
+			stream := streams[[2]uint64{id, streamData}]
+			for _, length := range decodeIntegers(t, streams[[2]uint64{id, streamLength}], n, false) {
+				values = append(values, string(stream[:length]))
+				stream = stream[length:]
+			}
+			require.Empty(t, stream)
+		case kindTimestamp:
+			seconds := decodeIntegers(t, streams[[2]uint64{id, streamData}], n, true)
+			nanos := decodeIntegers(t, streams[[2]uint64{id, streamSecondary}], n, false)
+			for i := range seconds {
+				nano := nanos[i] >> 3
+				for zeros := nanos[i] & 7; zeros > 0; zeros-- {
+					nano *= 10
+				}
+				if nanos[i]&7 != 0 {
+					nano *= 10
+				}
+				values = append(values, (seconds[i]+timestampBase)*1000+nano/1000000)
+			}
+		case kindList:
+			lengths := decodeIntegers(t, streams[[2]uint64{id, streamLength}], n, false)
+			total := 0
+			for _, length := range lengths {
+				total += int(length)
+			}
+			elements := readColumn(subtypes[0], total)
+			for _, length := range lengths {
+				values = append(values, elements[:length])
+				elements = elements[length:]
+			}
+		default:
+			t.Fatalf("unexpected type kind %d", kind)
+		}
+		return values
+	}
+
+	kind, subtypes, names := typeInfo(0)
+	require.Equal(t, uint64(kindStruct), kind)
+	for i, id := range subtypes {
+		result.columns[names[i]] = readColumn(id, int(result.numRows))
+	}
+	return result
+}
+
+func TestWriteRoundTrip(t *testing.T) {
+	rows := []interface{}{
+		testRow{Name: "a", Sequence: 10, ClosedAt: 1000000, Amount: 1.5, Deleted: true, Signers: []string{"x", "y"}},
+		testRow{Name: "bc", Sequence: -11, ClosedAt: 1756928309123, Amount: -2.25},
+		testRow{Name: "", Sequence: 1 << 40, ClosedAt: -1500, Amount: 0, Deleted: true, Signers: []string{"z"}},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, new(testRow), rows))
+	file := readORC(t, buf.Bytes())
+
+	assert.Equal(t, uint64(3), file.numRows)
+	assert.Equal(t, map[string][]interface{}{
+		"name":            {"a", "bc", ""},
+		"ledger_sequence": {int64(10), int64(-11), int64(1 << 40)},
+		"closed_at":       {int64(1000000), int64(1756928309123), int64(-1500)},
+		"amount":          {1.5, -2.25, 0.0},
+		"deleted":         {true, false, true},
+		"signers":         {[]interface{}{"x", "y"}, []interface{}{}, []interface{}{"z"}},
+	}, file.columns)
+
+	// The statistics of the root struct, the six columns and the list elements, in column id order
+	assert.Equal(t, []uint64{3, 3, 3, 3, 3, 3, 3, 3}, file.numValues)
+	assert.Equal(t, []bool{false, false, false, false, false, false, false, false}, file.hasNull)
+}
@@ -74,6 +74,13 @@ func TransformAccount(ledgerChange ingest.Change, header xdr.LedgerHeaderHistory
 	outputThreshMed := int32(accountEntry.ThresholdMedium())
 	outputThreshHigh := int32(accountEntry.ThresholdHigh())
 
+	// The minimum balance is the reserve of the account itself and of every subentry, and the reserves of the entries that
+	// the account sponsors are paid by it instead of the accounts it sponsors. The available balance is what the account
+	// can spend, which is negative if the base reserve was raised above what the account holds.
+	numReserves := 2 + int64(outputNumSubentries) + int64(accountEntry.NumSponsoring()) - int64(accountEntry.NumSponsored())
+	outputMinimumBalance := xdr.Int64(numReserves * int64(header.Header.BaseReserve))
+	outputAvailableBalance := outputBalance - outputMinimumBalance - outputSellingLiabilities
+
 	outputLastModifiedLedger := uint32(ledgerEntry.LastModifiedLedgerSeq)
 
 	closedAt, err := utils.TimePointToUTCTimeStamp(header.Header.ScpValue.CloseTime)
@@ -81,6 +88,11 @@ func TransformAccount(ledgerChange ingest.Change, header xdr.LedgerHeaderHistory
 		return AccountOutput{}, err
 	}
 
+	ledgerKeyHash, ledgerKeyHashBase64, err := utils.LedgerEntryToLedgerKeyHashes(ledgerEntry)
+	if err != nil {
+		return AccountOutput{}, err
+	}
+
 	ledgerSequence := header.Header.LedgerSeq
 
 	transformedAccount := AccountOutput{
@@ -103,10 +115,14 @@ func TransformAccount(ledgerChange ingest.Change, header xdr.LedgerHeaderHistory
 		Sponsor:              ledgerEntrySponsorToNullString(ledgerEntry),
 		NumSponsored:         uint32(accountEntry.NumSponsored()),
 		NumSponsoring:        uint32(accountEntry.NumSponsoring()),
+		MinimumBalance:       utils.ConvertStroopValueToReal(outputMinimumBalance),
+		AvailableBalance:     utils.ConvertStroopValueToReal(outputAvailableBalance),
 		LedgerEntryChange:    uint32(changeType),
 		Deleted:              outputDeleted,
 		ClosedAt:             closedAt,
 		LedgerSequence:       uint32(ledgerSequence),
+		LedgerKeyHash:        ledgerKeyHash,
+		LedgerKeyHashBase64:  ledgerKeyHashBase64,
 	}
 	return transformedAccount, nil
 }
@@ -0,0 +1,51 @@
+package transform
+
+import (
+	"fmt"
+
+	"github.com/stellar/go-stellar-sdk/ingest"
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stellar/stellar-etl/v2/internal/utils"
+)
+
+// TransformAccountOperations converts the participants of every operation in a transaction into (account, operation)
+// pairs, one per account and operation regardless of the account's roles, for building reverse indexes
+func TransformAccountOperations(transaction ingest.LedgerTransaction, ledgerSeq uint32, ledgerCloseMeta xdr.LedgerCloseMeta, networkPassphrase string) ([]AccountOperationOutput, error) {
+	transformedPairs := []AccountOperationOutput{}
+
+	outputCloseTime, err := utils.GetCloseTime(ledgerCloseMeta)
+	if err != nil {
+		return nil, err
+	}
+
+	for opi, op := range transaction.Envelope.Operations() {
+		operation := transactionOperationWrapper{
+			index:          uint32(opi),
+			transaction:    transaction,
+			operation:      op,
+			ledgerSequence: ledgerSeq,
+			network:        networkPassphrase,
+			ledgerClosed:   outputCloseTime,
+		}
+
+		participants, err := operation.Participants()
+		if err != nil {
+			return nil, fmt.Errorf("for operation %d (operation id=%d): %v", opi, operation.ID(), err)
+		}
+
+		seen := map[string]bool{}
+		for _, p := range participants {
+			if seen[p.address] {
+				continue
+			}
+			seen[p.address] = true
+
+			transformedPairs = append(transformedPairs, AccountOperationOutput{
+				AccountID:   p.address,
+				OperationID: operation.ID(),
+			})
+		}
+	}
+
+	return transformedPairs, nil
+}
@@ -0,0 +1,39 @@
+package transform
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+func TestTransformAccountOperations(t *testing.T) {
+	lcm := xdr.LedgerCloseMeta{
+		V: 1,
+		V1: &xdr.LedgerCloseMetaV1{
+			LedgerHeader: xdr.LedgerHeaderHistoryEntry{
+				Header: xdr.LedgerHeader{
+					LedgerSeq: 30521816,
+					ScpValue: xdr.StellarValue{
+						CloseTime: 1594272522,
+					},
+				},
+			},
+		},
+	}
+
+	// The payment is sent back to its own source through a muxed account, which is a single pair
+	transaction := makeClassicParticipantsTestInput()
+	payment := transaction.Envelope.V1.Tx.Operations[0].Body.PaymentOp
+	payment.Destination.Med25519.Ed25519 = *testAccount1ID.Ed25519
+
+	actualOutput, actualError := TransformAccountOperations(transaction, 30521816, lcm, "")
+	assert.NoError(t, actualError)
+	assert.Equal(t, []AccountOperationOutput{
+		{AccountID: testAccount1Address, OperationID: 131090201534533633},
+		{AccountID: testAccount1Address, OperationID: 131090201534533634},
+		{AccountID: testAccount4Address, OperationID: 131090201534533634},
+		{AccountID: testAccount3Address, OperationID: 131090201534533634},
+	}, actualOutput)
+}
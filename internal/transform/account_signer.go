@@ -29,6 +29,11 @@ func TransformSigners(ledgerChange ingest.Change, header xdr.LedgerHeaderHistory
 		return signers, err
 	}
 
+	ledgerKeyHash, ledgerKeyHashBase64, err := utils.LedgerEntryToLedgerKeyHashes(ledgerEntry)
+	if err != nil {
+		return signers, err
+	}
+
 	ledgerSequence := header.Header.LedgerSeq
 
 	sponsors := accountEntry.SponsorPerSigner()
@@ -39,15 +44,17 @@ func TransformSigners(ledgerChange ingest.Change, header xdr.LedgerHeaderHistory
 		}
 
 		signers = append(signers, AccountSignerOutput{
-			AccountID:          accountEntry.AccountId.Address(),
-			Signer:             signer,
-			Weight:             weight,
-			Sponsor:            sponsor,
-			LastModifiedLedger: outputLastModifiedLedger,
-			LedgerEntryChange:  uint32(changeType),
-			Deleted:            outputDeleted,
-			ClosedAt:           closedAt,
-			LedgerSequence:     uint32(ledgerSequence),
+			AccountID:           accountEntry.AccountId.Address(),
+			Signer:              signer,
+			Weight:              weight,
+			Sponsor:             sponsor,
+			LastModifiedLedger:  outputLastModifiedLedger,
+			LedgerEntryChange:   uint32(changeType),
+			Deleted:             outputDeleted,
+			ClosedAt:            closedAt,
+			LedgerSequence:      uint32(ledgerSequence),
+			LedgerKeyHash:       ledgerKeyHash,
+			LedgerKeyHashBase64: ledgerKeyHashBase64,
 		})
 	}
 	sort.Slice(signers, func(a, b int) bool { return signers[a].Weight < signers[b].Weight })
@@ -171,35 +171,41 @@ func makeSignersTestOutput(changeType xdr.LedgerEntryChangeType) ([]AccountSigne
 
 	return []AccountSignerOutput{
 		{
-			AccountID:          testAccount1ID.Address(),
-			Signer:             "GCEODJVUUVYVFD5KT4TOEDTMXQ76OPFOQC2EMYYMLPXQCUVPOB6XRWPQ",
-			Weight:             2.0,
-			Sponsor:            null.String{},
-			LastModifiedLedger: 30705278,
-			LedgerEntryChange:  ledgerEntryChange,
-			Deleted:            deleted,
-			LedgerSequence:     10,
-			ClosedAt:           time.Date(1970, time.January, 1, 0, 16, 40, 0, time.UTC),
+			AccountID:           testAccount1ID.Address(),
+			Signer:              "GCEODJVUUVYVFD5KT4TOEDTMXQ76OPFOQC2EMYYMLPXQCUVPOB6XRWPQ",
+			Weight:              2.0,
+			Sponsor:             null.String{},
+			LastModifiedLedger:  30705278,
+			LedgerEntryChange:   ledgerEntryChange,
+			Deleted:             deleted,
+			LedgerSequence:      10,
+			LedgerKeyHash:       "1ea006f77302989af1e10422a149ffce45b4c11c57564f0e921622e45cb66335",
+			LedgerKeyHashBase64: "AAAAAAAAAACI4aa0pXFSj6qfJuIObLw/5zyugLRGYwxb7wFSr3B9eA==",
+			ClosedAt:            time.Date(1970, time.January, 1, 0, 16, 40, 0, time.UTC),
 		}, {
-			AccountID:          testAccount1ID.Address(),
-			Signer:             "GACAKBQAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAB3BQ",
-			Weight:             10.0,
-			Sponsor:            null.StringFrom("GBADGWKHSUFOC4C7E3KXKINZSRX5KPHUWHH67UGJU77LEORGVLQ3BN3B"),
-			LastModifiedLedger: 30705278,
-			LedgerEntryChange:  ledgerEntryChange,
-			Deleted:            deleted,
-			LedgerSequence:     10,
-			ClosedAt:           time.Date(1970, time.January, 1, 0, 16, 40, 0, time.UTC),
+			AccountID:           testAccount1ID.Address(),
+			Signer:              "GACAKBQAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAB3BQ",
+			Weight:              10.0,
+			Sponsor:             null.StringFrom("GBADGWKHSUFOC4C7E3KXKINZSRX5KPHUWHH67UGJU77LEORGVLQ3BN3B"),
+			LastModifiedLedger:  30705278,
+			LedgerEntryChange:   ledgerEntryChange,
+			Deleted:             deleted,
+			LedgerSequence:      10,
+			LedgerKeyHash:       "1ea006f77302989af1e10422a149ffce45b4c11c57564f0e921622e45cb66335",
+			LedgerKeyHashBase64: "AAAAAAAAAACI4aa0pXFSj6qfJuIObLw/5zyugLRGYwxb7wFSr3B9eA==",
+			ClosedAt:            time.Date(1970, time.January, 1, 0, 16, 40, 0, time.UTC),
 		}, {
-			AccountID:          testAccount1ID.Address(),
-			Signer:             "GAFAWDAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAABNDC",
-			Weight:             20.0,
-			Sponsor:            null.String{},
-			LastModifiedLedger: 30705278,
-			LedgerEntryChange:  ledgerEntryChange,
-			Deleted:            deleted,
-			LedgerSequence:     10,
-			ClosedAt:           time.Date(1970, time.January, 1, 0, 16, 40, 0, time.UTC),
+			AccountID:           testAccount1ID.Address(),
+			Signer:              "GAFAWDAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAABNDC",
+			Weight:              20.0,
+			Sponsor:             null.String{},
+			LastModifiedLedger:  30705278,
+			LedgerEntryChange:   ledgerEntryChange,
+			Deleted:             deleted,
+			LedgerSequence:      10,
+			LedgerKeyHash:       "1ea006f77302989af1e10422a149ffce45b4c11c57564f0e921622e45cb66335",
+			LedgerKeyHashBase64: "AAAAAAAAAACI4aa0pXFSj6qfJuIObLw/5zyugLRGYwxb7wFSr3B9eA==",
+			ClosedAt:            time.Date(1970, time.January, 1, 0, 16, 40, 0, time.UTC),
 		},
 	}, nil
 }
@@ -101,7 +101,8 @@ func TestTransformAccount(t *testing.T) {
 				ScpValue: xdr.StellarValue{
 					CloseTime: 1000,
 				},
-				LedgerSeq: 10,
+				LedgerSeq:   10,
+				BaseReserve: 5000,
 			},
 		}
 		actualOutput, actualError := TransformAccount(test.input.ledgerChange, header)
@@ -110,6 +111,68 @@ func TestTransformAccount(t *testing.T) {
 	}
 }
 
+func TestTransformAccountMinimumBalance(t *testing.T) {
+	type sponsorship struct {
+		numSponsoring uint32
+		numSponsored  uint32
+	}
+	tests := []struct {
+		name                 string
+		balance              xdr.Int64
+		sponsorship          *sponsorship
+		wantMinimumBalance   float64
+		wantAvailableBalance float64
+	}{
+		// 2 base reserves for the account and 1 for each of its 3 subentries
+		{"no sponsorships", 100000000, nil, 2.5, 7.5},
+		// The reserves of the 2 entries the account sponsors are paid by it
+		{"sponsoring", 100000000, &sponsorship{numSponsoring: 2}, 3.5, 6.5},
+		// The reserves of the 2 entries sponsored for the account are paid by their sponsor
+		{"sponsored", 100000000, &sponsorship{numSponsored: 2}, 1.5, 8.5},
+		{"sponsoring and sponsored", 100000000, &sponsorship{numSponsoring: 4, numSponsored: 1}, 4, 6},
+		// An account sponsored for itself and all of its subentries has no minimum balance
+		{"fully sponsored", 100000000, &sponsorship{numSponsored: 5}, 0, 10},
+		// The available balance is negative if the reserves are above the balance
+		{"below the reserve", 10000000, &sponsorship{numSponsoring: 1}, 3, -2},
+	}
+
+	header := xdr.LedgerHeaderHistoryEntry{
+		Header: xdr.LedgerHeader{
+			LedgerSeq:   10,
+			BaseReserve: 5000000,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			accountEntry := xdr.AccountEntry{
+				AccountId:     testAccount1ID,
+				Balance:       test.balance,
+				NumSubEntries: 3,
+				Thresholds:    xdr.Thresholds([4]byte{1, 0, 0, 0}),
+			}
+			if test.sponsorship != nil {
+				accountEntry.Ext = xdr.AccountEntryExt{
+					V: 1,
+					V1: &xdr.AccountEntryExtensionV1{
+						Ext: xdr.AccountEntryExtensionV1Ext{
+							V: 2,
+							V2: &xdr.AccountEntryExtensionV2{
+								NumSponsoring: xdr.Uint32(test.sponsorship.numSponsoring),
+								NumSponsored:  xdr.Uint32(test.sponsorship.numSponsored),
+							},
+						},
+					},
+				}
+			}
+
+			output, err := TransformAccount(wrapAccountEntry(accountEntry, 10), header)
+			assert.NoError(t, err)
+			assert.Equal(t, test.wantMinimumBalance, output.MinimumBalance)
+			assert.Equal(t, test.wantAvailableBalance, output.AvailableBalance)
+		})
+	}
+}
+
 func wrapAccountEntry(accountEntry xdr.AccountEntry, lastModified int) ingest.Change {
 	return ingest.Change{
 		ChangeType: xdr.LedgerEntryChangeTypeLedgerEntryRemoved,
@@ -190,10 +253,14 @@ func makeAccountTestOutput() AccountOutput {
 		Sponsor:              null.StringFrom(testAccount3Address),
 		NumSponsored:         3,
 		NumSponsoring:        1,
+		MinimumBalance:       0.0705,
+		AvailableBalance:     1.0253479,
 		LastModifiedLedger:   30705278,
 		LedgerEntryChange:    2,
 		Deleted:              true,
 		LedgerSequence:       10,
+		LedgerKeyHash:        "1ea006f77302989af1e10422a149ffce45b4c11c57564f0e921622e45cb66335",
+		LedgerKeyHashBase64:  "AAAAAAAAAACI4aa0pXFSj6qfJuIObLw/5zyugLRGYwxb7wFSr3B9eA==",
 		ClosedAt:             time.Date(1970, time.January, 1, 0, 16, 40, 0, time.UTC),
 	}
 }
@@ -3,7 +3,6 @@ package transform
 import (
 	"fmt"
 
-	farm "github.com/dgryski/go-farm"
 	"github.com/stellar/stellar-etl/v2/internal/toid"
 	"github.com/stellar/stellar-etl/v2/internal/utils"
 
@@ -59,7 +58,7 @@ func transformSingleAsset(asset xdr.Asset) (AssetOutput, error) {
 		return AssetOutput{}, fmt.Errorf("could not extract asset from this operation")
 	}
 
-	farmAssetID := FarmHashAsset(outputAssetCode, outputAssetIssuer, outputAssetType)
+	farmAssetID := utils.FarmHashAsset(outputAssetCode, outputAssetIssuer, outputAssetType)
 
 	return AssetOutput{
 		AssetCode:   outputAssetCode,
@@ -68,10 +67,3 @@ func transformSingleAsset(asset xdr.Asset) (AssetOutput, error) {
 		AssetID:     farmAssetID,
 	}, nil
 }
-
-func FarmHashAsset(assetCode, assetIssuer, assetType string) int64 {
-	asset := fmt.Sprintf("%s%s%s", assetCode, assetIssuer, assetType)
-	hash := farm.Fingerprint64([]byte(asset))
-
-	return int64(hash)
-}
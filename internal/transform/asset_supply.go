@@ -0,0 +1,87 @@
+package transform
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// Token transfer event topics that change the supply of an asset
+const (
+	supplyEventMint     = "mint"
+	supplyEventBurn     = "burn"
+	supplyEventClawback = "clawback"
+)
+
+// TransformAssetSupply aggregates the mints, burns and clawbacks among the token transfers of a ledger into a supply
+// change per asset, in the order the assets first change supply within the ledger. Payments from and to the issuer of
+// a Stellar asset are reported as mints and burns by the token transfer processor, so they are included. supplies holds
+// the running circulating supply of every asset by contract id and is updated with the changes of the ledger.
+func TransformAssetSupply(transfers []TokenTransferOutput, supplies map[string]*big.Int) ([]AssetSupplyOutput, error) {
+	type supplyChange struct {
+		transfer   TokenTransferOutput
+		minted     *big.Int
+		burned     *big.Int
+		clawedBack *big.Int
+	}
+
+	var order []string
+	changes := map[string]*supplyChange{}
+	for _, transfer := range transfers {
+		if transfer.EventTopic != supplyEventMint && transfer.EventTopic != supplyEventBurn && transfer.EventTopic != supplyEventClawback {
+			continue
+		}
+
+		amount, ok := new(big.Int).SetString(transfer.AmountRaw, 10)
+		if !ok {
+			return []AssetSupplyOutput{}, fmt.Errorf("could not parse %s amount %q of transaction %s", transfer.EventTopic, transfer.AmountRaw, transfer.TransactionHash)
+		}
+
+		change, exists := changes[transfer.ContractID]
+		if !exists {
+			change = &supplyChange{transfer: transfer, minted: new(big.Int), burned: new(big.Int), clawedBack: new(big.Int)}
+			changes[transfer.ContractID] = change
+			order = append(order, transfer.ContractID)
+		}
+
+		switch transfer.EventTopic {
+		case supplyEventMint:
+			change.minted.Add(change.minted, amount)
+		case supplyEventBurn:
+			change.burned.Add(change.burned, amount)
+		case supplyEventClawback:
+			change.clawedBack.Add(change.clawedBack, amount)
+		}
+	}
+
+	transformedSupplies := make([]AssetSupplyOutput, 0, len(order))
+	for _, contractID := range order {
+		change := changes[contractID]
+		delta := new(big.Int).Sub(change.minted, change.burned)
+		delta.Sub(delta, change.clawedBack)
+
+		supply, exists := supplies[contractID]
+		if !exists {
+			supply = new(big.Int)
+			supplies[contractID] = supply
+		}
+		supply.Add(supply, delta)
+
+		transformedSupplies = append(transformedSupplies, AssetSupplyOutput{
+			LedgerSequence:    change.transfer.LedgerSequence,
+			ClosedAt:          change.transfer.ClosedAt,
+			Asset:             change.transfer.Asset,
+			AssetType:         change.transfer.AssetType,
+			AssetCode:         change.transfer.AssetCode,
+			AssetIssuer:       change.transfer.AssetIssuer,
+			AssetID:           change.transfer.AssetID,
+			ContractID:        contractID,
+			Minted:            change.minted.String(),
+			Burned:            change.burned.String(),
+			ClawedBack:        change.clawedBack.String(),
+			SupplyDelta:       delta.String(),
+			CirculatingSupply: supply.String(),
+		})
+	}
+
+	return transformedSupplies, nil
+}
@@ -0,0 +1,85 @@
+package transform
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/guregu/null"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransformAssetSupply(t *testing.T) {
+	closedAt := time.Unix(1000, 0).UTC()
+	issuedTransfer := func(topic, amount string) TokenTransferOutput {
+		return TokenTransferOutput{
+			TransactionHash: "txhash",
+			EventTopic:      topic,
+			Asset:           "credit_alphanum4:abc:def",
+			AssetType:       "credit_alphanum4",
+			AssetCode:       null.StringFrom("abc"),
+			AssetIssuer:     null.StringFrom("def"),
+			AssetID:         null.IntFrom(-3107237761707155200),
+			AmountRaw:       amount,
+			ContractID:      "issuedcontract",
+			LedgerSequence:  10,
+			ClosedAt:        closedAt,
+		}
+	}
+	tokenTransfer := func(topic, amount string) TokenTransferOutput {
+		return TokenTransferOutput{
+			TransactionHash: "txhash",
+			EventTopic:      topic,
+			AmountRaw:       amount,
+			ContractID:      "tokencontract",
+			LedgerSequence:  10,
+			ClosedAt:        closedAt,
+		}
+	}
+
+	supplies := map[string]*big.Int{"issuedcontract": big.NewInt(1000)}
+	transfers := []TokenTransferOutput{
+		tokenTransfer("mint", "170141183460469231731687303715884105727"),
+		issuedTransfer("transfer", "400"),
+		issuedTransfer("mint", "500"),
+		issuedTransfer("fee", "100"),
+		issuedTransfer("burn", "200"),
+		issuedTransfer("clawback", "50"),
+		tokenTransfer("burn", "27"),
+	}
+
+	actualOutput, err := TransformAssetSupply(transfers, supplies)
+	assert.NoError(t, err)
+	assert.Equal(t, []AssetSupplyOutput{
+		{
+			LedgerSequence:    10,
+			ClosedAt:          closedAt,
+			ContractID:        "tokencontract",
+			Minted:            "170141183460469231731687303715884105727",
+			Burned:            "27",
+			ClawedBack:        "0",
+			SupplyDelta:       "170141183460469231731687303715884105700",
+			CirculatingSupply: "170141183460469231731687303715884105700",
+		},
+		{
+			LedgerSequence:    10,
+			ClosedAt:          closedAt,
+			Asset:             "credit_alphanum4:abc:def",
+			AssetType:         "credit_alphanum4",
+			AssetCode:         null.StringFrom("abc"),
+			AssetIssuer:       null.StringFrom("def"),
+			AssetID:           null.IntFrom(-3107237761707155200),
+			ContractID:        "issuedcontract",
+			Minted:            "500",
+			Burned:            "200",
+			ClawedBack:        "50",
+			SupplyDelta:       "250",
+			CirculatingSupply: "1250",
+		},
+	}, actualOutput)
+	assert.Equal(t, "1250", supplies["issuedcontract"].String())
+
+	_, err = TransformAssetSupply([]TokenTransferOutput{issuedTransfer("mint", "not an amount")}, supplies)
+	assert.Error(t, err)
+	assert.Equal(t, "1250", supplies["issuedcontract"].String())
+}
@@ -54,24 +54,31 @@ func TransformClaimableBalance(ledgerChange ingest.Change, header xdr.LedgerHead
 		return ClaimableBalanceOutput{}, err
 	}
 
+	ledgerKeyHash, ledgerKeyHashBase64, err := utils.LedgerEntryToLedgerKeyHashes(ledgerEntry)
+	if err != nil {
+		return ClaimableBalanceOutput{}, err
+	}
+
 	ledgerSequence := header.Header.LedgerSeq
 
 	transformed := ClaimableBalanceOutput{
-		BalanceID:          balanceID,
-		AssetCode:          outputAsset.AssetCode,
-		AssetIssuer:        outputAsset.AssetIssuer,
-		AssetType:          outputAsset.AssetType,
-		AssetID:            outputAsset.AssetID,
-		Claimants:          outputClaimants,
-		AssetAmount:        float64(outputAmount) / 1.0e7,
-		Sponsor:            ledgerEntrySponsorToNullString(ledgerEntry),
-		LastModifiedLedger: outputLastModifiedLedger,
-		LedgerEntryChange:  uint32(changeType),
-		Flags:              outputFlags,
-		Deleted:            outputDeleted,
-		ClosedAt:           closedAt,
-		LedgerSequence:     uint32(ledgerSequence),
-		BalanceIDStrkey:    balanceIDStrkey,
+		BalanceID:           balanceID,
+		AssetCode:           outputAsset.AssetCode,
+		AssetIssuer:         outputAsset.AssetIssuer,
+		AssetType:           outputAsset.AssetType,
+		AssetID:             outputAsset.AssetID,
+		Claimants:           outputClaimants,
+		AssetAmount:         float64(outputAmount) / 1.0e7,
+		Sponsor:             ledgerEntrySponsorToNullString(ledgerEntry),
+		LastModifiedLedger:  outputLastModifiedLedger,
+		LedgerEntryChange:   uint32(changeType),
+		Flags:               outputFlags,
+		Deleted:             outputDeleted,
+		ClosedAt:            closedAt,
+		LedgerSequence:      uint32(ledgerSequence),
+		LedgerKeyHash:       ledgerKeyHash,
+		LedgerKeyHashBase64: ledgerKeyHashBase64,
+		BalanceIDStrkey:     balanceIDStrkey,
 	}
 	return transformed, nil
 }
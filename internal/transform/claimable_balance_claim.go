@@ -0,0 +1,174 @@
+package transform
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/guregu/null"
+	"github.com/stellar/go-stellar-sdk/ingest"
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stellar/stellar-etl/v2/internal/toid"
+	"github.com/stellar/stellar-etl/v2/internal/utils"
+)
+
+const (
+	// claimableBalanceClaimed is a claimable balance that was claimed by one of its claimants
+	claimableBalanceClaimed = "claim"
+	// claimableBalanceClawedBack is a claimable balance that was clawed back by the issuer of its asset
+	claimableBalanceClawedBack = "clawback"
+)
+
+// ClaimableBalanceCreation is the creation of a claimable balance, which is paired with the claim or clawback of the
+// balance by TransformClaimableBalanceClaim
+type ClaimableBalanceCreation struct {
+	BalanceID       string
+	CreatedBy       string
+	TransactionHash string
+	LedgerSequence  uint32
+	ClosedAt        time.Time
+}
+
+// TransformClaimableBalanceCreation returns the creation of the claimable balance of a successful create claimable
+// balance operation. Other operations don't create a balance.
+func TransformClaimableBalanceCreation(operation xdr.Operation, operationIndex int32, transaction ingest.LedgerTransaction, ledgerSeq int32, ledgerCloseMeta xdr.LedgerCloseMeta) (ClaimableBalanceCreation, bool, error) {
+	if operation.Body.Type != xdr.OperationTypeCreateClaimableBalance || !transaction.Result.Successful() {
+		return ClaimableBalanceCreation{}, false, nil
+	}
+
+	outputOperationID := toid.New(ledgerSeq, int32(transaction.Index), operationIndex+1).ToInt64()
+	operationResults, ok := transaction.Result.OperationResults()
+	if !ok || int(operationIndex) >= len(operationResults) {
+		return ClaimableBalanceCreation{}, false, fmt.Errorf("could not get the result of operation %d (operation id=%d)", operationIndex, outputOperationID)
+	}
+
+	result, ok := operationResults[operationIndex].MustTr().GetCreateClaimableBalanceResult()
+	if !ok {
+		return ClaimableBalanceCreation{}, false, fmt.Errorf("could not get the create claimable balance result of operation %d (operation id=%d)", operationIndex, outputOperationID)
+	}
+	resultBalanceID, ok := result.GetBalanceId()
+	if !ok {
+		return ClaimableBalanceCreation{}, false, fmt.Errorf("could not get the balance id of operation %d (operation id=%d)", operationIndex, outputOperationID)
+	}
+	balanceID, err := xdr.MarshalHex(resultBalanceID)
+	if err != nil {
+		return ClaimableBalanceCreation{}, false, fmt.Errorf("invalid balanceId in result of operation %d (operation id=%d)", operationIndex, outputOperationID)
+	}
+
+	closedAt, err := utils.GetCloseTime(ledgerCloseMeta)
+	if err != nil {
+		return ClaimableBalanceCreation{}, false, err
+	}
+
+	sourceAccount := getOperationSourceAccount(operation, transaction)
+	return ClaimableBalanceCreation{
+		BalanceID:       balanceID,
+		CreatedBy:       sourceAccount.ToAccountId().Address(),
+		TransactionHash: utils.HashToHexString(transaction.Result.TransactionHash),
+		LedgerSequence:  uint32(ledgerSeq),
+		ClosedAt:        closedAt,
+	}, true, nil
+}
+
+// TransformClaimableBalanceClaim converts a successful claim or clawback of a claimable balance into a lifecycle record
+// that pairs it with the creation of the balance, with the time it took to be claimed. creations has the creations
+// seen so far, keyed by balance id.
+//
+// A balance created before the creations that were seen has no creation time. Its creation ledger is the last ledger
+// that modified the balance, since balances only change when their sponsorship is revoked, and it was created by its
+// sponsor, which is the account that created it unless the sponsorship was transferred.
+func TransformClaimableBalanceClaim(operation xdr.Operation, operationIndex int32, transaction ingest.LedgerTransaction, ledgerSeq int32, ledgerCloseMeta xdr.LedgerCloseMeta, creations map[string]ClaimableBalanceCreation) (ClaimableBalanceClaimOutput, bool, error) {
+	if !transaction.Result.Successful() {
+		return ClaimableBalanceClaimOutput{}, false, nil
+	}
+
+	var balanceID xdr.ClaimableBalanceId
+	var claimType string
+	switch operation.Body.Type {
+	case xdr.OperationTypeClaimClaimableBalance:
+		balanceID = operation.Body.MustClaimClaimableBalanceOp().BalanceId
+		claimType = claimableBalanceClaimed
+	case xdr.OperationTypeClawbackClaimableBalance:
+		balanceID = operation.Body.MustClawbackClaimableBalanceOp().BalanceId
+		claimType = claimableBalanceClawedBack
+	default:
+		return ClaimableBalanceClaimOutput{}, false, nil
+	}
+
+	outputTransactionID := toid.New(ledgerSeq, int32(transaction.Index), 0).ToInt64()
+	outputOperationID := toid.New(ledgerSeq, int32(transaction.Index), operationIndex+1).ToInt64()
+	outputBalanceID, err := xdr.MarshalHex(balanceID)
+	if err != nil {
+		return ClaimableBalanceClaimOutput{}, false, fmt.Errorf("invalid balanceId in operation %d (operation id=%d)", operationIndex, outputOperationID)
+	}
+
+	balanceEntry, err := removedClaimableBalance(transaction, operationIndex, outputBalanceID)
+	if err != nil {
+		return ClaimableBalanceClaimOutput{}, false, fmt.Errorf("for operation %d (operation id=%d): %v", operationIndex, outputOperationID, err)
+	}
+	balance := balanceEntry.Data.MustClaimableBalance()
+
+	outputAsset, err := transformSingleAsset(balance.Asset)
+	if err != nil {
+		return ClaimableBalanceClaimOutput{}, false, err
+	}
+
+	closedAt, err := utils.GetCloseTime(ledgerCloseMeta)
+	if err != nil {
+		return ClaimableBalanceClaimOutput{}, false, err
+	}
+
+	sourceAccount := getOperationSourceAccount(operation, transaction)
+	claim := ClaimableBalanceClaimOutput{
+		BalanceID:             outputBalanceID,
+		BalanceIDStrkey:       balanceID.MustEncodeToStrkey(),
+		AssetCode:             outputAsset.AssetCode,
+		AssetIssuer:           outputAsset.AssetIssuer,
+		AssetType:             outputAsset.AssetType,
+		AssetID:               outputAsset.AssetID,
+		AssetAmount:           utils.ConvertStroopValueToReal(balance.Amount),
+		CreatedBy:             ledgerEntrySponsorToNullString(balanceEntry),
+		CreatedLedgerSequence: uint32(balanceEntry.LastModifiedLedgerSeq),
+		ClaimType:             claimType,
+		ClaimedBy:             sourceAccount.ToAccountId().Address(),
+		ElapsedLedgers:        int64(ledgerSeq) - int64(balanceEntry.LastModifiedLedgerSeq),
+		TransactionHash:       utils.HashToHexString(transaction.Result.TransactionHash),
+		TransactionID:         outputTransactionID,
+		OperationID:           outputOperationID,
+		ClosedAt:              closedAt,
+		LedgerSequence:        uint32(ledgerSeq),
+	}
+
+	if creation, ok := creations[outputBalanceID]; ok {
+		claim.CreatedBy = null.StringFrom(creation.CreatedBy)
+		claim.CreatedTransactionHash = null.StringFrom(creation.TransactionHash)
+		claim.CreatedLedgerSequence = creation.LedgerSequence
+		claim.CreatedAt = null.TimeFrom(creation.ClosedAt)
+		claim.ElapsedLedgers = int64(ledgerSeq) - int64(creation.LedgerSequence)
+		claim.ElapsedSeconds = null.IntFrom(int64(closedAt.Sub(creation.ClosedAt).Seconds()))
+	}
+
+	return claim, true, nil
+}
+
+// removedClaimableBalance finds the claimable balance that an operation removed
+func removedClaimableBalance(transaction ingest.LedgerTransaction, operationIndex int32, balanceID string) (xdr.LedgerEntry, error) {
+	changes, err := transaction.GetOperationChanges(uint32(operationIndex))
+	if err != nil {
+		return xdr.LedgerEntry{}, err
+	}
+
+	for _, change := range changes {
+		if change.Type != xdr.LedgerEntryTypeClaimableBalance || change.Pre == nil || change.Post != nil {
+			continue
+		}
+		preBalanceID, err := xdr.MarshalHex(change.Pre.Data.MustClaimableBalance().BalanceId)
+		if err != nil {
+			return xdr.LedgerEntry{}, err
+		}
+		if preBalanceID == balanceID {
+			return *change.Pre, nil
+		}
+	}
+
+	return xdr.LedgerEntry{}, fmt.Errorf("change not found for balanceId : %s", balanceID)
+}
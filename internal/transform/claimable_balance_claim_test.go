@@ -0,0 +1,167 @@
+package transform
+
+import (
+	"testing"
+	"time"
+
+	"github.com/guregu/null"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stellar/go-stellar-sdk/ingest"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+func TestTransformClaimableBalanceClaim(t *testing.T) {
+	createOperation := xdr.Operation{
+		Body: xdr.OperationBody{
+			Type: xdr.OperationTypeCreateClaimableBalance,
+			CreateClaimableBalanceOp: &xdr.CreateClaimableBalanceOp{
+				Asset:     nativeAsset,
+				Amount:    25000000,
+				Claimants: []xdr.Claimant{testClaimant},
+			},
+		},
+	}
+	createTransaction := makeClaimableBalanceTestTransaction(createOperation, xdr.OperationResultTr{
+		Type: xdr.OperationTypeCreateClaimableBalance,
+		CreateClaimableBalanceResult: &xdr.CreateClaimableBalanceResult{
+			Code:      xdr.CreateClaimableBalanceResultCodeCreateClaimableBalanceSuccess,
+			BalanceId: &genericClaimableBalance,
+		},
+	}, nil)
+
+	creation, created, err := TransformClaimableBalanceCreation(createOperation, 0, createTransaction, 20, makeClaimableBalanceTestLedgerCloseMeta(20, 1000))
+	assert.NoError(t, err)
+	assert.True(t, created)
+	assert.Equal(t, ClaimableBalanceCreation{
+		BalanceID:       "000000000102030405060708090000000000000000000000000000000000000000000000",
+		CreatedBy:       testAccount1Address,
+		TransactionHash: "0000000000000000000000000000000000000000000000000000000000000000",
+		LedgerSequence:  20,
+		ClosedAt:        time.Date(1970, time.January, 1, 0, 16, 40, 0, time.UTC),
+	}, creation)
+
+	claimOperation := xdr.Operation{
+		SourceAccount: &testAccount2,
+		Body: xdr.OperationBody{
+			Type:                    xdr.OperationTypeClaimClaimableBalance,
+			ClaimClaimableBalanceOp: &xdr.ClaimClaimableBalanceOp{BalanceId: genericClaimableBalance},
+		},
+	}
+	balanceEntry := xdr.LedgerEntry{
+		LastModifiedLedgerSeq: 18,
+		Data: xdr.LedgerEntryData{
+			Type: xdr.LedgerEntryTypeClaimableBalance,
+			ClaimableBalance: &xdr.ClaimableBalanceEntry{
+				BalanceId: genericClaimableBalance,
+				Claimants: []xdr.Claimant{testClaimant},
+				Asset:     nativeAsset,
+				Amount:    25000000,
+			},
+		},
+		Ext: xdr.LedgerEntryExt{
+			V:  1,
+			V1: &xdr.LedgerEntryExtensionV1{SponsoringId: &testAccount3ID},
+		},
+	}
+	claimTransaction := makeClaimableBalanceTestTransaction(claimOperation, xdr.OperationResultTr{
+		Type: xdr.OperationTypeClaimClaimableBalance,
+		ClaimClaimableBalanceResult: &xdr.ClaimClaimableBalanceResult{
+			Code: xdr.ClaimClaimableBalanceResultCodeClaimClaimableBalanceSuccess,
+		},
+	}, xdr.LedgerEntryChanges{
+		{Type: xdr.LedgerEntryChangeTypeLedgerEntryState, State: &balanceEntry},
+		{Type: xdr.LedgerEntryChangeTypeLedgerEntryRemoved, Removed: &xdr.LedgerKey{
+			Type:             xdr.LedgerEntryTypeClaimableBalance,
+			ClaimableBalance: &xdr.LedgerKeyClaimableBalance{BalanceId: genericClaimableBalance},
+		}},
+	})
+	claimedAt := time.Date(1970, time.January, 1, 1, 0, 0, 0, time.UTC)
+
+	// Without the creation, the creation ledger and creator come from the removed balance
+	expected := ClaimableBalanceClaimOutput{
+		BalanceID:             "000000000102030405060708090000000000000000000000000000000000000000000000",
+		BalanceIDStrkey:       "BAAACAQDAQCQMBYIBEAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAACPGI",
+		AssetType:             "native",
+		AssetID:               -5706705804583548011,
+		AssetAmount:           2.5,
+		CreatedBy:             null.StringFrom(testAccount3Address),
+		CreatedLedgerSequence: 18,
+		ClaimType:             "claim",
+		ClaimedBy:             testAccount2Address,
+		ElapsedLedgers:        12,
+		TransactionHash:       "0000000000000000000000000000000000000000000000000000000000000000",
+		TransactionID:         128849022976,
+		OperationID:           128849022977,
+		ClosedAt:              claimedAt,
+		LedgerSequence:        30,
+	}
+	claim, claimed, err := TransformClaimableBalanceClaim(claimOperation, 0, claimTransaction, 30, makeClaimableBalanceTestLedgerCloseMeta(30, 3600), nil)
+	assert.NoError(t, err)
+	assert.True(t, claimed)
+	assert.Equal(t, expected, claim)
+
+	expected.CreatedBy = null.StringFrom(testAccount1Address)
+	expected.CreatedTransactionHash = null.StringFrom("0000000000000000000000000000000000000000000000000000000000000000")
+	expected.CreatedLedgerSequence = 20
+	expected.CreatedAt = null.TimeFrom(creation.ClosedAt)
+	expected.ElapsedLedgers = 10
+	expected.ElapsedSeconds = null.IntFrom(2600)
+	claim, claimed, err = TransformClaimableBalanceClaim(claimOperation, 0, claimTransaction, 30, makeClaimableBalanceTestLedgerCloseMeta(30, 3600), map[string]ClaimableBalanceCreation{creation.BalanceID: creation})
+	assert.NoError(t, err)
+	assert.True(t, claimed)
+	assert.Equal(t, expected, claim)
+
+	// Other operations neither create nor claim a balance
+	_, created, err = TransformClaimableBalanceCreation(claimOperation, 0, claimTransaction, 30, makeClaimableBalanceTestLedgerCloseMeta(30, 3600))
+	assert.NoError(t, err)
+	assert.False(t, created)
+	_, claimed, err = TransformClaimableBalanceClaim(createOperation, 0, createTransaction, 20, makeClaimableBalanceTestLedgerCloseMeta(20, 1000), nil)
+	assert.NoError(t, err)
+	assert.False(t, claimed)
+}
+
+func makeClaimableBalanceTestLedgerCloseMeta(ledgerSeq uint32, closeTime uint64) xdr.LedgerCloseMeta {
+	return xdr.LedgerCloseMeta{
+		V: 0,
+		V0: &xdr.LedgerCloseMetaV0{
+			LedgerHeader: xdr.LedgerHeaderHistoryEntry{
+				Header: xdr.LedgerHeader{
+					ScpValue:  xdr.StellarValue{CloseTime: xdr.TimePoint(closeTime)},
+					LedgerSeq: xdr.Uint32(ledgerSeq),
+				},
+			},
+		},
+	}
+}
+
+func makeClaimableBalanceTestTransaction(operation xdr.Operation, result xdr.OperationResultTr, changes xdr.LedgerEntryChanges) ingest.LedgerTransaction {
+	return ingest.LedgerTransaction{
+		Index: 1,
+		Envelope: xdr.TransactionEnvelope{
+			Type: xdr.EnvelopeTypeEnvelopeTypeTx,
+			V1: &xdr.TransactionV1Envelope{
+				Tx: xdr.Transaction{
+					SourceAccount: testAccount1,
+					Operations:    []xdr.Operation{operation},
+				},
+			},
+		},
+		Result: xdr.TransactionResultPair{
+			Result: xdr.TransactionResult{
+				Result: xdr.TransactionResultResult{
+					Code: xdr.TransactionResultCodeTxSuccess,
+					Results: &[]xdr.OperationResult{
+						{Code: xdr.OperationResultCodeOpInner, Tr: &result},
+					},
+				},
+			},
+		},
+		UnsafeMeta: xdr.TransactionMeta{
+			V: 1,
+			V1: &xdr.TransactionMetaV1{
+				Operations: []xdr.OperationMeta{{Changes: changes}},
+			},
+		},
+	}
+}
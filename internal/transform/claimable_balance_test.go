@@ -115,18 +115,20 @@ func makeClaimableBalanceTestOutput() ClaimableBalanceOutput {
 				},
 			},
 		},
-		AssetIssuer:        "GBT4YAEGJQ5YSFUMNKX6BPBUOCPNAIOFAVZOF6MIME2CECBMEIUXFZZN",
-		AssetType:          "credit_alphanum12",
-		AssetCode:          "\x01\x02\x03\x04\x05\x06\a\b\t",
-		AssetAmount:        999,
-		AssetID:            -4023078858747574648,
-		Sponsor:            null.StringFrom("GAAQEAYEAUDAOCAJAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAABO3W"),
-		Flags:              10,
-		LastModifiedLedger: 30705278,
-		LedgerEntryChange:  2,
-		Deleted:            true,
-		LedgerSequence:     10,
-		ClosedAt:           time.Date(1970, time.January, 1, 0, 16, 40, 0, time.UTC),
-		BalanceIDStrkey:    "BAAACAQDAQCQMBYIBEAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAACPGI",
+		AssetIssuer:         "GBT4YAEGJQ5YSFUMNKX6BPBUOCPNAIOFAVZOF6MIME2CECBMEIUXFZZN",
+		AssetType:           "credit_alphanum12",
+		AssetCode:           "\x01\x02\x03\x04\x05\x06\a\b\t",
+		AssetAmount:         999,
+		AssetID:             -4023078858747574648,
+		Sponsor:             null.StringFrom("GAAQEAYEAUDAOCAJAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAABO3W"),
+		Flags:               10,
+		LastModifiedLedger:  30705278,
+		LedgerEntryChange:   2,
+		Deleted:             true,
+		LedgerSequence:      10,
+		LedgerKeyHash:       "61b2c7b5bab89ffacd7dfec8e04bcc8ed98b52b38bf45bb6596b762f0ca845e5",
+		LedgerKeyHashBase64: "AAAABAAAAAABAgMEBQYHCAkAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA==",
+		ClosedAt:            time.Date(1970, time.January, 1, 0, 16, 40, 0, time.UTC),
+		BalanceIDStrkey:     "BAAACAQDAQCQMBYIBEAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAACPGI",
 	}
 }
@@ -111,6 +111,11 @@ func TransformConfigSetting(ledgerChange ingest.Change, header xdr.LedgerHeaderH
 		return ConfigSettingOutput{}, err
 	}
 
+	ledgerKeyHash, ledgerKeyHashBase64, err := utils.LedgerEntryToLedgerKeyHashes(ledgerEntry)
+	if err != nil {
+		return ConfigSettingOutput{}, err
+	}
+
 	ledgerSequence := header.Header.LedgerSeq
 
 	transformedConfigSetting := ConfigSettingOutput{
@@ -175,6 +180,8 @@ func TransformConfigSetting(ledgerChange ingest.Change, header xdr.LedgerHeaderH
 		Deleted:                                outputDeleted,
 		ClosedAt:                               closedAt,
 		LedgerSequence:                         uint32(ledgerSequence),
+		LedgerKeyHash:                          ledgerKeyHash,
+		LedgerKeyHashBase64:                    ledgerKeyHashBase64,
 	}
 	return transformedConfigSetting, nil
 }
@@ -0,0 +1,112 @@
+package transform
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/guregu/null"
+	"github.com/stellar/go-stellar-sdk/ingest"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+// emptyConfigSettingValues are the json values of the config setting fields that a setting doesn't set
+var emptyConfigSettingValues = map[string]bool{
+	"0":     true,
+	`""`:    true,
+	"false": true,
+	"null":  true,
+	"[]":    true,
+}
+
+// TransformConfigSettingDiffs expands a config setting ledger change into one record per field whose value changed,
+// with the json encoded values before and after the change, so parameter changes such as fee rates can be read as a diff.
+// When the setting is created or removed, the missing side is null and only the fields that the setting sets are exported.
+func TransformConfigSettingDiffs(ledgerChange ingest.Change, header xdr.LedgerHeaderHistoryEntry) ([]ConfigSettingDiffOutput, error) {
+	configSetting, err := TransformConfigSetting(ledgerChange, header)
+	if err != nil {
+		return []ConfigSettingDiffOutput{}, err
+	}
+
+	oldFields, err := configSettingEntryFields(ledgerChange.Pre, header)
+	if err != nil {
+		return []ConfigSettingDiffOutput{}, fmt.Errorf("could not read previous config setting: %v", err)
+	}
+
+	newFields, err := configSettingEntryFields(ledgerChange.Post, header)
+	if err != nil {
+		return []ConfigSettingDiffOutput{}, fmt.Errorf("could not read config setting: %v", err)
+	}
+
+	names := []string{}
+	for name := range oldFields {
+		names = append(names, name)
+	}
+	for name := range newFields {
+		if _, ok := oldFields[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	configSettingID := xdr.ConfigSettingId(configSetting.ConfigSettingId)
+	diffs := []ConfigSettingDiffOutput{}
+	for _, name := range names {
+		// The id is on every record, so it is not a field of the diff
+		if configSettingMetadataFields[name] || name == "config_setting_id" {
+			continue
+		}
+
+		oldValue := configSettingFieldValue(oldFields, name, newFields == nil)
+		newValue := configSettingFieldValue(newFields, name, oldFields == nil)
+		if oldValue == newValue {
+			continue
+		}
+
+		diffs = append(diffs, ConfigSettingDiffOutput{
+			ConfigSettingId:       configSetting.ConfigSettingId,
+			ConfigSettingIdString: configSettingID.String(),
+			Field:                 name,
+			OldValue:              oldValue,
+			NewValue:              newValue,
+			LastModifiedLedger:    configSetting.LastModifiedLedger,
+			LedgerEntryChange:     configSetting.LedgerEntryChange,
+			Deleted:               configSetting.Deleted,
+			ClosedAt:              configSetting.ClosedAt,
+			LedgerSequence:        configSetting.LedgerSequence,
+			LedgerKeyHash:         configSetting.LedgerKeyHash,
+		})
+	}
+
+	return diffs, nil
+}
+
+// configSettingEntryFields returns the json encoded fields of a config setting ledger entry, or nil if there is no entry
+func configSettingEntryFields(entry *xdr.LedgerEntry, header xdr.LedgerHeaderHistoryEntry) (map[string]json.RawMessage, error) {
+	if entry == nil {
+		return nil, nil
+	}
+
+	// The entry is transformed on its own so that the state before and after the change can be compared
+	configSetting, err := TransformConfigSetting(ingest.Change{
+		Type:       xdr.LedgerEntryTypeConfigSetting,
+		Pre:        entry,
+		Post:       entry,
+		ChangeType: xdr.LedgerEntryChangeTypeLedgerEntryUpdated,
+	}, header)
+	if err != nil {
+		return nil, err
+	}
+
+	return configSettingFields(configSetting)
+}
+
+// configSettingFieldValue returns the json value of a field. If the other side of the change has no setting, fields that
+// the setting doesn't set are null, so a created or removed setting only lists its own fields.
+func configSettingFieldValue(fields map[string]json.RawMessage, name string, otherMissing bool) null.String {
+	value, ok := fields[name]
+	if !ok || (otherMissing && emptyConfigSettingValues[string(value)]) {
+		return null.String{}
+	}
+	return null.StringFrom(string(value))
+}
@@ -0,0 +1,91 @@
+package transform
+
+import (
+	"testing"
+	"time"
+
+	"github.com/guregu/null"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stellar/go-stellar-sdk/ingest"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+func TestTransformConfigSettingDiffs(t *testing.T) {
+	header := xdr.LedgerHeaderHistoryEntry{
+		Header: xdr.LedgerHeader{
+			ScpValue:  xdr.StellarValue{CloseTime: 1000},
+			LedgerSeq: 10,
+		},
+	}
+	oldEntry := makeContractComputeEntry(9, 10000, 41943040)
+	newEntry := makeContractComputeEntry(10, 12500, 41943040)
+
+	change := ingest.Change{
+		Type:       xdr.LedgerEntryTypeConfigSetting,
+		Pre:        &oldEntry,
+		Post:       &newEntry,
+		ChangeType: xdr.LedgerEntryChangeTypeLedgerEntryUpdated,
+	}
+	configSetting, err := TransformConfigSetting(change, header)
+	assert.NoError(t, err)
+
+	expected := []ConfigSettingDiffOutput{
+		{
+			ConfigSettingId:       1,
+			ConfigSettingIdString: "ConfigSettingIdConfigSettingContractComputeV0",
+			Field:                 "fee_rate_per_instructions_increment",
+			OldValue:              null.StringFrom("10000"),
+			NewValue:              null.StringFrom("12500"),
+			LastModifiedLedger:    10,
+			LedgerEntryChange:     1,
+			Deleted:               false,
+			ClosedAt:              time.Date(1970, time.January, 1, 0, 16, 40, 0, time.UTC),
+			LedgerSequence:        10,
+			LedgerKeyHash:         configSetting.LedgerKeyHash,
+		},
+	}
+
+	actualOutput, err := TransformConfigSettingDiffs(change, header)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, actualOutput)
+}
+
+func TestTransformConfigSettingDiffsCreated(t *testing.T) {
+	newEntry := makeContractComputeEntry(10, 12500, 41943040)
+	actualOutput, err := TransformConfigSettingDiffs(ingest.Change{
+		Type:       xdr.LedgerEntryTypeConfigSetting,
+		Post:       &newEntry,
+		ChangeType: xdr.LedgerEntryChangeTypeLedgerEntryCreated,
+	}, xdr.LedgerHeaderHistoryEntry{})
+	assert.NoError(t, err)
+
+	values := map[string][2]null.String{}
+	for _, diff := range actualOutput {
+		values[diff.Field] = [2]null.String{diff.OldValue, diff.NewValue}
+	}
+	assert.Equal(t, map[string][2]null.String{
+		"fee_rate_per_instructions_increment": {null.String{}, null.StringFrom("12500")},
+		"ledger_max_instructions":             {null.String{}, null.StringFrom("100000000")},
+		"tx_max_instructions":                 {null.String{}, null.StringFrom("25000000")},
+		"tx_memory_limit":                     {null.String{}, null.StringFrom("41943040")},
+	}, values)
+}
+
+func makeContractComputeEntry(lastModifiedLedger uint32, feeRate int64, memoryLimit uint32) xdr.LedgerEntry {
+	return xdr.LedgerEntry{
+		LastModifiedLedgerSeq: xdr.Uint32(lastModifiedLedger),
+		Data: xdr.LedgerEntryData{
+			Type: xdr.LedgerEntryTypeConfigSetting,
+			ConfigSetting: &xdr.ConfigSettingEntry{
+				ConfigSettingId: xdr.ConfigSettingIdConfigSettingContractComputeV0,
+				ContractCompute: &xdr.ConfigSettingContractComputeV0{
+					LedgerMaxInstructions:           100000000,
+					TxMaxInstructions:               25000000,
+					FeeRatePerInstructionsIncrement: xdr.Int64(feeRate),
+					TxMemoryLimit:                   xdr.Uint32(memoryLimit),
+				},
+			},
+		},
+	}
+}
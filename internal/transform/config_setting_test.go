@@ -150,6 +150,8 @@ func makeConfigSettingTestOutput() []ConfigSettingOutput {
 			LedgerEntryChange:                      1,
 			Deleted:                                false,
 			LedgerSequence:                         10,
+			LedgerKeyHash:                          "0473a26b7f2943c75581105f8c9c0b7d51189790b021b2891e9cbfb7f153a725",
+			LedgerKeyHashBase64:                    "AAAACAAAAAA=",
 			ClosedAt:                               time.Date(1970, time.January, 1, 0, 16, 40, 0, time.UTC),
 		},
 	}
@@ -0,0 +1,138 @@
+package transform
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/stellar/go-stellar-sdk/ingest"
+	"github.com/stellar/go-stellar-sdk/strkey"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+// configSettingMetadataFields are the ConfigSettingOutput fields that describe the ledger entry change instead of the setting itself
+var configSettingMetadataFields = map[string]bool{
+	"last_modified_ledger":    true,
+	"ledger_entry_change":     true,
+	"deleted":                 true,
+	"closed_at":               true,
+	"ledger_sequence":         true,
+	"ledger_key_hash":         true,
+	"ledger_key_hash_base_64": true,
+}
+
+// TransformConfigUpgrades converts the config settings changed by the soroban config upgrades applied while closing a ledger
+// into a form suitable for BigQuery. Each changed setting is exported with both its previous and upgraded values.
+func TransformConfigUpgrades(lcm xdr.LedgerCloseMeta) ([]ConfigUpgradeOutput, error) {
+	header := lcm.LedgerHeaderHistoryEntry()
+	outputLedgerSequence := uint32(header.Header.LedgerSeq)
+
+	var transformedUpgrades []ConfigUpgradeOutput
+	for i, upgradeEntry := range lcm.UpgradesProcessing() {
+		configKey, ok := upgradeEntry.Upgrade.GetNewConfig()
+		if !ok {
+			continue
+		}
+
+		outputContractID, err := strkey.Encode(strkey.VersionByteContract, configKey.ContractId[:])
+		if err != nil {
+			return []ConfigUpgradeOutput{}, fmt.Errorf("could not encode config upgrade contract id in ledger %d: %v", outputLedgerSequence, err)
+		}
+
+		for _, change := range ingest.GetChangesFromLedgerEntryChanges(upgradeEntry.Changes) {
+			if change.Type != xdr.LedgerEntryTypeConfigSetting {
+				continue
+			}
+
+			newConfigSetting, err := TransformConfigSetting(change, header)
+			if err != nil {
+				return []ConfigUpgradeOutput{}, fmt.Errorf("could not transform upgraded config setting in ledger %d: %v", outputLedgerSequence, err)
+			}
+
+			var oldConfigSetting *ConfigSettingOutput
+			if change.Pre != nil {
+				// The previous state is transformed on its own so that its values can be compared with the upgraded ones
+				transformedOld, err := TransformConfigSetting(ingest.Change{
+					Type:       change.Type,
+					Pre:        change.Pre,
+					Post:       change.Pre,
+					ChangeType: xdr.LedgerEntryChangeTypeLedgerEntryUpdated,
+				}, header)
+				if err != nil {
+					return []ConfigUpgradeOutput{}, fmt.Errorf("could not transform previous config setting in ledger %d: %v", outputLedgerSequence, err)
+				}
+				oldConfigSetting = &transformedOld
+			}
+
+			changedFields, err := changedConfigSettingFields(oldConfigSetting, newConfigSetting)
+			if err != nil {
+				return []ConfigUpgradeOutput{}, fmt.Errorf("could not compare config settings in ledger %d: %v", outputLedgerSequence, err)
+			}
+
+			configSettingID := xdr.ConfigSettingId(newConfigSetting.ConfigSettingId)
+			transformedUpgrades = append(transformedUpgrades, ConfigUpgradeOutput{
+				LedgerSequence:              outputLedgerSequence,
+				ClosedAt:                    newConfigSetting.ClosedAt,
+				UpgradeIndex:                int32(i),
+				ConfigUpgradeSetContractID:  outputContractID,
+				ConfigUpgradeSetContentHash: configKey.ContentHash.HexString(),
+				ConfigSettingId:             newConfigSetting.ConfigSettingId,
+				ConfigSettingIdString:       configSettingID.String(),
+				ChangedFields:               changedFields,
+				OldConfigSetting:            oldConfigSetting,
+				NewConfigSetting:            newConfigSetting,
+			})
+		}
+	}
+
+	return transformedUpgrades, nil
+}
+
+// changedConfigSettingFields returns the sorted json names of the config setting fields that differ between the old and new settings.
+// Every field is considered changed if there is no old setting.
+func changedConfigSettingFields(oldSetting *ConfigSettingOutput, newSetting ConfigSettingOutput) ([]string, error) {
+	newFields, err := configSettingFields(newSetting)
+	if err != nil {
+		return nil, err
+	}
+
+	oldFields := map[string]json.RawMessage{}
+	if oldSetting != nil {
+		oldFields, err = configSettingFields(*oldSetting)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	changedFields := []string{}
+	for name, newValue := range newFields {
+		if configSettingMetadataFields[name] {
+			continue
+		}
+
+		oldValue, ok := oldFields[name]
+		if !ok || string(oldValue) != string(newValue) {
+			changedFields = append(changedFields, name)
+		}
+	}
+	sort.Strings(changedFields)
+
+	return changedFields, nil
+}
+
+// configSettingFields returns the json encoded fields of the config setting keyed by their json names. Equal values have
+// the same encoding, so fields can be compared by their encoded values.
+func configSettingFields(setting ConfigSettingOutput) (map[string]json.RawMessage, error) {
+	marshalled, err := json.Marshal(setting)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := map[string]json.RawMessage{}
+	err = json.Unmarshal(marshalled, &fields)
+	if err != nil {
+		return nil, err
+	}
+
+	return fields, nil
+}
@@ -0,0 +1,128 @@
+package transform
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stellar/go-stellar-sdk/ingest"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+func TestTransformConfigUpgrades(t *testing.T) {
+	input, oldEntry, newEntry := makeConfigUpgradesTestInput()
+	header := input.LedgerHeaderHistoryEntry()
+
+	newConfigSetting, err := TransformConfigSetting(ingest.Change{
+		Type:       xdr.LedgerEntryTypeConfigSetting,
+		Pre:        &oldEntry,
+		Post:       &newEntry,
+		ChangeType: xdr.LedgerEntryChangeTypeLedgerEntryUpdated,
+	}, header)
+	assert.NoError(t, err)
+
+	oldConfigSetting, err := TransformConfigSetting(ingest.Change{
+		Type:       xdr.LedgerEntryTypeConfigSetting,
+		Pre:        &oldEntry,
+		Post:       &oldEntry,
+		ChangeType: xdr.LedgerEntryChangeTypeLedgerEntryUpdated,
+	}, header)
+	assert.NoError(t, err)
+
+	expected := []ConfigUpgradeOutput{
+		{
+			LedgerSequence:              10,
+			ClosedAt:                    time.Date(1970, time.January, 1, 0, 16, 40, 0, time.UTC),
+			UpgradeIndex:                1,
+			ConfigUpgradeSetContractID:  "CAAQEAYAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAABXAS",
+			ConfigUpgradeSetContentHash: "0405060000000000000000000000000000000000000000000000000000000000",
+			ConfigSettingId:             0,
+			ConfigSettingIdString:       "ConfigSettingIdConfigSettingContractMaxSizeBytes",
+			ChangedFields:               []string{"contract_max_size_bytes"},
+			OldConfigSetting:            &oldConfigSetting,
+			NewConfigSetting:            newConfigSetting,
+		},
+	}
+
+	actualOutput, actualError := TransformConfigUpgrades(input)
+	assert.NoError(t, actualError)
+	assert.Equal(t, expected, actualOutput)
+}
+
+func TestChangedConfigSettingFieldsWithoutOldSetting(t *testing.T) {
+	changedFields, err := changedConfigSettingFields(nil, ConfigSettingOutput{ContractMaxSizeBytes: 10})
+	assert.NoError(t, err)
+	assert.Contains(t, changedFields, "contract_max_size_bytes")
+	assert.NotContains(t, changedFields, "ledger_sequence")
+}
+
+func makeConfigUpgradesTestInput() (xdr.LedgerCloseMeta, xdr.LedgerEntry, xdr.LedgerEntry) {
+	oldMaxSize := xdr.Uint32(100)
+	newMaxSize := xdr.Uint32(200)
+	newLedgerVersion := xdr.Uint32(23)
+
+	oldEntry := xdr.LedgerEntry{
+		LastModifiedLedgerSeq: 5,
+		Data: xdr.LedgerEntryData{
+			Type: xdr.LedgerEntryTypeConfigSetting,
+			ConfigSetting: &xdr.ConfigSettingEntry{
+				ConfigSettingId:      xdr.ConfigSettingIdConfigSettingContractMaxSizeBytes,
+				ContractMaxSizeBytes: &oldMaxSize,
+			},
+		},
+	}
+	newEntry := xdr.LedgerEntry{
+		LastModifiedLedgerSeq: 10,
+		Data: xdr.LedgerEntryData{
+			Type: xdr.LedgerEntryTypeConfigSetting,
+			ConfigSetting: &xdr.ConfigSettingEntry{
+				ConfigSettingId:      xdr.ConfigSettingIdConfigSettingContractMaxSizeBytes,
+				ContractMaxSizeBytes: &newMaxSize,
+			},
+		},
+	}
+
+	lcm := xdr.LedgerCloseMeta{
+		V: 1,
+		V1: &xdr.LedgerCloseMetaV1{
+			LedgerHeader: xdr.LedgerHeaderHistoryEntry{
+				Header: xdr.LedgerHeader{
+					ScpValue: xdr.StellarValue{
+						CloseTime: 1000,
+					},
+					LedgerSeq: 10,
+				},
+			},
+			UpgradesProcessing: []xdr.UpgradeEntryMeta{
+				{
+					Upgrade: xdr.LedgerUpgrade{
+						Type:             xdr.LedgerUpgradeTypeLedgerUpgradeVersion,
+						NewLedgerVersion: &newLedgerVersion,
+					},
+				},
+				{
+					Upgrade: xdr.LedgerUpgrade{
+						Type: xdr.LedgerUpgradeTypeLedgerUpgradeConfig,
+						NewConfig: &xdr.ConfigUpgradeSetKey{
+							ContractId:  xdr.ContractId{1, 2, 3},
+							ContentHash: xdr.Hash{4, 5, 6},
+						},
+					},
+					Changes: xdr.LedgerEntryChanges{
+						{
+							Type:  xdr.LedgerEntryChangeTypeLedgerEntryState,
+							State: &oldEntry,
+						},
+						{
+							Type:    xdr.LedgerEntryChangeTypeLedgerEntryUpdated,
+							Updated: &newEntry,
+						},
+					},
+				},
+			},
+		},
+	}
+
+	return lcm, oldEntry, newEntry
+}
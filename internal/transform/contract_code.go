@@ -25,17 +25,7 @@ func TransformContractCode(ledgerChange ingest.Change, header xdr.LedgerHeaderHi
 		return ContractCodeOutput{}, nil
 	}
 
-	var ledgerKey xdr.LedgerKey
-	var ledgerKeyHashBase64 string
-
-	ledgerKeyHash := utils.LedgerEntryToLedgerKeyHash(ledgerEntry)
-
-	ledgerKey, err = ledgerEntry.LedgerKey()
-	if err != nil {
-		return ContractCodeOutput{}, err
-	}
-
-	ledgerKeyHashBase64, err = xdr.MarshalBase64(ledgerKey)
+	ledgerKeyHash, ledgerKeyHashBase64, err := utils.LedgerEntryToLedgerKeyHashes(ledgerEntry)
 	if err != nil {
 		return ContractCodeOutput{}, err
 	}
@@ -62,17 +62,7 @@ func (t *TransformContractDataStruct) TransformContractData(ledgerChange ingest.
 		return ContractDataOutput{}, nil, false
 	}
 
-	var ledgerKey xdr.LedgerKey
-	var ledgerKeyHashBase64 string
-
-	ledgerKeyHash := utils.LedgerEntryToLedgerKeyHash(ledgerEntry)
-
-	ledgerKey, err = ledgerEntry.LedgerKey()
-	if err != nil {
-		return ContractDataOutput{}, err, false
-	}
-
-	ledgerKeyHashBase64, err = xdr.MarshalBase64(ledgerKey)
+	ledgerKeyHash, ledgerKeyHashBase64, err := utils.LedgerEntryToLedgerKeyHashes(ledgerEntry)
 	if err != nil {
 		return ContractDataOutput{}, err, false
 	}
@@ -118,11 +108,11 @@ func (t *TransformContractDataStruct) TransformContractData(ledgerChange ingest.
 
 	ledgerSequence := header.Header.LedgerSeq
 
-	outputKey, outputKeyDecoded, err := serializeScVal(contractData.Key)
+	outputKey, outputKeyDecoded, keyTruncated, err := serializeScVal(contractData.Key)
 	if err != nil {
 		return ContractDataOutput{}, err, false
 	}
-	outputVal, outputValDecoded, err := serializeScVal(contractData.Val)
+	outputVal, outputValDecoded, valTruncated, err := serializeScVal(contractData.Val)
 	if err != nil {
 		return ContractDataOutput{}, err, false
 	}
@@ -151,6 +141,7 @@ func (t *TransformContractDataStruct) TransformContractData(ledgerChange ingest.
 		KeyDecoded:                outputKeyDecoded,
 		Val:                       outputVal,
 		ValDecoded:                outputValDecoded,
+		ValueTruncated:            keyTruncated || valTruncated,
 		ContractDataXDR:           outputContractDataXDR,
 		LedgerKeyHashBase64:       ledgerKeyHashBase64,
 	}
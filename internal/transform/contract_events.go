@@ -6,7 +6,6 @@ import (
 	"fmt"
 
 	"github.com/guregu/null"
-	"github.com/stellar/go-stellar-xdr-json/xdrjson"
 	"github.com/stellar/stellar-etl/v2/internal/toid"
 	"github.com/stellar/stellar-etl/v2/internal/utils"
 
@@ -26,6 +25,11 @@ func TransformContractEvent(transaction ingest.LedgerTransaction, lhe xdr.Ledger
 	}
 
 	var transformedContractEvents []ContractEventOutput
+	// The events are numbered in the order they are exported, which is the order of operationEventIndex
+	addEvent := func(parsedDiagnosticEvent ContractEventOutput) {
+		parsedDiagnosticEvent.EventID = ContractEventID(parsedDiagnosticEvent.TransactionID, len(transformedContractEvents))
+		transformedContractEvents = append(transformedContractEvents, parsedDiagnosticEvent)
+	}
 
 	// Need to loop through the 3 different arrays within TransactionEvents and join them all together in a final []ContractEventOutput
 	for _, transactionEvent := range transactionEvents.TransactionEvents {
@@ -35,7 +39,7 @@ func TransformContractEvent(transaction ingest.LedgerTransaction, lhe xdr.Ledger
 			return []ContractEventOutput{}, err
 		}
 
-		transformedContractEvents = append(transformedContractEvents, parsedDiagnosticEvent)
+		addEvent(parsedDiagnosticEvent)
 	}
 
 	// Note that OperationEvents is an array of operations each with an array of ContractEvents (e.g., [][]ContractEvents)
@@ -51,7 +55,7 @@ func TransformContractEvent(transaction ingest.LedgerTransaction, lhe xdr.Ledger
 
 			parsedDiagnosticEvent.OperationID = null.IntFrom(operationID)
 
-			transformedContractEvents = append(transformedContractEvents, parsedDiagnosticEvent)
+			addEvent(parsedDiagnosticEvent)
 		}
 	}
 
@@ -61,12 +65,45 @@ func TransformContractEvent(transaction ingest.LedgerTransaction, lhe xdr.Ledger
 			return []ContractEventOutput{}, err
 		}
 
-		transformedContractEvents = append(transformedContractEvents, parsedDiagnosticEvent)
+		addEvent(parsedDiagnosticEvent)
 	}
 
 	return transformedContractEvents, nil
 }
 
+// ContractEventTypeName returns the name of the type of an event in the event_type column: contract, system or
+// diagnostic
+func ContractEventTypeName(eventType xdr.ContractEventType) string {
+	switch eventType {
+	case xdr.ContractEventTypeContract:
+		return "contract"
+	case xdr.ContractEventTypeSystem:
+		return "system"
+	case xdr.ContractEventTypeDiagnostic:
+		return "diagnostic"
+	default:
+		return "unknown"
+	}
+}
+
+// ContractEventID returns the id of an event: the id of its transaction and the index of the event among the events of
+// the transaction, in the format of the event ids of stellar-rpc. Every record derived from the event has the same id,
+// so contract events and effects can be joined and deduplicated on it.
+func ContractEventID(transactionID int64, eventIndex int) string {
+	return fmt.Sprintf("%019d-%010d", transactionID, eventIndex)
+}
+
+// operationEventIndex returns the index among the events of a transaction of the first event of an operation. The
+// events of the transaction come first, then the events of every operation in order, and the diagnostic events last,
+// so the indexes of the other events don't depend on whether diagnostic events were recorded.
+func operationEventIndex(events ingest.TransactionEvents, operationIndex int) int {
+	index := len(events.TransactionEvents)
+	for _, operationEvents := range events.OperationEvents[:operationIndex] {
+		index += len(operationEvents)
+	}
+	return index
+}
+
 // TODO this should be a stellar/go/xdr function
 func getEventTopics(eventBody xdr.ContractEventBody) []xdr.ScVal {
 	switch eventBody.V {
@@ -93,10 +130,13 @@ func getEventData(eventBody xdr.ContractEventBody) xdr.ScVal {
 	return xdr.ScVal{}
 }
 
-func serializeScVal(scVal xdr.ScVal) (interface{}, interface{}, error) {
+// serializeScVal returns the base64 XDR and the decoded JSON of an ScVal, and whether the decoded JSON was truncated
+// to stay within the ScVal limits
+func serializeScVal(scVal xdr.ScVal) (interface{}, interface{}, bool, error) {
 	var serializedData, serializedDataDecoded interface{}
 	serializedData = "n/a"
 	serializedDataDecoded = "n/a"
+	var truncated bool
 
 	if _, ok := scVal.ArmForSwitch(int32(scVal.Type)); ok {
 		var err error
@@ -104,36 +144,38 @@ func serializeScVal(scVal xdr.ScVal) (interface{}, interface{}, error) {
 		var jsonMessage json.RawMessage
 		raw, err = scVal.MarshalBinary()
 		if err != nil {
-			return nil, nil, err
+			return nil, nil, false, err
 		}
 
 		serializedData = base64.StdEncoding.EncodeToString(raw)
-		jsonMessage, err = xdrjson.Decode(xdrjson.ScVal, raw)
+		jsonMessage, truncated, err = decodeScVal(scVal)
 		if err != nil {
-			return nil, nil, err
+			return nil, nil, false, err
 		}
 
 		serializedDataDecoded = jsonMessage
 	}
 
-	return serializedData, serializedDataDecoded, nil
+	return serializedData, serializedDataDecoded, truncated, nil
 }
 
-func serializeScValArray(scVals []xdr.ScVal) ([]interface{}, []interface{}, error) {
+func serializeScValArray(scVals []xdr.ScVal) ([]interface{}, []interface{}, bool, error) {
 	data := make([]interface{}, 0, len(scVals))
 	dataDecoded := make([]interface{}, 0, len(scVals))
+	var truncated bool
 
 	for _, scVal := range scVals {
-		serializedData, serializedDataDecoded, err := serializeScVal(scVal)
+		serializedData, serializedDataDecoded, serializedTruncated, err := serializeScVal(scVal)
 		if err != nil {
-			return nil, nil, err
+			return nil, nil, false, err
 		}
 
 		data = append(data, serializedData)
 		dataDecoded = append(dataDecoded, serializedDataDecoded)
+		truncated = truncated || serializedTruncated
 	}
 
-	return data, dataDecoded, nil
+	return data, dataDecoded, truncated, nil
 }
 
 // transactionEvent2DiagnosticEvent converts TransactionEvents into DiagnosticEvents
@@ -197,13 +239,13 @@ func parseDiagnosticEvent(
 	outputTypeString := event.Type.String()
 
 	eventTopics := getEventTopics(event.Body)
-	outputTopics, outputTopicsDecoded, err = serializeScValArray(eventTopics)
+	outputTopics, outputTopicsDecoded, topicsTruncated, err := serializeScValArray(eventTopics)
 	if err != nil {
 		return ContractEventOutput{}, err
 	}
 
 	eventData := getEventData(event.Body)
-	outputData, outputDataDecoded, err = serializeScVal(eventData)
+	outputData, outputDataDecoded, dataTruncated, err := serializeScVal(eventData)
 	if err != nil {
 		return ContractEventOutput{}, err
 	}
@@ -231,10 +273,12 @@ func parseDiagnosticEvent(
 		ContractId:               outputContractId,
 		Type:                     int32(outputType),
 		TypeString:               outputTypeString,
+		EventType:                ContractEventTypeName(outputType),
 		Topics:                   outputTopics,
 		TopicsDecoded:            outputTopicsDecoded,
 		Data:                     outputData,
 		DataDecoded:              outputDataDecoded,
+		ValueTruncated:           topicsTruncated || dataTruncated,
 		ContractEventXDR:         outputContractEventXDR,
 	}
 
@@ -45,6 +45,19 @@ func TestTransformContractEvent(t *testing.T) {
 	}
 }
 
+func TestOperationEventIndex(t *testing.T) {
+	events := ingest.TransactionEvents{
+		TransactionEvents: make([]xdr.TransactionEvent, 2),
+		OperationEvents:   [][]xdr.ContractEvent{make([]xdr.ContractEvent, 3), {}, make([]xdr.ContractEvent, 1)},
+		DiagnosticEvents:  make([]xdr.DiagnosticEvent, 4),
+	}
+	assert.Equal(t, 2, operationEventIndex(events, 0))
+	assert.Equal(t, 5, operationEventIndex(events, 1))
+	assert.Equal(t, 5, operationEventIndex(events, 2))
+
+	assert.Equal(t, "0131090201534533632-0000000005", ContractEventID(131090201534533632, 5))
+}
+
 func makeContractEventTestOutput() (output [][]ContractEventOutput, err error) {
 
 	var topics, topicsDecoded []interface{}
@@ -66,12 +79,14 @@ func makeContractEventTestOutput() (output [][]ContractEventOutput, err error) {
 			ContractId:               "CAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAABSC4",
 			Type:                     2,
 			TypeString:               "ContractEventTypeDiagnostic",
+			EventType:                "diagnostic",
 			Topics:                   topics,
 			TopicsDecoded:            topicsDecoded,
 			Data:                     data,
 			DataDecoded:              dataDecoded,
 			ContractEventXDR:         "AAAAAQAAAAAAAAABAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAACAAAAAAAAAAEAAAAAAAAAAQAAAAAAAAAB",
 			OperationID:              null.IntFrom(131090201534533633),
+			EventID:                  "0131090201534533632-0000000000",
 		},
 		ContractEventOutput{
 			TransactionHash:          "a87fef5eeb260269c380f2de456aad72b59bb315aaac777860456e09dac0bafb",
@@ -83,11 +98,13 @@ func makeContractEventTestOutput() (output [][]ContractEventOutput, err error) {
 			ContractId:               "CAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAABSC4",
 			Type:                     2,
 			TypeString:               "ContractEventTypeDiagnostic",
+			EventType:                "diagnostic",
 			Topics:                   topics,
 			TopicsDecoded:            topicsDecoded,
 			Data:                     data,
 			DataDecoded:              dataDecoded,
 			ContractEventXDR:         "AAAAAQAAAAAAAAABAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAACAAAAAAAAAAEAAAAAAAAAAQAAAAAAAAAB",
+			EventID:                  "0131090201534533632-0000000001",
 		},
 	},
 		{
@@ -101,12 +118,14 @@ func makeContractEventTestOutput() (output [][]ContractEventOutput, err error) {
 				ContractId:               "CAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAABSC4",
 				Type:                     1,
 				TypeString:               "ContractEventTypeContract",
+				EventType:                "contract",
 				Topics:                   topics,
 				TopicsDecoded:            topicsDecoded,
 				Data:                     data,
 				DataDecoded:              dataDecoded,
 				ContractEventXDR:         "AAAAAQAAAAAAAAABAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAABAAAAAAAAAAEAAAAAAAAAAQAAAAAAAAAB",
 				OperationID:              null.Int{},
+				EventID:                  "0131090201534537728-0000000000",
 			},
 			ContractEventOutput{
 				TransactionHash:          "a87fef5eeb260269c380f2de456aad72b59bb315aaac777860456e09dac0bafb",
@@ -118,12 +137,14 @@ func makeContractEventTestOutput() (output [][]ContractEventOutput, err error) {
 				ContractId:               "CAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAABSC4",
 				Type:                     1,
 				TypeString:               "ContractEventTypeContract",
+				EventType:                "contract",
 				Topics:                   topics,
 				TopicsDecoded:            topicsDecoded,
 				Data:                     data,
 				DataDecoded:              dataDecoded,
 				ContractEventXDR:         "AAAAAQAAAAAAAAABAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAABAAAAAAAAAAEAAAAAAAAAAQAAAAAAAAAB",
 				OperationID:              null.IntFrom(131090201534537729),
+				EventID:                  "0131090201534537728-0000000001",
 			},
 			ContractEventOutput{
 				TransactionHash:          "a87fef5eeb260269c380f2de456aad72b59bb315aaac777860456e09dac0bafb",
@@ -135,12 +156,14 @@ func makeContractEventTestOutput() (output [][]ContractEventOutput, err error) {
 				ContractId:               "CAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAABSC4",
 				Type:                     2,
 				TypeString:               "ContractEventTypeDiagnostic",
+				EventType:                "diagnostic",
 				Topics:                   topics,
 				TopicsDecoded:            topicsDecoded,
 				Data:                     data,
 				DataDecoded:              dataDecoded,
 				ContractEventXDR:         "AAAAAQAAAAAAAAABAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAACAAAAAAAAAAEAAAAAAAAAAQAAAAAAAAAB",
 				OperationID:              null.Int{},
+				EventID:                  "0131090201534537728-0000000002",
 			},
 		},
 	}
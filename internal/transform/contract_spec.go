@@ -0,0 +1,141 @@
+package transform
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"strings"
+
+	"github.com/stellar/go-stellar-sdk/ingest"
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stellar/stellar-etl/v2/internal/utils"
+)
+
+// contractSpecSection is the name of the wasm custom section that holds the XDR encoded spec entries of a contract
+const contractSpecSection = "contractspecv0"
+
+// TransformContractSpecs converts the functions declared in the spec of a created contract code into one record per
+// function, with its inputs and outputs, so the interface of a contract can be displayed without its wasm. Contract
+// code without a spec, such as code built without the soroban sdk, has no records.
+func TransformContractSpecs(ledgerChange ingest.Change, header xdr.LedgerHeaderHistoryEntry) ([]ContractSpecOutput, error) {
+	ledgerEntry, _, _, err := utils.ExtractEntryFromChange(ledgerChange)
+	if err != nil {
+		return []ContractSpecOutput{}, err
+	}
+
+	contractCode, ok := ledgerEntry.Data.GetContractCode()
+	if !ok {
+		return []ContractSpecOutput{}, fmt.Errorf("could not extract contract code from ledger entry; actual type is %s", ledgerEntry.Data.Type)
+	}
+
+	contractCodeHash := contractCode.Hash.HexString()
+	entries, err := contractSpecEntries(contractCode.Code)
+	if err != nil {
+		return []ContractSpecOutput{}, fmt.Errorf("could not read the spec of contract code %s: %v", contractCodeHash, err)
+	}
+
+	closedAt, err := utils.TimePointToUTCTimeStamp(header.Header.ScpValue.CloseTime)
+	if err != nil {
+		return []ContractSpecOutput{}, err
+	}
+
+	specs := []ContractSpecOutput{}
+	for _, entry := range entries {
+		function, ok := entry.GetFunctionV0()
+		if !ok {
+			continue
+		}
+
+		inputs := make([]ContractSpecInput, len(function.Inputs))
+		for i, input := range function.Inputs {
+			inputs[i] = ContractSpecInput{Name: input.Name, Type: specTypeName(input.Type), Doc: input.Doc}
+		}
+		outputs := make([]string, len(function.Outputs))
+		for i, output := range function.Outputs {
+			outputs[i] = specTypeName(output)
+		}
+
+		specs = append(specs, ContractSpecOutput{
+			ContractCodeHash: contractCodeHash,
+			FunctionName:     string(function.Name),
+			Inputs:           inputs,
+			Outputs:          outputs,
+			Doc:              function.Doc,
+			ClosedAt:         closedAt,
+			LedgerSequence:   uint32(header.Header.LedgerSeq),
+		})
+	}
+
+	return specs, nil
+}
+
+// contractSpecEntries decodes the spec entries of the contractspecv0 custom sections of a wasm module
+func contractSpecEntries(wasm []byte) ([]xdr.ScSpecEntry, error) {
+	if len(wasm) < 8 || !bytes.Equal(wasm[:4], []byte("\x00asm")) {
+		return nil, fmt.Errorf("not a wasm module")
+	}
+
+	entries := []xdr.ScSpecEntry{}
+	decoder := xdr.NewBytesDecoder()
+	for rest := wasm[8:]; len(rest) > 0; {
+		sectionID := rest[0]
+		size, n := binary.Uvarint(rest[1:])
+		if n <= 0 || uint64(len(rest)-1-n) < size {
+			return nil, fmt.Errorf("truncated wasm section")
+		}
+		section := rest[1+n : 1+n+int(size)]
+		rest = rest[1+n+int(size):]
+
+		// Only custom sections, which have the id 0, can hold the spec
+		if sectionID != 0 {
+			continue
+		}
+		nameSize, n := binary.Uvarint(section)
+		if n <= 0 || uint64(len(section)-n) < nameSize {
+			return nil, fmt.Errorf("truncated wasm custom section name")
+		}
+		if string(section[n:n+int(nameSize)]) != contractSpecSection {
+			continue
+		}
+
+		for data := section[n+int(nameSize):]; len(data) > 0; {
+			var entry xdr.ScSpecEntry
+			read, err := decoder.DecodeBytes(&entry, data)
+			if err != nil {
+				return nil, fmt.Errorf("could not decode spec entry: %v", err)
+			}
+			entries = append(entries, entry)
+			data = data[read:]
+		}
+	}
+
+	return entries, nil
+}
+
+// specTypeName returns the name of a spec type as it is written in soroban contracts, such as vec<address> or
+// result<i128, error>. User defined types are named after their declaration.
+func specTypeName(typeDef xdr.ScSpecTypeDef) string {
+	switch typeDef.Type {
+	case xdr.ScSpecTypeScSpecTypeOption:
+		return fmt.Sprintf("option<%s>", specTypeName(typeDef.Option.ValueType))
+	case xdr.ScSpecTypeScSpecTypeResult:
+		return fmt.Sprintf("result<%s, %s>", specTypeName(typeDef.Result.OkType), specTypeName(typeDef.Result.ErrorType))
+	case xdr.ScSpecTypeScSpecTypeVec:
+		return fmt.Sprintf("vec<%s>", specTypeName(typeDef.Vec.ElementType))
+	case xdr.ScSpecTypeScSpecTypeMap:
+		return fmt.Sprintf("map<%s, %s>", specTypeName(typeDef.Map.KeyType), specTypeName(typeDef.Map.ValueType))
+	case xdr.ScSpecTypeScSpecTypeTuple:
+		names := make([]string, len(typeDef.Tuple.ValueTypes))
+		for i, valueType := range typeDef.Tuple.ValueTypes {
+			names[i] = specTypeName(valueType)
+		}
+		return fmt.Sprintf("tuple<%s>", strings.Join(names, ", "))
+	case xdr.ScSpecTypeScSpecTypeBytesN:
+		return fmt.Sprintf("bytes_n<%d>", typeDef.BytesN.N)
+	case xdr.ScSpecTypeScSpecTypeUdt:
+		return typeDef.Udt.Name
+	}
+
+	// The other types have no parameters, and are named after their enum value, such as ScSpecTypeScSpecTypeU32
+	return strings.ToLower(strings.TrimPrefix(typeDef.Type.String(), "ScSpecTypeScSpecType"))
+}
@@ -0,0 +1,144 @@
+package transform
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stellar/go-stellar-sdk/ingest"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+func TestTransformContractSpecs(t *testing.T) {
+	header := xdr.LedgerHeaderHistoryEntry{
+		Header: xdr.LedgerHeader{
+			ScpValue:  xdr.StellarValue{CloseTime: 1000},
+			LedgerSeq: 10,
+		},
+	}
+
+	u32 := xdr.ScSpecTypeDef{Type: xdr.ScSpecTypeScSpecTypeU32}
+	address := xdr.ScSpecTypeDef{Type: xdr.ScSpecTypeScSpecTypeAddress}
+	entries := []xdr.ScSpecEntry{
+		{
+			Kind: xdr.ScSpecEntryKindScSpecEntryFunctionV0,
+			FunctionV0: &xdr.ScSpecFunctionV0{
+				Doc:  "Transfers an amount",
+				Name: "transfer",
+				Inputs: []xdr.ScSpecFunctionInputV0{
+					{Name: "to", Type: address},
+					{Name: "amounts", Type: xdr.ScSpecTypeDef{
+						Type: xdr.ScSpecTypeScSpecTypeVec,
+						Vec:  &xdr.ScSpecTypeVec{ElementType: xdr.ScSpecTypeDef{Type: xdr.ScSpecTypeScSpecTypeI128}},
+					}},
+				},
+				Outputs: []xdr.ScSpecTypeDef{{
+					Type: xdr.ScSpecTypeScSpecTypeResult,
+					Result: &xdr.ScSpecTypeResult{
+						OkType:    xdr.ScSpecTypeDef{Type: xdr.ScSpecTypeScSpecTypeMap, Map: &xdr.ScSpecTypeMap{KeyType: address, ValueType: u32}},
+						ErrorType: xdr.ScSpecTypeDef{Type: xdr.ScSpecTypeScSpecTypeUdt, Udt: &xdr.ScSpecTypeUdt{Name: "Error"}},
+					},
+				}},
+			},
+		},
+		{
+			Kind: xdr.ScSpecEntryKindScSpecEntryUdtErrorEnumV0,
+			UdtErrorEnumV0: &xdr.ScSpecUdtErrorEnumV0{
+				Name:  "Error",
+				Cases: []xdr.ScSpecUdtErrorEnumCaseV0{{Name: "Overflow", Value: 1}},
+			},
+		},
+		{
+			Kind: xdr.ScSpecEntryKindScSpecEntryFunctionV0,
+			FunctionV0: &xdr.ScSpecFunctionV0{
+				Name: "decimals",
+				Outputs: []xdr.ScSpecTypeDef{{
+					Type: xdr.ScSpecTypeScSpecTypeTuple,
+					Tuple: &xdr.ScSpecTypeTuple{ValueTypes: []xdr.ScSpecTypeDef{
+						{Type: xdr.ScSpecTypeScSpecTypeOption, Option: &xdr.ScSpecTypeOption{ValueType: u32}},
+						{Type: xdr.ScSpecTypeScSpecTypeBytesN, BytesN: &xdr.ScSpecTypeBytesN{N: 32}},
+					}},
+				}},
+			},
+		},
+	}
+
+	spec := []byte{}
+	for _, entry := range entries {
+		marshalled, err := entry.MarshalBinary()
+		assert.NoError(t, err)
+		spec = append(spec, marshalled...)
+	}
+	wasm := append([]byte("\x00asm\x01\x00\x00\x00"), wasmCustomSection("name", []byte{0x00})...)
+	wasm = append(wasm, 0x01, 0x01, 0x00) // an empty type section
+	wasm = append(wasm, wasmCustomSection("contractspecv0", spec)...)
+
+	change := ingest.Change{
+		Type:       xdr.LedgerEntryTypeContractCode,
+		ChangeType: xdr.LedgerEntryChangeTypeLedgerEntryCreated,
+		Post: &xdr.LedgerEntry{
+			LastModifiedLedgerSeq: 10,
+			Data: xdr.LedgerEntryData{
+				Type: xdr.LedgerEntryTypeContractCode,
+				ContractCode: &xdr.ContractCodeEntry{
+					Hash: xdr.Hash{0x01},
+					Code: wasm,
+				},
+			},
+		},
+	}
+
+	closedAt := time.Date(1970, time.January, 1, 0, 16, 40, 0, time.UTC)
+	expected := []ContractSpecOutput{
+		{
+			ContractCodeHash: "0100000000000000000000000000000000000000000000000000000000000000",
+			FunctionName:     "transfer",
+			Inputs: []ContractSpecInput{
+				{Name: "to", Type: "address"},
+				{Name: "amounts", Type: "vec<i128>"},
+			},
+			Outputs:        []string{"result<map<address, u32>, Error>"},
+			Doc:            "Transfers an amount",
+			ClosedAt:       closedAt,
+			LedgerSequence: 10,
+		},
+		{
+			ContractCodeHash: "0100000000000000000000000000000000000000000000000000000000000000",
+			FunctionName:     "decimals",
+			Inputs:           []ContractSpecInput{},
+			Outputs:          []string{"tuple<option<u32>, bytes_n<32>>"},
+			ClosedAt:         closedAt,
+			LedgerSequence:   10,
+		},
+	}
+
+	actualOutput, err := TransformContractSpecs(change, header)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, actualOutput)
+
+	// Code without a spec section has no functions, and code that isn't wasm can't be read
+	change.Post.Data.ContractCode.Code = []byte("\x00asm\x01\x00\x00\x00")
+	actualOutput, err = TransformContractSpecs(change, header)
+	assert.NoError(t, err)
+	assert.Empty(t, actualOutput)
+
+	change.Post.Data.ContractCode.Code = []byte("not wasm")
+	_, err = TransformContractSpecs(change, header)
+	assert.Error(t, err)
+}
+
+// wasmCustomSection encodes a wasm custom section whose name is shorter than 128 bytes
+func wasmCustomSection(name string, content []byte) []byte {
+	section := append([]byte{byte(len(name))}, name...)
+	section = append(section, content...)
+	size := []byte{}
+	for n := len(section); ; n >>= 7 {
+		if n < 0x80 {
+			size = append(size, byte(n))
+			break
+		}
+		size = append(size, byte(n&0x7f|0x80))
+	}
+	return append(append([]byte{0x00}, size...), section...)
+}
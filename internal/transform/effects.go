@@ -7,6 +7,7 @@ import (
 	"reflect"
 	"sort"
 	"strconv"
+	"time"
 
 	"github.com/guregu/null"
 	"github.com/stellar/go-stellar-sdk/amount"
@@ -17,6 +18,7 @@ import (
 	"github.com/stellar/go-stellar-sdk/support/contractevents"
 	"github.com/stellar/go-stellar-sdk/support/errors"
 	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stellar/stellar-etl/v2/internal/toid"
 	"github.com/stellar/stellar-etl/v2/internal/utils"
 )
 
@@ -28,10 +30,12 @@ func TransformEffect(transaction ingest.LedgerTransaction, ledgerSeq uint32, led
 		return effects, err
 	}
 
+	cache := NewTransactionCache(transaction)
 	for opi, op := range transaction.Envelope.Operations() {
 		operation := transactionOperationWrapper{
 			index:          uint32(opi),
 			transaction:    transaction,
+			cache:          cache,
 			operation:      op,
 			ledgerSequence: ledgerSeq,
 			network:        networkPassphrase,
@@ -60,7 +64,7 @@ func (operation *transactionOperationWrapper) effects() ([]EffectOutput, error)
 		err error
 	)
 
-	changes, err := operation.transaction.GetOperationChanges(operation.index)
+	changes, err := operation.operationChanges()
 	if err != nil {
 		return nil, err
 	}
@@ -118,8 +122,7 @@ func (operation *transactionOperationWrapper) effects() ([]EffectOutput, error)
 	case xdr.OperationTypeInvokeHostFunction:
 		// If there's an invokeHostFunction operation, there's definitely V3
 		// meta in the transaction, which means this error is real.
-		// TODO: Replace GetContractEvents with TransformContractEvent to get all the events
-		contractEvents, innerErr := operation.transaction.GetContractEvents()
+		transactionEvents, innerErr := operation.transaction.GetTransactionEvents()
 
 		if innerErr != nil {
 			return nil, innerErr
@@ -127,7 +130,7 @@ func (operation *transactionOperationWrapper) effects() ([]EffectOutput, error)
 
 		// For now, the only effects are related to the events themselves.
 		// Possible add'l work: https://github.com/stellar/go-stellar-sdk/issues/4585
-		err = wrapper.addInvokeHostFunctionEffects(contractEvents)
+		err = wrapper.addInvokeHostFunctionEffects(transactionEvents)
 	case xdr.OperationTypeExtendFootprintTtl:
 		err = wrapper.addExtendFootprintTtlEffect()
 	case xdr.OperationTypeRestoreFootprint:
@@ -433,7 +436,7 @@ func (e *effectsWrapper) addPaymentEffects() {
 	op := e.operation.operation.Body.MustPaymentOp()
 
 	details := map[string]interface{}{"amount": amount.String(op.Amount)}
-	addAssetDetails(details, op.Asset, "")
+	addAssetDetails(details, op.Asset, "", e.operation.network)
 
 	e.addMuxed(
 		&op.Destination,
@@ -453,7 +456,7 @@ func (e *effectsWrapper) pathPaymentStrictReceiveEffects() error {
 	source := e.operation.SourceAccount()
 
 	details := map[string]interface{}{"amount": amount.String(op.DestAmount)}
-	addAssetDetails(details, op.DestAsset, "")
+	addAssetDetails(details, op.DestAsset, "", e.operation.network)
 
 	e.addMuxed(
 		&op.Destination,
@@ -463,7 +466,7 @@ func (e *effectsWrapper) pathPaymentStrictReceiveEffects() error {
 
 	result := e.operation.OperationResult().MustPathPaymentStrictReceiveResult()
 	details = map[string]interface{}{"amount": amount.String(result.SendAmount())}
-	addAssetDetails(details, op.SendAsset, "")
+	addAssetDetails(details, op.SendAsset, "", e.operation.network)
 
 	e.addMuxed(
 		source,
@@ -481,11 +484,11 @@ func (e *effectsWrapper) addPathPaymentStrictSendEffects() error {
 	result := e.operation.OperationResult().MustPathPaymentStrictSendResult()
 
 	details := map[string]interface{}{"amount": amount.String(result.DestAmount())}
-	addAssetDetails(details, op.DestAsset, "")
+	addAssetDetails(details, op.DestAsset, "", e.operation.network)
 	e.addMuxed(&op.Destination, EffectAccountCredited, details)
 
 	details = map[string]interface{}{"amount": amount.String(op.SendAmount)}
-	addAssetDetails(details, op.SendAsset, "")
+	addAssetDetails(details, op.SendAsset, "", e.operation.network)
 	e.addMuxed(source, EffectAccountDebited, details)
 
 	return e.addIngestTradeEffects(*source, resultSuccess.Offers, true)
@@ -569,7 +572,7 @@ func (e *effectsWrapper) addSetOptionsEffects() error {
 			},
 		)
 	}
-	changes, err := e.operation.transaction.GetOperationChanges(e.operation.index)
+	changes, err := e.operation.operationChanges()
 	if err != nil {
 		return err
 	}
@@ -641,7 +644,7 @@ func (e *effectsWrapper) addChangeTrustEffects() error {
 	source := e.operation.SourceAccount()
 
 	op := e.operation.operation.Body.MustChangeTrustOp()
-	changes, err := e.operation.transaction.GetOperationChanges(e.operation.index)
+	changes, err := e.operation.operationChanges()
 	if err != nil {
 		return err
 	}
@@ -687,7 +690,7 @@ func (e *effectsWrapper) addChangeTrustEffects() error {
 				return err
 			}
 		} else {
-			addAssetDetails(details, op.Line.ToAsset(), "")
+			addAssetDetails(details, op.Line.ToAsset(), "", e.operation.network)
 		}
 
 		e.addMuxed(source, effect, details)
@@ -704,7 +707,7 @@ func (e *effectsWrapper) addAllowTrustEffects() error {
 	details := map[string]interface{}{
 		"trustor": op.Trustor.Address(),
 	}
-	addAssetDetails(details, asset, "")
+	addAssetDetails(details, asset, "", e.operation.network)
 
 	switch {
 	case xdr.TrustLineFlags(op.Authorize).IsAuthorized():
@@ -762,7 +765,7 @@ func (e *effectsWrapper) addManageDataEffects() error {
 	op := e.operation.operation.Body.MustManageDataOp()
 	details := map[string]interface{}{"name": op.DataName}
 	effect := EffectType(0)
-	changes, err := e.operation.transaction.GetOperationChanges(e.operation.index)
+	changes, err := e.operation.operationChanges()
 	if err != nil {
 		return err
 	}
@@ -800,7 +803,7 @@ func (e *effectsWrapper) addManageDataEffects() error {
 
 func (e *effectsWrapper) addBumpSequenceEffects() error {
 	source := e.operation.SourceAccount()
-	changes, err := e.operation.transaction.GetOperationChanges(e.operation.index)
+	changes, err := e.operation.operationChanges()
 	if err != nil {
 		return err
 	}
@@ -833,6 +836,78 @@ func setClaimableBalanceFlagDetails(details map[string]interface{}, flags xdr.Cl
 	}
 }
 
+// setClaimableBalanceSponsorDetails sets the sponsor of the reserve of a claimable balance, if it is sponsored
+func setClaimableBalanceSponsorDetails(details map[string]interface{}, sponsor *xdr.AccountId) {
+	if sponsor != nil {
+		details["sponsor"] = sponsor.Address()
+	}
+}
+
+// claimedBalanceClaimant is a claimant of a claimed balance, and whether its predicate was satisfied when the balance
+// was claimed
+type claimedBalanceClaimant struct {
+	Destination        string             `json:"destination"`
+	Predicate          xdr.ClaimPredicate `json:"predicate"`
+	PredicateSatisfied bool               `json:"predicate_satisfied"`
+}
+
+// claimedBalanceClaimants evaluates the predicates of the claimants of a balance at the close time of the ledger it was
+// claimed in
+func claimedBalanceClaimants(claimants []xdr.Claimant, closedAt time.Time) ([]claimedBalanceClaimant, error) {
+	evaluated := make([]claimedBalanceClaimant, 0, len(claimants))
+	for _, c := range claimants {
+		cv0 := c.MustV0()
+		satisfied, err := claimPredicateSatisfied(cv0.Predicate, closedAt)
+		if err != nil {
+			return nil, err
+		}
+		evaluated = append(evaluated, claimedBalanceClaimant{
+			Destination:        cv0.Destination.Address(),
+			Predicate:          cv0.Predicate,
+			PredicateSatisfied: satisfied,
+		})
+	}
+	return evaluated, nil
+}
+
+// claimPredicateSatisfied evaluates a claim predicate at a ledger close time. Stellar-Core converts relative predicates
+// to absolute ones when a balance is created, so the predicates of balance entries are never relative.
+func claimPredicateSatisfied(predicate xdr.ClaimPredicate, closedAt time.Time) (bool, error) {
+	switch predicate.Type {
+	case xdr.ClaimPredicateTypeClaimPredicateUnconditional:
+		return true, nil
+	case xdr.ClaimPredicateTypeClaimPredicateAnd:
+		for _, p := range predicate.MustAndPredicates() {
+			satisfied, err := claimPredicateSatisfied(p, closedAt)
+			if err != nil || !satisfied {
+				return false, err
+			}
+		}
+		return true, nil
+	case xdr.ClaimPredicateTypeClaimPredicateOr:
+		for _, p := range predicate.MustOrPredicates() {
+			satisfied, err := claimPredicateSatisfied(p, closedAt)
+			if err != nil || satisfied {
+				return satisfied, err
+			}
+		}
+		return false, nil
+	case xdr.ClaimPredicateTypeClaimPredicateNot:
+		notPredicate := predicate.MustNotPredicate()
+		if notPredicate == nil {
+			return false, fmt.Errorf("not predicate without a predicate")
+		}
+		satisfied, err := claimPredicateSatisfied(*notPredicate, closedAt)
+		return !satisfied, err
+	case xdr.ClaimPredicateTypeClaimPredicateBeforeAbsoluteTime:
+		return closedAt.Unix() < int64(predicate.MustAbsBefore()), nil
+	case xdr.ClaimPredicateTypeClaimPredicateBeforeRelativeTime:
+		return false, fmt.Errorf("relative predicates can't be evaluated without the time the balance was created")
+	default:
+		return false, fmt.Errorf("unknown claim predicate type %d", predicate.Type)
+	}
+}
+
 func (e *effectsWrapper) addCreateClaimableBalanceEffects(changes []ingest.Change) error {
 	source := e.operation.SourceAccount()
 	var cb *xdr.ClaimableBalanceEntry
@@ -841,7 +916,7 @@ func (e *effectsWrapper) addCreateClaimableBalanceEffects(changes []ingest.Chang
 			continue
 		}
 		cb = change.Post.Data.ClaimableBalance
-		e.addClaimableBalanceEntryCreatedEffects(source, cb)
+		e.addClaimableBalanceEntryCreatedEffects(source, cb, change.Post.SponsoringID())
 		break
 	}
 	if cb == nil {
@@ -851,7 +926,7 @@ func (e *effectsWrapper) addCreateClaimableBalanceEffects(changes []ingest.Chang
 	details := map[string]interface{}{
 		"amount": amount.String(cb.Amount),
 	}
-	addAssetDetails(details, cb.Asset, "")
+	addAssetDetails(details, cb.Asset, "", e.operation.network)
 	e.addMuxed(
 		source,
 		EffectAccountDebited,
@@ -861,7 +936,7 @@ func (e *effectsWrapper) addCreateClaimableBalanceEffects(changes []ingest.Chang
 	return nil
 }
 
-func (e *effectsWrapper) addClaimableBalanceEntryCreatedEffects(source *xdr.MuxedAccount, cb *xdr.ClaimableBalanceEntry) error {
+func (e *effectsWrapper) addClaimableBalanceEntryCreatedEffects(source *xdr.MuxedAccount, cb *xdr.ClaimableBalanceEntry, sponsor *xdr.AccountId) error {
 	id, err := xdr.MarshalHex(cb.BalanceId)
 	if err != nil {
 		return err
@@ -872,11 +947,7 @@ func (e *effectsWrapper) addClaimableBalanceEntryCreatedEffects(source *xdr.Muxe
 		"asset":      cb.Asset.StringCanonical(),
 	}
 	setClaimableBalanceFlagDetails(details, cb.Flags())
-	e.addMuxed(
-		source,
-		EffectClaimableBalanceCreated,
-		details,
-	)
+	setClaimableBalanceSponsorDetails(details, sponsor)
 	// EffectClaimableBalanceClaimantCreated can be generated by
 	// `create_claimable_balance` operation but also by `liquidity_pool_withdraw`
 	// operation causing a revocation.
@@ -890,6 +961,12 @@ func (e *effectsWrapper) addClaimableBalanceEntryCreatedEffects(source *xdr.Muxe
 	} else {
 		claimants = cb.Claimants
 	}
+	details["claimants"] = transformClaimants(claimants)
+	e.addMuxed(
+		source,
+		EffectClaimableBalanceCreated,
+		details,
+	)
 	for _, c := range claimants {
 		cv0 := c.MustV0()
 		e.addUnmuxed(
@@ -915,6 +992,7 @@ func (e *effectsWrapper) addClaimClaimableBalanceEffects(changes []ingest.Change
 	}
 
 	var cBalance xdr.ClaimableBalanceEntry
+	var sponsor *xdr.AccountId
 	found := false
 	for _, change := range changes {
 		if change.Type != xdr.LedgerEntryTypeClaimableBalance {
@@ -923,6 +1001,7 @@ func (e *effectsWrapper) addClaimClaimableBalanceEffects(changes []ingest.Change
 
 		if change.Pre != nil && change.Post == nil {
 			cBalance = change.Pre.Data.MustClaimableBalance()
+			sponsor = change.Pre.SponsoringID()
 			preBalanceID, err := xdr.MarshalHex(cBalance.BalanceId)
 			if err != nil {
 				return fmt.Errorf("invalid balanceId in meta changes for op: %d", e.operation.index)
@@ -939,13 +1018,21 @@ func (e *effectsWrapper) addClaimClaimableBalanceEffects(changes []ingest.Change
 		return fmt.Errorf("change not found for balanceId : %s", balanceID)
 	}
 
+	source := e.operation.SourceAccount()
+	claimants, err := claimedBalanceClaimants(cBalance.Claimants, e.operation.ledgerClosed)
+	if err != nil {
+		return fmt.Errorf("could not evaluate the claimants of balance %s: %v", balanceID, err)
+	}
+
 	details := map[string]interface{}{
 		"amount":     amount.String(cBalance.Amount),
 		"balance_id": balanceID,
 		"asset":      cBalance.Asset.StringCanonical(),
+		"claimed_by": source.ToAccountId().Address(),
+		"claimants":  claimants,
 	}
 	setClaimableBalanceFlagDetails(details, cBalance.Flags())
-	source := e.operation.SourceAccount()
+	setClaimableBalanceSponsorDetails(details, sponsor)
 	e.addMuxed(
 		source,
 		EffectClaimableBalanceClaimed,
@@ -955,7 +1042,7 @@ func (e *effectsWrapper) addClaimClaimableBalanceEffects(changes []ingest.Change
 	details = map[string]interface{}{
 		"amount": amount.String(cBalance.Amount),
 	}
-	addAssetDetails(details, cBalance.Asset, "")
+	addAssetDetails(details, cBalance.Asset, "", e.operation.network)
 	e.addMuxed(
 		source,
 		EffectAccountCredited,
@@ -984,7 +1071,7 @@ func (e *effectsWrapper) addIngestTradeEffects(buyer xdr.MuxedAccount, claims []
 
 func (e *effectsWrapper) addClaimTradeEffects(buyer xdr.MuxedAccount, claim xdr.ClaimAtom, isPathPayment bool) {
 	seller := claim.SellerId()
-	bd, sd := tradeDetails(buyer, seller, claim)
+	bd, sd := tradeDetails(buyer, seller, claim, e.operation.network)
 
 	tradeEffects := []EffectType{
 		EffectTrade,
@@ -1039,7 +1126,7 @@ func (e *effectsWrapper) addClawbackEffects() error {
 		"amount": amount.String(op.Amount),
 	}
 	source := e.operation.SourceAccount()
-	addAssetDetails(details, op.Asset, "")
+	addAssetDetails(details, op.Asset, "", e.operation.network)
 
 	// The funds will be burned, but even with that, we generated an account credited effect
 	e.addMuxed(
@@ -1063,9 +1150,21 @@ func (e *effectsWrapper) addClawbackClaimableBalanceEffects(changes []ingest.Cha
 	if err != nil {
 		return errors.Wrapf(err, "Invalid balanceId in op %d", e.operation.index)
 	}
+	var removed *xdr.LedgerEntry
+	for _, c := range changes {
+		if c.Type == xdr.LedgerEntryTypeClaimableBalance && c.Post == nil && c.Pre != nil {
+			removed = c.Pre
+			break
+		}
+	}
+
 	details := map[string]interface{}{
 		"balance_id": balanceId,
 	}
+	if removed != nil {
+		details["claimants"] = transformClaimants(removed.Data.MustClaimableBalance().Claimants)
+		setClaimableBalanceSponsorDetails(details, removed.SponsoringID())
+	}
 	source := e.operation.SourceAccount()
 	e.addMuxed(
 		source,
@@ -1074,18 +1173,15 @@ func (e *effectsWrapper) addClawbackClaimableBalanceEffects(changes []ingest.Cha
 	)
 
 	// Generate the account credited effect (although the funds will be burned) for the asset issuer
-	for _, c := range changes {
-		if c.Type == xdr.LedgerEntryTypeClaimableBalance && c.Post == nil && c.Pre != nil {
-			cb := c.Pre.Data.ClaimableBalance
-			details = map[string]interface{}{"amount": amount.String(cb.Amount)}
-			addAssetDetails(details, cb.Asset, "")
-			e.addMuxed(
-				source,
-				EffectAccountCredited,
-				details,
-			)
-			break
-		}
+	if removed != nil {
+		cb := removed.Data.ClaimableBalance
+		details = map[string]interface{}{"amount": amount.String(cb.Amount)}
+		addAssetDetails(details, cb.Asset, "", e.operation.network)
+		e.addMuxed(
+			source,
+			EffectAccountCredited,
+			details,
+		)
 	}
 
 	return nil
@@ -1107,7 +1203,7 @@ func (e *effectsWrapper) addTrustLineFlagsEffect(
 	details := map[string]interface{}{
 		"trustor": trustor.Address(),
 	}
-	addAssetDetails(details, asset, "")
+	addAssetDetails(details, asset, "", e.operation.network)
 
 	var flagDetailsAdded bool
 	if setFlags != nil {
@@ -1152,12 +1248,13 @@ func (e *effectsWrapper) addLiquidityPoolRevokedEffect() error {
 		}
 		return err
 	}
-	changes, err := e.operation.transaction.GetOperationChanges(e.operation.index)
+	changes, err := e.operation.operationChanges()
 	if err != nil {
 		return err
 	}
 	assetToCBID := map[string]string{}
 	var cbs sortableClaimableBalanceEntries
+	sponsors := map[string]*xdr.AccountId{}
 	for _, change := range changes {
 		if change.Type == xdr.LedgerEntryTypeClaimableBalance && change.Pre == nil && change.Post != nil {
 			cb := change.Post.Data.ClaimableBalance
@@ -1166,6 +1263,7 @@ func (e *effectsWrapper) addLiquidityPoolRevokedEffect() error {
 				return err
 			}
 			assetToCBID[cb.Asset.StringCanonical()] = id
+			sponsors[id] = change.Post.SponsoringID()
 			cbs = append(cbs, cb)
 		}
 	}
@@ -1177,7 +1275,7 @@ func (e *effectsWrapper) addLiquidityPoolRevokedEffect() error {
 	// so that effects are ordered consistently
 	sort.Sort(cbs)
 	for _, cb := range cbs {
-		if err := e.addClaimableBalanceEntryCreatedEffects(source, cb); err != nil {
+		if err := e.addClaimableBalanceEntryCreatedEffects(source, cb, sponsors[assetToCBID[cb.Asset.StringCanonical()]]); err != nil {
 			return err
 		}
 	}
@@ -1226,15 +1324,15 @@ func setAuthFlagDetails(flagDetails map[string]interface{}, flags xdr.AccountFla
 	}
 }
 
-func tradeDetails(buyer xdr.MuxedAccount, seller xdr.AccountId, claim xdr.ClaimAtom) (bd map[string]interface{}, sd map[string]interface{}) {
+func tradeDetails(buyer xdr.MuxedAccount, seller xdr.AccountId, claim xdr.ClaimAtom, network string) (bd map[string]interface{}, sd map[string]interface{}) {
 	bd = map[string]interface{}{
 		"offer_id":      claim.OfferId(),
 		"seller":        seller.Address(),
 		"bought_amount": amount.String(claim.AmountSold()),
 		"sold_amount":   amount.String(claim.AmountBought()),
 	}
-	addAssetDetails(bd, claim.AssetSold(), "bought_")
-	addAssetDetails(bd, claim.AssetBought(), "sold_")
+	addAssetDetails(bd, claim.AssetSold(), "bought_", network)
+	addAssetDetails(bd, claim.AssetBought(), "sold_", network)
 
 	sd = map[string]interface{}{
 		"offer_id":      claim.OfferId(),
@@ -1242,8 +1340,8 @@ func tradeDetails(buyer xdr.MuxedAccount, seller xdr.AccountId, claim xdr.ClaimA
 		"sold_amount":   amount.String(claim.AmountSold()),
 	}
 	addAccountAndMuxedAccountDetails(sd, buyer, "seller")
-	addAssetDetails(sd, claim.AssetBought(), "bought_")
-	addAssetDetails(sd, claim.AssetSold(), "sold_")
+	addAssetDetails(sd, claim.AssetBought(), "bought_", network)
+	addAssetDetails(sd, claim.AssetSold(), "sold_", network)
 
 	return
 }
@@ -1316,23 +1414,30 @@ func (e *effectsWrapper) addLiquidityPoolWithdrawEffect() error {
 	return nil
 }
 
-// addInvokeHostFunctionEffects iterates through the events and generates
+// addInvokeHostFunctionEffects iterates through the events of the operation and generates
 // account_credited and account_debited effects when it sees events related to
-// the Stellar Asset Contract corresponding to those effects.
-func (e *effectsWrapper) addInvokeHostFunctionEffects(events []contractevents.Event) error {
+// the Stellar Asset Contract corresponding to those effects. The effects have the
+// id of their event in contract_event_id.
+func (e *effectsWrapper) addInvokeHostFunctionEffects(transactionEvents ingest.TransactionEvents) error {
 	if e.operation.network == "" {
 		return errors.New("invokeHostFunction effects cannot be determined unless network passphrase is set")
 	}
+	if int(e.operation.index) >= len(transactionEvents.OperationEvents) {
+		return nil
+	}
 
 	source := e.operation.SourceAccount()
-	for _, event := range events {
+	transactionID := toid.New(int32(e.operation.ledgerSequence), int32(e.operation.transaction.Index), 0).ToInt64()
+	firstEventIndex := operationEventIndex(transactionEvents, int(e.operation.index))
+	for i, event := range transactionEvents.OperationEvents[e.operation.index] {
 		evt, err := contractevents.NewStellarAssetContractEvent(&event, e.operation.network)
 		if err != nil {
 			continue // irrelevant or unsupported event
 		}
 
-		details := make(map[string]interface{}, 4)
-		addAssetDetails(details, evt.GetAsset(), "")
+		details := make(map[string]interface{}, 5)
+		addAssetDetails(details, evt.GetAsset(), "", e.operation.network)
+		details["contract_event_id"] = ContractEventID(transactionID, firstEventIndex+i)
 
 		//
 		// Note: We ignore effects that involve contracts (until the day we have
@@ -1436,7 +1541,7 @@ func (e *effectsWrapper) addExtendFootprintTtlEffect() error {
 	op := e.operation.operation.Body.MustExtendFootprintTtlOp()
 
 	// Figure out which entries were affected
-	changes, err := e.operation.transaction.GetOperationChanges(e.operation.index)
+	changes, err := e.operation.operationChanges()
 	if err != nil {
 		return err
 	}
@@ -1478,7 +1583,7 @@ func (e *effectsWrapper) addRestoreFootprintExpirationEffect() error {
 	op := e.operation.operation.Body.MustRestoreFootprintOp()
 
 	// Figure out which entries were affected
-	changes, err := e.operation.transaction.GetOperationChanges(e.operation.index)
+	changes, err := e.operation.operationChanges()
 	if err != nil {
 		return err
 	}
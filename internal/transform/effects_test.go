@@ -2275,6 +2275,107 @@ func TestOperationEffectsClawbackClaimableBalance(t *testing.T) {
 	tt.Equal(expected, effects)
 }
 
+func TestOperationEffectsClaimClaimableBalance(t *testing.T) {
+	tt := assert.New(t)
+	claimer := xdr.MustAddress("GDRW375MAYR46ODGF2WGANQC2RRZL7O246DYHHCGWTV2RE7IHE2QUQLD")
+	other := xdr.MustAddress("GAUJETIZVEP2NRYLUESJ3LS66NVCEGMON4UDCBCSBEVPIID773P2W6AY")
+	sponsor := xdr.MustAddress("GBVVRXLMNCJQW3IDDXC3X6XCH35B5Q7QXNMMFPENSOGUPQO7WO7HGZPA")
+	source := claimer.ToMuxedAccount()
+	balanceID := xdr.ClaimableBalanceId{Type: xdr.ClaimableBalanceIdTypeClaimableBalanceIdTypeV0, V0: &xdr.Hash{1}}
+	expired := xdr.Int64(genericCloseTime.Unix() - 1)
+
+	unconditional := xdr.ClaimPredicate{Type: xdr.ClaimPredicateTypeClaimPredicateUnconditional}
+	expiredPredicate := xdr.ClaimPredicate{Type: xdr.ClaimPredicateTypeClaimPredicateBeforeAbsoluteTime, AbsBefore: &expired}
+	balance := xdr.LedgerEntry{
+		Data: xdr.LedgerEntryData{
+			Type: xdr.LedgerEntryTypeClaimableBalance,
+			ClaimableBalance: &xdr.ClaimableBalanceEntry{
+				BalanceId: balanceID,
+				Claimants: []xdr.Claimant{
+					{Type: xdr.ClaimantTypeClaimantTypeV0, V0: &xdr.ClaimantV0{Destination: claimer, Predicate: unconditional}},
+					{Type: xdr.ClaimantTypeClaimantTypeV0, V0: &xdr.ClaimantV0{Destination: other, Predicate: expiredPredicate}},
+				},
+				Asset:  xdr.MustNewNativeAsset(),
+				Amount: 100000000,
+			},
+		},
+		Ext: xdr.LedgerEntryExt{V: 1, V1: &xdr.LedgerEntryExtensionV1{SponsoringId: &sponsor}},
+	}
+	balanceKey, err := balance.LedgerKey()
+	tt.NoError(err)
+
+	operation := transactionOperationWrapper{
+		index: 0,
+		transaction: ingest.LedgerTransaction{
+			UnsafeMeta: xdr.TransactionMeta{
+				V: 2,
+				V2: &xdr.TransactionMetaV2{Operations: []xdr.OperationMeta{{Changes: xdr.LedgerEntryChanges{
+					{Type: xdr.LedgerEntryChangeTypeLedgerEntryState, State: &balance},
+					{Type: xdr.LedgerEntryChangeTypeLedgerEntryRemoved, Removed: &balanceKey},
+				}}}},
+			},
+		},
+		operation: xdr.Operation{
+			SourceAccount: &source,
+			Body: xdr.OperationBody{
+				Type:                    xdr.OperationTypeClaimClaimableBalance,
+				ClaimClaimableBalanceOp: &xdr.ClaimClaimableBalanceOp{BalanceId: balanceID},
+			},
+		},
+		ledgerSequence: 1,
+		ledgerClosed:   genericCloseTime.UTC(),
+	}
+
+	effects, err := operation.effects()
+	tt.NoError(err)
+	// The claimed balance is followed by the credit of its amount and the removal of its sponsorship
+	tt.Len(effects, 3)
+	tt.Equal(EffectTypeNames[EffectClaimableBalanceClaimed], effects[0].TypeString)
+	tt.Equal(map[string]interface{}{
+		"amount":     "10.0000000",
+		"asset":      "native",
+		"balance_id": "000000000100000000000000000000000000000000000000000000000000000000000000",
+		"claimed_by": claimer.Address(),
+		"sponsor":    sponsor.Address(),
+		"claimants": []claimedBalanceClaimant{
+			{Destination: claimer.Address(), Predicate: unconditional, PredicateSatisfied: true},
+			{Destination: other.Address(), Predicate: expiredPredicate, PredicateSatisfied: false},
+		},
+	}, effects[0].Details)
+}
+
+func TestClaimPredicateSatisfied(t *testing.T) {
+	closedAt := time.Unix(1000, 0)
+	before := func(seconds int64) xdr.ClaimPredicate {
+		absBefore := xdr.Int64(seconds)
+		return xdr.ClaimPredicate{Type: xdr.ClaimPredicateTypeClaimPredicateBeforeAbsoluteTime, AbsBefore: &absBefore}
+	}
+	not := func(predicate xdr.ClaimPredicate) xdr.ClaimPredicate {
+		inner := &predicate
+		return xdr.ClaimPredicate{Type: xdr.ClaimPredicateTypeClaimPredicateNot, NotPredicate: &inner}
+	}
+
+	for _, test := range []struct {
+		predicate xdr.ClaimPredicate
+		satisfied bool
+	}{
+		{xdr.ClaimPredicate{Type: xdr.ClaimPredicateTypeClaimPredicateUnconditional}, true},
+		{before(1001), true},
+		{before(1000), false},
+		{not(before(1000)), true},
+		{xdr.ClaimPredicate{Type: xdr.ClaimPredicateTypeClaimPredicateAnd, AndPredicates: &[]xdr.ClaimPredicate{before(2000), not(before(500))}}, true},
+		{xdr.ClaimPredicate{Type: xdr.ClaimPredicateTypeClaimPredicateOr, OrPredicates: &[]xdr.ClaimPredicate{before(500), before(900)}}, false},
+	} {
+		satisfied, err := claimPredicateSatisfied(test.predicate, closedAt)
+		assert.NoError(t, err)
+		assert.Equal(t, test.satisfied, satisfied)
+	}
+
+	relBefore := xdr.Int64(10)
+	_, err := claimPredicateSatisfied(xdr.ClaimPredicate{Type: xdr.ClaimPredicateTypeClaimPredicateBeforeRelativeTime, RelBefore: &relBefore}, closedAt)
+	assert.Error(t, err)
+}
+
 func TestOperationEffectsSetTrustLineFlags(t *testing.T) {
 	tt := assert.New(t)
 	aid := xdr.MustAddress("GDRW375MAYR46ODGF2WGANQC2RRZL7O246DYHHCGWTV2RE7IHE2QUQLD")
@@ -3129,6 +3230,10 @@ func TestLiquidityPoolEffects(t *testing.T) {
 						"amount":     "0.0000100",
 						"asset":      "USD:GAUJETIZVEP2NRYLUESJ3LS66NVCEGMON4UDCBCSBEVPIID773P2W6AY",
 						"balance_id": "000000000a0b000000000000000000000000000000000000000000000000000000000000",
+						"claimants": []Claimant{{
+							Destination: "GAUJETIZVEP2NRYLUESJ3LS66NVCEGMON4UDCBCSBEVPIID773P2W6AY",
+							Predicate:   xdr.ClaimPredicate{Type: xdr.ClaimPredicateTypeClaimPredicateUnconditional},
+						}},
 					},
 					LedgerClosed:   genericCloseTime.UTC(),
 					LedgerSequence: 1,
@@ -3510,6 +3615,10 @@ func TestInvokeHostFunctionEffects(t *testing.T) {
 	admin := randAddr()
 	asset := xdr.MustNewCreditAsset("TESTER", admin)
 	nativeAsset := xdr.MustNewNativeAsset()
+	assetContractID, err := utils.AssetContractID(asset, networkPassphrase)
+	assert.NoError(t, err)
+	nativeContractID, err := utils.AssetContractID(nativeAsset, networkPassphrase)
+	assert.NoError(t, err)
 	from, to := randAddr(), randAddr()
 	fromContractBytes, toContractBytes := xdr.Hash{}, xdr.Hash{1}
 	fromContract := strkey.MustEncode(strkey.VersionByteContract, fromContractBytes[:])
@@ -3538,6 +3647,7 @@ func TestInvokeHostFunctionEffects(t *testing.T) {
 						"amount":              "0.0012345",
 						"asset_code":          strings.Trim(asset.GetCode(), "\x00"),
 						"asset_issuer":        asset.GetIssuer(),
+						"asset_contract_id":   assetContractID,
 						"asset_type":          "credit_alphanum12",
 						"contract_event_type": "transfer",
 					},
@@ -3552,6 +3662,7 @@ func TestInvokeHostFunctionEffects(t *testing.T) {
 						"amount":              "0.0012345",
 						"asset_code":          strings.Trim(asset.GetCode(), "\x00"),
 						"asset_issuer":        asset.GetIssuer(),
+						"asset_contract_id":   assetContractID,
 						"asset_type":          "credit_alphanum12",
 						"contract_event_type": "transfer",
 					},
@@ -3575,6 +3686,7 @@ func TestInvokeHostFunctionEffects(t *testing.T) {
 						"amount":              "0.0012345",
 						"asset_code":          strings.Trim(asset.GetCode(), "\x00"),
 						"asset_issuer":        asset.GetIssuer(),
+						"asset_contract_id":   assetContractID,
 						"asset_type":          "credit_alphanum12",
 						"contract":            fromContract,
 						"contract_event_type": "transfer",
@@ -3590,6 +3702,7 @@ func TestInvokeHostFunctionEffects(t *testing.T) {
 						"amount":              "0.0012345",
 						"asset_code":          strings.Trim(asset.GetCode(), "\x00"),
 						"asset_issuer":        asset.GetIssuer(),
+						"asset_contract_id":   assetContractID,
 						"asset_type":          "credit_alphanum12",
 						"contract":            toContract,
 						"contract_event_type": "transfer",
@@ -3612,6 +3725,7 @@ func TestInvokeHostFunctionEffects(t *testing.T) {
 						"amount":              "0.0012345",
 						"asset_code":          strings.Trim(asset.GetCode(), "\x00"),
 						"asset_issuer":        asset.GetIssuer(),
+						"asset_contract_id":   assetContractID,
 						"asset_type":          "credit_alphanum12",
 						"contract_event_type": "mint",
 					},
@@ -3633,6 +3747,7 @@ func TestInvokeHostFunctionEffects(t *testing.T) {
 						"amount":              "0.0012345",
 						"asset_code":          strings.Trim(asset.GetCode(), "\x00"),
 						"asset_issuer":        asset.GetIssuer(),
+						"asset_contract_id":   assetContractID,
 						"asset_type":          "credit_alphanum12",
 						"contract_event_type": "burn",
 					},
@@ -3655,6 +3770,7 @@ func TestInvokeHostFunctionEffects(t *testing.T) {
 						"amount":              "0.0012345",
 						"asset_code":          strings.Trim(asset.GetCode(), "\x00"),
 						"asset_issuer":        asset.GetIssuer(),
+						"asset_contract_id":   assetContractID,
 						"asset_type":          "credit_alphanum12",
 						"contract":            fromContract,
 						"contract_event_type": "burn",
@@ -3677,6 +3793,7 @@ func TestInvokeHostFunctionEffects(t *testing.T) {
 						"amount":              "0.0012345",
 						"asset_code":          strings.Trim(asset.GetCode(), "\x00"),
 						"asset_issuer":        asset.GetIssuer(),
+						"asset_contract_id":   assetContractID,
 						"asset_type":          "credit_alphanum12",
 						"contract_event_type": "clawback",
 					},
@@ -3699,6 +3816,7 @@ func TestInvokeHostFunctionEffects(t *testing.T) {
 						"amount":              "0.0012345",
 						"asset_code":          strings.Trim(asset.GetCode(), "\x00"),
 						"asset_issuer":        asset.GetIssuer(),
+						"asset_contract_id":   assetContractID,
 						"asset_type":          "credit_alphanum12",
 						"contract":            fromContract,
 						"contract_event_type": "clawback",
@@ -3719,6 +3837,7 @@ func TestInvokeHostFunctionEffects(t *testing.T) {
 					OperationID: toid.New(1, 0, 1).ToInt64(),
 					Details: map[string]interface{}{
 						"amount":              "0.0012345",
+						"asset_contract_id":   nativeContractID,
 						"asset_type":          "native",
 						"contract_event_type": "transfer",
 					},
@@ -3731,6 +3850,7 @@ func TestInvokeHostFunctionEffects(t *testing.T) {
 					OperationID: toid.New(1, 0, 1).ToInt64(),
 					Details: map[string]interface{}{
 						"amount":              "0.0012345",
+						"asset_contract_id":   nativeContractID,
 						"asset_type":          "native",
 						"contract_event_type": "transfer",
 					},
@@ -3753,6 +3873,7 @@ func TestInvokeHostFunctionEffects(t *testing.T) {
 						"amount":              "0.0012345",
 						"asset_code":          strings.Trim(asset.GetCode(), "\x00"),
 						"asset_issuer":        asset.GetIssuer(),
+						"asset_contract_id":   assetContractID,
 						"asset_type":          "credit_alphanum12",
 						"contract_event_type": "transfer",
 					},
@@ -3767,6 +3888,7 @@ func TestInvokeHostFunctionEffects(t *testing.T) {
 						"amount":              "0.0012345",
 						"asset_code":          strings.Trim(asset.GetCode(), "\x00"),
 						"asset_issuer":        asset.GetIssuer(),
+						"asset_contract_id":   assetContractID,
 						"asset_type":          "credit_alphanum12",
 						"contract":            toContract,
 						"contract_event_type": "transfer",
@@ -3790,6 +3912,7 @@ func TestInvokeHostFunctionEffects(t *testing.T) {
 						"amount":              "0.0012345",
 						"asset_code":          strings.Trim(asset.GetCode(), "\x00"),
 						"asset_issuer":        asset.GetIssuer(),
+						"asset_contract_id":   assetContractID,
 						"asset_type":          "credit_alphanum12",
 						"contract":            fromContract,
 						"contract_event_type": "transfer",
@@ -3805,6 +3928,7 @@ func TestInvokeHostFunctionEffects(t *testing.T) {
 						"amount":              "0.0012345",
 						"asset_code":          strings.Trim(asset.GetCode(), "\x00"),
 						"asset_issuer":        asset.GetIssuer(),
+						"asset_contract_id":   assetContractID,
 						"asset_type":          "credit_alphanum12",
 						"contract_event_type": "transfer",
 					},
@@ -3851,6 +3975,8 @@ func TestInvokeHostFunctionEffects(t *testing.T) {
 			for i := range testCase.expected {
 				testCase.expected[i].EffectIndex = uint32(i)
 				testCase.expected[i].EffectId = fmt.Sprintf("%d-%d", testCase.expected[i].OperationID, testCase.expected[i].EffectIndex)
+				// Every test case has a single event, so its effects all come from the first event of the transaction
+				testCase.expected[i].Details["contract_event_id"] = ContractEventID(toid.New(1, 0, 0).ToInt64(), 0)
 			}
 
 			effects, err := operation.effects()
@@ -0,0 +1,118 @@
+package transform
+
+import (
+	"fmt"
+
+	"github.com/stellar/go-stellar-sdk/ingest"
+	"github.com/stellar/go-stellar-sdk/strkey"
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stellar/stellar-etl/v2/internal/toid"
+	"github.com/stellar/stellar-etl/v2/internal/utils"
+)
+
+const (
+	footprintAccessReadOnly  = "read_only"
+	footprintAccessReadWrite = "read_write"
+)
+
+// TransformFootprint converts the footprint of a soroban transaction into one record for every ledger key it declares,
+// with the key decoded into its type and identifying fields
+func TransformFootprint(transaction ingest.LedgerTransaction, lhe xdr.LedgerHeaderHistoryEntry) ([]FootprintOutput, error) {
+	sorobanData, ok := transaction.GetSorobanData()
+	if !ok {
+		return nil, nil
+	}
+
+	ledgerHeader := lhe.Header
+	outputTransactionHash := utils.HashToHexString(transaction.Result.TransactionHash)
+	outputLedgerSequence := uint32(ledgerHeader.LedgerSeq)
+	outputTransactionID := toid.New(int32(outputLedgerSequence), int32(transaction.Index), 0).ToInt64()
+
+	closedAt, err := utils.TimePointToUTCTimeStamp(ledgerHeader.ScpValue.CloseTime)
+	if err != nil {
+		return nil, err
+	}
+
+	footprint := sorobanData.Resources.Footprint
+	accesses := []struct {
+		access string
+		keys   []xdr.LedgerKey
+	}{
+		{footprintAccessReadOnly, footprint.ReadOnly},
+		{footprintAccessReadWrite, footprint.ReadWrite},
+	}
+
+	transformedFootprint := []FootprintOutput{}
+	for _, access := range accesses {
+		for _, ledgerKey := range access.keys {
+			footprintKey, err := decodeFootprintKey(ledgerKey)
+			if err != nil {
+				return nil, fmt.Errorf("for ledger %d; transaction %d (transaction id=%d): %v", outputLedgerSequence, transaction.Index, outputTransactionID, err)
+			}
+
+			footprintKey.TransactionHash = outputTransactionHash
+			footprintKey.TransactionID = outputTransactionID
+			footprintKey.Successful = transaction.Successful()
+			footprintKey.Access = access.access
+			footprintKey.ClosedAt = closedAt
+			footprintKey.LedgerSequence = outputLedgerSequence
+			transformedFootprint = append(transformedFootprint, footprintKey)
+		}
+	}
+
+	return transformedFootprint, nil
+}
+
+// decodeFootprintKey fills the key fields of a footprint record from a ledger key. Soroban footprints can only hold
+// account, trustline, contract data and contract code keys.
+func decodeFootprintKey(ledgerKey xdr.LedgerKey) (FootprintOutput, error) {
+	ledgerKeyBase64, err := xdr.MarshalBase64(ledgerKey)
+	if err != nil {
+		return FootprintOutput{}, err
+	}
+
+	footprintKey := FootprintOutput{
+		LedgerKeyHash:       utils.LedgerKeyToLedgerKeyHash(ledgerKey),
+		LedgerKeyHashBase64: ledgerKeyBase64,
+		LedgerEntryType:     ledgerKey.Type.String(),
+	}
+
+	switch ledgerKey.Type {
+	case xdr.LedgerEntryTypeAccount:
+		footprintKey.AccountID, err = ledgerKey.Account.AccountId.GetAddress()
+		if err != nil {
+			return FootprintOutput{}, err
+		}
+	case xdr.LedgerEntryTypeTrustline:
+		footprintKey.AccountID, err = ledgerKey.TrustLine.AccountId.GetAddress()
+		if err != nil {
+			return FootprintOutput{}, err
+		}
+
+		asset := ledgerKey.TrustLine.Asset
+		if asset.Type == xdr.AssetTypeAssetTypePoolShare {
+			footprintKey.AssetType = "pool_share"
+			footprintKey.LiquidityPoolID = PoolIDToString(asset.MustLiquidityPoolId())
+		} else if err = asset.Extract(&footprintKey.AssetType, &footprintKey.AssetCode, &footprintKey.AssetIssuer); err != nil {
+			return FootprintOutput{}, err
+		}
+	case xdr.LedgerEntryTypeContractData:
+		contractData := ledgerKey.ContractData
+		contractId, ok := contractData.Contract.GetContractId()
+		if !ok {
+			return FootprintOutput{}, fmt.Errorf("could not extract contractId from contract data key")
+		}
+		footprintKey.ContractId, err = strkey.Encode(strkey.VersionByteContract, contractId[:])
+		if err != nil {
+			return FootprintOutput{}, err
+		}
+		footprintKey.ContractKeyType = contractData.Key.Type.String()
+		footprintKey.ContractDurability = contractData.Durability.String()
+	case xdr.LedgerEntryTypeContractCode:
+		footprintKey.ContractCodeHash = utils.HashToHexString(ledgerKey.ContractCode.Hash)
+	default:
+		return FootprintOutput{}, fmt.Errorf("unexpected ledger key type %s in soroban footprint", ledgerKey.Type)
+	}
+
+	return footprintKey, nil
+}
@@ -0,0 +1,58 @@
+package transform
+
+import (
+	"fmt"
+
+	"github.com/stellar/go-stellar-sdk/ingest"
+	"github.com/stellar/go-stellar-sdk/strkey"
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stellar/stellar-etl/v2/internal/utils"
+)
+
+// TransformFootprintReads converts the read-only footprint of a soroban transaction into read records, one for every
+// ledger entry the transaction touched without changing it
+func TransformFootprintReads(transaction ingest.LedgerTransaction, lhe xdr.LedgerHeaderHistoryEntry) ([]FootprintReadOutput, error) {
+	sorobanData, ok := transaction.GetSorobanData()
+	if !ok {
+		return nil, nil
+	}
+
+	closedAt, err := utils.TimePointToUTCTimeStamp(lhe.Header.ScpValue.CloseTime)
+	if err != nil {
+		return nil, err
+	}
+
+	outputTransactionHash := utils.HashToHexString(transaction.Result.TransactionHash)
+	ledgerSequence := uint32(lhe.Header.LedgerSeq)
+
+	reads := []FootprintReadOutput{}
+	for _, ledgerKey := range sorobanData.Resources.Footprint.ReadOnly {
+		ledgerKeyBase64, err := xdr.MarshalBase64(ledgerKey)
+		if err != nil {
+			return nil, fmt.Errorf("could not encode ledger key read by transaction %s: %v", outputTransactionHash, err)
+		}
+
+		var contractId string
+		if ledgerKey.Type == xdr.LedgerEntryTypeContractData {
+			if id, ok := ledgerKey.ContractData.Contract.GetContractId(); ok {
+				contractId, err = strkey.Encode(strkey.VersionByteContract, id[:])
+				if err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		reads = append(reads, FootprintReadOutput{
+			LedgerKeyHash:       utils.LedgerKeyToLedgerKeyHash(ledgerKey),
+			LedgerKeyHashBase64: ledgerKeyBase64,
+			LedgerEntryType:     ledgerKey.Type.String(),
+			ContractId:          contractId,
+			TransactionHash:     outputTransactionHash,
+			Successful:          transaction.Successful(),
+			ClosedAt:            closedAt,
+			LedgerSequence:      ledgerSequence,
+		})
+	}
+
+	return reads, nil
+}
@@ -0,0 +1,120 @@
+package transform
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stellar/go-stellar-sdk/ingest"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+func TestTransformFootprintReads(t *testing.T) {
+	header := xdr.LedgerHeaderHistoryEntry{
+		Header: xdr.LedgerHeader{
+			ScpValue: xdr.StellarValue{
+				CloseTime: 1000,
+			},
+			LedgerSeq: 10,
+		},
+	}
+
+	actualOutput, actualError := TransformFootprintReads(makeFootprintReadTestInput(), header)
+	assert.NoError(t, actualError)
+	assert.Equal(t, makeFootprintReadTestOutput(), actualOutput)
+
+	classicTransaction := ingest.LedgerTransaction{
+		Envelope: xdr.TransactionEnvelope{
+			Type: xdr.EnvelopeTypeEnvelopeTypeTx,
+			V1:   &xdr.TransactionV1Envelope{},
+		},
+	}
+	actualOutput, actualError = TransformFootprintReads(classicTransaction, header)
+	assert.NoError(t, actualError)
+	assert.Empty(t, actualOutput)
+}
+
+func makeFootprintReadTestInput() ingest.LedgerTransaction {
+	contractId := xdr.ContractId{1, 2, 3}
+	return ingest.LedgerTransaction{
+		Envelope: xdr.TransactionEnvelope{
+			Type: xdr.EnvelopeTypeEnvelopeTypeTx,
+			V1: &xdr.TransactionV1Envelope{
+				Tx: xdr.Transaction{
+					Ext: xdr.TransactionExt{
+						V: 1,
+						SorobanData: &xdr.SorobanTransactionData{
+							Resources: xdr.SorobanResources{
+								Footprint: xdr.LedgerFootprint{
+									ReadOnly: []xdr.LedgerKey{
+										{
+											Type: xdr.LedgerEntryTypeContractData,
+											ContractData: &xdr.LedgerKeyContractData{
+												Contract: xdr.ScAddress{
+													Type:       xdr.ScAddressTypeScAddressTypeContract,
+													ContractId: &contractId,
+												},
+												Key: xdr.ScVal{
+													Type: xdr.ScValTypeScvLedgerKeyContractInstance,
+												},
+												Durability: xdr.ContractDataDurabilityPersistent,
+											},
+										},
+										{
+											Type: xdr.LedgerEntryTypeContractCode,
+											ContractCode: &xdr.LedgerKeyContractCode{
+												Hash: xdr.Hash{4, 5, 6},
+											},
+										},
+									},
+									ReadWrite: []xdr.LedgerKey{
+										{
+											Type: xdr.LedgerEntryTypeAccount,
+											Account: &xdr.LedgerKeyAccount{
+												AccountId: testAccount1ID,
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		Result: xdr.TransactionResultPair{
+			TransactionHash: xdr.Hash{7, 8, 9},
+			Result: xdr.TransactionResult{
+				Result: xdr.TransactionResultResult{
+					Code: xdr.TransactionResultCodeTxSuccess,
+				},
+			},
+		},
+	}
+}
+
+func makeFootprintReadTestOutput() []FootprintReadOutput {
+	return []FootprintReadOutput{
+		{
+			LedgerKeyHash:       "c74e644e72137ad7ccab5f4265db185e075f6d7f07255353da086ee3da0b06c3",
+			LedgerKeyHashBase64: "AAAABgAAAAEBAgMAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAABQAAAAB",
+			LedgerEntryType:     "LedgerEntryTypeContractData",
+			ContractId:          "CAAQEAYAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAABXAS",
+			TransactionHash:     "0708090000000000000000000000000000000000000000000000000000000000",
+			Successful:          true,
+			ClosedAt:            time.Date(1970, time.January, 1, 0, 16, 40, 0, time.UTC),
+			LedgerSequence:      10,
+		},
+		{
+			LedgerKeyHash:       "8dff10f772999940d867a653f238c7c85af80a9d43a1bc541614c054a76872f7",
+			LedgerKeyHashBase64: "AAAABwQFBgAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA",
+			LedgerEntryType:     "LedgerEntryTypeContractCode",
+			ContractId:          "",
+			TransactionHash:     "0708090000000000000000000000000000000000000000000000000000000000",
+			Successful:          true,
+			ClosedAt:            time.Date(1970, time.January, 1, 0, 16, 40, 0, time.UTC),
+			LedgerSequence:      10,
+		},
+	}
+}
@@ -0,0 +1,133 @@
+package transform
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stellar/go-stellar-sdk/ingest"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+func TestTransformFootprint(t *testing.T) {
+	header := xdr.LedgerHeaderHistoryEntry{
+		Header: xdr.LedgerHeader{
+			ScpValue: xdr.StellarValue{
+				CloseTime: 1000,
+			},
+			LedgerSeq: 10,
+		},
+	}
+
+	actualOutput, actualError := TransformFootprint(makeFootprintTestInput(), header)
+	assert.NoError(t, actualError)
+	assert.Equal(t, makeFootprintTestOutput(), actualOutput)
+}
+
+func makeFootprintTestInput() ingest.LedgerTransaction {
+	contractId := xdr.ContractId{1, 2, 3}
+	return ingest.LedgerTransaction{
+		Index: 1,
+		Envelope: xdr.TransactionEnvelope{
+			Type: xdr.EnvelopeTypeEnvelopeTypeTx,
+			V1: &xdr.TransactionV1Envelope{
+				Tx: xdr.Transaction{
+					Ext: xdr.TransactionExt{
+						V: 1,
+						SorobanData: &xdr.SorobanTransactionData{
+							Resources: xdr.SorobanResources{
+								Footprint: xdr.LedgerFootprint{
+									ReadOnly: []xdr.LedgerKey{
+										{
+											Type: xdr.LedgerEntryTypeContractCode,
+											ContractCode: &xdr.LedgerKeyContractCode{
+												Hash: xdr.Hash{4, 5, 6},
+											},
+										},
+									},
+									ReadWrite: []xdr.LedgerKey{
+										{
+											Type: xdr.LedgerEntryTypeContractData,
+											ContractData: &xdr.LedgerKeyContractData{
+												Contract: xdr.ScAddress{
+													Type:       xdr.ScAddressTypeScAddressTypeContract,
+													ContractId: &contractId,
+												},
+												Key: xdr.ScVal{
+													Type: xdr.ScValTypeScvLedgerKeyContractInstance,
+												},
+												Durability: xdr.ContractDataDurabilityPersistent,
+											},
+										},
+										{
+											Type: xdr.LedgerEntryTypeTrustline,
+											TrustLine: &xdr.LedgerKeyTrustLine{
+												AccountId: testAccount1ID,
+												Asset:     ethTrustLineAsset,
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		Result: xdr.TransactionResultPair{
+			TransactionHash: xdr.Hash{7, 8, 9},
+			Result: xdr.TransactionResult{
+				Result: xdr.TransactionResultResult{
+					Code: xdr.TransactionResultCodeTxFailed,
+				},
+			},
+		},
+	}
+}
+
+func makeFootprintTestOutput() []FootprintOutput {
+	return []FootprintOutput{
+		{
+			TransactionHash:     "0708090000000000000000000000000000000000000000000000000000000000",
+			TransactionID:       42949677056,
+			Successful:          false,
+			Access:              "read_only",
+			LedgerKeyHash:       "8dff10f772999940d867a653f238c7c85af80a9d43a1bc541614c054a76872f7",
+			LedgerKeyHashBase64: "AAAABwQFBgAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA",
+			LedgerEntryType:     "LedgerEntryTypeContractCode",
+			ContractCodeHash:    "0405060000000000000000000000000000000000000000000000000000000000",
+			ClosedAt:            time.Date(1970, time.January, 1, 0, 16, 40, 0, time.UTC),
+			LedgerSequence:      10,
+		},
+		{
+			TransactionHash:     "0708090000000000000000000000000000000000000000000000000000000000",
+			TransactionID:       42949677056,
+			Successful:          false,
+			Access:              "read_write",
+			LedgerKeyHash:       "c74e644e72137ad7ccab5f4265db185e075f6d7f07255353da086ee3da0b06c3",
+			LedgerKeyHashBase64: "AAAABgAAAAEBAgMAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAABQAAAAB",
+			LedgerEntryType:     "LedgerEntryTypeContractData",
+			ContractId:          "CAAQEAYAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAABXAS",
+			ContractKeyType:     "ScValTypeScvLedgerKeyContractInstance",
+			ContractDurability:  "ContractDataDurabilityPersistent",
+			ClosedAt:            time.Date(1970, time.January, 1, 0, 16, 40, 0, time.UTC),
+			LedgerSequence:      10,
+		},
+		{
+			TransactionHash:     "0708090000000000000000000000000000000000000000000000000000000000",
+			TransactionID:       42949677056,
+			Successful:          false,
+			Access:              "read_write",
+			LedgerKeyHash:       "1d6d13752448051fae239bcb1f0fa0967243e7870ab07f29e34c1a1ab3f82813",
+			LedgerKeyHashBase64: "AAAAAQAAAACI4aa0pXFSj6qfJuIObLw/5zyugLRGYwxb7wFSr3B9eAAAAAFFVEgAAAAAAGfMAIZMO4kWjGqv4Lw0cJ7QIcUFcuL5iGE0IggsIily",
+			LedgerEntryType:     "LedgerEntryTypeTrustline",
+			AccountID:           testAccount1Address,
+			AssetType:           "credit_alphanum4",
+			AssetCode:           "ETH",
+			AssetIssuer:         testAccount3Address,
+			ClosedAt:            time.Date(1970, time.January, 1, 0, 16, 40, 0, time.UTC),
+			LedgerSequence:      10,
+		},
+	}
+}
@@ -0,0 +1,71 @@
+package transform
+
+import (
+	"fmt"
+
+	"github.com/stellar/go-stellar-sdk/ingest"
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stellar/stellar-etl/v2/internal/toid"
+	"github.com/stellar/stellar-etl/v2/internal/utils"
+)
+
+// TransformInflationPayouts converts the payouts of a successful inflation operation into a form suitable for BigQuery.
+// Operations that are not inflation operations, or that did not succeed, have no payouts.
+func TransformInflationPayouts(operation xdr.Operation, operationIndex int32, transaction ingest.LedgerTransaction, ledgerSeq int32, ledgerCloseMeta xdr.LedgerCloseMeta) ([]InflationPayoutOutput, error) {
+	if operation.Body.Type != xdr.OperationTypeInflation || !transaction.Result.Successful() {
+		return nil, nil
+	}
+
+	outputTransactionID := toid.New(ledgerSeq, int32(transaction.Index), 0).ToInt64()
+	outputOperationID := toid.New(ledgerSeq, int32(transaction.Index), operationIndex+1).ToInt64() //operationIndex needs +1 increment to stay in sync with ingest package
+
+	operationResults, ok := transaction.Result.OperationResults()
+	if !ok || int(operationIndex) >= len(operationResults) {
+		return []InflationPayoutOutput{}, fmt.Errorf("could not get the result of operation %d (operation id=%d)", operationIndex, outputOperationID)
+	}
+
+	operationResultTr, ok := operationResults[operationIndex].GetTr()
+	if !ok {
+		return []InflationPayoutOutput{}, fmt.Errorf("could not get the result of operation %d (operation id=%d)", operationIndex, outputOperationID)
+	}
+
+	inflationResult, ok := operationResultTr.GetInflationResult()
+	if !ok {
+		return []InflationPayoutOutput{}, fmt.Errorf("could not get the inflation result of operation %d (operation id=%d)", operationIndex, outputOperationID)
+	}
+
+	payouts, ok := inflationResult.GetPayouts()
+	if !ok {
+		return nil, nil
+	}
+
+	outputCloseTime, err := utils.GetCloseTime(ledgerCloseMeta)
+	if err != nil {
+		return []InflationPayoutOutput{}, err
+	}
+
+	var transformedPayouts []InflationPayoutOutput
+	for i, payout := range payouts {
+		outputDestination, err := payout.Destination.GetAddress()
+		if err != nil {
+			return []InflationPayoutOutput{}, fmt.Errorf("could not get the destination of payout %d for operation %d (operation id=%d): %v", i, operationIndex, outputOperationID, err)
+		}
+
+		if payout.Amount < 0 {
+			return []InflationPayoutOutput{}, fmt.Errorf("amount is negative (%d) for payout %d of operation %d (operation id=%d)", payout.Amount, i, operationIndex, outputOperationID)
+		}
+
+		transformedPayouts = append(transformedPayouts, InflationPayoutOutput{
+			LedgerSequence:  uint32(ledgerSeq),
+			ClosedAt:        outputCloseTime,
+			TransactionHash: utils.HashToHexString(transaction.Result.TransactionHash),
+			TransactionID:   outputTransactionID,
+			OperationID:     outputOperationID,
+			PayoutIndex:     int32(i),
+			Destination:     outputDestination,
+			Amount:          utils.ConvertStroopValueToReal(payout.Amount),
+		})
+	}
+
+	return transformedPayouts, nil
+}
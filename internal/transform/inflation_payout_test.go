@@ -0,0 +1,147 @@
+package transform
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stellar/go-stellar-sdk/ingest"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+func TestTransformInflationPayouts(t *testing.T) {
+	type transformTest struct {
+		operation   xdr.Operation
+		transaction ingest.LedgerTransaction
+		wantOutput  []InflationPayoutOutput
+		wantErr     error
+	}
+
+	inflationOperation := xdr.Operation{
+		Body: xdr.OperationBody{
+			Type: xdr.OperationTypeInflation,
+		},
+	}
+
+	failedTransaction := makeInflationPayoutsTestTransaction()
+	failedTransaction.Result.Result.Result.Code = xdr.TransactionResultCodeTxFailed
+
+	tests := []transformTest{
+		{
+			genericBumpOperation,
+			makeInflationPayoutsTestTransaction(),
+			nil,
+			nil,
+		},
+		{
+			inflationOperation,
+			failedTransaction,
+			nil,
+			nil,
+		},
+		{
+			inflationOperation,
+			makeInflationPayoutsTestTransaction(),
+			makeInflationPayoutsTestOutput(),
+			nil,
+		},
+	}
+
+	for _, test := range tests {
+		actualOutput, actualError := TransformInflationPayouts(test.operation, 0, test.transaction, 30, makeInflationPayoutsTestLedgerCloseMeta())
+		assert.Equal(t, test.wantErr, actualError)
+		assert.Equal(t, test.wantOutput, actualOutput)
+	}
+}
+
+func makeInflationPayoutsTestLedgerCloseMeta() xdr.LedgerCloseMeta {
+	return xdr.LedgerCloseMeta{
+		V: 0,
+		V0: &xdr.LedgerCloseMetaV0{
+			LedgerHeader: xdr.LedgerHeaderHistoryEntry{
+				Header: xdr.LedgerHeader{
+					ScpValue: xdr.StellarValue{
+						CloseTime: 1000,
+					},
+					LedgerSeq: 30,
+				},
+			},
+		},
+	}
+}
+
+func makeInflationPayoutsTestTransaction() ingest.LedgerTransaction {
+	return ingest.LedgerTransaction{
+		Index: 1,
+		Envelope: xdr.TransactionEnvelope{
+			Type: xdr.EnvelopeTypeEnvelopeTypeTx,
+			V1: &xdr.TransactionV1Envelope{
+				Tx: xdr.Transaction{
+					SourceAccount: testAccount1,
+					Operations: []xdr.Operation{
+						{
+							Body: xdr.OperationBody{
+								Type: xdr.OperationTypeInflation,
+							},
+						},
+					},
+				},
+			},
+		},
+		Result: xdr.TransactionResultPair{
+			Result: xdr.TransactionResult{
+				Result: xdr.TransactionResultResult{
+					Code: xdr.TransactionResultCodeTxSuccess,
+					Results: &[]xdr.OperationResult{
+						{
+							Code: xdr.OperationResultCodeOpInner,
+							Tr: &xdr.OperationResultTr{
+								Type: xdr.OperationTypeInflation,
+								InflationResult: &xdr.InflationResult{
+									Code: xdr.InflationResultCodeInflationSuccess,
+									Payouts: &[]xdr.InflationPayout{
+										{
+											Destination: testAccount1ID,
+											Amount:      10000000,
+										},
+										{
+											Destination: testAccount4ID,
+											Amount:      25000000,
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func makeInflationPayoutsTestOutput() []InflationPayoutOutput {
+	closedAt := time.Date(1970, time.January, 1, 0, 16, 40, 0, time.UTC)
+	return []InflationPayoutOutput{
+		{
+			LedgerSequence:  30,
+			ClosedAt:        closedAt,
+			TransactionHash: "0000000000000000000000000000000000000000000000000000000000000000",
+			TransactionID:   128849022976,
+			OperationID:     128849022977,
+			PayoutIndex:     0,
+			Destination:     testAccount1Address,
+			Amount:          1,
+		},
+		{
+			LedgerSequence:  30,
+			ClosedAt:        closedAt,
+			TransactionHash: "0000000000000000000000000000000000000000000000000000000000000000",
+			TransactionID:   128849022976,
+			OperationID:     128849022977,
+			PayoutIndex:     1,
+			Destination:     testAccount4Address,
+			Amount:          2.5,
+		},
+	}
+}
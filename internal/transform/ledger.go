@@ -9,12 +9,15 @@ import (
 	"github.com/stellar/stellar-etl/v2/internal/utils"
 
 	"github.com/stellar/go-stellar-sdk/historyarchive"
+	"github.com/stellar/go-stellar-sdk/network"
 	"github.com/stellar/go-stellar-sdk/strkey"
 	"github.com/stellar/go-stellar-sdk/xdr"
 )
 
-// TransformLedger converts a ledger from the history archive ingestion system into a form suitable for BigQuery
-func TransformLedger(inputLedger historyarchive.Ledger, lcm xdr.LedgerCloseMeta) (LedgerOutput, error) {
+// TransformLedger converts a ledger from the history archive ingestion system into a form suitable for BigQuery. The
+// network passphrase is needed to hash the transactions of the transaction set, which are compared to the results to
+// tell if they were applied in a different order.
+func TransformLedger(inputLedger historyarchive.Ledger, lcm xdr.LedgerCloseMeta, networkPassphrase string) (LedgerOutput, error) {
 	ledgerHeader := inputLedger.Header.Header
 
 	outputSequence := uint32(ledgerHeader.LedgerSeq)
@@ -35,6 +38,18 @@ func TransformLedger(inputLedger historyarchive.Ledger, lcm xdr.LedgerCloseMeta)
 		return LedgerOutput{}, fmt.Errorf("for ledger %d (ledger id=%d): %v", outputSequence, outputLedgerID, err)
 	}
 
+	outputTxSetHash := utils.HashToHexString(ledgerHeader.ScpValue.TxSetHash)
+
+	outputClassicPhaseCount, outputSorobanPhaseCount, err := extractPhaseCounts(inputLedger)
+	if err != nil {
+		return LedgerOutput{}, fmt.Errorf("for ledger %d (ledger id=%d): %v", outputSequence, outputLedgerID, err)
+	}
+
+	outputApplyOrderDiffers, err := applyOrderDiffers(inputLedger, networkPassphrase)
+	if err != nil {
+		return LedgerOutput{}, fmt.Errorf("for ledger %d (ledger id=%d): %v", outputSequence, outputLedgerID, err)
+	}
+
 	outputCloseTime, err := utils.TimePointToUTCTimeStamp(ledgerHeader.ScpValue.CloseTime)
 	if err != nil {
 		return LedgerOutput{}, err
@@ -58,33 +73,17 @@ func TransformLedger(inputLedger historyarchive.Ledger, lcm xdr.LedgerCloseMeta)
 
 	outputProtocolVersion := uint32(ledgerHeader.LedgerVersion)
 
-	var outputSorobanFeeWrite1Kb int64
-	var outputTotalByteSizeOfLiveSorobanState uint64
+	sorobanState, err := utils.GetLedgerCloseMetaSorobanState(lcm)
+	if err != nil {
+		return LedgerOutput{}, err
+	}
+	outputSorobanFeeWrite1Kb := sorobanState.SorobanFeeWrite1Kb
+	outputTotalByteSizeOfLiveSorobanState := sorobanState.TotalByteSizeOfLiveSorobanState
+	// V0 metas predate eviction, so they have no evicted keys rather than an empty list
 	var outputEvictedKeysHash []string
 	var outputEvictedKeysType []string
-	lcmV1, ok := lcm.GetV1()
-	if ok {
-		extV1, ok := lcmV1.Ext.GetV1()
-		if ok {
-			outputSorobanFeeWrite1Kb = int64(extV1.SorobanFeeWrite1Kb)
-		}
-		totalByteSizeOfBucketList := lcmV1.TotalByteSizeOfLiveSorobanState
-		outputTotalByteSizeOfLiveSorobanState = uint64(totalByteSizeOfBucketList)
-		outputEvictedKeysHash, outputEvictedKeysType, err = transformLedgerKeys(lcmV1.EvictedKeys)
-		if err != nil {
-			return LedgerOutput{}, err
-		}
-	}
-
-	lcmV2, ok := lcm.GetV2()
-	if ok {
-		extV1, ok := lcmV2.Ext.GetV1()
-		if ok {
-			outputSorobanFeeWrite1Kb = int64(extV1.SorobanFeeWrite1Kb)
-		}
-		totalByteSizeOfBucketList := lcmV2.TotalByteSizeOfLiveSorobanState
-		outputTotalByteSizeOfLiveSorobanState = uint64(totalByteSizeOfBucketList)
-		outputEvictedKeysHash, outputEvictedKeysType, err = transformLedgerKeys(lcmV2.EvictedKeys)
+	if lcm.V != 0 {
+		outputEvictedKeysHash, outputEvictedKeysType, err = transformLedgerKeys(sorobanState.EvictedKeys)
 		if err != nil {
 			return LedgerOutput{}, err
 		}
@@ -113,6 +112,10 @@ func TransformLedger(inputLedger historyarchive.Ledger, lcm xdr.LedgerCloseMeta)
 		FailedTransactionCount:          outputFailedCount,
 		TxSetOperationCount:             outputTxSetOperationCount,
 		ClosedAt:                        outputCloseTime,
+		TxSetHash:                       outputTxSetHash,
+		ClassicPhaseTransactionCount:    outputClassicPhaseCount,
+		SorobanPhaseTransactionCount:    outputSorobanPhaseCount,
+		ApplyOrderDiffers:               outputApplyOrderDiffers,
 		TotalCoins:                      outputTotalCoins,
 		FeePool:                         outputFeePool,
 		BaseFee:                         outputBaseFee,
@@ -165,6 +168,53 @@ func extractCounts(ledger historyarchive.Ledger) (transactionCount int32, operat
 	return
 }
 
+// extractPhaseCounts counts the transactions of the classic and soroban phases of the transaction set. Transaction sets
+// from before generalized transaction sets only have classic transactions.
+func extractPhaseCounts(ledger historyarchive.Ledger) (classicCount int32, sorobanCount int32, err error) {
+	switch ledger.Transaction.Ext.V {
+	case 0:
+		return int32(len(ledger.Transaction.TxSet.Txs)), 0, nil
+	case 1:
+		phases := ledger.Transaction.Ext.GeneralizedTxSet.V1TxSet.Phases
+		if len(phases) > 2 {
+			return 0, 0, fmt.Errorf("the transaction set has %d phases; expected a classic and a soroban phase", len(phases))
+		}
+		for i, phase := range phases {
+			count := int32(len(getTransactionPhase([]xdr.TransactionPhase{phase})))
+			if i == 0 {
+				classicCount = count
+			} else {
+				sorobanCount = count
+			}
+		}
+		return classicCount, sorobanCount, nil
+	default:
+		return 0, 0, fmt.Errorf("unsupported TransactionHistoryEntry.Ext: %d", ledger.Transaction.Ext.V)
+	}
+}
+
+// applyOrderDiffers checks if the transactions were applied in a different order than the order of the transaction
+// set. The results are in the order the transactions were applied, so their hashes are compared to the hashes of the
+// transactions of the set.
+func applyOrderDiffers(ledger historyarchive.Ledger, networkPassphrase string) (bool, error) {
+	transactions := GetTransactionSet(ledger)
+	results := ledger.TransactionResult.TxResultSet.Results
+	if len(transactions) != len(results) {
+		return false, fmt.Errorf("the number of transactions and results are different (%d != %d)", len(transactions), len(results))
+	}
+
+	for i, transaction := range transactions {
+		hash, err := network.HashTransactionInEnvelope(transaction, networkPassphrase)
+		if err != nil {
+			return false, fmt.Errorf("could not hash transaction %d of the transaction set: %v", i, err)
+		}
+		if xdr.Hash(hash) != results[i].TransactionHash {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 func GetTransactionSet(transactionEntry historyarchive.Ledger) (transactionProcessing []xdr.TransactionEnvelope) {
 	switch transactionEntry.Transaction.Ext.V {
 	case 0:
@@ -0,0 +1,123 @@
+package transform
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/stellar/go-stellar-sdk/ingest"
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stellar/stellar-etl/v2/internal/utils"
+)
+
+// TransformLedgerFeeStats derives the inclusion metrics of a ledger from its transaction set and the fees charged to
+// its transactions, so that surge pricing can be detected from ledger data alone
+func TransformLedgerFeeStats(lcm xdr.LedgerCloseMeta, transactions []ingest.LedgerTransaction) (LedgerFeeStatsOutput, error) {
+	ledgerHeader := lcm.LedgerHeaderHistoryEntry().Header
+	outputSequence := uint32(ledgerHeader.LedgerSeq)
+	outputBaseFee := uint32(ledgerHeader.BaseFee)
+	outputMaxTxSetSize := uint32(ledgerHeader.MaxTxSetSize)
+
+	outputCloseTime, err := utils.TimePointToUTCTimeStamp(ledgerHeader.ScpValue.CloseTime)
+	if err != nil {
+		return LedgerFeeStatsOutput{}, err
+	}
+
+	var outputTxSetOperationCount, outputClassicOperationCount int32
+	for _, transaction := range transactions {
+		operationCount := int32(transaction.OperationCount())
+		outputTxSetOperationCount += operationCount
+		if !transaction.IsSorobanTx() {
+			outputClassicOperationCount += operationCount
+		}
+	}
+
+	outputInclusionBaseFee, err := maxComponentBaseFee(lcm)
+	if err != nil {
+		return LedgerFeeStatsOutput{}, err
+	}
+
+	// Before generalized transaction sets the charged base fee isn't recorded, so a full transaction set is the only
+	// sign of surge pricing
+	outputIsSurge := outputInclusionBaseFee > int64(outputBaseFee)
+	if lcm.V == 0 && outputMaxTxSetSize > 0 && uint32(outputClassicOperationCount) >= outputMaxTxSetSize {
+		outputIsSurge = true
+	}
+
+	var outputCapacityUsage float64
+	if outputMaxTxSetSize > 0 {
+		outputCapacityUsage = float64(outputClassicOperationCount) / float64(outputMaxTxSetSize)
+	}
+
+	inclusionFees := make([]int64, 0, len(transactions))
+	for _, transaction := range transactions {
+		inclusionFee, ok := transaction.InclusionFeeCharged()
+		if !ok {
+			return LedgerFeeStatsOutput{}, fmt.Errorf("could not get the inclusion fee of transaction %d in ledger %d", transaction.Index, outputSequence)
+		}
+		inclusionFees = append(inclusionFees, inclusionFee)
+	}
+	outputMinInclusionFee, outputMedianInclusionFee, outputMaxInclusionFee := feeQuantiles(inclusionFees)
+
+	transformedStats := LedgerFeeStatsOutput{
+		LedgerSequence:        outputSequence,
+		ClosedAt:              outputCloseTime,
+		BaseFee:               outputBaseFee,
+		MaxTxSetSize:          outputMaxTxSetSize,
+		TransactionCount:      int32(len(transactions)),
+		TxSetOperationCount:   outputTxSetOperationCount,
+		ClassicOperationCount: outputClassicOperationCount,
+		CapacityUsage:         outputCapacityUsage,
+		MinInclusionFee:       outputMinInclusionFee,
+		MedianInclusionFee:    outputMedianInclusionFee,
+		MaxInclusionFee:       outputMaxInclusionFee,
+		InclusionBaseFee:      outputInclusionBaseFee,
+		IsSurge:               outputIsSurge,
+	}
+	return transformedStats, nil
+}
+
+// maxComponentBaseFee returns the highest base fee charged by a component of a generalized transaction set, or 0 if
+// no component sets one
+func maxComponentBaseFee(lcm xdr.LedgerCloseMeta) (int64, error) {
+	phases, err := utils.LedgerCloseMetaTxPhases(lcm)
+	if err != nil {
+		return 0, err
+	}
+
+	var baseFee int64
+	for _, phase := range phases {
+		switch phase.V {
+		case 0:
+			for _, component := range *phase.V0Components {
+				if fee := component.TxsMaybeDiscountedFee.BaseFee; fee != nil && int64(*fee) > baseFee {
+					baseFee = int64(*fee)
+				}
+			}
+		case 1:
+			if fee := phase.ParallelTxsComponent.BaseFee; fee != nil && int64(*fee) > baseFee {
+				baseFee = int64(*fee)
+			}
+		}
+	}
+
+	return baseFee, nil
+}
+
+// feeQuantiles returns the minimum, median and maximum of the fees. The median of an even number of fees is the mean
+// of the two middle fees, rounded down.
+func feeQuantiles(fees []int64) (int64, int64, int64) {
+	if len(fees) == 0 {
+		return 0, 0, 0
+	}
+
+	sorted := append([]int64{}, fees...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	middle := len(sorted) / 2
+	median := sorted[middle]
+	if len(sorted)%2 == 0 {
+		median = (sorted[middle-1] + sorted[middle]) / 2
+	}
+
+	return sorted[0], median, sorted[len(sorted)-1]
+}
@@ -0,0 +1,118 @@
+package transform
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stellar/go-stellar-sdk/ingest"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+func TestTransformLedgerFeeStats(t *testing.T) {
+	lcm, transactions := makeLedgerFeeStatsTestInput()
+	actualOutput, actualError := TransformLedgerFeeStats(lcm, transactions)
+	assert.NoError(t, actualError)
+	assert.Equal(t, LedgerFeeStatsOutput{
+		LedgerSequence:        30578981,
+		ClosedAt:              time.Date(2020, time.July, 9, 5, 28, 42, 0, time.UTC),
+		BaseFee:               100,
+		MaxTxSetSize:          10,
+		TransactionCount:      3,
+		TxSetOperationCount:   6,
+		ClassicOperationCount: 6,
+		CapacityUsage:         0.6,
+		MinInclusionFee:       200,
+		MedianInclusionFee:    300,
+		MaxInclusionFee:       1000,
+		InclusionBaseFee:      200,
+		IsSurge:               true,
+	}, actualOutput)
+
+	baseFee := xdr.Int64(100)
+	lcm.V1.TxSet.V1TxSet.Phases[0].V0Components = &[]xdr.TxSetComponent{
+		{
+			Type: xdr.TxSetComponentTypeTxsetCompTxsMaybeDiscountedFee,
+			TxsMaybeDiscountedFee: &xdr.TxSetComponentTxsMaybeDiscountedFee{
+				BaseFee: &baseFee,
+			},
+		},
+	}
+	actualOutput, actualError = TransformLedgerFeeStats(lcm, transactions)
+	assert.NoError(t, actualError)
+	assert.False(t, actualOutput.IsSurge)
+}
+
+func TestFeeQuantiles(t *testing.T) {
+	min, median, max := feeQuantiles([]int64{300, 100, 200})
+	assert.Equal(t, []int64{100, 200, 300}, []int64{min, median, max})
+
+	min, median, max = feeQuantiles([]int64{400, 100, 250, 100})
+	assert.Equal(t, []int64{100, 175, 400}, []int64{min, median, max})
+
+	min, median, max = feeQuantiles(nil)
+	assert.Equal(t, []int64{0, 0, 0}, []int64{min, median, max})
+}
+
+func makeLedgerFeeStatsTestInput() (xdr.LedgerCloseMeta, []ingest.LedgerTransaction) {
+	surgeBaseFee := xdr.Int64(200)
+	lcm := xdr.LedgerCloseMeta{
+		V: 1,
+		V1: &xdr.LedgerCloseMetaV1{
+			LedgerHeader: xdr.LedgerHeaderHistoryEntry{
+				Header: xdr.LedgerHeader{
+					LedgerSeq:    30578981,
+					BaseFee:      100,
+					MaxTxSetSize: 10,
+					ScpValue: xdr.StellarValue{
+						CloseTime: 1594272522,
+					},
+				},
+			},
+			TxSet: xdr.GeneralizedTransactionSet{
+				V: 1,
+				V1TxSet: &xdr.TransactionSetV1{
+					Phases: []xdr.TransactionPhase{
+						{
+							V: 0,
+							V0Components: &[]xdr.TxSetComponent{
+								{
+									Type: xdr.TxSetComponentTypeTxsetCompTxsMaybeDiscountedFee,
+									TxsMaybeDiscountedFee: &xdr.TxSetComponentTxsMaybeDiscountedFee{
+										BaseFee: &surgeBaseFee,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	newTransaction := func(operationCount int, feeCharged xdr.Int64) ingest.LedgerTransaction {
+		return ingest.LedgerTransaction{
+			Envelope: xdr.TransactionEnvelope{
+				Type: xdr.EnvelopeTypeEnvelopeTypeTx,
+				V1: &xdr.TransactionV1Envelope{
+					Tx: xdr.Transaction{
+						Operations: make([]xdr.Operation, operationCount),
+					},
+				},
+			},
+			Result: xdr.TransactionResultPair{
+				Result: xdr.TransactionResult{
+					FeeCharged: feeCharged,
+				},
+			},
+		}
+	}
+
+	transactions := []ingest.LedgerTransaction{
+		newTransaction(1, 1000),
+		newTransaction(2, 400),
+		newTransaction(3, 900),
+	}
+	return lcm, transactions
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/stellar/stellar-etl/v2/internal/utils"
 
 	"github.com/stellar/go-stellar-sdk/historyarchive"
+	"github.com/stellar/go-stellar-sdk/network"
 	"github.com/stellar/go-stellar-sdk/xdr"
 	"github.com/stretchr/testify/assert"
 )
@@ -110,7 +111,7 @@ func TestTransformLedger(t *testing.T) {
 	}
 
 	for _, test := range tests {
-		actualOutput, actualError := TransformLedger(test.input.Ledger, test.input.LCM)
+		actualOutput, actualError := TransformLedger(test.input.Ledger, test.input.LCM, network.PublicNetworkPassphrase)
 		assert.Equal(t, test.wantErr, actualError)
 		assert.Equal(t, test.wantOutput, actualOutput)
 	}
@@ -131,6 +132,11 @@ func makeLedgerTestOutput() (output LedgerOutput, err error) {
 		LedgerHeader:       string(correctBytes),
 		ClosedAt:           correctTime,
 
+		TxSetHash:                    "0000000000000000000000000000000000000000000000000000000000000000",
+		ClassicPhaseTransactionCount: 2,
+		SorobanPhaseTransactionCount: 0,
+		ApplyOrderDiffers:            false,
+
 		TotalCoins:      1054439020873472865,
 		FeePool:         18153766209161,
 		BaseFee:         100,
@@ -161,6 +167,13 @@ func makeLedgerTestInput() (lcm []utils.HistoryArchiveLedgerAndLCM, err error) {
 		utils.CreateSampleResultPair(false, 3),
 		utils.CreateSampleResultPair(true, 10),
 	}
+	for i, envelope := range hardCodedTxSet.Txs {
+		hash, err := network.HashTransactionInEnvelope(envelope, network.PublicNetworkPassphrase)
+		if err != nil {
+			return nil, err
+		}
+		hardCodedTxProcessing[i].TransactionHash = hash
+	}
 	ledger := historyarchive.Ledger{
 		Header: xdr.LedgerHeaderHistoryEntry{
 			Header: xdr.LedgerHeader{
@@ -238,3 +251,55 @@ func makeLedgerTestInput() (lcm []utils.HistoryArchiveLedgerAndLCM, err error) {
 
 	return lcm, nil
 }
+
+func TestTransformLedgerTxSetPhases(t *testing.T) {
+	classic := utils.CreateSampleTx(0, 1)
+	soroban := []xdr.TransactionEnvelope{utils.CreateSampleTx(1, 1), utils.CreateSampleTx(2, 1)}
+	phase := func(txs []xdr.TransactionEnvelope) xdr.TransactionPhase {
+		return xdr.TransactionPhase{V: 0, V0Components: &[]xdr.TxSetComponent{{
+			Type:                  xdr.TxSetComponentTypeTxsetCompTxsMaybeDiscountedFee,
+			TxsMaybeDiscountedFee: &xdr.TxSetComponentTxsMaybeDiscountedFee{Txs: txs},
+		}}}
+	}
+
+	// The second soroban transaction is applied before the first one
+	results := []xdr.TransactionResultPair{}
+	for _, envelope := range []xdr.TransactionEnvelope{classic, soroban[1], soroban[0]} {
+		hash, err := network.HashTransactionInEnvelope(envelope, network.TestNetworkPassphrase)
+		assert.NoError(t, err)
+		result := utils.CreateSampleResultPair(true, 1)
+		result.TransactionHash = hash
+		results = append(results, result)
+	}
+
+	ledger := historyarchive.Ledger{
+		Transaction: xdr.TransactionHistoryEntry{
+			Ext: xdr.TransactionHistoryEntryExt{
+				V: 1,
+				GeneralizedTxSet: &xdr.GeneralizedTransactionSet{
+					V: 1,
+					V1TxSet: &xdr.TransactionSetV1{
+						Phases: []xdr.TransactionPhase{phase([]xdr.TransactionEnvelope{classic}), phase(soroban)},
+					},
+				},
+			},
+		},
+		TransactionResult: xdr.TransactionHistoryResultEntry{
+			TxResultSet: xdr.TransactionResultSet{Results: results},
+		},
+	}
+
+	classicCount, sorobanCount, err := extractPhaseCounts(ledger)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(1), classicCount)
+	assert.Equal(t, int32(2), sorobanCount)
+
+	differs, err := applyOrderDiffers(ledger, network.TestNetworkPassphrase)
+	assert.NoError(t, err)
+	assert.True(t, differs)
+
+	results[1], results[2] = results[2], results[1]
+	differs, err = applyOrderDiffers(ledger, network.TestNetworkPassphrase)
+	assert.NoError(t, err)
+	assert.False(t, differs)
+}
@@ -0,0 +1,69 @@
+package transform
+
+import (
+	"fmt"
+
+	"github.com/guregu/null"
+	"github.com/stellar/stellar-etl/v2/internal/utils"
+
+	"github.com/stellar/go-stellar-sdk/strkey"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+// TransformLedgerUpgrades converts the upgrades applied while closing a ledger into a form suitable for BigQuery
+func TransformLedgerUpgrades(lcm xdr.LedgerCloseMeta) ([]LedgerUpgradeOutput, error) {
+	header := lcm.LedgerHeaderHistoryEntry()
+	outputLedgerSequence := uint32(header.Header.LedgerSeq)
+
+	outputCloseTime, err := utils.TimePointToUTCTimeStamp(header.Header.ScpValue.CloseTime)
+	if err != nil {
+		return []LedgerUpgradeOutput{}, err
+	}
+
+	var transformedUpgrades []LedgerUpgradeOutput
+	for i, upgradeEntry := range lcm.UpgradesProcessing() {
+		upgrade := upgradeEntry.Upgrade
+
+		transformedUpgrade := LedgerUpgradeOutput{
+			LedgerSequence: outputLedgerSequence,
+			ClosedAt:       outputCloseTime,
+			UpgradeIndex:   int32(i),
+			Type:           int32(upgrade.Type),
+			TypeString:     upgrade.Type.String(),
+		}
+
+		switch upgrade.Type {
+		case xdr.LedgerUpgradeTypeLedgerUpgradeVersion:
+			transformedUpgrade.NewLedgerVersion = null.IntFrom(int64(upgrade.MustNewLedgerVersion()))
+		case xdr.LedgerUpgradeTypeLedgerUpgradeBaseFee:
+			transformedUpgrade.NewBaseFee = null.IntFrom(int64(upgrade.MustNewBaseFee()))
+		case xdr.LedgerUpgradeTypeLedgerUpgradeMaxTxSetSize:
+			transformedUpgrade.NewMaxTxSetSize = null.IntFrom(int64(upgrade.MustNewMaxTxSetSize()))
+		case xdr.LedgerUpgradeTypeLedgerUpgradeBaseReserve:
+			transformedUpgrade.NewBaseReserve = null.IntFrom(int64(upgrade.MustNewBaseReserve()))
+		case xdr.LedgerUpgradeTypeLedgerUpgradeFlags:
+			transformedUpgrade.NewFlags = null.IntFrom(int64(upgrade.MustNewFlags()))
+		case xdr.LedgerUpgradeTypeLedgerUpgradeConfig:
+			configKey := upgrade.MustNewConfig()
+			contractID, err := strkey.Encode(strkey.VersionByteContract, configKey.ContractId[:])
+			if err != nil {
+				return []LedgerUpgradeOutput{}, fmt.Errorf("could not encode config upgrade contract id in ledger %d: %v", outputLedgerSequence, err)
+			}
+			transformedUpgrade.ConfigUpgradeSetContractID = null.StringFrom(contractID)
+			transformedUpgrade.ConfigUpgradeSetContentHash = null.StringFrom(configKey.ContentHash.HexString())
+		case xdr.LedgerUpgradeTypeLedgerUpgradeMaxSorobanTxSetSize:
+			transformedUpgrade.NewMaxSorobanTxSetSize = null.IntFrom(int64(upgrade.MustNewMaxSorobanTxSetSize()))
+		default:
+			return []LedgerUpgradeOutput{}, fmt.Errorf("unknown upgrade type %d in ledger %d", upgrade.Type, outputLedgerSequence)
+		}
+
+		transformedUpgrade.UpgradeXDR, err = xdr.MarshalBase64(upgrade)
+		if err != nil {
+			return []LedgerUpgradeOutput{}, fmt.Errorf("could not marshal upgrade %d in ledger %d: %v", i, outputLedgerSequence, err)
+		}
+
+		transformedUpgrades = append(transformedUpgrades, transformedUpgrade)
+	}
+
+	return transformedUpgrades, nil
+}
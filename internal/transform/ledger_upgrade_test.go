@@ -0,0 +1,122 @@
+package transform
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/guregu/null"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+func TestTransformLedgerUpgrades(t *testing.T) {
+	type transformTest struct {
+		input      xdr.LedgerCloseMeta
+		wantOutput []LedgerUpgradeOutput
+		wantErr    error
+	}
+
+	tests := []transformTest{
+		{
+			makeLedgerUpgradesTestInput(nil),
+			nil,
+			nil,
+		},
+		{
+			makeLedgerUpgradesTestInput([]xdr.LedgerUpgrade{{Type: xdr.LedgerUpgradeType(99)}}),
+			[]LedgerUpgradeOutput{},
+			fmt.Errorf("unknown upgrade type 99 in ledger 10"),
+		},
+		{
+			makeLedgerUpgradesTestInput(makeLedgerUpgradesTestUpgrades()),
+			makeLedgerUpgradesTestOutput(),
+			nil,
+		},
+	}
+
+	for _, test := range tests {
+		actualOutput, actualError := TransformLedgerUpgrades(test.input)
+		assert.Equal(t, test.wantErr, actualError)
+		assert.Equal(t, test.wantOutput, actualOutput)
+	}
+}
+
+func makeLedgerUpgradesTestInput(upgrades []xdr.LedgerUpgrade) xdr.LedgerCloseMeta {
+	var upgradesProcessing []xdr.UpgradeEntryMeta
+	for _, upgrade := range upgrades {
+		upgradesProcessing = append(upgradesProcessing, xdr.UpgradeEntryMeta{Upgrade: upgrade})
+	}
+
+	return xdr.LedgerCloseMeta{
+		V: 1,
+		V1: &xdr.LedgerCloseMetaV1{
+			LedgerHeader: xdr.LedgerHeaderHistoryEntry{
+				Header: xdr.LedgerHeader{
+					ScpValue: xdr.StellarValue{
+						CloseTime: 1000,
+					},
+					LedgerSeq: 10,
+				},
+			},
+			UpgradesProcessing: upgradesProcessing,
+		},
+	}
+}
+
+func makeLedgerUpgradesTestUpgrades() []xdr.LedgerUpgrade {
+	newLedgerVersion := xdr.Uint32(23)
+	newBaseFee := xdr.Uint32(200)
+	return []xdr.LedgerUpgrade{
+		{
+			Type:             xdr.LedgerUpgradeTypeLedgerUpgradeVersion,
+			NewLedgerVersion: &newLedgerVersion,
+		},
+		{
+			Type:       xdr.LedgerUpgradeTypeLedgerUpgradeBaseFee,
+			NewBaseFee: &newBaseFee,
+		},
+		{
+			Type: xdr.LedgerUpgradeTypeLedgerUpgradeConfig,
+			NewConfig: &xdr.ConfigUpgradeSetKey{
+				ContractId:  xdr.ContractId{1, 2, 3},
+				ContentHash: xdr.Hash{4, 5, 6},
+			},
+		},
+	}
+}
+
+func makeLedgerUpgradesTestOutput() []LedgerUpgradeOutput {
+	closedAt := time.Date(1970, time.January, 1, 0, 16, 40, 0, time.UTC)
+	return []LedgerUpgradeOutput{
+		{
+			LedgerSequence:   10,
+			ClosedAt:         closedAt,
+			UpgradeIndex:     0,
+			Type:             1,
+			TypeString:       "LedgerUpgradeTypeLedgerUpgradeVersion",
+			NewLedgerVersion: null.IntFrom(23),
+			UpgradeXDR:       "AAAAAQAAABc=",
+		},
+		{
+			LedgerSequence: 10,
+			ClosedAt:       closedAt,
+			UpgradeIndex:   1,
+			Type:           2,
+			TypeString:     "LedgerUpgradeTypeLedgerUpgradeBaseFee",
+			NewBaseFee:     null.IntFrom(200),
+			UpgradeXDR:     "AAAAAgAAAMg=",
+		},
+		{
+			LedgerSequence:              10,
+			ClosedAt:                    closedAt,
+			UpgradeIndex:                2,
+			Type:                        6,
+			TypeString:                  "LedgerUpgradeTypeLedgerUpgradeConfig",
+			ConfigUpgradeSetContractID:  null.StringFrom("CAAQEAYAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAABXAS"),
+			ConfigUpgradeSetContentHash: null.StringFrom("0405060000000000000000000000000000000000000000000000000000000000"),
+			UpgradeXDR:                  "AAAABgECAwAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAABAUGAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA=",
+		},
+	}
+}
@@ -41,20 +41,25 @@ func TransformPool(ledgerChange ingest.Change, header xdr.LedgerHeaderHistoryEnt
 	if err != nil {
 		return PoolOutput{}, err
 	}
-	assetAID := FarmHashAsset(assetACode, assetAIssuer, assetAType)
+	assetAID := utils.FarmHashAsset(assetACode, assetAIssuer, assetAType)
 
 	var assetBType, assetBCode, assetBIssuer string
 	err = cp.Params.AssetB.Extract(&assetBType, &assetBCode, &assetBIssuer)
 	if err != nil {
 		return PoolOutput{}, err
 	}
-	assetBID := FarmHashAsset(assetBCode, assetBIssuer, assetBType)
+	assetBID := utils.FarmHashAsset(assetBCode, assetBIssuer, assetBType)
 
 	closedAt, err := utils.TimePointToUTCTimeStamp(header.Header.ScpValue.CloseTime)
 	if err != nil {
 		return PoolOutput{}, err
 	}
 
+	ledgerKeyHash, ledgerKeyHashBase64, err := utils.LedgerEntryToLedgerKeyHashes(ledgerEntry)
+	if err != nil {
+		return PoolOutput{}, err
+	}
+
 	ledgerSequence := header.Header.LedgerSeq
 
 	var poolIDStrkey string
@@ -64,27 +69,29 @@ func TransformPool(ledgerChange ingest.Change, header xdr.LedgerHeaderHistoryEnt
 	}
 
 	transformedPool := PoolOutput{
-		PoolID:             PoolIDToString(lp.LiquidityPoolId),
-		PoolType:           poolType,
-		PoolFee:            uint32(cp.Params.Fee),
-		TrustlineCount:     uint64(cp.PoolSharesTrustLineCount),
-		PoolShareCount:     utils.ConvertStroopValueToReal(cp.TotalPoolShares),
-		AssetAType:         assetAType,
-		AssetACode:         assetACode,
-		AssetAIssuer:       assetAIssuer,
-		AssetAID:           assetAID,
-		AssetAReserve:      utils.ConvertStroopValueToReal(cp.ReserveA),
-		AssetBType:         assetBType,
-		AssetBCode:         assetBCode,
-		AssetBIssuer:       assetBIssuer,
-		AssetBID:           assetBID,
-		AssetBReserve:      utils.ConvertStroopValueToReal(cp.ReserveB),
-		LastModifiedLedger: uint32(ledgerEntry.LastModifiedLedgerSeq),
-		LedgerEntryChange:  uint32(changeType),
-		Deleted:            outputDeleted,
-		ClosedAt:           closedAt,
-		LedgerSequence:     uint32(ledgerSequence),
-		PoolIDStrkey:       poolIDStrkey,
+		PoolID:              PoolIDToString(lp.LiquidityPoolId),
+		PoolType:            poolType,
+		PoolFee:             uint32(cp.Params.Fee),
+		TrustlineCount:      uint64(cp.PoolSharesTrustLineCount),
+		PoolShareCount:      utils.ConvertStroopValueToReal(cp.TotalPoolShares),
+		AssetAType:          assetAType,
+		AssetACode:          assetACode,
+		AssetAIssuer:        assetAIssuer,
+		AssetAID:            assetAID,
+		AssetAReserve:       utils.ConvertStroopValueToReal(cp.ReserveA),
+		AssetBType:          assetBType,
+		AssetBCode:          assetBCode,
+		AssetBIssuer:        assetBIssuer,
+		AssetBID:            assetBID,
+		AssetBReserve:       utils.ConvertStroopValueToReal(cp.ReserveB),
+		LastModifiedLedger:  uint32(ledgerEntry.LastModifiedLedgerSeq),
+		LedgerEntryChange:   uint32(changeType),
+		Deleted:             outputDeleted,
+		ClosedAt:            closedAt,
+		LedgerSequence:      uint32(ledgerSequence),
+		LedgerKeyHash:       ledgerKeyHash,
+		LedgerKeyHashBase64: ledgerKeyHashBase64,
+		PoolIDStrkey:        poolIDStrkey,
 	}
 	return transformedPool, nil
 }
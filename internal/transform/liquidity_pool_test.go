@@ -96,26 +96,28 @@ func makePoolTestInput() ingest.Change {
 
 func makePoolTestOutput() PoolOutput {
 	return PoolOutput{
-		PoolID:             "172d430000000000000000000000000000000000000000000000000000000000",
-		PoolType:           "constant_product",
-		PoolFee:            30,
-		TrustlineCount:     5,
-		PoolShareCount:     0.0000035,
-		AssetAType:         "native",
-		AssetACode:         lpAssetA.GetCode(),
-		AssetAIssuer:       lpAssetA.GetIssuer(),
-		AssetAID:           -5706705804583548011,
-		AssetAReserve:      0.0000105,
-		AssetBType:         "credit_alphanum4",
-		AssetBCode:         lpAssetB.GetCode(),
-		AssetBID:           6690054458235693884,
-		AssetBIssuer:       lpAssetB.GetIssuer(),
-		AssetBReserve:      0.0000010,
-		LastModifiedLedger: 30705278,
-		LedgerEntryChange:  2,
-		Deleted:            true,
-		LedgerSequence:     10,
-		ClosedAt:           time.Date(1970, time.January, 1, 0, 16, 40, 0, time.UTC),
-		PoolIDStrkey:       "LALS2QYAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAC2X",
+		PoolID:              "172d430000000000000000000000000000000000000000000000000000000000",
+		PoolType:            "constant_product",
+		PoolFee:             30,
+		TrustlineCount:      5,
+		PoolShareCount:      0.0000035,
+		AssetAType:          "native",
+		AssetACode:          lpAssetA.GetCode(),
+		AssetAIssuer:        lpAssetA.GetIssuer(),
+		AssetAID:            -5706705804583548011,
+		AssetAReserve:       0.0000105,
+		AssetBType:          "credit_alphanum4",
+		AssetBCode:          lpAssetB.GetCode(),
+		AssetBID:            6690054458235693884,
+		AssetBIssuer:        lpAssetB.GetIssuer(),
+		AssetBReserve:       0.0000010,
+		LastModifiedLedger:  30705278,
+		LedgerEntryChange:   2,
+		Deleted:             true,
+		LedgerSequence:      10,
+		LedgerKeyHash:       "723b18d79da64aaa19577c7d4aa54f845a86e75b04e559175ae2afeb750f190b",
+		LedgerKeyHashBase64: "AAAABRctQwAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA",
+		ClosedAt:            time.Date(1970, time.January, 1, 0, 16, 40, 0, time.UTC),
+		PoolIDStrkey:        "LALS2QYAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAC2X",
 	}
 }
@@ -74,30 +74,37 @@ func TransformOffer(ledgerChange ingest.Change, header xdr.LedgerHeaderHistoryEn
 		return OfferOutput{}, err
 	}
 
+	ledgerKeyHash, ledgerKeyHashBase64, err := utils.LedgerEntryToLedgerKeyHashes(ledgerEntry)
+	if err != nil {
+		return OfferOutput{}, err
+	}
+
 	ledgerSequence := header.Header.LedgerSeq
 
 	transformedOffer := OfferOutput{
-		SellerID:           outputSellerID,
-		OfferID:            outputOfferID,
-		SellingAssetType:   outputSellingAsset.AssetType,
-		SellingAssetCode:   outputSellingAsset.AssetCode,
-		SellingAssetIssuer: outputSellingAsset.AssetIssuer,
-		SellingAssetID:     outputSellingAsset.AssetID,
-		BuyingAssetType:    outputBuyingAsset.AssetType,
-		BuyingAssetCode:    outputBuyingAsset.AssetCode,
-		BuyingAssetIssuer:  outputBuyingAsset.AssetIssuer,
-		BuyingAssetID:      outputBuyingAsset.AssetID,
-		Amount:             utils.ConvertStroopValueToReal(outputAmount),
-		PriceN:             outputPriceN,
-		PriceD:             outputPriceD,
-		Price:              outputPrice,
-		Flags:              outputFlags,
-		LastModifiedLedger: outputLastModifiedLedger,
-		LedgerEntryChange:  uint32(changeType),
-		Deleted:            outputDeleted,
-		Sponsor:            ledgerEntrySponsorToNullString(ledgerEntry),
-		ClosedAt:           closedAt,
-		LedgerSequence:     uint32(ledgerSequence),
+		SellerID:            outputSellerID,
+		OfferID:             outputOfferID,
+		SellingAssetType:    outputSellingAsset.AssetType,
+		SellingAssetCode:    outputSellingAsset.AssetCode,
+		SellingAssetIssuer:  outputSellingAsset.AssetIssuer,
+		SellingAssetID:      outputSellingAsset.AssetID,
+		BuyingAssetType:     outputBuyingAsset.AssetType,
+		BuyingAssetCode:     outputBuyingAsset.AssetCode,
+		BuyingAssetIssuer:   outputBuyingAsset.AssetIssuer,
+		BuyingAssetID:       outputBuyingAsset.AssetID,
+		Amount:              utils.ConvertStroopValueToReal(outputAmount),
+		PriceN:              outputPriceN,
+		PriceD:              outputPriceD,
+		Price:               outputPrice,
+		Flags:               outputFlags,
+		LastModifiedLedger:  outputLastModifiedLedger,
+		LedgerEntryChange:   uint32(changeType),
+		Deleted:             outputDeleted,
+		Sponsor:             ledgerEntrySponsorToNullString(ledgerEntry),
+		ClosedAt:            closedAt,
+		LedgerSequence:      uint32(ledgerSequence),
+		LedgerKeyHash:       ledgerKeyHash,
+		LedgerKeyHashBase64: ledgerKeyHashBase64,
 	}
 	return transformedOffer, nil
 }
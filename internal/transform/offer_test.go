@@ -162,26 +162,28 @@ func makeOfferTestInput() (ledgerChange ingest.Change, err error) {
 
 func makeOfferTestOutput() OfferOutput {
 	return OfferOutput{
-		SellerID:           testAccount1Address,
-		OfferID:            260678439,
-		SellingAssetType:   "native",
-		SellingAssetCode:   "",
-		SellingAssetIssuer: "",
-		SellingAssetID:     -5706705804583548011,
-		BuyingAssetType:    "credit_alphanum4",
-		BuyingAssetCode:    "ETH",
-		BuyingAssetIssuer:  testAccount3Address,
-		BuyingAssetID:      4476940172956910889,
-		Amount:             262.8450327,
-		PriceN:             920936891,
-		PriceD:             1790879058,
-		Price:              0.5142373444404865,
-		Flags:              2,
-		LastModifiedLedger: 30715263,
-		LedgerEntryChange:  2,
-		Deleted:            true,
-		Sponsor:            null.StringFrom(testAccount3Address),
-		LedgerSequence:     10,
-		ClosedAt:           time.Date(1970, time.January, 1, 0, 16, 40, 0, time.UTC),
+		SellerID:            testAccount1Address,
+		OfferID:             260678439,
+		SellingAssetType:    "native",
+		SellingAssetCode:    "",
+		SellingAssetIssuer:  "",
+		SellingAssetID:      -5706705804583548011,
+		BuyingAssetType:     "credit_alphanum4",
+		BuyingAssetCode:     "ETH",
+		BuyingAssetIssuer:   testAccount3Address,
+		BuyingAssetID:       4476940172956910889,
+		Amount:              262.8450327,
+		PriceN:              920936891,
+		PriceD:              1790879058,
+		Price:               0.5142373444404865,
+		Flags:               2,
+		LastModifiedLedger:  30715263,
+		LedgerEntryChange:   2,
+		Deleted:             true,
+		Sponsor:             null.StringFrom(testAccount3Address),
+		LedgerSequence:      10,
+		LedgerKeyHash:       "8cc323d734b0263b26708e4842c3a3a9cd3db13146e5e7a5862092749e5d3377",
+		LedgerKeyHashBase64: "AAAAAgAAAACI4aa0pXFSj6qfJuIObLw/5zyugLRGYwxb7wFSr3B9eAAAAAAPiaMn",
+		ClosedAt:            time.Date(1970, time.January, 1, 0, 16, 40, 0, time.UTC),
 	}
 }
@@ -2,9 +2,11 @@ package transform
 
 import (
 	"encoding/base64"
+	"encoding/hex"
 	"fmt"
 	"strconv"
 	"time"
+	"unicode/utf8"
 
 	"github.com/guregu/null"
 	"github.com/pkg/errors"
@@ -28,6 +30,17 @@ type liquidityPoolDelta struct {
 
 // TransformOperation converts an operation from the history archive ingestion system into a form suitable for BigQuery
 func TransformOperation(operation xdr.Operation, operationIndex int32, transaction ingest.LedgerTransaction, ledgerSeq int32, ledgerCloseMeta xdr.LedgerCloseMeta, network string) (OperationOutput, error) {
+	return NewTransactionCache(transaction).TransformOperation(operation, operationIndex, ledgerSeq, ledgerCloseMeta, network)
+}
+
+// TransformOperationWithoutDetails converts an operation like TransformOperation, but leaves its details empty. Details
+// are most of the cost of transforming an operation, so this is much faster when only the ids, types and accounts of
+// the operations are needed.
+func TransformOperationWithoutDetails(operation xdr.Operation, operationIndex int32, transaction ingest.LedgerTransaction, ledgerSeq int32, ledgerCloseMeta xdr.LedgerCloseMeta) (OperationOutput, error) {
+	return transformOperation(operation, operationIndex, transaction, nil, ledgerSeq, ledgerCloseMeta, "", false)
+}
+
+func transformOperation(operation xdr.Operation, operationIndex int32, transaction ingest.LedgerTransaction, cache *TransactionCache, ledgerSeq int32, ledgerCloseMeta xdr.LedgerCloseMeta, network string, withDetails bool) (OperationOutput, error) {
 	outputTransactionID := toid.New(ledgerSeq, int32(transaction.Index), 0).ToInt64()
 	outputOperationID := toid.New(ledgerSeq, int32(transaction.Index), operationIndex+1).ToInt64() //operationIndex needs +1 increment to stay in sync with ingest package
 
@@ -51,9 +64,12 @@ func TransformOperation(operation xdr.Operation, operationIndex int32, transacti
 		return OperationOutput{}, fmt.Errorf("the operation type (%d) is negative for  operation %d (operation id=%d)", outputOperationType, operationIndex, outputOperationID)
 	}
 
-	outputDetails, err := extractOperationDetails(operation, transaction, operationIndex, network)
-	if err != nil {
-		return OperationOutput{}, err
+	var outputDetails map[string]interface{}
+	if withDetails {
+		outputDetails, err = extractOperationDetails(operation, transaction, cache, operationIndex, network)
+		if err != nil {
+			return OperationOutput{}, err
+		}
 	}
 
 	outputOperationTypeString, err := mapOperationType(operation)
@@ -100,6 +116,44 @@ func TransformOperation(operation xdr.Operation, operationIndex int32, transacti
 	return transformedOperation, nil
 }
 
+// DecodeManageDataValue adds the decoded value of a manage_data operation to its details. The value is set as text in
+// value_decoded when it is valid UTF-8, and hex encoded otherwise, with value_is_utf8 telling the two apart. Other
+// operations, and manage_data operations that delete an entry, are left as they are.
+func DecodeManageDataValue(operation OperationOutput) error {
+	if operation.Type != int32(xdr.OperationTypeManageData) {
+		return nil
+	}
+
+	encoded, ok := operation.OperationDetails["value"].(string)
+	if !ok {
+		return nil
+	}
+
+	value, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return fmt.Errorf("could not decode manage_data value: %v", err)
+	}
+
+	isUTF8 := utf8.Valid(value)
+	operation.OperationDetails["value_is_utf8"] = isUTF8
+	if isUTF8 {
+		operation.OperationDetails["value_decoded"] = string(value)
+	} else {
+		operation.OperationDetails["value_decoded"] = hex.EncodeToString(value)
+	}
+	return nil
+}
+
+// OperationTypeName returns the type_string of an operation, such as invoke_host_function, or unknown for operation
+// types that can't be transformed
+func OperationTypeName(operation xdr.Operation) string {
+	name, err := mapOperationType(operation)
+	if err != nil {
+		return "unknown"
+	}
+	return name
+}
+
 func mapOperationType(operation xdr.Operation) (string, error) {
 	var op_string_type string
 	operationType := operation.Body.Type
@@ -235,8 +289,8 @@ func PoolIDToString(id xdr.PoolId) string {
 }
 
 // operation xdr.Operation, operationIndex int32, transaction ingest.LedgerTransaction, ledgerSeq int32
-func getLiquidityPoolAndProductDelta(operationIndex int32, transaction ingest.LedgerTransaction, lpID *xdr.PoolId) (*xdr.LiquidityPoolEntry, *liquidityPoolDelta, error) {
-	changes, err := transaction.GetOperationChanges(uint32(operationIndex))
+func getLiquidityPoolAndProductDelta(operationIndex int32, cache *TransactionCache, lpID *xdr.PoolId) (*xdr.LiquidityPoolEntry, *liquidityPoolDelta, error) {
+	changes, err := cache.operationChanges(uint32(operationIndex))
 	if err != nil {
 		return nil, nil, err
 	}
@@ -293,8 +347,8 @@ func getOperationSourceAccount(operation xdr.Operation, transaction ingest.Ledge
 	return transaction.Envelope.SourceAccount()
 }
 
-func getSponsor(operation xdr.Operation, transaction ingest.LedgerTransaction, operationIndex int32) (*xdr.AccountId, error) {
-	changes, err := transaction.GetOperationChanges(uint32(operationIndex))
+func getSponsor(operation xdr.Operation, cache *TransactionCache, operationIndex int32) (*xdr.AccountId, error) {
+	changes, err := cache.operationChanges(uint32(operationIndex))
 	if err != nil {
 		return nil, err
 	}
@@ -364,7 +418,7 @@ func formatPrefix(p string) string {
 	return p
 }
 
-func addAssetDetailsToOperationDetails(result map[string]interface{}, asset xdr.Asset, prefix string) error {
+func addAssetDetailsToOperationDetails(result map[string]interface{}, asset xdr.Asset, prefix string, network string) error {
 	var assetType, code, issuer string
 	err := asset.Extract(&assetType, &code, &issuer)
 	if err != nil {
@@ -374,14 +428,18 @@ func addAssetDetailsToOperationDetails(result map[string]interface{}, asset xdr.
 	prefix = formatPrefix(prefix)
 	result[prefix+"asset_type"] = assetType
 
+	result[prefix+"asset_id"] = utils.FarmHashAsset(code, issuer, assetType)
+
+	if err := addAssetContractID(result, asset, prefix, network); err != nil {
+		return err
+	}
+
 	if asset.Type == xdr.AssetTypeAssetTypeNative {
-		result[prefix+"asset_id"] = int64(-5706705804583548011)
 		return nil
 	}
 
 	result[prefix+"asset_code"] = code
 	result[prefix+"asset_issuer"] = issuer
-	result[prefix+"asset_id"] = FarmHashAsset(code, issuer, assetType)
 
 	return nil
 }
@@ -406,6 +464,37 @@ func addLiquidityPoolAssetDetails(result map[string]interface{}, lpp xdr.Liquidi
 	return nil
 }
 
+// addSignerKeyDetails sets the key of a signer on `result`, with the type of the key so that account recovery setups
+// can be told apart from regular signers. Signed payload signers also have their account and payload decoded.
+func addSignerKeyDetails(result map[string]interface{}, key xdr.SignerKey) error {
+	address, err := key.GetAddress()
+	if err != nil {
+		return err
+	}
+	result["signer_key"] = address
+
+	switch key.Type {
+	case xdr.SignerKeyTypeSignerKeyTypeEd25519:
+		result["signer_type"] = "ed25519"
+	case xdr.SignerKeyTypeSignerKeyTypePreAuthTx:
+		result["signer_type"] = "pre_auth_tx"
+	case xdr.SignerKeyTypeSignerKeyTypeHashX:
+		result["signer_type"] = "hash_x"
+	case xdr.SignerKeyTypeSignerKeyTypeEd25519SignedPayload:
+		result["signer_type"] = "ed25519_signed_payload"
+		payload := key.MustEd25519SignedPayload()
+		account, err := strkey.Encode(strkey.VersionByteAccountID, payload.Ed25519[:])
+		if err != nil {
+			return err
+		}
+		result["signer_payload_account"] = account
+		result["signer_payload"] = base64.StdEncoding.EncodeToString(payload.Payload)
+	default:
+		return fmt.Errorf("unknown signer key type %d", key.Type)
+	}
+	return nil
+}
+
 func addPriceDetails(result map[string]interface{}, price xdr.Price, prefix string) error {
 	prefix = formatPrefix(prefix)
 	parsedPrice, err := strconv.ParseFloat(price.String(), 64)
@@ -525,6 +614,7 @@ func transformPath(initialPath []xdr.Asset) []Path {
 			AssetType:   assetType,
 			AssetIssuer: issuer,
 			AssetCode:   code,
+			AssetID:     utils.FarmHashAsset(code, issuer, assetType),
 		})
 	}
 	return path
@@ -581,7 +671,7 @@ func addOperationFlagToOperationDetails(result map[string]interface{}, flag uint
 	result[prefix+"flags_s"] = stringFlags
 }
 
-func extractOperationDetails(operation xdr.Operation, transaction ingest.LedgerTransaction, operationIndex int32, network string) (map[string]interface{}, error) {
+func extractOperationDetails(operation xdr.Operation, transaction ingest.LedgerTransaction, cache *TransactionCache, operationIndex int32, network string) (map[string]interface{}, error) {
 	details := map[string]interface{}{}
 	sourceAccount := getOperationSourceAccount(operation, transaction)
 	operationType := operation.Body.Type
@@ -612,7 +702,7 @@ func extractOperationDetails(operation xdr.Operation, transaction ingest.LedgerT
 			return details, err
 		}
 		details["amount"] = utils.ConvertStroopValueToReal(op.Amount)
-		if err := addAssetDetailsToOperationDetails(details, op.Asset, ""); err != nil {
+		if err := addAssetDetailsToOperationDetails(details, op.Asset, "", network); err != nil {
 			return details, err
 		}
 
@@ -631,10 +721,10 @@ func extractOperationDetails(operation xdr.Operation, transaction ingest.LedgerT
 		details["amount"] = utils.ConvertStroopValueToReal(op.DestAmount)
 		details["source_amount"] = amount.String(0)
 		details["source_max"] = utils.ConvertStroopValueToReal(op.SendMax)
-		if err := addAssetDetailsToOperationDetails(details, op.DestAsset, ""); err != nil {
+		if err := addAssetDetailsToOperationDetails(details, op.DestAsset, "", network); err != nil {
 			return details, err
 		}
-		if err := addAssetDetailsToOperationDetails(details, op.SendAsset, "source"); err != nil {
+		if err := addAssetDetailsToOperationDetails(details, op.SendAsset, "source", network); err != nil {
 			return details, err
 		}
 
@@ -672,10 +762,10 @@ func extractOperationDetails(operation xdr.Operation, transaction ingest.LedgerT
 		details["amount"] = amount.String(0)
 		details["source_amount"] = utils.ConvertStroopValueToReal(op.SendAmount)
 		details["destination_min"] = amount.String(op.DestMin)
-		if err := addAssetDetailsToOperationDetails(details, op.DestAsset, ""); err != nil {
+		if err := addAssetDetailsToOperationDetails(details, op.DestAsset, "", network); err != nil {
 			return details, err
 		}
-		if err := addAssetDetailsToOperationDetails(details, op.SendAsset, "source"); err != nil {
+		if err := addAssetDetailsToOperationDetails(details, op.SendAsset, "source", network); err != nil {
 			return details, err
 		}
 
@@ -710,10 +800,10 @@ func extractOperationDetails(operation xdr.Operation, transaction ingest.LedgerT
 			return details, err
 		}
 
-		if err := addAssetDetailsToOperationDetails(details, op.Buying, "buying"); err != nil {
+		if err := addAssetDetailsToOperationDetails(details, op.Buying, "buying", network); err != nil {
 			return details, err
 		}
-		if err := addAssetDetailsToOperationDetails(details, op.Selling, "selling"); err != nil {
+		if err := addAssetDetailsToOperationDetails(details, op.Selling, "selling", network); err != nil {
 			return details, err
 		}
 
@@ -729,10 +819,10 @@ func extractOperationDetails(operation xdr.Operation, transaction ingest.LedgerT
 			return details, err
 		}
 
-		if err := addAssetDetailsToOperationDetails(details, op.Buying, "buying"); err != nil {
+		if err := addAssetDetailsToOperationDetails(details, op.Buying, "buying", network); err != nil {
 			return details, err
 		}
-		if err := addAssetDetailsToOperationDetails(details, op.Selling, "selling"); err != nil {
+		if err := addAssetDetailsToOperationDetails(details, op.Selling, "selling", network); err != nil {
 			return details, err
 		}
 
@@ -747,10 +837,10 @@ func extractOperationDetails(operation xdr.Operation, transaction ingest.LedgerT
 			return details, err
 		}
 
-		if err := addAssetDetailsToOperationDetails(details, op.Buying, "buying"); err != nil {
+		if err := addAssetDetailsToOperationDetails(details, op.Buying, "buying", network); err != nil {
 			return details, err
 		}
-		if err := addAssetDetailsToOperationDetails(details, op.Selling, "selling"); err != nil {
+		if err := addAssetDetailsToOperationDetails(details, op.Selling, "selling", network); err != nil {
 			return details, err
 		}
 
@@ -793,7 +883,9 @@ func extractOperationDetails(operation xdr.Operation, transaction ingest.LedgerT
 		}
 
 		if op.Signer != nil {
-			details["signer_key"] = op.Signer.Key.Address()
+			if err := addSignerKeyDetails(details, op.Signer.Key); err != nil {
+				return details, err
+			}
 			details["signer_weight"] = uint32(op.Signer.Weight)
 		}
 
@@ -808,7 +900,7 @@ func extractOperationDetails(operation xdr.Operation, transaction ingest.LedgerT
 				return details, err
 			}
 		} else {
-			if err := addAssetDetailsToOperationDetails(details, op.Line.ToAsset(), ""); err != nil {
+			if err := addAssetDetailsToOperationDetails(details, op.Line.ToAsset(), "", network); err != nil {
 				return details, err
 			}
 			details["trustee"] = details["asset_issuer"]
@@ -825,7 +917,7 @@ func extractOperationDetails(operation xdr.Operation, transaction ingest.LedgerT
 			return details, fmt.Errorf("could not access AllowTrust info for this operation (index %d)", operationIndex)
 		}
 
-		if err := addAssetDetailsToOperationDetails(details, op.Asset.ToAsset(sourceAccount.ToAccountId()), ""); err != nil {
+		if err := addAssetDetailsToOperationDetails(details, op.Asset.ToAsset(sourceAccount.ToAccountId()), "", network); err != nil {
 			return details, err
 		}
 		if err := addAccountAndMuxedAccountDetails(details, sourceAccount, "trustee"); err != nil {
@@ -856,6 +948,31 @@ func extractOperationDetails(operation xdr.Operation, transaction ingest.LedgerT
 			return details, err
 		}
 
+		// The whole native balance of the merged account moves to the destination, which only the result records
+		if transaction.Result.Successful() {
+			allOperationResults, ok := transaction.Result.OperationResults()
+			if !ok {
+				return details, fmt.Errorf("could not access any results for this transaction")
+			}
+			currentOperationResult := allOperationResults[operationIndex]
+			resultBody, ok := currentOperationResult.GetTr()
+			if !ok {
+				return details, fmt.Errorf("could not access result body for this operation (index %d)", operationIndex)
+			}
+			result, ok := resultBody.GetAccountMergeResult()
+			if !ok {
+				return details, fmt.Errorf("could not access AccountMerge result info for this operation (index %d)", operationIndex)
+			}
+			balance, ok := result.GetSourceAccountBalance()
+			if !ok {
+				return details, fmt.Errorf("could not access the merged balance for this operation (index %d)", operationIndex)
+			}
+			details["amount"] = utils.ConvertStroopValueToReal(balance)
+			if err := addAssetDetailsToOperationDetails(details, xdr.MustNewNativeAsset(), "", network); err != nil {
+				return details, err
+			}
+		}
+
 	case xdr.OperationTypeInflation:
 		// Inflation operations don't have information that affects the details struct
 	case xdr.OperationTypeManageData:
@@ -881,9 +998,41 @@ func extractOperationDetails(operation xdr.Operation, transaction ingest.LedgerT
 	case xdr.OperationTypeCreateClaimableBalance:
 		op := operation.Body.MustCreateClaimableBalanceOp()
 		details["asset"] = op.Asset.StringCanonical()
+		assetID, err := utils.AssetID(op.Asset)
+		if err != nil {
+			return details, err
+		}
+		details["asset_id"] = assetID
 		details["amount"] = utils.ConvertStroopValueToReal(op.Amount)
 		details["claimants"] = transformClaimants(op.Claimants)
 
+		// The id of the balance is derived from the operation id, so only the result records it
+		if transaction.Result.Successful() {
+			allOperationResults, ok := transaction.Result.OperationResults()
+			if !ok {
+				return details, fmt.Errorf("could not access any results for this transaction")
+			}
+			currentOperationResult := allOperationResults[operationIndex]
+			resultBody, ok := currentOperationResult.GetTr()
+			if !ok {
+				return details, fmt.Errorf("could not access result body for this operation (index %d)", operationIndex)
+			}
+			result, ok := resultBody.GetCreateClaimableBalanceResult()
+			if !ok {
+				return details, fmt.Errorf("could not access CreateClaimableBalance result info for this operation (index %d)", operationIndex)
+			}
+			resultBalanceID, ok := result.GetBalanceId()
+			if !ok {
+				return details, fmt.Errorf("could not access the balance id for this operation (index %d)", operationIndex)
+			}
+			balanceID, err := xdr.MarshalHex(resultBalanceID)
+			if err != nil {
+				return details, fmt.Errorf("invalid balanceId in result of op: %d", operationIndex)
+			}
+			details["balance_id"] = balanceID
+			details["balance_id_strkey"] = resultBalanceID.MustEncodeToStrkey()
+		}
+
 	case xdr.OperationTypeClaimClaimableBalance:
 		op := operation.Body.MustClaimClaimableBalanceOp()
 		balanceID, err := xdr.MarshalHex(op.BalanceId)
@@ -923,7 +1072,7 @@ func extractOperationDetails(operation xdr.Operation, transaction ingest.LedgerT
 
 	case xdr.OperationTypeClawback:
 		op := operation.Body.MustClawbackOp()
-		if err := addAssetDetailsToOperationDetails(details, op.Asset, ""); err != nil {
+		if err := addAssetDetailsToOperationDetails(details, op.Asset, "", network); err != nil {
 			return details, err
 		}
 		if err := addAccountAndMuxedAccountDetails(details, op.From, "from"); err != nil {
@@ -943,7 +1092,7 @@ func extractOperationDetails(operation xdr.Operation, transaction ingest.LedgerT
 	case xdr.OperationTypeSetTrustLineFlags:
 		op := operation.Body.MustSetTrustLineFlagsOp()
 		details["trustor"] = op.Trustor.Address()
-		if err := addAssetDetailsToOperationDetails(details, op.Asset, ""); err != nil {
+		if err := addAssetDetailsToOperationDetails(details, op.Asset, "", network); err != nil {
 			return details, err
 		}
 		if op.SetFlags > 0 {
@@ -973,7 +1122,7 @@ func extractOperationDetails(operation xdr.Operation, transaction ingest.LedgerT
 
 		if transaction.Result.Successful() {
 			// we will use the defaults (omitted asset and 0 amounts) if the transaction failed
-			lp, delta, err := getLiquidityPoolAndProductDelta(operationIndex, transaction, &op.LiquidityPoolId)
+			lp, delta, err := getLiquidityPoolAndProductDelta(operationIndex, cache, &op.LiquidityPoolId)
 			if err != nil {
 				return nil, err
 			}
@@ -984,7 +1133,7 @@ func extractOperationDetails(operation xdr.Operation, transaction ingest.LedgerT
 		}
 
 		// Process ReserveA Details
-		if err := addAssetDetailsToOperationDetails(details, assetA, "reserve_a"); err != nil {
+		if err := addAssetDetailsToOperationDetails(details, assetA, "reserve_a", network); err != nil {
 			return details, err
 		}
 		details["reserve_a_max_amount"] = utils.ConvertStroopValueToReal(op.MaxAmountA)
@@ -995,7 +1144,7 @@ func extractOperationDetails(operation xdr.Operation, transaction ingest.LedgerT
 		details["reserve_a_deposit_amount"] = depositA
 
 		//Process ReserveB Details
-		if err := addAssetDetailsToOperationDetails(details, assetB, "reserve_b"); err != nil {
+		if err := addAssetDetailsToOperationDetails(details, assetB, "reserve_b", network); err != nil {
 			return details, err
 		}
 		details["reserve_b_max_amount"] = utils.ConvertStroopValueToReal(op.MaxAmountB)
@@ -1036,7 +1185,7 @@ func extractOperationDetails(operation xdr.Operation, transaction ingest.LedgerT
 
 		if transaction.Result.Successful() {
 			// we will use the defaults (omitted asset and 0 amounts) if the transaction failed
-			lp, delta, err := getLiquidityPoolAndProductDelta(operationIndex, transaction, &op.LiquidityPoolId)
+			lp, delta, err := getLiquidityPoolAndProductDelta(operationIndex, cache, &op.LiquidityPoolId)
 			if err != nil {
 				return nil, err
 			}
@@ -1045,14 +1194,14 @@ func extractOperationDetails(operation xdr.Operation, transaction ingest.LedgerT
 			receivedA, receivedB = -delta.ReserveA, -delta.ReserveB
 		}
 		// Process AssetA Details
-		if err := addAssetDetailsToOperationDetails(details, assetA, "reserve_a"); err != nil {
+		if err := addAssetDetailsToOperationDetails(details, assetA, "reserve_a", network); err != nil {
 			return details, err
 		}
 		details["reserve_a_min_amount"] = utils.ConvertStroopValueToReal(op.MinAmountA)
 		details["reserve_a_withdraw_amount"] = utils.ConvertStroopValueToReal(receivedA)
 
 		// Process AssetB Details
-		if err := addAssetDetailsToOperationDetails(details, assetB, "reserve_b"); err != nil {
+		if err := addAssetDetailsToOperationDetails(details, assetB, "reserve_b", network); err != nil {
 			return details, err
 		}
 		details["reserve_b_min_amount"] = utils.ConvertStroopValueToReal(op.MinAmountB)
@@ -1079,16 +1228,20 @@ func extractOperationDetails(operation xdr.Operation, transaction ingest.LedgerT
 				return nil, err
 			}
 
-			transactionEnvelope := getTransactionV1Envelope(transaction.Envelope)
-			details["ledger_key_hash"] = ledgerKeyHashFromTxEnvelope(transactionEnvelope)
+			footprint := cache.footprint()
+			details["ledger_key_hash"] = footprint.ledgerKeyHashes
 			details["contract_id"] = contractId
-			details["contract_code_hash"] = contractCodeHashFromTxEnvelope(transactionEnvelope)
+			details["contract_code_hash"] = footprint.contractCodeHash
 
 			details["parameters"], details["parameters_decoded"] = serializeParameters(args)
-			details["parameters_json"], details["parameters_json_decoded"], err = serializeScValArray(args)
+			var parametersTruncated bool
+			details["parameters_json"], details["parameters_json_decoded"], parametersTruncated, err = serializeScValArray(args)
 			if err != nil {
 				return nil, err
 			}
+			if parametersTruncated {
+				details["parameters_json_truncated"] = true
+			}
 
 			if balanceChanges, err := parseAssetBalanceChangesFromContractEvents(transaction, network); err != nil {
 				return nil, err
@@ -1100,10 +1253,10 @@ func extractOperationDetails(operation xdr.Operation, transaction ingest.LedgerT
 			args := op.HostFunction.MustCreateContract()
 			details["type"] = "create_contract"
 
-			transactionEnvelope := getTransactionV1Envelope(transaction.Envelope)
-			details["ledger_key_hash"] = ledgerKeyHashFromTxEnvelope(transactionEnvelope)
-			details["contract_id"] = contractIdFromTxEnvelope(transactionEnvelope)
-			details["contract_code_hash"] = contractCodeHashFromTxEnvelope(transactionEnvelope)
+			footprint := cache.footprint()
+			details["ledger_key_hash"] = footprint.ledgerKeyHashes
+			details["contract_id"] = footprint.contractID
+			details["contract_code_hash"] = footprint.contractCodeHash
 
 			preimageTypeMap := switchContractIdPreimageType(args.ContractIdPreimage)
 			for key, val := range preimageTypeMap {
@@ -1113,24 +1266,28 @@ func extractOperationDetails(operation xdr.Operation, transaction ingest.LedgerT
 			}
 		case xdr.HostFunctionTypeHostFunctionTypeUploadContractWasm:
 			details["type"] = "upload_wasm"
-			transactionEnvelope := getTransactionV1Envelope(transaction.Envelope)
-			details["ledger_key_hash"] = ledgerKeyHashFromTxEnvelope(transactionEnvelope)
-			details["contract_code_hash"] = contractCodeHashFromTxEnvelope(transactionEnvelope)
+			footprint := cache.footprint()
+			details["ledger_key_hash"] = footprint.ledgerKeyHashes
+			details["contract_code_hash"] = footprint.contractCodeHash
 		case xdr.HostFunctionTypeHostFunctionTypeCreateContractV2:
 			var err error
 			args := op.HostFunction.MustCreateContractV2()
 			details["type"] = "create_contract_v2"
 
-			transactionEnvelope := getTransactionV1Envelope(transaction.Envelope)
-			details["ledger_key_hash"] = ledgerKeyHashFromTxEnvelope(transactionEnvelope)
-			details["contract_id"] = contractIdFromTxEnvelope(transactionEnvelope)
-			details["contract_code_hash"] = contractCodeHashFromTxEnvelope(transactionEnvelope)
+			footprint := cache.footprint()
+			details["ledger_key_hash"] = footprint.ledgerKeyHashes
+			details["contract_id"] = footprint.contractID
+			details["contract_code_hash"] = footprint.contractCodeHash
 
 			details["parameters"], details["parameters_decoded"] = serializeParameters(args.ConstructorArgs)
-			details["parameters_json"], details["parameters_json_decoded"], err = serializeScValArray(args.ConstructorArgs)
+			var parametersTruncated bool
+			details["parameters_json"], details["parameters_json_decoded"], parametersTruncated, err = serializeScValArray(args.ConstructorArgs)
 			if err != nil {
 				return nil, err
 			}
+			if parametersTruncated {
+				details["parameters_json_truncated"] = true
+			}
 
 			preimageTypeMap := switchContractIdPreimageType(args.ContractIdPreimage)
 			for key, val := range preimageTypeMap {
@@ -1146,22 +1303,22 @@ func extractOperationDetails(operation xdr.Operation, transaction ingest.LedgerT
 		details["type"] = "extend_footprint_ttl"
 		details["extend_to"] = op.ExtendTo
 
-		transactionEnvelope := getTransactionV1Envelope(transaction.Envelope)
-		details["ledger_key_hash"] = ledgerKeyHashFromTxEnvelope(transactionEnvelope)
-		details["contract_id"] = contractIdFromTxEnvelope(transactionEnvelope)
-		details["contract_code_hash"] = contractCodeHashFromTxEnvelope(transactionEnvelope)
+		footprint := cache.footprint()
+		details["ledger_key_hash"] = footprint.ledgerKeyHashes
+		details["contract_id"] = footprint.contractID
+		details["contract_code_hash"] = footprint.contractCodeHash
 	case xdr.OperationTypeRestoreFootprint:
 		details["type"] = "restore_footprint"
 
-		transactionEnvelope := getTransactionV1Envelope(transaction.Envelope)
-		details["ledger_key_hash"] = ledgerKeyHashFromTxEnvelope(transactionEnvelope)
-		details["contract_id"] = contractIdFromTxEnvelope(transactionEnvelope)
-		details["contract_code_hash"] = contractCodeHashFromTxEnvelope(transactionEnvelope)
+		footprint := cache.footprint()
+		details["ledger_key_hash"] = footprint.ledgerKeyHashes
+		details["contract_id"] = footprint.contractID
+		details["contract_code_hash"] = footprint.contractCodeHash
 	default:
 		return details, fmt.Errorf("unknown operation type: %s", operation.Body.Type.String())
 	}
 
-	sponsor, err := getSponsor(operation, transaction, operationIndex)
+	sponsor, err := getSponsor(operation, cache, operationIndex)
 	if err != nil {
 		return nil, err
 	}
@@ -1176,12 +1333,30 @@ func extractOperationDetails(operation xdr.Operation, transaction ingest.LedgerT
 type transactionOperationWrapper struct {
 	index          uint32
 	transaction    ingest.LedgerTransaction
+	cache          *TransactionCache
 	operation      xdr.Operation
 	ledgerSequence uint32
 	network        string
 	ledgerClosed   time.Time
 }
 
+// operationChanges returns the changes of the operation, from the cache of its transaction if it has one
+func (operation *transactionOperationWrapper) operationChanges() ([]ingest.Change, error) {
+	if operation.cache == nil {
+		return operation.transaction.GetOperationChanges(operation.index)
+	}
+	return operation.cache.operationChanges(operation.index)
+}
+
+// footprint returns the soroban footprint of the transaction of the operation, from the cache of its transaction if it
+// has one
+func (operation *transactionOperationWrapper) footprint() *sorobanFootprint {
+	if operation.cache == nil {
+		return NewTransactionCache(operation.transaction).footprint()
+	}
+	return operation.cache.footprint()
+}
+
 // ID returns the ID for the operation.
 func (operation *transactionOperationWrapper) ID() int64 {
 	return toid.New(
@@ -1250,7 +1425,7 @@ func (operation *transactionOperationWrapper) getSignerSponsorInChange(signerKey
 }
 
 func (operation *transactionOperationWrapper) getSponsor() (*xdr.AccountId, error) {
-	changes, err := operation.transaction.GetOperationChanges(operation.index)
+	changes, err := operation.operationChanges()
 	if err != nil {
 		return nil, err
 	}
@@ -1284,7 +1459,7 @@ func (operation *transactionOperationWrapper) getSponsor() (*xdr.AccountId, erro
 var errLiquidityPoolChangeNotFound = errors.New("liquidity pool change not found")
 
 func (operation *transactionOperationWrapper) getLiquidityPoolAndProductDelta(lpID *xdr.PoolId) (*xdr.LiquidityPoolEntry, *liquidityPoolDelta, error) {
-	changes, err := operation.transaction.GetOperationChanges(operation.index)
+	changes, err := operation.operationChanges()
 	if err != nil {
 		return nil, nil, err
 	}
@@ -1375,7 +1550,7 @@ func (operation *transactionOperationWrapper) Details() (map[string]interface{},
 		addAccountAndMuxedAccountDetails(details, *source, "from")
 		addAccountAndMuxedAccountDetails(details, op.Destination, "to")
 		details["amount"] = amount.String(op.Amount)
-		addAssetDetails(details, op.Asset, "")
+		addAssetDetails(details, op.Asset, "", operation.network)
 	case xdr.OperationTypePathPaymentStrictReceive:
 		op := operation.operation.Body.MustPathPaymentStrictReceiveOp()
 		addAccountAndMuxedAccountDetails(details, *source, "from")
@@ -1384,8 +1559,8 @@ func (operation *transactionOperationWrapper) Details() (map[string]interface{},
 		details["amount"] = amount.String(op.DestAmount)
 		details["source_amount"] = amount.String(0)
 		details["source_max"] = amount.String(op.SendMax)
-		addAssetDetails(details, op.DestAsset, "")
-		addAssetDetails(details, op.SendAsset, "source_")
+		addAssetDetails(details, op.DestAsset, "", operation.network)
+		addAssetDetails(details, op.SendAsset, "source_", operation.network)
 
 		if operation.transaction.Result.Successful() {
 			result := operation.OperationResult().MustPathPaymentStrictReceiveResult()
@@ -1395,7 +1570,7 @@ func (operation *transactionOperationWrapper) Details() (map[string]interface{},
 		var path = make([]map[string]interface{}, len(op.Path))
 		for i := range op.Path {
 			path[i] = make(map[string]interface{})
-			addAssetDetails(path[i], op.Path[i], "")
+			addAssetDetails(path[i], op.Path[i], "", operation.network)
 		}
 		details["path"] = path
 
@@ -1407,8 +1582,8 @@ func (operation *transactionOperationWrapper) Details() (map[string]interface{},
 		details["amount"] = amount.String(0)
 		details["source_amount"] = amount.String(op.SendAmount)
 		details["destination_min"] = amount.String(op.DestMin)
-		addAssetDetails(details, op.DestAsset, "")
-		addAssetDetails(details, op.SendAsset, "source_")
+		addAssetDetails(details, op.DestAsset, "", operation.network)
+		addAssetDetails(details, op.SendAsset, "source_", operation.network)
 
 		if operation.transaction.Result.Successful() {
 			result := operation.OperationResult().MustPathPaymentStrictSendResult()
@@ -1418,7 +1593,7 @@ func (operation *transactionOperationWrapper) Details() (map[string]interface{},
 		var path = make([]map[string]interface{}, len(op.Path))
 		for i := range op.Path {
 			path[i] = make(map[string]interface{})
-			addAssetDetails(path[i], op.Path[i], "")
+			addAssetDetails(path[i], op.Path[i], "", operation.network)
 		}
 		details["path"] = path
 	case xdr.OperationTypeManageBuyOffer:
@@ -1430,8 +1605,8 @@ func (operation *transactionOperationWrapper) Details() (map[string]interface{},
 			"n": op.Price.N,
 			"d": op.Price.D,
 		}
-		addAssetDetails(details, op.Buying, "buying_")
-		addAssetDetails(details, op.Selling, "selling_")
+		addAssetDetails(details, op.Buying, "buying_", operation.network)
+		addAssetDetails(details, op.Selling, "selling_", operation.network)
 	case xdr.OperationTypeManageSellOffer:
 		op := operation.operation.Body.MustManageSellOfferOp()
 		details["offer_id"] = op.OfferId
@@ -1441,8 +1616,8 @@ func (operation *transactionOperationWrapper) Details() (map[string]interface{},
 			"n": op.Price.N,
 			"d": op.Price.D,
 		}
-		addAssetDetails(details, op.Buying, "buying_")
-		addAssetDetails(details, op.Selling, "selling_")
+		addAssetDetails(details, op.Buying, "buying_", operation.network)
+		addAssetDetails(details, op.Selling, "selling_", operation.network)
 	case xdr.OperationTypeCreatePassiveSellOffer:
 		op := operation.operation.Body.MustCreatePassiveSellOfferOp()
 		details["amount"] = amount.String(op.Amount)
@@ -1451,8 +1626,8 @@ func (operation *transactionOperationWrapper) Details() (map[string]interface{},
 			"n": op.Price.N,
 			"d": op.Price.D,
 		}
-		addAssetDetails(details, op.Buying, "buying_")
-		addAssetDetails(details, op.Selling, "selling_")
+		addAssetDetails(details, op.Buying, "buying_", operation.network)
+		addAssetDetails(details, op.Selling, "selling_", operation.network)
 	case xdr.OperationTypeSetOptions:
 		op := operation.operation.Body.MustSetOptionsOp()
 
@@ -1489,7 +1664,7 @@ func (operation *transactionOperationWrapper) Details() (map[string]interface{},
 		}
 
 		if op.Signer != nil {
-			details["signer_key"] = op.Signer.Key.Address()
+			addSignerKeyDetails(details, op.Signer.Key)
 			details["signer_weight"] = op.Signer.Weight
 		}
 	case xdr.OperationTypeChangeTrust:
@@ -1499,14 +1674,14 @@ func (operation *transactionOperationWrapper) Details() (map[string]interface{},
 				return nil, err
 			}
 		} else {
-			addAssetDetails(details, op.Line.ToAsset(), "")
+			addAssetDetails(details, op.Line.ToAsset(), "", operation.network)
 			details["trustee"] = details["asset_issuer"]
 		}
 		addAccountAndMuxedAccountDetails(details, *source, "trustor")
 		details["limit"] = amount.String(op.Limit)
 	case xdr.OperationTypeAllowTrust:
 		op := operation.operation.Body.MustAllowTrustOp()
-		addAssetDetails(details, op.Asset.ToAsset(source.ToAccountId()), "")
+		addAssetDetails(details, op.Asset.ToAsset(source.ToAccountId()), "", operation.network)
 		addAccountAndMuxedAccountDetails(details, *source, "trustee")
 		details["trustor"] = op.Trustor.Address()
 		details["authorize"] = xdr.TrustLineFlags(op.Authorize).IsAuthorized()
@@ -1521,6 +1696,11 @@ func (operation *transactionOperationWrapper) Details() (map[string]interface{},
 	case xdr.OperationTypeAccountMerge:
 		addAccountAndMuxedAccountDetails(details, *source, "account")
 		addAccountAndMuxedAccountDetails(details, operation.operation.Body.MustDestination(), "into")
+		if operation.transaction.Result.Successful() {
+			result := operation.OperationResult().MustAccountMergeResult()
+			details["amount"] = amount.String(result.MustSourceAccountBalance())
+			addAssetDetails(details, xdr.MustNewNativeAsset(), "", operation.network)
+		}
 	case xdr.OperationTypeInflation:
 		// no inflation details, presently
 	case xdr.OperationTypeManageData:
@@ -1547,6 +1727,15 @@ func (operation *transactionOperationWrapper) Details() (map[string]interface{},
 			})
 		}
 		details["claimants"] = claimants
+		if operation.transaction.Result.Successful() {
+			result := operation.OperationResult().MustCreateClaimableBalanceResult()
+			balanceID, err := xdr.MarshalHex(result.MustBalanceId())
+			if err != nil {
+				panic(fmt.Errorf("invalid balanceId in result of op: %d", operation.index))
+			}
+			details["balance_id"] = balanceID
+			details["balance_id_strkey"] = result.MustBalanceId().MustEncodeToStrkey()
+		}
 	case xdr.OperationTypeClaimClaimableBalance:
 		op := operation.operation.Body.MustClaimClaimableBalanceOp()
 		balanceID, err := xdr.MarshalHex(op.BalanceId)
@@ -1578,7 +1767,7 @@ func (operation *transactionOperationWrapper) Details() (map[string]interface{},
 		}
 	case xdr.OperationTypeClawback:
 		op := operation.operation.Body.MustClawbackOp()
-		addAssetDetails(details, op.Asset, "")
+		addAssetDetails(details, op.Asset, "", operation.network)
 		addAccountAndMuxedAccountDetails(details, op.From, "from")
 		details["amount"] = amount.String(op.Amount)
 	case xdr.OperationTypeClawbackClaimableBalance:
@@ -1592,7 +1781,7 @@ func (operation *transactionOperationWrapper) Details() (map[string]interface{},
 	case xdr.OperationTypeSetTrustLineFlags:
 		op := operation.operation.Body.MustSetTrustLineFlagsOp()
 		details["trustor"] = op.Trustor.Address()
-		addAssetDetails(details, op.Asset, "")
+		addAssetDetails(details, op.Asset, "", operation.network)
 		if op.SetFlags > 0 {
 			addTrustLineFlagDetails(details, xdr.TrustLineFlags(op.SetFlags), "set")
 		}
@@ -1701,16 +1890,20 @@ func (operation *transactionOperationWrapper) Details() (map[string]interface{},
 				return nil, err
 			}
 
-			transactionEnvelope := getTransactionV1Envelope(operation.transaction.Envelope)
-			details["ledger_key_hash"] = ledgerKeyHashFromTxEnvelope(transactionEnvelope)
+			footprint := operation.footprint()
+			details["ledger_key_hash"] = footprint.ledgerKeyHashes
 			details["contract_id"] = contractId
-			details["contract_code_hash"] = contractCodeHashFromTxEnvelope(transactionEnvelope)
+			details["contract_code_hash"] = footprint.contractCodeHash
 
 			details["parameters"], details["parameters_decoded"] = serializeParameters(args)
-			details["parameters_json"], details["parameters_json_decoded"], err = serializeScValArray(args)
+			var parametersTruncated bool
+			details["parameters_json"], details["parameters_json_decoded"], parametersTruncated, err = serializeScValArray(args)
 			if err != nil {
 				return nil, err
 			}
+			if parametersTruncated {
+				details["parameters_json_truncated"] = true
+			}
 
 			if balanceChanges, err := operation.parseAssetBalanceChangesFromContractEvents(); err != nil {
 				return nil, err
@@ -1722,10 +1915,10 @@ func (operation *transactionOperationWrapper) Details() (map[string]interface{},
 			args := op.HostFunction.MustCreateContract()
 			details["type"] = "create_contract"
 
-			transactionEnvelope := getTransactionV1Envelope(operation.transaction.Envelope)
-			details["ledger_key_hash"] = ledgerKeyHashFromTxEnvelope(transactionEnvelope)
-			details["contract_id"] = contractIdFromTxEnvelope(transactionEnvelope)
-			details["contract_code_hash"] = contractCodeHashFromTxEnvelope(transactionEnvelope)
+			footprint := operation.footprint()
+			details["ledger_key_hash"] = footprint.ledgerKeyHashes
+			details["contract_id"] = footprint.contractID
+			details["contract_code_hash"] = footprint.contractCodeHash
 
 			preimageTypeMap := switchContractIdPreimageType(args.ContractIdPreimage)
 			for key, val := range preimageTypeMap {
@@ -1735,24 +1928,28 @@ func (operation *transactionOperationWrapper) Details() (map[string]interface{},
 			}
 		case xdr.HostFunctionTypeHostFunctionTypeUploadContractWasm:
 			details["type"] = "upload_wasm"
-			transactionEnvelope := getTransactionV1Envelope(operation.transaction.Envelope)
-			details["ledger_key_hash"] = ledgerKeyHashFromTxEnvelope(transactionEnvelope)
-			details["contract_code_hash"] = contractCodeHashFromTxEnvelope(transactionEnvelope)
+			footprint := operation.footprint()
+			details["ledger_key_hash"] = footprint.ledgerKeyHashes
+			details["contract_code_hash"] = footprint.contractCodeHash
 		case xdr.HostFunctionTypeHostFunctionTypeCreateContractV2:
 			var err error
 			args := op.HostFunction.MustCreateContractV2()
 			details["type"] = "create_contract_v2"
 
-			transactionEnvelope := getTransactionV1Envelope(operation.transaction.Envelope)
-			details["ledger_key_hash"] = ledgerKeyHashFromTxEnvelope(transactionEnvelope)
-			details["contract_id"] = contractIdFromTxEnvelope(transactionEnvelope)
-			details["contract_code_hash"] = contractCodeHashFromTxEnvelope(transactionEnvelope)
+			footprint := operation.footprint()
+			details["ledger_key_hash"] = footprint.ledgerKeyHashes
+			details["contract_id"] = footprint.contractID
+			details["contract_code_hash"] = footprint.contractCodeHash
 
 			details["parameters"], details["parameters_decoded"] = serializeParameters(args.ConstructorArgs)
-			details["parameters_json"], details["parameters_json_decoded"], err = serializeScValArray(args.ConstructorArgs)
+			var parametersTruncated bool
+			details["parameters_json"], details["parameters_json_decoded"], parametersTruncated, err = serializeScValArray(args.ConstructorArgs)
 			if err != nil {
 				return nil, err
 			}
+			if parametersTruncated {
+				details["parameters_json_truncated"] = true
+			}
 
 			preimageTypeMap := switchContractIdPreimageType(args.ContractIdPreimage)
 			for key, val := range preimageTypeMap {
@@ -1768,17 +1965,17 @@ func (operation *transactionOperationWrapper) Details() (map[string]interface{},
 		details["type"] = "extend_footprint_ttl"
 		details["extend_to"] = op.ExtendTo
 
-		transactionEnvelope := getTransactionV1Envelope(operation.transaction.Envelope)
-		details["ledger_key_hash"] = ledgerKeyHashFromTxEnvelope(transactionEnvelope)
-		details["contract_id"] = contractIdFromTxEnvelope(transactionEnvelope)
-		details["contract_code_hash"] = contractCodeHashFromTxEnvelope(transactionEnvelope)
+		footprint := operation.footprint()
+		details["ledger_key_hash"] = footprint.ledgerKeyHashes
+		details["contract_id"] = footprint.contractID
+		details["contract_code_hash"] = footprint.contractCodeHash
 	case xdr.OperationTypeRestoreFootprint:
 		details["type"] = "restore_footprint"
 
-		transactionEnvelope := getTransactionV1Envelope(operation.transaction.Envelope)
-		details["ledger_key_hash"] = ledgerKeyHashFromTxEnvelope(transactionEnvelope)
-		details["contract_id"] = contractIdFromTxEnvelope(transactionEnvelope)
-		details["contract_code_hash"] = contractCodeHashFromTxEnvelope(transactionEnvelope)
+		footprint := operation.footprint()
+		details["ledger_key_hash"] = footprint.ledgerKeyHashes
+		details["contract_id"] = footprint.contractID
+		details["contract_code_hash"] = footprint.contractCodeHash
 	default:
 		panic(fmt.Errorf("unknown operation type: %s", operation.OperationType()))
 	}
@@ -1859,14 +2056,14 @@ func contractCodeHashFromTxEnvelope(transactionEnvelope xdr.TransactionV1Envelop
 func ledgerKeyHashFromTxEnvelope(transactionEnvelope xdr.TransactionV1Envelope) []string {
 	var ledgerKeyHash []string
 	for _, ledgerKey := range transactionEnvelope.Tx.Ext.SorobanData.Resources.Footprint.ReadOnly {
-		if utils.LedgerKeyToLedgerKeyHash(ledgerKey) != "" {
-			ledgerKeyHash = append(ledgerKeyHash, utils.LedgerKeyToLedgerKeyHash(ledgerKey))
+		if hash := utils.LedgerKeyToLedgerKeyHash(ledgerKey); hash != "" {
+			ledgerKeyHash = append(ledgerKeyHash, hash)
 		}
 	}
 
 	for _, ledgerKey := range transactionEnvelope.Tx.Ext.SorobanData.Resources.Footprint.ReadWrite {
-		if utils.LedgerKeyToLedgerKeyHash(ledgerKey) != "" {
-			ledgerKeyHash = append(ledgerKeyHash, utils.LedgerKeyToLedgerKeyHash(ledgerKey))
+		if hash := utils.LedgerKeyToLedgerKeyHash(ledgerKey); hash != "" {
+			ledgerKeyHash = append(ledgerKeyHash, hash)
 		}
 	}
 
@@ -1922,16 +2119,16 @@ func (operation *transactionOperationWrapper) parseAssetBalanceChangesFromContra
 			switch sacEvent.GetType() {
 			case contractevents.EventTypeTransfer:
 				transferEvt := sacEvent.(*contractevents.TransferEvent)
-				balanceChanges = append(balanceChanges, createSACBalanceChangeEntry(transferEvt.From, transferEvt.To, transferEvt.Amount, transferEvt.Asset, "transfer"))
+				balanceChanges = append(balanceChanges, createSACBalanceChangeEntry(transferEvt.From, transferEvt.To, transferEvt.Amount, transferEvt.Asset, "transfer", operation.network))
 			case contractevents.EventTypeMint:
 				mintEvt := sacEvent.(*contractevents.MintEvent)
-				balanceChanges = append(balanceChanges, createSACBalanceChangeEntry("", mintEvt.To, mintEvt.Amount, mintEvt.Asset, "mint"))
+				balanceChanges = append(balanceChanges, createSACBalanceChangeEntry("", mintEvt.To, mintEvt.Amount, mintEvt.Asset, "mint", operation.network))
 			case contractevents.EventTypeClawback:
 				clawbackEvt := sacEvent.(*contractevents.ClawbackEvent)
-				balanceChanges = append(balanceChanges, createSACBalanceChangeEntry(clawbackEvt.From, "", clawbackEvt.Amount, clawbackEvt.Asset, "clawback"))
+				balanceChanges = append(balanceChanges, createSACBalanceChangeEntry(clawbackEvt.From, "", clawbackEvt.Amount, clawbackEvt.Asset, "clawback", operation.network))
 			case contractevents.EventTypeBurn:
 				burnEvt := sacEvent.(*contractevents.BurnEvent)
-				balanceChanges = append(balanceChanges, createSACBalanceChangeEntry(burnEvt.From, "", burnEvt.Amount, burnEvt.Asset, "burn"))
+				balanceChanges = append(balanceChanges, createSACBalanceChangeEntry(burnEvt.From, "", burnEvt.Amount, burnEvt.Asset, "burn", operation.network))
 			}
 		}
 	}
@@ -1957,16 +2154,16 @@ func parseAssetBalanceChangesFromContractEvents(transaction ingest.LedgerTransac
 			switch sacEvent.GetType() {
 			case contractevents.EventTypeTransfer:
 				transferEvt := sacEvent.(*contractevents.TransferEvent)
-				balanceChanges = append(balanceChanges, createSACBalanceChangeEntry(transferEvt.From, transferEvt.To, transferEvt.Amount, transferEvt.Asset, "transfer"))
+				balanceChanges = append(balanceChanges, createSACBalanceChangeEntry(transferEvt.From, transferEvt.To, transferEvt.Amount, transferEvt.Asset, "transfer", network))
 			case contractevents.EventTypeMint:
 				mintEvt := sacEvent.(*contractevents.MintEvent)
-				balanceChanges = append(balanceChanges, createSACBalanceChangeEntry("", mintEvt.To, mintEvt.Amount, mintEvt.Asset, "mint"))
+				balanceChanges = append(balanceChanges, createSACBalanceChangeEntry("", mintEvt.To, mintEvt.Amount, mintEvt.Asset, "mint", network))
 			case contractevents.EventTypeClawback:
 				clawbackEvt := sacEvent.(*contractevents.ClawbackEvent)
-				balanceChanges = append(balanceChanges, createSACBalanceChangeEntry(clawbackEvt.From, "", clawbackEvt.Amount, clawbackEvt.Asset, "clawback"))
+				balanceChanges = append(balanceChanges, createSACBalanceChangeEntry(clawbackEvt.From, "", clawbackEvt.Amount, clawbackEvt.Asset, "clawback", network))
 			case contractevents.EventTypeBurn:
 				burnEvt := sacEvent.(*contractevents.BurnEvent)
-				balanceChanges = append(balanceChanges, createSACBalanceChangeEntry(burnEvt.From, "", burnEvt.Amount, burnEvt.Asset, "burn"))
+				balanceChanges = append(balanceChanges, createSACBalanceChangeEntry(burnEvt.From, "", burnEvt.Amount, burnEvt.Asset, "burn", network))
 			}
 		}
 	}
@@ -1979,9 +2176,10 @@ func parseAssetBalanceChangesFromContractEvents(transaction ingest.LedgerTransac
 // amountChanged - absolute value that asset balance changed
 // asset         - the fully qualified issuer:code for asset that had balance change
 // changeType    - the type of source sac event that triggered this change
+// network       - the network passphrase, which the contract id of the asset is derived from
 //
 // return        - a balance changed record expressed as map of key/value's
-func createSACBalanceChangeEntry(fromAccount string, toAccount string, amountChanged xdr.Int128Parts, asset xdr.Asset, changeType string) map[string]interface{} {
+func createSACBalanceChangeEntry(fromAccount string, toAccount string, amountChanged xdr.Int128Parts, asset xdr.Asset, changeType string, network string) map[string]interface{} {
 	balanceChange := map[string]interface{}{}
 
 	if fromAccount != "" {
@@ -1993,12 +2191,12 @@ func createSACBalanceChangeEntry(fromAccount string, toAccount string, amountCha
 
 	balanceChange["type"] = changeType
 	balanceChange["amount"] = amount.String128(amountChanged)
-	addAssetDetails(balanceChange, asset, "")
+	addAssetDetailsToOperationDetails(balanceChange, asset, "", network)
 	return balanceChange
 }
 
 // addAssetDetails sets the details for `a` on `result` using keys with `prefix`
-func addAssetDetails(result map[string]interface{}, a xdr.Asset, prefix string) error {
+func addAssetDetails(result map[string]interface{}, a xdr.Asset, prefix string, network string) error {
 	var (
 		assetType string
 		code      string
@@ -2011,6 +2209,10 @@ func addAssetDetails(result map[string]interface{}, a xdr.Asset, prefix string)
 	}
 	result[prefix+"asset_type"] = assetType
 
+	if err := addAssetContractID(result, a, prefix, network); err != nil {
+		return err
+	}
+
 	if a.Type == xdr.AssetTypeAssetTypeNative {
 		return nil
 	}
@@ -2020,6 +2222,19 @@ func addAssetDetails(result map[string]interface{}, a xdr.Asset, prefix string)
 	return nil
 }
 
+// addAssetContractID sets the id of the Stellar Asset Contract of `a` on the network on `result`, so that classic
+// assets can be joined to the contract data and events of their contract
+func addAssetContractID(result map[string]interface{}, a xdr.Asset, prefix string, network string) error {
+	contractID, err := utils.AssetContractID(a, network)
+	if err != nil {
+		return errors.Wrap(err, "xdr.Asset.ContractID error")
+	}
+	if contractID != "" {
+		result[prefix+"asset_contract_id"] = contractID
+	}
+	return nil
+}
+
 // addAuthFlagDetails adds the account flag details for `f` on `result`.
 func addAuthFlagDetails(result map[string]interface{}, f xdr.AccountFlags, prefix string) {
 	var (
@@ -2123,6 +2338,59 @@ func addLedgerKeyDetails(result map[string]interface{}, ledgerKey xdr.LedgerKey)
 	return nil
 }
 
+// Roles of the participants of an operation
+const (
+	participantRoleSource          = "source"
+	participantRoleDestination     = "destination"
+	participantRoleClaimant        = "claimant"
+	participantRoleTrustor         = "trustor"
+	participantRoleSponsored       = "sponsored"
+	participantRoleSponsor         = "sponsor"
+	participantRoleEntryOwner      = "entry_owner"
+	participantRoleClawbackFrom    = "clawback_from"
+	participantRoleReserveSponsor  = "reserve_sponsor"
+	participantRoleInvokedContract = "invoked_contract"
+	participantRoleAuthorizer      = "authorizer"
+	participantRoleSACSender       = "sac_sender"
+	participantRoleSACRecipient    = "sac_recipient"
+)
+
+// participant is an account or contract taking part in an operation
+type participant struct {
+	address string // G or C strkey
+	muxed   string // M strkey, if the account was given as a muxed account
+	role    string
+}
+
+func accountParticipant(accountId xdr.AccountId, role string) participant {
+	return participant{address: accountId.Address(), role: role}
+}
+
+func muxedAccountParticipant(account xdr.MuxedAccount, role string) (participant, error) {
+	address, err := utils.GetAccountAddressFromMuxedAccount(account)
+	if err != nil {
+		return participant{}, err
+	}
+
+	var muxed string
+	if account.Type == xdr.CryptoKeyTypeKeyTypeMuxedEd25519 {
+		muxed, err = account.GetAddress()
+		if err != nil {
+			return participant{}, err
+		}
+	}
+
+	return participant{address: address, muxed: muxed, role: role}, nil
+}
+
+func scAddressParticipant(address xdr.ScAddress, role string) (participant, error) {
+	strkeyAddress, err := address.String()
+	if err != nil {
+		return participant{}, err
+	}
+	return participant{address: strkeyAddress, role: role}, nil
+}
+
 func getLedgerKeyParticipants(ledgerKey xdr.LedgerKey) []xdr.AccountId {
 	var result []xdr.AccountId
 	switch ledgerKey.Type {
@@ -2140,21 +2408,40 @@ func getLedgerKeyParticipants(ledgerKey xdr.LedgerKey) []xdr.AccountId {
 	return result
 }
 
-// Participants returns the accounts taking part in the operation.
-func (operation *transactionOperationWrapper) Participants() ([]xdr.AccountId, error) {
-	participants := []xdr.AccountId{}
-	participants = append(participants, operation.SourceAccount().ToAccountId())
+// Participants returns the accounts and contracts taking part in the operation, along with their roles. Muxed
+// accounts keep their muxed address.
+func (operation *transactionOperationWrapper) Participants() ([]participant, error) {
+	source, err := muxedAccountParticipant(*operation.SourceAccount(), participantRoleSource)
+	if err != nil {
+		return nil, err
+	}
+	participants := []participant{source}
 	op := operation.operation
 
+	addMuxed := func(account xdr.MuxedAccount, role string) error {
+		p, err := muxedAccountParticipant(account, role)
+		if err != nil {
+			return err
+		}
+		participants = append(participants, p)
+		return nil
+	}
+
 	switch operation.OperationType() {
 	case xdr.OperationTypeCreateAccount:
-		participants = append(participants, op.Body.MustCreateAccountOp().Destination)
+		participants = append(participants, accountParticipant(op.Body.MustCreateAccountOp().Destination, participantRoleDestination))
 	case xdr.OperationTypePayment:
-		participants = append(participants, op.Body.MustPaymentOp().Destination.ToAccountId())
+		if err := addMuxed(op.Body.MustPaymentOp().Destination, participantRoleDestination); err != nil {
+			return nil, err
+		}
 	case xdr.OperationTypePathPaymentStrictReceive:
-		participants = append(participants, op.Body.MustPathPaymentStrictReceiveOp().Destination.ToAccountId())
+		if err := addMuxed(op.Body.MustPathPaymentStrictReceiveOp().Destination, participantRoleDestination); err != nil {
+			return nil, err
+		}
 	case xdr.OperationTypePathPaymentStrictSend:
-		participants = append(participants, op.Body.MustPathPaymentStrictSendOp().Destination.ToAccountId())
+		if err := addMuxed(op.Body.MustPathPaymentStrictSendOp().Destination, participantRoleDestination); err != nil {
+			return nil, err
+		}
 	case xdr.OperationTypeManageBuyOffer:
 		// the only direct participant is the source_account
 	case xdr.OperationTypeManageSellOffer:
@@ -2166,9 +2453,11 @@ func (operation *transactionOperationWrapper) Participants() ([]xdr.AccountId, e
 	case xdr.OperationTypeChangeTrust:
 		// the only direct participant is the source_account
 	case xdr.OperationTypeAllowTrust:
-		participants = append(participants, op.Body.MustAllowTrustOp().Trustor)
+		participants = append(participants, accountParticipant(op.Body.MustAllowTrustOp().Trustor, participantRoleTrustor))
 	case xdr.OperationTypeAccountMerge:
-		participants = append(participants, op.Body.MustDestination().ToAccountId())
+		if err := addMuxed(op.Body.MustDestination(), participantRoleDestination); err != nil {
+			return nil, err
+		}
 	case xdr.OperationTypeInflation:
 		// the only direct participant is the source_account
 	case xdr.OperationTypeManageData:
@@ -2177,41 +2466,50 @@ func (operation *transactionOperationWrapper) Participants() ([]xdr.AccountId, e
 		// the only direct participant is the source_account
 	case xdr.OperationTypeCreateClaimableBalance:
 		for _, c := range op.Body.MustCreateClaimableBalanceOp().Claimants {
-			participants = append(participants, c.MustV0().Destination)
+			participants = append(participants, accountParticipant(c.MustV0().Destination, participantRoleClaimant))
 		}
 	case xdr.OperationTypeClaimClaimableBalance:
 		// the only direct participant is the source_account
 	case xdr.OperationTypeBeginSponsoringFutureReserves:
-		participants = append(participants, op.Body.MustBeginSponsoringFutureReservesOp().SponsoredId)
+		participants = append(participants, accountParticipant(op.Body.MustBeginSponsoringFutureReservesOp().SponsoredId, participantRoleSponsored))
 	case xdr.OperationTypeEndSponsoringFutureReserves:
 		beginSponsorshipOp := operation.findInitatingBeginSponsoringOp()
 		if beginSponsorshipOp != nil {
-			participants = append(participants, beginSponsorshipOp.SourceAccount().ToAccountId())
+			if err := addMuxed(*beginSponsorshipOp.SourceAccount(), participantRoleSponsor); err != nil {
+				return nil, err
+			}
 		}
 	case xdr.OperationTypeRevokeSponsorship:
 		op := operation.operation.Body.MustRevokeSponsorshipOp()
 		switch op.Type {
 		case xdr.RevokeSponsorshipTypeRevokeSponsorshipLedgerEntry:
-			participants = append(participants, getLedgerKeyParticipants(*op.LedgerKey)...)
+			for _, owner := range getLedgerKeyParticipants(*op.LedgerKey) {
+				participants = append(participants, accountParticipant(owner, participantRoleEntryOwner))
+			}
 		case xdr.RevokeSponsorshipTypeRevokeSponsorshipSigner:
-			participants = append(participants, op.Signer.AccountId)
+			participants = append(participants, accountParticipant(op.Signer.AccountId, participantRoleEntryOwner))
 			// We don't add signer as a participant because a signer can be arbitrary account.
 			// This can spam successful operations history of any account.
 		}
 	case xdr.OperationTypeClawback:
-		op := operation.operation.Body.MustClawbackOp()
-		participants = append(participants, op.From.ToAccountId())
+		if err := addMuxed(operation.operation.Body.MustClawbackOp().From, participantRoleClawbackFrom); err != nil {
+			return nil, err
+		}
 	case xdr.OperationTypeClawbackClaimableBalance:
 		// the only direct participant is the source_account
 	case xdr.OperationTypeSetTrustLineFlags:
 		op := operation.operation.Body.MustSetTrustLineFlagsOp()
-		participants = append(participants, op.Trustor)
+		participants = append(participants, accountParticipant(op.Trustor, participantRoleTrustor))
 	case xdr.OperationTypeLiquidityPoolDeposit:
 		// the only direct participant is the source_account
 	case xdr.OperationTypeLiquidityPoolWithdraw:
 		// the only direct participant is the source_account
 	case xdr.OperationTypeInvokeHostFunction:
-		// the only direct participant is the source_account
+		sorobanParticipants, err := operation.sorobanParticipants()
+		if err != nil {
+			return nil, err
+		}
+		participants = append(participants, sorobanParticipants...)
 	case xdr.OperationTypeExtendFootprintTtl:
 		// the only direct participant is the source_account
 	case xdr.OperationTypeRestoreFootprint:
@@ -2225,21 +2523,67 @@ func (operation *transactionOperationWrapper) Participants() ([]xdr.AccountId, e
 		return nil, err
 	}
 	if sponsor != nil {
-		participants = append(participants, *sponsor)
+		participants = append(participants, accountParticipant(*sponsor, participantRoleReserveSponsor))
 	}
 
 	return dedupeParticipants(participants), nil
 }
 
-// dedupeParticipants remove any duplicate ids from `in`
-func dedupeParticipants(in []xdr.AccountId) (out []xdr.AccountId) {
-	set := map[string]xdr.AccountId{}
-	for _, id := range in {
-		set[id.Address()] = id
+// sorobanParticipants returns the invoked contract, the addresses that authorized the invocation, and the senders and
+// recipients of the stellar asset contract events emitted by the invocation
+func (operation *transactionOperationWrapper) sorobanParticipants() ([]participant, error) {
+	participants := []participant{}
+	invokeHostFunctionOp := operation.operation.Body.MustInvokeHostFunctionOp()
+
+	if invokeArgs, ok := invokeHostFunctionOp.HostFunction.GetInvokeContract(); ok {
+		p, err := scAddressParticipant(invokeArgs.ContractAddress, participantRoleInvokedContract)
+		if err != nil {
+			return nil, err
+		}
+		participants = append(participants, p)
 	}
 
-	for _, id := range set {
-		out = append(out, id)
+	for _, authEntry := range invokeHostFunctionOp.Auth {
+		// Entries with source account credentials are authorized by the source account, which is already a participant
+		if address, ok := authEntry.Credentials.GetAddress(); ok {
+			p, err := scAddressParticipant(address.Address, participantRoleAuthorizer)
+			if err != nil {
+				return nil, err
+			}
+			participants = append(participants, p)
+		}
+	}
+
+	// Failed invocations don't emit events
+	if !operation.transaction.Successful() {
+		return participants, nil
+	}
+
+	balanceChanges, err := operation.parseAssetBalanceChangesFromContractEvents()
+	if err != nil {
+		return nil, err
+	}
+	for _, balanceChange := range balanceChanges {
+		if from, ok := balanceChange["from"].(string); ok {
+			participants = append(participants, participant{address: from, role: participantRoleSACSender})
+		}
+		if to, ok := balanceChange["to"].(string); ok {
+			participants = append(participants, participant{address: to, role: participantRoleSACRecipient})
+		}
+	}
+
+	return participants, nil
+}
+
+// dedupeParticipants remove any duplicate participants from `in`, keeping the order in which they were first found
+func dedupeParticipants(in []participant) (out []participant) {
+	seen := map[participant]bool{}
+	for _, p := range in {
+		if seen[p] {
+			continue
+		}
+		seen[p] = true
+		out = append(out, p)
 	}
 	return
 }
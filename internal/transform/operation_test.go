@@ -9,6 +9,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/stellar/go-stellar-sdk/ingest"
+	"github.com/stellar/go-stellar-sdk/network"
 	"github.com/stellar/go-stellar-sdk/xdr"
 )
 
@@ -74,6 +75,81 @@ func TestTransformOperation(t *testing.T) {
 	}
 }
 
+func TestAddAssetDetailsToOperationDetails(t *testing.T) {
+	details := map[string]interface{}{}
+	assert.NoError(t, addAssetDetailsToOperationDetails(details, xdr.MustNewNativeAsset(), "selling", network.TestNetworkPassphrase))
+	assert.Equal(t, map[string]interface{}{
+		"selling_asset_type":        "native",
+		"selling_asset_id":          int64(-5706705804583548011),
+		"selling_asset_contract_id": "CDLZFC3SYJYDZT7K67VZ75HPJVIEUVNIXF47ZG2FB2RMQQVU2HHGCYSC",
+	}, details)
+
+	details = map[string]interface{}{}
+	assert.NoError(t, addAssetDetails(details, xdr.MustNewNativeAsset(), "bought_", network.PublicNetworkPassphrase))
+	assert.Equal(t, map[string]interface{}{
+		"bought_asset_type":        "native",
+		"bought_asset_contract_id": "CAS3J7GYLGXMF6TDJBBYYSE3HQ6BBSMLNUQ34T6TZMYMW2EVH34XOWMA",
+	}, details)
+}
+
+func TestAddSignerKeyDetails(t *testing.T) {
+	details := map[string]interface{}{}
+	key := xdr.SignerKey{
+		Type: xdr.SignerKeyTypeSignerKeyTypeEd25519SignedPayload,
+		Ed25519SignedPayload: &xdr.SignerKeyEd25519SignedPayload{
+			Ed25519: xdr.Uint256{},
+			Payload: []byte{1, 2, 3, 4},
+		},
+	}
+	assert.NoError(t, addSignerKeyDetails(details, key))
+	assert.Equal(t, map[string]interface{}{
+		"signer_key":             key.Address(),
+		"signer_type":            "ed25519_signed_payload",
+		"signer_payload_account": "GAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAWHF",
+		"signer_payload":         "AQIDBA==",
+	}, details)
+
+	details = map[string]interface{}{}
+	assert.NoError(t, addSignerKeyDetails(details, xdr.SignerKey{Type: xdr.SignerKeyTypeSignerKeyTypeHashX, HashX: &xdr.Uint256{}}))
+	assert.Equal(t, "hash_x", details["signer_type"])
+}
+
+func TestDecodeManageDataValue(t *testing.T) {
+	manageData := func(value interface{}) OperationOutput {
+		return OperationOutput{Type: int32(xdr.OperationTypeManageData), OperationDetails: map[string]interface{}{"name": "config", "value": value}}
+	}
+
+	text := manageData(base64.StdEncoding.EncodeToString([]byte("héllo")))
+	assert.NoError(t, DecodeManageDataValue(text))
+	assert.Equal(t, map[string]interface{}{"name": "config", "value": "aMOpbGxv", "value_decoded": "héllo", "value_is_utf8": true}, text.OperationDetails)
+
+	binary := manageData(base64.StdEncoding.EncodeToString([]byte{0xff, 0x00, 0x10}))
+	assert.NoError(t, DecodeManageDataValue(binary))
+	assert.Equal(t, map[string]interface{}{"name": "config", "value": "/wAQ", "value_decoded": "ff0010", "value_is_utf8": false}, binary.OperationDetails)
+
+	deleted := manageData(nil)
+	assert.NoError(t, DecodeManageDataValue(deleted))
+	assert.Equal(t, map[string]interface{}{"name": "config", "value": nil}, deleted.OperationDetails)
+
+	assert.EqualError(t, DecodeManageDataValue(manageData("not base64!")), "could not decode manage_data value: illegal base64 data at input byte 3")
+}
+
+func TestTransformOperationWithoutDetails(t *testing.T) {
+	hardCodedInputTransaction, err := makeOperationTestInput()
+	assert.NoError(t, err)
+	hardCodedOutputArray := makeOperationTestOutputs()
+
+	for i, op := range hardCodedInputTransaction.Envelope.Operations() {
+		actualOutput, actualError := TransformOperationWithoutDetails(op, int32(i), hardCodedInputTransaction, 0, makeLedgerCloseMeta())
+		assert.NoError(t, actualError)
+
+		expectedOutput := hardCodedOutputArray[i]
+		expectedOutput.OperationDetails = nil
+		expectedOutput.OperationDetailsJSON = nil
+		assert.Equal(t, expectedOutput, actualOutput)
+	}
+}
+
 func makeLedgerCloseMeta() (ledgerCloseMeta xdr.LedgerCloseMeta) {
 	return xdr.LedgerCloseMeta{
 		V: 0,
@@ -115,6 +191,7 @@ func makeOperationTestInput() (inputTransaction ingest.LedgerTransaction, err er
 	hardCodedLowThresh := xdr.Uint32(1)
 	hardCodedMedThresh := xdr.Uint32(3)
 	hardCodedHighThresh := xdr.Uint32(5)
+	hardCodedMergedBalance := xdr.Int64(125000000)
 	hardCodedHomeDomain := xdr.String32("2019=DRA;n-test")
 	hardCodedSignerKey, err := xdr.NewSignerKey(xdr.SignerKeyTypeSignerKeyTypeEd25519, xdr.Uint256([32]byte{}))
 	if err != nil {
@@ -756,7 +833,8 @@ func makeOperationTestInput() (inputTransaction ingest.LedgerTransaction, err er
 			Tr: &xdr.OperationResultTr{
 				Type: xdr.OperationTypeAccountMerge,
 				AccountMergeResult: &xdr.AccountMergeResult{
-					Code: xdr.AccountMergeResultCodeAccountMergeSuccess,
+					Code:                 xdr.AccountMergeResultCodeAccountMergeSuccess,
+					SourceAccountBalance: &hardCodedMergedBalance,
 				},
 			},
 		},
@@ -813,7 +891,8 @@ func makeOperationTestInput() (inputTransaction ingest.LedgerTransaction, err er
 			Tr: &xdr.OperationResultTr{
 				Type: xdr.OperationTypeCreateClaimableBalance,
 				CreateClaimableBalanceResult: &xdr.CreateClaimableBalanceResult{
-					Code: xdr.CreateClaimableBalanceResultCodeCreateClaimableBalanceSuccess,
+					Code:      xdr.CreateClaimableBalanceResultCodeCreateClaimableBalanceSuccess,
+					BalanceId: &hardCodedClaimableBalance,
 				},
 			},
 		},
@@ -1227,6 +1306,7 @@ func makeOperationTestOutputs() (transformedOperations []OperationOutput) {
 				"high_threshold":    uint32(5),
 				"home_domain":       "2019=DRA;n-test",
 				"signer_key":        "GAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAWHF",
+				"signer_type":       "ed25519",
 				"signer_weight":     uint32(1),
 			},
 			ClosedAt:            hardCodedLedgerClose,
@@ -1245,6 +1325,7 @@ func makeOperationTestOutputs() (transformedOperations []OperationOutput) {
 				"high_threshold":    uint32(5),
 				"home_domain":       "2019=DRA;n-test",
 				"signer_key":        "GAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAWHF",
+				"signer_type":       "ed25519",
 				"signer_weight":     uint32(1),
 			},
 		},
@@ -1338,16 +1419,22 @@ func makeOperationTestOutputs() (transformedOperations []OperationOutput) {
 			TransactionID: 4096,
 			OperationID:   4107,
 			OperationDetails: map[string]interface{}{
-				"account": hardCodedSourceAccountAddress,
-				"into":    hardCodedDestAccountAddress,
+				"account":    hardCodedSourceAccountAddress,
+				"into":       hardCodedDestAccountAddress,
+				"amount":     12.5,
+				"asset_type": "native",
+				"asset_id":   int64(-5706705804583548011),
 			},
 			ClosedAt:            hardCodedLedgerClose,
 			OperationResultCode: "OperationResultCodeOpInner",
 			OperationTraceCode:  "AccountMergeResultCodeAccountMergeSuccess",
 			LedgerSequence:      0,
 			OperationDetailsJSON: map[string]interface{}{
-				"account": hardCodedSourceAccountAddress,
-				"into":    hardCodedDestAccountAddress,
+				"account":    hardCodedSourceAccountAddress,
+				"into":       hardCodedDestAccountAddress,
+				"amount":     12.5,
+				"asset_type": "native",
+				"asset_id":   int64(-5706705804583548011),
 			},
 		},
 		{
@@ -1482,18 +1569,24 @@ func makeOperationTestOutputs() (transformedOperations []OperationOutput) {
 			TransactionID: 4096,
 			OperationID:   4113,
 			OperationDetails: map[string]interface{}{
-				"asset":     "USDT:GBVVRXLMNCJQW3IDDXC3X6XCH35B5Q7QXNMMFPENSOGUPQO7WO7HGZPA",
-				"amount":    123456.789,
-				"claimants": []Claimant{testClaimantDetails},
+				"asset":             "USDT:GBVVRXLMNCJQW3IDDXC3X6XCH35B5Q7QXNMMFPENSOGUPQO7WO7HGZPA",
+				"asset_id":          int64(-8205667356306085451),
+				"amount":            123456.789,
+				"claimants":         []Claimant{testClaimantDetails},
+				"balance_id":        "000000000102030405060708090000000000000000000000000000000000000000000000",
+				"balance_id_strkey": "BAAACAQDAQCQMBYIBEAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAACPGI",
 			},
 			ClosedAt:            hardCodedLedgerClose,
 			OperationResultCode: "OperationResultCodeOpInner",
 			OperationTraceCode:  "CreateClaimableBalanceResultCodeCreateClaimableBalanceSuccess",
 			LedgerSequence:      0,
 			OperationDetailsJSON: map[string]interface{}{
-				"asset":     "USDT:GBVVRXLMNCJQW3IDDXC3X6XCH35B5Q7QXNMMFPENSOGUPQO7WO7HGZPA",
-				"amount":    123456.789,
-				"claimants": []Claimant{testClaimantDetails},
+				"asset":             "USDT:GBVVRXLMNCJQW3IDDXC3X6XCH35B5Q7QXNMMFPENSOGUPQO7WO7HGZPA",
+				"asset_id":          int64(-8205667356306085451),
+				"amount":            123456.789,
+				"claimants":         []Claimant{testClaimantDetails},
+				"balance_id":        "000000000102030405060708090000000000000000000000000000000000000000000000",
+				"balance_id_strkey": "BAAACAQDAQCQMBYIBEAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAACPGI",
 			},
 		},
 		{
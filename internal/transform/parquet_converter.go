@@ -10,6 +10,8 @@ package transform
 
 import (
 	"encoding/json"
+	"reflect"
+	"strconv"
 )
 
 type SchemaParquet interface {
@@ -24,6 +26,58 @@ func toJSONString(v interface{}) string {
 	return string(jsonData)
 }
 
+// detailString returns a string detail, or an empty string if the detail isn't set
+func detailString(details map[string]interface{}, key string) string {
+	value, ok := details[key].(string)
+	if !ok {
+		return ""
+	}
+	return value
+}
+
+// detailFloat returns a numeric detail as a float64. Some details, such as amounts, are stored as decimal strings.
+func detailFloat(details map[string]interface{}, key string) float64 {
+	if value, ok := details[key].(string); ok {
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return 0
+		}
+		return parsed
+	}
+
+	value := reflect.ValueOf(details[key])
+	switch value.Kind() {
+	case reflect.Float32, reflect.Float64:
+		return value.Float()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(value.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(value.Uint())
+	}
+	return 0
+}
+
+// detailInt returns an integer detail as an int64. Some details, such as bump_to, are stored as strings, and others
+// use xdr types such as xdr.Int64.
+func detailInt(details map[string]interface{}, key string) int64 {
+	if value, ok := details[key].(string); ok {
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return 0
+		}
+		return parsed
+	}
+
+	value := reflect.ValueOf(details[key])
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return value.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int64(value.Uint())
+	}
+	return 0
+}
+
 func (lo LedgerOutput) ToParquet() interface{} {
 	return LedgerOutputParquet{
 		// Note that uint32 data types need to be converted to int64 due to restrictions
@@ -39,6 +93,10 @@ func (lo LedgerOutput) ToParquet() interface{} {
 		FailedTransactionCount:          lo.FailedTransactionCount,
 		TxSetOperationCount:             lo.TxSetOperationCount,
 		ClosedAt:                        lo.ClosedAt.UnixMilli(),
+		TxSetHash:                       lo.TxSetHash,
+		ClassicPhaseTransactionCount:    lo.ClassicPhaseTransactionCount,
+		SorobanPhaseTransactionCount:    lo.SorobanPhaseTransactionCount,
+		ApplyOrderDiffers:               lo.ApplyOrderDiffers,
 		TotalCoins:                      lo.TotalCoins,
 		FeePool:                         lo.FeePool,
 		BaseFee:                         int64(lo.BaseFee),
@@ -81,6 +139,9 @@ func (to TransactionOutput) ToParquet() interface{} {
 		InnerTransactionHash:                 to.InnerTransactionHash,
 		NewMaxFee:                            int64(to.NewMaxFee),
 		LedgerBounds:                         to.LedgerBounds,
+		ValidAfter:                           to.ValidAfter.Bool,
+		ValidBefore:                          to.ValidBefore.Bool,
+		WithinLedgerBounds:                   to.WithinLedgerBounds.Bool,
 		MinAccountSequence:                   to.MinAccountSequence.Int64,
 		MinAccountSequenceAge:                to.MinAccountSequenceAge.Int64,
 		MinAccountSequenceLedgerGap:          to.MinAccountSequenceLedgerGap.Int64,
@@ -96,9 +157,14 @@ func (to TransactionOutput) ToParquet() interface{} {
 		InclusionFeeBid:                      to.InclusionFeeBid,
 		InclusionFeeCharged:                  to.InclusionFeeCharged,
 		ResourceFeeRefund:                    to.ResourceFeeRefund,
+		ResourceFeeCharged:                   to.ResourceFeeCharged,
 		TotalNonRefundableResourceFeeCharged: to.TotalNonRefundableResourceFeeCharged,
 		TotalRefundableResourceFeeCharged:    to.TotalRefundableResourceFeeCharged,
 		RentFeeCharged:                       to.RentFeeCharged,
+		ContractEventsCount:                  to.ContractEventsCount,
+		DiagnosticEventsCount:                to.DiagnosticEventsCount,
+		IsSoroban:                            to.IsSoroban,
+		SorobanResourcesUsed:                 to.SorobanResourcesUsed,
 	}
 }
 
@@ -122,25 +188,31 @@ func (ao AccountOutput) ToParquet() interface{} {
 		Sponsor:              ao.Sponsor.String,
 		NumSponsored:         int64(ao.NumSponsored),
 		NumSponsoring:        int64(ao.NumSponsoring),
+		MinimumBalance:       ao.MinimumBalance,
+		AvailableBalance:     ao.AvailableBalance,
 		LastModifiedLedger:   int64(ao.LastModifiedLedger),
 		LedgerEntryChange:    int64(ao.LedgerEntryChange),
 		Deleted:              ao.Deleted,
 		ClosedAt:             ao.ClosedAt.UnixMilli(),
 		LedgerSequence:       int64(ao.LedgerSequence),
+		LedgerKeyHash:        ao.LedgerKeyHash,
+		LedgerKeyHashBase64:  ao.LedgerKeyHashBase64,
 	}
 }
 
 func (aso AccountSignerOutput) ToParquet() interface{} {
 	return AccountSignerOutputParquet{
-		AccountID:          aso.AccountID,
-		Signer:             aso.Signer,
-		Weight:             aso.Weight,
-		Sponsor:            aso.Sponsor.String,
-		LastModifiedLedger: int64(aso.LastModifiedLedger),
-		LedgerEntryChange:  int64(aso.LedgerEntryChange),
-		Deleted:            aso.Deleted,
-		ClosedAt:           aso.ClosedAt.UnixMilli(),
-		LedgerSequence:     int64(aso.LedgerSequence),
+		AccountID:           aso.AccountID,
+		Signer:              aso.Signer,
+		Weight:              aso.Weight,
+		Sponsor:             aso.Sponsor.String,
+		LastModifiedLedger:  int64(aso.LastModifiedLedger),
+		LedgerEntryChange:   int64(aso.LedgerEntryChange),
+		Deleted:             aso.Deleted,
+		ClosedAt:            aso.ClosedAt.UnixMilli(),
+		LedgerSequence:      int64(aso.LedgerSequence),
+		LedgerKeyHash:       aso.LedgerKeyHash,
+		LedgerKeyHashBase64: aso.LedgerKeyHashBase64,
 	}
 }
 
@@ -157,31 +229,120 @@ func (oo OperationOutput) ToParquet() interface{} {
 		OperationResultCode: oo.OperationResultCode,
 		OperationTraceCode:  oo.OperationTraceCode,
 		LedgerSequence:      int64(oo.LedgerSequence),
+		ParentOperationID:   oo.ParentOperationID,
+	}
+}
+
+func (fo FlattenedOperationOutput) ToParquet() interface{} {
+	details := fo.OperationDetails
+	return FlattenedOperationOutputParquet{
+		SourceAccount:       fo.SourceAccount,
+		SourceAccountMuxed:  fo.SourceAccountMuxed,
+		Type:                fo.Type,
+		TypeString:          fo.TypeString,
+		OperationDetails:    toJSONString(fo.OperationDetails),
+		TransactionID:       fo.TransactionID,
+		OperationID:         fo.OperationID,
+		ClosedAt:            fo.ClosedAt.UnixMilli(),
+		OperationResultCode: fo.OperationResultCode,
+		OperationTraceCode:  fo.OperationTraceCode,
+		LedgerSequence:      int64(fo.LedgerSequence),
+		ParentOperationID:   fo.ParentOperationID,
+		Amount:              detailFloat(details, "amount"),
+		AssetCode:           detailString(details, "asset_code"),
+		AssetIssuer:         detailString(details, "asset_issuer"),
+		AssetType:           detailString(details, "asset_type"),
+		AssetID:             detailInt(details, "asset_id"),
+		SourceAmount:        detailFloat(details, "source_amount"),
+		SourceMax:           detailFloat(details, "source_max"),
+		DestinationMin:      detailFloat(details, "destination_min"),
+		SourceAssetCode:     detailString(details, "source_asset_code"),
+		SourceAssetIssuer:   detailString(details, "source_asset_issuer"),
+		SourceAssetType:     detailString(details, "source_asset_type"),
+		SourceAssetID:       detailInt(details, "source_asset_id"),
+		From:                detailString(details, "from"),
+		To:                  detailString(details, "to"),
+		Account:             detailString(details, "account"),
+		Funder:              detailString(details, "funder"),
+		Into:                detailString(details, "into"),
+		StartingBalance:     detailFloat(details, "starting_balance"),
+		SellingAssetCode:    detailString(details, "selling_asset_code"),
+		SellingAssetIssuer:  detailString(details, "selling_asset_issuer"),
+		SellingAssetType:    detailString(details, "selling_asset_type"),
+		SellingAssetID:      detailInt(details, "selling_asset_id"),
+		BuyingAssetCode:     detailString(details, "buying_asset_code"),
+		BuyingAssetIssuer:   detailString(details, "buying_asset_issuer"),
+		BuyingAssetType:     detailString(details, "buying_asset_type"),
+		BuyingAssetID:       detailInt(details, "buying_asset_id"),
+		Price:               detailFloat(details, "price"),
+		OfferID:             detailInt(details, "offer_id"),
+		Trustor:             detailString(details, "trustor"),
+		Trustee:             detailString(details, "trustee"),
+		Limit:               detailFloat(details, "limit"),
+		BumpTo:              detailInt(details, "bump_to"),
+		BalanceID:           detailString(details, "balance_id"),
+		LiquidityPoolID:     detailString(details, "liquidity_pool_id"),
+		Shares:              detailFloat(details, "shares"),
+		Function:            detailString(details, "function"),
+		ContractID:          detailString(details, "contract_id"),
 	}
 }
 
 func (po PoolOutput) ToParquet() interface{} {
 	return PoolOutputParquet{
-		PoolID:             po.PoolID,
-		PoolType:           po.PoolType,
-		PoolFee:            int64(po.PoolFee),
-		TrustlineCount:     int64(po.TrustlineCount),
-		PoolShareCount:     po.PoolShareCount,
-		AssetAType:         po.AssetAType,
-		AssetACode:         po.AssetACode,
-		AssetAIssuer:       po.AssetAIssuer,
-		AssetAReserve:      po.AssetAReserve,
-		AssetAID:           po.AssetAID,
-		AssetBType:         po.AssetBType,
-		AssetBCode:         po.AssetBCode,
-		AssetBIssuer:       po.AssetBIssuer,
-		AssetBReserve:      po.AssetBReserve,
-		AssetBID:           po.AssetBID,
-		LastModifiedLedger: int64(po.LastModifiedLedger),
-		LedgerEntryChange:  int64(po.LedgerEntryChange),
-		Deleted:            po.Deleted,
-		ClosedAt:           po.ClosedAt.UnixMilli(),
-		LedgerSequence:     int64(po.LedgerSequence),
+		PoolID:              po.PoolID,
+		PoolType:            po.PoolType,
+		PoolFee:             int64(po.PoolFee),
+		TrustlineCount:      int64(po.TrustlineCount),
+		PoolShareCount:      po.PoolShareCount,
+		AssetAType:          po.AssetAType,
+		AssetACode:          po.AssetACode,
+		AssetAIssuer:        po.AssetAIssuer,
+		AssetAReserve:       po.AssetAReserve,
+		AssetAID:            po.AssetAID,
+		AssetBType:          po.AssetBType,
+		AssetBCode:          po.AssetBCode,
+		AssetBIssuer:        po.AssetBIssuer,
+		AssetBReserve:       po.AssetBReserve,
+		AssetBID:            po.AssetBID,
+		LastModifiedLedger:  int64(po.LastModifiedLedger),
+		LedgerEntryChange:   int64(po.LedgerEntryChange),
+		Deleted:             po.Deleted,
+		ClosedAt:            po.ClosedAt.UnixMilli(),
+		LedgerSequence:      int64(po.LedgerSequence),
+		LedgerKeyHash:       po.LedgerKeyHash,
+		LedgerKeyHashBase64: po.LedgerKeyHashBase64,
+	}
+}
+
+func (cbc ClaimableBalanceClaimOutput) ToParquet() interface{} {
+	// Balances created before the export have no creation time, which is stored as 0
+	var createdAt int64
+	if cbc.CreatedAt.Valid {
+		createdAt = cbc.CreatedAt.Time.UnixMilli()
+	}
+
+	return ClaimableBalanceClaimOutputParquet{
+		BalanceID:              cbc.BalanceID,
+		BalanceIDStrkey:        cbc.BalanceIDStrkey,
+		AssetCode:              cbc.AssetCode,
+		AssetIssuer:            cbc.AssetIssuer,
+		AssetType:              cbc.AssetType,
+		AssetID:                cbc.AssetID,
+		AssetAmount:            cbc.AssetAmount,
+		CreatedBy:              cbc.CreatedBy.String,
+		CreatedTransactionHash: cbc.CreatedTransactionHash.String,
+		CreatedLedgerSequence:  int64(cbc.CreatedLedgerSequence),
+		CreatedAt:              createdAt,
+		ClaimType:              cbc.ClaimType,
+		ClaimedBy:              cbc.ClaimedBy,
+		ElapsedLedgers:         cbc.ElapsedLedgers,
+		ElapsedSeconds:         cbc.ElapsedSeconds.Int64,
+		TransactionHash:        cbc.TransactionHash,
+		TransactionID:          cbc.TransactionID,
+		OperationID:            cbc.OperationID,
+		ClosedAt:               cbc.ClosedAt.UnixMilli(),
+		LedgerSequence:         int64(cbc.LedgerSequence),
 	}
 }
 
@@ -196,81 +357,130 @@ func (ao AssetOutput) ToParquet() interface{} {
 	}
 }
 
+func (amo AssetMetadataOutput) ToParquet() interface{} {
+	return AssetMetadataOutputParquet{
+		AssetCode:       amo.AssetCode,
+		AssetIssuer:     amo.AssetIssuer,
+		AssetType:       amo.AssetType,
+		AssetID:         amo.AssetID,
+		HomeDomain:      amo.HomeDomain,
+		OrgName:         amo.OrgName,
+		Name:            amo.Name,
+		Description:     amo.Description,
+		Image:           amo.Image,
+		DisplayDecimals: amo.DisplayDecimals.Int64,
+		Status:          amo.Status,
+		IsAssetAnchored: amo.IsAssetAnchored,
+		AnchorAssetType: amo.AnchorAssetType,
+		AnchorAsset:     amo.AnchorAsset,
+		MetadataError:   amo.MetadataError,
+		FetchedAt:       amo.FetchedAt.UnixMilli(),
+	}
+}
+
 func (to TrustlineOutput) ToParquet() interface{} {
 	return TrustlineOutputParquet{
-		LedgerKey:          to.LedgerKey,
-		AccountID:          to.AccountID,
-		AssetCode:          to.AssetCode,
-		AssetIssuer:        to.AssetIssuer,
-		AssetType:          to.AssetType,
-		AssetID:            to.AssetID,
-		Balance:            to.Balance,
-		TrustlineLimit:     to.TrustlineLimit,
-		LiquidityPoolID:    to.LiquidityPoolID,
-		BuyingLiabilities:  to.BuyingLiabilities,
-		SellingLiabilities: to.SellingLiabilities,
-		Flags:              int64(to.Flags),
-		LastModifiedLedger: int64(to.LastModifiedLedger),
-		LedgerEntryChange:  int64(to.LedgerEntryChange),
-		Sponsor:            to.Sponsor.String,
-		Deleted:            to.Deleted,
-		ClosedAt:           to.ClosedAt.UnixMilli(),
-		LedgerSequence:     int64(to.LedgerSequence),
+		LedgerKey:           to.LedgerKey,
+		AccountID:           to.AccountID,
+		AssetCode:           to.AssetCode,
+		AssetIssuer:         to.AssetIssuer,
+		AssetType:           to.AssetType,
+		AssetID:             to.AssetID,
+		Balance:             to.Balance,
+		TrustlineLimit:      to.TrustlineLimit,
+		LiquidityPoolID:     to.LiquidityPoolID,
+		BuyingLiabilities:   to.BuyingLiabilities,
+		SellingLiabilities:  to.SellingLiabilities,
+		Flags:               int64(to.Flags),
+		LastModifiedLedger:  int64(to.LastModifiedLedger),
+		LedgerEntryChange:   int64(to.LedgerEntryChange),
+		Sponsor:             to.Sponsor.String,
+		Deleted:             to.Deleted,
+		ClosedAt:            to.ClosedAt.UnixMilli(),
+		LedgerSequence:      int64(to.LedgerSequence),
+		LedgerKeyHash:       to.LedgerKeyHash,
+		LedgerKeyHashBase64: to.LedgerKeyHashBase64,
 	}
 }
 
 func (oo OfferOutput) ToParquet() interface{} {
 	return OfferOutputParquet{
-		SellerID:           oo.SellerID,
-		OfferID:            oo.OfferID,
-		SellingAssetType:   oo.SellingAssetType,
-		SellingAssetCode:   oo.SellingAssetCode,
-		SellingAssetIssuer: oo.SellingAssetIssuer,
-		SellingAssetID:     oo.SellingAssetID,
-		BuyingAssetType:    oo.BuyingAssetType,
-		BuyingAssetCode:    oo.BuyingAssetCode,
-		BuyingAssetIssuer:  oo.BuyingAssetIssuer,
-		BuyingAssetID:      oo.BuyingAssetID,
-		Amount:             oo.Amount,
-		PriceN:             oo.PriceN,
-		PriceD:             oo.PriceD,
-		Price:              oo.Price,
-		Flags:              int64(oo.Flags),
-		LastModifiedLedger: int64(oo.LastModifiedLedger),
-		LedgerEntryChange:  int64(oo.LedgerEntryChange),
-		Deleted:            oo.Deleted,
-		Sponsor:            oo.Sponsor.String,
-		ClosedAt:           oo.ClosedAt.UnixMilli(),
-		LedgerSequence:     int64(oo.LedgerSequence),
+		SellerID:            oo.SellerID,
+		OfferID:             oo.OfferID,
+		SellingAssetType:    oo.SellingAssetType,
+		SellingAssetCode:    oo.SellingAssetCode,
+		SellingAssetIssuer:  oo.SellingAssetIssuer,
+		SellingAssetID:      oo.SellingAssetID,
+		BuyingAssetType:     oo.BuyingAssetType,
+		BuyingAssetCode:     oo.BuyingAssetCode,
+		BuyingAssetIssuer:   oo.BuyingAssetIssuer,
+		BuyingAssetID:       oo.BuyingAssetID,
+		Amount:              oo.Amount,
+		PriceN:              oo.PriceN,
+		PriceD:              oo.PriceD,
+		Price:               oo.Price,
+		Flags:               int64(oo.Flags),
+		LastModifiedLedger:  int64(oo.LastModifiedLedger),
+		LedgerEntryChange:   int64(oo.LedgerEntryChange),
+		Deleted:             oo.Deleted,
+		Sponsor:             oo.Sponsor.String,
+		ClosedAt:            oo.ClosedAt.UnixMilli(),
+		LedgerSequence:      int64(oo.LedgerSequence),
+		LedgerKeyHash:       oo.LedgerKeyHash,
+		LedgerKeyHashBase64: oo.LedgerKeyHashBase64,
 	}
 }
 
 func (to TradeOutput) ToParquet() interface{} {
 	return TradeOutputParquet{
-		Order:                  to.Order,
-		LedgerClosedAt:         to.LedgerClosedAt.UnixMilli(),
-		SellingAccountAddress:  to.SellingAccountAddress,
-		SellingAssetCode:       to.SellingAssetCode,
-		SellingAssetIssuer:     to.SellingAssetIssuer,
-		SellingAssetType:       to.SellingAssetType,
-		SellingAssetID:         to.SellingAssetID,
-		SellingAmount:          to.SellingAmount,
-		BuyingAccountAddress:   to.BuyingAccountAddress,
-		BuyingAssetCode:        to.BuyingAssetCode,
-		BuyingAssetIssuer:      to.BuyingAssetIssuer,
-		BuyingAssetType:        to.BuyingAssetType,
-		BuyingAssetID:          to.BuyingAssetID,
-		BuyingAmount:           to.BuyingAmount,
-		PriceN:                 to.PriceN,
-		PriceD:                 to.PriceD,
-		SellingOfferID:         to.SellingOfferID.Int64,
-		BuyingOfferID:          to.BuyingOfferID.Int64,
-		SellingLiquidityPoolID: to.SellingLiquidityPoolID.String,
-		LiquidityPoolFee:       to.LiquidityPoolFee.Int64,
-		HistoryOperationID:     to.HistoryOperationID,
-		TradeType:              to.TradeType,
-		RoundingSlippage:       to.RoundingSlippage.Int64,
-		SellerIsExact:          to.SellerIsExact.Bool,
+		Order:                     to.Order,
+		LedgerClosedAt:            to.LedgerClosedAt.UnixMilli(),
+		SellingAccountAddress:     to.SellingAccountAddress,
+		SellingAssetCode:          to.SellingAssetCode,
+		SellingAssetIssuer:        to.SellingAssetIssuer,
+		SellingAssetType:          to.SellingAssetType,
+		SellingAssetID:            to.SellingAssetID,
+		SellingAmount:             to.SellingAmount,
+		BuyingAccountAddress:      to.BuyingAccountAddress,
+		BuyingAssetCode:           to.BuyingAssetCode,
+		BuyingAssetIssuer:         to.BuyingAssetIssuer,
+		BuyingAssetType:           to.BuyingAssetType,
+		BuyingAssetID:             to.BuyingAssetID,
+		BuyingAmount:              to.BuyingAmount,
+		PriceN:                    to.PriceN,
+		PriceD:                    to.PriceD,
+		SellingOfferID:            to.SellingOfferID.Int64,
+		BuyingOfferID:             to.BuyingOfferID.Int64,
+		SellingLiquidityPoolID:    to.SellingLiquidityPoolID.String,
+		LiquidityPoolFee:          to.LiquidityPoolFee.Int64,
+		HistoryOperationID:        to.HistoryOperationID,
+		TradeType:                 to.TradeType,
+		RoundingSlippage:          to.RoundingSlippage.Int64,
+		SellerIsExact:             to.SellerIsExact.Bool,
+		BuyingAccountAddressMuxed: to.BuyingAccountAddressMuxed.String,
+		Price:                     to.Price,
+		BaseIsSeller:              to.BaseIsSeller,
+		BaseAccountAddress:        to.BaseAccountAddress,
+		BaseAssetCode:             to.BaseAssetCode,
+		BaseAssetIssuer:           to.BaseAssetIssuer,
+		BaseAssetType:             to.BaseAssetType,
+		BaseAssetID:               to.BaseAssetID,
+		BaseAmount:                to.BaseAmount,
+		BaseOfferID:               to.BaseOfferID.Int64,
+		BaseLiquidityPoolID:       to.BaseLiquidityPoolID.String,
+		CounterAccountAddress:     to.CounterAccountAddress,
+		CounterAssetCode:          to.CounterAssetCode,
+		CounterAssetIssuer:        to.CounterAssetIssuer,
+		CounterAssetType:          to.CounterAssetType,
+		CounterAssetID:            to.CounterAssetID,
+		CounterAmount:             to.CounterAmount,
+		CounterOfferID:            to.CounterOfferID.Int64,
+		CounterLiquidityPoolID:    to.CounterLiquidityPoolID.String,
+		BasePriceN:                to.BasePriceN,
+		BasePriceD:                to.BasePriceD,
+		BasePrice:                 to.BasePrice,
+		SellingAssetContractID:    to.SellingAssetContractID.String,
+		BuyingAssetContractID:     to.BuyingAssetContractID.String,
 	}
 }
 
@@ -289,6 +499,44 @@ func (eo EffectOutput) ToParquet() interface{} {
 	}
 }
 
+func (fo FlattenedEffectOutput) ToParquet() interface{} {
+	details := fo.Details
+	return FlattenedEffectOutputParquet{
+		Address:           fo.Address,
+		AddressMuxed:      fo.AddressMuxed.String,
+		OperationID:       fo.OperationID,
+		Details:           toJSONString(fo.Details),
+		Type:              fo.Type,
+		TypeString:        fo.TypeString,
+		LedgerClosed:      fo.LedgerClosed.UnixMilli(),
+		LedgerSequence:    int64(fo.LedgerSequence),
+		EffectIndex:       int64(fo.EffectIndex),
+		EffectId:          fo.EffectId,
+		Amount:            detailFloat(details, "amount"),
+		AssetCode:         detailString(details, "asset_code"),
+		AssetIssuer:       detailString(details, "asset_issuer"),
+		AssetType:         detailString(details, "asset_type"),
+		Trustor:           detailString(details, "trustor"),
+		Sponsor:           detailString(details, "sponsor"),
+		FormerSponsor:     detailString(details, "former_sponsor"),
+		NewSponsor:        detailString(details, "new_sponsor"),
+		OfferID:           detailInt(details, "offer_id"),
+		Seller:            detailString(details, "seller"),
+		BoughtAmount:      detailFloat(details, "bought_amount"),
+		BoughtAssetCode:   detailString(details, "bought_asset_code"),
+		BoughtAssetIssuer: detailString(details, "bought_asset_issuer"),
+		BoughtAssetType:   detailString(details, "bought_asset_type"),
+		SoldAmount:        detailFloat(details, "sold_amount"),
+		SoldAssetCode:     detailString(details, "sold_asset_code"),
+		SoldAssetIssuer:   detailString(details, "sold_asset_issuer"),
+		SoldAssetType:     detailString(details, "sold_asset_type"),
+		StartingBalance:   detailFloat(details, "starting_balance"),
+		NewSeq:            detailInt(details, "new_seq"),
+		BalanceID:         detailString(details, "balance_id"),
+		Limit:             detailFloat(details, "limit"),
+	}
+}
+
 func (cdo ContractDataOutput) ToParquet() interface{} {
 	return ContractDataOutputParquet{
 		ContractId:                cdo.ContractId,
@@ -309,30 +557,33 @@ func (cdo ContractDataOutput) ToParquet() interface{} {
 		KeyDecoded:                cdo.KeyDecoded,
 		Val:                       cdo.Val,
 		ValDecoded:                cdo.ValDecoded,
+		ValueTruncated:            cdo.ValueTruncated,
 		ContractDataXDR:           cdo.ContractDataXDR,
+		LedgerKeyHashBase64:       cdo.LedgerKeyHashBase64,
 	}
 }
 
 func (cco ContractCodeOutput) ToParquet() interface{} {
 	return ContractCodeOutputParquet{
-		ContractCodeHash:   cco.ContractCodeHash,
-		ContractCodeExtV:   cco.ContractCodeExtV,
-		LastModifiedLedger: int64(cco.LastModifiedLedger),
-		LedgerEntryChange:  int64(cco.LedgerEntryChange),
-		Deleted:            cco.Deleted,
-		ClosedAt:           cco.ClosedAt.UnixMilli(),
-		LedgerSequence:     int64(cco.LedgerSequence),
-		LedgerKeyHash:      cco.LedgerKeyHash,
-		NInstructions:      int64(cco.NInstructions),
-		NFunctions:         int64(cco.NFunctions),
-		NGlobals:           int64(cco.NGlobals),
-		NTableEntries:      int64(cco.NTableEntries),
-		NTypes:             int64(cco.NTypes),
-		NDataSegments:      int64(cco.NDataSegments),
-		NElemSegments:      int64(cco.NElemSegments),
-		NImports:           int64(cco.NImports),
-		NExports:           int64(cco.NExports),
-		NDataSegmentBytes:  int64(cco.NDataSegmentBytes),
+		ContractCodeHash:    cco.ContractCodeHash,
+		ContractCodeExtV:    cco.ContractCodeExtV,
+		LastModifiedLedger:  int64(cco.LastModifiedLedger),
+		LedgerEntryChange:   int64(cco.LedgerEntryChange),
+		Deleted:             cco.Deleted,
+		ClosedAt:            cco.ClosedAt.UnixMilli(),
+		LedgerSequence:      int64(cco.LedgerSequence),
+		LedgerKeyHash:       cco.LedgerKeyHash,
+		NInstructions:       int64(cco.NInstructions),
+		NFunctions:          int64(cco.NFunctions),
+		NGlobals:            int64(cco.NGlobals),
+		NTableEntries:       int64(cco.NTableEntries),
+		NTypes:              int64(cco.NTypes),
+		NDataSegments:       int64(cco.NDataSegments),
+		NElemSegments:       int64(cco.NElemSegments),
+		NImports:            int64(cco.NImports),
+		NExports:            int64(cco.NExports),
+		NDataSegmentBytes:   int64(cco.NDataSegmentBytes),
+		LedgerKeyHashBase64: cco.LedgerKeyHashBase64,
 	}
 }
 
@@ -407,18 +658,176 @@ func (cso ConfigSettingOutput) ToParquet() interface{} {
 		Deleted:                                cso.Deleted,
 		ClosedAt:                               cso.ClosedAt.UnixMilli(),
 		LedgerSequence:                         int64(cso.LedgerSequence),
+		LedgerKeyHash:                          cso.LedgerKeyHash,
+		LedgerKeyHashBase64:                    cso.LedgerKeyHashBase64,
 	}
 }
 
 func (to TtlOutput) ToParquet() interface{} {
 	return TtlOutputParquet{
-		KeyHash:            to.KeyHash,
-		LiveUntilLedgerSeq: int64(to.LiveUntilLedgerSeq),
-		LastModifiedLedger: int64(to.LastModifiedLedger),
-		LedgerEntryChange:  int64(to.LedgerEntryChange),
-		Deleted:            to.Deleted,
-		ClosedAt:           to.ClosedAt.UnixMilli(),
-		LedgerSequence:     int64(to.LedgerSequence),
+		KeyHash:             to.KeyHash,
+		LiveUntilLedgerSeq:  int64(to.LiveUntilLedgerSeq),
+		LastModifiedLedger:  int64(to.LastModifiedLedger),
+		LedgerEntryChange:   int64(to.LedgerEntryChange),
+		Deleted:             to.Deleted,
+		ClosedAt:            to.ClosedAt.UnixMilli(),
+		LedgerSequence:      int64(to.LedgerSequence),
+		LedgerKeyHash:       to.LedgerKeyHash,
+		LedgerKeyHashBase64: to.LedgerKeyHashBase64,
+	}
+}
+
+func (teo TtlExpiryOutput) ToParquet() interface{} {
+	return TtlExpiryOutputParquet{
+		KeyHash:            teo.KeyHash,
+		LiveUntilLedgerSeq: int64(teo.LiveUntilLedgerSeq),
+		LedgersUntilExpiry: teo.LedgersUntilExpiry,
+		Expired:            teo.Expired,
+		LastModifiedLedger: int64(teo.LastModifiedLedger),
+		ClosedAt:           teo.ClosedAt.UnixMilli(),
+		LedgerSequence:     int64(teo.LedgerSequence),
+		LedgerKeyHash:      teo.LedgerKeyHash,
+	}
+}
+
+func (ppo PoolParticipantOutput) ToParquet() interface{} {
+	return PoolParticipantOutputParquet{
+		AccountID:             ppo.AccountID,
+		LiquidityPoolID:       ppo.LiquidityPoolID,
+		LiquidityPoolIDStrkey: ppo.LiquidityPoolIDStrkey,
+		SharesDelta:           ppo.SharesDelta,
+		Shares:                ppo.Shares,
+		LastModifiedLedger:    int64(ppo.LastModifiedLedger),
+		LedgerEntryChange:     int64(ppo.LedgerEntryChange),
+		Deleted:               ppo.Deleted,
+		ClosedAt:              ppo.ClosedAt.UnixMilli(),
+		LedgerSequence:        int64(ppo.LedgerSequence),
+		LedgerKeyHash:         ppo.LedgerKeyHash,
+	}
+}
+
+func (aoo AccountOperationOutput) ToParquet() interface{} {
+	return AccountOperationOutputParquet{
+		AccountID:   aoo.AccountID,
+		OperationID: aoo.OperationID,
+	}
+}
+
+func (po ParticipantOutput) ToParquet() interface{} {
+	return ParticipantOutputParquet{
+		OperationID:     po.OperationID,
+		TransactionID:   po.TransactionID,
+		TransactionHash: po.TransactionHash,
+		OperationType:   po.OperationType,
+		Account:         po.Account,
+		AccountMuxed:    po.AccountMuxed,
+		Role:            po.Role,
+		Successful:      po.Successful,
+		ClosedAt:        po.ClosedAt.UnixMilli(),
+		LedgerSequence:  int64(po.LedgerSequence),
+	}
+}
+
+func (lfso LedgerFeeStatsOutput) ToParquet() interface{} {
+	return LedgerFeeStatsOutputParquet{
+		LedgerSequence:        int64(lfso.LedgerSequence),
+		ClosedAt:              lfso.ClosedAt.UnixMilli(),
+		BaseFee:               int64(lfso.BaseFee),
+		MaxTxSetSize:          int64(lfso.MaxTxSetSize),
+		TransactionCount:      lfso.TransactionCount,
+		TxSetOperationCount:   lfso.TxSetOperationCount,
+		ClassicOperationCount: lfso.ClassicOperationCount,
+		CapacityUsage:         lfso.CapacityUsage,
+		MinInclusionFee:       lfso.MinInclusionFee,
+		MedianInclusionFee:    lfso.MedianInclusionFee,
+		MaxInclusionFee:       lfso.MaxInclusionFee,
+		InclusionBaseFee:      lfso.InclusionBaseFee,
+		IsSurge:               lfso.IsSurge,
+	}
+}
+
+func (aso AssetSupplyOutput) ToParquet() interface{} {
+	return AssetSupplyOutputParquet{
+		LedgerSequence:    int64(aso.LedgerSequence),
+		ClosedAt:          aso.ClosedAt.UnixMilli(),
+		Asset:             aso.Asset,
+		AssetType:         aso.AssetType,
+		AssetCode:         aso.AssetCode.String,
+		AssetIssuer:       aso.AssetIssuer.String,
+		AssetID:           aso.AssetID.Int64,
+		ContractID:        aso.ContractID,
+		Minted:            aso.Minted,
+		Burned:            aso.Burned,
+		ClawedBack:        aso.ClawedBack,
+		SupplyDelta:       aso.SupplyDelta,
+		CirculatingSupply: aso.CirculatingSupply,
+	}
+}
+
+func (fo FootprintOutput) ToParquet() interface{} {
+	return FootprintOutputParquet{
+		TransactionHash:     fo.TransactionHash,
+		TransactionID:       fo.TransactionID,
+		Successful:          fo.Successful,
+		Access:              fo.Access,
+		LedgerKeyHash:       fo.LedgerKeyHash,
+		LedgerKeyHashBase64: fo.LedgerKeyHashBase64,
+		LedgerEntryType:     fo.LedgerEntryType,
+		AccountID:           fo.AccountID,
+		AssetType:           fo.AssetType,
+		AssetCode:           fo.AssetCode,
+		AssetIssuer:         fo.AssetIssuer,
+		LiquidityPoolID:     fo.LiquidityPoolID,
+		ContractId:          fo.ContractId,
+		ContractKeyType:     fo.ContractKeyType,
+		ContractDurability:  fo.ContractDurability,
+		ContractCodeHash:    fo.ContractCodeHash,
+		ClosedAt:            fo.ClosedAt.UnixMilli(),
+		LedgerSequence:      int64(fo.LedgerSequence),
+	}
+}
+
+func (fro FootprintReadOutput) ToParquet() interface{} {
+	return FootprintReadOutputParquet{
+		LedgerKeyHash:       fro.LedgerKeyHash,
+		LedgerKeyHashBase64: fro.LedgerKeyHashBase64,
+		LedgerEntryType:     fro.LedgerEntryType,
+		ContractId:          fro.ContractId,
+		TransactionHash:     fro.TransactionHash,
+		Successful:          fro.Successful,
+		ClosedAt:            fro.ClosedAt.UnixMilli(),
+		LedgerSequence:      int64(fro.LedgerSequence),
+	}
+}
+
+func (rco RestoreCandidateOutput) ToParquet() interface{} {
+	return RestoreCandidateOutputParquet{
+		LedgerKeyHash:       rco.LedgerKeyHash,
+		LedgerKeyHashBase64: rco.LedgerKeyHashBase64,
+		LedgerEntryType:     rco.LedgerEntryType,
+		ContractId:          rco.ContractId,
+		ContractKeyType:     rco.ContractKeyType,
+		ContractCodeHash:    rco.ContractCodeHash,
+		Reason:              rco.Reason,
+		LiveUntilLedgerSeq:  rco.LiveUntilLedgerSeq.Int64,
+		TransactionHash:     rco.TransactionHash,
+		TransactionID:       rco.TransactionID,
+		Successful:          rco.Successful,
+		ClosedAt:            rco.ClosedAt.UnixMilli(),
+		LedgerSequence:      int64(rco.LedgerSequence),
+	}
+}
+
+func (cso ContractSpecOutput) ToParquet() interface{} {
+	// The inputs are nested records, so they are stored as a JSON string
+	return ContractSpecOutputParquet{
+		ContractCodeHash: cso.ContractCodeHash,
+		FunctionName:     cso.FunctionName,
+		Inputs:           toJSONString(cso.Inputs),
+		Outputs:          cso.Outputs,
+		Doc:              cso.Doc,
+		ClosedAt:         cso.ClosedAt.UnixMilli(),
+		LedgerSequence:   int64(cso.LedgerSequence),
 	}
 }
 
@@ -433,10 +842,108 @@ func (ceo ContractEventOutput) ToParquet() interface{} {
 		ContractId:               ceo.ContractId,
 		Type:                     ceo.Type,
 		TypeString:               ceo.TypeString,
+		EventType:                ceo.EventType,
 		Topics:                   ceo.Topics,
 		TopicsDecoded:            ceo.TopicsDecoded,
 		Data:                     ceo.Data,
 		DataDecoded:              ceo.DataDecoded,
+		ValueTruncated:           ceo.ValueTruncated,
 		ContractEventXDR:         ceo.ContractEventXDR,
+		EventID:                  ceo.EventID,
+	}
+}
+
+func (luo LedgerUpgradeOutput) ToParquet() interface{} {
+	return LedgerUpgradeOutputParquet{
+		LedgerSequence:              int64(luo.LedgerSequence),
+		ClosedAt:                    luo.ClosedAt.UnixMilli(),
+		UpgradeIndex:                luo.UpgradeIndex,
+		Type:                        luo.Type,
+		TypeString:                  luo.TypeString,
+		NewLedgerVersion:            luo.NewLedgerVersion.Int64,
+		NewBaseFee:                  luo.NewBaseFee.Int64,
+		NewMaxTxSetSize:             luo.NewMaxTxSetSize.Int64,
+		NewBaseReserve:              luo.NewBaseReserve.Int64,
+		NewFlags:                    luo.NewFlags.Int64,
+		NewMaxSorobanTxSetSize:      luo.NewMaxSorobanTxSetSize.Int64,
+		ConfigUpgradeSetContractID:  luo.ConfigUpgradeSetContractID.String,
+		ConfigUpgradeSetContentHash: luo.ConfigUpgradeSetContentHash.String,
+		UpgradeXDR:                  luo.UpgradeXDR,
+	}
+}
+
+func (cuo ConfigUpgradeOutput) ToParquet() interface{} {
+	// The config settings are nested records, so they are stored as JSON strings
+	var oldConfigSetting string
+	if cuo.OldConfigSetting != nil {
+		oldConfigSetting = toJSONString(cuo.OldConfigSetting)
+	}
+
+	return ConfigUpgradeOutputParquet{
+		LedgerSequence:              int64(cuo.LedgerSequence),
+		ClosedAt:                    cuo.ClosedAt.UnixMilli(),
+		UpgradeIndex:                cuo.UpgradeIndex,
+		ConfigUpgradeSetContractID:  cuo.ConfigUpgradeSetContractID,
+		ConfigUpgradeSetContentHash: cuo.ConfigUpgradeSetContentHash,
+		ConfigSettingId:             cuo.ConfigSettingId,
+		ConfigSettingIdString:       cuo.ConfigSettingIdString,
+		ChangedFields:               cuo.ChangedFields,
+		OldConfigSetting:            oldConfigSetting,
+		NewConfigSetting:            toJSONString(cuo.NewConfigSetting),
+	}
+}
+
+func (csdo ConfigSettingDiffOutput) ToParquet() interface{} {
+	return ConfigSettingDiffOutputParquet{
+		ConfigSettingId:       csdo.ConfigSettingId,
+		ConfigSettingIdString: csdo.ConfigSettingIdString,
+		Field:                 csdo.Field,
+		OldValue:              csdo.OldValue.String,
+		NewValue:              csdo.NewValue.String,
+		LastModifiedLedger:    int64(csdo.LastModifiedLedger),
+		LedgerEntryChange:     int64(csdo.LedgerEntryChange),
+		Deleted:               csdo.Deleted,
+		ClosedAt:              csdo.ClosedAt.UnixMilli(),
+		LedgerSequence:        int64(csdo.LedgerSequence),
+		LedgerKeyHash:         csdo.LedgerKeyHash,
+	}
+}
+
+func (ipo InflationPayoutOutput) ToParquet() interface{} {
+	return InflationPayoutOutputParquet{
+		LedgerSequence:  int64(ipo.LedgerSequence),
+		ClosedAt:        ipo.ClosedAt.UnixMilli(),
+		TransactionHash: ipo.TransactionHash,
+		TransactionID:   ipo.TransactionID,
+		OperationID:     ipo.OperationID,
+		PayoutIndex:     ipo.PayoutIndex,
+		Destination:     ipo.Destination,
+		Amount:          ipo.Amount,
+	}
+}
+
+func (ppho PathPaymentHopOutput) ToParquet() interface{} {
+	return PathPaymentHopOutputParquet{
+		HistoryOperationID:                ppho.HistoryOperationID,
+		LedgerClosedAt:                    ppho.LedgerClosedAt.UnixMilli(),
+		OperationType:                     ppho.OperationType,
+		SourceAccount:                     ppho.SourceAccount,
+		HopIndex:                          ppho.HopIndex,
+		ClaimOrder:                        ppho.ClaimOrder,
+		SourceAssetCode:                   ppho.SourceAssetCode,
+		SourceAssetIssuer:                 ppho.SourceAssetIssuer,
+		SourceAssetType:                   ppho.SourceAssetType,
+		SourceAssetID:                     ppho.SourceAssetID,
+		SourceAmount:                      ppho.SourceAmount,
+		DestinationAssetCode:              ppho.DestinationAssetCode,
+		DestinationAssetIssuer:            ppho.DestinationAssetIssuer,
+		DestinationAssetType:              ppho.DestinationAssetType,
+		DestinationAssetID:                ppho.DestinationAssetID,
+		DestinationAmount:                 ppho.DestinationAmount,
+		CounterpartyType:                  ppho.CounterpartyType,
+		CounterpartyAccount:               ppho.CounterpartyAccount.String,
+		CounterpartyOfferID:               ppho.CounterpartyOfferID.Int64,
+		CounterpartyLiquidityPoolID:       ppho.CounterpartyLiquidityPoolID.String,
+		CounterpartyLiquidityPoolIDStrkey: ppho.CounterpartyLiquidityPoolIDStrkey.String,
 	}
 }
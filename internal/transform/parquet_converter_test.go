@@ -0,0 +1,83 @@
+package transform
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+func TestFlattenedOperationToParquet(t *testing.T) {
+	operation := FlattenedOperationOutput{
+		OperationOutput: OperationOutput{
+			SourceAccount:  "GCEODJVUUVYVFD5KT4TOEDTMXQ76OPFOQC2EMYYMLPXQCUVPOB6XRWPQ",
+			Type:           13,
+			TypeString:     "path_payment_strict_send",
+			TransactionID:  128849022976,
+			OperationID:    128849022977,
+			ClosedAt:       time.Date(1970, time.January, 1, 0, 16, 40, 0, time.UTC),
+			LedgerSequence: 30,
+			OperationDetails: map[string]interface{}{
+				"amount":            "0.0000000",
+				"source_amount":     0.1,
+				"destination_min":   "12.5000000",
+				"asset_type":        "native",
+				"asset_id":          int64(-5706705804583548011),
+				"source_asset_code": "USDT",
+				"from":              "GCEODJVUUVYVFD5KT4TOEDTMXQ76OPFOQC2EMYYMLPXQCUVPOB6XRWPQ",
+				"bump_to":           "100",
+			},
+		},
+	}
+
+	flattened := operation.ToParquet().(FlattenedOperationOutputParquet)
+	assert.Equal(t, 0.0, flattened.Amount)
+	assert.Equal(t, 0.1, flattened.SourceAmount)
+	assert.Equal(t, 12.5, flattened.DestinationMin)
+	assert.Equal(t, "native", flattened.AssetType)
+	assert.Equal(t, int64(-5706705804583548011), flattened.AssetID)
+	assert.Equal(t, "USDT", flattened.SourceAssetCode)
+	assert.Equal(t, "GCEODJVUUVYVFD5KT4TOEDTMXQ76OPFOQC2EMYYMLPXQCUVPOB6XRWPQ", flattened.From)
+	assert.Equal(t, "", flattened.To)
+	assert.Equal(t, int64(100), flattened.BumpTo)
+	assert.Equal(t, int64(1000000), flattened.ClosedAt)
+	assert.Equal(t, int64(30), flattened.LedgerSequence)
+	assert.Contains(t, flattened.OperationDetails, `"source_asset_code":"USDT"`)
+}
+
+func TestFlattenedEffectToParquet(t *testing.T) {
+	effect := FlattenedEffectOutput{
+		EffectOutput: EffectOutput{
+			Address:        "GCEODJVUUVYVFD5KT4TOEDTMXQ76OPFOQC2EMYYMLPXQCUVPOB6XRWPQ",
+			OperationID:    128849022977,
+			Type:           int32(EffectTrade),
+			TypeString:     EffectTypeNames[EffectTrade],
+			LedgerClosed:   time.Date(1970, time.January, 1, 0, 16, 40, 0, time.UTC),
+			LedgerSequence: 30,
+			EffectId:       "128849022977-1",
+			Details: map[string]interface{}{
+				"offer_id":          xdr.Int64(97),
+				"seller":            "GBT4YAEGJQ5YSFUMNKX6BPBUOCPNAIOFAVZOF6MIME2CECBMEIUXFZZN",
+				"bought_amount":     "1.0000000",
+				"bought_asset_type": "native",
+				"sold_amount":       "2.5000000",
+				"sold_asset_code":   "USDT",
+				"new_seq":           xdr.SequenceNumber(9007199254740993),
+			},
+		},
+	}
+
+	flattened := effect.ToParquet().(FlattenedEffectOutputParquet)
+	assert.Equal(t, int64(97), flattened.OfferID)
+	assert.Equal(t, "GBT4YAEGJQ5YSFUMNKX6BPBUOCPNAIOFAVZOF6MIME2CECBMEIUXFZZN", flattened.Seller)
+	assert.Equal(t, 1.0, flattened.BoughtAmount)
+	assert.Equal(t, "native", flattened.BoughtAssetType)
+	assert.Equal(t, 2.5, flattened.SoldAmount)
+	assert.Equal(t, "USDT", flattened.SoldAssetCode)
+	assert.Equal(t, int64(9007199254740993), flattened.NewSeq)
+	assert.Equal(t, 0.0, flattened.Amount)
+	assert.Equal(t, "", flattened.Sponsor)
+	assert.Equal(t, int64(1000000), flattened.LedgerClosed)
+}
@@ -0,0 +1,60 @@
+package transform
+
+import (
+	"fmt"
+
+	"github.com/stellar/go-stellar-sdk/ingest"
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stellar/stellar-etl/v2/internal/utils"
+)
+
+// TransformParticipants converts the participants of every operation in a transaction into one record per account or
+// contract and role, so that the activity of an account can be indexed
+func TransformParticipants(transaction ingest.LedgerTransaction, ledgerSeq uint32, ledgerCloseMeta xdr.LedgerCloseMeta, networkPassphrase string) ([]ParticipantOutput, error) {
+	transformedParticipants := []ParticipantOutput{}
+
+	outputCloseTime, err := utils.GetCloseTime(ledgerCloseMeta)
+	if err != nil {
+		return nil, err
+	}
+
+	outputTransactionHash := utils.HashToHexString(transaction.Result.TransactionHash)
+
+	for opi, op := range transaction.Envelope.Operations() {
+		operation := transactionOperationWrapper{
+			index:          uint32(opi),
+			transaction:    transaction,
+			operation:      op,
+			ledgerSequence: ledgerSeq,
+			network:        networkPassphrase,
+			ledgerClosed:   outputCloseTime,
+		}
+
+		outputOperationType, err := mapOperationType(op)
+		if err != nil {
+			return nil, err
+		}
+
+		participants, err := operation.Participants()
+		if err != nil {
+			return nil, fmt.Errorf("for operation %d (operation id=%d): %v", opi, operation.ID(), err)
+		}
+
+		for _, p := range participants {
+			transformedParticipants = append(transformedParticipants, ParticipantOutput{
+				OperationID:     operation.ID(),
+				TransactionID:   operation.TransactionID(),
+				TransactionHash: outputTransactionHash,
+				OperationType:   outputOperationType,
+				Account:         p.address,
+				AccountMuxed:    p.muxed,
+				Role:            p.role,
+				Successful:      transaction.Successful(),
+				ClosedAt:        outputCloseTime,
+				LedgerSequence:  ledgerSeq,
+			})
+		}
+	}
+
+	return transformedParticipants, nil
+}
@@ -0,0 +1,192 @@
+package transform
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stellar/go-stellar-sdk/ingest"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+func TestTransformParticipants(t *testing.T) {
+	lcm := xdr.LedgerCloseMeta{
+		V: 1,
+		V1: &xdr.LedgerCloseMetaV1{
+			LedgerHeader: xdr.LedgerHeaderHistoryEntry{
+				Header: xdr.LedgerHeader{
+					LedgerSeq: 30521816,
+					ScpValue: xdr.StellarValue{
+						CloseTime: 1594272522,
+					},
+				},
+			},
+		},
+	}
+	closedAt := time.Date(2020, time.July, 9, 5, 28, 42, 0, time.UTC)
+
+	actualOutput, actualError := TransformParticipants(makeClassicParticipantsTestInput(), 30521816, lcm, "")
+	assert.NoError(t, actualError)
+	assert.Equal(t, []ParticipantOutput{
+		{OperationID: 131090201534533633, TransactionID: 131090201534533632, TransactionHash: "0100000000000000000000000000000000000000000000000000000000000000", OperationType: "payment", Account: testAccount1Address, Role: "source", Successful: true, ClosedAt: closedAt, LedgerSequence: 30521816},
+		{OperationID: 131090201534533633, TransactionID: 131090201534533632, TransactionHash: "0100000000000000000000000000000000000000000000000000000000000000", OperationType: "payment", Account: testAccount2Address, AccountMuxed: "MAOEOQMXDDXPVJC3HDFX6LZFKANJ4OOLQOD2MNXJ7PGAY5FEO4BRQAAAAAAAAAAAAACDW", Role: "destination", Successful: true, ClosedAt: closedAt, LedgerSequence: 30521816},
+		{OperationID: 131090201534533634, TransactionID: 131090201534533632, TransactionHash: "0100000000000000000000000000000000000000000000000000000000000000", OperationType: "create_account", Account: testAccount1Address, Role: "source", Successful: true, ClosedAt: closedAt, LedgerSequence: 30521816},
+		{OperationID: 131090201534533634, TransactionID: 131090201534533632, TransactionHash: "0100000000000000000000000000000000000000000000000000000000000000", OperationType: "create_account", Account: testAccount4Address, Role: "destination", Successful: true, ClosedAt: closedAt, LedgerSequence: 30521816},
+		{OperationID: 131090201534533634, TransactionID: 131090201534533632, TransactionHash: "0100000000000000000000000000000000000000000000000000000000000000", OperationType: "create_account", Account: testAccount3Address, Role: "reserve_sponsor", Successful: true, ClosedAt: closedAt, LedgerSequence: 30521816},
+	}, actualOutput)
+
+	actualOutput, actualError = TransformParticipants(makeSorobanParticipantsTestInput(), 30521816, lcm, "")
+	assert.NoError(t, actualError)
+	assert.Equal(t, []ParticipantOutput{
+		{OperationID: 131090201534537729, TransactionID: 131090201534537728, TransactionHash: "0200000000000000000000000000000000000000000000000000000000000000", OperationType: "invoke_host_function", Account: testAccount1Address, Role: "source", ClosedAt: closedAt, LedgerSequence: 30521816},
+		{OperationID: 131090201534537729, TransactionID: 131090201534537728, TransactionHash: "0200000000000000000000000000000000000000000000000000000000000000", OperationType: "invoke_host_function", Account: "CAAQEAYAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAABXAS", Role: "invoked_contract", ClosedAt: closedAt, LedgerSequence: 30521816},
+		{OperationID: 131090201534537729, TransactionID: 131090201534537728, TransactionHash: "0200000000000000000000000000000000000000000000000000000000000000", OperationType: "invoke_host_function", Account: testAccount3Address, Role: "authorizer", ClosedAt: closedAt, LedgerSequence: 30521816},
+	}, actualOutput)
+}
+
+func makeClassicParticipantsTestInput() ingest.LedgerTransaction {
+	muxedDestination := xdr.MuxedAccount{
+		Type: xdr.CryptoKeyTypeKeyTypeMuxedEd25519,
+		Med25519: &xdr.MuxedAccountMed25519{
+			Id:      0,
+			Ed25519: *testAccount2ID.Ed25519,
+		},
+	}
+
+	return ingest.LedgerTransaction{
+		Index: 1,
+		Envelope: xdr.TransactionEnvelope{
+			Type: xdr.EnvelopeTypeEnvelopeTypeTx,
+			V1: &xdr.TransactionV1Envelope{
+				Tx: xdr.Transaction{
+					SourceAccount: testAccount1,
+					Operations: []xdr.Operation{
+						{
+							Body: xdr.OperationBody{
+								Type: xdr.OperationTypePayment,
+								PaymentOp: &xdr.PaymentOp{
+									Destination: muxedDestination,
+									Asset:       nativeAsset,
+									Amount:      100,
+								},
+							},
+						},
+						{
+							Body: xdr.OperationBody{
+								Type: xdr.OperationTypeCreateAccount,
+								CreateAccountOp: &xdr.CreateAccountOp{
+									Destination:     testAccount4ID,
+									StartingBalance: 10000000,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		Result: xdr.TransactionResultPair{
+			TransactionHash: xdr.Hash{1},
+			Result: xdr.TransactionResult{
+				Result: xdr.TransactionResultResult{
+					Code:    xdr.TransactionResultCodeTxSuccess,
+					Results: &[]xdr.OperationResult{},
+				},
+			},
+		},
+		UnsafeMeta: xdr.TransactionMeta{
+			V: 1,
+			V1: &xdr.TransactionMetaV1{
+				Operations: []xdr.OperationMeta{
+					{},
+					{
+						Changes: xdr.LedgerEntryChanges{
+							{
+								Type: xdr.LedgerEntryChangeTypeLedgerEntryCreated,
+								Created: &xdr.LedgerEntry{
+									Data: xdr.LedgerEntryData{
+										Type: xdr.LedgerEntryTypeAccount,
+										Account: &xdr.AccountEntry{
+											AccountId: testAccount4ID,
+											Balance:   10000000,
+										},
+									},
+									Ext: xdr.LedgerEntryExt{
+										V: 1,
+										V1: &xdr.LedgerEntryExtensionV1{
+											SponsoringId: &testAccount3ID,
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func makeSorobanParticipantsTestInput() ingest.LedgerTransaction {
+	contractId := xdr.ContractId{1, 2, 3}
+	return ingest.LedgerTransaction{
+		Index: 2,
+		Envelope: xdr.TransactionEnvelope{
+			Type: xdr.EnvelopeTypeEnvelopeTypeTx,
+			V1: &xdr.TransactionV1Envelope{
+				Tx: xdr.Transaction{
+					SourceAccount: testAccount1,
+					Operations: []xdr.Operation{
+						{
+							Body: xdr.OperationBody{
+								Type: xdr.OperationTypeInvokeHostFunction,
+								InvokeHostFunctionOp: &xdr.InvokeHostFunctionOp{
+									HostFunction: xdr.HostFunction{
+										Type: xdr.HostFunctionTypeHostFunctionTypeInvokeContract,
+										InvokeContract: &xdr.InvokeContractArgs{
+											ContractAddress: xdr.ScAddress{
+												Type:       xdr.ScAddressTypeScAddressTypeContract,
+												ContractId: &contractId,
+											},
+											FunctionName: "transfer",
+										},
+									},
+									Auth: []xdr.SorobanAuthorizationEntry{
+										{
+											Credentials: xdr.SorobanCredentials{
+												Type: xdr.SorobanCredentialsTypeSorobanCredentialsSourceAccount,
+											},
+										},
+										{
+											Credentials: xdr.SorobanCredentials{
+												Type: xdr.SorobanCredentialsTypeSorobanCredentialsAddress,
+												Address: &xdr.SorobanAddressCredentials{
+													Address: xdr.ScAddress{
+														Type:      xdr.ScAddressTypeScAddressTypeAccount,
+														AccountId: &testAccount3ID,
+													},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		Result: xdr.TransactionResultPair{
+			TransactionHash: xdr.Hash{2},
+			Result: xdr.TransactionResult{
+				Result: xdr.TransactionResultResult{
+					Code: xdr.TransactionResultCodeTxFailed,
+				},
+			},
+		},
+		UnsafeMeta: xdr.TransactionMeta{
+			V:  3,
+			V3: &xdr.TransactionMetaV3{},
+		},
+	}
+}
@@ -0,0 +1,146 @@
+package transform
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/guregu/null"
+
+	"github.com/stellar/go-stellar-sdk/ingest"
+	"github.com/stellar/go-stellar-sdk/strkey"
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stellar/stellar-etl/v2/internal/utils"
+)
+
+// TransformPathPaymentHops converts the offers and liquidity pools crossed by a path payment into a form suitable for BigQuery.
+// Each crossed offer or pool is a row, tagged with the index of the hop along the payment path that it converted.
+// Operations that are not path payments have no hops.
+func TransformPathPaymentHops(operationIndex int32, operationID int64, transaction ingest.LedgerTransaction, ledgerCloseTime time.Time) ([]PathPaymentHopOutput, error) {
+	operation := transaction.Envelope.Operations()[operationIndex]
+	fullPath, ok := pathPaymentFullPath(operation)
+	if !ok || !transaction.Result.Successful() {
+		return nil, nil
+	}
+
+	operationResults, ok := transaction.Result.OperationResults()
+	if !ok {
+		return []PathPaymentHopOutput{}, fmt.Errorf("could not get any results from this transaction")
+	}
+
+	// operation id is +1 incremented to stay in sync with ingest package
+	outputOperationID := operationID + 1
+	claimedOffers, _, _, err := extractClaimedOffers(operationResults, operationIndex, operation.Body.Type)
+	if err != nil {
+		return []PathPaymentHopOutput{}, err
+	}
+
+	var outputSourceAccount string
+	if source := operation.SourceAccount; source != nil {
+		accid := source.ToAccountId()
+		outputSourceAccount = accid.Address()
+	} else {
+		sa := transaction.Envelope.SourceAccount().ToAccountId()
+		outputSourceAccount = sa.Address()
+	}
+
+	var transformedHops []PathPaymentHopOutput
+	for claimOrder, claimOffer := range claimedOffers {
+		// The payment sends the asset bought by the counterparty and receives the asset it sold
+		hopIndex, ok := pathPaymentHopIndex(fullPath, claimOffer.AssetBought(), claimOffer.AssetSold())
+		if !ok {
+			return []PathPaymentHopOutput{}, fmt.Errorf("claimed offer %d is not part of the path of operation at index %d", claimOrder, operationIndex)
+		}
+
+		var outputSourceAssetType, outputSourceAssetCode, outputSourceAssetIssuer string
+		err = claimOffer.AssetBought().Extract(&outputSourceAssetType, &outputSourceAssetCode, &outputSourceAssetIssuer)
+		if err != nil {
+			return []PathPaymentHopOutput{}, err
+		}
+
+		var outputDestinationAssetType, outputDestinationAssetCode, outputDestinationAssetIssuer string
+		err = claimOffer.AssetSold().Extract(&outputDestinationAssetType, &outputDestinationAssetCode, &outputDestinationAssetIssuer)
+		if err != nil {
+			return []PathPaymentHopOutput{}, err
+		}
+
+		outputSourceAmount := claimOffer.AmountBought()
+		if outputSourceAmount < 0 {
+			return []PathPaymentHopOutput{}, fmt.Errorf("amount bought is negative (%d) for operation at index %d", outputSourceAmount, operationIndex)
+		}
+
+		outputDestinationAmount := claimOffer.AmountSold()
+		if outputDestinationAmount < 0 {
+			return []PathPaymentHopOutput{}, fmt.Errorf("amount sold is negative (%d) for operation at index %d", outputDestinationAmount, operationIndex)
+		}
+
+		var counterpartyAccount, liquidityPoolID, liquidityPoolIDStrkey null.String
+		var counterpartyOfferID null.Int
+		var counterpartyType string
+		if claimOffer.Type == xdr.ClaimAtomTypeClaimAtomTypeLiquidityPool {
+			id := claimOffer.MustLiquidityPool().LiquidityPoolId
+			liquidityPoolIDString, err := strkey.Encode(strkey.VersionByteLiquidityPool, id[:])
+			if err != nil {
+				return []PathPaymentHopOutput{}, err
+			}
+			liquidityPoolIDStrkey = null.StringFrom(liquidityPoolIDString)
+			liquidityPoolID = null.StringFrom(PoolIDToString(id))
+			counterpartyType = "liquidity_pool"
+		} else {
+			counterpartyAccount = null.StringFrom(claimOffer.SellerId().Address())
+			counterpartyOfferID = null.IntFrom(int64(claimOffer.OfferId()))
+			counterpartyType = "offer"
+		}
+
+		transformedHops = append(transformedHops, PathPaymentHopOutput{
+			HistoryOperationID:                outputOperationID,
+			LedgerClosedAt:                    ledgerCloseTime,
+			OperationType:                     int32(operation.Body.Type),
+			SourceAccount:                     outputSourceAccount,
+			HopIndex:                          int32(hopIndex),
+			ClaimOrder:                        int32(claimOrder),
+			SourceAssetCode:                   outputSourceAssetCode,
+			SourceAssetIssuer:                 outputSourceAssetIssuer,
+			SourceAssetType:                   outputSourceAssetType,
+			SourceAssetID:                     utils.FarmHashAsset(outputSourceAssetCode, outputSourceAssetIssuer, outputSourceAssetType),
+			SourceAmount:                      utils.ConvertStroopValueToReal(outputSourceAmount),
+			DestinationAssetCode:              outputDestinationAssetCode,
+			DestinationAssetIssuer:            outputDestinationAssetIssuer,
+			DestinationAssetType:              outputDestinationAssetType,
+			DestinationAssetID:                utils.FarmHashAsset(outputDestinationAssetCode, outputDestinationAssetIssuer, outputDestinationAssetType),
+			DestinationAmount:                 utils.ConvertStroopValueToReal(outputDestinationAmount),
+			CounterpartyType:                  counterpartyType,
+			CounterpartyAccount:               counterpartyAccount,
+			CounterpartyOfferID:               counterpartyOfferID,
+			CounterpartyLiquidityPoolID:       liquidityPoolID,
+			CounterpartyLiquidityPoolIDStrkey: liquidityPoolIDStrkey,
+		})
+	}
+
+	return transformedHops, nil
+}
+
+// pathPaymentFullPath returns the assets a path payment converts through, starting with the sent asset and ending with the destination asset
+func pathPaymentFullPath(operation xdr.Operation) ([]xdr.Asset, bool) {
+	switch operation.Body.Type {
+	case xdr.OperationTypePathPaymentStrictSend:
+		op := operation.Body.MustPathPaymentStrictSendOp()
+		return append(append([]xdr.Asset{op.SendAsset}, op.Path...), op.DestAsset), true
+	case xdr.OperationTypePathPaymentStrictReceive:
+		op := operation.Body.MustPathPaymentStrictReceiveOp()
+		return append(append([]xdr.Asset{op.SendAsset}, op.Path...), op.DestAsset), true
+	default:
+		return nil, false
+	}
+}
+
+// pathPaymentHopIndex returns the index of the first hop along the path that converts the source asset into the destination asset.
+// Strict receive payments are filled from the destination backwards, so the order of the claimed offers can't be used instead.
+func pathPaymentHopIndex(fullPath []xdr.Asset, source, destination xdr.Asset) (int, bool) {
+	for i := 0; i+1 < len(fullPath); i++ {
+		if fullPath[i].Equals(source) && fullPath[i+1].Equals(destination) {
+			return i, true
+		}
+	}
+
+	return 0, false
+}
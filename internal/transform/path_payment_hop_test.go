@@ -0,0 +1,179 @@
+package transform
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/guregu/null"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stellar/go-stellar-sdk/ingest"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+func TestTransformPathPaymentHops(t *testing.T) {
+	type transformTest struct {
+		transaction ingest.LedgerTransaction
+		wantOutput  []PathPaymentHopOutput
+		wantErr     error
+	}
+
+	failedTransaction := makePathPaymentHopsTestInput()
+	failedTransaction.Result.Result.Result.Code = xdr.TransactionResultCodeTxFailed
+
+	offPathTransaction := makePathPaymentHopsTestInput()
+	offPathOperation := offPathTransaction.Envelope.V1.Tx.Operations[0]
+	offPathBody := *offPathOperation.Body.PathPaymentStrictReceiveOp
+	offPathBody.Path = nil
+	offPathOperation.Body.PathPaymentStrictReceiveOp = &offPathBody
+	offPathTransaction.Envelope.V1 = &xdr.TransactionV1Envelope{
+		Tx: xdr.Transaction{
+			SourceAccount: testAccount3,
+			Operations:    []xdr.Operation{offPathOperation},
+		},
+	}
+
+	tests := []transformTest{
+		{
+			genericLedgerTransaction,
+			nil,
+			nil,
+		},
+		{
+			failedTransaction,
+			nil,
+			nil,
+		},
+		{
+			offPathTransaction,
+			[]PathPaymentHopOutput{},
+			fmt.Errorf("claimed offer 0 is not part of the path of operation at index 0"),
+		},
+		{
+			makePathPaymentHopsTestInput(),
+			makePathPaymentHopsTestOutput(),
+			nil,
+		},
+	}
+
+	for _, test := range tests {
+		actualOutput, actualError := TransformPathPaymentHops(0, 100, test.transaction, genericCloseTime)
+		assert.Equal(t, test.wantErr, actualError)
+		assert.Equal(t, test.wantOutput, actualOutput)
+	}
+}
+
+func makePathPaymentHopsTestInput() ingest.LedgerTransaction {
+	// Strict receive payments are filled from the destination backwards, so the last hop is claimed first
+	claimedOffers := []xdr.ClaimAtom{
+		{
+			Type: xdr.ClaimAtomTypeClaimAtomTypeOrderBook,
+			OrderBook: &xdr.ClaimOfferAtom{
+				SellerId:     testAccount1ID,
+				OfferId:      97684906,
+				AssetSold:    ethAsset,
+				AmountSold:   1000,
+				AssetBought:  usdtAsset,
+				AmountBought: 2000,
+			},
+		},
+		{
+			Type: xdr.ClaimAtomTypeClaimAtomTypeLiquidityPool,
+			LiquidityPool: &xdr.ClaimLiquidityAtom{
+				LiquidityPoolId: xdr.PoolId{1, 2, 3},
+				AssetSold:       usdtAsset,
+				AmountSold:      2000,
+				AssetBought:     nativeAsset,
+				AmountBought:    5000,
+			},
+		},
+	}
+
+	return ingest.LedgerTransaction{
+		Index: 1,
+		Envelope: xdr.TransactionEnvelope{
+			Type: xdr.EnvelopeTypeEnvelopeTypeTx,
+			V1: &xdr.TransactionV1Envelope{
+				Tx: xdr.Transaction{
+					SourceAccount: testAccount3,
+					Operations: []xdr.Operation{
+						{
+							Body: xdr.OperationBody{
+								Type: xdr.OperationTypePathPaymentStrictReceive,
+								PathPaymentStrictReceiveOp: &xdr.PathPaymentStrictReceiveOp{
+									SendAsset:   nativeAsset,
+									SendMax:     5000,
+									Destination: testAccount1,
+									DestAsset:   ethAsset,
+									DestAmount:  1000,
+									Path:        []xdr.Asset{usdtAsset},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		Result: wrapOperationsResultsSlice([]xdr.OperationResult{
+			{
+				Code: xdr.OperationResultCodeOpInner,
+				Tr: &xdr.OperationResultTr{
+					Type: xdr.OperationTypePathPaymentStrictReceive,
+					PathPaymentStrictReceiveResult: &xdr.PathPaymentStrictReceiveResult{
+						Code: xdr.PathPaymentStrictReceiveResultCodePathPaymentStrictReceiveSuccess,
+						Success: &xdr.PathPaymentStrictReceiveResultSuccess{
+							Offers: claimedOffers,
+						},
+					},
+				},
+			},
+		}, true),
+	}
+}
+
+func makePathPaymentHopsTestOutput() []PathPaymentHopOutput {
+	return []PathPaymentHopOutput{
+		{
+			HistoryOperationID:     101,
+			LedgerClosedAt:         genericCloseTime,
+			OperationType:          2,
+			SourceAccount:          testAccount3Address,
+			HopIndex:               1,
+			ClaimOrder:             0,
+			SourceAssetCode:        "USDT",
+			SourceAssetIssuer:      testAccount4Address,
+			SourceAssetType:        "credit_alphanum4",
+			SourceAssetID:          -8205667356306085451,
+			SourceAmount:           2000 * 0.0000001,
+			DestinationAssetCode:   "ETH",
+			DestinationAssetIssuer: testAccount3Address,
+			DestinationAssetType:   "credit_alphanum4",
+			DestinationAssetID:     4476940172956910889,
+			DestinationAmount:      1000 * 0.0000001,
+			CounterpartyType:       "offer",
+			CounterpartyAccount:    null.StringFrom(testAccount1Address),
+			CounterpartyOfferID:    null.IntFrom(97684906),
+		},
+		{
+			HistoryOperationID:                101,
+			LedgerClosedAt:                    genericCloseTime,
+			OperationType:                     2,
+			SourceAccount:                     testAccount3Address,
+			HopIndex:                          0,
+			ClaimOrder:                        1,
+			SourceAssetCode:                   "",
+			SourceAssetIssuer:                 "",
+			SourceAssetType:                   "native",
+			SourceAssetID:                     -5706705804583548011,
+			SourceAmount:                      5000 * 0.0000001,
+			DestinationAssetCode:              "USDT",
+			DestinationAssetIssuer:            testAccount4Address,
+			DestinationAssetType:              "credit_alphanum4",
+			DestinationAssetID:                -8205667356306085451,
+			DestinationAmount:                 2000 * 0.0000001,
+			CounterpartyType:                  "liquidity_pool",
+			CounterpartyLiquidityPoolID:       null.StringFrom("0102030000000000000000000000000000000000000000000000000000000000"),
+			CounterpartyLiquidityPoolIDStrkey: null.StringFrom("LAAQEAYAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAABOK7"),
+		},
+	}
+}
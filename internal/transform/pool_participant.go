@@ -0,0 +1,42 @@
+package transform
+
+import (
+	"github.com/stellar/go-stellar-sdk/ingest"
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stellar/stellar-etl/v2/internal/utils"
+)
+
+// TransformPoolParticipant converts a change of a pool share trustline into the change of the shares that its account
+// holds in the liquidity pool. The shares of a created trustline are all added, and the shares of a removed trustline are
+// all withdrawn. Trustlines of other assets are not pool participations.
+func TransformPoolParticipant(ledgerChange ingest.Change, header xdr.LedgerHeaderHistoryEntry) (PoolParticipantOutput, bool, error) {
+	trustline, err := TransformTrustline(ledgerChange, header)
+	if err != nil {
+		return PoolParticipantOutput{}, false, err
+	}
+	if trustline.LiquidityPoolID == "" {
+		return PoolParticipantOutput{}, false, nil
+	}
+
+	var shares, previousShares xdr.Int64
+	if ledgerChange.Post != nil {
+		shares = ledgerChange.Post.Data.MustTrustLine().Balance
+	}
+	if ledgerChange.Pre != nil {
+		previousShares = ledgerChange.Pre.Data.MustTrustLine().Balance
+	}
+
+	return PoolParticipantOutput{
+		AccountID:             trustline.AccountID,
+		LiquidityPoolID:       trustline.LiquidityPoolID,
+		LiquidityPoolIDStrkey: trustline.LiquidityPoolIDStrkey,
+		SharesDelta:           utils.ConvertStroopValueToReal(shares - previousShares),
+		Shares:                utils.ConvertStroopValueToReal(shares),
+		LastModifiedLedger:    trustline.LastModifiedLedger,
+		LedgerEntryChange:     trustline.LedgerEntryChange,
+		Deleted:               trustline.Deleted,
+		ClosedAt:              trustline.ClosedAt,
+		LedgerSequence:        trustline.LedgerSequence,
+		LedgerKeyHash:         trustline.LedgerKeyHash,
+	}, true, nil
+}
@@ -0,0 +1,87 @@
+package transform
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stellar/go-stellar-sdk/ingest"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+func TestTransformPoolParticipant(t *testing.T) {
+	header := xdr.LedgerHeaderHistoryEntry{
+		Header: xdr.LedgerHeader{
+			ScpValue:  xdr.StellarValue{CloseTime: 1000},
+			LedgerSeq: 10,
+		},
+	}
+	oldEntry := makeParticipantTrustlineEntry(liquidityPoolAsset, 9, 50000000)
+	newEntry := makeParticipantTrustlineEntry(liquidityPoolAsset, 10, 35000000)
+
+	updated := ingest.Change{
+		Type:       xdr.LedgerEntryTypeTrustline,
+		Pre:        &oldEntry,
+		Post:       &newEntry,
+		ChangeType: xdr.LedgerEntryChangeTypeLedgerEntryUpdated,
+	}
+	trustline, err := TransformTrustline(updated, header)
+	assert.NoError(t, err)
+
+	participant, ok, err := TransformPoolParticipant(updated, header)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, PoolParticipantOutput{
+		AccountID:             testAccount1Address,
+		LiquidityPoolID:       trustline.LiquidityPoolID,
+		LiquidityPoolIDStrkey: trustline.LiquidityPoolIDStrkey,
+		SharesDelta:           -1.5,
+		Shares:                3.5,
+		LastModifiedLedger:    10,
+		LedgerEntryChange:     1,
+		Deleted:               false,
+		ClosedAt:              time.Date(1970, time.January, 1, 0, 16, 40, 0, time.UTC),
+		LedgerSequence:        10,
+		LedgerKeyHash:         trustline.LedgerKeyHash,
+	}, participant)
+
+	// A removed trustline withdraws all of its shares
+	removed := ingest.Change{
+		Type:       xdr.LedgerEntryTypeTrustline,
+		Pre:        &oldEntry,
+		ChangeType: xdr.LedgerEntryChangeTypeLedgerEntryRemoved,
+	}
+	participant, ok, err = TransformPoolParticipant(removed, header)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, -5.0, participant.SharesDelta)
+	assert.Equal(t, 0.0, participant.Shares)
+	assert.True(t, participant.Deleted)
+
+	// Trustlines of other assets are not pool participations
+	assetEntry := makeParticipantTrustlineEntry(usdtTrustLineAsset, 10, 35000000)
+	_, ok, err = TransformPoolParticipant(ingest.Change{
+		Type:       xdr.LedgerEntryTypeTrustline,
+		Post:       &assetEntry,
+		ChangeType: xdr.LedgerEntryChangeTypeLedgerEntryCreated,
+	}, header)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func makeParticipantTrustlineEntry(asset xdr.TrustLineAsset, lastModifiedLedger uint32, balance int64) xdr.LedgerEntry {
+	return xdr.LedgerEntry{
+		LastModifiedLedgerSeq: xdr.Uint32(lastModifiedLedger),
+		Data: xdr.LedgerEntryData{
+			Type: xdr.LedgerEntryTypeTrustline,
+			TrustLine: &xdr.TrustLineEntry{
+				AccountId: testAccount1ID,
+				Asset:     asset,
+				Balance:   xdr.Int64(balance),
+				Limit:     1000000000,
+				Flags:     xdr.Uint32(xdr.TrustLineFlagsAuthorizedFlag),
+			},
+		},
+	}
+}
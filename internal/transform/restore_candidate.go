@@ -0,0 +1,161 @@
+package transform
+
+import (
+	"fmt"
+
+	"github.com/guregu/null"
+	"github.com/stellar/go-stellar-sdk/ingest"
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stellar/stellar-etl/v2/internal/toid"
+	"github.com/stellar/stellar-etl/v2/internal/utils"
+)
+
+const (
+	// restoreReasonEntryArchived is a footprint entry of a transaction that failed because an entry was archived
+	restoreReasonEntryArchived = "entry_archived"
+	// restoreReasonMarkedArchived is a footprint entry that the transaction declared as archived, to be restored by it
+	restoreReasonMarkedArchived = "marked_archived"
+)
+
+// TransformRestoreCandidates converts the footprint of a soroban transaction that tried to use archived entries into the
+// entries that may need a restore. liveUntil has the live until ledger of the ttls known so far, keyed by the hash of the
+// ledger key of their entry.
+//
+// A transaction that fails with INVOKE_HOST_FUNCTION_ENTRY_ARCHIVED doesn't say which entry is archived, so every
+// persistent entry and contract code of its footprint is a candidate, except the ones whose known ttl is still live.
+// Entries that a transaction marks as archived in its footprint are always candidates. Temporary entries are deleted
+// once they expire, so they can't be restored and are left out.
+func TransformRestoreCandidates(transaction ingest.LedgerTransaction, lhe xdr.LedgerHeaderHistoryEntry, liveUntil map[string]uint32) ([]RestoreCandidateOutput, error) {
+	sorobanData, ok := transaction.GetSorobanData()
+	if !ok {
+		return nil, nil
+	}
+
+	ledgerSequence := uint32(lhe.Header.LedgerSeq)
+	entryArchived := failedWithArchivedEntry(transaction)
+
+	markedArchived := map[int]bool{}
+	if resourceExt, ok := sorobanData.Ext.GetResourceExt(); ok {
+		for _, index := range resourceExt.ArchivedSorobanEntries {
+			markedArchived[int(index)] = true
+		}
+	}
+	if !entryArchived && len(markedArchived) == 0 {
+		return nil, nil
+	}
+
+	closedAt, err := utils.TimePointToUTCTimeStamp(lhe.Header.ScpValue.CloseTime)
+	if err != nil {
+		return nil, err
+	}
+
+	outputTransactionHash := utils.HashToHexString(transaction.Result.TransactionHash)
+	outputTransactionID := toid.New(int32(ledgerSequence), int32(transaction.Index), 0).ToInt64()
+
+	footprint := sorobanData.Resources.Footprint
+	keys := append(append([]xdr.LedgerKey{}, footprint.ReadOnly...), footprint.ReadWrite...)
+
+	candidates := []RestoreCandidateOutput{}
+	for i, ledgerKey := range keys {
+		if !restorable(ledgerKey) {
+			continue
+		}
+
+		// The archived entry indexes point into the read-write footprint, which comes after the read-only one
+		reason := restoreReasonEntryArchived
+		if i >= len(footprint.ReadOnly) && markedArchived[i-len(footprint.ReadOnly)] {
+			reason = restoreReasonMarkedArchived
+		} else if !entryArchived {
+			continue
+		}
+
+		ledgerKeyHash := utils.LedgerKeyToLedgerKeyHash(ledgerKey)
+		var outputLiveUntil null.Int
+		if liveUntilLedger, ok := liveUntil[ledgerKeyHash]; ok {
+			if reason == restoreReasonEntryArchived && liveUntilLedger >= ledgerSequence {
+				continue
+			}
+			outputLiveUntil = null.IntFrom(int64(liveUntilLedger))
+		}
+
+		footprintKey, err := decodeFootprintKey(ledgerKey)
+		if err != nil {
+			return nil, fmt.Errorf("for ledger %d; transaction %d (transaction id=%d): %v", ledgerSequence, transaction.Index, outputTransactionID, err)
+		}
+
+		candidates = append(candidates, RestoreCandidateOutput{
+			LedgerKeyHash:       footprintKey.LedgerKeyHash,
+			LedgerKeyHashBase64: footprintKey.LedgerKeyHashBase64,
+			LedgerEntryType:     footprintKey.LedgerEntryType,
+			ContractId:          footprintKey.ContractId,
+			ContractKeyType:     footprintKey.ContractKeyType,
+			ContractCodeHash:    footprintKey.ContractCodeHash,
+			Reason:              reason,
+			LiveUntilLedgerSeq:  outputLiveUntil,
+			TransactionHash:     outputTransactionHash,
+			TransactionID:       outputTransactionID,
+			Successful:          transaction.Successful(),
+			ClosedAt:            closedAt,
+			LedgerSequence:      ledgerSequence,
+		})
+	}
+
+	return candidates, nil
+}
+
+// TransactionTtls returns the live until ledger of the ttls changed by a transaction, keyed by the hash of the ledger key
+// of their entry. Removed ttls have a live until ledger of 0.
+func TransactionTtls(transaction ingest.LedgerTransaction) (map[string]uint32, error) {
+	changes, err := transaction.GetChanges()
+	if err != nil {
+		return nil, err
+	}
+
+	ttls := map[string]uint32{}
+	for _, change := range changes {
+		if change.Type != xdr.LedgerEntryTypeTtl {
+			continue
+		}
+		if change.Post == nil {
+			ttls[change.Pre.Data.MustTtl().KeyHash.HexString()] = 0
+			continue
+		}
+
+		ttl := change.Post.Data.MustTtl()
+		ttls[ttl.KeyHash.HexString()] = uint32(ttl.LiveUntilLedgerSeq)
+	}
+	return ttls, nil
+}
+
+// failedWithArchivedEntry checks if an operation of a transaction failed because it used an archived entry
+func failedWithArchivedEntry(transaction ingest.LedgerTransaction) bool {
+	if transaction.Successful() {
+		return false
+	}
+
+	results, ok := transaction.Result.Result.OperationResults()
+	if !ok {
+		return false
+	}
+	for _, result := range results {
+		invokeResult, ok := result.GetTr()
+		if !ok || invokeResult.Type != xdr.OperationTypeInvokeHostFunction {
+			continue
+		}
+		if invokeResult.InvokeHostFunctionResult.Code == xdr.InvokeHostFunctionResultCodeInvokeHostFunctionEntryArchived {
+			return true
+		}
+	}
+	return false
+}
+
+// restorable checks if an entry of a footprint is restored from the archive once it expires
+func restorable(ledgerKey xdr.LedgerKey) bool {
+	switch ledgerKey.Type {
+	case xdr.LedgerEntryTypeContractCode:
+		return true
+	case xdr.LedgerEntryTypeContractData:
+		return ledgerKey.ContractData.Durability == xdr.ContractDataDurabilityPersistent
+	}
+	return false
+}
@@ -0,0 +1,85 @@
+package transform
+
+import (
+	"testing"
+	"time"
+
+	"github.com/guregu/null"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+func TestTransformRestoreCandidates(t *testing.T) {
+	header := xdr.LedgerHeaderHistoryEntry{
+		Header: xdr.LedgerHeader{
+			ScpValue: xdr.StellarValue{
+				CloseTime: 1000,
+			},
+			LedgerSeq: 10,
+		},
+	}
+
+	// Successful transactions that didn't mark any entry as archived have no candidates
+	actualOutput, actualError := TransformRestoreCandidates(makeFootprintReadTestInput(), header, map[string]uint32{})
+	assert.NoError(t, actualError)
+	assert.Empty(t, actualOutput)
+
+	// The contract code is known to be live, so only the contract instance is a candidate
+	failed := makeFootprintReadTestInput()
+	failed.Result.Result.Result = xdr.TransactionResultResult{
+		Code: xdr.TransactionResultCodeTxFailed,
+		Results: &[]xdr.OperationResult{
+			{
+				Code: xdr.OperationResultCodeOpInner,
+				Tr: &xdr.OperationResultTr{
+					Type: xdr.OperationTypeInvokeHostFunction,
+					InvokeHostFunctionResult: &xdr.InvokeHostFunctionResult{
+						Code: xdr.InvokeHostFunctionResultCodeInvokeHostFunctionEntryArchived,
+					},
+				},
+			},
+		},
+	}
+	liveUntil := map[string]uint32{
+		"8dff10f772999940d867a653f238c7c85af80a9d43a1bc541614c054a76872f7": 20,
+		"c74e644e72137ad7ccab5f4265db185e075f6d7f07255353da086ee3da0b06c3": 9,
+	}
+
+	actualOutput, actualError = TransformRestoreCandidates(failed, header, liveUntil)
+	assert.NoError(t, actualError)
+	assert.Equal(t, []RestoreCandidateOutput{
+		{
+			LedgerKeyHash:       "c74e644e72137ad7ccab5f4265db185e075f6d7f07255353da086ee3da0b06c3",
+			LedgerKeyHashBase64: "AAAABgAAAAEBAgMAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAABQAAAAB",
+			LedgerEntryType:     "LedgerEntryTypeContractData",
+			ContractId:          "CAAQEAYAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAABXAS",
+			ContractKeyType:     "ScValTypeScvLedgerKeyContractInstance",
+			Reason:              "entry_archived",
+			LiveUntilLedgerSeq:  null.IntFrom(9),
+			TransactionHash:     "0708090000000000000000000000000000000000000000000000000000000000",
+			TransactionID:       42949672960,
+			Successful:          false,
+			ClosedAt:            time.Date(1970, time.January, 1, 0, 16, 40, 0, time.UTC),
+			LedgerSequence:      10,
+		},
+	}, actualOutput)
+
+	// Entries marked as archived are candidates even if the transaction succeeded
+	marked := makeFootprintReadTestInput()
+	sorobanData := marked.Envelope.V1.Tx.Ext.SorobanData
+	footprint := &sorobanData.Resources.Footprint
+	footprint.ReadOnly, footprint.ReadWrite = footprint.ReadOnly[1:], []xdr.LedgerKey{footprint.ReadOnly[0]}
+	sorobanData.Ext = xdr.SorobanTransactionDataExt{
+		V:           1,
+		ResourceExt: &xdr.SorobanResourcesExtV0{ArchivedSorobanEntries: []xdr.Uint32{0}},
+	}
+
+	actualOutput, actualError = TransformRestoreCandidates(marked, header, map[string]uint32{})
+	assert.NoError(t, actualError)
+	assert.Len(t, actualOutput, 1)
+	assert.Equal(t, "c74e644e72137ad7ccab5f4265db185e075f6d7f07255353da086ee3da0b06c3", actualOutput[0].LedgerKeyHash)
+	assert.Equal(t, "marked_archived", actualOutput[0].Reason)
+	assert.False(t, actualOutput[0].LiveUntilLedgerSeq.Valid)
+	assert.True(t, actualOutput[0].Successful)
+}
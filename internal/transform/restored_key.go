@@ -19,15 +19,13 @@ func TransformRestoredKey(ledgerChange ingest.Change, header xdr.LedgerHeaderHis
 		return RestoredKeyOutput{}, fmt.Errorf("expected change type to be LedgerEntryRestored, got %s", changeType.String())
 	}
 
-	key, err := ledgerEntry.LedgerKey()
-	if err != nil {
-		return RestoredKeyOutput{}, err
-	}
-	ledgerKeyHash, err := xdr.MarshalBase64(key)
-	ledgerEntryType := key.Type.String()
+	// ledger_key_hash of restored keys has always been the base64 encoded key, so the hex encoded hash that the other
+	// change exports call ledger_key_hash is added as ledger_key_hash_hex
+	ledgerKeyHashHex, ledgerKeyHashBase64, err := utils.LedgerEntryToLedgerKeyHashes(ledgerEntry)
 	if err != nil {
 		return RestoredKeyOutput{}, err
 	}
+	ledgerEntryType := ledgerEntry.Data.Type.String()
 
 	closedAt, err := utils.TimePointToUTCTimeStamp(header.Header.ScpValue.CloseTime)
 	if err != nil {
@@ -38,11 +36,12 @@ func TransformRestoredKey(ledgerChange ingest.Change, header xdr.LedgerHeaderHis
 	ledgerSequence := header.Header.LedgerSeq
 
 	transformedKey := RestoredKeyOutput{
-		LedgerKeyHash:      ledgerKeyHash,
+		LedgerKeyHash:      ledgerKeyHashBase64,
 		LedgerEntryType:    ledgerEntryType,
 		LastModifiedLedger: outputLastModifiedLedger,
 		ClosedAt:           closedAt,
 		LedgerSequence:     uint32(ledgerSequence),
+		LedgerKeyHashHex:   ledgerKeyHashHex,
 	}
 	return transformedKey, nil
 }
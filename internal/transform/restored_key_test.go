@@ -87,6 +87,7 @@ func makeRestoredKeyTestOutput() RestoredKeyOutput {
 		LedgerEntryType:    "LedgerEntryTypeOffer",
 		LastModifiedLedger: 30715263,
 		LedgerSequence:     10,
+		LedgerKeyHashHex:   "8cc323d734b0263b26708e4842c3a3a9cd3db13146e5e7a5862092749e5d3377",
 		ClosedAt:           time.Date(1970, time.January, 1, 0, 16, 40, 0, time.UTC),
 	}
 }
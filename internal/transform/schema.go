@@ -21,6 +21,10 @@ type LedgerOutput struct {
 	FailedTransactionCount          int32     `json:"failed_transaction_count"`
 	TxSetOperationCount             string    `json:"tx_set_operation_count"` // counts all operations, even those that are part of failed transactions
 	ClosedAt                        time.Time `json:"closed_at"`              // UTC timestamp
+	TxSetHash                       string    `json:"tx_set_hash"`
+	ClassicPhaseTransactionCount    int32     `json:"classic_phase_transaction_count"`
+	SorobanPhaseTransactionCount    int32     `json:"soroban_phase_transaction_count"`
+	ApplyOrderDiffers               bool      `json:"apply_order_differs"`
 	TotalCoins                      int64     `json:"total_coins"`
 	FeePool                         int64     `json:"fee_pool"`
 	BaseFee                         uint32    `json:"base_fee"`
@@ -37,6 +41,43 @@ type LedgerOutput struct {
 	EvictedLedgerKeysHash           []string  `json:"evicted_ledger_keys_hash"`
 }
 
+// LedgerFeeStatsOutput is a representation of the inclusion metrics of a ledger that aligns with the BigQuery table ledger_fee_stats
+type LedgerFeeStatsOutput struct {
+	LedgerSequence        uint32    `json:"ledger_sequence"`
+	ClosedAt              time.Time `json:"closed_at"`
+	BaseFee               uint32    `json:"base_fee"`
+	MaxTxSetSize          uint32    `json:"max_tx_set_size"`
+	TransactionCount      int32     `json:"transaction_count"`      // counts all transactions in the transaction set, even failed ones
+	TxSetOperationCount   int32     `json:"tx_set_operation_count"` // counts all operations in the transaction set
+	ClassicOperationCount int32     `json:"classic_operation_count"`
+	CapacityUsage         float64   `json:"capacity_usage"` // classic operations over the max tx set size
+	MinInclusionFee       int64     `json:"min_inclusion_fee"`
+	MedianInclusionFee    int64     `json:"median_inclusion_fee"`
+	MaxInclusionFee       int64     `json:"max_inclusion_fee"`
+	InclusionBaseFee      int64     `json:"inclusion_base_fee"` // highest base fee charged by a transaction set component
+	IsSurge               bool      `json:"is_surge"`
+}
+
+// AccountOperationOutput is an (account, operation) pair that aligns with the BigQuery table account_operations
+type AccountOperationOutput struct {
+	AccountID   string `json:"account_id"`
+	OperationID int64  `json:"operation_id"`
+}
+
+// ParticipantOutput is an account or contract taking part in an operation, along with its role, that aligns with the BigQuery table participants
+type ParticipantOutput struct {
+	OperationID     int64     `json:"operation_id"`
+	TransactionID   int64     `json:"transaction_id"`
+	TransactionHash string    `json:"transaction_hash"`
+	OperationType   string    `json:"type_string"`
+	Account         string    `json:"account"`
+	AccountMuxed    string    `json:"account_muxed,omitempty"`
+	Role            string    `json:"role"`
+	Successful      bool      `json:"successful"`
+	ClosedAt        time.Time `json:"closed_at"`
+	LedgerSequence  uint32    `json:"ledger_sequence"`
+}
+
 // TransactionOutput is a representation of a transaction that aligns with the BigQuery table history_transactions
 type TransactionOutput struct {
 	TransactionHash                      string         `json:"transaction_hash"`
@@ -62,6 +103,9 @@ type TransactionOutput struct {
 	InnerTransactionHash                 string         `json:"inner_transaction_hash,omitempty"`
 	NewMaxFee                            uint32         `json:"new_max_fee,omitempty"`
 	LedgerBounds                         string         `json:"ledger_bounds"`
+	ValidAfter                           null.Bool      `json:"valid_after"`
+	ValidBefore                          null.Bool      `json:"valid_before"`
+	WithinLedgerBounds                   null.Bool      `json:"within_ledger_bounds"`
 	MinAccountSequence                   null.Int       `json:"min_account_sequence"`
 	MinAccountSequenceAge                null.Int       `json:"min_account_sequence_age"`
 	MinAccountSequenceLedgerGap          null.Int       `json:"min_account_sequence_ledger_gap"`
@@ -77,10 +121,15 @@ type TransactionOutput struct {
 	InclusionFeeBid                      int64          `json:"inclusion_fee_bid"`
 	InclusionFeeCharged                  int64          `json:"inclusion_fee_charged"`
 	ResourceFeeRefund                    int64          `json:"resource_fee_refund"`
+	ResourceFeeCharged                   int64          `json:"resource_fee_charged"`
 	TotalNonRefundableResourceFeeCharged int64          `json:"non_refundable_resource_fee_charged"`
 	TotalRefundableResourceFeeCharged    int64          `json:"refundable_resource_fee_charged"`
 	RentFeeCharged                       int64          `json:"rent_fee_charged"`
 	TxSigners                            []string       `json:"tx_signers"`
+	ContractEventsCount                  int32          `json:"contract_events_count"`
+	DiagnosticEventsCount                int32          `json:"diagnostic_events_count"`
+	IsSoroban                            bool           `json:"is_soroban"`
+	SorobanResourcesUsed                 bool           `json:"soroban_resources_used"`
 }
 
 type LedgerTransactionOutput struct {
@@ -113,59 +162,74 @@ type AccountOutput struct {
 	Sponsor              null.String `json:"sponsor"`
 	NumSponsored         uint32      `json:"num_sponsored"`
 	NumSponsoring        uint32      `json:"num_sponsoring"`
+	MinimumBalance       float64     `json:"minimum_balance"`
+	AvailableBalance     float64     `json:"available_balance"`
 	LastModifiedLedger   uint32      `json:"last_modified_ledger"`
 	LedgerEntryChange    uint32      `json:"ledger_entry_change"`
 	Deleted              bool        `json:"deleted"`
 	ClosedAt             time.Time   `json:"closed_at"`
 	LedgerSequence       uint32      `json:"ledger_sequence"`
+	LedgerKeyHash        string      `json:"ledger_key_hash"`
+	LedgerKeyHashBase64  string      `json:"ledger_key_hash_base_64"`
 }
 
 // AccountSignerOutput is a representation of an account signer that aligns with the BigQuery table account_signers
 type AccountSignerOutput struct {
-	AccountID          string      `json:"account_id"`
-	Signer             string      `json:"signer"`
-	Weight             int32       `json:"weight"`
-	Sponsor            null.String `json:"sponsor"`
-	LastModifiedLedger uint32      `json:"last_modified_ledger"`
-	LedgerEntryChange  uint32      `json:"ledger_entry_change"`
-	Deleted            bool        `json:"deleted"`
-	ClosedAt           time.Time   `json:"closed_at"`
-	LedgerSequence     uint32      `json:"ledger_sequence"`
+	AccountID           string      `json:"account_id"`
+	Signer              string      `json:"signer"`
+	Weight              int32       `json:"weight"`
+	Sponsor             null.String `json:"sponsor"`
+	LastModifiedLedger  uint32      `json:"last_modified_ledger"`
+	LedgerEntryChange   uint32      `json:"ledger_entry_change"`
+	Deleted             bool        `json:"deleted"`
+	ClosedAt            time.Time   `json:"closed_at"`
+	LedgerSequence      uint32      `json:"ledger_sequence"`
+	LedgerKeyHash       string      `json:"ledger_key_hash"`
+	LedgerKeyHashBase64 string      `json:"ledger_key_hash_base_64"`
 }
 
 // OperationOutput is a representation of an operation that aligns with the BigQuery table history_operations
 type OperationOutput struct {
-	SourceAccount        string                 `json:"source_account"`
-	SourceAccountMuxed   string                 `json:"source_account_muxed,omitempty"`
-	Type                 int32                  `json:"type"`
-	TypeString           string                 `json:"type_string"`
-	OperationDetails     map[string]interface{} `json:"details"` //Details is a JSON object that varies based on operation type
-	TransactionID        int64                  `json:"transaction_id"`
-	OperationID          int64                  `json:"id"`
-	ClosedAt             time.Time              `json:"closed_at"`
-	OperationResultCode  string                 `json:"operation_result_code"`
-	OperationTraceCode   string                 `json:"operation_trace_code"`
-	LedgerSequence       uint32                 `json:"ledger_sequence"`
-	OperationDetailsJSON map[string]interface{} `json:"details_json"`
+	SourceAccount        string                 `json:"source_account" description:"Account that is the source of the operation, or of its transaction if the operation has none"`
+	SourceAccountMuxed   string                 `json:"source_account_muxed,omitempty" description:"Muxed account that is the source of the operation, if the source is a muxed account"`
+	Type                 int32                  `json:"type" description:"Number of the operation type"`
+	TypeString           string                 `json:"type_string" description:"Name of the operation type, such as payment or invoke_host_function"`
+	OperationDetails     map[string]interface{} `json:"details" description:"Fields of the operation that depend on its type"` //Details is a JSON object that varies based on operation type
+	TransactionID        int64                  `json:"transaction_id" description:"ID of the transaction of the operation"`
+	OperationID          int64                  `json:"id" description:"ID of the operation, unique across all operations"`
+	ClosedAt             time.Time              `json:"closed_at" description:"Close time of the ledger of the operation, in UTC"`
+	OperationResultCode  string                 `json:"operation_result_code" description:"Result code of the operation"`
+	OperationTraceCode   string                 `json:"operation_trace_code" description:"Result code of the operation type, such as PaymentResultCodePaymentSuccess"`
+	LedgerSequence       uint32                 `json:"ledger_sequence" description:"Sequence number of the ledger of the operation"`
+	OperationDetailsJSON map[string]interface{} `json:"details_json" description:"Same as details; it is not written to parquet outputs"`
+	ParentOperationID    int64                  `json:"parent_operation_id,omitempty" description:"ID of the operation whose authorized sub-invocation the row is, set only with expand-sub-invocations"` //Only set on the rows derived from the sub-invocations of an operation
+}
+
+// FlattenedOperationOutput is an operation whose parquet output stores the well-known detail keys in typed columns.
+// Its JSON output is the same as the JSON output of the operation.
+type FlattenedOperationOutput struct {
+	OperationOutput
 }
 
 // ClaimableBalanceOutput is a representation of a claimable balances that aligns with the BigQuery table claimable_balances
 type ClaimableBalanceOutput struct {
-	BalanceID          string      `json:"balance_id"`
-	Claimants          []Claimant  `json:"claimants"`
-	AssetCode          string      `json:"asset_code"`
-	AssetIssuer        string      `json:"asset_issuer"`
-	AssetType          string      `json:"asset_type"`
-	AssetID            int64       `json:"asset_id"`
-	AssetAmount        float64     `json:"asset_amount"`
-	Sponsor            null.String `json:"sponsor"`
-	Flags              uint32      `json:"flags"`
-	LastModifiedLedger uint32      `json:"last_modified_ledger"`
-	LedgerEntryChange  uint32      `json:"ledger_entry_change"`
-	Deleted            bool        `json:"deleted"`
-	ClosedAt           time.Time   `json:"closed_at"`
-	LedgerSequence     uint32      `json:"ledger_sequence"`
-	BalanceIDStrkey    string      `json:"balance_id_strkey"`
+	BalanceID           string      `json:"balance_id"`
+	Claimants           []Claimant  `json:"claimants"`
+	AssetCode           string      `json:"asset_code"`
+	AssetIssuer         string      `json:"asset_issuer"`
+	AssetType           string      `json:"asset_type"`
+	AssetID             int64       `json:"asset_id"`
+	AssetAmount         float64     `json:"asset_amount"`
+	Sponsor             null.String `json:"sponsor"`
+	Flags               uint32      `json:"flags"`
+	LastModifiedLedger  uint32      `json:"last_modified_ledger"`
+	LedgerEntryChange   uint32      `json:"ledger_entry_change"`
+	Deleted             bool        `json:"deleted"`
+	ClosedAt            time.Time   `json:"closed_at"`
+	LedgerSequence      uint32      `json:"ledger_sequence"`
+	BalanceIDStrkey     string      `json:"balance_id_strkey"`
+	LedgerKeyHash       string      `json:"ledger_key_hash"`
+	LedgerKeyHashBase64 string      `json:"ledger_key_hash_base_64"`
 }
 
 // Claimants
@@ -185,6 +249,7 @@ type Path struct {
 	AssetCode   string `json:"asset_code"`
 	AssetIssuer string `json:"asset_issuer"`
 	AssetType   string `json:"asset_type"`
+	AssetID     int64  `json:"asset_id"`
 }
 
 // LiquidityPoolAsset represents the asset pairs in a liquidity pool
@@ -201,27 +266,29 @@ type LiquidityPoolAsset struct {
 
 // PoolOutput is a representation of a liquidity pool that aligns with the Bigquery table liquidity_pools
 type PoolOutput struct {
-	PoolID             string    `json:"liquidity_pool_id"`
-	PoolType           string    `json:"type"`
-	PoolFee            uint32    `json:"fee"`
-	TrustlineCount     uint64    `json:"trustline_count"`
-	PoolShareCount     float64   `json:"pool_share_count"`
-	AssetAType         string    `json:"asset_a_type"`
-	AssetACode         string    `json:"asset_a_code"`
-	AssetAIssuer       string    `json:"asset_a_issuer"`
-	AssetAReserve      float64   `json:"asset_a_amount"`
-	AssetAID           int64     `json:"asset_a_id"`
-	AssetBType         string    `json:"asset_b_type"`
-	AssetBCode         string    `json:"asset_b_code"`
-	AssetBIssuer       string    `json:"asset_b_issuer"`
-	AssetBReserve      float64   `json:"asset_b_amount"`
-	AssetBID           int64     `json:"asset_b_id"`
-	LastModifiedLedger uint32    `json:"last_modified_ledger"`
-	LedgerEntryChange  uint32    `json:"ledger_entry_change"`
-	Deleted            bool      `json:"deleted"`
-	ClosedAt           time.Time `json:"closed_at"`
-	LedgerSequence     uint32    `json:"ledger_sequence"`
-	PoolIDStrkey       string    `json:"liquidity_pool_id_strkey"`
+	PoolID              string    `json:"liquidity_pool_id"`
+	PoolType            string    `json:"type"`
+	PoolFee             uint32    `json:"fee"`
+	TrustlineCount      uint64    `json:"trustline_count"`
+	PoolShareCount      float64   `json:"pool_share_count"`
+	AssetAType          string    `json:"asset_a_type"`
+	AssetACode          string    `json:"asset_a_code"`
+	AssetAIssuer        string    `json:"asset_a_issuer"`
+	AssetAReserve       float64   `json:"asset_a_amount"`
+	AssetAID            int64     `json:"asset_a_id"`
+	AssetBType          string    `json:"asset_b_type"`
+	AssetBCode          string    `json:"asset_b_code"`
+	AssetBIssuer        string    `json:"asset_b_issuer"`
+	AssetBReserve       float64   `json:"asset_b_amount"`
+	AssetBID            int64     `json:"asset_b_id"`
+	LastModifiedLedger  uint32    `json:"last_modified_ledger"`
+	LedgerEntryChange   uint32    `json:"ledger_entry_change"`
+	Deleted             bool      `json:"deleted"`
+	ClosedAt            time.Time `json:"closed_at"`
+	LedgerSequence      uint32    `json:"ledger_sequence"`
+	PoolIDStrkey        string    `json:"liquidity_pool_id_strkey"`
+	LedgerKeyHash       string    `json:"ledger_key_hash"`
+	LedgerKeyHashBase64 string    `json:"ledger_key_hash_base_64"`
 }
 
 // AssetOutput is a representation of an asset that aligns with the BigQuery table history_assets
@@ -234,6 +301,27 @@ type AssetOutput struct {
 	LedgerSequence uint32    `json:"ledger_sequence"`
 }
 
+// AssetMetadataOutput is the metadata that the issuer of an asset publishes in the stellar.toml of its home domain,
+// that aligns with the BigQuery table asset_metadata
+type AssetMetadataOutput struct {
+	AssetCode       string    `json:"asset_code"`
+	AssetIssuer     string    `json:"asset_issuer"`
+	AssetType       string    `json:"asset_type"`
+	AssetID         int64     `json:"asset_id"`
+	HomeDomain      string    `json:"home_domain"`
+	OrgName         string    `json:"org_name"`
+	Name            string    `json:"name"`
+	Description     string    `json:"description"`
+	Image           string    `json:"image"`
+	DisplayDecimals null.Int  `json:"display_decimals"`
+	Status          string    `json:"status"`
+	IsAssetAnchored bool      `json:"is_asset_anchored"`
+	AnchorAssetType string    `json:"anchor_asset_type"`
+	AnchorAsset     string    `json:"anchor_asset"`
+	MetadataError   string    `json:"metadata_error"`
+	FetchedAt       time.Time `json:"fetched_at"`
+}
+
 // TrustlineOutput is a representation of a trustline that aligns with the BigQuery table trust_lines
 type TrustlineOutput struct {
 	LedgerKey             string      `json:"ledger_key"`
@@ -255,31 +343,35 @@ type TrustlineOutput struct {
 	ClosedAt              time.Time   `json:"closed_at"`
 	LedgerSequence        uint32      `json:"ledger_sequence"`
 	LiquidityPoolIDStrkey string      `json:"liquidity_pool_id_strkey"`
+	LedgerKeyHash         string      `json:"ledger_key_hash"`
+	LedgerKeyHashBase64   string      `json:"ledger_key_hash_base_64"`
 }
 
 // OfferOutput is a representation of an offer that aligns with the BigQuery table offers
 type OfferOutput struct {
-	SellerID           string      `json:"seller_id"` // Account address of the seller
-	OfferID            int64       `json:"offer_id"`
-	SellingAssetType   string      `json:"selling_asset_type"`
-	SellingAssetCode   string      `json:"selling_asset_code"`
-	SellingAssetIssuer string      `json:"selling_asset_issuer"`
-	SellingAssetID     int64       `json:"selling_asset_id"`
-	BuyingAssetType    string      `json:"buying_asset_type"`
-	BuyingAssetCode    string      `json:"buying_asset_code"`
-	BuyingAssetIssuer  string      `json:"buying_asset_issuer"`
-	BuyingAssetID      int64       `json:"buying_asset_id"`
-	Amount             float64     `json:"amount"`
-	PriceN             int32       `json:"pricen"`
-	PriceD             int32       `json:"priced"`
-	Price              float64     `json:"price"`
-	Flags              uint32      `json:"flags"`
-	LastModifiedLedger uint32      `json:"last_modified_ledger"`
-	LedgerEntryChange  uint32      `json:"ledger_entry_change"`
-	Deleted            bool        `json:"deleted"`
-	Sponsor            null.String `json:"sponsor"`
-	ClosedAt           time.Time   `json:"closed_at"`
-	LedgerSequence     uint32      `json:"ledger_sequence"`
+	SellerID            string      `json:"seller_id"` // Account address of the seller
+	OfferID             int64       `json:"offer_id"`
+	SellingAssetType    string      `json:"selling_asset_type"`
+	SellingAssetCode    string      `json:"selling_asset_code"`
+	SellingAssetIssuer  string      `json:"selling_asset_issuer"`
+	SellingAssetID      int64       `json:"selling_asset_id"`
+	BuyingAssetType     string      `json:"buying_asset_type"`
+	BuyingAssetCode     string      `json:"buying_asset_code"`
+	BuyingAssetIssuer   string      `json:"buying_asset_issuer"`
+	BuyingAssetID       int64       `json:"buying_asset_id"`
+	Amount              float64     `json:"amount"`
+	PriceN              int32       `json:"pricen"`
+	PriceD              int32       `json:"priced"`
+	Price               float64     `json:"price"`
+	Flags               uint32      `json:"flags"`
+	LastModifiedLedger  uint32      `json:"last_modified_ledger"`
+	LedgerEntryChange   uint32      `json:"ledger_entry_change"`
+	Deleted             bool        `json:"deleted"`
+	Sponsor             null.String `json:"sponsor"`
+	ClosedAt            time.Time   `json:"closed_at"`
+	LedgerSequence      uint32      `json:"ledger_sequence"`
+	LedgerKeyHash       string      `json:"ledger_key_hash"`
+	LedgerKeyHashBase64 string      `json:"ledger_key_hash_base_64"`
 }
 
 // TradeOutput is a representation of a trade that aligns with the BigQuery table history_trades
@@ -309,6 +401,30 @@ type TradeOutput struct {
 	RoundingSlippage             null.Int    `json:"rounding_slippage"`
 	SellerIsExact                null.Bool   `json:"seller_is_exact"`
 	SellingLiquidityPoolIDStrkey null.String `json:"selling_liquidity_pool_id_strkey"`
+	BuyingAccountAddressMuxed    null.String `json:"buying_account_address_muxed"`
+	Price                        string      `json:"price"`
+	BaseIsSeller                 bool        `json:"base_is_seller"`
+	BaseAccountAddress           string      `json:"base_account_address"`
+	BaseAssetCode                string      `json:"base_asset_code"`
+	BaseAssetIssuer              string      `json:"base_asset_issuer"`
+	BaseAssetType                string      `json:"base_asset_type"`
+	BaseAssetID                  int64       `json:"base_asset_id"`
+	BaseAmount                   float64     `json:"base_amount"`
+	BaseOfferID                  null.Int    `json:"base_offer_id"`
+	BaseLiquidityPoolID          null.String `json:"base_liquidity_pool_id"`
+	CounterAccountAddress        string      `json:"counter_account_address"`
+	CounterAssetCode             string      `json:"counter_asset_code"`
+	CounterAssetIssuer           string      `json:"counter_asset_issuer"`
+	CounterAssetType             string      `json:"counter_asset_type"`
+	CounterAssetID               int64       `json:"counter_asset_id"`
+	CounterAmount                float64     `json:"counter_amount"`
+	CounterOfferID               null.Int    `json:"counter_offer_id"`
+	CounterLiquidityPoolID       null.String `json:"counter_liquidity_pool_id"`
+	BasePriceN                   int64       `json:"base_price_n"`
+	BasePriceD                   int64       `json:"base_price_d"`
+	BasePrice                    string      `json:"base_price"`
+	SellingAssetContractID       null.String `json:"selling_asset_contract_id"`
+	BuyingAssetContractID        null.String `json:"buying_asset_contract_id"`
 }
 
 // DimAccount is a representation of an account that aligns with the BigQuery table dim_accounts
@@ -371,6 +487,12 @@ type EffectOutput struct {
 	EffectId       string                 `json:"id"`
 }
 
+// FlattenedEffectOutput is an effect whose parquet output stores the most common detail keys in typed columns.
+// Its JSON output is the same as the JSON output of the effect.
+type FlattenedEffectOutput struct {
+	EffectOutput
+}
+
 // EffectType is the numeric type for an effect
 type EffectType int
 
@@ -532,6 +654,7 @@ type ContractDataOutput struct {
 	KeyDecoded                interface{} `json:"key_decoded"`
 	Val                       interface{} `json:"val"`
 	ValDecoded                interface{} `json:"val_decoded"`
+	ValueTruncated            bool        `json:"value_truncated"`
 	ContractDataXDR           string      `json:"contract_data_xdr"`
 	LedgerKeyHashBase64       string      `json:"ledger_key_hash_base_64"`
 }
@@ -624,17 +747,69 @@ type ConfigSettingOutput struct {
 	Deleted                                bool                `json:"deleted"`
 	ClosedAt                               time.Time           `json:"closed_at"`
 	LedgerSequence                         uint32              `json:"ledger_sequence"`
+	LedgerKeyHash                          string              `json:"ledger_key_hash"`
+	LedgerKeyHashBase64                    string              `json:"ledger_key_hash_base_64"`
+}
+
+// ContractSpecOutput is a function declared in the spec of a contract code, with its inputs and outputs, that aligns
+// with the BigQuery table contract_specs
+type ContractSpecOutput struct {
+	ContractCodeHash string              `json:"contract_code_hash"`
+	FunctionName     string              `json:"function_name"`
+	Inputs           []ContractSpecInput `json:"inputs"`
+	Outputs          []string            `json:"outputs"`
+	Doc              string              `json:"doc"`
+	ClosedAt         time.Time           `json:"closed_at"`
+	LedgerSequence   uint32              `json:"ledger_sequence"`
+}
+
+// ContractSpecInput is an input of a contract function, with the name of its type
+type ContractSpecInput struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+	Doc  string `json:"doc"`
 }
 
 // TtlOutput is a representation of soroban ttl that aligns with the Bigquery table ttls
 type TtlOutput struct {
-	KeyHash            string    `json:"key_hash"` // key_hash is contract_code_hash or contract_id
+	KeyHash             string    `json:"key_hash"` // key_hash is contract_code_hash or contract_id
+	LiveUntilLedgerSeq  uint32    `json:"live_until_ledger_seq"`
+	LastModifiedLedger  uint32    `json:"last_modified_ledger"`
+	LedgerEntryChange   uint32    `json:"ledger_entry_change"`
+	Deleted             bool      `json:"deleted"`
+	ClosedAt            time.Time `json:"closed_at"`
+	LedgerSequence      uint32    `json:"ledger_sequence"`
+	LedgerKeyHash       string    `json:"ledger_key_hash"`
+	LedgerKeyHashBase64 string    `json:"ledger_key_hash_base_64"`
+}
+
+// TtlExpiryOutput is the number of ledgers left before the entry of a soroban ttl expires, as of the ledger the ttl was
+// exported at, that aligns with the BigQuery table ttl_expiry
+type TtlExpiryOutput struct {
+	KeyHash            string    `json:"key_hash"`
 	LiveUntilLedgerSeq uint32    `json:"live_until_ledger_seq"`
+	LedgersUntilExpiry int64     `json:"ledgers_until_expiry"`
+	Expired            bool      `json:"expired"`
 	LastModifiedLedger uint32    `json:"last_modified_ledger"`
-	LedgerEntryChange  uint32    `json:"ledger_entry_change"`
-	Deleted            bool      `json:"deleted"`
 	ClosedAt           time.Time `json:"closed_at"`
 	LedgerSequence     uint32    `json:"ledger_sequence"`
+	LedgerKeyHash      string    `json:"ledger_key_hash"`
+}
+
+// PoolParticipantOutput is the change of the shares that an account holds in a liquidity pool, that aligns with the
+// BigQuery table liquidity_pool_participants
+type PoolParticipantOutput struct {
+	AccountID             string    `json:"account_id"`
+	LiquidityPoolID       string    `json:"liquidity_pool_id"`
+	LiquidityPoolIDStrkey string    `json:"liquidity_pool_id_strkey"`
+	SharesDelta           float64   `json:"shares_delta"`
+	Shares                float64   `json:"shares"`
+	LastModifiedLedger    uint32    `json:"last_modified_ledger"`
+	LedgerEntryChange     uint32    `json:"ledger_entry_change"`
+	Deleted               bool      `json:"deleted"`
+	ClosedAt              time.Time `json:"closed_at"`
+	LedgerSequence        uint32    `json:"ledger_sequence"`
+	LedgerKeyHash         string    `json:"ledger_key_hash"`
 }
 
 // ContractEventOutput is a representation of soroban contract events and diagnostic events
@@ -648,12 +823,15 @@ type ContractEventOutput struct {
 	ContractId               string        `json:"contract_id"`
 	Type                     int32         `json:"type"`
 	TypeString               string        `json:"type_string"`
+	EventType                string        `json:"event_type"`
 	Topics                   []interface{} `json:"topics"`
 	TopicsDecoded            []interface{} `json:"topics_decoded"`
 	Data                     interface{}   `json:"data"`
 	DataDecoded              interface{}   `json:"data_decoded"`
+	ValueTruncated           bool          `json:"value_truncated"`
 	ContractEventXDR         string        `json:"contract_event_xdr"`
 	OperationID              null.Int      `json:"operation_id"`
+	EventID                  string        `json:"event_id"`
 }
 
 type TokenTransferOutput struct {
@@ -667,6 +845,7 @@ type TokenTransferOutput struct {
 	AssetType       string      `json:"asset_type"`
 	AssetCode       null.String `json:"asset_code"`
 	AssetIssuer     null.String `json:"asset_issuer"`
+	AssetID         null.Int    `json:"asset_id"`
 	Amount          float64     `json:"amount"`
 	AmountRaw       string      `json:"amount_raw"`
 	ContractID      string      `json:"contract_id"`
@@ -676,6 +855,24 @@ type TokenTransferOutput struct {
 	ToMuxedID       null.String `json:"to_muxed_id"`
 }
 
+// AssetSupplyOutput is the change in the circulating supply of an asset within a ledger that aligns with the BigQuery table asset_supply.
+// Amounts are in stroops, or the smallest unit of a token, and are strings because token amounts are 128 bit integers.
+type AssetSupplyOutput struct {
+	LedgerSequence    uint32      `json:"ledger_sequence"`
+	ClosedAt          time.Time   `json:"closed_at"`
+	Asset             string      `json:"asset"`
+	AssetType         string      `json:"asset_type"`
+	AssetCode         null.String `json:"asset_code"`
+	AssetIssuer       null.String `json:"asset_issuer"`
+	AssetID           null.Int    `json:"asset_id"`
+	ContractID        string      `json:"contract_id"`
+	Minted            string      `json:"minted"`
+	Burned            string      `json:"burned"`
+	ClawedBack        string      `json:"clawed_back"`
+	SupplyDelta       string      `json:"supply_delta"`       // minted less burned and clawed back
+	CirculatingSupply string      `json:"circulating_supply"` // running total since the start of the tracked supply
+}
+
 // RestoredKeyOutput is a representation of a restored key that aligns with the BigQuery table restored_key
 type RestoredKeyOutput struct {
 	LedgerKeyHash      string    `json:"ledger_key_hash"`
@@ -683,4 +880,169 @@ type RestoredKeyOutput struct {
 	LastModifiedLedger uint32    `json:"last_modified_ledger"`
 	ClosedAt           time.Time `json:"closed_at"`
 	LedgerSequence     uint32    `json:"ledger_sequence"`
+	LedgerKeyHashHex   string    `json:"ledger_key_hash_hex"`
+}
+
+// FootprintOutput is a ledger key declared in the footprint of a soroban transaction, decoded into its type and
+// identifying fields. Only the fields of the key's type are set.
+type FootprintOutput struct {
+	TransactionHash     string    `json:"transaction_hash"`
+	TransactionID       int64     `json:"transaction_id"`
+	Successful          bool      `json:"successful"`
+	Access              string    `json:"access"`
+	LedgerKeyHash       string    `json:"ledger_key_hash"`
+	LedgerKeyHashBase64 string    `json:"ledger_key_hash_base_64"`
+	LedgerEntryType     string    `json:"ledger_entry_type"`
+	AccountID           string    `json:"account_id"`
+	AssetType           string    `json:"asset_type"`
+	AssetCode           string    `json:"asset_code"`
+	AssetIssuer         string    `json:"asset_issuer"`
+	LiquidityPoolID     string    `json:"liquidity_pool_id"`
+	ContractId          string    `json:"contract_id"`
+	ContractKeyType     string    `json:"contract_key_type"`
+	ContractDurability  string    `json:"contract_durability"`
+	ContractCodeHash    string    `json:"contract_code_hash"`
+	ClosedAt            time.Time `json:"closed_at"`
+	LedgerSequence      uint32    `json:"ledger_sequence"`
+}
+
+// FootprintReadOutput is a ledger entry that a soroban transaction read without changing it, taken from the read-only
+// footprint of the transaction
+type FootprintReadOutput struct {
+	LedgerKeyHash       string    `json:"ledger_key_hash"`
+	LedgerKeyHashBase64 string    `json:"ledger_key_hash_base_64"`
+	LedgerEntryType     string    `json:"ledger_entry_type"`
+	ContractId          string    `json:"contract_id"`
+	TransactionHash     string    `json:"transaction_hash"`
+	Successful          bool      `json:"successful"`
+	ClosedAt            time.Time `json:"closed_at"`
+	LedgerSequence      uint32    `json:"ledger_sequence"`
+}
+
+// RestoreCandidateOutput is an archived ledger entry that a soroban transaction tried to use, taken from its footprint,
+// that aligns with the BigQuery table restore_candidates. LiveUntilLedgerSeq is null if the ttl of the entry is not known.
+type RestoreCandidateOutput struct {
+	LedgerKeyHash       string    `json:"ledger_key_hash"`
+	LedgerKeyHashBase64 string    `json:"ledger_key_hash_base_64"`
+	LedgerEntryType     string    `json:"ledger_entry_type"`
+	ContractId          string    `json:"contract_id"`
+	ContractKeyType     string    `json:"contract_key_type"`
+	ContractCodeHash    string    `json:"contract_code_hash"`
+	Reason              string    `json:"reason"`
+	LiveUntilLedgerSeq  null.Int  `json:"live_until_ledger_seq"`
+	TransactionHash     string    `json:"transaction_hash"`
+	TransactionID       int64     `json:"transaction_id"`
+	Successful          bool      `json:"successful"`
+	ClosedAt            time.Time `json:"closed_at"`
+	LedgerSequence      uint32    `json:"ledger_sequence"`
+}
+
+// LedgerUpgradeOutput is a representation of a ledger upgrade that aligns with the BigQuery table ledger_upgrades
+type LedgerUpgradeOutput struct {
+	LedgerSequence              uint32      `json:"ledger_sequence"`
+	ClosedAt                    time.Time   `json:"closed_at"`
+	UpgradeIndex                int32       `json:"upgrade_index"`
+	Type                        int32       `json:"type"`
+	TypeString                  string      `json:"type_string"`
+	NewLedgerVersion            null.Int    `json:"new_ledger_version"`
+	NewBaseFee                  null.Int    `json:"new_base_fee"`
+	NewMaxTxSetSize             null.Int    `json:"new_max_tx_set_size"`
+	NewBaseReserve              null.Int    `json:"new_base_reserve"`
+	NewFlags                    null.Int    `json:"new_flags"`
+	NewMaxSorobanTxSetSize      null.Int    `json:"new_max_soroban_tx_set_size"`
+	ConfigUpgradeSetContractID  null.String `json:"config_upgrade_set_contract_id"`
+	ConfigUpgradeSetContentHash null.String `json:"config_upgrade_set_content_hash"`
+	UpgradeXDR                  string      `json:"upgrade_xdr"`
+}
+
+// ConfigUpgradeOutput is a representation of a soroban config setting changed by a config upgrade that aligns with the BigQuery table config_upgrades
+type ConfigUpgradeOutput struct {
+	LedgerSequence              uint32               `json:"ledger_sequence"`
+	ClosedAt                    time.Time            `json:"closed_at"`
+	UpgradeIndex                int32                `json:"upgrade_index"`
+	ConfigUpgradeSetContractID  string               `json:"config_upgrade_set_contract_id"`
+	ConfigUpgradeSetContentHash string               `json:"config_upgrade_set_content_hash"`
+	ConfigSettingId             int32                `json:"config_setting_id"`
+	ConfigSettingIdString       string               `json:"config_setting_id_string"`
+	ChangedFields               []string             `json:"changed_fields"`
+	OldConfigSetting            *ConfigSettingOutput `json:"old_config_setting"`
+	NewConfigSetting            ConfigSettingOutput  `json:"new_config_setting"`
+}
+
+// ConfigSettingDiffOutput is a field of a soroban config setting changed by a ledger entry change, with its json encoded
+// values before and after the change, that aligns with the BigQuery table config_setting_diffs
+type ConfigSettingDiffOutput struct {
+	ConfigSettingId       int32       `json:"config_setting_id"`
+	ConfigSettingIdString string      `json:"config_setting_id_string"`
+	Field                 string      `json:"field"`
+	OldValue              null.String `json:"old_value"`
+	NewValue              null.String `json:"new_value"`
+	LastModifiedLedger    uint32      `json:"last_modified_ledger"`
+	LedgerEntryChange     uint32      `json:"ledger_entry_change"`
+	Deleted               bool        `json:"deleted"`
+	ClosedAt              time.Time   `json:"closed_at"`
+	LedgerSequence        uint32      `json:"ledger_sequence"`
+	LedgerKeyHash         string      `json:"ledger_key_hash"`
+}
+
+// InflationPayoutOutput is a representation of a single payout of an inflation operation that aligns with the BigQuery table inflation_payouts
+type InflationPayoutOutput struct {
+	LedgerSequence  uint32    `json:"ledger_sequence"`
+	ClosedAt        time.Time `json:"closed_at"`
+	TransactionHash string    `json:"transaction_hash"`
+	TransactionID   int64     `json:"transaction_id"`
+	OperationID     int64     `json:"operation_id"`
+	PayoutIndex     int32     `json:"payout_index"`
+	Destination     string    `json:"destination"`
+	Amount          float64   `json:"amount"`
+}
+
+// ClaimableBalanceClaimOutput is the lifecycle of a claimable balance, from its creation to its claim or clawback, that
+// aligns with the BigQuery table cb_claims
+type ClaimableBalanceClaimOutput struct {
+	BalanceID              string      `json:"balance_id"`
+	BalanceIDStrkey        string      `json:"balance_id_strkey"`
+	AssetCode              string      `json:"asset_code"`
+	AssetIssuer            string      `json:"asset_issuer"`
+	AssetType              string      `json:"asset_type"`
+	AssetID                int64       `json:"asset_id"`
+	AssetAmount            float64     `json:"asset_amount"`
+	CreatedBy              null.String `json:"created_by"`
+	CreatedTransactionHash null.String `json:"created_transaction_hash"`
+	CreatedLedgerSequence  uint32      `json:"created_ledger_sequence"`
+	CreatedAt              null.Time   `json:"created_at"`
+	ClaimType              string      `json:"claim_type"`
+	ClaimedBy              string      `json:"claimed_by"`
+	ElapsedLedgers         int64       `json:"elapsed_ledgers"`
+	ElapsedSeconds         null.Int    `json:"elapsed_seconds"`
+	TransactionHash        string      `json:"transaction_hash"`
+	TransactionID          int64       `json:"transaction_id"`
+	OperationID            int64       `json:"operation_id"`
+	ClosedAt               time.Time   `json:"closed_at"`
+	LedgerSequence         uint32      `json:"ledger_sequence"`
+}
+
+// PathPaymentHopOutput is a representation of an offer or liquidity pool crossed by a path payment that aligns with the BigQuery table path_payment_hops
+type PathPaymentHopOutput struct {
+	HistoryOperationID                int64       `json:"history_operation_id"`
+	LedgerClosedAt                    time.Time   `json:"ledger_closed_at"`
+	OperationType                     int32       `json:"operation_type"`
+	SourceAccount                     string      `json:"source_account"`
+	HopIndex                          int32       `json:"hop_index"`
+	ClaimOrder                        int32       `json:"claim_order"`
+	SourceAssetCode                   string      `json:"source_asset_code"`
+	SourceAssetIssuer                 string      `json:"source_asset_issuer"`
+	SourceAssetType                   string      `json:"source_asset_type"`
+	SourceAssetID                     int64       `json:"source_asset_id"`
+	SourceAmount                      float64     `json:"source_amount"`
+	DestinationAssetCode              string      `json:"destination_asset_code"`
+	DestinationAssetIssuer            string      `json:"destination_asset_issuer"`
+	DestinationAssetType              string      `json:"destination_asset_type"`
+	DestinationAssetID                int64       `json:"destination_asset_id"`
+	DestinationAmount                 float64     `json:"destination_amount"`
+	CounterpartyType                  string      `json:"counterparty_type"`
+	CounterpartyAccount               null.String `json:"counterparty_account"`
+	CounterpartyOfferID               null.Int    `json:"counterparty_offer_id"`
+	CounterpartyLiquidityPoolID       null.String `json:"counterparty_liquidity_pool_id"`
+	CounterpartyLiquidityPoolIDStrkey null.String `json:"counterparty_liquidity_pool_id_strkey"`
 }
@@ -12,6 +12,10 @@ type LedgerOutputParquet struct {
 	FailedTransactionCount          int32    `parquet:"name=failed_transaction_count, type=INT32"`
 	TxSetOperationCount             string   `parquet:"name=tx_set_operation_count, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
 	ClosedAt                        int64    `parquet:"name=closed_at, type=INT64, convertedtype=TIMESTAMP_MILLIS"`
+	TxSetHash                       string   `parquet:"name=tx_set_hash, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	ClassicPhaseTransactionCount    int32    `parquet:"name=classic_phase_transaction_count, type=INT32"`
+	SorobanPhaseTransactionCount    int32    `parquet:"name=soroban_phase_transaction_count, type=INT32"`
+	ApplyOrderDiffers               bool     `parquet:"name=apply_order_differs, type=BOOLEAN"`
 	TotalCoins                      int64    `parquet:"name=total_coins, type=INT64"`
 	FeePool                         int64    `parquet:"name=fee_pool, type=INT64"`
 	BaseFee                         int64    `parquet:"name=base_fee, type=INT64, convertedtype=UINT_64"`
@@ -53,6 +57,9 @@ type TransactionOutputParquet struct {
 	InnerTransactionHash                 string   `parquet:"name=inner_transaction_hash, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
 	NewMaxFee                            int64    `parquet:"name=new_max_fee, type=INT64, convertedtype=UINT_64"`
 	LedgerBounds                         string   `parquet:"name=ledger_bounds, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	ValidAfter                           bool     `parquet:"name=valid_after, type=BOOLEAN"`
+	ValidBefore                          bool     `parquet:"name=valid_before, type=BOOLEAN"`
+	WithinLedgerBounds                   bool     `parquet:"name=within_ledger_bounds, type=BOOLEAN"`
 	MinAccountSequence                   int64    `parquet:"name=min_account_sequence, type=INT64"`
 	MinAccountSequenceAge                int64    `parquet:"name=min_account_sequence_age, type=INT64"`
 	MinAccountSequenceLedgerGap          int64    `parquet:"name=min_account_sequence_ledger_gap, type=INT64"`
@@ -68,9 +75,14 @@ type TransactionOutputParquet struct {
 	InclusionFeeBid                      int64    `parquet:"name=inclusion_fee_bid, type=INT64"`
 	InclusionFeeCharged                  int64    `parquet:"name=inclusion_fee_charged, type=INT64"`
 	ResourceFeeRefund                    int64    `parquet:"name=resource_fee_refund, type=INT64"`
+	ResourceFeeCharged                   int64    `parquet:"name=resource_fee_charged, type=INT64"`
 	TotalNonRefundableResourceFeeCharged int64    `parquet:"name=non_refundable_resource_fee_charged, type=INT64"`
 	TotalRefundableResourceFeeCharged    int64    `parquet:"name=refundable_resource_fee_charged, type=INT64"`
 	RentFeeCharged                       int64    `parquet:"name=rent_fee_charged, type=INT64"`
+	ContractEventsCount                  int32    `parquet:"name=contract_events_count, type=INT32"`
+	DiagnosticEventsCount                int32    `parquet:"name=diagnostic_events_count, type=INT32"`
+	IsSoroban                            bool     `parquet:"name=is_soroban, type=BOOLEAN"`
+	SorobanResourcesUsed                 bool     `parquet:"name=soroban_resources_used, type=BOOLEAN"`
 }
 
 // AccountOutputParquet is a representation of an account that aligns with the BigQuery table accounts
@@ -93,24 +105,30 @@ type AccountOutputParquet struct {
 	Sponsor              string  `parquet:"name=sponsor, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
 	NumSponsored         int64   `parquet:"name=num_sponsored, type=INT64, convertedtype=UINT_64"`
 	NumSponsoring        int64   `parquet:"name=num_sponsoring, type=INT64, convertedtype=UINT_64"`
+	MinimumBalance       float64 `parquet:"name=minimum_balance, type=DOUBLE"`
+	AvailableBalance     float64 `parquet:"name=available_balance, type=DOUBLE"`
 	LastModifiedLedger   int64   `parquet:"name=last_modified_ledger, type=INT64, convertedtype=UINT_64"`
 	LedgerEntryChange    int64   `parquet:"name=ledger_entry_change, type=INT64, convertedtype=UINT_64"`
 	Deleted              bool    `parquet:"name=deleted, type=BOOLEAN"`
 	ClosedAt             int64   `parquet:"name=closed_at, type=INT64, convertedtype=TIMESTAMP_MILLIS"`
 	LedgerSequence       int64   `parquet:"name=ledger_sequence, type=INT64, convertedtype=UINT_64"`
+	LedgerKeyHash        string  `parquet:"name=ledger_key_hash, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	LedgerKeyHashBase64  string  `parquet:"name=ledger_key_hash_base_64, type=BYTE_ARRAY, convertedtype=UTF8"`
 }
 
 // AccountSignerOutputParquet is a representation of an account signer that aligns with the BigQuery table account_signers
 type AccountSignerOutputParquet struct {
-	AccountID          string `parquet:"name=account_id, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
-	Signer             string `parquet:"name=signer, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
-	Weight             int32  `parquet:"name=weight, type=INT32"`
-	Sponsor            string `parquet:"name=sponsor, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
-	LastModifiedLedger int64  `parquet:"name=last_modified_ledger, type=INT64, convertedtype=INT64, convertedtype=UINT_64"`
-	LedgerEntryChange  int64  `parquet:"name=ledger_entry_change, type=INT64, convertedtype=INT64, convertedtype=UINT_64"`
-	Deleted            bool   `parquet:"name=deleted, type=BOOLEAN"`
-	ClosedAt           int64  `parquet:"name=closed_at, type=INT64, convertedtype=TIMESTAMP_MILLIS"`
-	LedgerSequence     int64  `parquet:"name=ledger_sequence, type=INT64, convertedtype=INT64, convertedtype=UINT_64"`
+	AccountID           string `parquet:"name=account_id, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	Signer              string `parquet:"name=signer, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	Weight              int32  `parquet:"name=weight, type=INT32"`
+	Sponsor             string `parquet:"name=sponsor, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	LastModifiedLedger  int64  `parquet:"name=last_modified_ledger, type=INT64, convertedtype=INT64, convertedtype=UINT_64"`
+	LedgerEntryChange   int64  `parquet:"name=ledger_entry_change, type=INT64, convertedtype=INT64, convertedtype=UINT_64"`
+	Deleted             bool   `parquet:"name=deleted, type=BOOLEAN"`
+	ClosedAt            int64  `parquet:"name=closed_at, type=INT64, convertedtype=TIMESTAMP_MILLIS"`
+	LedgerSequence      int64  `parquet:"name=ledger_sequence, type=INT64, convertedtype=INT64, convertedtype=UINT_64"`
+	LedgerKeyHash       string `parquet:"name=ledger_key_hash, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	LedgerKeyHashBase64 string `parquet:"name=ledger_key_hash_base_64, type=BYTE_ARRAY, convertedtype=UTF8"`
 }
 
 // OperationOutputParquet is a representation of an operation that aligns with the BigQuery table history_operations
@@ -126,6 +144,61 @@ type OperationOutputParquet struct {
 	OperationResultCode string `parquet:"name=operation_result_code, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
 	OperationTraceCode  string `parquet:"name=operation_trace_code, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
 	LedgerSequence      int64  `parquet:"name=ledger_sequence, type=INT64, convertedtype=INT64, convertedtype=UINT_64"`
+	ParentOperationID   int64  `parquet:"name=parent_operation_id, type=INT64"`
+}
+
+// FlattenedOperationOutputParquet is a representation of an operation with the well-known detail keys stored in typed columns.
+// Details that are not set for the operation type are left as zero values; the full details are still available in the details column.
+type FlattenedOperationOutputParquet struct {
+	SourceAccount       string  `parquet:"name=source_account, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	SourceAccountMuxed  string  `parquet:"name=source_account_muxed, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	Type                int32   `parquet:"name=type, type=INT32"`
+	TypeString          string  `parquet:"name=type_string, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	OperationDetails    string  `parquet:"name=details, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	TransactionID       int64   `parquet:"name=transaction_id, type=INT64"`
+	OperationID         int64   `parquet:"name=id, type=INT64"`
+	ClosedAt            int64   `parquet:"name=closed_at, type=INT64, convertedtype=TIMESTAMP_MILLIS"`
+	OperationResultCode string  `parquet:"name=operation_result_code, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	OperationTraceCode  string  `parquet:"name=operation_trace_code, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	LedgerSequence      int64   `parquet:"name=ledger_sequence, type=INT64, convertedtype=INT64, convertedtype=UINT_64"`
+	ParentOperationID   int64   `parquet:"name=parent_operation_id, type=INT64"`
+	Amount              float64 `parquet:"name=amount, type=DOUBLE"`
+	AssetCode           string  `parquet:"name=asset_code, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	AssetIssuer         string  `parquet:"name=asset_issuer, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	AssetType           string  `parquet:"name=asset_type, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	AssetID             int64   `parquet:"name=asset_id, type=INT64"`
+	SourceAmount        float64 `parquet:"name=source_amount, type=DOUBLE"`
+	SourceMax           float64 `parquet:"name=source_max, type=DOUBLE"`
+	DestinationMin      float64 `parquet:"name=destination_min, type=DOUBLE"`
+	SourceAssetCode     string  `parquet:"name=source_asset_code, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	SourceAssetIssuer   string  `parquet:"name=source_asset_issuer, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	SourceAssetType     string  `parquet:"name=source_asset_type, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	SourceAssetID       int64   `parquet:"name=source_asset_id, type=INT64"`
+	From                string  `parquet:"name=from, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	To                  string  `parquet:"name=to, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	Account             string  `parquet:"name=account, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	Funder              string  `parquet:"name=funder, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	Into                string  `parquet:"name=into, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	StartingBalance     float64 `parquet:"name=starting_balance, type=DOUBLE"`
+	SellingAssetCode    string  `parquet:"name=selling_asset_code, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	SellingAssetIssuer  string  `parquet:"name=selling_asset_issuer, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	SellingAssetType    string  `parquet:"name=selling_asset_type, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	SellingAssetID      int64   `parquet:"name=selling_asset_id, type=INT64"`
+	BuyingAssetCode     string  `parquet:"name=buying_asset_code, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	BuyingAssetIssuer   string  `parquet:"name=buying_asset_issuer, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	BuyingAssetType     string  `parquet:"name=buying_asset_type, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	BuyingAssetID       int64   `parquet:"name=buying_asset_id, type=INT64"`
+	Price               float64 `parquet:"name=price, type=DOUBLE"`
+	OfferID             int64   `parquet:"name=offer_id, type=INT64"`
+	Trustor             string  `parquet:"name=trustor, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	Trustee             string  `parquet:"name=trustee, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	Limit               float64 `parquet:"name=limit, type=DOUBLE"`
+	BumpTo              int64   `parquet:"name=bump_to, type=INT64"`
+	BalanceID           string  `parquet:"name=balance_id, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	LiquidityPoolID     string  `parquet:"name=liquidity_pool_id, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	Shares              float64 `parquet:"name=shares, type=DOUBLE"`
+	Function            string  `parquet:"name=function, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	ContractID          string  `parquet:"name=contract_id, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
 }
 
 //// Skipping ClaimableBalanceOutputParquet because it is not needed in the current scope of work
@@ -136,26 +209,28 @@ type OperationOutputParquet struct {
 
 // PoolOutputParquet is a representation of a liquidity pool that aligns with the Bigquery table liquidity_pools
 type PoolOutputParquet struct {
-	PoolID             string  `parquet:"name=liquidity_pool_id, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
-	PoolType           string  `parquet:"name=type, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
-	PoolFee            int64   `parquet:"name=fee, type=INT64, convertedtype=UINT_64"`
-	TrustlineCount     int64   `parquet:"name=trustline_count, type=INT64, convertedtype=UINT_64"`
-	PoolShareCount     float64 `parquet:"name=pool_share_count, type=DOUBLE"`
-	AssetAType         string  `parquet:"name=asset_a_type, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
-	AssetACode         string  `parquet:"name=asset_a_code, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
-	AssetAIssuer       string  `parquet:"name=asset_a_issuer, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
-	AssetAReserve      float64 `parquet:"name=asset_a_amount, type=DOUBLE"`
-	AssetAID           int64   `parquet:"name=asset_a_id, type=INT64"`
-	AssetBType         string  `parquet:"name=asset_b_type, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
-	AssetBCode         string  `parquet:"name=asset_b_code, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
-	AssetBIssuer       string  `parquet:"name=asset_b_issuer, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
-	AssetBReserve      float64 `parquet:"name=asset_b_amount, type=DOUBLE"`
-	AssetBID           int64   `parquet:"name=asset_b_id, type=INT64"`
-	LastModifiedLedger int64   `parquet:"name=last_modified_ledger, type=INT64, convertedtype=UINT_64"`
-	LedgerEntryChange  int64   `parquet:"name=ledger_entry_change, type=INT64, convertedtype=UINT_64"`
-	Deleted            bool    `parquet:"name=deleted, type=BOOLEAN"`
-	ClosedAt           int64   `parquet:"name=closed_at, type=INT64, convertedtype=TIMESTAMP_MILLIS"`
-	LedgerSequence     int64   `parquet:"name=ledger_sequence, type=INT64, convertedtype=UINT_64"`
+	PoolID              string  `parquet:"name=liquidity_pool_id, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	PoolType            string  `parquet:"name=type, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	PoolFee             int64   `parquet:"name=fee, type=INT64, convertedtype=UINT_64"`
+	TrustlineCount      int64   `parquet:"name=trustline_count, type=INT64, convertedtype=UINT_64"`
+	PoolShareCount      float64 `parquet:"name=pool_share_count, type=DOUBLE"`
+	AssetAType          string  `parquet:"name=asset_a_type, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	AssetACode          string  `parquet:"name=asset_a_code, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	AssetAIssuer        string  `parquet:"name=asset_a_issuer, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	AssetAReserve       float64 `parquet:"name=asset_a_amount, type=DOUBLE"`
+	AssetAID            int64   `parquet:"name=asset_a_id, type=INT64"`
+	AssetBType          string  `parquet:"name=asset_b_type, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	AssetBCode          string  `parquet:"name=asset_b_code, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	AssetBIssuer        string  `parquet:"name=asset_b_issuer, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	AssetBReserve       float64 `parquet:"name=asset_b_amount, type=DOUBLE"`
+	AssetBID            int64   `parquet:"name=asset_b_id, type=INT64"`
+	LastModifiedLedger  int64   `parquet:"name=last_modified_ledger, type=INT64, convertedtype=UINT_64"`
+	LedgerEntryChange   int64   `parquet:"name=ledger_entry_change, type=INT64, convertedtype=UINT_64"`
+	Deleted             bool    `parquet:"name=deleted, type=BOOLEAN"`
+	ClosedAt            int64   `parquet:"name=closed_at, type=INT64, convertedtype=TIMESTAMP_MILLIS"`
+	LedgerSequence      int64   `parquet:"name=ledger_sequence, type=INT64, convertedtype=UINT_64"`
+	LedgerKeyHash       string  `parquet:"name=ledger_key_hash, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	LedgerKeyHashBase64 string  `parquet:"name=ledger_key_hash_base_64, type=BYTE_ARRAY, convertedtype=UTF8"`
 }
 
 // AssetOutputParquet is a representation of an asset that aligns with the BigQuery table history_assets
@@ -168,79 +243,127 @@ type AssetOutputParquet struct {
 	LedgerSequence int64  `parquet:"name=ledger_sequence, type=INT64, convertedtype=UINT_64"`
 }
 
+// AssetMetadataOutputParquet is the stellar.toml metadata of an asset that aligns with the Bigquery table asset_metadata
+type AssetMetadataOutputParquet struct {
+	AssetCode       string `parquet:"name=asset_code, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	AssetIssuer     string `parquet:"name=asset_issuer, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	AssetType       string `parquet:"name=asset_type, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	AssetID         int64  `parquet:"name=asset_id, type=INT64"`
+	HomeDomain      string `parquet:"name=home_domain, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	OrgName         string `parquet:"name=org_name, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	Name            string `parquet:"name=name, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Description     string `parquet:"name=description, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Image           string `parquet:"name=image, type=BYTE_ARRAY, convertedtype=UTF8"`
+	DisplayDecimals int64  `parquet:"name=display_decimals, type=INT64"`
+	Status          string `parquet:"name=status, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	IsAssetAnchored bool   `parquet:"name=is_asset_anchored, type=BOOLEAN"`
+	AnchorAssetType string `parquet:"name=anchor_asset_type, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	AnchorAsset     string `parquet:"name=anchor_asset, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	MetadataError   string `parquet:"name=metadata_error, type=BYTE_ARRAY, convertedtype=UTF8"`
+	FetchedAt       int64  `parquet:"name=fetched_at, type=INT64, convertedtype=TIMESTAMP_MILLIS"`
+}
+
 // TrustlineOutputParquet is a representation of a trustline that aligns with the BigQuery table trust_lines
 type TrustlineOutputParquet struct {
-	LedgerKey          string  `parquet:"name=ledger_key, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
-	AccountID          string  `parquet:"name=account_id, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
-	AssetCode          string  `parquet:"name=asset_code, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
-	AssetIssuer        string  `parquet:"name=asset_issuer, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
-	AssetType          string  `parquet:"name=asset_type, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
-	AssetID            int64   `parquet:"name=asset_id, type=INT64"`
-	Balance            float64 `parquet:"name=balance, type=DOUBLE"`
-	TrustlineLimit     int64   `parquet:"name=trust_line_limit, type=INT64"`
-	LiquidityPoolID    string  `parquet:"name=liquidity_pool_id, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
-	BuyingLiabilities  float64 `parquet:"name=buying_liabilities, type=DOUBLE"`
-	SellingLiabilities float64 `parquet:"name=selling_liabilities, type=DOUBLE"`
-	Flags              int64   `parquet:"name=flags, type=INT64, convertedtype=UINT_64"`
-	LastModifiedLedger int64   `parquet:"name=last_modified_ledger, type=INT64, convertedtype=UINT_64"`
-	LedgerEntryChange  int64   `parquet:"name=ledger_entry_change, type=INT64, convertedtype=UINT_64"`
-	Sponsor            string  `parquet:"name=sponsor, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
-	Deleted            bool    `parquet:"name=deleted, type=BOOLEAN"`
-	ClosedAt           int64   `parquet:"name=closed_at, type=INT64, convertedtype=TIMESTAMP_MILLIS"`
-	LedgerSequence     int64   `parquet:"name=ledger_sequence, type=INT64, convertedtype=UINT_64"`
+	LedgerKey           string  `parquet:"name=ledger_key, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	AccountID           string  `parquet:"name=account_id, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	AssetCode           string  `parquet:"name=asset_code, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	AssetIssuer         string  `parquet:"name=asset_issuer, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	AssetType           string  `parquet:"name=asset_type, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	AssetID             int64   `parquet:"name=asset_id, type=INT64"`
+	Balance             float64 `parquet:"name=balance, type=DOUBLE"`
+	TrustlineLimit      int64   `parquet:"name=trust_line_limit, type=INT64"`
+	LiquidityPoolID     string  `parquet:"name=liquidity_pool_id, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	BuyingLiabilities   float64 `parquet:"name=buying_liabilities, type=DOUBLE"`
+	SellingLiabilities  float64 `parquet:"name=selling_liabilities, type=DOUBLE"`
+	Flags               int64   `parquet:"name=flags, type=INT64, convertedtype=UINT_64"`
+	LastModifiedLedger  int64   `parquet:"name=last_modified_ledger, type=INT64, convertedtype=UINT_64"`
+	LedgerEntryChange   int64   `parquet:"name=ledger_entry_change, type=INT64, convertedtype=UINT_64"`
+	Sponsor             string  `parquet:"name=sponsor, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	Deleted             bool    `parquet:"name=deleted, type=BOOLEAN"`
+	ClosedAt            int64   `parquet:"name=closed_at, type=INT64, convertedtype=TIMESTAMP_MILLIS"`
+	LedgerSequence      int64   `parquet:"name=ledger_sequence, type=INT64, convertedtype=UINT_64"`
+	LedgerKeyHash       string  `parquet:"name=ledger_key_hash, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	LedgerKeyHashBase64 string  `parquet:"name=ledger_key_hash_base_64, type=BYTE_ARRAY, convertedtype=UTF8"`
 }
 
 // OfferOutputParquet is a representation of an offer that aligns with the BigQuery table offers
 type OfferOutputParquet struct {
-	SellerID           string  `parquet:"name=seller_id, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
-	OfferID            int64   `parquet:"name=offer_id, type=INT64"`
-	SellingAssetType   string  `parquet:"name=selling_asset_type, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
-	SellingAssetCode   string  `parquet:"name=selling_asset_code, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
-	SellingAssetIssuer string  `parquet:"name=selling_asset_issuer, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
-	SellingAssetID     int64   `parquet:"name=selling_asset_id, type=INT64"`
-	BuyingAssetType    string  `parquet:"name=buying_asset_type, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
-	BuyingAssetCode    string  `parquet:"name=buying_asset_code, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
-	BuyingAssetIssuer  string  `parquet:"name=buying_asset_issuer, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
-	BuyingAssetID      int64   `parquet:"name=buying_asset_id, type=INT64"`
-	Amount             float64 `parquet:"name=amount, type=DOUBLE"`
-	PriceN             int32   `parquet:"name=pricen, type=INT32"`
-	PriceD             int32   `parquet:"name=priced, type=INT32"`
-	Price              float64 `parquet:"name=price, type=DOUBLE"`
-	Flags              int64   `parquet:"name=flags, type=INT64, convertedtype=UINT_64"`
-	LastModifiedLedger int64   `parquet:"name=last_modified_ledger, type=INT64, convertedtype=UINT_64"`
-	LedgerEntryChange  int64   `parquet:"name=ledger_entry_change, type=INT64, convertedtype=UINT_64"`
-	Deleted            bool    `parquet:"name=deleted, type=BOOLEAN"`
-	Sponsor            string  `parquet:"name=sponsor, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
-	ClosedAt           int64   `parquet:"name=closed_at, type=INT64, convertedtype=TIMESTAMP_MILLIS"`
-	LedgerSequence     int64   `parquet:"name=ledger_sequence, type=INT64, convertedtype=UINT_64"`
+	SellerID            string  `parquet:"name=seller_id, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	OfferID             int64   `parquet:"name=offer_id, type=INT64"`
+	SellingAssetType    string  `parquet:"name=selling_asset_type, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	SellingAssetCode    string  `parquet:"name=selling_asset_code, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	SellingAssetIssuer  string  `parquet:"name=selling_asset_issuer, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	SellingAssetID      int64   `parquet:"name=selling_asset_id, type=INT64"`
+	BuyingAssetType     string  `parquet:"name=buying_asset_type, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	BuyingAssetCode     string  `parquet:"name=buying_asset_code, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	BuyingAssetIssuer   string  `parquet:"name=buying_asset_issuer, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	BuyingAssetID       int64   `parquet:"name=buying_asset_id, type=INT64"`
+	Amount              float64 `parquet:"name=amount, type=DOUBLE"`
+	PriceN              int32   `parquet:"name=pricen, type=INT32"`
+	PriceD              int32   `parquet:"name=priced, type=INT32"`
+	Price               float64 `parquet:"name=price, type=DOUBLE"`
+	Flags               int64   `parquet:"name=flags, type=INT64, convertedtype=UINT_64"`
+	LastModifiedLedger  int64   `parquet:"name=last_modified_ledger, type=INT64, convertedtype=UINT_64"`
+	LedgerEntryChange   int64   `parquet:"name=ledger_entry_change, type=INT64, convertedtype=UINT_64"`
+	Deleted             bool    `parquet:"name=deleted, type=BOOLEAN"`
+	Sponsor             string  `parquet:"name=sponsor, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	ClosedAt            int64   `parquet:"name=closed_at, type=INT64, convertedtype=TIMESTAMP_MILLIS"`
+	LedgerSequence      int64   `parquet:"name=ledger_sequence, type=INT64, convertedtype=UINT_64"`
+	LedgerKeyHash       string  `parquet:"name=ledger_key_hash, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	LedgerKeyHashBase64 string  `parquet:"name=ledger_key_hash_base_64, type=BYTE_ARRAY, convertedtype=UTF8"`
 }
 
 // TradeOutputParquet is a representation of a trade that aligns with the BigQuery table history_trades
 type TradeOutputParquet struct {
-	Order                  int32   `parquet:"name=order, type=INT32"`
-	LedgerClosedAt         int64   `parquet:"name=ledger_closed_at, type=INT64, convertedtype=TIMESTAMP_MILLIS"`
-	SellingAccountAddress  string  `parquet:"name=selling_account_address, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
-	SellingAssetCode       string  `parquet:"name=selling_asset_code, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
-	SellingAssetIssuer     string  `parquet:"name=selling_asset_issuer, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
-	SellingAssetType       string  `parquet:"name=selling_asset_type, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
-	SellingAssetID         int64   `parquet:"name=selling_asset_id, type=INT64"`
-	SellingAmount          float64 `parquet:"name=selling_amount, type=DOUBLE"`
-	BuyingAccountAddress   string  `parquet:"name=buying_account_address, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
-	BuyingAssetCode        string  `parquet:"name=buying_asset_code, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
-	BuyingAssetIssuer      string  `parquet:"name=buying_asset_issuer, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
-	BuyingAssetType        string  `parquet:"name=buying_asset_type, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
-	BuyingAssetID          int64   `parquet:"name=buying_asset_id, type=INT64"`
-	BuyingAmount           float64 `parquet:"name=buying_amount, type=DOUBLE"`
-	PriceN                 int64   `parquet:"name=price_n, type=INT64"`
-	PriceD                 int64   `parquet:"name=price_d, type=INT64"`
-	SellingOfferID         int64   `parquet:"name=selling_offer_id, type=INT64"`
-	BuyingOfferID          int64   `parquet:"name=buying_offer_id, type=INT64"`
-	SellingLiquidityPoolID string  `parquet:"name=selling_liquidity_pool_id, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
-	LiquidityPoolFee       int64   `parquet:"name=liquidity_pool_fee, type=INT64"`
-	HistoryOperationID     int64   `parquet:"name=history_operation_id, type=INT64"`
-	TradeType              int32   `parquet:"name=trade_type, type=INT32"`
-	RoundingSlippage       int64   `parquet:"name=rounding_slippage, type=INT64"`
-	SellerIsExact          bool    `parquet:"name=seller_is_exact, type=BOOLEAN"`
+	Order                     int32   `parquet:"name=order, type=INT32"`
+	LedgerClosedAt            int64   `parquet:"name=ledger_closed_at, type=INT64, convertedtype=TIMESTAMP_MILLIS"`
+	SellingAccountAddress     string  `parquet:"name=selling_account_address, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	SellingAssetCode          string  `parquet:"name=selling_asset_code, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	SellingAssetIssuer        string  `parquet:"name=selling_asset_issuer, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	SellingAssetType          string  `parquet:"name=selling_asset_type, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	SellingAssetID            int64   `parquet:"name=selling_asset_id, type=INT64"`
+	SellingAmount             float64 `parquet:"name=selling_amount, type=DOUBLE"`
+	BuyingAccountAddress      string  `parquet:"name=buying_account_address, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	BuyingAssetCode           string  `parquet:"name=buying_asset_code, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	BuyingAssetIssuer         string  `parquet:"name=buying_asset_issuer, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	BuyingAssetType           string  `parquet:"name=buying_asset_type, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	BuyingAssetID             int64   `parquet:"name=buying_asset_id, type=INT64"`
+	BuyingAmount              float64 `parquet:"name=buying_amount, type=DOUBLE"`
+	PriceN                    int64   `parquet:"name=price_n, type=INT64"`
+	PriceD                    int64   `parquet:"name=price_d, type=INT64"`
+	SellingOfferID            int64   `parquet:"name=selling_offer_id, type=INT64"`
+	BuyingOfferID             int64   `parquet:"name=buying_offer_id, type=INT64"`
+	SellingLiquidityPoolID    string  `parquet:"name=selling_liquidity_pool_id, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	LiquidityPoolFee          int64   `parquet:"name=liquidity_pool_fee, type=INT64"`
+	HistoryOperationID        int64   `parquet:"name=history_operation_id, type=INT64"`
+	TradeType                 int32   `parquet:"name=trade_type, type=INT32"`
+	RoundingSlippage          int64   `parquet:"name=rounding_slippage, type=INT64"`
+	SellerIsExact             bool    `parquet:"name=seller_is_exact, type=BOOLEAN"`
+	BuyingAccountAddressMuxed string  `parquet:"name=buying_account_address_muxed, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	Price                     string  `parquet:"name=price, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	BaseIsSeller              bool    `parquet:"name=base_is_seller, type=BOOLEAN"`
+	BaseAccountAddress        string  `parquet:"name=base_account_address, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	BaseAssetCode             string  `parquet:"name=base_asset_code, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	BaseAssetIssuer           string  `parquet:"name=base_asset_issuer, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	BaseAssetType             string  `parquet:"name=base_asset_type, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	BaseAssetID               int64   `parquet:"name=base_asset_id, type=INT64"`
+	BaseAmount                float64 `parquet:"name=base_amount, type=DOUBLE"`
+	BaseOfferID               int64   `parquet:"name=base_offer_id, type=INT64"`
+	BaseLiquidityPoolID       string  `parquet:"name=base_liquidity_pool_id, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	CounterAccountAddress     string  `parquet:"name=counter_account_address, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	CounterAssetCode          string  `parquet:"name=counter_asset_code, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	CounterAssetIssuer        string  `parquet:"name=counter_asset_issuer, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	CounterAssetType          string  `parquet:"name=counter_asset_type, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	CounterAssetID            int64   `parquet:"name=counter_asset_id, type=INT64"`
+	CounterAmount             float64 `parquet:"name=counter_amount, type=DOUBLE"`
+	CounterOfferID            int64   `parquet:"name=counter_offer_id, type=INT64"`
+	CounterLiquidityPoolID    string  `parquet:"name=counter_liquidity_pool_id, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	BasePriceN                int64   `parquet:"name=base_price_n, type=INT64"`
+	BasePriceD                int64   `parquet:"name=base_price_d, type=INT64"`
+	BasePrice                 string  `parquet:"name=base_price, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	SellingAssetContractID    string  `parquet:"name=selling_asset_contract_id, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	BuyingAssetContractID     string  `parquet:"name=buying_asset_contract_id, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
 }
 
 // EffectOutputParquet is a representation of an operation that aligns with the BigQuery table history_effects
@@ -257,6 +380,43 @@ type EffectOutputParquet struct {
 	EffectId       string `parquet:"name=id, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
 }
 
+// FlattenedEffectOutputParquet is a representation of an effect with the most common detail keys stored in typed columns.
+// Details that are not set for the effect type are left as zero values; the full details are still available in the details column.
+type FlattenedEffectOutputParquet struct {
+	Address           string  `parquet:"name=address, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	AddressMuxed      string  `parquet:"name=address_muxed, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	OperationID       int64   `parquet:"name=operation_id, type=INT64"`
+	Details           string  `parquet:"name=details, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	Type              int32   `parquet:"name=type, type=INT32"`
+	TypeString        string  `parquet:"name=type_string, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	LedgerClosed      int64   `parquet:"name=closed_at, type=INT64, convertedtype=TIMESTAMP_MILLIS"`
+	LedgerSequence    int64   `parquet:"name=ledger_sequence, type=INT64, convertedtype=UINT_64"`
+	EffectIndex       int64   `parquet:"name=index, type=INT64, convertedtype=UINT_64"`
+	EffectId          string  `parquet:"name=id, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	Amount            float64 `parquet:"name=amount, type=DOUBLE"`
+	AssetCode         string  `parquet:"name=asset_code, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	AssetIssuer       string  `parquet:"name=asset_issuer, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	AssetType         string  `parquet:"name=asset_type, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	Trustor           string  `parquet:"name=trustor, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	Sponsor           string  `parquet:"name=sponsor, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	FormerSponsor     string  `parquet:"name=former_sponsor, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	NewSponsor        string  `parquet:"name=new_sponsor, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	OfferID           int64   `parquet:"name=offer_id, type=INT64"`
+	Seller            string  `parquet:"name=seller, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	BoughtAmount      float64 `parquet:"name=bought_amount, type=DOUBLE"`
+	BoughtAssetCode   string  `parquet:"name=bought_asset_code, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	BoughtAssetIssuer string  `parquet:"name=bought_asset_issuer, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	BoughtAssetType   string  `parquet:"name=bought_asset_type, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	SoldAmount        float64 `parquet:"name=sold_amount, type=DOUBLE"`
+	SoldAssetCode     string  `parquet:"name=sold_asset_code, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	SoldAssetIssuer   string  `parquet:"name=sold_asset_issuer, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	SoldAssetType     string  `parquet:"name=sold_asset_type, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	StartingBalance   float64 `parquet:"name=starting_balance, type=DOUBLE"`
+	NewSeq            int64   `parquet:"name=new_seq, type=INT64"`
+	BalanceID         string  `parquet:"name=balance_id, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	Limit             float64 `parquet:"name=limit, type=DOUBLE"`
+}
+
 // ContractDataOutputParquet is a representation of contract data that aligns with the Bigquery table soroban_contract_data
 type ContractDataOutputParquet struct {
 	ContractId                string      `parquet:"name=contract_id, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
@@ -277,29 +437,32 @@ type ContractDataOutputParquet struct {
 	KeyDecoded                interface{} `parquet:"name=key_decoded, type=MAP, convertedtype=MAP, keytype=BYTE_ARRAY, keyconvertedtype=UTF8, valuetype=STRING"`
 	Val                       interface{} `parquet:"name=val, type=MAP, convertedtype=MAP, keytype=BYTE_ARRAY, keyconvertedtype=UTF8, valuetype=STRING"`
 	ValDecoded                interface{} `parquet:"name=val_decoded, type=MAP, convertedtype=MAP, keytype=BYTE_ARRAY, keyconvertedtype=UTF8, valuetype=STRING"`
+	ValueTruncated            bool        `parquet:"name=value_truncated, type=BOOLEAN"`
 	ContractDataXDR           string      `parquet:"name=contract_data_xdr, type=BYTE_ARRAY, convertedtype=UTF8"`
+	LedgerKeyHashBase64       string      `parquet:"name=ledger_key_hash_base_64, type=BYTE_ARRAY, convertedtype=UTF8"`
 }
 
 // ContractCodeOutputParquet is a representation of contract code that aligns with the Bigquery table soroban_contract_code
 type ContractCodeOutputParquet struct {
-	ContractCodeHash   string `parquet:"name=contract_code_hash, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
-	ContractCodeExtV   int32  `parquet:"name=contract_code_ext_v, type=INT32"`
-	LastModifiedLedger int64  `parquet:"name=last_modified_ledger, type=INT64, convertedtype=UINT_64"`
-	LedgerEntryChange  int64  `parquet:"name=ledger_entry_change, type=INT64, convertedtype=UINT_64"`
-	Deleted            bool   `parquet:"name=deleted, type=BOOLEAN"`
-	ClosedAt           int64  `parquet:"name=closed_at, type=INT64, convertedtype=TIMESTAMP_MILLIS"`
-	LedgerSequence     int64  `parquet:"name=ledger_sequence, type=INT64, convertedtype=UINT_64"`
-	LedgerKeyHash      string `parquet:"name=ledger_key_hash, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
-	NInstructions      int64  `parquet:"name=n_instructions, type=INT64, convertedtype=UINT_64"`
-	NFunctions         int64  `parquet:"name=n_functions, type=INT64, convertedtype=UINT_64"`
-	NGlobals           int64  `parquet:"name=n_globals, type=INT64, convertedtype=UINT_64"`
-	NTableEntries      int64  `parquet:"name=n_table_entries, type=INT64, convertedtype=UINT_64"`
-	NTypes             int64  `parquet:"name=n_types, type=INT64, convertedtype=UINT_64"`
-	NDataSegments      int64  `parquet:"name=n_data_segments, type=INT64, convertedtype=UINT_64"`
-	NElemSegments      int64  `parquet:"name=n_elem_segments, type=INT64, convertedtype=UINT_64"`
-	NImports           int64  `parquet:"name=n_imports, type=INT64, convertedtype=UINT_64"`
-	NExports           int64  `parquet:"name=n_exports, type=INT64, convertedtype=UINT_64"`
-	NDataSegmentBytes  int64  `parquet:"name=n_data_segment_bytes, type=INT64, convertedtype=UINT_64"`
+	ContractCodeHash    string `parquet:"name=contract_code_hash, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	ContractCodeExtV    int32  `parquet:"name=contract_code_ext_v, type=INT32"`
+	LastModifiedLedger  int64  `parquet:"name=last_modified_ledger, type=INT64, convertedtype=UINT_64"`
+	LedgerEntryChange   int64  `parquet:"name=ledger_entry_change, type=INT64, convertedtype=UINT_64"`
+	Deleted             bool   `parquet:"name=deleted, type=BOOLEAN"`
+	ClosedAt            int64  `parquet:"name=closed_at, type=INT64, convertedtype=TIMESTAMP_MILLIS"`
+	LedgerSequence      int64  `parquet:"name=ledger_sequence, type=INT64, convertedtype=UINT_64"`
+	LedgerKeyHash       string `parquet:"name=ledger_key_hash, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	NInstructions       int64  `parquet:"name=n_instructions, type=INT64, convertedtype=UINT_64"`
+	NFunctions          int64  `parquet:"name=n_functions, type=INT64, convertedtype=UINT_64"`
+	NGlobals            int64  `parquet:"name=n_globals, type=INT64, convertedtype=UINT_64"`
+	NTableEntries       int64  `parquet:"name=n_table_entries, type=INT64, convertedtype=UINT_64"`
+	NTypes              int64  `parquet:"name=n_types, type=INT64, convertedtype=UINT_64"`
+	NDataSegments       int64  `parquet:"name=n_data_segments, type=INT64, convertedtype=UINT_64"`
+	NElemSegments       int64  `parquet:"name=n_elem_segments, type=INT64, convertedtype=UINT_64"`
+	NImports            int64  `parquet:"name=n_imports, type=INT64, convertedtype=UINT_64"`
+	NExports            int64  `parquet:"name=n_exports, type=INT64, convertedtype=UINT_64"`
+	NDataSegmentBytes   int64  `parquet:"name=n_data_segment_bytes, type=INT64, convertedtype=UINT_64"`
+	LedgerKeyHashBase64 string `parquet:"name=ledger_key_hash_base_64, type=BYTE_ARRAY, convertedtype=UTF8"`
 }
 
 // ConfigSettingOutputParquet is a representation of soroban config settings that aligns with the Bigquery table config_settings
@@ -366,17 +529,164 @@ type ConfigSettingOutputParquet struct {
 	Deleted                                bool    `parquet:"name=deleted, type=BOOLEAN"`
 	ClosedAt                               int64   `parquet:"name=closed_at, type=INT64, convertedtype=TIMESTAMP_MILLIS"`
 	LedgerSequence                         int64   `parquet:"name=ledger_sequence, type=INT64, convertedtype=UINT_64"`
+	LedgerKeyHash                          string  `parquet:"name=ledger_key_hash, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	LedgerKeyHashBase64                    string  `parquet:"name=ledger_key_hash_base_64, type=BYTE_ARRAY, convertedtype=UTF8"`
+}
+
+// AccountOperationOutputParquet is a representation of an (account, operation) pair that aligns with the Bigquery table account_operations
+type AccountOperationOutputParquet struct {
+	AccountID   string `parquet:"name=account_id, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	OperationID int64  `parquet:"name=operation_id, type=INT64"`
+}
+
+// ParticipantOutputParquet is a representation of an operation participant that aligns with the Bigquery table participants
+type ParticipantOutputParquet struct {
+	OperationID     int64  `parquet:"name=operation_id, type=INT64"`
+	TransactionID   int64  `parquet:"name=transaction_id, type=INT64"`
+	TransactionHash string `parquet:"name=transaction_hash, type=BYTE_ARRAY, convertedtype=UTF8"`
+	OperationType   string `parquet:"name=type_string, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	Account         string `parquet:"name=account, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	AccountMuxed    string `parquet:"name=account_muxed, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	Role            string `parquet:"name=role, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	Successful      bool   `parquet:"name=successful, type=BOOLEAN"`
+	ClosedAt        int64  `parquet:"name=closed_at, type=INT64, convertedtype=TIMESTAMP_MILLIS"`
+	LedgerSequence  int64  `parquet:"name=ledger_sequence, type=INT64, convertedtype=UINT_64"`
+}
+
+// LedgerFeeStatsOutputParquet is a representation of the inclusion metrics of a ledger that aligns with the Bigquery table ledger_fee_stats
+type LedgerFeeStatsOutputParquet struct {
+	LedgerSequence        int64   `parquet:"name=ledger_sequence, type=INT64, convertedtype=UINT_64"`
+	ClosedAt              int64   `parquet:"name=closed_at, type=INT64, convertedtype=TIMESTAMP_MILLIS"`
+	BaseFee               int64   `parquet:"name=base_fee, type=INT64, convertedtype=UINT_64"`
+	MaxTxSetSize          int64   `parquet:"name=max_tx_set_size, type=INT64, convertedtype=UINT_64"`
+	TransactionCount      int32   `parquet:"name=transaction_count, type=INT32"`
+	TxSetOperationCount   int32   `parquet:"name=tx_set_operation_count, type=INT32"`
+	ClassicOperationCount int32   `parquet:"name=classic_operation_count, type=INT32"`
+	CapacityUsage         float64 `parquet:"name=capacity_usage, type=DOUBLE"`
+	MinInclusionFee       int64   `parquet:"name=min_inclusion_fee, type=INT64"`
+	MedianInclusionFee    int64   `parquet:"name=median_inclusion_fee, type=INT64"`
+	MaxInclusionFee       int64   `parquet:"name=max_inclusion_fee, type=INT64"`
+	InclusionBaseFee      int64   `parquet:"name=inclusion_base_fee, type=INT64"`
+	IsSurge               bool    `parquet:"name=is_surge, type=BOOLEAN"`
+}
+
+// AssetSupplyOutputParquet is the change in the circulating supply of an asset within a ledger that aligns with the Bigquery table asset_supply
+type AssetSupplyOutputParquet struct {
+	LedgerSequence    int64  `parquet:"name=ledger_sequence, type=INT64, convertedtype=UINT_64"`
+	ClosedAt          int64  `parquet:"name=closed_at, type=INT64, convertedtype=TIMESTAMP_MILLIS"`
+	Asset             string `parquet:"name=asset, type=BYTE_ARRAY, convertedtype=UTF8"`
+	AssetType         string `parquet:"name=asset_type, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	AssetCode         string `parquet:"name=asset_code, type=BYTE_ARRAY, convertedtype=UTF8"`
+	AssetIssuer       string `parquet:"name=asset_issuer, type=BYTE_ARRAY, convertedtype=UTF8"`
+	AssetID           int64  `parquet:"name=asset_id, type=INT64"`
+	ContractID        string `parquet:"name=contract_id, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Minted            string `parquet:"name=minted, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Burned            string `parquet:"name=burned, type=BYTE_ARRAY, convertedtype=UTF8"`
+	ClawedBack        string `parquet:"name=clawed_back, type=BYTE_ARRAY, convertedtype=UTF8"`
+	SupplyDelta       string `parquet:"name=supply_delta, type=BYTE_ARRAY, convertedtype=UTF8"`
+	CirculatingSupply string `parquet:"name=circulating_supply, type=BYTE_ARRAY, convertedtype=UTF8"`
+}
+
+// FootprintOutputParquet is a representation of a footprint key that aligns with the Bigquery table footprints
+type FootprintOutputParquet struct {
+	TransactionHash     string `parquet:"name=transaction_hash, type=BYTE_ARRAY, convertedtype=UTF8"`
+	TransactionID       int64  `parquet:"name=transaction_id, type=INT64"`
+	Successful          bool   `parquet:"name=successful, type=BOOLEAN"`
+	Access              string `parquet:"name=access, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	LedgerKeyHash       string `parquet:"name=ledger_key_hash, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	LedgerKeyHashBase64 string `parquet:"name=ledger_key_hash_base_64, type=BYTE_ARRAY, convertedtype=UTF8"`
+	LedgerEntryType     string `parquet:"name=ledger_entry_type, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	AccountID           string `parquet:"name=account_id, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	AssetType           string `parquet:"name=asset_type, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	AssetCode           string `parquet:"name=asset_code, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	AssetIssuer         string `parquet:"name=asset_issuer, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	LiquidityPoolID     string `parquet:"name=liquidity_pool_id, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	ContractId          string `parquet:"name=contract_id, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	ContractKeyType     string `parquet:"name=contract_key_type, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	ContractDurability  string `parquet:"name=contract_durability, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	ContractCodeHash    string `parquet:"name=contract_code_hash, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	ClosedAt            int64  `parquet:"name=closed_at, type=INT64, convertedtype=TIMESTAMP_MILLIS"`
+	LedgerSequence      int64  `parquet:"name=ledger_sequence, type=INT64, convertedtype=UINT_64"`
+}
+
+// FootprintReadOutputParquet is a representation of a footprint read that aligns with the Bigquery table footprint_reads
+type FootprintReadOutputParquet struct {
+	LedgerKeyHash       string `parquet:"name=ledger_key_hash, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	LedgerKeyHashBase64 string `parquet:"name=ledger_key_hash_base_64, type=BYTE_ARRAY, convertedtype=UTF8"`
+	LedgerEntryType     string `parquet:"name=ledger_entry_type, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	ContractId          string `parquet:"name=contract_id, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	TransactionHash     string `parquet:"name=transaction_hash, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Successful          bool   `parquet:"name=successful, type=BOOLEAN"`
+	ClosedAt            int64  `parquet:"name=closed_at, type=INT64, convertedtype=TIMESTAMP_MILLIS"`
+	LedgerSequence      int64  `parquet:"name=ledger_sequence, type=INT64, convertedtype=UINT_64"`
+}
+
+// RestoreCandidateOutputParquet is an archived entry used by a soroban transaction that aligns with the Bigquery table restore_candidates
+type RestoreCandidateOutputParquet struct {
+	LedgerKeyHash       string `parquet:"name=ledger_key_hash, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	LedgerKeyHashBase64 string `parquet:"name=ledger_key_hash_base_64, type=BYTE_ARRAY, convertedtype=UTF8"`
+	LedgerEntryType     string `parquet:"name=ledger_entry_type, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	ContractId          string `parquet:"name=contract_id, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	ContractKeyType     string `parquet:"name=contract_key_type, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	ContractCodeHash    string `parquet:"name=contract_code_hash, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	Reason              string `parquet:"name=reason, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	LiveUntilLedgerSeq  int64  `parquet:"name=live_until_ledger_seq, type=INT64"`
+	TransactionHash     string `parquet:"name=transaction_hash, type=BYTE_ARRAY, convertedtype=UTF8"`
+	TransactionID       int64  `parquet:"name=transaction_id, type=INT64"`
+	Successful          bool   `parquet:"name=successful, type=BOOLEAN"`
+	ClosedAt            int64  `parquet:"name=closed_at, type=INT64, convertedtype=TIMESTAMP_MILLIS"`
+	LedgerSequence      int64  `parquet:"name=ledger_sequence, type=INT64, convertedtype=UINT_64"`
+}
+
+// ContractSpecOutputParquet is a function of a contract spec that aligns with the Bigquery table contract_specs
+type ContractSpecOutputParquet struct {
+	ContractCodeHash string   `parquet:"name=contract_code_hash, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	FunctionName     string   `parquet:"name=function_name, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	Inputs           string   `parquet:"name=inputs, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Outputs          []string `parquet:"name=outputs, type=BYTE_ARRAY, convertedtype=UTF8, repetitiontype=REPEATED"`
+	Doc              string   `parquet:"name=doc, type=BYTE_ARRAY, convertedtype=UTF8"`
+	ClosedAt         int64    `parquet:"name=closed_at, type=INT64, convertedtype=TIMESTAMP_MILLIS"`
+	LedgerSequence   int64    `parquet:"name=ledger_sequence, type=INT64, convertedtype=UINT_64"`
 }
 
 // TtlOutputParquet is a representation of soroban ttl that aligns with the Bigquery table ttls
 type TtlOutputParquet struct {
+	KeyHash             string `parquet:"name=key_hash, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	LiveUntilLedgerSeq  int64  `parquet:"name=live_until_ledger_seq, type=INT64, convertedtype=UINT_64"`
+	LastModifiedLedger  int64  `parquet:"name=last_modified_ledger, type=INT64, convertedtype=UINT_64"`
+	LedgerEntryChange   int64  `parquet:"name=ledger_entry_change, type=INT64, convertedtype=UINT_64"`
+	Deleted             bool   `parquet:"name=deleted, type=BOOLEAN"`
+	ClosedAt            int64  `parquet:"name=closed_at, type=INT64, convertedtype=TIMESTAMP_MILLIS"`
+	LedgerSequence      int64  `parquet:"name=ledger_sequence, type=INT64, convertedtype=UINT_64"`
+	LedgerKeyHash       string `parquet:"name=ledger_key_hash, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	LedgerKeyHashBase64 string `parquet:"name=ledger_key_hash_base_64, type=BYTE_ARRAY, convertedtype=UTF8"`
+}
+
+// TtlExpiryOutputParquet is the number of ledgers left before a soroban entry expires that aligns with the BigQuery table ttl_expiry
+type TtlExpiryOutputParquet struct {
 	KeyHash            string `parquet:"name=key_hash, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
 	LiveUntilLedgerSeq int64  `parquet:"name=live_until_ledger_seq, type=INT64, convertedtype=UINT_64"`
+	LedgersUntilExpiry int64  `parquet:"name=ledgers_until_expiry, type=INT64"`
+	Expired            bool   `parquet:"name=expired, type=BOOLEAN"`
 	LastModifiedLedger int64  `parquet:"name=last_modified_ledger, type=INT64, convertedtype=UINT_64"`
-	LedgerEntryChange  int64  `parquet:"name=ledger_entry_change, type=INT64, convertedtype=UINT_64"`
-	Deleted            bool   `parquet:"name=deleted, type=BOOLEAN"`
 	ClosedAt           int64  `parquet:"name=closed_at, type=INT64, convertedtype=TIMESTAMP_MILLIS"`
 	LedgerSequence     int64  `parquet:"name=ledger_sequence, type=INT64, convertedtype=UINT_64"`
+	LedgerKeyHash      string `parquet:"name=ledger_key_hash, type=BYTE_ARRAY, convertedtype=UTF8"`
+}
+
+// PoolParticipantOutputParquet is the change of the shares of an account in a liquidity pool that aligns with the BigQuery table liquidity_pool_participants
+type PoolParticipantOutputParquet struct {
+	AccountID             string  `parquet:"name=account_id, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	LiquidityPoolID       string  `parquet:"name=liquidity_pool_id, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	LiquidityPoolIDStrkey string  `parquet:"name=liquidity_pool_id_strkey, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	SharesDelta           float64 `parquet:"name=shares_delta, type=DOUBLE"`
+	Shares                float64 `parquet:"name=shares, type=DOUBLE"`
+	LastModifiedLedger    int64   `parquet:"name=last_modified_ledger, type=INT64, convertedtype=UINT_64"`
+	LedgerEntryChange     int64   `parquet:"name=ledger_entry_change, type=INT64, convertedtype=UINT_64"`
+	Deleted               bool    `parquet:"name=deleted, type=BOOLEAN"`
+	ClosedAt              int64   `parquet:"name=closed_at, type=INT64, convertedtype=TIMESTAMP_MILLIS"`
+	LedgerSequence        int64   `parquet:"name=ledger_sequence, type=INT64, convertedtype=UINT_64"`
+	LedgerKeyHash         string  `parquet:"name=ledger_key_hash, type=BYTE_ARRAY, convertedtype=UTF8"`
 }
 
 // ContractEventOutputParquet is a representation of soroban contract events and diagnostic events
@@ -390,10 +700,121 @@ type ContractEventOutputParquet struct {
 	ContractId               string        `parquet:"name=contract_id, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
 	Type                     int32         `parquet:"name=type, type=INT32"`
 	TypeString               string        `parquet:"name=type_string, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	EventType                string        `parquet:"name=event_type, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
 	Topics                   []interface{} `parquet:"name=topics, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
 	TopicsDecoded            []interface{} `parquet:"name=topics_decoded, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
 	Data                     interface{}   `parquet:"name=data, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
 	DataDecoded              interface{}   `parquet:"name=data_decoded, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	ValueTruncated           bool          `parquet:"name=value_truncated, type=BOOLEAN"`
 	ContractEventXDR         string        `parquet:"name=contract_event_xdr, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
 	OperationID              int64         `parquet:"name=operation_id, type=INT64"`
+	EventID                  string        `parquet:"name=event_id, type=BYTE_ARRAY, convertedtype=UTF8"`
+}
+
+// LedgerUpgradeOutputParquet is a representation of a ledger upgrade that aligns with the BigQuery table ledger_upgrades
+type LedgerUpgradeOutputParquet struct {
+	LedgerSequence              int64  `parquet:"name=ledger_sequence, type=INT64, convertedtype=UINT_64"`
+	ClosedAt                    int64  `parquet:"name=closed_at, type=INT64, convertedtype=TIMESTAMP_MILLIS"`
+	UpgradeIndex                int32  `parquet:"name=upgrade_index, type=INT32"`
+	Type                        int32  `parquet:"name=type, type=INT32"`
+	TypeString                  string `parquet:"name=type_string, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	NewLedgerVersion            int64  `parquet:"name=new_ledger_version, type=INT64"`
+	NewBaseFee                  int64  `parquet:"name=new_base_fee, type=INT64"`
+	NewMaxTxSetSize             int64  `parquet:"name=new_max_tx_set_size, type=INT64"`
+	NewBaseReserve              int64  `parquet:"name=new_base_reserve, type=INT64"`
+	NewFlags                    int64  `parquet:"name=new_flags, type=INT64"`
+	NewMaxSorobanTxSetSize      int64  `parquet:"name=new_max_soroban_tx_set_size, type=INT64"`
+	ConfigUpgradeSetContractID  string `parquet:"name=config_upgrade_set_contract_id, type=BYTE_ARRAY, convertedtype=UTF8"`
+	ConfigUpgradeSetContentHash string `parquet:"name=config_upgrade_set_content_hash, type=BYTE_ARRAY, convertedtype=UTF8"`
+	UpgradeXDR                  string `parquet:"name=upgrade_xdr, type=BYTE_ARRAY, convertedtype=UTF8"`
+}
+
+// ConfigUpgradeOutputParquet is a representation of a soroban config setting changed by a config upgrade that aligns with the BigQuery table config_upgrades
+type ConfigUpgradeOutputParquet struct {
+	LedgerSequence              int64    `parquet:"name=ledger_sequence, type=INT64, convertedtype=UINT_64"`
+	ClosedAt                    int64    `parquet:"name=closed_at, type=INT64, convertedtype=TIMESTAMP_MILLIS"`
+	UpgradeIndex                int32    `parquet:"name=upgrade_index, type=INT32"`
+	ConfigUpgradeSetContractID  string   `parquet:"name=config_upgrade_set_contract_id, type=BYTE_ARRAY, convertedtype=UTF8"`
+	ConfigUpgradeSetContentHash string   `parquet:"name=config_upgrade_set_content_hash, type=BYTE_ARRAY, convertedtype=UTF8"`
+	ConfigSettingId             int32    `parquet:"name=config_setting_id, type=INT32"`
+	ConfigSettingIdString       string   `parquet:"name=config_setting_id_string, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	ChangedFields               []string `parquet:"name=changed_fields, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	OldConfigSetting            string   `parquet:"name=old_config_setting, type=BYTE_ARRAY, convertedtype=UTF8"`
+	NewConfigSetting            string   `parquet:"name=new_config_setting, type=BYTE_ARRAY, convertedtype=UTF8"`
+}
+
+// ConfigSettingDiffOutputParquet is a changed field of a soroban config setting that aligns with the BigQuery table config_setting_diffs
+type ConfigSettingDiffOutputParquet struct {
+	ConfigSettingId       int32  `parquet:"name=config_setting_id, type=INT32"`
+	ConfigSettingIdString string `parquet:"name=config_setting_id_string, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	Field                 string `parquet:"name=field, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	OldValue              string `parquet:"name=old_value, type=BYTE_ARRAY, convertedtype=UTF8"`
+	NewValue              string `parquet:"name=new_value, type=BYTE_ARRAY, convertedtype=UTF8"`
+	LastModifiedLedger    int64  `parquet:"name=last_modified_ledger, type=INT64, convertedtype=UINT_64"`
+	LedgerEntryChange     int64  `parquet:"name=ledger_entry_change, type=INT64, convertedtype=UINT_64"`
+	Deleted               bool   `parquet:"name=deleted, type=BOOLEAN"`
+	ClosedAt              int64  `parquet:"name=closed_at, type=INT64, convertedtype=TIMESTAMP_MILLIS"`
+	LedgerSequence        int64  `parquet:"name=ledger_sequence, type=INT64, convertedtype=UINT_64"`
+	LedgerKeyHash         string `parquet:"name=ledger_key_hash, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+}
+
+// InflationPayoutOutputParquet is a representation of a single payout of an inflation operation that aligns with the BigQuery table inflation_payouts
+type InflationPayoutOutputParquet struct {
+	LedgerSequence  int64   `parquet:"name=ledger_sequence, type=INT64, convertedtype=UINT_64"`
+	ClosedAt        int64   `parquet:"name=closed_at, type=INT64, convertedtype=TIMESTAMP_MILLIS"`
+	TransactionHash string  `parquet:"name=transaction_hash, type=BYTE_ARRAY, convertedtype=UTF8"`
+	TransactionID   int64   `parquet:"name=transaction_id, type=INT64"`
+	OperationID     int64   `parquet:"name=operation_id, type=INT64"`
+	PayoutIndex     int32   `parquet:"name=payout_index, type=INT32"`
+	Destination     string  `parquet:"name=destination, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	Amount          float64 `parquet:"name=amount, type=DOUBLE"`
+}
+
+// ClaimableBalanceClaimOutputParquet is the lifecycle of a claimable balance that aligns with the Bigquery table cb_claims
+type ClaimableBalanceClaimOutputParquet struct {
+	BalanceID              string  `parquet:"name=balance_id, type=BYTE_ARRAY, convertedtype=UTF8"`
+	BalanceIDStrkey        string  `parquet:"name=balance_id_strkey, type=BYTE_ARRAY, convertedtype=UTF8"`
+	AssetCode              string  `parquet:"name=asset_code, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	AssetIssuer            string  `parquet:"name=asset_issuer, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	AssetType              string  `parquet:"name=asset_type, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	AssetID                int64   `parquet:"name=asset_id, type=INT64"`
+	AssetAmount            float64 `parquet:"name=asset_amount, type=DOUBLE"`
+	CreatedBy              string  `parquet:"name=created_by, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	CreatedTransactionHash string  `parquet:"name=created_transaction_hash, type=BYTE_ARRAY, convertedtype=UTF8"`
+	CreatedLedgerSequence  int64   `parquet:"name=created_ledger_sequence, type=INT64, convertedtype=UINT_64"`
+	CreatedAt              int64   `parquet:"name=created_at, type=INT64, convertedtype=TIMESTAMP_MILLIS"`
+	ClaimType              string  `parquet:"name=claim_type, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	ClaimedBy              string  `parquet:"name=claimed_by, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	ElapsedLedgers         int64   `parquet:"name=elapsed_ledgers, type=INT64"`
+	ElapsedSeconds         int64   `parquet:"name=elapsed_seconds, type=INT64"`
+	TransactionHash        string  `parquet:"name=transaction_hash, type=BYTE_ARRAY, convertedtype=UTF8"`
+	TransactionID          int64   `parquet:"name=transaction_id, type=INT64"`
+	OperationID            int64   `parquet:"name=operation_id, type=INT64"`
+	ClosedAt               int64   `parquet:"name=closed_at, type=INT64, convertedtype=TIMESTAMP_MILLIS"`
+	LedgerSequence         int64   `parquet:"name=ledger_sequence, type=INT64, convertedtype=UINT_64"`
+}
+
+// PathPaymentHopOutputParquet is a representation of an offer or liquidity pool crossed by a path payment that aligns with the BigQuery table path_payment_hops
+type PathPaymentHopOutputParquet struct {
+	HistoryOperationID                int64   `parquet:"name=history_operation_id, type=INT64"`
+	LedgerClosedAt                    int64   `parquet:"name=ledger_closed_at, type=INT64, convertedtype=TIMESTAMP_MILLIS"`
+	OperationType                     int32   `parquet:"name=operation_type, type=INT32"`
+	SourceAccount                     string  `parquet:"name=source_account, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	HopIndex                          int32   `parquet:"name=hop_index, type=INT32"`
+	ClaimOrder                        int32   `parquet:"name=claim_order, type=INT32"`
+	SourceAssetCode                   string  `parquet:"name=source_asset_code, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	SourceAssetIssuer                 string  `parquet:"name=source_asset_issuer, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	SourceAssetType                   string  `parquet:"name=source_asset_type, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	SourceAssetID                     int64   `parquet:"name=source_asset_id, type=INT64"`
+	SourceAmount                      float64 `parquet:"name=source_amount, type=DOUBLE"`
+	DestinationAssetCode              string  `parquet:"name=destination_asset_code, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	DestinationAssetIssuer            string  `parquet:"name=destination_asset_issuer, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	DestinationAssetType              string  `parquet:"name=destination_asset_type, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	DestinationAssetID                int64   `parquet:"name=destination_asset_id, type=INT64"`
+	DestinationAmount                 float64 `parquet:"name=destination_amount, type=DOUBLE"`
+	CounterpartyType                  string  `parquet:"name=counterparty_type, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	CounterpartyAccount               string  `parquet:"name=counterparty_account, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	CounterpartyOfferID               int64   `parquet:"name=counterparty_offer_id, type=INT64"`
+	CounterpartyLiquidityPoolID       string  `parquet:"name=counterparty_liquidity_pool_id, type=BYTE_ARRAY, convertedtype=UTF8"`
+	CounterpartyLiquidityPoolIDStrkey string  `parquet:"name=counterparty_liquidity_pool_id_strkey, type=BYTE_ARRAY, convertedtype=UTF8"`
 }
@@ -0,0 +1,178 @@
+package transform
+
+import (
+	"encoding/json"
+
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stellar/go-stellar-xdr-json/xdrjson"
+)
+
+const (
+	// DefaultScValMaxDepth is the default maximum nesting depth of the maps and vecs in decoded ScVals
+	DefaultScValMaxDepth = 32
+	// DefaultScValMaxSize is the default maximum size in bytes of a decoded ScVal
+	DefaultScValMaxSize = 256 * 1024
+)
+
+var (
+	scValMaxDepth = DefaultScValMaxDepth
+	scValMaxSize  = DefaultScValMaxSize
+)
+
+// SetScValLimits sets the maximum nesting depth and the maximum size in bytes of the JSON that ScVals are decoded to.
+// A limit of 0 disables it.
+func SetScValLimits(maxDepth, maxSize int) {
+	scValMaxDepth = maxDepth
+	scValMaxSize = maxSize
+}
+
+// decodeScVal decodes an ScVal to JSON within the configured limits, and reports whether the value was truncated.
+// Maps and vecs nested deeper than the maximum depth are replaced with a {"truncated":{"type":"vec","len":<items>}}
+// marker, and a value whose JSON is larger than the maximum size is replaced with a {"truncated":{"size":<bytes>}}
+// marker.
+func decodeScVal(scVal xdr.ScVal) (json.RawMessage, bool, error) {
+	truncated := scValMaxDepth > 0 && scValDepth(scVal) > scValMaxDepth
+
+	decodedVal := scVal
+	if truncated {
+		decodedVal = truncateScVal(scVal, 1)
+	}
+
+	raw, err := decodedVal.MarshalBinary()
+	if err != nil {
+		return nil, false, err
+	}
+	decoded, err := xdrjson.Decode(xdrjson.ScVal, raw)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if truncated {
+		var value interface{}
+		if err = json.Unmarshal(decoded, &value); err != nil {
+			return nil, false, err
+		}
+		decoded, err = json.Marshal(markTruncatedScVal(scVal, value, 1))
+		if err != nil {
+			return nil, false, err
+		}
+	}
+
+	if scValMaxSize > 0 && len(decoded) > scValMaxSize {
+		decoded, err = json.Marshal(map[string]interface{}{
+			"truncated": map[string]int{"size": len(decoded)},
+		})
+		if err != nil {
+			return nil, false, err
+		}
+		truncated = true
+	}
+
+	return decoded, truncated, nil
+}
+
+// scValItems returns the values nested in a map or a vec, with the key of every map entry followed by its value.
+// The bool is false for every other type of ScVal.
+func scValItems(scVal xdr.ScVal) (string, []xdr.ScVal, bool) {
+	if vec, ok := scVal.GetVec(); ok && vec != nil {
+		return "vec", *vec, true
+	}
+	if scMap, ok := scVal.GetMap(); ok && scMap != nil {
+		items := make([]xdr.ScVal, 0, 2*len(*scMap))
+		for _, entry := range *scMap {
+			items = append(items, entry.Key, entry.Val)
+		}
+		return "map", items, true
+	}
+	return "", nil, false
+}
+
+// scValDepth returns how deeply the maps and vecs of an ScVal are nested, where a value that isn't a map or a vec has
+// a depth of 0
+func scValDepth(scVal xdr.ScVal) int {
+	_, items, ok := scValItems(scVal)
+	if !ok {
+		return 0
+	}
+	depth := 0
+	for _, item := range items {
+		depth = max(depth, scValDepth(item))
+	}
+	return depth + 1
+}
+
+// truncateScVal returns a copy of the ScVal in which the maps and vecs nested deeper than the maximum depth are
+// replaced with void, so that the copy can be decoded
+func truncateScVal(scVal xdr.ScVal, depth int) xdr.ScVal {
+	containerType, items, ok := scValItems(scVal)
+	if !ok {
+		return scVal
+	}
+	if depth > scValMaxDepth {
+		return xdr.ScVal{Type: xdr.ScValTypeScvVoid}
+	}
+
+	truncatedItems := make([]xdr.ScVal, 0, len(items))
+	for _, item := range items {
+		truncatedItems = append(truncatedItems, truncateScVal(item, depth+1))
+	}
+
+	if containerType == "vec" {
+		vec := xdr.ScVec(truncatedItems)
+		vecPtr := &vec
+		return xdr.ScVal{Type: xdr.ScValTypeScvVec, Vec: &vecPtr}
+	}
+
+	scMap := make(xdr.ScMap, 0, len(truncatedItems)/2)
+	for i := 0; i < len(truncatedItems); i += 2 {
+		scMap = append(scMap, xdr.ScMapEntry{Key: truncatedItems[i], Val: truncatedItems[i+1]})
+	}
+	scMapPtr := &scMap
+	return xdr.ScVal{Type: xdr.ScValTypeScvMap, Map: &scMapPtr}
+}
+
+// markTruncatedScVal walks an ScVal together with the decoded JSON of its truncated copy, and replaces the voids that
+// stand in for truncated maps and vecs with truncation markers
+func markTruncatedScVal(scVal xdr.ScVal, value interface{}, depth int) interface{} {
+	containerType, items, ok := scValItems(scVal)
+	if !ok {
+		return value
+	}
+	if depth > scValMaxDepth {
+		length := len(items)
+		if containerType == "map" {
+			length /= 2
+		}
+		return map[string]interface{}{
+			"truncated": map[string]interface{}{"type": containerType, "len": length},
+		}
+	}
+
+	object, ok := value.(map[string]interface{})
+	if !ok {
+		return value
+	}
+	decodedItems, ok := object[containerType].([]interface{})
+	if !ok {
+		return value
+	}
+
+	if containerType == "vec" {
+		for i := range decodedItems {
+			if i < len(items) {
+				decodedItems[i] = markTruncatedScVal(items[i], decodedItems[i], depth+1)
+			}
+		}
+		return value
+	}
+
+	for i, decodedEntry := range decodedItems {
+		entry, ok := decodedEntry.(map[string]interface{})
+		if !ok || 2*i+1 >= len(items) {
+			continue
+		}
+		entry["key"] = markTruncatedScVal(items[2*i], entry["key"], depth+1)
+		entry["val"] = markTruncatedScVal(items[2*i+1], entry["val"], depth+1)
+	}
+	return value
+}
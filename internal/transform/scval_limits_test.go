@@ -0,0 +1,108 @@
+package transform
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+func makeNestedScVec(depth int) xdr.ScVal {
+	value := xdr.Uint32(7)
+	scVal := xdr.ScVal{Type: xdr.ScValTypeScvU32, U32: &value}
+	for i := 0; i < depth; i++ {
+		vec := xdr.ScVec{scVal}
+		vecPtr := &vec
+		scVal = xdr.ScVal{Type: xdr.ScValTypeScvVec, Vec: &vecPtr}
+	}
+	return scVal
+}
+
+func makeScMapTestInput() xdr.ScVal {
+	symbol := xdr.ScSymbol("balances")
+	inner := makeNestedScVec(2)
+	scMap := xdr.ScMap{
+		{Key: xdr.ScVal{Type: xdr.ScValTypeScvSymbol, Sym: &symbol}, Val: inner},
+	}
+	scMapPtr := &scMap
+	return xdr.ScVal{Type: xdr.ScValTypeScvMap, Map: &scMapPtr}
+}
+
+func TestDecodeScValLimits(t *testing.T) {
+	defer SetScValLimits(DefaultScValMaxDepth, DefaultScValMaxSize)
+
+	type testCase struct {
+		name          string
+		input         xdr.ScVal
+		maxDepth      int
+		maxSize       int
+		wantOutput    string
+		wantTruncated bool
+	}
+
+	tests := []testCase{
+		{
+			name:       "within limits",
+			input:      makeScMapTestInput(),
+			maxDepth:   3,
+			maxSize:    1024,
+			wantOutput: `{"map":[{"key":{"symbol":"balances"},"val":{"vec":[{"vec":[{"u32":7}]}]}}]}`,
+		},
+		{
+			name:          "nested map value",
+			input:         makeScMapTestInput(),
+			maxDepth:      2,
+			maxSize:       1024,
+			wantOutput:    `{"map":[{"key":{"symbol":"balances"},"val":{"vec":[{"truncated":{"len":1,"type":"vec"}}]}}]}`,
+			wantTruncated: true,
+		},
+		{
+			name:          "too large",
+			input:         makeScMapTestInput(),
+			maxDepth:      3,
+			maxSize:       16,
+			wantOutput:    `{"truncated":{"size":75}}`,
+			wantTruncated: true,
+		},
+		{
+			name:          "deeper than xdrjson can decode",
+			input:         makeNestedScVec(400),
+			maxDepth:      1,
+			maxSize:       0,
+			wantOutput:    `{"vec":[{"truncated":{"len":1,"type":"vec"}}]}`,
+			wantTruncated: true,
+		},
+		{
+			name:       "limits disabled",
+			input:      makeNestedScVec(2),
+			maxDepth:   0,
+			maxSize:    0,
+			wantOutput: `{"vec":[{"vec":[{"u32":7}]}]}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			SetScValLimits(test.maxDepth, test.maxSize)
+			actualOutput, actualTruncated, actualError := decodeScVal(test.input)
+			assert.NoError(t, actualError)
+			assert.JSONEq(t, test.wantOutput, string(actualOutput))
+			assert.Equal(t, test.wantTruncated, actualTruncated)
+			assert.True(t, json.Valid(actualOutput))
+		})
+	}
+}
+
+func TestSerializeScValArrayTruncated(t *testing.T) {
+	defer SetScValLimits(DefaultScValMaxDepth, DefaultScValMaxSize)
+	SetScValLimits(1, 0)
+
+	data, dataDecoded, truncated, err := serializeScValArray([]xdr.ScVal{makeNestedScVec(0), makeNestedScVec(2)})
+	assert.NoError(t, err)
+	assert.True(t, truncated)
+	assert.Equal(t, []interface{}{"AAAAAwAAAAc=", "AAAAEAAAAAEAAAABAAAAEAAAAAEAAAABAAAAAwAAAAc="}, data)
+	assert.Equal(t, `{"u32":7}`, string(dataDecoded[0].(json.RawMessage)))
+	assert.Equal(t, `{"vec":[{"truncated":{"len":1,"type":"vec"}}]}`, string(dataDecoded[1].(json.RawMessage)))
+}
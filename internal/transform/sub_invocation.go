@@ -0,0 +1,165 @@
+package transform
+
+import (
+	"fmt"
+
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stellar/stellar-etl/v2/internal/toid"
+)
+
+// firstSubInvocationOrder is the operation order of the id of the first sub-invocation of a transaction. A transaction
+// has at most 100 operations, so the orders above them are free for the rows derived from its operations.
+const firstSubInvocationOrder = 101
+
+// TransformSubInvocations derives a row from the transformed invoke_host_function operation for every invocation
+// authorized in its authorization entries, so contract calls routed through another contract can be accounted to the
+// contract they called. Only the invocations that required authorization are in the envelope; calls that did not are
+// not expanded. The root invocation of an entry that is the top level call of the operation is not repeated, but its
+// sub-invocations are.
+//
+// The rows have the accounts, transaction and results of the operation, the id of the operation as their
+// parent_operation_id, and ids in the operation orders after the operations of the transaction. Other operations have
+// no sub-invocations.
+func TransformSubInvocations(parent OperationOutput, operation xdr.Operation) ([]OperationOutput, error) {
+	op, ok := operation.Body.GetInvokeHostFunctionOp()
+	if !ok {
+		return nil, nil
+	}
+
+	parentID := toid.Parse(parent.OperationID)
+	var subInvocations []OperationOutput
+	for entryIndex, entry := range op.Auth {
+		authorizer := parent.SourceAccount
+		if address, ok := entry.Credentials.GetAddress(); ok {
+			var err error
+			authorizer, err = address.Address.String()
+			if err != nil {
+				return nil, fmt.Errorf("could not get the address of authorization entry %d: %v", entryIndex, err)
+			}
+		}
+
+		invocations := []authorizedInvocation{{entry.RootInvocation, 0}}
+		if isTopLevelInvocation(entry.RootInvocation.Function, op.HostFunction) {
+			invocations = childInvocations(entry.RootInvocation, 0)
+		}
+		for len(invocations) > 0 {
+			invocation := invocations[0]
+			invocations = append(childInvocations(invocation.invocation, invocation.depth), invocations[1:]...)
+
+			order := firstSubInvocationOrder + int32(len(subInvocations))
+			if order > toid.OperationMask {
+				return nil, fmt.Errorf("operation %d has more than %d authorized sub-invocations", parent.OperationID, toid.OperationMask-firstSubInvocationOrder+1)
+			}
+
+			details, err := subInvocationDetails(invocation.invocation.Function)
+			if err != nil {
+				return nil, fmt.Errorf("could not get the details of a sub-invocation of authorization entry %d: %v", entryIndex, err)
+			}
+			details["authorizer"] = authorizer
+			details["auth_entry_index"] = entryIndex
+			details["invocation_depth"] = invocation.depth
+
+			subInvocation := parent
+			subInvocation.OperationID = toid.New(parentID.LedgerSequence, parentID.TransactionOrder, order).ToInt64()
+			subInvocation.ParentOperationID = parent.OperationID
+			subInvocation.OperationDetails = details
+			subInvocation.OperationDetailsJSON = details
+			subInvocations = append(subInvocations, subInvocation)
+		}
+	}
+
+	return subInvocations, nil
+}
+
+// authorizedInvocation is an invocation of an authorization entry and its depth in the invocation tree of the operation,
+// where the top level call is at depth 0
+type authorizedInvocation struct {
+	invocation xdr.SorobanAuthorizedInvocation
+	depth      int
+}
+
+// childInvocations returns the sub-invocations of an invocation, in the order they were authorized
+func childInvocations(invocation xdr.SorobanAuthorizedInvocation, depth int) []authorizedInvocation {
+	children := make([]authorizedInvocation, 0, len(invocation.SubInvocations))
+	for _, child := range invocation.SubInvocations {
+		children = append(children, authorizedInvocation{child, depth + 1})
+	}
+	return children
+}
+
+// isTopLevelInvocation checks if an authorized contract call is the call made by the host function of the operation
+func isTopLevelInvocation(function xdr.SorobanAuthorizedFunction, hostFunction xdr.HostFunction) bool {
+	authorized, ok := function.GetContractFn()
+	if !ok {
+		return false
+	}
+	invoked, ok := hostFunction.GetInvokeContract()
+	if !ok {
+		return false
+	}
+
+	authorizedXDR, err := authorized.MarshalBinary()
+	if err != nil {
+		return false
+	}
+	invokedXDR, err := invoked.MarshalBinary()
+	if err != nil {
+		return false
+	}
+	return string(authorizedXDR) == string(invokedXDR)
+}
+
+// subInvocationDetails returns the details of an authorized function, with the keys of the details of the
+// invoke_host_function operations that make the same call
+func subInvocationDetails(function xdr.SorobanAuthorizedFunction) (map[string]interface{}, error) {
+	details := map[string]interface{}{"function": function.Type.String()}
+
+	switch function.Type {
+	case xdr.SorobanAuthorizedFunctionTypeSorobanAuthorizedFunctionTypeContractFn:
+		invokeArgs := function.MustContractFn()
+		details["type"] = "invoke_contract"
+
+		contractId, err := invokeArgs.ContractAddress.String()
+		if err != nil {
+			return nil, err
+		}
+		details["contract_id"] = contractId
+		details["function_name"] = string(invokeArgs.FunctionName)
+
+		details["parameters"], details["parameters_decoded"] = serializeParameters(invokeArgs.Args)
+		var parametersTruncated bool
+		details["parameters_json"], details["parameters_json_decoded"], parametersTruncated, err = serializeScValArray(invokeArgs.Args)
+		if err != nil {
+			return nil, err
+		}
+		if parametersTruncated {
+			details["parameters_json_truncated"] = true
+		}
+	case xdr.SorobanAuthorizedFunctionTypeSorobanAuthorizedFunctionTypeCreateContractHostFn:
+		details["type"] = "create_contract"
+		for key, val := range switchContractIdPreimageType(function.MustCreateContractHostFn().ContractIdPreimage) {
+			details[key] = val
+		}
+	case xdr.SorobanAuthorizedFunctionTypeSorobanAuthorizedFunctionTypeCreateContractV2HostFn:
+		args := function.MustCreateContractV2HostFn()
+		details["type"] = "create_contract_v2"
+		for key, val := range switchContractIdPreimageType(args.ContractIdPreimage) {
+			details[key] = val
+		}
+
+		var err error
+		details["parameters"], details["parameters_decoded"] = serializeParameters(args.ConstructorArgs)
+		var parametersTruncated bool
+		details["parameters_json"], details["parameters_json_decoded"], parametersTruncated, err = serializeScValArray(args.ConstructorArgs)
+		if err != nil {
+			return nil, err
+		}
+		if parametersTruncated {
+			details["parameters_json_truncated"] = true
+		}
+	default:
+		return nil, fmt.Errorf("unknown authorized function type: %s", function.Type)
+	}
+
+	return details, nil
+}
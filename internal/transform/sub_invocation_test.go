@@ -0,0 +1,93 @@
+package transform
+
+import (
+	"testing"
+
+	"github.com/stellar/go-stellar-sdk/strkey"
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stellar/stellar-etl/v2/internal/toid"
+	"github.com/stretchr/testify/assert"
+)
+
+func contractInvocation(contractByte byte, function string, subInvocations ...xdr.SorobanAuthorizedInvocation) xdr.SorobanAuthorizedInvocation {
+	contractID := xdr.ContractId{contractByte}
+	return xdr.SorobanAuthorizedInvocation{
+		Function: xdr.SorobanAuthorizedFunction{
+			Type: xdr.SorobanAuthorizedFunctionTypeSorobanAuthorizedFunctionTypeContractFn,
+			ContractFn: &xdr.InvokeContractArgs{
+				ContractAddress: xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: &contractID},
+				FunctionName:    xdr.ScSymbol(function),
+			},
+		},
+		SubInvocations: subInvocations,
+	}
+}
+
+func TestTransformSubInvocations(t *testing.T) {
+	router := contractInvocation(1, "swap", contractInvocation(2, "transfer"), contractInvocation(3, "swap", contractInvocation(4, "transfer")))
+	token := contractInvocation(5, "transfer")
+	operation := xdr.Operation{Body: xdr.OperationBody{
+		Type: xdr.OperationTypeInvokeHostFunction,
+		InvokeHostFunctionOp: &xdr.InvokeHostFunctionOp{
+			HostFunction: xdr.HostFunction{Type: xdr.HostFunctionTypeHostFunctionTypeInvokeContract, InvokeContract: router.Function.ContractFn},
+			Auth: []xdr.SorobanAuthorizationEntry{
+				{Credentials: xdr.SorobanCredentials{Type: xdr.SorobanCredentialsTypeSorobanCredentialsSourceAccount}, RootInvocation: router},
+				{
+					Credentials: xdr.SorobanCredentials{
+						Type:    xdr.SorobanCredentialsTypeSorobanCredentialsAddress,
+						Address: &xdr.SorobanAddressCredentials{Address: xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeAccount, AccountId: &testAccount2ID}},
+					},
+					RootInvocation: token,
+				},
+			},
+		},
+	}}
+	parent := OperationOutput{
+		SourceAccount: testAccount1Address,
+		Type:          int32(xdr.OperationTypeInvokeHostFunction),
+		TypeString:    "invoke_host_function",
+		OperationID:   toid.New(30, 2, 1).ToInt64(),
+	}
+
+	subInvocations, err := TransformSubInvocations(parent, operation)
+	assert.NoError(t, err)
+
+	contract := func(contractByte byte) string {
+		contractID := xdr.ContractId{contractByte}
+		return strkey.MustEncode(strkey.VersionByteContract, contractID[:])
+	}
+	type expectedRow struct {
+		order      int32
+		contractID string
+		function   string
+		authorizer string
+		entryIndex int
+		depth      int
+	}
+	expected := []expectedRow{
+		{101, contract(2), "transfer", testAccount1Address, 0, 1},
+		{102, contract(3), "swap", testAccount1Address, 0, 1},
+		{103, contract(4), "transfer", testAccount1Address, 0, 2},
+		{104, contract(5), "transfer", testAccount2Address, 1, 0},
+	}
+	if assert.Len(t, subInvocations, len(expected)) {
+		for i, row := range expected {
+			subInvocation := subInvocations[i]
+			assert.Equal(t, toid.New(30, 2, row.order).ToInt64(), subInvocation.OperationID)
+			assert.Equal(t, parent.OperationID, subInvocation.ParentOperationID)
+			assert.Equal(t, parent.SourceAccount, subInvocation.SourceAccount)
+			assert.Equal(t, parent.TypeString, subInvocation.TypeString)
+			assert.Equal(t, "invoke_contract", subInvocation.OperationDetails["type"])
+			assert.Equal(t, row.contractID, subInvocation.OperationDetails["contract_id"])
+			assert.Equal(t, row.function, subInvocation.OperationDetails["function_name"])
+			assert.Equal(t, row.authorizer, subInvocation.OperationDetails["authorizer"])
+			assert.Equal(t, row.entryIndex, subInvocation.OperationDetails["auth_entry_index"])
+			assert.Equal(t, row.depth, subInvocation.OperationDetails["invocation_depth"])
+		}
+	}
+
+	// Other operations have no sub-invocations
+	subInvocations, err = TransformSubInvocations(parent, xdr.Operation{Body: xdr.OperationBody{Type: xdr.OperationTypeInflation}})
+	assert.NoError(t, err)
+	assert.Empty(t, subInvocations)
+}
@@ -168,6 +168,7 @@ var usdtAssetPath = Path{
 	AssetType:   "credit_alphanum4",
 	AssetCode:   "USDT",
 	AssetIssuer: testAccount4Address,
+	AssetID:     -8205667356306085451,
 }
 
 var ethAsset = xdr.Asset{
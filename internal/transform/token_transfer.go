@@ -9,6 +9,7 @@ import (
 	"github.com/stellar/go-stellar-sdk/strkey"
 	"github.com/stellar/go-stellar-sdk/xdr"
 	"github.com/stellar/stellar-etl/v2/internal/toid"
+	"github.com/stellar/stellar-etl/v2/internal/utils"
 )
 
 func TransformTokenTransfer(ledgerCloseMeta xdr.LedgerCloseMeta, networkPassphrase string) ([]TokenTransferOutput, error) {
@@ -92,6 +93,12 @@ func transformEvents(events []*token_transfer.TokenTransferEvent, ledgerCloseMet
 
 		asset, assetType, assetCode, assetIssuer = getAssetFromEvent(event)
 
+		// Tokens that are not Stellar assets have no asset type and are not in the assets dimension
+		var assetID null.Int
+		if assetType != "" {
+			assetID = null.IntFrom(utils.FarmHashAsset(assetCode.String, assetIssuer.String, assetType))
+		}
+
 		var toMuxedID null.String
 		var toMuxed null.String
 
@@ -116,6 +123,7 @@ func transformEvents(events []*token_transfer.TokenTransferEvent, ledgerCloseMet
 			AssetType:       assetType,
 			AssetCode:       assetCode,
 			AssetIssuer:     assetIssuer,
+			AssetID:         assetID,
 			AmountRaw:       amount,
 			Amount:          amountFloat,
 			ContractID:      eventMeta.ContractAddress,
@@ -63,6 +63,7 @@ func makeTokenTransferTestOutput() (output [][]TokenTransferOutput, err error) {
 				AssetType:       "credit_alphanum4",
 				AssetCode:       null.StringFrom("abc"),
 				AssetIssuer:     null.StringFrom("def"),
+				AssetID:         null.IntFrom(-3107237761707155200),
 				Amount:          9.999999999999999e-06,
 				AmountRaw:       "100",
 				ContractID:      "contractaddress",
@@ -82,6 +83,7 @@ func makeTokenTransferTestOutput() (output [][]TokenTransferOutput, err error) {
 				AssetType:       "credit_alphanum4",
 				AssetCode:       null.StringFrom("abc"),
 				AssetIssuer:     null.StringFrom("def"),
+				AssetID:         null.IntFrom(-3107237761707155200),
 				Amount:          9.999999999999999e-06,
 				AmountRaw:       "100",
 				ContractID:      "contractaddress",
@@ -101,6 +103,7 @@ func makeTokenTransferTestOutput() (output [][]TokenTransferOutput, err error) {
 				AssetType:       "credit_alphanum4",
 				AssetCode:       null.StringFrom("abc"),
 				AssetIssuer:     null.StringFrom("def"),
+				AssetID:         null.IntFrom(-3107237761707155200),
 				Amount:          9.999999999999999e-06,
 				AmountRaw:       "100",
 				ContractID:      "contractaddress",
@@ -120,6 +123,7 @@ func makeTokenTransferTestOutput() (output [][]TokenTransferOutput, err error) {
 				AssetType:       "credit_alphanum4",
 				AssetCode:       null.StringFrom("abc"),
 				AssetIssuer:     null.StringFrom("def"),
+				AssetID:         null.IntFrom(-3107237761707155200),
 				Amount:          9.999999999999999e-06,
 				AmountRaw:       "100",
 				ContractID:      "contractaddress",
@@ -139,6 +143,7 @@ func makeTokenTransferTestOutput() (output [][]TokenTransferOutput, err error) {
 				AssetType:       "credit_alphanum4",
 				AssetCode:       null.StringFrom("abc"),
 				AssetIssuer:     null.StringFrom("def"),
+				AssetID:         null.IntFrom(-3107237761707155200),
 				Amount:          9.999999999999999e-06,
 				AmountRaw:       "100",
 				ContractID:      "contractaddress",
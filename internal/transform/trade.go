@@ -3,6 +3,7 @@ package transform
 import (
 	"fmt"
 	"math"
+	"math/big"
 	"time"
 
 	"github.com/guregu/null"
@@ -17,8 +18,9 @@ import (
 	"github.com/stellar/stellar-etl/v2/internal/utils"
 )
 
-// TransformTrade converts a relevant operation from the history archive ingestion system into a form suitable for BigQuery
-func TransformTrade(operationIndex int32, operationID int64, transaction ingest.LedgerTransaction, ledgerCloseTime time.Time) ([]TradeOutput, error) {
+// TransformTrade converts a relevant operation from the history archive ingestion system into a form suitable for BigQuery.
+// The network passphrase is used to derive the contract ids of the traded assets.
+func TransformTrade(operationIndex int32, operationID int64, transaction ingest.LedgerTransaction, ledgerCloseTime time.Time, network string) ([]TradeOutput, error) {
 	operationResults, ok := transaction.Result.OperationResults()
 	if !ok {
 		return []TradeOutput{}, fmt.Errorf("could not get any results from this transaction")
@@ -29,6 +31,7 @@ func TransformTrade(operationIndex int32, operationID int64, transaction ingest.
 	}
 
 	operation := transaction.Envelope.Operations()[operationIndex]
+	changes := NewTransactionCache(transaction)
 	// operation id is +1 incremented to stay in sync with ingest package
 	outputOperationID := operationID + 1
 	claimedOffers, BuyingOffer, sellerIsExact, err := extractClaimedOffers(operationResults, operationIndex, operation.Body.Type)
@@ -47,7 +50,11 @@ func TransformTrade(operationIndex int32, operationID int64, transaction ingest.
 		if err != nil {
 			return []TradeOutput{}, err
 		}
-		outputSellingAssetID := FarmHashAsset(outputSellingAssetCode, outputSellingAssetIssuer, outputSellingAssetType)
+		outputSellingAssetID := utils.FarmHashAsset(outputSellingAssetCode, outputSellingAssetIssuer, outputSellingAssetType)
+		outputSellingAssetContractID, err := utils.AssetContractID(claimOffer.AssetSold(), network)
+		if err != nil {
+			return []TradeOutput{}, err
+		}
 
 		outputSellingAmount := claimOffer.AmountSold()
 		if outputSellingAmount < 0 {
@@ -59,7 +66,11 @@ func TransformTrade(operationIndex int32, operationID int64, transaction ingest.
 		if err != nil {
 			return []TradeOutput{}, err
 		}
-		outputBuyingAssetID := FarmHashAsset(outputBuyingAssetCode, outputBuyingAssetIssuer, outputBuyingAssetType)
+		outputBuyingAssetID := utils.FarmHashAsset(outputBuyingAssetCode, outputBuyingAssetIssuer, outputBuyingAssetType)
+		outputBuyingAssetContractID, err := utils.AssetContractID(claimOffer.AssetBought(), network)
+		if err != nil {
+			return []TradeOutput{}, err
+		}
 
 		outputBuyingAmount := int64(claimOffer.AmountBought())
 		if outputBuyingAmount < 0 {
@@ -72,7 +83,7 @@ func TransformTrade(operationIndex int32, operationID int64, transaction ingest.
 		}
 
 		// Final price should be buy / sell
-		outputPriceN, outputPriceD, err := findTradeSellPrice(transaction, operationIndex, claimOffer)
+		outputPriceN, outputPriceD, err := findTradeSellPrice(changes, operationIndex, claimOffer)
 		if err != nil {
 			return []TradeOutput{}, err
 		}
@@ -92,12 +103,12 @@ func TransformTrade(operationIndex int32, operationID int64, transaction ingest.
 			liquidityPoolID = null.StringFrom(PoolIDToString(id))
 			tradeType = int32(2)
 			var fee uint32
-			if fee, err = findPoolFee(transaction, operationIndex, id); err != nil {
+			if fee, err = findPoolFee(changes, operationIndex, id); err != nil {
 				return []TradeOutput{}, fmt.Errorf("cannot parse fee for liquidity pool %v", liquidityPoolID)
 			}
 			outputPoolFee = null.IntFrom(int64(fee))
 
-			change, err := liquidityPoolChange(transaction, operationIndex, claimOffer)
+			change, err := liquidityPoolChange(changes, operationIndex, claimOffer)
 			if err != nil {
 				return nil, err
 			}
@@ -119,13 +130,21 @@ func TransformTrade(operationIndex int32, operationID int64, transaction ingest.
 			outputBuyingOfferID = null.IntFrom(toid.EncodeOfferId(uint64(operationID)+1, toid.TOIDType))
 		}
 
-		var outputBuyingAccountAddress string
-		if buyer := operation.SourceAccount; buyer != nil {
-			accid := buyer.ToAccountId()
-			outputBuyingAccountAddress = accid.Address()
-		} else {
-			sa := transaction.Envelope.SourceAccount().ToAccountId()
-			outputBuyingAccountAddress = sa.Address()
+		buyer := transaction.Envelope.SourceAccount()
+		if operation.SourceAccount != nil {
+			buyer = *operation.SourceAccount
+		}
+		buyerAccountID := buyer.ToAccountId()
+		outputBuyingAccountAddress := buyerAccountID.Address()
+
+		// Offers are owned by account ids, so only the buyer can be muxed
+		var outputBuyingAccountAddressMuxed null.String
+		if buyer.Type == xdr.CryptoKeyTypeKeyTypeMuxedEd25519 {
+			muxedAddress, err := buyer.GetAddress()
+			if err != nil {
+				return []TradeOutput{}, err
+			}
+			outputBuyingAccountAddressMuxed = null.StringFrom(muxedAddress)
 		}
 
 		trade := TradeOutput{
@@ -154,13 +173,72 @@ func TransformTrade(operationIndex int32, operationID int64, transaction ingest.
 			RoundingSlippage:             roundingSlippageBips,
 			SellerIsExact:                sellerIsExact,
 			SellingLiquidityPoolIDStrkey: liquidityPoolIDStrkey,
+			BuyingAccountAddressMuxed:    outputBuyingAccountAddressMuxed,
+			Price:                        priceString(outputPriceN, outputPriceD),
+			SellingAssetContractID:       null.NewString(outputSellingAssetContractID, outputSellingAssetContractID != ""),
+			BuyingAssetContractID:        null.NewString(outputBuyingAssetContractID, outputBuyingAssetContractID != ""),
 		}
+		normalizeTradeSides(&trade, claimOffer.AssetSold(), claimOffer.AssetBought(), outputSellingAmount, xdr.Int64(outputBuyingAmount))
 
 		transformedTrades = append(transformedTrades, trade)
 	}
 	return transformedTrades, nil
 }
 
+// normalizeTradeSides assigns the sides of a trade to a base and counter asset the way Horizon does, so that trades of
+// the same pair are comparable whichever side sold. The base is the lesser asset in the canonical asset order, and the
+// base price is the price of the base asset in units of the counter asset.
+func normalizeTradeSides(trade *TradeOutput, soldAsset, boughtAsset xdr.Asset, amountSold, amountBought xdr.Int64) {
+	trade.BaseIsSeller = !boughtAsset.LessThan(soldAsset)
+	if trade.BaseIsSeller {
+		trade.BaseAccountAddress = trade.SellingAccountAddress
+		trade.BaseAssetCode = trade.SellingAssetCode
+		trade.BaseAssetIssuer = trade.SellingAssetIssuer
+		trade.BaseAssetType = trade.SellingAssetType
+		trade.BaseAssetID = trade.SellingAssetID
+		trade.BaseAmount = utils.ConvertStroopValueToReal(amountSold)
+		trade.BaseOfferID = trade.SellingOfferID
+		trade.BaseLiquidityPoolID = trade.SellingLiquidityPoolID
+		trade.CounterAccountAddress = trade.BuyingAccountAddress
+		trade.CounterAssetCode = trade.BuyingAssetCode
+		trade.CounterAssetIssuer = trade.BuyingAssetIssuer
+		trade.CounterAssetType = trade.BuyingAssetType
+		trade.CounterAssetID = trade.BuyingAssetID
+		trade.CounterAmount = utils.ConvertStroopValueToReal(amountBought)
+		trade.CounterOfferID = trade.BuyingOfferID
+		trade.BasePriceN = trade.PriceN
+		trade.BasePriceD = trade.PriceD
+	} else {
+		trade.BaseAccountAddress = trade.BuyingAccountAddress
+		trade.BaseAssetCode = trade.BuyingAssetCode
+		trade.BaseAssetIssuer = trade.BuyingAssetIssuer
+		trade.BaseAssetType = trade.BuyingAssetType
+		trade.BaseAssetID = trade.BuyingAssetID
+		trade.BaseAmount = utils.ConvertStroopValueToReal(amountBought)
+		trade.BaseOfferID = trade.BuyingOfferID
+		trade.CounterAccountAddress = trade.SellingAccountAddress
+		trade.CounterAssetCode = trade.SellingAssetCode
+		trade.CounterAssetIssuer = trade.SellingAssetIssuer
+		trade.CounterAssetType = trade.SellingAssetType
+		trade.CounterAssetID = trade.SellingAssetID
+		trade.CounterAmount = utils.ConvertStroopValueToReal(amountSold)
+		trade.CounterOfferID = trade.SellingOfferID
+		trade.CounterLiquidityPoolID = trade.SellingLiquidityPoolID
+		trade.BasePriceN = trade.PriceD
+		trade.BasePriceD = trade.PriceN
+	}
+	trade.BasePrice = priceString(trade.BasePriceN, trade.BasePriceD)
+}
+
+// priceString returns a rational price as a decimal string with the 7 digit precision of Stellar amounts, or an empty
+// string if the price has no denominator
+func priceString(n, d int64) string {
+	if d == 0 {
+		return ""
+	}
+	return big.NewRat(n, d).FloatString(7)
+}
+
 func extractClaimedOffers(operationResults []xdr.OperationResult, operationIndex int32, operationType xdr.OperationType) (claimedOffers []xdr.ClaimAtom, BuyingOffer *xdr.OfferEntry, sellerIsExact null.Bool, err error) {
 	if operationIndex >= int32(len(operationResults)) {
 		err = fmt.Errorf("operation index of %d is out of bounds in result slice (len = %d)", operationIndex, len(operationResults))
@@ -261,7 +339,7 @@ func extractClaimedOffers(operationResults []xdr.OperationResult, operationIndex
 	return
 }
 
-func findTradeSellPrice(t ingest.LedgerTransaction, operationIndex int32, trade xdr.ClaimAtom) (n, d int64, err error) {
+func findTradeSellPrice(changes *TransactionCache, operationIndex int32, trade xdr.ClaimAtom) (n, d int64, err error) {
 	if trade.Type == xdr.ClaimAtomTypeClaimAtomTypeLiquidityPool {
 		return int64(trade.AmountBought()), int64(trade.AmountSold()), nil
 	}
@@ -270,7 +348,7 @@ func findTradeSellPrice(t ingest.LedgerTransaction, operationIndex int32, trade
 	if err := key.SetOffer(trade.SellerId(), uint64(trade.OfferId())); err != nil {
 		return 0, 0, errors.Wrap(err, "Could not create offer ledger key")
 	}
-	change, err := findLatestOperationChange(t, operationIndex, key)
+	change, err := findLatestOperationChange(changes, operationIndex, key)
 	if err != nil {
 		return 0, 0, errors.Wrap(err, "could not find change for trade offer")
 	}
@@ -278,8 +356,8 @@ func findTradeSellPrice(t ingest.LedgerTransaction, operationIndex int32, trade
 	return int64(change.Pre.Data.MustOffer().Price.N), int64(change.Pre.Data.MustOffer().Price.D), nil
 }
 
-func findLatestOperationChange(t ingest.LedgerTransaction, operationIndex int32, key xdr.LedgerKey) (ingest.Change, error) {
-	changes, err := t.GetOperationChanges(uint32(operationIndex))
+func findLatestOperationChange(cache *TransactionCache, operationIndex int32, key xdr.LedgerKey) (ingest.Change, error) {
+	changes, err := cache.operationChanges(uint32(operationIndex))
 	if err != nil {
 		return ingest.Change{}, errors.Wrap(err, "could not determine changes for operation")
 	}
@@ -303,12 +381,12 @@ func findLatestOperationChange(t ingest.LedgerTransaction, operationIndex int32,
 	return ingest.Change{}, errors.Errorf("could not find operation for key %v", key)
 }
 
-func findPoolFee(t ingest.LedgerTransaction, operationIndex int32, poolID xdr.PoolId) (fee uint32, err error) {
+func findPoolFee(changes *TransactionCache, operationIndex int32, poolID xdr.PoolId) (fee uint32, err error) {
 	key := xdr.LedgerKey{}
 	if err := key.SetLiquidityPool(poolID); err != nil {
 		return 0, errors.Wrap(err, "Could not create liquidity pool ledger key")
 	}
-	change, err := findLatestOperationChange(t, operationIndex, key)
+	change, err := findLatestOperationChange(changes, operationIndex, key)
 	if err != nil {
 		return 0, errors.Wrap(err, "could not find change for liquidity pool")
 	}
@@ -316,7 +394,7 @@ func findPoolFee(t ingest.LedgerTransaction, operationIndex int32, poolID xdr.Po
 	return uint32(change.Pre.Data.MustLiquidityPool().Body.MustConstantProduct().Params.Fee), nil
 }
 
-func liquidityPoolChange(t ingest.LedgerTransaction, operationIndex int32, trade xdr.ClaimAtom) (*ingest.Change, error) {
+func liquidityPoolChange(changes *TransactionCache, operationIndex int32, trade xdr.ClaimAtom) (*ingest.Change, error) {
 	if trade.Type != xdr.ClaimAtomTypeClaimAtomTypeLiquidityPool {
 		return nil, nil
 	}
@@ -328,7 +406,7 @@ func liquidityPoolChange(t ingest.LedgerTransaction, operationIndex int32, trade
 		return nil, errors.Wrap(err, "Could not create liquidity pool ledger key")
 	}
 
-	change, err := findLatestOperationChange(t, operationIndex, key)
+	change, err := findLatestOperationChange(changes, operationIndex, key)
 	if err != nil {
 		return nil, errors.Wrap(err, "Could not find change for liquidity pool")
 	}
@@ -169,7 +169,7 @@ func TestTransformTrade(t *testing.T) {
 	}
 
 	for _, test := range tests {
-		actualOutput, actualError := TransformTrade(test.input.index, 100, test.input.transaction, test.input.closeTime)
+		actualOutput, actualError := TransformTrade(test.input.index, 100, test.input.transaction, test.input.closeTime, "")
 		assert.Equal(t, test.wantErr, actualError)
 		assert.Equal(t, test.wantOutput, actualOutput)
 	}
@@ -195,6 +195,13 @@ func makeTradeTestInput() (inputTransaction ingest.LedgerTransaction) {
 	inputEnvelope := genericBumpOperationEnvelope
 
 	inputEnvelope.Tx.SourceAccount = testAccount3
+	muxedBuyer := xdr.MuxedAccount{
+		Type: xdr.CryptoKeyTypeKeyTypeMuxedEd25519,
+		Med25519: &xdr.MuxedAccountMed25519{
+			Id:      0xcafebabe,
+			Ed25519: *testAccount3ID.Ed25519,
+		},
+	}
 	offerOne := xdr.ClaimAtom{
 		Type: xdr.ClaimAtomTypeClaimAtomTypeOrderBook,
 		OrderBook: &xdr.ClaimOfferAtom{
@@ -264,7 +271,7 @@ func makeTradeTestInput() (inputTransaction ingest.LedgerTransaction) {
 			},
 		},
 		{
-			SourceAccount: &testAccount3,
+			SourceAccount: &muxedBuyer,
 			Body: xdr.OperationBody{
 				Type: xdr.OperationTypePathPaymentStrictReceive,
 				PathPaymentStrictReceiveOp: &xdr.PathPaymentStrictReceiveOp{
@@ -718,6 +725,7 @@ func makeTradeTestInput() (inputTransaction ingest.LedgerTransaction) {
 }
 
 func makeTradeTestOutput() [][]TradeOutput {
+	testAccount3MuxedAddress := "MBT4YAEGJQ5YSFUMNKX6BPBUOCPNAIOFAVZOF6MIME2CECBMEIUXEAAAAAAMV7V2X3RXU"
 	offerOneOutput := TradeOutput{
 		Order:                 0,
 		LedgerClosedAt:        genericCloseTime,
@@ -739,6 +747,25 @@ func makeTradeTestOutput() [][]TradeOutput {
 		BuyingOfferID:         null.IntFrom(4611686018427388005),
 		HistoryOperationID:    101,
 		TradeType:             1,
+		Price:                 "0.0009499",
+		BaseIsSeller:          true,
+		BaseAccountAddress:    testAccount1Address,
+		BaseAssetCode:         "ETH",
+		BaseAssetIssuer:       testAccount3Address,
+		BaseAssetType:         "credit_alphanum4",
+		BaseAssetID:           4476940172956910889,
+		BaseAmount:            13300347 * 0.0000001,
+		BaseOfferID:           null.IntFrom(97684906),
+		CounterAccountAddress: testAccount3Address,
+		CounterAssetCode:      "USDT",
+		CounterAssetIssuer:    testAccount4Address,
+		CounterAssetType:      "credit_alphanum4",
+		CounterAssetID:        -8205667356306085451,
+		CounterAmount:         12634 * 0.0000001,
+		CounterOfferID:        null.IntFrom(4611686018427388005),
+		BasePriceN:            12634,
+		BasePriceD:            13300347,
+		BasePrice:             "0.0009499",
 	}
 	offerTwoOutput := TradeOutput{
 		Order:                 0,
@@ -761,6 +788,23 @@ func makeTradeTestOutput() [][]TradeOutput {
 		BuyingOfferID:         null.IntFrom(4611686018427388005),
 		HistoryOperationID:    101,
 		TradeType:             1,
+		Price:                 "25.0000000",
+		BaseIsSeller:          false,
+		BaseAccountAddress:    testAccount3Address,
+		BaseAssetType:         "native",
+		BaseAssetID:           -5706705804583548011,
+		BaseAmount:            20 * 0.0000001,
+		BaseOfferID:           null.IntFrom(4611686018427388005),
+		CounterAccountAddress: testAccount3Address,
+		CounterAssetCode:      "USDT",
+		CounterAssetIssuer:    testAccount4Address,
+		CounterAssetType:      "credit_alphanum4",
+		CounterAssetID:        -8205667356306085451,
+		CounterAmount:         500 * 0.0000001,
+		CounterOfferID:        null.IntFrom(86106895),
+		BasePriceN:            1,
+		BasePriceD:            25,
+		BasePrice:             "0.0400000",
 	}
 
 	lPOneOutput := TradeOutput{
@@ -787,6 +831,24 @@ func makeTradeTestOutput() [][]TradeOutput {
 		RoundingSlippage:             null.IntFrom(0),
 		SellerIsExact:                null.BoolFrom(false),
 		SellingLiquidityPoolIDStrkey: null.StringFrom("LACAKBQAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAGOE"),
+		Price:                        "3.7073171",
+		BaseIsSeller:                 false,
+		BaseAccountAddress:           testAccount3Address,
+		BaseAssetCode:                "NIJ",
+		BaseAssetIssuer:              testAccount1Address,
+		BaseAssetType:                "credit_alphanum4",
+		BaseAssetID:                  -8061435944444096568,
+		BaseAmount:                   456 * 0.0000001,
+		BaseOfferID:                  null.IntFrom(4611686018427388005),
+		CounterAssetCode:             "WER",
+		CounterAssetIssuer:           testAccount4Address,
+		CounterAssetType:             "credit_alphanum4",
+		CounterAssetID:               -7615773297180926952,
+		CounterAmount:                123 * 0.0000001,
+		CounterLiquidityPoolID:       null.StringFrom("0405060000000000000000000000000000000000000000000000000000000000"),
+		BasePriceN:                   123,
+		BasePriceD:                   456,
+		BasePrice:                    "0.2697368",
 	}
 
 	lPTwoOutput := TradeOutput{
@@ -813,6 +875,24 @@ func makeTradeTestOutput() [][]TradeOutput {
 		RoundingSlippage:             null.IntFrom(9223372036854775807),
 		SellerIsExact:                null.BoolFrom(true),
 		SellingLiquidityPoolIDStrkey: null.StringFrom("LAAQEAYEAUDAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAABUTF"),
+		Price:                        "1.0000000",
+		BaseIsSeller:                 true,
+		BaseAssetCode:                "HAH",
+		BaseAssetIssuer:              testAccount1Address,
+		BaseAssetType:                "credit_alphanum4",
+		BaseAssetID:                  -6231594281606355691,
+		BaseAmount:                   1 * 0.0000001,
+		BaseLiquidityPoolID:          null.StringFrom("0102030405060000000000000000000000000000000000000000000000000000"),
+		CounterAccountAddress:        testAccount3Address,
+		CounterAssetCode:             "WHO",
+		CounterAssetIssuer:           testAccount4Address,
+		CounterAssetType:             "credit_alphanum4",
+		CounterAssetID:               -680582465233747022,
+		CounterAmount:                1 * 0.0000001,
+		CounterOfferID:               null.IntFrom(4611686018427388005),
+		BasePriceN:                   1,
+		BasePriceD:                   1,
+		BasePrice:                    "1.0000000",
 	}
 
 	onePriceIsAmount := offerOneOutput
@@ -823,11 +903,19 @@ func makeTradeTestOutput() [][]TradeOutput {
 	offerOneOutputSecondPlace := onePriceIsAmount
 	offerOneOutputSecondPlace.Order = 1
 	offerOneOutputSecondPlace.SellerIsExact = null.BoolFrom(true)
+	offerOneOutputSecondPlace.BuyingAccountAddressMuxed = null.StringFrom(testAccount3MuxedAddress)
 
 	twoPriceIsAmount := offerTwoOutput
 	twoPriceIsAmount.PriceN = int64(twoPriceIsAmount.BuyingAmount * 10000000)
 	twoPriceIsAmount.PriceD = int64(twoPriceIsAmount.SellingAmount * 10000000)
 	twoPriceIsAmount.SellerIsExact = null.BoolFrom(true)
+	twoPriceIsAmount.Price = "0.0400000"
+	twoPriceIsAmount.BasePriceN = 500
+	twoPriceIsAmount.BasePriceD = 20
+	twoPriceIsAmount.BasePrice = "25.0000000"
+
+	twoPriceIsAmountMuxedBuyer := twoPriceIsAmount
+	twoPriceIsAmountMuxedBuyer.BuyingAccountAddressMuxed = null.StringFrom(testAccount3MuxedAddress)
 
 	offerTwoOutputSecondPlace := twoPriceIsAmount
 	offerTwoOutputSecondPlace.Order = 1
@@ -837,7 +925,7 @@ func makeTradeTestOutput() [][]TradeOutput {
 		{offerOneOutput},
 		{offerTwoOutput},
 		{onePriceIsAmount, offerTwoOutputSecondPlace},
-		{twoPriceIsAmount, offerOneOutputSecondPlace},
+		{twoPriceIsAmountMuxedBuyer, offerOneOutputSecondPlace},
 		{lPOneOutput},
 		{lPTwoOutput},
 		{},
@@ -104,10 +104,23 @@ func TransformTransaction(transaction ingest.LedgerTransaction, lhe xdr.LedgerHe
 
 	}
 
+	// The preconditions are checked against the close time and sequence of the ledger the transaction was applied in.
+	// They are null if the transaction doesn't have the bounds.
+	closeTime := uint64(ledgerHeader.ScpValue.CloseTime)
+	outputValidAfter := null.Bool{}
+	outputValidBefore := null.Bool{}
+	if timeBound != nil {
+		outputValidAfter = null.BoolFrom(closeTime >= uint64(timeBound.MinTime))
+		outputValidBefore = null.BoolFrom(timeBound.MaxTime == 0 || closeTime <= uint64(timeBound.MaxTime))
+	}
+
 	ledgerBound := transaction.Envelope.LedgerBounds()
 	outputLedgerBound := ""
+	outputWithinLedgerBounds := null.Bool{}
 	if ledgerBound != nil {
 		outputLedgerBound = fmt.Sprintf("[%d,%d)", int64(ledgerBound.MinLedger), int64(ledgerBound.MaxLedger))
+		outputWithinLedgerBounds = null.BoolFrom(outputLedgerSequence >= uint32(ledgerBound.MinLedger) &&
+			(ledgerBound.MaxLedger == 0 || outputLedgerSequence < uint32(ledgerBound.MaxLedger)))
 	}
 
 	minSequenceNumber := transaction.Envelope.MinSeqNum()
@@ -142,6 +155,7 @@ func TransformTransaction(transaction ingest.LedgerTransaction, lhe xdr.LedgerHe
 	var outputInclusionFeeBid int64
 	var outputInclusionFeeCharged int64
 	var outputResourceFeeRefund int64
+	var outputResourceFeeCharged int64
 	var outputTotalNonRefundableResourceFeeCharged int64
 	var outputTotalRefundableResourceFeeCharged int64
 	var outputRentFeeCharged int64
@@ -192,11 +206,17 @@ func TransformTransaction(transaction ingest.LedgerTransaction, lhe xdr.LedgerHe
 			}
 		}
 
-		// For P23 onwards, transaction meta v3 will be empty
+		// For P23 onwards, transaction meta v3 will be empty. The refund is applied after all the transactions of the
+		// ledger, so it is in the PostTxApplyFeeChanges instead of TxChangesAfter, and it is emitted as a fee event.
 		metav4, ok := transaction.UnsafeMeta.GetV4()
 		if ok {
-			accountBalanceStart, accountBalanceEnd := getAccountBalanceFromLedgerEntryChanges(metav4.TxChangesAfter, feeAccountAddress)
-			outputResourceFeeRefund = accountBalanceEnd - accountBalanceStart
+			if refund, found := feeEventRefund(metav4.Events); found {
+				outputResourceFeeRefund = refund
+			} else {
+				changesAfter := append(append(xdr.LedgerEntryChanges{}, metav4.TxChangesAfter...), transaction.PostTxApplyFeeChanges...)
+				accountBalanceStart, accountBalanceEnd := getAccountBalanceFromLedgerEntryChanges(changesAfter, feeAccountAddress)
+				outputResourceFeeRefund = accountBalanceEnd - accountBalanceStart
+			}
 			if metav4.SorobanMeta != nil {
 				extV1, ok := metav4.SorobanMeta.Ext.GetV1()
 				if ok {
@@ -212,6 +232,8 @@ func TransformTransaction(transaction ingest.LedgerTransaction, lhe xdr.LedgerHe
 		if ledgerHeader.LedgerVersion < 21 && transaction.Envelope.Type == xdr.EnvelopeTypeEnvelopeTypeTxFeeBump {
 			outputFeeCharged = outputResourceFee - outputResourceFeeRefund
 		}
+
+		outputResourceFeeCharged = outputResourceFee - outputResourceFeeRefund
 	}
 
 	outputCloseTime, err := utils.TimePointToUTCTimeStamp(ledgerHeader.ScpValue.CloseTime)
@@ -226,6 +248,11 @@ func TransformTransaction(transaction ingest.LedgerTransaction, lhe xdr.LedgerHe
 		return TransactionOutput{}, err
 	}
 
+	outputContractEventsCount, outputDiagnosticEventsCount, err := countTransactionEvents(transaction)
+	if err != nil {
+		return TransactionOutput{}, fmt.Errorf("for ledger %d; transaction %d (transaction id=%d): %v", outputLedgerSequence, transactionIndex, outputTransactionID, err)
+	}
+
 	outputSuccessful := transaction.Result.Successful()
 	transformedTransaction := TransactionOutput{
 		TransactionHash:                      outputTransactionHash,
@@ -246,6 +273,9 @@ func TransformTransaction(transaction ingest.LedgerTransaction, lhe xdr.LedgerHe
 		TimeBounds:                           outputTimeBounds,
 		Successful:                           outputSuccessful,
 		LedgerBounds:                         outputLedgerBound,
+		ValidAfter:                           outputValidAfter,
+		ValidBefore:                          outputValidBefore,
+		WithinLedgerBounds:                   outputWithinLedgerBounds,
 		MinAccountSequence:                   outputMinSequence,
 		MinAccountSequenceAge:                outputMinSequenceAge,
 		MinAccountSequenceLedgerGap:          outputMinSequenceLedgerGap,
@@ -261,10 +291,15 @@ func TransformTransaction(transaction ingest.LedgerTransaction, lhe xdr.LedgerHe
 		InclusionFeeBid:                      outputInclusionFeeBid,
 		InclusionFeeCharged:                  outputInclusionFeeCharged,
 		ResourceFeeRefund:                    outputResourceFeeRefund,
+		ResourceFeeCharged:                   outputResourceFeeCharged,
 		TotalNonRefundableResourceFeeCharged: outputTotalNonRefundableResourceFeeCharged,
 		TotalRefundableResourceFeeCharged:    outputTotalRefundableResourceFeeCharged,
 		RentFeeCharged:                       outputRentFeeCharged,
 		TxSigners:                            txSigners,
+		ContractEventsCount:                  outputContractEventsCount,
+		DiagnosticEventsCount:                outputDiagnosticEventsCount,
+		IsSoroban:                            hasSorobanData,
+		SorobanResourcesUsed:                 outputResourceFeeCharged > 0,
 	}
 
 	// Add Muxed Account Details, if exists
@@ -300,6 +335,32 @@ func TransformTransaction(transaction ingest.LedgerTransaction, lhe xdr.LedgerHe
 	return transformedTransaction, nil
 }
 
+// feeEventRefund returns the refund of the resource fee in the fee events of a transaction. The fee is emitted as a
+// fee event before the transactions of the ledger are applied, and the refund as a fee event with a negative amount
+// after all of them are applied. found is false if the transaction has no fee events.
+func feeEventRefund(events []xdr.TransactionEvent) (refund int64, found bool) {
+	for _, event := range events {
+		body, ok := event.Event.Body.GetV0()
+		if !ok || len(body.Topics) == 0 {
+			continue
+		}
+		if sym, ok := body.Topics[0].GetSym(); !ok || sym != "fee" {
+			continue
+		}
+		amount, ok := body.Data.GetI128()
+		if !ok {
+			continue
+		}
+
+		found = true
+		if event.Stage == xdr.TransactionEventStageTransactionEventStageAfterAllTxs {
+			refund -= int64(amount.Lo)
+		}
+	}
+
+	return refund, found
+}
+
 func getAccountBalanceFromLedgerEntryChanges(changes xdr.LedgerEntryChanges, sourceAccountAddress string) (int64, int64) {
 	var accountBalanceStart int64
 	var accountBalanceEnd int64
@@ -356,3 +417,23 @@ func getTxSigners(xdrSignatures []xdr.DecoratedSignature) ([]string, error) {
 
 	return signers, nil
 }
+
+// countTransactionEvents returns the number of contract and system events of a transaction and its operations, and the
+// number of its diagnostic events, which are the events export_contract_events exports for the transaction
+func countTransactionEvents(transaction ingest.LedgerTransaction) (int32, int32, error) {
+	// Transactions applied before protocol 10 have V0 meta, which has no events
+	if transaction.UnsafeMeta.V == 0 {
+		return 0, 0, nil
+	}
+
+	events, err := transaction.GetTransactionEvents()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	contractEvents := len(events.TransactionEvents)
+	for _, operationEvents := range events.OperationEvents {
+		contractEvents += len(operationEvents)
+	}
+	return int32(contractEvents), int32(len(events.DiagnosticEvents)), nil
+}
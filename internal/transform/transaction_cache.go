@@ -0,0 +1,71 @@
+package transform
+
+import (
+	"github.com/stellar/go-stellar-sdk/ingest"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+// TransactionCache holds what the transforms of the operations of a transaction read from the transaction more than
+// once. GetOperationChanges walks the meta of the transaction every time it is called, and the details, effects,
+// sponsor and liquidity pool lookups of an operation all read its changes, while the ledger key hashes, contract id and
+// contract code hash of the soroban details are derived from the footprint of the envelope. A cache is shared by the
+// transforms of a transaction, so every operation's meta is walked once and the footprint is hashed once. The cached
+// values are shared, so they must not be modified.
+type TransactionCache struct {
+	transaction ingest.LedgerTransaction
+	changes     map[uint32][]ingest.Change
+	soroban     *sorobanFootprint
+}
+
+// sorobanFootprint is what the details of soroban operations read from the footprint of their transaction
+type sorobanFootprint struct {
+	ledgerKeyHashes  []string
+	contractID       string
+	contractCodeHash string
+}
+
+// NewTransactionCache creates the cache of a transaction, which is filled as the transforms read it
+func NewTransactionCache(transaction ingest.LedgerTransaction) *TransactionCache {
+	return &TransactionCache{
+		transaction: transaction,
+		changes:     map[uint32][]ingest.Change{},
+	}
+}
+
+// TransactionIndex returns the index of the transaction of the cache in its ledger
+func (c *TransactionCache) TransactionIndex() uint32 {
+	return c.transaction.Index
+}
+
+// TransformOperation converts an operation of the transaction of the cache like the TransformOperation function
+func (c *TransactionCache) TransformOperation(operation xdr.Operation, operationIndex int32, ledgerSeq int32, ledgerCloseMeta xdr.LedgerCloseMeta, network string) (OperationOutput, error) {
+	return transformOperation(operation, operationIndex, c.transaction, c, ledgerSeq, ledgerCloseMeta, network, true)
+}
+
+// operationChanges returns the changes of the operation at an index of the transaction
+func (c *TransactionCache) operationChanges(index uint32) ([]ingest.Change, error) {
+	if changes, ok := c.changes[index]; ok {
+		return changes, nil
+	}
+
+	changes, err := c.transaction.GetOperationChanges(index)
+	if err != nil {
+		return nil, err
+	}
+	c.changes[index] = changes
+	return changes, nil
+}
+
+// footprint returns the ledger key hashes, contract id and contract code hash of the footprint of the transaction. It
+// must only be called for soroban transactions.
+func (c *TransactionCache) footprint() *sorobanFootprint {
+	if c.soroban == nil {
+		envelope := getTransactionV1Envelope(c.transaction.Envelope)
+		c.soroban = &sorobanFootprint{
+			ledgerKeyHashes:  ledgerKeyHashFromTxEnvelope(envelope),
+			contractID:       contractIdFromTxEnvelope(envelope),
+			contractCodeHash: contractCodeHashFromTxEnvelope(envelope),
+		}
+	}
+	return c.soroban
+}
@@ -0,0 +1,69 @@
+package transform
+
+import (
+	"testing"
+
+	"github.com/stellar/go-stellar-sdk/ingest"
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransactionCache(t *testing.T) {
+	data := xdr.LedgerEntry{Data: xdr.LedgerEntryData{
+		Type: xdr.LedgerEntryTypeData,
+		Data: &xdr.DataEntry{AccountId: testAccount1ID, DataName: "name", DataValue: xdr.DataValue("value")},
+	}}
+	transaction := ingest.LedgerTransaction{UnsafeMeta: xdr.TransactionMeta{V: 1, V1: &xdr.TransactionMetaV1{
+		Operations: []xdr.OperationMeta{
+			{Changes: xdr.LedgerEntryChanges{{Type: xdr.LedgerEntryChangeTypeLedgerEntryCreated, Created: &data}}},
+			{},
+		},
+	}}}
+
+	cache := NewTransactionCache(transaction)
+	changes, err := cache.operationChanges(0)
+	assert.NoError(t, err)
+	expected, err := transaction.GetOperationChanges(0)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, changes)
+
+	// The second lookup returns the cached changes instead of walking the meta again
+	cached, err := cache.operationChanges(0)
+	assert.NoError(t, err)
+	assert.Same(t, &changes[0], &cached[0])
+
+	changes, err = cache.operationChanges(1)
+	assert.NoError(t, err)
+	assert.Empty(t, changes)
+
+	_, err = NewTransactionCache(ingest.LedgerTransaction{}).operationChanges(0)
+	assert.EqualError(t, err, "TransactionMeta.V=0 not supported")
+}
+
+func TestTransactionCacheFootprint(t *testing.T) {
+	contractID := xdr.ContractId{1}
+	codeHash := xdr.Hash{2}
+	contractData := xdr.LedgerKey{Type: xdr.LedgerEntryTypeContractData, ContractData: &xdr.LedgerKeyContractData{
+		Contract: xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: &contractID},
+		Key:      xdr.ScVal{Type: xdr.ScValTypeScvLedgerKeyContractInstance},
+	}}
+	contractCode := xdr.LedgerKey{Type: xdr.LedgerEntryTypeContractCode, ContractCode: &xdr.LedgerKeyContractCode{Hash: codeHash}}
+	transaction := ingest.LedgerTransaction{Envelope: xdr.TransactionEnvelope{
+		Type: xdr.EnvelopeTypeEnvelopeTypeTx,
+		V1: &xdr.TransactionV1Envelope{Tx: xdr.Transaction{Ext: xdr.TransactionExt{V: 1, SorobanData: &xdr.SorobanTransactionData{
+			Resources: xdr.SorobanResources{Footprint: xdr.LedgerFootprint{
+				ReadOnly:  []xdr.LedgerKey{contractCode},
+				ReadWrite: []xdr.LedgerKey{contractData},
+			}},
+		}}}},
+	}}
+
+	cache := NewTransactionCache(transaction)
+	footprint := cache.footprint()
+	envelope := transaction.Envelope.MustV1()
+	assert.Equal(t, ledgerKeyHashFromTxEnvelope(envelope), footprint.ledgerKeyHashes)
+	assert.Len(t, footprint.ledgerKeyHashes, 2)
+	assert.Equal(t, "CAAQAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAABDQF", footprint.contractID)
+	assert.Equal(t, codeHash.HexString(), footprint.contractCodeHash)
+	assert.Same(t, footprint, cache.footprint())
+}
@@ -79,6 +79,46 @@ func TestTransformTransaction(t *testing.T) {
 	}
 }
 
+func TestFeeEventRefund(t *testing.T) {
+	feeEvent := func(stage xdr.TransactionEventStage, topic string, amount int64) xdr.TransactionEvent {
+		sym := xdr.ScSymbol(topic)
+		hi := xdr.Int64(0)
+		if amount < 0 {
+			hi = -1
+		}
+		return xdr.TransactionEvent{
+			Stage: stage,
+			Event: xdr.ContractEvent{
+				Type: xdr.ContractEventTypeContract,
+				Body: xdr.ContractEventBody{
+					V: 0,
+					V0: &xdr.ContractEventV0{
+						Topics: []xdr.ScVal{{Type: xdr.ScValTypeScvSymbol, Sym: &sym}},
+						Data:   xdr.ScVal{Type: xdr.ScValTypeScvI128, I128: &xdr.Int128Parts{Hi: hi, Lo: xdr.Uint64(uint64(amount))}},
+					},
+				},
+			},
+		}
+	}
+
+	refund, found := feeEventRefund([]xdr.TransactionEvent{
+		feeEvent(xdr.TransactionEventStageTransactionEventStageBeforeAllTxs, "fee", 1100),
+		feeEvent(xdr.TransactionEventStageTransactionEventStageAfterTx, "transfer", 500),
+		feeEvent(xdr.TransactionEventStageTransactionEventStageAfterAllTxs, "fee", -300),
+	})
+	assert.True(t, found)
+	assert.Equal(t, int64(300), refund)
+
+	refund, found = feeEventRefund([]xdr.TransactionEvent{
+		feeEvent(xdr.TransactionEventStageTransactionEventStageBeforeAllTxs, "fee", 1100),
+	})
+	assert.True(t, found)
+	assert.Equal(t, int64(0), refund)
+
+	_, found = feeEventRefund(nil)
+	assert.False(t, found)
+}
+
 func makeTransactionTestOutput() (output []TransactionOutput, err error) {
 	correctTime, err := time.Parse("2006-1-2 15:04:05 MST", "2020-07-09 05:28:42 UTC")
 	output = []TransactionOutput{
@@ -99,6 +139,8 @@ func makeTransactionTestOutput() (output []TransactionOutput, err error) {
 			MemoType:                      "MemoTypeMemoText",
 			Memo:                          "HL5aCgozQHIW7sSc5XdcfmR",
 			TimeBounds:                    "[0,1594272628)",
+			ValidAfter:                    null.BoolFrom(true),
+			ValidBefore:                   null.BoolFrom(true),
 			Successful:                    false,
 			ClosedAt:                      time.Date(2020, time.July, 9, 5, 28, 42, 0, time.UTC),
 			ResourceFee:                   0,
@@ -126,6 +168,8 @@ func makeTransactionTestOutput() (output []TransactionOutput, err error) {
 			MemoType:                      "MemoTypeMemoText",
 			Memo:                          "HL5aCgozQHIW7sSc5XdcfmR",
 			TimeBounds:                    "[0,1594272628)",
+			ValidAfter:                    null.BoolFrom(true),
+			ValidBefore:                   null.BoolFrom(true),
 			Successful:                    true,
 			InnerTransactionHash:          "a87fef5eeb260269c380f2de456aad72b59bb315aaac777860456e09dac0bafb",
 			FeeAccount:                    testAccount5Address,
@@ -139,8 +183,11 @@ func makeTransactionTestOutput() (output []TransactionOutput, err error) {
 			SorobanResourcesWriteBytes:    0,
 			TransactionResultCode:         "TransactionResultCodeTxFeeBumpInnerSuccess", //inner fee bump success
 			TxSigners:                     []string{"GD2GXC24XWOM6T2UHABEMSYW5UZGJ4U7WEN7AQT2WYW32TQFP4ND3M7O4VGCBTT2BWNILFEVDX5DBBBMK2RTQIBMJNL6F62MAQ53NBAIXUDA"},
+			IsSoroban:                     true,
+			SorobanResourcesUsed:          true,
 			InclusionFeeBid:               6200,
 			InclusionFeeCharged:           50,
+			ResourceFeeCharged:            1000,
 		},
 		{
 			TxEnvelope:                    "AAAAAgAAAAAcR0GXGO76pFs4y38vJVAanjnLg4emNun7zAx0pHcDGAAAAGQBpLyvsiV6gwAAAAIAAAABAAAAAAAAAAAAAAAAXwardAAAAAEAAAAFAAAACgAAAAAAAAAAAAAAAAAAAAAAAAABAAAAAAMCAQAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAQAAABdITDVhQ2dvelFISVc3c1NjNVhkY2ZtUgAAAAABAAAAAQAAAABrWN1saJMLbQMdxbv64j76HsPwu1jCvI2TjUfB37O+cwAAAAIAAAAAAAAAAAAAAAAAAAAAAQIDAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAFjQq+PAAAAQPRri1y9nM9PVDgCRksW7TJk8p+xG/BCerYtvU4Ffxo9s+7lTCDOeg2ahZSVHfowhCxWozggLEtX4vtMBDu2hAg=",
@@ -159,8 +206,11 @@ func makeTransactionTestOutput() (output []TransactionOutput, err error) {
 			MemoType:                      "MemoTypeMemoText",
 			Memo:                          "HL5aCgozQHIW7sSc5XdcfmR",
 			TimeBounds:                    "[0,1594272628)",
+			ValidAfter:                    null.BoolFrom(true),
+			ValidBefore:                   null.BoolFrom(true),
 			Successful:                    false,
 			LedgerBounds:                  "[5,10)",
+			WithinLedgerBounds:            null.BoolFrom(false),
 			ExtraSigners:                  pq.StringArray{"GABQEAIAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAB7QL"},
 			MinAccountSequenceAge:         null.IntFrom(0),
 			MinAccountSequenceLedgerGap:   null.IntFrom(0),
@@ -449,3 +499,24 @@ func makeTransactionTestInput() (transaction []ingest.LedgerTransaction, history
 	}
 	return
 }
+
+func TestCountTransactionEvents(t *testing.T) {
+	transaction := ingest.LedgerTransaction{UnsafeMeta: xdr.TransactionMeta{
+		V: 4,
+		V4: &xdr.TransactionMetaV4{
+			Events:           make([]xdr.TransactionEvent, 2),
+			Operations:       []xdr.OperationMetaV2{{Events: make([]xdr.ContractEvent, 3)}, {}},
+			DiagnosticEvents: make([]xdr.DiagnosticEvent, 4),
+		},
+	}}
+	contractEvents, diagnosticEvents, err := countTransactionEvents(transaction)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(5), contractEvents)
+	assert.Equal(t, int32(4), diagnosticEvents)
+
+	// V0 meta has no events
+	contractEvents, diagnosticEvents, err = countTransactionEvents(ingest.LedgerTransaction{UnsafeMeta: xdr.TransactionMeta{V: 0, Operations: &[]xdr.OperationMeta{}}})
+	assert.NoError(t, err)
+	assert.Equal(t, int32(0), contractEvents)
+	assert.Equal(t, int32(0), diagnosticEvents)
+}
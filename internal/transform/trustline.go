@@ -54,7 +54,7 @@ func TransformTrustline(ledgerChange ingest.Change, header xdr.LedgerHeaderHisto
 		}
 	}
 
-	outputAssetID := FarmHashAsset(outputAssetCode, outputAssetIssuer, asset.Type.String())
+	outputAssetID := utils.FarmHashAsset(outputAssetCode, outputAssetIssuer, assetType)
 
 	liabilities := trustEntry.Liabilities()
 
@@ -63,6 +63,11 @@ func TransformTrustline(ledgerChange ingest.Change, header xdr.LedgerHeaderHisto
 		return TrustlineOutput{}, err
 	}
 
+	ledgerKeyHash, ledgerKeyHashBase64, err := utils.LedgerEntryToLedgerKeyHashes(ledgerEntry)
+	if err != nil {
+		return TrustlineOutput{}, err
+	}
+
 	ledgerSequence := header.Header.LedgerSeq
 
 	transformedTrustline := TrustlineOutput{
@@ -84,6 +89,8 @@ func TransformTrustline(ledgerChange ingest.Change, header xdr.LedgerHeaderHisto
 		Deleted:               outputDeleted,
 		ClosedAt:              closedAt,
 		LedgerSequence:        uint32(ledgerSequence),
+		LedgerKeyHash:         ledgerKeyHash,
+		LedgerKeyHashBase64:   ledgerKeyHashBase64,
 		LiquidityPoolIDStrkey: poolIDStrkey,
 	}
 
@@ -129,28 +129,30 @@ func makeTrustlineTestInput() []ingest.Change {
 func makeTrustlineTestOutput() []TrustlineOutput {
 	return []TrustlineOutput{
 		{
-			LedgerKey:          "AAAAAQAAAACI4aa0pXFSj6qfJuIObLw/5zyugLRGYwxb7wFSr3B9eAAAAAFFVEgAAAAAAGfMAIZMO4kWjGqv4Lw0cJ7QIcUFcuL5iGE0IggsIily",
-			AccountID:          testAccount1Address,
-			AssetType:          "credit_alphanum4",
-			AssetIssuer:        testAccount3Address,
-			AssetCode:          "ETH",
-			AssetID:            -2311386320395871674,
-			Balance:            0.6203,
-			TrustlineLimit:     9000000000000000000,
-			Flags:              1,
-			BuyingLiabilities:  0.0001,
-			SellingLiabilities: 0.0002,
-			LastModifiedLedger: 24229503,
-			LedgerEntryChange:  1,
-			Deleted:            false,
-			LedgerSequence:     10,
-			ClosedAt:           time.Date(1970, time.January, 1, 0, 16, 40, 0, time.UTC),
+			LedgerKey:           "AAAAAQAAAACI4aa0pXFSj6qfJuIObLw/5zyugLRGYwxb7wFSr3B9eAAAAAFFVEgAAAAAAGfMAIZMO4kWjGqv4Lw0cJ7QIcUFcuL5iGE0IggsIily",
+			AccountID:           testAccount1Address,
+			AssetType:           "credit_alphanum4",
+			AssetIssuer:         testAccount3Address,
+			AssetCode:           "ETH",
+			AssetID:             4476940172956910889,
+			Balance:             0.6203,
+			TrustlineLimit:      9000000000000000000,
+			Flags:               1,
+			BuyingLiabilities:   0.0001,
+			SellingLiabilities:  0.0002,
+			LastModifiedLedger:  24229503,
+			LedgerEntryChange:   1,
+			Deleted:             false,
+			LedgerSequence:      10,
+			LedgerKeyHash:       "1d6d13752448051fae239bcb1f0fa0967243e7870ab07f29e34c1a1ab3f82813",
+			LedgerKeyHashBase64: "AAAAAQAAAACI4aa0pXFSj6qfJuIObLw/5zyugLRGYwxb7wFSr3B9eAAAAAFFVEgAAAAAAGfMAIZMO4kWjGqv4Lw0cJ7QIcUFcuL5iGE0IggsIily",
+			ClosedAt:            time.Date(1970, time.January, 1, 0, 16, 40, 0, time.UTC),
 		},
 		{
 			LedgerKey:             "AAAAAQAAAAAcR0GXGO76pFs4y38vJVAanjnLg4emNun7zAx0pHcDGAAAAAMBAwQFBwkAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA==",
 			AccountID:             testAccount2Address,
 			AssetType:             "pool_share",
-			AssetID:               -1967220342708457407,
+			AssetID:               721683941183578983,
 			Balance:               0.5,
 			TrustlineLimit:        1111111111111111111,
 			LiquidityPoolID:       "0103040507090000000000000000000000000000000000000000000000000000",
@@ -161,6 +163,8 @@ func makeTrustlineTestOutput() []TrustlineOutput {
 			LedgerEntryChange:     1,
 			Deleted:               false,
 			LedgerSequence:        10,
+			LedgerKeyHash:         "7aa8e8e5010a9e52f50b88d81b0429b15d30655f0640e07f30dc50a54cd79b49",
+			LedgerKeyHashBase64:   "AAAAAQAAAAAcR0GXGO76pFs4y38vJVAanjnLg4emNun7zAx0pHcDGAAAAAMBAwQFBwkAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA==",
 			ClosedAt:              time.Date(1970, time.January, 1, 0, 16, 40, 0, time.UTC),
 			LiquidityPoolIDStrkey: "LAAQGBAFA4EQAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA2VM",
 		},
@@ -33,16 +33,23 @@ func TransformTtl(ledgerChange ingest.Change, header xdr.LedgerHeaderHistoryEntr
 		return TtlOutput{}, err
 	}
 
+	ledgerKeyHash, ledgerKeyHashBase64, err := utils.LedgerEntryToLedgerKeyHashes(ledgerEntry)
+	if err != nil {
+		return TtlOutput{}, err
+	}
+
 	ledgerSequence := header.Header.LedgerSeq
 
 	transformedPool := TtlOutput{
-		KeyHash:            keyHash,
-		LiveUntilLedgerSeq: uint32(liveUntilLedgerSeq),
-		LastModifiedLedger: uint32(ledgerEntry.LastModifiedLedgerSeq),
-		LedgerEntryChange:  uint32(changeType),
-		Deleted:            outputDeleted,
-		ClosedAt:           closedAt,
-		LedgerSequence:     uint32(ledgerSequence),
+		KeyHash:             keyHash,
+		LiveUntilLedgerSeq:  uint32(liveUntilLedgerSeq),
+		LastModifiedLedger:  uint32(ledgerEntry.LastModifiedLedgerSeq),
+		LedgerEntryChange:   uint32(changeType),
+		Deleted:             outputDeleted,
+		ClosedAt:            closedAt,
+		LedgerSequence:      uint32(ledgerSequence),
+		LedgerKeyHash:       ledgerKeyHash,
+		LedgerKeyHashBase64: ledgerKeyHashBase64,
 	}
 
 	return transformedPool, nil
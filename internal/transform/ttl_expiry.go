@@ -0,0 +1,18 @@
+package transform
+
+// TransformTtlExpiry converts a ttl into the number of ledgers left before its entry expires, as of the ledger the ttl was
+// exported at. An entry is live until the end of its live until ledger, so entries whose count is negative are expired and
+// need to be restored before they can be used.
+func TransformTtlExpiry(ttl TtlOutput) TtlExpiryOutput {
+	ledgersUntilExpiry := int64(ttl.LiveUntilLedgerSeq) - int64(ttl.LedgerSequence)
+	return TtlExpiryOutput{
+		KeyHash:            ttl.KeyHash,
+		LiveUntilLedgerSeq: ttl.LiveUntilLedgerSeq,
+		LedgersUntilExpiry: ledgersUntilExpiry,
+		Expired:            ledgersUntilExpiry < 0,
+		LastModifiedLedger: ttl.LastModifiedLedger,
+		ClosedAt:           ttl.ClosedAt,
+		LedgerSequence:     ttl.LedgerSequence,
+		LedgerKeyHash:      ttl.LedgerKeyHash,
+	}
+}
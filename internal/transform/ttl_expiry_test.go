@@ -0,0 +1,39 @@
+package transform
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransformTtlExpiry(t *testing.T) {
+	closedAt := time.Date(1970, time.January, 1, 0, 16, 40, 0, time.UTC)
+	ttl := TtlOutput{
+		KeyHash:            "0000000000000000000000000000000000000000000000000000000000000000",
+		LiveUntilLedgerSeq: 1000,
+		LastModifiedLedger: 900,
+		ClosedAt:           closedAt,
+		LedgerSequence:     990,
+		LedgerKeyHash:      "d0d51b29a2e2c5b46a4d5a7e2b3d2c1a0f9e8d7c6b5a4f3e2d1c0b0a09080706",
+	}
+
+	assert.Equal(t, TtlExpiryOutput{
+		KeyHash:            "0000000000000000000000000000000000000000000000000000000000000000",
+		LiveUntilLedgerSeq: 1000,
+		LedgersUntilExpiry: 10,
+		Expired:            false,
+		LastModifiedLedger: 900,
+		ClosedAt:           closedAt,
+		LedgerSequence:     990,
+		LedgerKeyHash:      "d0d51b29a2e2c5b46a4d5a7e2b3d2c1a0f9e8d7c6b5a4f3e2d1c0b0a09080706",
+	}, TransformTtlExpiry(ttl))
+
+	ttl.LedgerSequence = 1000
+	assert.Equal(t, int64(0), TransformTtlExpiry(ttl).LedgersUntilExpiry)
+	assert.False(t, TransformTtlExpiry(ttl).Expired)
+
+	ttl.LedgerSequence = 1005
+	assert.Equal(t, int64(-5), TransformTtlExpiry(ttl).LedgersUntilExpiry)
+	assert.True(t, TransformTtlExpiry(ttl).Expired)
+}
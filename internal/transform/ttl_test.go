@@ -97,13 +97,15 @@ func makeTtlTestInput() []ingest.Change {
 func makeTtlTestOutput() []TtlOutput {
 	return []TtlOutput{
 		{
-			KeyHash:            "0000000000000000000000000000000000000000000000000000000000000000",
-			LiveUntilLedgerSeq: 123,
-			LastModifiedLedger: 1,
-			LedgerEntryChange:  1,
-			Deleted:            false,
-			LedgerSequence:     10,
-			ClosedAt:           time.Date(1970, time.January, 1, 0, 16, 40, 0, time.UTC),
+			KeyHash:             "0000000000000000000000000000000000000000000000000000000000000000",
+			LiveUntilLedgerSeq:  123,
+			LastModifiedLedger:  1,
+			LedgerEntryChange:   1,
+			Deleted:             false,
+			LedgerSequence:      10,
+			LedgerKeyHash:       "cfd63cfe971516211d7fccb9c1df526c51a810773bca0c6198adda7cb24a13e5",
+			LedgerKeyHashBase64: "AAAACQAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA",
+			ClosedAt:            time.Date(1970, time.January, 1, 0, 16, 40, 0, time.UTC),
 		},
 	}
 }
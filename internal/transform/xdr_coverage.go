@@ -0,0 +1,99 @@
+package transform
+
+import (
+	_ "embed"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// mappedXdrArmsFile lists the arms of every XDR union that the transforms were written against, one "<union> <arm>"
+// per line. Arms added to the XDR by later protocols are missing from it until the transforms map them.
+//
+//go:embed xdr_arms.txt
+var mappedXdrArmsFile string
+
+var mappedXdrArms = parseXdrArms(mappedXdrArmsFile)
+
+// xdrUnion is implemented by the decoded XDR unions
+type xdrUnion interface {
+	SwitchFieldName() string
+	ArmForSwitch(sw int32) (string, bool)
+}
+
+var xdrUnionType = reflect.TypeOf((*xdrUnion)(nil)).Elem()
+
+// UnhandledXdrArm is an arm of an XDR union that the transforms were not written against, such as a new operation
+// type or a new extension version added by a protocol upgrade
+type UnhandledXdrArm struct {
+	Union string
+	Arm   string
+}
+
+func (a UnhandledXdrArm) String() string {
+	return fmt.Sprintf("%s %s", a.Union, a.Arm)
+}
+
+func parseXdrArms(contents string) map[string]bool {
+	arms := map[string]bool{}
+	for _, line := range strings.Split(contents, "\n") {
+		if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "#") {
+			arms[line] = true
+		}
+	}
+	return arms
+}
+
+// FindUnhandledXdrArms walks a decoded XDR value, such as a LedgerCloseMeta, and returns the arms of the unions in it
+// that the transforms were not written against, in the order they are found. Every arm is returned once.
+func FindUnhandledXdrArms(value interface{}) []UnhandledXdrArm {
+	var unhandled []UnhandledXdrArm
+	seen := map[UnhandledXdrArm]bool{}
+	walkXdrUnions(reflect.ValueOf(value), func(arm UnhandledXdrArm) {
+		if mappedXdrArms[arm.String()] || seen[arm] {
+			return
+		}
+		seen[arm] = true
+		unhandled = append(unhandled, arm)
+	})
+	return unhandled
+}
+
+// walkXdrUnions calls visit with the arm of every union in a decoded XDR value
+func walkXdrUnions(value reflect.Value, visit func(UnhandledXdrArm)) {
+	switch value.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if !value.IsNil() {
+			walkXdrUnions(value.Elem(), visit)
+		}
+	case reflect.Slice, reflect.Array:
+		// Opaque data such as hashes and keys are byte arrays, which have no unions in them
+		if value.Type().Elem().Kind() == reflect.Uint8 {
+			return
+		}
+		for i := 0; i < value.Len(); i++ {
+			walkXdrUnions(value.Index(i), visit)
+		}
+	case reflect.Struct:
+		if value.Type().Implements(xdrUnionType) {
+			union := value.Interface().(xdrUnion)
+			visit(UnhandledXdrArm{Union: value.Type().String(), Arm: xdrArmName(value.FieldByName(union.SwitchFieldName()))})
+		}
+		for i := 0; i < value.NumField(); i++ {
+			if value.Type().Field(i).IsExported() {
+				walkXdrUnions(value.Field(i), visit)
+			}
+		}
+	}
+}
+
+// xdrArmName names an arm by the value of its switch: the name of the enum value, or the number of an int32 switch
+// such as the version of an extension point
+func xdrArmName(discriminant reflect.Value) string {
+	if stringer, ok := discriminant.Interface().(fmt.Stringer); ok {
+		if name := stringer.String(); name != "" {
+			return name
+		}
+	}
+	return fmt.Sprint(discriminant.Interface())
+}
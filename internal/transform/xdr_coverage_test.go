@@ -0,0 +1,87 @@
+package transform
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+)
+
+var update = flag.Bool("update", false, "update xdr_arms.txt with the arms of the XDR unions of the go sdk")
+
+func TestFindUnhandledXdrArms(t *testing.T) {
+	entry := xdr.LedgerEntry{
+		Data: xdr.LedgerEntryData{Type: xdr.LedgerEntryTypeAccount, Account: &xdr.AccountEntry{}},
+		Ext:  xdr.LedgerEntryExt{V: 1, V1: &xdr.LedgerEntryExtensionV1{}},
+	}
+	assert.Empty(t, FindUnhandledXdrArms(entry))
+
+	// Arms that are missing from the list are reported once
+	delete(mappedXdrArms, "xdr.LedgerEntryExt 1")
+	defer func() { mappedXdrArms["xdr.LedgerEntryExt 1"] = true }()
+	assert.Equal(t, []UnhandledXdrArm{{Union: "xdr.LedgerEntryExt", Arm: "1"}}, FindUnhandledXdrArms([]xdr.LedgerEntry{entry, entry}))
+
+	entry.Ext = xdr.LedgerEntryExt{V: 7}
+	assert.Equal(t, []UnhandledXdrArm{{Union: "xdr.LedgerEntryExt", Arm: "7"}}, FindUnhandledXdrArms(&entry))
+}
+
+// TestXdrArms regenerates xdr_arms.txt with -update, once the transforms map the arms of a new protocol
+func TestXdrArms(t *testing.T) {
+	if !*update {
+		t.Skip("run with -update to regenerate xdr_arms.txt")
+	}
+
+	arms := []string{}
+	for union := range reachableXdrUnions(reflect.TypeOf(xdr.LedgerCloseMeta{}), map[reflect.Type]bool{}) {
+		zero := reflect.New(union).Elem().Interface().(xdrUnion)
+		field, _ := union.FieldByName(zero.SwitchFieldName())
+		for sw := int32(-256); sw < 4096; sw++ {
+			if _, ok := zero.ArmForSwitch(sw); !ok {
+				continue
+			}
+			discriminant := reflect.New(field.Type).Elem()
+			discriminant.SetInt(int64(sw))
+			arms = append(arms, fmt.Sprintf("%s %s", union.String(), xdrArmName(discriminant)))
+		}
+	}
+	sort.Strings(arms)
+
+	header := "# The arms of the XDR unions that the transforms were written against. Regenerate with\n" +
+		"# go test ./internal/transform -run TestXdrArms -update once the transforms map the arms of a new protocol.\n"
+	assert.NoError(t, os.WriteFile("xdr_arms.txt", []byte(header+strings.Join(arms, "\n")+"\n"), 0644))
+}
+
+// reachableXdrUnions returns the union types that values of a type can hold
+func reachableXdrUnions(typ reflect.Type, visited map[reflect.Type]bool) map[reflect.Type]bool {
+	if visited[typ] {
+		return nil
+	}
+	visited[typ] = true
+
+	unions := map[reflect.Type]bool{}
+	switch typ.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Array:
+		for union := range reachableXdrUnions(typ.Elem(), visited) {
+			unions[union] = true
+		}
+	case reflect.Struct:
+		if typ.Implements(xdrUnionType) {
+			unions[typ] = true
+		}
+		for i := 0; i < typ.NumField(); i++ {
+			if !typ.Field(i).IsExported() {
+				continue
+			}
+			for union := range reachableXdrUnions(typ.Field(i).Type, visited) {
+				unions[union] = true
+			}
+		}
+	}
+	return unions
+}
@@ -0,0 +1,155 @@
+package utils
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/stellar/go-stellar-sdk/strkey"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+// AccountBloomBitsPerKey and AccountBloomNumHashes size the bloom filter of an AccountBloom for a false positive rate
+// of about 1%
+const (
+	AccountBloomBitsPerKey = 10
+	AccountBloomNumHashes  = 7
+)
+
+var errUnsupportedAddress = errors.New("unsupported address")
+
+// AccountBloom is a bloom filter of the 32 byte keys of accounts and contracts. The bit of the i-th hash of a key is
+// (h1 + i*h2) mod NumBits, where h1 and h2 are the little endian uint64s of the bytes 0 to 8 and 8 to 16 of the key,
+// with the lowest bit of h2 set. Bit n is the bit n mod 64 of the word n / 64 of Bits.
+type AccountBloom struct {
+	Bits    []uint64
+	NumBits uint64
+}
+
+// NewAccountBloom creates an empty bloom filter sized for a number of keys
+func NewAccountBloom(numKeys int) *AccountBloom {
+	numBits := uint64(max(numKeys*AccountBloomBitsPerKey, 64))
+	return &AccountBloom{
+		Bits:    make([]uint64, (numBits+63)/64),
+		NumBits: numBits,
+	}
+}
+
+// Add adds a 32 byte key to the bloom filter
+func (b *AccountBloom) Add(key []byte) {
+	h1, h2 := accountBloomHashes(key)
+	for i := uint64(0); i < AccountBloomNumHashes; i++ {
+		bit := (h1 + i*h2) % b.NumBits
+		b.Bits[bit/64] |= 1 << (bit % 64)
+	}
+}
+
+// MightContain reports whether a 32 byte key might have been added to the bloom filter
+func (b *AccountBloom) MightContain(key []byte) bool {
+	h1, h2 := accountBloomHashes(key)
+	for i := uint64(0); i < AccountBloomNumHashes; i++ {
+		bit := (h1 + i*h2) % b.NumBits
+		if b.Bits[bit/64]&(1<<(bit%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// accountBloomHashes derives the hashes of a key from its bytes. Public keys and contract ids are uniformly
+// distributed, so they don't need to be hashed again.
+func accountBloomHashes(key []byte) (uint64, uint64) {
+	return binary.LittleEndian.Uint64(key[0:8]), binary.LittleEndian.Uint64(key[8:16]) | 1
+}
+
+// AddressKey returns the 32 byte key of a G account, M muxed account or C contract address. Muxed accounts have the key
+// of their underlying account.
+func AddressKey(address string) ([32]byte, error) {
+	var key [32]byte
+	versionByte, err := strkey.Version(address)
+	if err != nil {
+		return key, err
+	}
+
+	var raw []byte
+	switch versionByte {
+	case strkey.VersionByteAccountID, strkey.VersionByteContract:
+		raw, err = strkey.Decode(versionByte, address)
+	case strkey.VersionByteMuxedAccount:
+		var muxed xdr.MuxedAccount
+		if err = muxed.SetAddress(address); err == nil {
+			ed25519 := muxed.MustMed25519().Ed25519
+			raw = ed25519[:]
+		}
+	default:
+		return key, errUnsupportedAddress
+	}
+	if err != nil {
+		return key, err
+	}
+
+	copy(key[:], raw)
+	return key, nil
+}
+
+// AccountFilter matches the transactions whose envelope has one of a set of accounts or contracts. Account ids,
+// muxed accounts and contract addresses are all encoded as 32 byte keys aligned to 4 bytes in the XDR of an envelope,
+// so the raw envelope is screened for the keys without decoding it. Every aligned window is first tested against a
+// bloom filter of the keys, which rejects almost all windows after reading a single bit, and only the windows that
+// pass it are looked up in the set of keys.
+type AccountFilter struct {
+	bloom *AccountBloom
+	keys  map[[32]byte]struct{}
+}
+
+// NewAccountFilter creates the filter of a list of G account, M muxed account and C contract addresses. Muxed accounts
+// match every transaction of their underlying account.
+func NewAccountFilter(addresses []string) (*AccountFilter, error) {
+	keys := map[[32]byte]struct{}{}
+	for _, address := range addresses {
+		key, err := AddressKey(address)
+		if errors.Is(err, errUnsupportedAddress) {
+			return nil, fmt.Errorf("unsupported address %q in filter-accounts; expected an account, muxed account or contract", address)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid address %q in filter-accounts: %v", address, err)
+		}
+		keys[key] = struct{}{}
+	}
+
+	bloom := NewAccountBloom(len(keys))
+	for key := range keys {
+		bloom.Add(key[:])
+	}
+	return &AccountFilter{bloom: bloom, keys: keys}, nil
+}
+
+// Len returns the number of accounts and contracts of the filter
+func (f *AccountFilter) Len() int {
+	return len(f.keys)
+}
+
+// MatchesEnvelope reports whether one of the accounts or contracts of the filter is in the envelope, such as its
+// source, the destination of a payment, the issuer of an asset or an address in a contract invocation
+func (f *AccountFilter) MatchesEnvelope(envelope xdr.TransactionEnvelope) (bool, error) {
+	raw, err := envelope.MarshalBinary()
+	if err != nil {
+		return false, fmt.Errorf("could not encode transaction envelope: %v", err)
+	}
+	return f.matchesRaw(raw), nil
+}
+
+func (f *AccountFilter) matchesRaw(raw []byte) bool {
+	var key [32]byte
+	for offset := 0; offset+32 <= len(raw); offset += 4 {
+		window := raw[offset : offset+32]
+		if !f.bloom.MightContain(window) {
+			continue
+		}
+		copy(key[:], window)
+		if _, ok := f.keys[key]; ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,63 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stellar/go-stellar-sdk/keypair"
+	"github.com/stellar/go-stellar-sdk/strkey"
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+)
+
+func paymentEnvelope(source, destination string) xdr.TransactionEnvelope {
+	return xdr.TransactionEnvelope{
+		Type: xdr.EnvelopeTypeEnvelopeTypeTx,
+		V1: &xdr.TransactionV1Envelope{Tx: xdr.Transaction{
+			SourceAccount: xdr.MustMuxedAddress(source),
+			Operations: []xdr.Operation{{Body: xdr.OperationBody{
+				Type: xdr.OperationTypePayment,
+				PaymentOp: &xdr.PaymentOp{
+					Destination: xdr.MustMuxedAddress(destination),
+					Asset:       xdr.MustNewNativeAsset(),
+					Amount:      10,
+				},
+			}}},
+		}},
+	}
+}
+
+func TestAccountFilter(t *testing.T) {
+	source, destination, other := keypair.MustRandom().Address(), keypair.MustRandom().Address(), keypair.MustRandom().Address()
+
+	var addresses []string
+	for i := 0; i < 5000; i++ {
+		addresses = append(addresses, keypair.MustRandom().Address())
+	}
+	filter, err := NewAccountFilter(append(addresses, destination))
+	assert.NoError(t, err)
+	assert.Equal(t, 5001, filter.Len())
+
+	matches, err := filter.MatchesEnvelope(paymentEnvelope(source, destination))
+	assert.NoError(t, err)
+	assert.True(t, matches)
+
+	matches, err = filter.MatchesEnvelope(paymentEnvelope(source, other))
+	assert.NoError(t, err)
+	assert.False(t, matches)
+
+	// Muxed accounts match their underlying account
+	muxed := xdr.MustMuxedAddress(source)
+	muxedAccount := xdr.MuxedAccount{Type: xdr.CryptoKeyTypeKeyTypeMuxedEd25519, Med25519: &xdr.MuxedAccountMed25519{Id: 7, Ed25519: *muxed.Ed25519}}
+	muxedFilter, err := NewAccountFilter([]string{muxedAccount.Address()})
+	assert.NoError(t, err)
+	matches, err = muxedFilter.MatchesEnvelope(paymentEnvelope(source, other))
+	assert.NoError(t, err)
+	assert.True(t, matches)
+
+	contract := strkey.MustEncode(strkey.VersionByteContract, make([]byte, 32))
+	_, err = NewAccountFilter([]string{contract})
+	assert.NoError(t, err)
+
+	_, err = NewAccountFilter([]string{"GABC"})
+	assert.ErrorContains(t, err, `invalid address "GABC" in filter-accounts`)
+}
@@ -0,0 +1,208 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/stellar/go-stellar-sdk/ingest/ledgerbackend"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+// MaxLedgerCloseMetaVersion is the newest LedgerCloseMeta version the transforms read. Newer versions are rejected
+// instead of being read as an older version, which would silently drop the fields they add.
+const MaxLedgerCloseMetaVersion = 2
+
+// ErrUnsupportedLedger is wrapped by the errors of ledgers that can't be exported. Reading such a ledger again fails
+// the same way, so it is not retried.
+var ErrUnsupportedLedger = errors.New("unsupported ledger")
+
+// CheckLedgerCloseMetaVersion returns an error if the transforms can't read the version of a LedgerCloseMeta
+func CheckLedgerCloseMetaVersion(lcm xdr.LedgerCloseMeta) error {
+	switch lcm.V {
+	case 0, 1, 2:
+		return nil
+	default:
+		return fmt.Errorf("%w: LedgerCloseMeta version %d is newer than version %d", ErrUnsupportedLedger, lcm.V, MaxLedgerCloseMetaVersion)
+	}
+}
+
+// LedgerCloseMetaTxSet returns the transaction history extension holding the generalized transaction set of a
+// LedgerCloseMeta, or an empty extension for V0 metas whose transactions are in the classic transaction set
+func LedgerCloseMetaTxSet(lcm xdr.LedgerCloseMeta) (xdr.TransactionHistoryEntryExt, error) {
+	switch lcm.V {
+	case 0:
+		return xdr.TransactionHistoryEntryExt{V: 0}, nil
+	case 1:
+		return xdr.TransactionHistoryEntryExt{V: 1, GeneralizedTxSet: &lcm.V1.TxSet}, nil
+	case 2:
+		return xdr.TransactionHistoryEntryExt{V: 1, GeneralizedTxSet: &lcm.V2.TxSet}, nil
+	default:
+		return xdr.TransactionHistoryEntryExt{}, CheckLedgerCloseMetaVersion(lcm)
+	}
+}
+
+// LedgerCloseMetaTxPhases returns the phases of the generalized transaction set of a LedgerCloseMeta. V0 metas have
+// no phases.
+func LedgerCloseMetaTxPhases(lcm xdr.LedgerCloseMeta) ([]xdr.TransactionPhase, error) {
+	ext, err := LedgerCloseMetaTxSet(lcm)
+	if err != nil {
+		return nil, err
+	}
+	if ext.GeneralizedTxSet == nil {
+		return nil, nil
+	}
+	return ext.GeneralizedTxSet.MustV1TxSet().Phases, nil
+}
+
+// LedgerCloseMetaResultPairs returns the result of every transaction of a LedgerCloseMeta in apply order
+func LedgerCloseMetaResultPairs(lcm xdr.LedgerCloseMeta) ([]xdr.TransactionResultPair, error) {
+	var results []xdr.TransactionResultPair
+	switch lcm.V {
+	case 0:
+		for _, transactionResultMeta := range lcm.MustV0().TxProcessing {
+			results = append(results, transactionResultMeta.Result)
+		}
+	case 1:
+		for _, transactionResultMeta := range lcm.MustV1().TxProcessing {
+			results = append(results, transactionResultMeta.Result)
+		}
+	case 2:
+		for _, transactionResultMeta := range lcm.MustV2().TxProcessing {
+			results = append(results, transactionResultMeta.Result)
+		}
+	default:
+		return nil, CheckLedgerCloseMetaVersion(lcm)
+	}
+	return results, nil
+}
+
+// LedgerHasSorobanTransactions reports whether the soroban phase of the transaction set of a LedgerCloseMeta has any
+// transactions. Ledgers from before soroban have no soroban phase.
+func LedgerHasSorobanTransactions(lcm xdr.LedgerCloseMeta) (bool, error) {
+	phases, err := LedgerCloseMetaTxPhases(lcm)
+	if err != nil {
+		return false, err
+	}
+	if len(phases) < 2 {
+		return false, nil
+	}
+
+	sorobanPhase := phases[1]
+	switch sorobanPhase.V {
+	case 0:
+		for _, component := range *sorobanPhase.V0Components {
+			if len(component.TxsMaybeDiscountedFee.Txs) > 0 {
+				return true, nil
+			}
+		}
+	case 1:
+		for _, stage := range sorobanPhase.ParallelTxsComponent.ExecutionStages {
+			for _, cluster := range stage {
+				if len(cluster) > 0 {
+					return true, nil
+				}
+			}
+		}
+	default:
+		return false, fmt.Errorf("%w: transaction phase version %d is not supported", ErrUnsupportedLedger, sorobanPhase.V)
+	}
+	return false, nil
+}
+
+// FailedTransactionHashes returns the hex encoded hashes of the transactions of a LedgerCloseMeta that failed
+func FailedTransactionHashes(lcm xdr.LedgerCloseMeta) (map[string]bool, error) {
+	results, err := LedgerCloseMetaResultPairs(lcm)
+	if err != nil {
+		return nil, err
+	}
+
+	failed := map[string]bool{}
+	for _, result := range results {
+		if !result.Result.Successful() {
+			failed[HashToHexString(result.TransactionHash)] = true
+		}
+	}
+	return failed, nil
+}
+
+// LedgerCloseMetaSorobanState holds the soroban fields that LedgerCloseMeta V1 added and V2 kept
+type LedgerCloseMetaSorobanState struct {
+	SorobanFeeWrite1Kb              int64
+	TotalByteSizeOfLiveSorobanState uint64
+	EvictedKeys                     []xdr.LedgerKey
+}
+
+// GetLedgerCloseMetaSorobanState returns the soroban fields of a LedgerCloseMeta, which are empty for V0 metas
+func GetLedgerCloseMetaSorobanState(lcm xdr.LedgerCloseMeta) (LedgerCloseMetaSorobanState, error) {
+	var ext xdr.LedgerCloseMetaExt
+	var state LedgerCloseMetaSorobanState
+	switch lcm.V {
+	case 0:
+		return state, nil
+	case 1:
+		lcmV1 := lcm.MustV1()
+		ext = lcmV1.Ext
+		state.TotalByteSizeOfLiveSorobanState = uint64(lcmV1.TotalByteSizeOfLiveSorobanState)
+		state.EvictedKeys = lcmV1.EvictedKeys
+	case 2:
+		lcmV2 := lcm.MustV2()
+		ext = lcmV2.Ext
+		state.TotalByteSizeOfLiveSorobanState = uint64(lcmV2.TotalByteSizeOfLiveSorobanState)
+		state.EvictedKeys = lcmV2.EvictedKeys
+	default:
+		return LedgerCloseMetaSorobanState{}, CheckLedgerCloseMetaVersion(lcm)
+	}
+
+	switch ext.V {
+	case 0:
+	case 1:
+		state.SorobanFeeWrite1Kb = int64(ext.MustV1().SorobanFeeWrite1Kb)
+	default:
+		return LedgerCloseMetaSorobanState{}, fmt.Errorf("%w: ledger %d has LedgerCloseMetaExt version %d", ErrUnsupportedLedger, lcm.LedgerSequence(), ext.V)
+	}
+
+	return state, nil
+}
+
+// CheckProtocolVersion returns an error if a ledger is outside of the min-protocol and max-protocol range, or has a
+// LedgerCloseMeta version the transforms can't read. A bound of 0 leaves that side of the range open.
+func CheckProtocolVersion(lcm xdr.LedgerCloseMeta, minProtocol, maxProtocol uint32) error {
+	if err := CheckLedgerCloseMetaVersion(lcm); err != nil {
+		return err
+	}
+
+	protocol := lcm.ProtocolVersion()
+	if minProtocol != 0 && protocol < minProtocol {
+		return fmt.Errorf("%w: ledger %d has protocol version %d, which is below min-protocol %d", ErrUnsupportedLedger, lcm.LedgerSequence(), protocol, minProtocol)
+	}
+	if maxProtocol != 0 && protocol > maxProtocol {
+		return fmt.Errorf("%w: ledger %d has protocol version %d, which is above max-protocol %d", ErrUnsupportedLedger, lcm.LedgerSequence(), protocol, maxProtocol)
+	}
+	return nil
+}
+
+// protocolGuardBackend is a ledger backend that fails to return ledgers that CheckProtocolVersion rejects, so that
+// every export stops at the first ledger it can't read instead of exporting it incompletely
+type protocolGuardBackend struct {
+	ledgerbackend.LedgerBackend
+	minProtocol uint32
+	maxProtocol uint32
+}
+
+// NewProtocolGuardBackend wraps a ledger backend with the checks of CheckProtocolVersion
+func NewProtocolGuardBackend(backend ledgerbackend.LedgerBackend, minProtocol, maxProtocol uint32) ledgerbackend.LedgerBackend {
+	return protocolGuardBackend{LedgerBackend: backend, minProtocol: minProtocol, maxProtocol: maxProtocol}
+}
+
+func (p protocolGuardBackend) GetLedger(ctx context.Context, sequence uint32) (xdr.LedgerCloseMeta, error) {
+	lcm, err := p.LedgerBackend.GetLedger(ctx, sequence)
+	if err != nil {
+		return lcm, err
+	}
+
+	if err := CheckProtocolVersion(lcm, p.minProtocol, p.maxProtocol); err != nil {
+		return xdr.LedgerCloseMeta{}, err
+	}
+	return lcm, nil
+}
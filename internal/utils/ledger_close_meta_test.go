@@ -0,0 +1,109 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+)
+
+func protocolLedger(sequence, protocol uint32) xdr.LedgerCloseMeta {
+	return xdr.LedgerCloseMeta{
+		V: 2,
+		V2: &xdr.LedgerCloseMetaV2{
+			Ext: xdr.LedgerCloseMetaExt{V: 1, V1: &xdr.LedgerCloseMetaExtV1{SorobanFeeWrite1Kb: 3500}},
+			LedgerHeader: xdr.LedgerHeaderHistoryEntry{Header: xdr.LedgerHeader{
+				LedgerSeq:     xdr.Uint32(sequence),
+				LedgerVersion: xdr.Uint32(protocol),
+			}},
+			TxSet:                           xdr.GeneralizedTransactionSet{V: 1, V1TxSet: &xdr.TransactionSetV1{}},
+			TotalByteSizeOfLiveSorobanState: 4096,
+		},
+	}
+}
+
+func TestCheckProtocolVersion(t *testing.T) {
+	lcm := protocolLedger(10, 23)
+	assert.NoError(t, CheckProtocolVersion(lcm, 0, 0))
+	assert.NoError(t, CheckProtocolVersion(lcm, 23, 23))
+
+	err := CheckProtocolVersion(lcm, 24, 0)
+	assert.True(t, errors.Is(err, ErrUnsupportedLedger))
+	assert.EqualError(t, err, "unsupported ledger: ledger 10 has protocol version 23, which is below min-protocol 24")
+
+	err = CheckProtocolVersion(lcm, 0, 22)
+	assert.EqualError(t, err, "unsupported ledger: ledger 10 has protocol version 23, which is above max-protocol 22")
+
+	err = CheckProtocolVersion(xdr.LedgerCloseMeta{V: 3}, 0, 0)
+	assert.True(t, errors.Is(err, ErrUnsupportedLedger))
+	assert.EqualError(t, err, "unsupported ledger: LedgerCloseMeta version 3 is newer than version 2")
+}
+
+func TestGetLedgerCloseMetaSorobanState(t *testing.T) {
+	state, err := GetLedgerCloseMetaSorobanState(protocolLedger(10, 23))
+	assert.NoError(t, err)
+	assert.Equal(t, LedgerCloseMetaSorobanState{SorobanFeeWrite1Kb: 3500, TotalByteSizeOfLiveSorobanState: 4096}, state)
+
+	state, err = GetLedgerCloseMetaSorobanState(fixtureLedger(10))
+	assert.NoError(t, err)
+	assert.Equal(t, LedgerCloseMetaSorobanState{}, state)
+
+	ext, err := LedgerCloseMetaTxSet(protocolLedger(10, 23))
+	assert.NoError(t, err)
+	assert.Equal(t, int32(1), ext.V)
+
+	_, err = LedgerCloseMetaResultPairs(xdr.LedgerCloseMeta{V: 3})
+	assert.Error(t, err)
+}
+
+func TestFailedTransactionHashes(t *testing.T) {
+	lcm := protocolLedger(10, 23)
+	lcm.V2.TxProcessing = []xdr.TransactionResultMetaV1{
+		{Result: xdr.TransactionResultPair{TransactionHash: xdr.Hash{1}, Result: xdr.TransactionResult{Result: xdr.TransactionResultResult{Code: xdr.TransactionResultCodeTxSuccess, Results: &[]xdr.OperationResult{}}}}},
+		{Result: xdr.TransactionResultPair{TransactionHash: xdr.Hash{2}, Result: xdr.TransactionResult{Result: xdr.TransactionResultResult{Code: xdr.TransactionResultCodeTxFailed, Results: &[]xdr.OperationResult{}}}}},
+	}
+
+	failed, err := FailedTransactionHashes(lcm)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]bool{HashToHexString(xdr.Hash{2}): true}, failed)
+}
+
+func TestLedgerHasSorobanTransactions(t *testing.T) {
+	hasSoroban, err := LedgerHasSorobanTransactions(fixtureLedger(10))
+	assert.NoError(t, err)
+	assert.False(t, hasSoroban)
+
+	lcm := protocolLedger(10, 23)
+	lcm.V2.TxSet.V1TxSet.Phases = []xdr.TransactionPhase{
+		{V: 0, V0Components: &[]xdr.TxSetComponent{}},
+		{V: 1, ParallelTxsComponent: &xdr.ParallelTxsComponent{ExecutionStages: []xdr.ParallelTxExecutionStage{{{}}}}},
+	}
+	hasSoroban, err = LedgerHasSorobanTransactions(lcm)
+	assert.NoError(t, err)
+	assert.False(t, hasSoroban)
+
+	lcm.V2.TxSet.V1TxSet.Phases[1].ParallelTxsComponent.ExecutionStages[0][0] = xdr.DependentTxCluster{{}}
+	hasSoroban, err = LedgerHasSorobanTransactions(lcm)
+	assert.NoError(t, err)
+	assert.True(t, hasSoroban)
+}
+
+func TestProtocolGuardBackend(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "ledgers.xdr.zst")
+	assert.NoError(t, WriteLedgerFixture(path, []xdr.LedgerCloseMeta{protocolLedger(10, 22), protocolLedger(11, 23)}))
+
+	fixture, err := NewLedgerFixtureBackend(path)
+	assert.NoError(t, err)
+	backend := NewProtocolGuardBackend(fixture, 0, 22)
+
+	lcm, err := backend.GetLedger(ctx, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(10), lcm.LedgerSequence())
+
+	_, err = backend.GetLedger(ctx, 11)
+	assert.True(t, errors.Is(err, ErrUnsupportedLedger))
+}
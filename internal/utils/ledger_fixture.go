@@ -0,0 +1,197 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/stellar/go-stellar-sdk/ingest/ledgerbackend"
+	"github.com/stellar/go-stellar-sdk/support/compressxdr"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+// WriteLedgerFixture writes a range of ledgers to a ledger fixture, which is a zstd compressed LedgerCloseMetaBatch
+// like the files of the datastore. The ledgers must be consecutive and in ascending order.
+func WriteLedgerFixture(path string, ledgers []xdr.LedgerCloseMeta) error {
+	if len(ledgers) == 0 {
+		return fmt.Errorf("a ledger fixture needs at least one ledger")
+	}
+
+	batch := xdr.LedgerCloseMetaBatch{
+		StartSequence:    xdr.Uint32(ledgers[0].LedgerSequence()),
+		EndSequence:      xdr.Uint32(ledgers[len(ledgers)-1].LedgerSequence()),
+		LedgerCloseMetas: ledgers,
+	}
+	for i, lcm := range ledgers {
+		if lcm.LedgerSequence() != uint32(batch.StartSequence)+uint32(i) {
+			return fmt.Errorf("ledger %d of a ledger fixture starting at %d is out of order", lcm.LedgerSequence(), batch.StartSequence)
+		}
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("could not create ledger fixture %s: %v", path, err)
+	}
+	defer file.Close()
+
+	if _, err = compressxdr.NewXDREncoder(compressxdr.DefaultCompressor, batch).WriteTo(file); err != nil {
+		return fmt.Errorf("could not write ledger fixture %s: %v", path, err)
+	}
+
+	return file.Close()
+}
+
+// ReadLedgerFixture reads the ledgers of a ledger fixture
+func ReadLedgerFixture(path string) (xdr.LedgerCloseMetaBatch, error) {
+	batch := xdr.LedgerCloseMetaBatch{}
+	file, err := os.Open(path)
+	if err != nil {
+		return batch, fmt.Errorf("could not open ledger fixture %s: %v", path, err)
+	}
+	defer file.Close()
+
+	if _, err = compressxdr.NewXDRDecoder(compressxdr.DefaultCompressor, &batch).ReadFrom(file); err != nil {
+		return batch, fmt.Errorf("could not read ledger fixture %s: %v", path, err)
+	}
+
+	return batch, nil
+}
+
+// ReadLedgerCloseMetas reads the ledgers of a ledger fixture, or of every LedgerCloseMeta XDR file in a folder and its
+// subfolders, sorted by sequence. The files of a folder can be zstd compressed batches ending in .zst, like the files of
+// the datastore, or uncompressed batches or single ledgers ending in .xdr. Other files are ignored.
+func ReadLedgerCloseMetas(path string) ([]xdr.LedgerCloseMeta, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read ledgers from %s: %v", path, err)
+	}
+
+	if !info.IsDir() {
+		batch, err := ReadLedgerFixture(path)
+		if err != nil {
+			return nil, err
+		}
+		if len(batch.LedgerCloseMetas) == 0 {
+			return nil, fmt.Errorf("ledger fixture %s has no ledgers", path)
+		}
+		return batch.LedgerCloseMetas, nil
+	}
+
+	ledgers := map[uint32]xdr.LedgerCloseMeta{}
+	err = filepath.WalkDir(path, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+
+		var fileLedgers []xdr.LedgerCloseMeta
+		switch {
+		case strings.HasSuffix(filePath, ".zst"):
+			batch, err := ReadLedgerFixture(filePath)
+			if err != nil {
+				return err
+			}
+			fileLedgers = batch.LedgerCloseMetas
+		case strings.HasSuffix(filePath, ".xdr"):
+			fileLedgers, err = readUncompressedLedgers(filePath)
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, lcm := range fileLedgers {
+			ledgers[lcm.LedgerSequence()] = lcm
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(ledgers) == 0 {
+		return nil, fmt.Errorf("there are no LedgerCloseMeta files in %s", path)
+	}
+
+	sorted := make([]xdr.LedgerCloseMeta, 0, len(ledgers))
+	for _, lcm := range ledgers {
+		sorted = append(sorted, lcm)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].LedgerSequence() < sorted[j].LedgerSequence()
+	})
+	return sorted, nil
+}
+
+// readUncompressedLedgers reads an uncompressed file of a LedgerCloseMetaBatch or of a single LedgerCloseMeta
+func readUncompressedLedgers(path string) ([]xdr.LedgerCloseMeta, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read ledger file %s: %v", path, err)
+	}
+
+	var batch xdr.LedgerCloseMetaBatch
+	if err := xdr.SafeUnmarshal(contents, &batch); err == nil {
+		return batch.LedgerCloseMetas, nil
+	}
+
+	var lcm xdr.LedgerCloseMeta
+	if err := xdr.SafeUnmarshal(contents, &lcm); err != nil {
+		return nil, fmt.Errorf("%s is neither a LedgerCloseMetaBatch nor a LedgerCloseMeta: %v", path, err)
+	}
+	return []xdr.LedgerCloseMeta{lcm}, nil
+}
+
+// ledgerFixtureBackend is a ledger backend that serves locally stored ledgers, so exports can be replayed without a
+// datastore
+type ledgerFixtureBackend struct {
+	ledgers map[uint32]xdr.LedgerCloseMeta
+	start   uint32
+	end     uint32
+}
+
+// NewLedgerFixtureBackend creates a ledger backend from a ledger fixture or a folder of LedgerCloseMeta files
+func NewLedgerFixtureBackend(path string) (ledgerbackend.LedgerBackend, error) {
+	lcms, err := ReadLedgerCloseMetas(path)
+	if err != nil {
+		return nil, err
+	}
+
+	backend := ledgerFixtureBackend{
+		ledgers: make(map[uint32]xdr.LedgerCloseMeta, len(lcms)),
+		start:   lcms[0].LedgerSequence(),
+		end:     lcms[len(lcms)-1].LedgerSequence(),
+	}
+	for _, lcm := range lcms {
+		backend.ledgers[lcm.LedgerSequence()] = lcm
+	}
+	return backend, nil
+}
+
+func (f ledgerFixtureBackend) GetLatestLedgerSequence(ctx context.Context) (uint32, error) {
+	return f.end, nil
+}
+
+func (f ledgerFixtureBackend) GetLedger(ctx context.Context, sequence uint32) (xdr.LedgerCloseMeta, error) {
+	lcm, ok := f.ledgers[sequence]
+	if !ok {
+		return xdr.LedgerCloseMeta{}, fmt.Errorf("ledger %d is not in the local ledgers %d-%d", sequence, f.start, f.end)
+	}
+	return lcm, nil
+}
+
+func (f ledgerFixtureBackend) PrepareRange(ctx context.Context, ledgerRange ledgerbackend.Range) error {
+	if ledgerRange.From() < f.start || !ledgerRange.Bounded() || ledgerRange.To() > f.end {
+		return fmt.Errorf("range %s is not in the local ledgers %d-%d", ledgerRange, f.start, f.end)
+	}
+	return nil
+}
+
+func (f ledgerFixtureBackend) IsPrepared(ctx context.Context, ledgerRange ledgerbackend.Range) (bool, error) {
+	return true, nil
+}
+
+func (f ledgerFixtureBackend) Close() error {
+	return nil
+}
@@ -0,0 +1,47 @@
+package utils
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stellar/go-stellar-sdk/ingest/ledgerbackend"
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+)
+
+func fixtureLedger(sequence uint32) xdr.LedgerCloseMeta {
+	return xdr.LedgerCloseMeta{
+		V: 0,
+		V0: &xdr.LedgerCloseMetaV0{
+			LedgerHeader: xdr.LedgerHeaderHistoryEntry{Header: xdr.LedgerHeader{LedgerSeq: xdr.Uint32(sequence)}},
+		},
+	}
+}
+
+func TestLedgerFixtureBackend(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "ledgers.xdr.zst")
+	assert.NoError(t, WriteLedgerFixture(path, []xdr.LedgerCloseMeta{fixtureLedger(10), fixtureLedger(11), fixtureLedger(12)}))
+
+	backend, err := NewLedgerFixtureBackend(path)
+	assert.NoError(t, err)
+
+	latest, err := backend.GetLatestLedgerSequence(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(12), latest)
+
+	assert.NoError(t, backend.PrepareRange(ctx, ledgerbackend.BoundedRange(11, 12)))
+	assert.Error(t, backend.PrepareRange(ctx, ledgerbackend.BoundedRange(9, 12)))
+	assert.Error(t, backend.PrepareRange(ctx, ledgerbackend.UnboundedRange(10)))
+
+	lcm, err := backend.GetLedger(ctx, 11)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(11), lcm.LedgerSequence())
+
+	_, err = backend.GetLedger(ctx, 13)
+	assert.Error(t, err)
+
+	assert.Error(t, WriteLedgerFixture(path, []xdr.LedgerCloseMeta{fixtureLedger(10), fixtureLedger(12)}))
+	assert.Error(t, WriteLedgerFixture(path, nil))
+}
@@ -0,0 +1,181 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/pflag"
+	"github.com/stellar/go-stellar-sdk/historyarchive"
+	"github.com/stellar/go-stellar-sdk/network"
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAssetID(t *testing.T) {
+	assert.Equal(t, int64(-5706705804583548011), FarmHashAsset("", "", "native"))
+	assert.Equal(t, int64(-8205667356306085451), FarmHashAsset("USDT", "GBVVRXLMNCJQW3IDDXC3X6XCH35B5Q7QXNMMFPENSOGUPQO7WO7HGZPA", "credit_alphanum4"))
+
+	nativeID, err := AssetID(xdr.MustNewNativeAsset())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(-5706705804583548011), nativeID)
+
+	usdtID, err := AssetID(xdr.MustNewCreditAsset("USDT", "GBVVRXLMNCJQW3IDDXC3X6XCH35B5Q7QXNMMFPENSOGUPQO7WO7HGZPA"))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(-8205667356306085451), usdtID)
+}
+
+func TestAssetContractID(t *testing.T) {
+	pubnetID, err := AssetContractID(xdr.MustNewNativeAsset(), network.PublicNetworkPassphrase)
+	assert.NoError(t, err)
+	assert.Equal(t, "CAS3J7GYLGXMF6TDJBBYYSE3HQ6BBSMLNUQ34T6TZMYMW2EVH34XOWMA", pubnetID)
+
+	testnetID, err := AssetContractID(xdr.MustNewNativeAsset(), network.TestNetworkPassphrase)
+	assert.NoError(t, err)
+	assert.Equal(t, "CDLZFC3SYJYDZT7K67VZ75HPJVIEUVNIXF47ZG2FB2RMQQVU2HHGCYSC", testnetID)
+
+	emptyID, err := AssetContractID(xdr.MustNewNativeAsset(), "")
+	assert.NoError(t, err)
+	assert.Equal(t, "", emptyID)
+}
+
+func TestAlignToCheckpoints(t *testing.T) {
+	start, end := AlignToCheckpoints(100, 200)
+	assert.Equal(t, uint32(64), start)
+	assert.Equal(t, uint32(255), end)
+
+	start, end = AlignToCheckpoints(2, 63)
+	assert.Equal(t, uint32(1), start)
+	assert.Equal(t, uint32(63), end)
+
+	start, end = AlignToCheckpoints(128, 191)
+	assert.Equal(t, uint32(128), start)
+	assert.Equal(t, uint32(191), end)
+}
+
+func TestResolveNameTemplate(t *testing.T) {
+	name, err := ResolveNameTemplate("stellar.{network}.{dataset}", "testnet", "trades")
+	assert.NoError(t, err)
+	assert.Equal(t, "stellar.testnet.trades", name)
+
+	_, err = ResolveNameTemplate("stellar.{env}.{dataset}", "testnet", "trades")
+	assert.Error(t, err)
+}
+
+func TestMustFlagsNameTemplate(t *testing.T) {
+	newFlags := func(args ...string) FlagValues {
+		flags := pflag.NewFlagSet("export_trades", pflag.ContinueOnError)
+		AddCommonFlags(flags)
+		AddArchiveFlags("trades", flags)
+		AddCloudStorageFlags(flags)
+		assert.NoError(t, flags.Parse(args))
+		return MustFlags(flags, NewEtlLogger())
+	}
+
+	t.Setenv(NameTemplateEnvVar, "")
+	values := newFlags()
+	assert.Equal(t, "stellar-etl:trades", values.RedisStream)
+	assert.Equal(t, "trades", values.SQLiteTable)
+
+	values = newFlags("--name-template", "stellar.{network}.{dataset}", "--testnet", "--duckdb-table", "my_trades")
+	assert.Equal(t, "stellar.testnet.trades", values.RedisStream)
+	assert.Equal(t, "stellar.testnet.trades", values.SQLiteTable)
+	assert.Equal(t, "my_trades", values.DuckDBTable)
+
+	t.Setenv(NameTemplateEnvVar, "{dataset}_{network}")
+	values = newFlags("--dataset", "dex_trades")
+	assert.Equal(t, "dex_trades_pubnet", values.RedisStream)
+}
+
+func TestResolveFilenameTemplate(t *testing.T) {
+	name, err := ResolveFilenameTemplate("{dataset}_{network}_{start}_{end}.{ext}", "pubnet", "trades", "", 1000, 1999, "parquet")
+	assert.NoError(t, err)
+	assert.Equal(t, "trades_pubnet_1000_1999.parquet", name)
+
+	name, err = ResolveFilenameTemplate("{dataset}_{start}_{end}_{token}.{ext}", "pubnet", "trades", "3f9a1c", 1000, 1999, "txt")
+	assert.NoError(t, err)
+	assert.Equal(t, "trades_1000_1999_3f9a1c.txt", name)
+
+	_, err = ResolveFilenameTemplate("{dataset}_{date}.{ext}", "pubnet", "trades", "", 1000, 1999, "txt")
+	assert.Error(t, err)
+}
+
+func TestMustFlagsFilenameTemplate(t *testing.T) {
+	newFlags := func(args ...string) FlagValues {
+		flags := pflag.NewFlagSet("export_trades", pflag.ContinueOnError)
+		AddCommonFlags(flags)
+		AddArchiveFlags("trades", flags)
+		AddCloudStorageFlags(flags)
+		assert.NoError(t, flags.Parse(args))
+		return MustFlags(flags, NewEtlLogger())
+	}
+
+	values := newFlags("--start-ledger", "1000", "--end-ledger", "1999")
+	assert.Equal(t, "exported_trades.txt", values.Path)
+
+	values = newFlags("--start-ledger", "1000", "--end-ledger", "1999", "--testnet",
+		"--filename-template", "{dataset}/{network}/{start}-{end}.{ext}", "--parquet-output", "trades.parquet")
+	assert.Equal(t, "trades/testnet/1000-1999.txt", values.Path)
+	assert.Equal(t, "trades.parquet", values.ParquetPath)
+	assert.Equal(t, "trades/testnet/1000-1999.orc", values.ORCPath)
+	assert.Equal(t, "trades/testnet/1000-1999.arrow", values.ArrowPath)
+
+	values = newFlags("--start-ledger", "1000", "--end-ledger", "1999",
+		"--filename-template", "{dataset}_{start}_{end}_{token}.{ext}", "--chunk-token", "3f9a1c")
+	assert.Equal(t, "trades_1000_1999_3f9a1c.txt", values.Path)
+	assert.Equal(t, "3f9a1c", values.ChunkToken)
+}
+
+func TestHistoryArchiveBackend(t *testing.T) {
+	ctx := context.Background()
+	backend := historyArchiveBackend{ledgers: map[uint32]*historyarchive.Ledger{
+		10: {
+			Header: xdr.LedgerHeaderHistoryEntry{Header: xdr.LedgerHeader{LedgerSeq: 10}},
+			TransactionResult: xdr.TransactionHistoryResultEntry{TxResultSet: xdr.TransactionResultSet{
+				Results: []xdr.TransactionResultPair{{}, {}},
+			}},
+		},
+	}}
+
+	_, err := backend.GetLedger(ctx, 10)
+	assert.True(t, errors.Is(err, ErrNoTxMeta))
+
+	lcm, err := backend.GetLedgerHeaderMeta(ctx, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(10), lcm.LedgerSequence())
+	assert.Equal(t, 2, lcm.CountTransactions())
+
+	_, err = backend.GetLedgerHeaderMeta(ctx, 11)
+	assert.Error(t, err)
+
+	// The checkpoint of a ledger is downloaded the first time one of its ledgers is read
+	archive := &historyarchive.MockArchive{}
+	archive.On("GetLedgers", uint32(64), uint32(64)).Return(map[uint32]*historyarchive.Ledger{
+		64: {Header: xdr.LedgerHeaderHistoryEntry{Header: xdr.LedgerHeader{LedgerSeq: 64}}},
+		65: {Header: xdr.LedgerHeaderHistoryEntry{Header: xdr.LedgerHeader{LedgerSeq: 65}}},
+	}, nil).Once()
+	backend = historyArchiveBackend{client: archive, ledgers: map[uint32]*historyarchive.Ledger{}}
+
+	ledger, err := backend.GetLedgerArchive(ctx, 64)
+	assert.NoError(t, err)
+	assert.Equal(t, xdr.Uint32(64), ledger.Header.Header.LedgerSeq)
+	lcm, err = backend.GetLedgerHeaderMeta(ctx, 65)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(65), lcm.LedgerSequence())
+	archive.AssertExpectations(t)
+}
+
+func TestCoreVersion(t *testing.T) {
+	binaryPath := filepath.Join(t.TempDir(), "stellar-core")
+	script := "#!/bin/sh\necho 'v22.1.0'\necho 'ledger protocol version: 22'\n"
+	assert.NoError(t, os.WriteFile(binaryPath, []byte(script), 0o755))
+
+	version, err := CoreVersion(binaryPath)
+	assert.NoError(t, err)
+	assert.Equal(t, "v22.1.0", version)
+
+	_, err = CoreVersion(filepath.Join(t.TempDir(), "missing"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,57 @@
+package utils
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// MetricsRegistry has the metrics that are served at the metrics-address
+var MetricsRegistry = prometheus.NewRegistry()
+
+var (
+	// DatastoreNumWorkers is the number of workers of the datastore backend that is being read
+	DatastoreNumWorkers = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "stellar_etl", Subsystem: "datastore", Name: "num_workers",
+		Help: "Number of workers downloading ledgers from the datastore.",
+	})
+	// DatastoreBufferSize is the buffer size of the datastore backend that is being read
+	DatastoreBufferSize = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "stellar_etl", Subsystem: "datastore", Name: "buffer_size",
+		Help: "Maximum number of ledgers downloaded from the datastore ahead of the export.",
+	})
+	// LedgerReadRate is the number of ledgers read per second, measured by autoscale-workers
+	LedgerReadRate = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "stellar_etl", Subsystem: "datastore", Name: "ledgers_per_second",
+		Help: "Number of ledgers read per second since the datastore workers were last adjusted.",
+	})
+	// LedgerWaitRatio is the fraction of the time the export waited for ledgers, measured by autoscale-workers
+	LedgerWaitRatio = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "stellar_etl", Subsystem: "datastore", Name: "wait_ratio",
+		Help: "Fraction of the time the export waited for the datastore since the datastore workers were last adjusted.",
+	})
+	// TransformDuration is how long the transforms of every dataset take, by the type of the transformed operations
+	TransformDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "stellar_etl", Subsystem: "transform", Name: "duration_seconds",
+		Help:    "Time taken by a transform, by dataset and operation type.",
+		Buckets: prometheus.ExponentialBuckets(0.00001, 4, 10),
+	}, []string{"dataset", "operation_type"})
+)
+
+func init() {
+	MetricsRegistry.MustRegister(DatastoreNumWorkers, DatastoreBufferSize, LedgerReadRate, LedgerWaitRatio, TransformDuration)
+}
+
+// ServeMetrics serves the metrics of MetricsRegistry at http://<address>/metrics in the background. The export goes on
+// without metrics if the server stops.
+func ServeMetrics(address string, logger *EtlLogger) {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(MetricsRegistry, promhttp.HandlerOpts{}))
+	go func() {
+		if err := http.ListenAndServe(address, mux); err != nil {
+			logger.Errorf("could not serve metrics at %s: %v", address, err)
+		}
+	}()
+	logger.Infof("Serving metrics at http://%s/metrics", address)
+}
@@ -0,0 +1,60 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/stellar/go-stellar-sdk/historyarchive"
+)
+
+// RootLedgerSequence is the ledger whose hash identifies the history of a network. The genesis ledger is the same
+// after every reset of a network, but the ledgers closed after it are not, so the last ledger of the first checkpoint
+// is used instead.
+const RootLedgerSequence = 63
+
+// ErrNetworkReset is wrapped by the errors of history archives whose history is not the expected one, which is what
+// happens to testnet every time it is reset
+var ErrNetworkReset = errors.New("network was reset")
+
+// GetRootLedgerHash returns the hex encoded hash of the root ledger of the network of the history archives
+func GetRootLedgerHash(archive historyarchive.ArchiveInterface) (string, error) {
+	header, err := archive.GetLedgerHeader(RootLedgerSequence)
+	if err != nil {
+		return "", fmt.Errorf("could not read root ledger %d: %v", RootLedgerSequence, err)
+	}
+	return HashToHexString(header.Hash), nil
+}
+
+// CheckNetworkRoot returns an error if the history archives are of another network than networkPassphrase, or if the
+// hash of their root ledger is not expectedRootHash. An empty expectedRootHash skips the hash check.
+func CheckNetworkRoot(archive historyarchive.ArchiveInterface, network, networkPassphrase, expectedRootHash string) error {
+	root, err := archive.GetRootHAS()
+	if err != nil {
+		return fmt.Errorf("could not read the history archive state: %v", err)
+	}
+
+	// Archives only publish their passphrase since stellar-core 14.1.0
+	if root.NetworkPassphrase != "" && root.NetworkPassphrase != networkPassphrase {
+		return fmt.Errorf("the history archives are of the network %q instead of %s (%q)", root.NetworkPassphrase, network, networkPassphrase)
+	}
+
+	if expectedRootHash == "" {
+		return nil
+	}
+
+	if root.CurrentLedger < RootLedgerSequence {
+		return fmt.Errorf("%s %w: the history archives end at ledger %d, before root ledger %d", network, ErrNetworkReset, root.CurrentLedger, RootLedgerSequence)
+	}
+
+	rootHash, err := GetRootLedgerHash(archive)
+	if err != nil {
+		return err
+	}
+	if !strings.EqualFold(rootHash, expectedRootHash) {
+		return fmt.Errorf("%s %w: root ledger %d has hash %s instead of expected-root-hash %s; ledgers exported before the reset are not in the history archives anymore",
+			network, ErrNetworkReset, RootLedgerSequence, rootHash, expectedRootHash)
+	}
+
+	return nil
+}
@@ -0,0 +1,34 @@
+package utils
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stellar/go-stellar-sdk/historyarchive"
+	"github.com/stellar/go-stellar-sdk/network"
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckNetworkRoot(t *testing.T) {
+	rootHash := xdr.Hash{1, 2, 3}
+	archive := &historyarchive.MockArchive{}
+	archive.On("GetRootHAS").Return(historyarchive.HistoryArchiveState{CurrentLedger: 1000, NetworkPassphrase: network.TestNetworkPassphrase}, nil)
+	archive.On("GetLedgerHeader", uint32(RootLedgerSequence)).Return(xdr.LedgerHeaderHistoryEntry{Hash: rootHash}, nil)
+
+	hexHash := HashToHexString(rootHash)
+	assert.NoError(t, CheckNetworkRoot(archive, "testnet", network.TestNetworkPassphrase, ""))
+	assert.NoError(t, CheckNetworkRoot(archive, "testnet", network.TestNetworkPassphrase, hexHash))
+
+	err := CheckNetworkRoot(archive, "testnet", network.TestNetworkPassphrase, HashToHexString(xdr.Hash{4}))
+	assert.True(t, errors.Is(err, ErrNetworkReset))
+	assert.Contains(t, err.Error(), "testnet network was reset: root ledger 63 has hash "+hexHash)
+
+	err = CheckNetworkRoot(archive, "pubnet", network.PublicNetworkPassphrase, "")
+	assert.EqualError(t, err, `the history archives are of the network "Test SDF Network ; September 2015" instead of pubnet ("Public Global Stellar Network ; September 2015")`)
+
+	resetArchive := &historyarchive.MockArchive{}
+	resetArchive.On("GetRootHAS").Return(historyarchive.HistoryArchiveState{CurrentLedger: 31, NetworkPassphrase: network.TestNetworkPassphrase}, nil)
+	err = CheckNetworkRoot(resetArchive, "testnet", network.TestNetworkPassphrase, hexHash)
+	assert.EqualError(t, err, "testnet network was reset: the history archives end at ledger 31, before root ledger 63")
+}